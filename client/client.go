@@ -360,6 +360,15 @@ func WithForwardingOption(enableForwarding bool) ClientOption {
 	}
 }
 
+// WithInitialWindowSize configures the client with the initial HTTP/2 window
+// size of the gRPC streams and connections. A larger window allows more data
+// in flight on a single connection. gRPC ignores the sizes less than 64KB.
+func WithInitialWindowSize(size int32) ClientOption {
+	return func(c *client) {
+		c.option.initialWindowSize = size
+	}
+}
+
 // WithMaxErrorRetry configures the client max retry times when connect meets error.
 func WithMaxErrorRetry(count int) ClientOption {
 	return func(c *client) {
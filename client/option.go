@@ -51,6 +51,9 @@ type option struct {
 	timeout          time.Duration
 	maxRetryTimes    int
 	enableForwarding bool
+	// initialWindowSize is the initial HTTP/2 window size of the gRPC
+	// streams and connections, 0 means the gRPC default is used.
+	initialWindowSize int32
 
 	// Dynamic options.
 	dynamicOptions [dynamicOptionCount]atomic.Value
@@ -71,6 +74,19 @@ func newOption() *option {
 	return co
 }
 
+// getGRPCDialOptions returns the gRPC dial options including the ones
+// generated by the other static options.
+func (o *option) getGRPCDialOptions() []grpc.DialOption {
+	if o.initialWindowSize <= 0 {
+		return o.gRPCDialOptions
+	}
+	opts := make([]grpc.DialOption, 0, len(o.gRPCDialOptions)+2)
+	opts = append(opts, o.gRPCDialOptions...)
+	return append(opts,
+		grpc.WithInitialWindowSize(o.initialWindowSize),
+		grpc.WithInitialConnWindowSize(o.initialWindowSize))
+}
+
 // setMaxTSOBatchWaitInterval sets the max TSO batch wait interval option.
 // It only accepts the interval value between 0 and 10ms.
 func (o *option) setMaxTSOBatchWaitInterval(interval time.Duration) error {
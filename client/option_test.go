@@ -19,12 +19,23 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/tikv/pd/client/testutil"
+	"google.golang.org/grpc"
 )
 
 var _ = Suite(&testClientOptionSuite{})
 
 type testClientOptionSuite struct{}
 
+func (s *testClientSuite) TestGRPCDialOptions(c *C) {
+	o := newOption()
+	o.gRPCDialOptions = []grpc.DialOption{grpc.WithBlock()}
+	c.Assert(o.getGRPCDialOptions(), HasLen, 1)
+	o.initialWindowSize = 1 << 20
+	c.Assert(o.getGRPCDialOptions(), HasLen, 3)
+	// The configured dial options are not changed.
+	c.Assert(o.gRPCDialOptions, HasLen, 1)
+}
+
 func (s *testClientSuite) TestDynamicOptionChange(c *C) {
 	o := newOption()
 	// Check the default value setting.
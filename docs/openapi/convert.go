@@ -0,0 +1,241 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// Version is the OpenAPI version of the converted specification.
+const Version = "3.0.3"
+
+type object = map[string]interface{}
+
+// Convert converts the Swagger 2.0 specification generated by swag to an
+// OpenAPI 3.0 specification.
+func Convert(swagger []byte) ([]byte, error) {
+	var src object
+	if err := json.Unmarshal(swagger, &src); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if v, _ := src["swagger"].(string); v != "2.0" {
+		return nil, errors.Errorf("unsupported swagger version %q", v)
+	}
+	dst := object{
+		"openapi": Version,
+		"info":    src["info"],
+		"paths":   convertPaths(objectOf(src["paths"])),
+	}
+	basePath, _ := src["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+	dst["servers"] = []interface{}{object{"url": basePath}}
+
+	components := object{}
+	if defs := objectOf(src["definitions"]); len(defs) > 0 {
+		schemas := object{}
+		for name, schema := range defs {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if defs := objectOf(src["securityDefinitions"]); len(defs) > 0 {
+		schemes := object{}
+		for name, def := range defs {
+			schemes[name] = convertSecurityScheme(objectOf(def))
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		dst["components"] = components
+	}
+	for _, key := range []string{"security", "tags", "externalDocs"} {
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(dst); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func convertPaths(paths object) object {
+	dst := object{}
+	for path, item := range paths {
+		ops := object{}
+		for method, op := range objectOf(item) {
+			ops[method] = convertOperation(objectOf(op))
+		}
+		dst[path] = ops
+	}
+	return dst
+}
+
+func convertOperation(op object) object {
+	dst := object{}
+	for _, key := range []string{"tags", "summary", "description", "operationId", "deprecated", "security"} {
+		if v, ok := op[key]; ok {
+			dst[key] = v
+		}
+	}
+	consumes := mediaTypes(op["consumes"])
+	var params []interface{}
+	for _, p := range sliceOf(op["parameters"]) {
+		param := objectOf(p)
+		if param["in"] == "body" {
+			body := object{
+				"content": contentOf(consumes, convertSchema(param["schema"])),
+			}
+			if v, ok := param["description"]; ok {
+				body["description"] = v
+			}
+			if v, ok := param["required"]; ok {
+				body["required"] = v
+			}
+			dst["requestBody"] = body
+			continue
+		}
+		params = append(params, convertParameter(param))
+	}
+	if len(params) > 0 {
+		dst["parameters"] = params
+	}
+
+	produces := mediaTypes(op["produces"])
+	responses := object{}
+	for code, r := range objectOf(op["responses"]) {
+		resp := objectOf(r)
+		dst := object{"description": resp["description"]}
+		if dst["description"] == nil {
+			dst["description"] = ""
+		}
+		if schema, ok := resp["schema"]; ok {
+			dst["content"] = contentOf(produces, convertSchema(schema))
+		}
+		responses[code] = dst
+	}
+	// The responses are required by OpenAPI 3.0.
+	if len(responses) == 0 {
+		responses["default"] = object{"description": ""}
+	}
+	dst["responses"] = responses
+	return dst
+}
+
+func convertParameter(param object) object {
+	dst := object{
+		"name": param["name"],
+		"in":   param["in"],
+	}
+	if v, ok := param["description"]; ok {
+		dst["description"] = v
+	}
+	if v, ok := param["required"]; ok {
+		dst["required"] = v
+	}
+	// The path parameters are always required.
+	if param["in"] == "path" {
+		dst["required"] = true
+	}
+	schema := object{}
+	for _, key := range []string{"type", "format", "items", "enum", "default", "minimum", "maximum"} {
+		if v, ok := param[key]; ok {
+			schema[key] = v
+		}
+	}
+	dst["schema"] = convertSchema(schema)
+	return dst
+}
+
+func convertSecurityScheme(def object) object {
+	switch def["type"] {
+	case "basic":
+		return object{"type": "http", "scheme": "basic"}
+	case "apiKey":
+		dst := object{"type": "apiKey", "in": def["in"], "name": def["name"]}
+		if v, ok := def["description"]; ok {
+			dst["description"] = v
+		}
+		return dst
+	default:
+		return def
+	}
+}
+
+// convertSchema rewrites the references to the definitions to the ones of the
+// components.
+func convertSchema(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		dst := make(object, len(s))
+		for k, v := range s {
+			if ref, ok := v.(string); ok && k == "$ref" {
+				dst[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			dst[k] = convertSchema(v)
+		}
+		return dst
+	case []interface{}:
+		dst := make([]interface{}, 0, len(s))
+		for _, v := range s {
+			dst = append(dst, convertSchema(v))
+		}
+		return dst
+	default:
+		return s
+	}
+}
+
+func contentOf(mediaTypes []string, schema interface{}) object {
+	content := object{}
+	for _, t := range mediaTypes {
+		content[t] = object{"schema": schema}
+	}
+	return content
+}
+
+func mediaTypes(v interface{}) []string {
+	var types []string
+	for _, t := range sliceOf(v) {
+		if s, ok := t.(string); ok {
+			types = append(types, s)
+		}
+	}
+	if len(types) == 0 {
+		types = []string{"application/json"}
+	}
+	return types
+}
+
+func objectOf(v interface{}) object {
+	o, _ := v.(map[string]interface{})
+	return o
+}
+
+func sliceOf(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
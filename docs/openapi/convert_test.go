@@ -0,0 +1,121 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testConvertSuite{})
+
+type testConvertSuite struct{}
+
+const testSwagger = `{
+    "swagger": "2.0",
+    "info": {"title": "test", "version": "1.0"},
+    "basePath": "/pd/api/v1",
+    "paths": {
+        "/rule/{id}": {
+            "post": {
+                "consumes": ["application/json"],
+                "produces": ["application/json"],
+                "parameters": [
+                    {"type": "string", "name": "id", "in": "path", "required": true},
+                    {"type": "boolean", "default": false, "name": "force", "in": "query"},
+                    {"name": "rule", "in": "body", "required": true, "schema": {"$ref": "#/definitions/placement.Rule"}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/placement.Rule"}}},
+                    "400": {"description": "The input is invalid."}
+                }
+            },
+            "delete": {}
+        }
+    },
+    "definitions": {
+        "placement.Rule": {"type": "object", "properties": {"peers": {"type": "array", "items": {"$ref": "#/definitions/placement.Peer"}}}}
+    },
+    "securityDefinitions": {
+        "BearerAuth": {"type": "apiKey", "in": "header", "name": "Authorization"}
+    }
+}`
+
+func (s *testConvertSuite) TestConvert(c *C) {
+	out, err := Convert([]byte(testSwagger))
+	c.Assert(err, IsNil)
+	var spec map[string]interface{}
+	c.Assert(json.Unmarshal(out, &spec), IsNil)
+
+	expected := `{
+    "openapi": "3.0.3",
+    "info": {"title": "test", "version": "1.0"},
+    "servers": [{"url": "/pd/api/v1"}],
+    "paths": {
+        "/rule/{id}": {
+            "post": {
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+                    {"name": "force", "in": "query", "schema": {"type": "boolean", "default": false}}
+                ],
+                "requestBody": {
+                    "required": true,
+                    "content": {"application/json": {"schema": {"$ref": "#/components/schemas/placement.Rule"}}}
+                },
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/placement.Rule"}}}}
+                    },
+                    "400": {"description": "The input is invalid."}
+                }
+            },
+            "delete": {"responses": {"default": {"description": ""}}}
+        }
+    },
+    "components": {
+        "schemas": {
+            "placement.Rule": {"type": "object", "properties": {"peers": {"type": "array", "items": {"$ref": "#/components/schemas/placement.Peer"}}}}
+        },
+        "securitySchemes": {
+            "BearerAuth": {"type": "apiKey", "in": "header", "name": "Authorization"}
+        }
+    }
+}`
+	var expectedSpec map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(expected), &expectedSpec), IsNil)
+	c.Assert(spec, DeepEquals, expectedSpec)
+}
+
+func (s *testConvertSuite) TestConvertError(c *C) {
+	_, err := Convert([]byte(`{"openapi": "3.0.3"}`))
+	c.Assert(err, NotNil)
+	_, err = Convert([]byte(`{`))
+	c.Assert(err, NotNil)
+}
+
+func (s *testConvertSuite) TestSpec(c *C) {
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+	}
+	c.Assert(json.Unmarshal(Spec(), &spec), IsNil)
+	c.Assert(spec.OpenAPI, Equals, Version)
+}
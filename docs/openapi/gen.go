@@ -0,0 +1,40 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+// gen converts the Swagger 2.0 specification in docs/swagger to openapi.json.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/tikv/pd/docs/openapi"
+)
+
+func main() {
+	swagger, err := os.ReadFile("../swagger/swagger.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, err := openapi.Convert(swagger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("openapi.json", spec, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapi provides the OpenAPI 3.0 specification of the HTTP API. It
+// is converted from the Swagger 2.0 specification generated by swag in
+// docs/swagger, run `make swagger-spec` to update both of them.
+package openapi
+
+import (
+	_ "embed" // for go:embed
+)
+
+//go:generate go run gen.go
+
+//go:embed openapi.json
+var spec []byte
+
+// Spec returns the OpenAPI 3.0 specification of the HTTP API in JSON.
+func Spec() []byte {
+	return spec
+}
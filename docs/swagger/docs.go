@@ -0,0 +1,10442 @@
+// GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
+// This file was generated by swaggo/swag
+
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/alecthomas/template"
+	"github.com/swaggo/swag"
+)
+
+var doc = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{.Description}}",
+        "title": "{{.Title}}",
+        "contact": {
+            "name": "Placement Driver Support",
+            "url": "https://github.com/tikv/pd/issues",
+            "email": "info@pingcap.com"
+        },
+        "license": {
+            "name": "Apache 2.0",
+            "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
+        },
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/audit-middleware": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "switch audit middleware",
+                "parameters": [
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "description": "enable",
+                        "name": "enable",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Switching audit middleware is successful.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cache/region/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Drop a specific region from cache.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The region is removed from server cache.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/log": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set log level.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "level",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The log level is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "PD server has no leader.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reset-ts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset the ts.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reset ts successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Reset ts is forbidden.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/unsafe/remove-failed-stores": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Remove failed stores unsafely."
+            }
+        },
+        "/admin/unsafe/remove-failed-stores/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Show the history of failed stores removal."
+            }
+        },
+        "/admin/unsafe/remove-failed-stores/show": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Show the current status of failed stores removal."
+            }
+        },
+        "/auth/token": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Issue a token of the HTTP API by the username and the password.",
+                "parameters": [
+                    {
+                        "description": "The credential of the user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.tokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "The username or the password is wrong.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/users/{name}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Create or update a user of the HTTP API.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the user",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The password and the role of the user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.userRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user is saved.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Delete a user of the HTTP API, the issued tokens are valid until they expire.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the user",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user is deleted.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/checker/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checker"
+                ],
+                "summary": "Get if checker is paused",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause or resume the scheduler successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checker"
+                ],
+                "summary": "Pause or resume region merge.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the checker.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause or resume the scheduler successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad format request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cluster": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Get cluster info.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/metapb.Cluster"
+                        }
+                    }
+                }
+            }
+        },
+        "/cluster/status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "Get cluster status.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cluster.Status"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get full config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.Config"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update a config item.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ttl",
+                        "name": "ttlSecond",
+                        "in": "query"
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The config is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/cluster-version": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get cluster version.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/semver.Version"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update cluster version.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The cluster version is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "PD server has no leader.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/default": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get default config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.Config"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/label-property": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get label property config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.LabelPropertyConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update label property config item.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The config is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "PD server has no leader.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/pd-server": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get PD server config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.PDServerConfig"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/placement-rule": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rules and groups configuration.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.GroupBundle"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Update all rules and groups configuration.",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "if partially update rules",
+                        "name": "partial",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rules and groups successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/placement-rule/{group}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Get group config and all rules belong to the group.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/placement.GroupBundle"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Update group and all rules belong to it.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters of group and rules",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/placement.GroupBundle"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update group and rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Get group config and all rules belong to the group.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name or name pattern of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Use regular expression",
+                        "name": "regexp",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete group and rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/region-label/rule": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Update region label rule of cluster.",
+                "parameters": [
+                    {
+                        "description": "Parameters of label rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/labeler.LabelRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rule successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/region-label/rule/ids": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Get label rules of cluster by ids.",
+                "parameters": [
+                    {
+                        "description": "IDs of query rules",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/labeler.LabelRule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/region-label/rule/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Get label rule of cluster by id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/labeler.LabelRule"
+                        }
+                    },
+                    "404": {
+                        "description": "The rule does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Delete label rule of cluster by id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete rule successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The rule does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/region-label/rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "List all label rules of cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/labeler.LabelRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Update region label rules in batch.",
+                "parameters": [
+                    {
+                        "description": "Patch to update rules",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/labeler.LabelRulePatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update region label rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/replicate": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get replication config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.ReplicationConfig"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update a replication config item.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The config is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "PD server has no leader.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/replication-mode": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get replication mode config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.ReplicationModeConfig"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Set replication mode config.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The replication mode config is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rule": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Update rule of cluster.",
+                "parameters": [
+                    {
+                        "description": "Parameters of rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/placement.Rule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rule successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rule/{group}/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Get rule of cluster by group and id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/placement.Rule"
+                        }
+                    },
+                    "404": {
+                        "description": "The rule does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Delete rule of cluster.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete rule successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rule_group": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Update rule group config.",
+                "parameters": [
+                    {
+                        "description": "Parameters of rule group",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/placement.RuleGroup"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rule group config successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rule_group/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Get rule group config by group id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/placement.RuleGroup"
+                        }
+                    },
+                    "404": {
+                        "description": "The RuleGroup does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Delete rule group config.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete rule group config successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rule_groups": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rule group configs.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.RuleGroup"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Set all rules for the cluster. If there is an error, modifications are promised to be rollback in memory, but may fail to rollback disk. You probably want to request again to make rules in memory/disk consistent.",
+                "parameters": [
+                    {
+                        "description": "Parameters of rules",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "The rules conflict.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.RuleConflict"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/apply-template": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Set the rules of a rule template with the label values of its parameters.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the template",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "The label values of the template parameters",
+                        "name": "values",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The template does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "The rules conflict.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.RuleConflict"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/batch": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Batch operations for the cluster. Operations should be independent(different ID). If there is an error, modifications are promised to be rollback in memory, but may fail to rollback disk. You probably want to request again to make rules in memory/disk consistent.",
+                "parameters": [
+                    {
+                        "description": "Parameters of rule operations",
+                        "name": "operations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.RuleOp"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch operations successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/group/{group}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rules of cluster by group.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of group",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/key/{key}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rules of cluster by key.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/keyspace/{keyspace-id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rules of a keyspace.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "keyspace-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Set the rules of a keyspace, which take precedence over the cluster-wide rules for the data of the keyspace.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "keyspace-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters of rules",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "The rules conflict.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.RuleConflict"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Delete all rules of a keyspace.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "keyspace-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete rules successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/region/{region}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List all rules of cluster by region.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of region",
+                        "name": "region",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/placement.Rule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/simulate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "Simulate a rule without applying it, and report the regions which need to move.",
+                "parameters": [
+                    {
+                        "description": "Parameters of rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/placement.Rule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cluster.RuleSimulationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/rules/templates": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule"
+                ],
+                "summary": "List the rule templates for the common cluster topologies.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/placement.RuleTemplate"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/schedule": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get schedule config.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/config.ScheduleConfig"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Update a schedule config item.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The config is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "PD server has no leader.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/pprof/allocs": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug allocs of PD servers."
+            }
+        },
+        "/debug/pprof/block": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug block of PD servers."
+            }
+        },
+        "/debug/pprof/goroutine": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug goroutine of PD servers."
+            }
+        },
+        "/debug/pprof/heap": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug heap of PD servers."
+            }
+        },
+        "/debug/pprof/mutex": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug mutex of PD servers."
+            }
+        },
+        "/debug/pprof/profile": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug profile of PD servers."
+            }
+        },
+        "/debug/pprof/symbol": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug symbol of PD servers."
+            }
+        },
+        "/debug/pprof/threadcreate": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug threadcreate of PD servers."
+            }
+        },
+        "/debug/pprof/trace": {
+            "get": {
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug trace of PD servers."
+            }
+        },
+        "/debug/pprof/zip": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "debug zip of PD servers."
+            }
+        },
+        "/diagnostic/store-config/breakers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diagnostic"
+                ],
+                "summary": "Get the states of the circuit breakers of loading the store config.",
+                "responses": {
+                    "200": {
+                        "description": "The state of every store, the key is the status address.",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "Subscribe the cluster events, the events are pushed as server-sent events until the client disconnects.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.ClusterEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/gc/safepoint": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servicegcsafepoint"
+                ],
+                "summary": "Get all service GC safepoint.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.listServiceGCSafepoint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/gc/safepoint/{service_id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servicegcsafepoint",
+                    "rule"
+                ],
+                "summary": "Delete a service GC safepoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete service GC safepoint successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Health status of PD servers.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.Health"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotspot/regions/history": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotspot"
+                ],
+                "summary": "List the history hot regions.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/storage.HistoryHotRegions"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotspot/regions/read": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotspot"
+                ],
+                "summary": "List the hot read regions.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only list the hot regions on the stores, store_id is also accepted",
+                        "name": "store-id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list the hot regions overlapping with the key range in the format of hex(start)-hex(end), either key can be empty",
+                        "name": "key-range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort the hot regions by bytes, keys or qps",
+                        "name": "sort-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort in ascending order, default is descending",
+                        "name": "asc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list the top N hot regions across all the stores",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.StoreHotPeersInfos"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotspot/regions/write": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotspot"
+                ],
+                "summary": "List the hot write regions.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only list the hot regions on the stores, store_id is also accepted",
+                        "name": "store-id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list the hot regions overlapping with the key range in the format of hex(start)-hex(end), either key can be empty",
+                        "name": "key-range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort the hot regions by bytes, keys or qps",
+                        "name": "sort-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort in ascending order, default is descending",
+                        "name": "asc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only list the top N hot regions across all the stores",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.StoreHotPeersInfos"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotspot/stores": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotspot"
+                ],
+                "summary": "List the hot stores.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.HotStoreStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "List all the keyspaces, including the ones marked for deletion.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Create a keyspace with the next unused ID.",
+                "parameters": [
+                    {
+                        "description": "The name and the config of the keyspace",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateKeyspaceParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The keyspace name is used.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/merge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Merge two keyspaces with contiguous key ranges, the keyspace with the lower range takes the merged range and the other one is marked for deletion.",
+                "parameters": [
+                    {
+                        "description": "The IDs of the two keyspaces",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MergeKeyspacesParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Get a keyspace by ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Mark a keyspace for deletion.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Update the config of a keyspace, an item set to null is removed.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The config items to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}/affinity": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Get the store affinity of a keyspace.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceAffinity"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace or its affinity does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Set the store affinity of a keyspace, the regions of the keyspace are only placed on the stores with all the required labels. An affinity without any required label removes the affinity.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The required store labels",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceAffinity"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceAffinity"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}/gc-safe-point": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Get the GC safe point of a keyspace with TTL, the data before it is expired. It is 0 if the keyspace has no TTL.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.KeyspaceGCSafePoint"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}/split": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Split the key range of a keyspace, the upper half is routed to a new keyspace. Splitting at the same key again returns the keyspace created before.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The hex-encoded split key",
+                        "name": "split-key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The name of the new keyspace",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "The keyspace name is used.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}/stats": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Get the region count, size and store distribution of a keyspace. If the interval is set, the stats are pushed as server-sent events every interval until the client disconnects.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The interval to push the stats, e.g. 10s",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cluster.KeyspaceStats"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/keyspaces/{id}/ttl": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keyspace"
+                ],
+                "summary": "Set the time to live of the data in a keyspace. The GC safe point of the keyspace is published as min(GC safe point, now - TTL), and the regions not written since it are cleaned up, 0 disables the expiry.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Keyspace Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The TTL in seconds",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SetKeyspaceTTLParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/endpoint.KeyspaceMeta"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The keyspace does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/labels": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "List all label values.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/metapb.StoreLabel"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/labels/stores": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "label"
+                ],
+                "summary": "List stores that have specific label values.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name of store label filter",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "value of store label filter",
+                        "name": "value",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoresInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/leader": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leader"
+                ],
+                "summary": "Get the leader PD server of the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pdpb.Member"
+                        }
+                    }
+                }
+            }
+        },
+        "/leader/resign": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leader"
+                ],
+                "summary": "Transfer etcd leadership to another PD server.",
+                "responses": {
+                    "200": {
+                        "description": "The resign command is submitted.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/leader/transfer/{nextLeader}": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leader"
+                ],
+                "summary": "Transfer etcd leadership to the specific PD server.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PD server that transfer leader to",
+                        "name": "nextLeader",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The transfer command is submitted.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/members": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "member"
+                ],
+                "summary": "List all PD servers in the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pdpb.GetMembersResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/id/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "member"
+                ],
+                "summary": "Remove a PD server from the cluster.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PD server Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The PD server is successfully removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/name/{name}": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "member"
+                ],
+                "summary": "Set leader priority of a PD member.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PD server name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The leader priority is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The member does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "member"
+                ],
+                "summary": "Remove a PD server from the cluster.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PD server name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The PD server is successfully removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The member does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/min-resolved-ts": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "minresolvedts"
+                ],
+                "summary": "Get cluster-level min resolved ts.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.minResolvedTS"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the OpenAPI 3.0 specification of the HTTP API.",
+                "responses": {
+                    "200": {
+                        "description": "The specification of the HTTP API.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "List pending operators.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "admin",
+                            "leader",
+                            "region"
+                        ],
+                        "type": "string",
+                        "description": "Specify the operator kind.",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/operator.Operator"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Create an operator.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The operator is created.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/audit": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "lists the finished operators in the audit log from the newest to the oldest.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "The max number of the operators",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The type of the operators, e.g. transfer-leader",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/schedule.OperatorAuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The request is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/records": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "lists the finished operators since the given timestamp in second.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "From Unix timestamp",
+                        "name": "from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/operator.OpRecord"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The request is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Get the count of the finished operators and steps by type and result.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.OperatorResultStats"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/stores/{store_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Get the count of the pending operators moving peers or leaders into a store.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "store_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreOperatorCount"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/{region_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Get a Region's pending operator.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "A Region's Id",
+                        "name": "region_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.OperatorWithStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Cancel a Region's pending operator.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "A Region's Id",
+                        "name": "region_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The pending operator is canceled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/operators/{region_id}/watch": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operator"
+                ],
+                "summary": "Watch the progress of a Region's pending operator through WebSocket. The events are sent when the steps start and finish, and the connection is closed once the operator finishes.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "A Region's Id",
+                        "name": "region_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/events.ClusterEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The operator does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "summary": "Ping PD servers."
+            }
+        },
+        "/plugin": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plugin"
+                ],
+                "summary": "Load plugin.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Load plugin success.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plugin"
+                ],
+                "summary": "Unload plugin.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Load/Unload plugin successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "List all region quotas of cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quota.RegionQuota"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Update region quota of cluster.",
+                "parameters": [
+                    {
+                        "description": "Parameters of region quota",
+                        "name": "quota",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quota.RegionQuota"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update region quota successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotas/violations": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "List the regions exceeding their quotas in the last heartbeats.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quota.Violation"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/quotas/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Get region quota of cluster by id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quota Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/quota.RegionQuota"
+                        }
+                    },
+                    "404": {
+                        "description": "The quota does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "Delete region quota of cluster by id.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quota Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete region quota successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The quota does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/region/id/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Search for a region by region ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/region/id/{id}/label/{key}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Get label of a region.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/region/id/{id}/labels": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region_label"
+                ],
+                "summary": "Get labels of a region.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/region/key/{key}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Search for a region by a key. GetRegion is named to be consistent with gRPC",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Region key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions in the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/accelerate-schedule": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Accelerate regions scheduling a in given range, only receive hex format for keys",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "default": 256,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Accelerate regions scheduling in a given range [startKey, endKey)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/down-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that has down peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/empty-region": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all empty regions.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/extra-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that has extra peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/hist-keys": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get keys of histogram.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Key bound of region histogram",
+                        "name": "bound",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.histItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/hist-size": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get size of histogram.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Size bound of region histogram",
+                        "name": "bound",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.histItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/learner-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that has learner peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/miss-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that miss peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/offline-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that has offline peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/check/pending-peer": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions that has pending peer.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/confver": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions with the largest conf version.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/count": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get count of regions.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/key": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions in a given range [startKey, endKey).",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Region range start key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region range end key",
+                        "name": "endkey",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/range": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions in a given range [start-key, end-key) page by page.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Region range start key, hex encoded",
+                        "name": "start-key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region range end key, hex encoded",
+                        "name": "end-key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The next-page-token returned by the previous page",
+                        "name": "page-token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/range-holes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all range holes whitout any region info.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/readflow": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions with the highest read flow.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/replicated": {
+            "get": {
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Check if regions in the given key ranges are replicated. Returns 'REPLICATED', 'INPROGRESS', or 'PENDING'. 'PENDING' means that there is at least one region pending for scheduling. Similarly, 'INPROGRESS' means there is at least one region in scheduling.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regions start key, hex encoded",
+                        "name": "startKey",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regions end key, hex encoded",
+                        "name": "endKey",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "INPROGRESS",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/scatter": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Scatter regions by given key ranges or regions id distributed by given group with given retry limit",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scatter regions by given key ranges or regions id distributed by given group with given retry limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/sibling/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List sibling regions of a specific region.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/size": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions with the largest size.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/size-estimation": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Estimate the size and the keys of the regions overlapping a given range [start-key, end-key).",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Region range start key, hex encoded",
+                        "name": "start-key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region range end key, hex encoded",
+                        "name": "end-key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsSizeEstimation"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/size-histogram": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get the histogram of the region sizes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "10,50,100,144,256,512",
+                        "description": "Comma separated upper bounds of the buckets in MiB, in ascending order",
+                        "name": "buckets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.RegionSizeBucket"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/split": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Split regions with given split keys",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Split regions with given split keys",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/store/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List all regions of a specific store.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/version": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions with the largest version.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/writeflow": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "List regions with the highest write flow.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 16,
+                        "description": "Limit count",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegionsInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{id}/genealogy": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get the splits and the merges which a region is derived from and derived to.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/genealogy.Chain"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{id}/labels": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get the union of the labels of the stores holding the replicas of a region.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{id}/rule-trace": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Explain how the placement rules are evaluated for a region.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/placement.RuleTrace"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The region does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Placement rules feature is disabled.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{id}/traffic": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "region"
+                ],
+                "summary": "Get the recent read and write traffic of a region reported by the heartbeats.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Region Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "5m",
+                        "description": "The duration of the recent traffic",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The duration to sum the traffic, every heartbeat is returned if it is empty",
+                        "name": "resolution",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/statistics.TrafficSample"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/replication_mode/lag": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replication_mode"
+                ],
+                "summary": "Get the replication lag of the stores in the dr zone and the replication peers, which is estimated from the pending peers of the stores.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/replication.ReplicationLag"
+                        }
+                    }
+                }
+            }
+        },
+        "/replication_mode/state": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replication_mode"
+                ],
+                "summary": "Switch the dr-auto-sync state manually. If the state is pinned, the automatic transitions are skipped until pin_until, or until it is unpinned if pin_until is not set. An empty state only updates the pin.",
+                "parameters": [
+                    {
+                        "description": "The state and the pin",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SetDRStateParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/replication.HTTPReplicationStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/replication_mode/status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replication_mode"
+                ],
+                "summary": "Get status of replication mode",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/replication.HTTPReplicationStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedulers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "List all created schedulers by status.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Create a scheduler.",
+                "parameters": [
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The scheduler is created.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad format request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedulers/{name}": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Pause or resume a scheduler.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause or resume the scheduler successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad format request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Delete a scheduler.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The scheduler is removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The scheduler is not found.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedulers/{name}/pause": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Pause a scheduler for a duration, it is resumed automatically after the duration.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The pause duration, e.g. 300s.",
+                        "name": "duration",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause the scheduler successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad format request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedulers/{name}/resume": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Resume a paused scheduler.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resume the scheduler successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedulers/{name}/time-window": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Get the time window of a scheduler.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.TimeWindow"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Restrict a scheduler to schedule only inside a daily time window.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the scheduler.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params, e.g. {\\",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Set the time window successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/cross-dc": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get the current rate and the recent history of the data moved across and within the zones by the operators.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schedule.CrossDCTraffic"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/region": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get region statistics of a specified range.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start key",
+                        "name": "start_key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End key",
+                        "name": "end_key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.RegionStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/snapshots": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get the snapshots in flight of the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cluster.SnapshotStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the build info of PD server.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.status"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get a store's information.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Take down a store from the cluster.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "description": "force",
+                        "name": "force",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store is set as Offline.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "The store has already been removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/bandwidth": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set the store's bandwidth in MB/s for sending and receiving snapshots, 0 means no limit.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's bandwidth is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/label": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set the store's label.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Labels in json format",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's label is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/limit": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set the store's limit.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ttl",
+                        "name": "ttlSecond",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's label is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/state": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set the store's state.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "Up",
+                            "Offline"
+                        ],
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's state is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/warm-up": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Restart the warm-up of the store with the given duration, 0s stops the warm-up.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's warm-up is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "The store has been removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/store/{id}/weight": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set the store's leader/region weight.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store's label is updated.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get stores in the cluster.",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "description": "Specify accepted store states.",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoresInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/limit": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get limit of all stores in the cluster.",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "include Tombstone",
+                        "name": "include_tombstone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set limit of all stores in the cluster.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ttl",
+                        "name": "ttlSecond",
+                        "in": "query"
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Set store limit successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/limit/scene": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get limit scene in the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "Set store limit scene successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Set limit scene in the cluster.",
+                "parameters": [
+                    {
+                        "description": "Store limit scene",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storelimit.Scene"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Set store limit scene successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/remove-tombstone": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Remove tombstone records in the cluster.",
+                "responses": {
+                    "200": {
+                        "description": "Remove tombstone successfully.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/slow": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get the stores whose p99 latency reported by the last heartbeat exceeds the thresholds.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The threshold of the p99 read latency in milliseconds",
+                        "name": "read-p99-ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The threshold of the p99 write latency in milliseconds",
+                        "name": "write-p99-ms",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/tombstones": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Remove the tombstone stores which have not sent heartbeats for a period of time.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The period of time, such as 30d or 12h. Default to tombstone-store-retention-days.",
+                        "name": "older-than",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return the stores to be removed. Default to tombstone-store-cleanup-dry-run.",
+                        "name": "dry-run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}/amplification": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get the current and the recent read and write amplification of the store.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.StoreAmplificationInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}/drain": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get the progress of draining the store.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cluster.StoreDrainStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Drain the store, all of its regions are moved away before it is removed.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store is draining.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "The store has been removed.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Stop draining the store, the regions moved away are not moved back.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The store stops draining.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get the health score of the store aggregated from multiple health signals.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.StoreHealthScore"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}/io": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Get the rolling average of the disk and the client IO rates of the store.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/statistics.StoreIO"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}/labels": {
+            "patch": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "store"
+                ],
+                "summary": "Patch the store's labels without re-registering the store, the label with an empty value is deleted.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Labels to be patched in json format, such as {",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.StoreInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The store does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trend": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trend"
+                ],
+                "summary": "Get the growth and changes of data in the most recent period of time.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "From Unix timestamp",
+                        "name": "from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.Trend"
+                        }
+                    },
+                    "400": {
+                        "description": "The request is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tso/allocator/transfer/{name}": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tso"
+                ],
+                "summary": "Transfer Local TSO Allocator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PD server name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "json params",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The transfer command is submitted.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is invalid.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The member does not exist.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "PD server failed to proceed the request.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the version of PD server.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.version"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CreateKeyspaceParams": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.Health": {
+            "type": "object",
+            "properties": {
+                "client_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "health": {
+                    "type": "boolean"
+                },
+                "member_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.HotStoreStats": {
+            "type": "object",
+            "properties": {
+                "bytes-read-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "bytes-write-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "keys-read-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "keys-write-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "query-read-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "query-write-rate": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "api.KeyspaceGCSafePoint": {
+            "type": "object",
+            "properties": {
+                "safe_point": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.MergeKeyspacesParams": {
+            "type": "object",
+            "properties": {
+                "keyspace_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.MetaPeer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "is_learner": {
+                    "description": "IsLearner is ` + "`" + `Role == \"Learner\"` + "`" + `.\nSince IsLearner was changed to Role in kvproto in 5.0, this field was introduced to ensure api compatibility.",
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "integer"
+                },
+                "role_name": {
+                    "description": "RoleName is ` + "`" + `Role.String()` + "`" + `.\nSince Role is serialized as int by json by default,\nintroducing it will make the output of pd-ctl easier to identify Role.",
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.MetaStore": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address to handle client requests (kv, cop, etc.)",
+                    "type": "string"
+                },
+                "deploy_path": {
+                    "type": "string"
+                },
+                "git_hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/metapb.StoreLabel"
+                    }
+                },
+                "last_heartbeat": {
+                    "description": "The last heartbeat timestamp of the store.",
+                    "type": "integer"
+                },
+                "node_state": {
+                    "description": "NodeState is used to replace StoreState which will be deprecated in the future.",
+                    "type": "integer"
+                },
+                "peer_address": {
+                    "description": "Address to handle peer requests (raft messages from other store).\nEmpty means same as address.",
+                    "type": "string"
+                },
+                "physically_destroyed": {
+                    "description": "If the store is physically destroyed, which means it can never up again.",
+                    "type": "boolean"
+                },
+                "start_timestamp": {
+                    "description": "The start timestamp of the current store",
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "integer"
+                },
+                "state_name": {
+                    "type": "string"
+                },
+                "status_address": {
+                    "description": "Status address provides the HTTP service for external components",
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.PDPeerStats": {
+            "type": "object",
+            "properties": {
+                "down_seconds": {
+                    "type": "integer"
+                },
+                "peer": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.MetaPeer"
+                }
+            }
+        },
+        "api.RegionInfo": {
+            "type": "object",
+            "properties": {
+                "approximate_keys": {
+                    "type": "integer"
+                },
+                "approximate_size": {
+                    "type": "integer"
+                },
+                "down_peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PDPeerStats"
+                    }
+                },
+                "end_key": {
+                    "type": "string"
+                },
+                "epoch": {
+                    "type": "object",
+                    "$ref": "#/definitions/metapb.RegionEpoch"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "leader": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.MetaPeer"
+                },
+                "peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MetaPeer"
+                    }
+                },
+                "pending_peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MetaPeer"
+                    }
+                },
+                "read_bytes": {
+                    "type": "integer"
+                },
+                "read_keys": {
+                    "type": "integer"
+                },
+                "replication_status": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.ReplicationStatus"
+                },
+                "start_key": {
+                    "type": "string"
+                },
+                "written_bytes": {
+                    "type": "integer"
+                },
+                "written_keys": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.RegionSizeBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "upper_bound": {
+                    "description": "UpperBound is the inclusive upper bound of the region sizes in MiB, it\nis omitted in the last bucket which has no upper bound.",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.RegionsInfo": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.RegionInfo"
+                    }
+                }
+            }
+        },
+        "api.RegionsPage": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "next-page-token": {
+                    "description": "NextPageToken is used to get the next page, it is empty on the last page.",
+                    "type": "string"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.RegionInfo"
+                    }
+                }
+            }
+        },
+        "api.RegionsSizeEstimation": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "description": "Accuracy is \"exact\" if the key range is aligned to the region boundaries,\notherwise it is \"approximate\" since the regions at the edges are counted\nas a whole.",
+                    "type": "string"
+                },
+                "approximate_keys": {
+                    "type": "integer"
+                },
+                "approximate_size_bytes": {
+                    "type": "integer"
+                },
+                "region_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.ReplicationStatus": {
+            "type": "object",
+            "properties": {
+                "state": {
+                    "type": "string"
+                },
+                "state_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.SetDRStateParams": {
+            "type": "object",
+            "properties": {
+                "pin": {
+                    "type": "boolean"
+                },
+                "pin_until": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SetKeyspaceTTLParams": {
+            "type": "object",
+            "properties": {
+                "ttl_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.StoreInfo": {
+            "type": "object",
+            "properties": {
+                "label_warnings": {
+                    "description": "LabelWarnings are the warnings of the store labels not matching the\nlocation labels of the cluster.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.StoreStatus"
+                },
+                "store": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.MetaStore"
+                }
+            }
+        },
+        "api.StoreOperatorCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "description": "Limit is the max count of the operators, 0 means no limit.",
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.StoreStatus": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "is_busy": {
+                    "type": "boolean"
+                },
+                "is_warming_up": {
+                    "type": "boolean"
+                },
+                "last_heartbeat_ts": {
+                    "type": "string"
+                },
+                "leader_count": {
+                    "type": "integer"
+                },
+                "leader_score": {
+                    "type": "number"
+                },
+                "leader_size": {
+                    "type": "integer"
+                },
+                "leader_weight": {
+                    "type": "number"
+                },
+                "receiving_snap_count": {
+                    "type": "integer"
+                },
+                "region_count": {
+                    "type": "integer"
+                },
+                "region_score": {
+                    "type": "number"
+                },
+                "region_size": {
+                    "type": "integer"
+                },
+                "region_weight": {
+                    "type": "number"
+                },
+                "sending_snap_count": {
+                    "type": "integer"
+                },
+                "slow_score": {
+                    "type": "integer"
+                },
+                "start_ts": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "used_size": {
+                    "type": "integer"
+                },
+                "warm_up_ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.StoresInfo": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.StoreInfo"
+                    }
+                }
+            }
+        },
+        "api.Trend": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "object",
+                    "$ref": "#/definitions/api.trendHistory"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.trendStore"
+                    }
+                }
+            }
+        },
+        "api.histItem": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.listServiceGCSafepoint": {
+            "type": "object",
+            "properties": {
+                "gc_safe_point": {
+                    "type": "integer"
+                },
+                "service_gc_safe_points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/endpoint.ServiceSafePoint"
+                    }
+                }
+            }
+        },
+        "api.minResolvedTS": {
+            "type": "object",
+            "properties": {
+                "is_real_time": {
+                    "type": "boolean"
+                },
+                "min_resolved_ts": {
+                    "type": "integer"
+                },
+                "persist_interval": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                }
+            }
+        },
+        "api.status": {
+            "type": "object",
+            "properties": {
+                "build_ts": {
+                    "type": "string"
+                },
+                "git_hash": {
+                    "type": "string"
+                },
+                "start_timestamp": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.tokenRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.tokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires-at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.trendHistory": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.trendHistoryEntry"
+                    }
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.trendHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.trendStore": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "available": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "hot_read_flow": {
+                    "type": "number"
+                },
+                "hot_read_region_flows": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "hot_write_flow": {
+                    "type": "number"
+                },
+                "hot_write_region_flows": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_heartbeat_ts": {
+                    "type": "string"
+                },
+                "leader_count": {
+                    "type": "integer"
+                },
+                "region_count": {
+                    "type": "integer"
+                },
+                "start_ts": {
+                    "type": "string"
+                },
+                "state_name": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                }
+            }
+        },
+        "api.userRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.version": {
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "build_time": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "atomic.Int32": {
+            "type": "object"
+        },
+        "cluster.KeyspaceStats": {
+            "type": "object",
+            "properties": {
+                "approximate_keys": {
+                    "type": "integer"
+                },
+                "approximate_size_bytes": {
+                    "type": "integer"
+                },
+                "region_count": {
+                    "type": "integer"
+                },
+                "store_distribution": {
+                    "description": "StoreDistribution is the number of the replicas of the keyspace regions\non each store.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "cluster.RuleSimulationRegion": {
+            "type": "object",
+            "properties": {
+                "end_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operator": {
+                    "description": "Operator is the description of the operator created for the region, it\nis empty if the rule checker can not fix the region now.",
+                    "type": "string"
+                },
+                "start_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "cluster.RuleSimulationReport": {
+            "type": "object",
+            "properties": {
+                "affected_region_count": {
+                    "description": "AffectedRegionCount is the number of the regions not satisfying the\nrules after the rule is applied.",
+                    "type": "integer"
+                },
+                "estimated_data_movement_bytes": {
+                    "description": "EstimatedDataMovementBytes is the size of the replicas the operators add.",
+                    "type": "integer"
+                },
+                "required_operator_count": {
+                    "description": "RequiredOperatorCount is the number of the operators the rule checker\ncreates for the affected regions.",
+                    "type": "integer"
+                },
+                "sample_regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cluster.RuleSimulationRegion"
+                    }
+                }
+            }
+        },
+        "cluster.SnapshotStats": {
+            "type": "object",
+            "properties": {
+                "snapshot_sending_bytes": {
+                    "description": "SnapshotSendingBytes is the size of the snapshots sent by the running\noperators, estimated by the approximate size of the regions.",
+                    "type": "integer"
+                },
+                "snapshot_sending_count": {
+                    "description": "SnapshotSendingCount is the sum of the sending snapshots reported by the\nstore heartbeats.",
+                    "type": "integer"
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cluster.StoreSnapshotStats"
+                    }
+                }
+            }
+        },
+        "cluster.Status": {
+            "type": "object",
+            "properties": {
+                "is_initialized": {
+                    "type": "boolean"
+                },
+                "raft_bootstrap_time": {
+                    "type": "string"
+                },
+                "replication_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "cluster.StoreDrainStatus": {
+            "type": "object",
+            "properties": {
+                "draining": {
+                    "type": "boolean"
+                },
+                "finished": {
+                    "type": "boolean"
+                },
+                "leader_count": {
+                    "type": "integer"
+                },
+                "region_count": {
+                    "description": "RegionCount and LeaderCount are the regions and the leaders remaining\non the store.",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cluster.StoreSnapshotStats": {
+            "type": "object",
+            "properties": {
+                "receiving_count": {
+                    "type": "integer"
+                },
+                "sending_count": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.AuditLogConfig": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "description": "Filename is the file of the audit log if the writer is \"file\".",
+                    "type": "string"
+                },
+                "hmac-key-path": {
+                    "description": "HMACKeyPath is the file of the key to chain the entries by the HMAC, so\nthe entries can not be forged without the key. The entries are chained\nby the SHA-256 if it is empty.",
+                    "type": "string"
+                },
+                "writer": {
+                    "description": "Writer is where the audit log is written to.\nThere are some values supported: \"file\", \"syslog\", or \"\" which disables\nthe audit log, default: \"\".",
+                    "type": "string"
+                }
+            }
+        },
+        "config.Config": {
+            "type": "object",
+            "properties": {
+                "advertise-client-urls": {
+                    "type": "string"
+                },
+                "advertise-peer-urls": {
+                    "type": "string"
+                },
+                "auto-compaction-mode": {
+                    "description": "AutoCompactionMode is either 'periodic' or 'revision'. The default value is 'periodic'.",
+                    "type": "string"
+                },
+                "auto-compaction-retention-v2": {
+                    "description": "AutoCompactionRetention is either duration string with time unit\n(e.g. '5m' for 5-minute), or revision unit (e.g. '5000').\nIf no time unit is provided and compaction mode is 'periodic',\nthe unit defaults to hour. For example, '5' translates into 5-hour.\nThe default retention is 1 hour.\nBefore etcd v3.3.x, the type of retention is int. We add 'v2' suffix to make it backward compatible.",
+                    "type": "string"
+                },
+                "client-urls": {
+                    "type": "string"
+                },
+                "cluster-version": {
+                    "type": "object",
+                    "$ref": "#/definitions/semver.Version"
+                },
+                "dashboard": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.DashboardConfig"
+                },
+                "data-dir": {
+                    "type": "string"
+                },
+                "disableStrictReconfigCheck": {
+                    "type": "boolean"
+                },
+                "electionInterval": {
+                    "description": "ElectionInterval is the interval for etcd Raft election.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "enable-grpc-gateway": {
+                    "type": "boolean"
+                },
+                "enable-grpc-web": {
+                    "description": "EnableGRPCWeb serves the gRPC-Web requests of the browsers on\nGRPCWebAddr, which are translated to the gRPC requests. The TLS config\nis the same as the gRPC server.",
+                    "type": "boolean"
+                },
+                "enable-local-tso": {
+                    "description": "EnableLocalTSO is used to enable the Local TSO Allocator feature,\nwhich allows the PD server to generate Local TSO for certain DC-level transactions.\nTo make this feature meaningful, user has to set the \"zone\" label for the PD server\nto indicate which DC this PD belongs to.",
+                    "type": "boolean"
+                },
+                "enableAuditMiddleware": {
+                    "type": "boolean"
+                },
+                "force-new-cluster": {
+                    "type": "boolean"
+                },
+                "grpc-web-addr": {
+                    "description": "GRPCWebAddr is the address to listen for the gRPC-Web requests.",
+                    "type": "string"
+                },
+                "grpc-web-allowed-origins": {
+                    "description": "GRPCWebAllowedOrigins are the origins of the web pages allowed to send\nthe cross-origin gRPC-Web requests, such as \"https://dashboard.example.com\",\n\"*\" allows any origin. Only the same-origin requests are allowed if it is\nempty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "heartbeatStreamBindInterval": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "initial-cluster": {
+                    "type": "string"
+                },
+                "initial-cluster-state": {
+                    "type": "string"
+                },
+                "initial-cluster-token": {
+                    "type": "string"
+                },
+                "join": {
+                    "description": "Join to an existing pd cluster, a string of endpoints.",
+                    "type": "string"
+                },
+                "label-property": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.LabelPropertyConfig"
+                },
+                "labels": {
+                    "description": "Labels indicates the labels set for **this** PD server. The labels describe some specific properties\nlike ` + "`" + `zone` + "`" + `/` + "`" + `rack` + "`" + `/` + "`" + `host` + "`" + `. Currently, labels won't affect the PD server except for some special\nlabel keys. Now we have following special keys:\n1. 'zone' is a special key that indicates the DC location of this PD server. If it is set, the value for this\nwill be used to determine which DC's Local TSO service this PD will provide with if EnableLocalTSO is true.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "leaderPriorityCheckInterval": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "lease": {
+                    "description": "LeaderLease time, if leader doesn't update its TTL\nin etcd after lease time, etcd will expire the leader key\nand other servers can campaign the leader again.\nEtcd only supports seconds TTL, so here is second too.",
+                    "type": "integer"
+                },
+                "log": {
+                    "description": "Log related config.",
+                    "type": "object",
+                    "$ref": "#/definitions/log.Config"
+                },
+                "log-file": {
+                    "description": "Backward compatibility.",
+                    "type": "string"
+                },
+                "log-level": {
+                    "type": "string"
+                },
+                "max-request-bytes": {
+                    "type": "integer"
+                },
+                "metric": {
+                    "type": "object",
+                    "$ref": "#/definitions/metricutil.MetricConfig"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pd-server": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.PDServerConfig"
+                },
+                "peer-urls": {
+                    "type": "string"
+                },
+                "preVote": {
+                    "description": "Prevote is true to enable Raft Pre-Vote.\nIf enabled, Raft runs an additional election phase\nto check whether it would get enough votes to win\nan election, thus minimizing disruptions.",
+                    "type": "boolean"
+                },
+                "quota-backend-bytes": {
+                    "description": "QuotaBackendBytes Raise alarms when backend size exceeds the given quota. 0 means use the default quota.\nthe default size is 2GB, the maximum is 8GB.",
+                    "type": "integer"
+                },
+                "replication": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.ReplicationConfig"
+                },
+                "replication-mode": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.ReplicationModeConfig"
+                },
+                "rpc-deadlines": {
+                    "description": "RPCDeadlines are the max deadlines of the unary gRPC methods, such as\n\"GetRegion\". The longer deadlines provided by the clients are truncated,\nand they are used if the clients provide no deadline.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/typeutil.Duration"
+                    }
+                },
+                "schedule": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.ScheduleConfig"
+                },
+                "security": {
+                    "type": "object",
+                    "$ref": "#/definitions/config.SecurityConfig"
+                },
+                "tickInterval": {
+                    "description": "TickInterval is the interval for etcd Raft tick.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "tso-client-burst": {
+                    "description": "TSOClientBurst is the max burst of TSO requests of every client.",
+                    "type": "integer"
+                },
+                "tso-client-rate-limit": {
+                    "description": "TSOClientRateLimit is the max number of TSO requests per second of every client,\nwhich is identified by the CN of its certificate or its IP address. The requests\nforwarded by a follower are limited as the ones of the follower. 0 means no limit.",
+                    "type": "number"
+                },
+                "tso-clock-drift-fatal-threshold": {
+                    "description": "TSOClockDriftFatalThreshold is the max drift between the system time and the physical part\nof TSO before the TSO allocator steps down from the leadership. 0 means never step down.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "tso-clock-drift-warning-threshold": {
+                    "description": "TSOClockDriftWarningThreshold is the max drift between the system time and the physical\npart of TSO before a warning is logged. The drift usually happens when the system time\nfalls back, e.g., due to NTP corrections.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "tso-hlc-compatible": {
+                    "description": "TSOHLCCompatible keeps the standard layout of the timestamps in HLC mode, which has the physical\npart in the high 46 bits and the logical part in the low 18 bits, for the clients which parse the\nphysical time from the timestamps. Otherwise the timestamps are encoded in the HLC layout, which\nhas the physical part in the high 48 bits and the logical part in the low 16 bits.",
+                    "type": "boolean"
+                },
+                "tso-hlc-mode": {
+                    "description": "TSOHLCMode makes the TSO allocator work as a hybrid logical clock, which advances the physical\npart with the system time on allocation instead of waiting for the periodical update, and limits\nthe logical part to 16 bits. It's enough for causally consistent reads rather than linearizable ones.",
+                    "type": "boolean"
+                },
+                "tso-save-interval": {
+                    "description": "TSOSaveInterval is the interval to save timestamp.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "tso-update-physical-interval": {
+                    "description": "The interval to update physical part of timestamp. Usually, this config should not be set.\nIt's only useful for test purposes.\nThis config is only valid in 50ms to 10s. If it's configured too long or too short, it will\nbe automatically clamped to the range.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "warningMsgs": {
+                    "description": "For all warnings during parsing.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.DRAutoSyncReplicationConfig": {
+            "type": "object",
+            "properties": {
+                "dr": {
+                    "type": "string"
+                },
+                "dr-replicas": {
+                    "type": "integer"
+                },
+                "label-key": {
+                    "type": "string"
+                },
+                "max-allowed-lag-bytes": {
+                    "description": "MaxAllowedLagBytes blocks switching from sync to async if the dr stores\nlag behind more than it, 0 means no limit.",
+                    "type": "integer"
+                },
+                "primary": {
+                    "type": "string"
+                },
+                "primary-replicas": {
+                    "type": "integer"
+                },
+                "wait-async-timeout": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "wait-store-timeout": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "wait-sync-timeout": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                }
+            }
+        },
+        "config.DashboardConfig": {
+            "type": "object",
+            "properties": {
+                "disable-telemetry": {
+                    "description": "WARN: DisableTelemetry is deprecated.",
+                    "type": "boolean"
+                },
+                "enable-experimental": {
+                    "type": "boolean"
+                },
+                "enable-telemetry": {
+                    "type": "boolean"
+                },
+                "internal-proxy": {
+                    "type": "boolean"
+                },
+                "public-path-prefix": {
+                    "type": "string"
+                },
+                "tidb-cacert-path": {
+                    "type": "string"
+                },
+                "tidb-cert-path": {
+                    "type": "string"
+                },
+                "tidb-key-path": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.LabelPropertyConfig": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "$ref": "#/definitions/config.StoreLabel"
+                }
+            }
+        },
+        "config.PDServerConfig": {
+            "type": "object",
+            "properties": {
+                "dashboard-address": {
+                    "description": "There are some values supported: \"auto\", \"none\", or a specific address, default: \"auto\"",
+                    "type": "string"
+                },
+                "event-buffer-size": {
+                    "description": "EventBufferSize is the number of the cluster events buffered for each\nsubscriber of the events API, the events are dropped if the buffer is full.",
+                    "type": "integer"
+                },
+                "flow-round-by-digit": {
+                    "description": "FlowRoundByDigit used to discretization processing flow information.",
+                    "type": "integer"
+                },
+                "key-type": {
+                    "description": "KeyType is option to specify the type of keys.\nThere are some types supported: [\"table\", \"raw\", \"txn\"], default: \"table\"",
+                    "type": "string"
+                },
+                "max-gap-reset-ts": {
+                    "description": "MaxResetTSGap is the max gap to reset the TSO.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "max-genealogy-entries": {
+                    "description": "MaxGenealogyEntries is the max number of the splits and merges kept in\nthe region genealogy, the oldest ones are removed.",
+                    "type": "integer"
+                },
+                "metric-storage": {
+                    "description": "MetricStorage is the cluster metric storage.\nCurrently we use prometheus as metric storage, we may use PD/TiKV as metric storage later.",
+                    "type": "string"
+                },
+                "min-resolved-ts-persistence-interval": {
+                    "description": "MinResolvedTSPersistenceInterval is the interval to save the min resolved ts.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "region-traffic-samples": {
+                    "description": "RegionTrafficSamples is the number of the recent heartbeats whose traffic\nis kept for each region, the traffic is not kept if it is 0.",
+                    "type": "integer"
+                },
+                "runtime-services": {
+                    "description": "RuntimeServices is the running the running extension services.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.StringSlice"
+                },
+                "trace-region-flow": {
+                    "description": "TraceRegionFlow the option to update flow information of regions.\nWARN: TraceRegionFlow is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "use-region-storage": {
+                    "description": "UseRegionStorage enables the independent region storage.",
+                    "type": "string",
+                    "example": "false"
+                }
+            }
+        },
+        "config.ReplicationConfig": {
+            "type": "object",
+            "properties": {
+                "enable-placement-rules": {
+                    "description": "When PlacementRules feature is enabled. MaxReplicas, LocationLabels and IsolationLabels are not used any more.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-placement-rules-cache": {
+                    "description": "EnablePlacementRuleCache controls whether use cache during rule checker",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-rack-isolation": {
+                    "description": "EnableRackIsolation is the option to move the replicas sharing a rack\nto different racks if it is possible.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "isolation-level": {
+                    "description": "IsolationLevel is used to isolate replicas explicitly and forcibly if it's not empty.\nIts value must be empty or one of LocationLabels.\nExample:\nlocation-labels = [\"zone\", \"rack\", \"host\"]\nisolation-level = \"zone\"\nWith configuration like above, PD ensure that all replicas be placed in different zones.\nEven if a zone is down, PD will not try to make up replicas in other zone\nbecause other zones already have replicas on it.",
+                    "type": "string"
+                },
+                "location-labels": {
+                    "description": "The label keys specified the location of a store.\nThe placement priorities is implied by the order of label keys.\nFor example, [\"zone\", \"rack\"] means that we should place replicas to\ndifferent zones first, then to different racks if we don't have enough zones.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.StringSlice"
+                },
+                "max-replicas": {
+                    "description": "MaxReplicas is the number of replicas for each region.",
+                    "type": "integer"
+                },
+                "rack-label-key": {
+                    "description": "RackLabelKey is the label key of the store specifying the rack, racks can\nbe a sub-label of other location labels, e.g. \"zone\".",
+                    "type": "string"
+                },
+                "strictly-match-label": {
+                    "description": "StrictlyMatchLabel strictly checks if the label of TiKV is matched with LocationLabels.",
+                    "type": "string",
+                    "example": "false"
+                }
+            }
+        },
+        "config.ReplicationModeConfig": {
+            "type": "object",
+            "properties": {
+                "dr-auto-sync": {
+                    "description": "used when ReplicationMode is 'dr-auto-sync'",
+                    "type": "object",
+                    "$ref": "#/definitions/config.DRAutoSyncReplicationConfig"
+                },
+                "replication-mode": {
+                    "description": "can be 'dr-auto-sync' or 'majority', default value is 'majority'",
+                    "type": "string"
+                },
+                "replication-peers": {
+                    "description": "ReplicationPeers are the spokes in a hub-and-spoke topology, whose\nreplication lag is tracked in every replication mode.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ReplicationPeer"
+                    }
+                }
+            }
+        },
+        "config.ReplicationPeer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "max-allowed-lag-ms": {
+                    "description": "MaxAllowedLagMs is the lag tolerance of the peer, 0 means no limit.",
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.ScheduleConfig": {
+            "type": "object",
+            "properties": {
+                "compaction-reserved-space": {
+                    "description": "CompactionReservedSpace is the space reserved for the compaction of a\nstore, it is regarded as used when checking the critical space.",
+                    "type": "integer"
+                },
+                "critical-space-ratio": {
+                    "description": "CriticalSpaceRatio is the usage ratio of store which regarded as critical space.\nWhen in critical space, the peers on the store are moved away aggressively.",
+                    "type": "number"
+                },
+                "disable-location-replacement": {
+                    "description": "DisableLocationReplacement is the option to prevent replica checker from\nmoving replica to a better location.\nWARN: DisableLocationReplacement is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "disable-make-up-replica": {
+                    "description": "DisableMakeUpReplica is the option to prevent replica checker from making up\nreplicas when replica count is less than expected.\nWARN: DisableMakeUpReplica is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "disable-raft-learner": {
+                    "description": "WARN: DisableLearner is deprecated.\nDisableLearner is the option to disable using AddLearnerNode instead of AddNode.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "disable-remove-down-replica": {
+                    "description": "DisableRemoveDownReplica is the option to prevent replica checker from\nremoving down replicas.\nWARN: DisableRemoveDownReplica is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "disable-remove-extra-replica": {
+                    "description": "DisableRemoveExtraReplica is the option to prevent replica checker from\nremoving extra replicas.\nWARN: DisableRemoveExtraReplica is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "disable-replace-offline-replica": {
+                    "description": "DisableReplaceOfflineReplica is the option to prevent replica checker from\nreplacing offline replicas.\nWARN: DisableReplaceOfflineReplica is deprecated.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-cross-table-merge": {
+                    "description": "EnableCrossTableMerge is the option to enable cross table merge. This means two Regions can be merged with different table IDs.\nThis option only works when key type is \"table\".",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-debug-metrics": {
+                    "description": "EnableDebugMetrics is the option to enable debug metrics.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-joint-consensus": {
+                    "description": "EnableJointConsensus is the option to enable using joint consensus as a operator step.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-location-replacement": {
+                    "description": "EnableLocationReplacement is the option to enable replica checker to move replica to a better location.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-make-up-replica": {
+                    "description": "EnableMakeUpReplica is the option to enable replica checker to make up replica.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-one-way-merge": {
+                    "description": "EnableOneWayMerge is the option to enable one way merge. This means a Region can only be merged into the next region of it.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-remove-down-replica": {
+                    "description": "EnableRemoveDownReplica is the option to enable replica checker to remove down replica.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-remove-extra-replica": {
+                    "description": "EnableRemoveExtraReplica is the option to enable replica checker to remove extra replica.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-remove-orphan-learner": {
+                    "description": "EnableRemoveOrphanLearner is the option to enable orphan learner checker to remove the learners on\nthe tombstone stores or the stores which have been down for a long time.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-replace-offline-replica": {
+                    "description": "EnableReplaceOfflineReplica is the option to enable replica checker to replace offline replica.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-store-flow-conflict": {
+                    "description": "EnableStoreFlowConflict is the option to refuse the operators which move\nthe leaders or the peers of a store in the opposite direction of the\noperators generated by other schedulers.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "enable-zone-aware-leader": {
+                    "description": "EnableZoneAwareLeader is the option to allow the zone-aware-leader\nscheduler to move the leaders to the zones sending the most requests.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "high-space-ratio": {
+                    "description": "HighSpaceRatio is the highest usage ratio of store which regraded as high space.\nHigh space means there is a lot of spare capacity, and store region score varies directly with used size.",
+                    "type": "number"
+                },
+                "hot-cache-decay-rate": {
+                    "description": "HotCacheDecayRate is the rate per second the loads of a hot peer decay\nexponentially after HotCacheEntryTTL.",
+                    "type": "number"
+                },
+                "hot-cache-entry-ttl": {
+                    "description": "HotCacheEntryTTL is the time after which the loads of a hot peer not\nupdated start to decay, 0 means the loads never decay.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "hot-long-window": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "hot-medium-window": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "hot-region-cache-hits-threshold": {
+                    "description": "HotRegionCacheHitThreshold is the cache hits threshold of the hot region.\nIf the number of times a region hits the hot cache is greater than this\nthreshold, it is considered a hot region.",
+                    "type": "integer"
+                },
+                "hot-region-schedule-limit": {
+                    "description": "HotRegionScheduleLimit is the max coexist hot region schedules.",
+                    "type": "integer"
+                },
+                "hot-regions-reserved-days": {
+                    "description": "The day of hot regions data to be reserved. 0 means close.",
+                    "type": "integer"
+                },
+                "hot-regions-write-interval": {
+                    "description": "Controls the time interval between write hot regions info into leveldb.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "hot-short-window": {
+                    "description": "HotShortWindow, HotMediumWindow and HotLongWindow are the windows to\ncalculate the heat scores of the hot peers, which are the weighted\naverages of the loads in the windows by HotWindowWeights. They help to\ndistinguish the sustained hotspots from the traffic bursts.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "hot-window-weights": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "leader-schedule-limit": {
+                    "description": "LeaderScheduleLimit is the max coexist leader schedules.",
+                    "type": "integer"
+                },
+                "leader-schedule-policy": {
+                    "description": "LeaderSchedulePolicy is the option to balance leader, there are some policies supported: [\"count\", \"size\"], default: \"count\"",
+                    "type": "string"
+                },
+                "low-space-ratio": {
+                    "description": "high space stage         transition stage           low space stage\n  |--------------------|-----------------------------|-------------------------|\n  ^                    ^                             ^                         ^\n  0       HighSpaceRatio * capacity       LowSpaceRatio * capacity          capacity\n\nLowSpaceRatio is the lowest usage ratio of store which regraded as low space.\nWhen in low space, store region score increases to very large and varies inversely with available size.",
+                    "type": "number"
+                },
+                "max-merge-region-keys": {
+                    "type": "integer"
+                },
+                "max-merge-region-size": {
+                    "description": "If both the size of region is smaller than MaxMergeRegionSize\nand the number of rows in region is smaller than MaxMergeRegionKeys,\nit will try to merge with adjacent regions.",
+                    "type": "integer"
+                },
+                "max-operators-per-store": {
+                    "description": "MaxOperatorsPerStore is the max coexist operators which move peers or\nleaders into the same store, except the ones repairing the replicas or\ncreated by the administrator. 0 means no limit.",
+                    "type": "integer"
+                },
+                "max-pending-peer-count": {
+                    "type": "integer"
+                },
+                "max-snapshot-bytes-in-flight": {
+                    "description": "MaxSnapshotBytesInFlight is the max total size of the snapshots sent by\nthe running operators which add peers. 0 means no limit.",
+                    "type": "integer"
+                },
+                "max-snapshot-count": {
+                    "description": "If the snapshot count of one store is greater than this value,\nit will never be used as a source or target store.",
+                    "type": "integer"
+                },
+                "max-store-down-time": {
+                    "description": "MaxStoreDownTime is the max duration after which\na store will be considered to be down if it hasn't reported heartbeats.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "merge-schedule-limit": {
+                    "description": "MergeScheduleLimit is the max coexist merge schedules.",
+                    "type": "integer"
+                },
+                "operator-audit-log-capacity": {
+                    "description": "OperatorAuditLogCapacity is the max number of the finished operators\nkept in the operator audit log. 0 means the audit log is disabled.",
+                    "type": "integer"
+                },
+                "patrol-region-interval": {
+                    "description": "PatrolRegionInterval is the interval for scanning region during patrol.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "pin-read-hot-regions": {
+                    "description": "PinReadHotRegions is the option to pin the leaders of the regions whose\nleader read QPS exceeds ReadHotPinThreshold to their current stores. The\nhot region scheduler does not move these leaders, to avoid the latency\nspike caused by the leader election.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "read-hot-pin-threshold": {
+                    "type": "number"
+                },
+                "region-io-imbalance-threshold": {
+                    "description": "If the disk IO rate of a store exceeds (1 + RegionIOImbalanceThreshold)\ntimes that of another store, the balance region scheduler moves regions\nbetween them even if their region scores are balanced. 0 means the disk\nIO rates are not considered.",
+                    "type": "number"
+                },
+                "region-schedule-limit": {
+                    "description": "RegionScheduleLimit is the max coexist region schedules.",
+                    "type": "integer"
+                },
+                "region-score-formula-version": {
+                    "description": "RegionScoreFormulaVersion is used to control the formula used to calculate region score.",
+                    "type": "string"
+                },
+                "replica-schedule-limit": {
+                    "description": "ReplicaScheduleLimit is the max coexist replica schedules.",
+                    "type": "integer"
+                },
+                "scheduler-max-waiting-operator": {
+                    "description": "SchedulerMaxWaitingOperator is the max coexist operators for each scheduler.",
+                    "type": "integer"
+                },
+                "schedulers-payload": {
+                    "description": "Only used to display",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "schedulers-v2": {
+                    "description": "Schedulers support for loading customized schedulers",
+                    "type": "object",
+                    "$ref": "#/definitions/config.SchedulerConfigs"
+                },
+                "slow-store-read-latency-threshold-us": {
+                    "description": "SlowStoreReadLatencyThresholdUs and SlowStoreWriteLatencyThresholdUs\nare the p99 latency thresholds in microseconds of the store. A store\nexceeding them for consecutive heartbeats is regarded as slow and is\nnot selected as the target of leaders. 0 means no threshold.",
+                    "type": "integer"
+                },
+                "slow-store-write-latency-threshold-us": {
+                    "type": "integer"
+                },
+                "split-merge-interval": {
+                    "description": "SplitMergeInterval is the minimum interval time to permit merge after split.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "split-qps-threshold": {
+                    "description": "If the read QPS of a region exceeds SplitQPSThreshold or the write QPS\nexceeds SplitWriteQPSThreshold, it will be split to distribute the load.\n0 means the region is never split by the QPS.",
+                    "type": "number"
+                },
+                "split-write-bytes-threshold": {
+                    "description": "If the write byte rate of a region exceeds SplitWriteBytesThreshold, it\nwill be split even if it has few keys. 0 means never.",
+                    "type": "integer"
+                },
+                "split-write-qps-threshold": {
+                    "type": "number"
+                },
+                "store-balance-rate": {
+                    "description": "StoreBalanceRate is the maximum of balance rate for each store.\nWARN: StoreBalanceRate is deprecated.",
+                    "type": "number"
+                },
+                "store-bandwidth": {
+                    "description": "StoreBandwidth is the network bandwidth of the stores, which is used to\nestimate the max number of the concurrent snapshots of a store.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.StoreBandwidthConfig"
+                    }
+                },
+                "store-limit": {
+                    "description": "StoreLimit is the limit of scheduling for stores.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.StoreLimitConfig"
+                    }
+                },
+                "store-limit-mode": {
+                    "description": "StoreLimitMode can be auto or manual, when set to auto,\nPD tries to change the store limit values according to\nthe load state of the cluster dynamically. User can\noverwrite the auto-tuned value by pd-ctl, when the value\nis overwritten, the value is fixed until it is deleted.\nDefault: manual",
+                    "type": "string"
+                },
+                "store-warm-up-duration": {
+                    "description": "StoreWarmUpDuration is the duration during which the usable capacity of\na newly joined store ramps up, so that it does not receive too many\nregions at once. 0 means the new stores do not warm up.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "tolerant-size-ratio": {
+                    "description": "TolerantSizeRatio is the ratio of buffer size for balance scheduler.",
+                    "type": "number"
+                },
+                "tombstone-store-cleanup-dry-run": {
+                    "description": "TombstoneStoreCleanupDryRun makes the cleanup of the tombstone stores\nonly log the stores to be removed. It is true by default, so the old\ntombstone stores are not removed after upgrading unless it is disabled.",
+                    "type": "string",
+                    "example": "false"
+                },
+                "tombstone-store-retention-days": {
+                    "description": "The days after which the tombstone stores are removed. 0 means never.",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.SchedulerConfig": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "args-payload": {
+                    "type": "string"
+                },
+                "disable": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.SchedulerConfigs": {
+            "type": "array",
+            "items": {
+                "$ref": "#/definitions/config.SchedulerConfig"
+            }
+        },
+        "config.SecurityConfig": {
+            "type": "object",
+            "properties": {
+                "api-allow-cidrs": {
+                    "description": "APIAllowCIDRs are the networks allowed to access the HTTP and gRPC APIs,\nall networks are allowed if it is empty. Note that the other PD members\nmust be allowed to forward the requests and sync the regions.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "api-deny-cidrs": {
+                    "description": "APIDenyCIDRs are the networks denied to access the HTTP and gRPC APIs,\nthey take precedence over APIAllowCIDRs.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "audit-log": {
+                    "description": "AuditLog is the audit log of the mutating HTTP API calls.",
+                    "type": "object",
+                    "$ref": "#/definitions/config.AuditLogConfig"
+                },
+                "cacert-path": {
+                    "description": "CAPath is the path of file that contains list of trusted SSL CAs. if set, following four settings shouldn't be empty",
+                    "type": "string"
+                },
+                "cert-allowed-cn": {
+                    "description": "CertAllowedCN is a CN which must be provided by a client",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cert-path": {
+                    "description": "CertPath is the path of file that contains X509 certificate in PEM format.",
+                    "type": "string"
+                },
+                "enable-grpc-reflection": {
+                    "description": "EnableGRPCReflection registers the gRPC reflection service for the tools\nlike grpcurl to discover the RPCs. It only takes effect if TLS is enabled.",
+                    "type": "boolean"
+                },
+                "enable-jwt-auth": {
+                    "description": "EnableJWTAuth enables the users stored in PD to get the tokens from\n` + "`" + `/pd/api/v1/auth/token` + "`" + `, the tokens are accepted by the HTTP API like the\nAPITokens. It requires an admin token in APITokens to create the users.",
+                    "type": "boolean"
+                },
+                "encryption": {
+                    "type": "object",
+                    "$ref": "#/definitions/encryption.Config"
+                },
+                "jwt-expiry": {
+                    "description": "JWTExpiry is how long the issued tokens are valid.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "key-path": {
+                    "description": "KeyPath is the path of file that contains X509 key in PEM format.",
+                    "type": "string"
+                },
+                "redact-info-log": {
+                    "description": "RedactInfoLog indicates that whether enabling redact log",
+                    "type": "boolean"
+                },
+                "sslcabytes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sslcertBytes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sslkeybytes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "config.StoreBandwidthConfig": {
+            "type": "object",
+            "properties": {
+                "max-recv-bandwidth-mbps": {
+                    "type": "number"
+                },
+                "max-send-bandwidth-mbps": {
+                    "description": "MaxSendBandwidthMBps and MaxRecvBandwidthMBps are the bandwidth in MB/s\nfor sending and receiving the snapshots, 0 means no limit.",
+                    "type": "number"
+                }
+            }
+        },
+        "config.StoreLabel": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.StoreLimitConfig": {
+            "type": "object",
+            "properties": {
+                "add-peer": {
+                    "type": "number"
+                },
+                "remove-peer": {
+                    "type": "number"
+                }
+            }
+        },
+        "encryption.Config": {
+            "type": "object",
+            "properties": {
+                "data-encryption-method": {
+                    "description": "Encryption method to use for PD data.",
+                    "type": "string"
+                },
+                "data-key-rotation-period": {
+                    "description": "Specifies how often PD rotates data encryption key.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "master-key": {
+                    "description": "Specifies master key if encryption is enabled.",
+                    "type": "object",
+                    "$ref": "#/definitions/encryption.MasterKeyConfig"
+                }
+            }
+        },
+        "encryption.MasterKeyConfig": {
+            "type": "object",
+            "properties": {
+                "endpoint": {
+                    "description": "Custom endpoint to access KMS.",
+                    "type": "string"
+                },
+                "key-id": {
+                    "description": "KMS CMK key id.",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Master key file path.",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "KMS region of the CMK.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Master key type, one of \"plaintext\", \"kms\" or \"file\".",
+                    "type": "string"
+                }
+            }
+        },
+        "encryptionpb.EncryptionMeta": {
+            "type": "object",
+            "properties": {
+                "iv": {
+                    "description": "Initialization vector (IV) of the data.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "key_id": {
+                    "description": "ID of the key used to encrypt the data.",
+                    "type": "integer"
+                }
+            }
+        },
+        "endpoint.KeyspaceAffinity": {
+            "type": "object",
+            "properties": {
+                "keyspace_id": {
+                    "type": "integer"
+                },
+                "required_store_labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "endpoint.KeyspaceMeta": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "end_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "start_key": {
+                    "description": "StartKey and EndKey are the hex-encoded key range routed to the keyspace.",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "description": "TTLSeconds is the time to live of the data in the keyspace, the data is\nkept forever if it is 0.",
+                    "type": "integer"
+                }
+            }
+        },
+        "endpoint.ServiceSafePoint": {
+            "type": "object",
+            "properties": {
+                "expired_at": {
+                    "type": "integer"
+                },
+                "safe_point": {
+                    "type": "integer"
+                },
+                "service_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.ClusterEvent": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "region_id": {
+                    "description": "RegionID is set if the event is about a region.",
+                    "type": "integer"
+                },
+                "step": {
+                    "description": "Step is set if the event is about an operator step.",
+                    "type": "object",
+                    "$ref": "#/definitions/events.OperatorStep"
+                },
+                "store_id": {
+                    "description": "StoreID is set if the event is about a store.",
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.OperatorStep": {
+            "type": "object",
+            "properties": {
+                "elapsed": {
+                    "description": "Elapsed is the time since the operator started.",
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "description": "StoreID is the store which the step works on, it is 0 if the step is\nnot about a single store, e.g. merging regions.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "genealogy.Chain": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "description": "Ancestors are the entries which the region is derived from, in the\norder of the sequence.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genealogy.Entry"
+                    }
+                },
+                "descendants": {
+                    "description": "Descendants are the entries derived from the region, in the order of\nthe sequence.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genealogy.Entry"
+                    }
+                },
+                "region-id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genealogy.Entry": {
+            "type": "object",
+            "properties": {
+                "child-ids": {
+                    "description": "ChildIDs are the new regions split from the parent.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "parent-id": {
+                    "description": "ParentID is the region which is split, it keeps one of the ranges.",
+                    "type": "integer"
+                },
+                "result-id": {
+                    "description": "ResultID is the region which the sources are merged into.",
+                    "type": "integer"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "source-ids": {
+                    "description": "SourceIDs are the regions merged into the result.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "split-keys": {
+                    "description": "SplitKeys are the hex encoded keys where the parent is split.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "labeler.LabelRule": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/labeler.RegionLabel"
+                    }
+                },
+                "rule_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "labeler.LabelRulePatch": {
+            "type": "object",
+            "properties": {
+                "deletes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/labeler.LabelRule"
+                    }
+                }
+            }
+        },
+        "labeler.RegionLabel": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "log.Config": {
+            "type": "object",
+            "properties": {
+                "development": {
+                    "description": "Development puts the logger in development mode, which changes the\nbehavior of DPanicLevel and takes stacktraces more liberally.",
+                    "type": "boolean"
+                },
+                "disable-caller": {
+                    "description": "DisableCaller stops annotating logs with the calling function's file\nname and line number. By default, all logs are annotated.",
+                    "type": "boolean"
+                },
+                "disable-error-verbose": {
+                    "description": "DisableErrorVerbose stops annotating logs with the full verbose error\nmessage.",
+                    "type": "boolean"
+                },
+                "disable-stacktrace": {
+                    "description": "DisableStacktrace completely disables automatic stacktrace capturing. By\ndefault, stacktraces are captured for WarnLevel and above logs in\ndevelopment and ErrorLevel and above in production.",
+                    "type": "boolean"
+                },
+                "disable-timestamp": {
+                    "description": "Disable automatic timestamps in output.",
+                    "type": "boolean"
+                },
+                "file": {
+                    "description": "File log config.",
+                    "type": "object",
+                    "$ref": "#/definitions/log.FileLogConfig"
+                },
+                "format": {
+                    "description": "Log format. one of json, text, or console.",
+                    "type": "string"
+                },
+                "level": {
+                    "description": "Log level.",
+                    "type": "string"
+                },
+                "sampling": {
+                    "description": "SamplingConfig sets a sampling strategy for the logger. Sampling caps the\nglobal CPU and I/O load that logging puts on your process while attempting\nto preserve a representative subset of your logs.\n\nValues configured here are per-second. See zapcore.NewSampler for details.",
+                    "type": "object",
+                    "$ref": "#/definitions/zap.SamplingConfig"
+                }
+            }
+        },
+        "log.FileLogConfig": {
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "description": "Log filename, leave empty to disable file log.",
+                    "type": "string"
+                },
+                "max-backups": {
+                    "description": "Maximum number of old log files to retain.",
+                    "type": "integer"
+                },
+                "max-days": {
+                    "description": "Max log keep days, default is never deleting.",
+                    "type": "integer"
+                },
+                "max-size": {
+                    "description": "Max size for a single file, in MB.",
+                    "type": "integer"
+                }
+            }
+        },
+        "log.ZapProperties": {
+            "type": "object",
+            "properties": {
+                "core": {
+                    "type": "object",
+                    "$ref": "#/definitions/zapcore.Core"
+                },
+                "level": {
+                    "type": "object",
+                    "$ref": "#/definitions/zap.AtomicLevel"
+                },
+                "syncer": {
+                    "type": "object",
+                    "$ref": "#/definitions/zapcore.WriteSyncer"
+                }
+            }
+        },
+        "metapb.Cluster": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "max_peer_count": {
+                    "description": "max peer count for a region.\npd will do the auto-balance if region peer count mismatches.",
+                    "type": "integer"
+                }
+            }
+        },
+        "metapb.Peer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "metapb.RegionEpoch": {
+            "type": "object",
+            "properties": {
+                "conf_ver": {
+                    "description": "Conf change version, auto increment when add or remove peer",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Region version, auto increment when split or merge",
+                    "type": "integer"
+                }
+            }
+        },
+        "metapb.StoreLabel": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "metricutil.MetricConfig": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "object",
+                    "$ref": "#/definitions/typeutil.Duration"
+                },
+                "job": {
+                    "type": "string"
+                }
+            }
+        },
+        "operator.OpRecord": {
+            "type": "object",
+            "properties": {
+                "additionalInfos": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "approximateSize": {
+                    "type": "integer"
+                },
+                "counters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "finishTime": {
+                    "type": "string"
+                },
+                "finishedCounters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "rollbackSteps": {
+                    "description": "RollbackSteps are the steps compensating for the steps, the i-th one is\nfor the i-th step, nil means nothing needs to be compensated.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/operator.OpStep"
+                    }
+                }
+            }
+        },
+        "operator.OpStep": {
+            "type": "object"
+        },
+        "operator.Operator": {
+            "type": "object",
+            "properties": {
+                "additionalInfos": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "approximateSize": {
+                    "type": "integer"
+                },
+                "counters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "finishedCounters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "rollbackSteps": {
+                    "description": "RollbackSteps are the steps compensating for the steps, the i-th one is\nfor the i-th step, nil means nothing needs to be compensated.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/operator.OpStep"
+                    }
+                }
+            }
+        },
+        "pdpb.Error": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pdpb.GetMembersResponse": {
+            "type": "object",
+            "properties": {
+                "etcd_leader": {
+                    "type": "object",
+                    "$ref": "#/definitions/pdpb.Member"
+                },
+                "header": {
+                    "type": "object",
+                    "$ref": "#/definitions/pdpb.ResponseHeader"
+                },
+                "leader": {
+                    "type": "object",
+                    "$ref": "#/definitions/pdpb.Member"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pdpb.Member"
+                    }
+                },
+                "tso_allocator_leaders": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/pdpb.Member"
+                    }
+                }
+            }
+        },
+        "pdpb.Member": {
+            "type": "object",
+            "properties": {
+                "binary_version": {
+                    "type": "string"
+                },
+                "client_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dc_location": {
+                    "type": "string"
+                },
+                "deploy_path": {
+                    "type": "string"
+                },
+                "git_hash": {
+                    "type": "string"
+                },
+                "leader_priority": {
+                    "type": "integer"
+                },
+                "member_id": {
+                    "description": "member_id is the unique id of the PD member.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "name is the name of the PD member.",
+                    "type": "string"
+                },
+                "peer_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "pdpb.ResponseHeader": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "description": "cluster_id is the ID of the cluster which sent the response.",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/pdpb.Error"
+                }
+            }
+        },
+        "pflag.FlagSet": {
+            "type": "object",
+            "properties": {
+                "parseErrorsWhitelist": {
+                    "description": "ParseErrorsWhitelist is used to configure a whitelist of errors",
+                    "type": "object",
+                    "$ref": "#/definitions/pflag.ParseErrorsWhitelist"
+                },
+                "sortFlags": {
+                    "description": "SortFlags is used to indicate, if user wants to have sorted flags in\nhelp/usage messages.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "pflag.ParseErrorsWhitelist": {
+            "type": "object",
+            "properties": {
+                "unknownFlags": {
+                    "description": "UnknownFlags will ignore unknown flags errors and continue parsing rest of the flags",
+                    "type": "boolean"
+                }
+            }
+        },
+        "placement.GroupBundle": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string"
+                },
+                "group_index": {
+                    "type": "integer"
+                },
+                "group_override": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.Rule"
+                    }
+                }
+            }
+        },
+        "placement.LabelConstraint": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "placement.Rule": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "description": "the time of day in \"HH:MM[:SS]\" the rule becomes active every day",
+                    "type": "string"
+                },
+                "active_timezone": {
+                    "description": "the IANA time zone of ActiveFrom and ActiveUntil, UTC if it is empty",
+                    "type": "string"
+                },
+                "active_until": {
+                    "description": "the time of day in \"HH:MM[:SS]\" the rule becomes inactive every day",
+                    "type": "string"
+                },
+                "count": {
+                    "description": "expected count of the peers",
+                    "type": "integer"
+                },
+                "create_timestamp": {
+                    "description": "only set at runtime, recorded rule create timestamp",
+                    "type": "integer"
+                },
+                "end_key": {
+                    "description": "hex format end key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "group_id": {
+                    "description": "mark the source that add the rule",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "unique ID within a group",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "rule apply order in a group, rule with less ID is applied first when indexes are equal",
+                    "type": "integer"
+                },
+                "isolation_level": {
+                    "description": "used to isolate replicas explicitly and forcibly",
+                    "type": "string"
+                },
+                "keyspace_id": {
+                    "description": "the keyspace whose data the rule places, 0 means the rule is cluster-wide",
+                    "type": "integer"
+                },
+                "label_constraints": {
+                    "description": "used to select stores to place peers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.LabelConstraint"
+                    }
+                },
+                "location_labels": {
+                    "description": "used to make peers isolated physically",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "override": {
+                    "description": "when it is true, all rules with less indexes are disabled",
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "expected role of the peers",
+                    "type": "string"
+                },
+                "start_key": {
+                    "description": "hex format start key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "store_label_match": {
+                    "description": "used to match regions by the union of the labels of their stores",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.LabelConstraint"
+                    }
+                },
+                "supersedes": {
+                    "description": "IDs of the rules in the same group which are skipped when the rule matches",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "description": "only set at runtime, add 1 each time rules updated, begin from 0.",
+                    "type": "integer"
+                }
+            }
+        },
+        "placement.RuleConflict": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "rules": {
+                    "description": "Rules are the ` + "`" + `group/id` + "`" + ` of the conflicting rules.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "placement.RuleGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "override": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "placement.RuleOp": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "active_from": {
+                    "description": "the time of day in \"HH:MM[:SS]\" the rule becomes active every day",
+                    "type": "string"
+                },
+                "active_timezone": {
+                    "description": "the IANA time zone of ActiveFrom and ActiveUntil, UTC if it is empty",
+                    "type": "string"
+                },
+                "active_until": {
+                    "description": "the time of day in \"HH:MM[:SS]\" the rule becomes inactive every day",
+                    "type": "string"
+                },
+                "count": {
+                    "description": "expected count of the peers",
+                    "type": "integer"
+                },
+                "create_timestamp": {
+                    "description": "only set at runtime, recorded rule create timestamp",
+                    "type": "integer"
+                },
+                "delete_by_id_prefix": {
+                    "description": "if action == delete, delete by the prefix of id",
+                    "type": "boolean"
+                },
+                "end_key": {
+                    "description": "hex format end key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "group_id": {
+                    "description": "mark the source that add the rule",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "unique ID within a group",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "rule apply order in a group, rule with less ID is applied first when indexes are equal",
+                    "type": "integer"
+                },
+                "isolation_level": {
+                    "description": "used to isolate replicas explicitly and forcibly",
+                    "type": "string"
+                },
+                "keyspace_id": {
+                    "description": "the keyspace whose data the rule places, 0 means the rule is cluster-wide",
+                    "type": "integer"
+                },
+                "label_constraints": {
+                    "description": "used to select stores to place peers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.LabelConstraint"
+                    }
+                },
+                "location_labels": {
+                    "description": "used to make peers isolated physically",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "override": {
+                    "description": "when it is true, all rules with less indexes are disabled",
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "expected role of the peers",
+                    "type": "string"
+                },
+                "start_key": {
+                    "description": "hex format start key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "store_label_match": {
+                    "description": "used to match regions by the union of the labels of their stores",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.LabelConstraint"
+                    }
+                },
+                "supersedes": {
+                    "description": "IDs of the rules in the same group which are skipped when the rule matches",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "description": "only set at runtime, add 1 each time rules updated, begin from 0.",
+                    "type": "integer"
+                }
+            }
+        },
+        "placement.RuleTemplate": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.Rule"
+                    }
+                }
+            }
+        },
+        "placement.RuleTrace": {
+            "type": "object",
+            "properties": {
+                "orphan_peers": {
+                    "description": "OrphanPeers are the peers not needed by any applied rule.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.RuleTracePeer"
+                    }
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "description": "Rules are the rules of the key range of the region in priority order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.RuleTraceStep"
+                    }
+                },
+                "satisfied": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "placement.RuleTraceElimination": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "placement.RuleTracePeer": {
+            "type": "object",
+            "properties": {
+                "peer_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "placement.RuleTraceStep": {
+            "type": "object",
+            "properties": {
+                "candidate_stores": {
+                    "description": "CandidateStores are the stores matching the label constraints of the rule.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "eliminated_stores": {
+                    "description": "EliminatedStores are the stores not matching the label constraints.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.RuleTraceElimination"
+                    }
+                },
+                "isolation_score": {
+                    "type": "number"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "satisfied": {
+                    "type": "boolean"
+                },
+                "selected_peers": {
+                    "description": "SelectedPeers are the peers of the region fitted to the rule.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/placement.RuleTracePeer"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "prometheus.Counter": {
+            "type": "object"
+        },
+        "quota.RegionQuota": {
+            "type": "object",
+            "properties": {
+                "end_key": {
+                    "description": "hex format end key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "read_bytes_per_second": {
+                    "description": "ReadBytesPerSecond and WriteBytesPerSecond are the max flow of a region, 0 means no limit.",
+                    "type": "integer"
+                },
+                "start_key": {
+                    "description": "hex format start key, for marshal/unmarshal",
+                    "type": "string"
+                },
+                "write_bytes_per_second": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quota.Violation": {
+            "type": "object",
+            "properties": {
+                "quota_id": {
+                    "type": "string"
+                },
+                "read_bytes_per_second": {
+                    "type": "integer"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "write_bytes_per_second": {
+                    "type": "integer"
+                }
+            }
+        },
+        "replication.HTTPReplicationStatus": {
+            "type": "object",
+            "properties": {
+                "dr-auto-sync": {
+                    "type": "object",
+                    "properties": {
+                        "label_key": {
+                            "type": "string"
+                        },
+                        "pin_until": {
+                            "type": "string"
+                        },
+                        "pinned": {
+                            "type": "boolean"
+                        },
+                        "recover_progress": {
+                            "type": "number"
+                        },
+                        "state": {
+                            "type": "string"
+                        },
+                        "state_id": {
+                            "type": "integer"
+                        },
+                        "synced_regions": {
+                            "type": "integer"
+                        },
+                        "total_regions": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "replication.PeerLag": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "exceeded": {
+                    "description": "Exceeded is true if the peer lags longer than its lag tolerance.",
+                    "type": "boolean"
+                },
+                "lag_bytes": {
+                    "type": "integer"
+                },
+                "lag_duration_ms": {
+                    "type": "integer"
+                },
+                "max_allowed_lag_ms": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "description": "StoreID is 0 if there is no store with the address.",
+                    "type": "integer"
+                }
+            }
+        },
+        "replication.ReplicationLag": {
+            "type": "object",
+            "properties": {
+                "lagging_store_count": {
+                    "type": "integer"
+                },
+                "max_lag_bytes": {
+                    "type": "integer"
+                },
+                "max_lag_duration_ms": {
+                    "type": "integer"
+                },
+                "peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/replication.PeerLag"
+                    }
+                }
+            }
+        },
+        "schedule.CrossDCTraffic": {
+            "type": "object",
+            "properties": {
+                "cross_dc_bytes_rate": {
+                    "description": "CrossDCBytesRate and SameDCBytesRate are the bytes per second moved in\nthe last 5 minutes.",
+                    "type": "number"
+                },
+                "history": {
+                    "description": "History is the bytes moved in each minute of the last hour from the\noldest to the newest, the minutes without any move are omitted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schedule.CrossDCTrafficSample"
+                    }
+                },
+                "same_dc_bytes_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "schedule.CrossDCTrafficSample": {
+            "type": "object",
+            "properties": {
+                "cross_dc_bytes": {
+                    "type": "integer"
+                },
+                "same_dc_bytes": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Timestamp is the unix timestamp in seconds at the start of the minute.",
+                    "type": "integer"
+                }
+            }
+        },
+        "schedule.OperatorAuditEntry": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "string"
+                },
+                "finish_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the sequence number of the operator in the audit log.",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schedule.OperatorAuditStep"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "schedule.OperatorAuditStep": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "description": "EndTime is nil if the step has not finished.",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "StartTime is nil if the step has not started.",
+                    "type": "string"
+                },
+                "step": {
+                    "type": "string"
+                }
+            }
+        },
+        "schedule.OperatorResultStats": {
+            "type": "object",
+            "properties": {
+                "operators": {
+                    "description": "Operators is keyed by the operator description, then the result.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "steps": {
+                    "description": "Steps is keyed by the step type, then the result. The steps after the\none where an operator ends are not counted since they never start.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "schedule.OperatorWithStatus": {
+            "type": "object",
+            "properties": {
+                "additionalInfos": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "approximateSize": {
+                    "type": "integer"
+                },
+                "counters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "finishTime": {
+                    "type": "string"
+                },
+                "finishedCounters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prometheus.Counter"
+                    }
+                },
+                "rollbackSteps": {
+                    "description": "RollbackSteps are the steps compensating for the steps, the i-th one is\nfor the i-th step, nil means nothing needs to be compensated.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/operator.OpStep"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "schedule.TimeWindow": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "Location is the time zone of the window, nil means the local time zone.",
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "semver.Version": {
+            "type": "object",
+            "properties": {
+                "major": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "minor": {
+                    "type": "integer"
+                },
+                "patch": {
+                    "type": "integer"
+                },
+                "preRelease": {
+                    "type": "string"
+                }
+            }
+        },
+        "statistics.AmplificationSample": {
+            "type": "object",
+            "properties": {
+                "read_amplification": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "description": "Timestamp is the unix timestamp in seconds at the end of the report interval.",
+                    "type": "integer"
+                },
+                "write_amplification": {
+                    "type": "number"
+                }
+            }
+        },
+        "statistics.HotPeerStatShow": {
+            "type": "object",
+            "properties": {
+                "anti_count": {
+                    "type": "integer"
+                },
+                "flow_bytes": {
+                    "type": "number"
+                },
+                "flow_keys": {
+                    "type": "number"
+                },
+                "flow_query": {
+                    "type": "number"
+                },
+                "hot_degree": {
+                    "type": "integer"
+                },
+                "last_update_time": {
+                    "type": "string"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "statistics.HotPeersStat": {
+            "type": "object",
+            "properties": {
+                "regions_count": {
+                    "type": "integer"
+                },
+                "statistics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/statistics.HotPeerStatShow"
+                    }
+                },
+                "store_bytes": {
+                    "type": "number"
+                },
+                "store_keys": {
+                    "type": "number"
+                },
+                "store_query": {
+                    "type": "number"
+                },
+                "total_flow_bytes": {
+                    "type": "number"
+                },
+                "total_flow_keys": {
+                    "type": "number"
+                },
+                "total_flow_query": {
+                    "type": "number"
+                }
+            }
+        },
+        "statistics.RegionStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "empty_count": {
+                    "type": "integer"
+                },
+                "storage_keys": {
+                    "type": "integer"
+                },
+                "storage_size": {
+                    "type": "integer"
+                },
+                "store_leader_count": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "store_leader_keys": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "store_leader_size": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "store_peer_count": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "store_peer_keys": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "store_peer_size": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "statistics.StoreAmplificationInfo": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "object",
+                    "$ref": "#/definitions/statistics.AmplificationSample"
+                },
+                "history": {
+                    "description": "History is from the oldest to the newest, including the current one.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/statistics.AmplificationSample"
+                    }
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "statistics.StoreHealthScore": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "overall_score": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "statistics.StoreHotPeersInfos": {
+            "type": "object",
+            "properties": {
+                "as_leader": {
+                    "type": "object",
+                    "$ref": "#/definitions/statistics.StoreHotPeersStat"
+                },
+                "as_peer": {
+                    "type": "object",
+                    "$ref": "#/definitions/statistics.StoreHotPeersStat"
+                }
+            }
+        },
+        "statistics.StoreHotPeersStat": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/statistics.HotPeersStat"
+            }
+        },
+        "statistics.StoreIO": {
+            "type": "object",
+            "properties": {
+                "disk_read_rate": {
+                    "description": "DiskReadRate and DiskWriteRate are the IO rates of the disk.",
+                    "type": "number"
+                },
+                "disk_write_rate": {
+                    "type": "number"
+                },
+                "read_bytes_rate": {
+                    "description": "ReadBytesRate and WriteBytesRate are the rates of the bytes read and\nwritten by the clients.",
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "write_bytes_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "statistics.TrafficSample": {
+            "type": "object",
+            "properties": {
+                "read_bytes": {
+                    "type": "integer"
+                },
+                "read_keys": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Timestamp is the unix timestamp in seconds at the end of the report interval.",
+                    "type": "integer"
+                },
+                "write_bytes": {
+                    "type": "integer"
+                },
+                "write_keys": {
+                    "type": "integer"
+                }
+            }
+        },
+        "storage.HistoryHotRegion": {
+            "type": "object",
+            "properties": {
+                "encryption_meta": {
+                    "description": "Encryption metadata for start_key and end_key. encryption_meta.iv is IV for start_key.\nIV for end_key is calculated from (encryption_meta.iv + len(start_key)).\nThe field is only used by PD and should be ignored otherwise.\nIf encryption_meta is empty (i.e. nil), it means start_key and end_key are unencrypted.",
+                    "type": "object",
+                    "$ref": "#/definitions/encryptionpb.EncryptionMeta"
+                },
+                "end_key": {
+                    "type": "string"
+                },
+                "flow_bytes": {
+                    "type": "number"
+                },
+                "hot_degree": {
+                    "type": "integer"
+                },
+                "hot_region_type": {
+                    "type": "string"
+                },
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "is_learner": {
+                    "type": "boolean"
+                },
+                "key_rate": {
+                    "type": "number"
+                },
+                "peer_id": {
+                    "type": "integer"
+                },
+                "query_rate": {
+                    "type": "number"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "start_key": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "update_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "storage.HistoryHotRegions": {
+            "type": "object",
+            "properties": {
+                "history_hot_region": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.HistoryHotRegion"
+                    }
+                }
+            }
+        },
+        "storelimit.Scene": {
+            "type": "object",
+            "properties": {
+                "high": {
+                    "type": "integer"
+                },
+                "idle": {
+                    "type": "integer"
+                },
+                "low": {
+                    "type": "integer"
+                },
+                "normal": {
+                    "type": "integer"
+                }
+            }
+        },
+        "typeutil.Duration": {
+            "type": "object"
+        },
+        "typeutil.StringSlice": {
+            "type": "array",
+            "items": {
+                "type": "string"
+            }
+        },
+        "zap.AtomicLevel": {
+            "type": "object"
+        },
+        "zap.Logger": {
+            "type": "object"
+        },
+        "zap.SamplingConfig": {
+            "type": "object",
+            "properties": {
+                "initial": {
+                    "type": "integer"
+                },
+                "thereafter": {
+                    "type": "integer"
+                }
+            }
+        },
+        "zapcore.Clock": {
+            "type": "object"
+        },
+        "zapcore.Core": {
+            "type": "object"
+        },
+        "zapcore.LevelEnabler": {
+            "type": "object"
+        },
+        "zapcore.WriteSyncer": {
+            "type": "object"
+        }
+    }
+}`
+
+type swaggerInfo struct {
+	Version     string
+	Host        string
+	BasePath    string
+	Schemes     []string
+	Title       string
+	Description string
+}
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = swaggerInfo{
+	Version:     "1.0",
+	Host:        "",
+	BasePath:    "/pd/api/v1",
+	Schemes:     []string{},
+	Title:       "Placement Driver Core API",
+	Description: "This is placement driver.",
+}
+
+type s struct{}
+
+func (s *s) ReadDoc() string {
+	sInfo := SwaggerInfo
+	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
+
+	t, err := template.New("swagger_info").Funcs(template.FuncMap{
+		"marshal": func(v interface{}) string {
+			a, _ := json.Marshal(v)
+			return string(a)
+		},
+	}).Parse(doc)
+	if err != nil {
+		return doc
+	}
+
+	var tpl bytes.Buffer
+	if err := t.Execute(&tpl, sInfo); err != nil {
+		return doc
+	}
+
+	return tpl.String()
+}
+
+func init() {
+	swag.Register(swag.Name, &s{})
+}
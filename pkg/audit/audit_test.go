@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -110,6 +111,56 @@ func (s *testAuditSuite) TestLocalLogBackendUsingFile(c *C) {
 		time.Unix(info.StartTimeStamp, 0).String()))
 }
 
+func (s *testAuditSuite) TestLogger(c *C) {
+	dir := c.MkDir()
+	fname, headPath := filepath.Join(dir, "audit.log"), filepath.Join(dir, "audit.head")
+	key := []byte("key")
+
+	w, err := NewFileWriter(fname)
+	c.Assert(err, IsNil)
+	logger, err := NewLogger(w, key, headPath)
+	c.Assert(err, IsNil)
+	for i := 0; i < 3; i++ {
+		c.Assert(logger.Log(&Entry{Method: "POST", URL: fmt.Sprintf("/pd/api/v1/config/%d", i), StatusCode: http.StatusOK}), IsNil)
+	}
+	c.Assert(logger.Close(), IsNil)
+	b, err := os.ReadFile(fname)
+	c.Assert(err, IsNil)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	c.Assert(lines, HasLen, 3)
+	head, err := ReadHead(headPath)
+	c.Assert(err, IsNil)
+	c.Assert(Verify(strings.NewReader(string(b)), key, head), IsNil)
+	// The chain can not be verified without the key.
+	c.Assert(Verify(strings.NewReader(string(b)), nil, head), NotNil)
+
+	// The entries written after restarting continue the chain.
+	w, err = NewFileWriter(fname)
+	c.Assert(err, IsNil)
+	logger, err = NewLogger(w, key, headPath)
+	c.Assert(err, IsNil)
+	c.Assert(logger.Log(&Entry{Method: "DELETE", URL: "/pd/api/v1/store/1", StatusCode: http.StatusOK}), IsNil)
+	c.Assert(logger.Close(), IsNil)
+	b, err = os.ReadFile(fname)
+	c.Assert(err, IsNil)
+	head, err = ReadHead(headPath)
+	c.Assert(err, IsNil)
+	c.Assert(Verify(strings.NewReader(string(b)), key, head), IsNil)
+
+	// Modifying an entry breaks the chain.
+	tampered := strings.Replace(string(b), "/pd/api/v1/config/1", "/pd/api/v1/config/9", 1)
+	c.Assert(Verify(strings.NewReader(tampered), key, head), NotNil)
+	// Removing an entry breaks the chain.
+	lines = strings.Split(string(b), "\n")
+	removed := strings.Join(append(lines[:1:1], lines[2:]...), "\n")
+	c.Assert(Verify(strings.NewReader(removed), key, head), NotNil)
+	// Removing the first entry breaks the chain.
+	c.Assert(Verify(strings.NewReader(strings.Join(lines[1:], "\n")), key, head), NotNil)
+	// Removing the last entry does not match the head.
+	c.Assert(Verify(strings.NewReader(strings.Join(lines[:3], "\n")), key, head), NotNil)
+	c.Assert(Verify(strings.NewReader(strings.Join(lines[:3], "\n")), key, ""), IsNil)
+}
+
 func BenchmarkLocalLogAuditUsingTerminal(b *testing.B) {
 	b.StopTimer()
 	backend := NewLocalLogBackend(true)
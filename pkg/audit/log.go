@@ -0,0 +1,170 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// Entry is the audit record of a mutating HTTP API call.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// IP is the IP of the client, which may be from the forwarding headers
+	// provided by the client.
+	IP string `json:"ip"`
+	// RemoteAddr is the address of the connection.
+	RemoteAddr string `json:"remote_addr"`
+	// User is the CN of the client certificate, or the component name if the
+	// client does not use a certificate.
+	User       string `json:"user"`
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	BodyHash   string `json:"body_hash"`
+	StatusCode int    `json:"status_code"`
+	// PrevHash is the hash of the previous entry, which chains the entries up
+	// to make the audit log tamper-evident. It is empty for the first entry.
+	PrevHash string `json:"prev_hash"`
+}
+
+// Writer writes the encoded audit entries, each call of Write writes exactly
+// one entry. It can be a file, the syslog or a message queue like Kafka.
+type Writer interface {
+	io.WriteCloser
+}
+
+// Logger writes the audit entries to a Writer, it is separated from the
+// operational log.
+type Logger struct {
+	mu  sync.Mutex
+	w   Writer
+	key []byte
+	// headPath is the file to persist the hash of the last entry, so the
+	// chain is continued after PD restarts.
+	headPath string
+	lastHash string
+}
+
+// NewLogger creates a Logger writing to w. The entries are chained by the
+// HMAC with the key, or by the SHA-256 if the key is empty, and the hash of
+// the last entry is persisted to headPath.
+func NewLogger(w Writer, key []byte, headPath string) (*Logger, error) {
+	head, err := ReadHead(headPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{w: w, key: key, headPath: headPath, lastHash: head}, nil
+}
+
+// ReadHead reads the hash of the last entry persisted by the Logger, it is
+// empty if nothing is written.
+func ReadHead(headPath string) (string, error) {
+	head, err := ioutil.ReadFile(headPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(bytes.TrimSpace(head)), nil
+}
+
+// Log writes the entry, it sets the PrevHash of the entry.
+func (l *Logger) Log(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.PrevHash = l.lastHash
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		return errors.WithStack(err)
+	}
+	l.lastHash = hashOf(l.key, data)
+	return errors.WithStack(writeHead(l.headPath, l.lastHash))
+}
+
+// writeHead writes the head by renaming, so it is never partially written.
+func writeHead(headPath, head string) error {
+	tmp := headPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(head), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, headPath)
+}
+
+// Close closes the Writer.
+func (l *Logger) Close() error {
+	return l.w.Close()
+}
+
+// Verify checks whether the audit log, which is written by Logger line by
+// line with the key, has been tampered with. The hash of the last entry is
+// compared with head, which is read by ReadHead, to detect the removed
+// entries at the end, it is skipped if head is empty.
+func Verify(r io.Reader, key []byte, head string) error {
+	scanner := bufio.NewScanner(r)
+	lastHash := ""
+	for line := 1; scanner.Scan(); line++ {
+		e := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return errors.Annotatef(err, "line %d", line)
+		}
+		if e.PrevHash != lastHash {
+			return errors.Errorf("line %d does not follow the previous line", line)
+		}
+		lastHash = hashOf(key, scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	if head != "" && head != lastHash {
+		return errors.New("the last line does not match the head")
+	}
+	return nil
+}
+
+func hashOf(key, data []byte) string {
+	var h hash.Hash
+	if len(key) == 0 {
+		h = sha256.New()
+	} else {
+		h = hmac.New(sha256.New, key)
+	}
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewFileWriter creates a Writer appending to the file.
+func NewFileWriter(filename string) (Writer, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return f, nil
+}
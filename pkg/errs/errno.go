@@ -39,6 +39,7 @@ var (
 	ErrGenerateTimestamp  = errors.Normalize("generate timestamp failed, %s", errors.RFCCodeText("PD:tso:ErrGenerateTimestamp"))
 	ErrLogicOverflow      = errors.Normalize("logic part overflow", errors.RFCCodeText("PD:tso:ErrLogicOverflow"))
 	ErrProxyTSOTimeout    = errors.Normalize("proxy tso timeout", errors.RFCCodeText("PD:tso:ErrProxyTSOTimeout"))
+	ErrClockDrift         = errors.Normalize("clock drift %v exceeds the fatal threshold %v", errors.RFCCodeText("PD:tso:ErrClockDrift"))
 )
 
 // member errors
@@ -73,6 +74,10 @@ var (
 	ErrUnknownOperatorStep      = errors.Normalize("unknown operator step found", errors.RFCCodeText("PD:schedule:ErrUnknownOperatorStep"))
 	ErrMergeOperator            = errors.Normalize("merge operator error, %s", errors.RFCCodeText("PD:schedule:ErrMergeOperator"))
 	ErrCreateOperator           = errors.Normalize("unable to create operator, %s", errors.RFCCodeText("PD:schedule:ErrCreateOperator"))
+	ErrRollbackOperator         = errors.Normalize("unable to roll back operator, %s", errors.RFCCodeText("PD:schedule:ErrRollbackOperator"))
+	ErrStoreBusy                = errors.Normalize("store %d is busy, %d operators are moving peers or leaders into it", errors.RFCCodeText("PD:schedule:ErrStoreBusy"))
+	ErrStoreSnapshotBusy        = errors.Normalize("store %d is busy, %d snapshots are %s by it which exceed its bandwidth", errors.RFCCodeText("PD:schedule:ErrStoreSnapshotBusy"))
+	ErrSnapshotBytesExceeded    = errors.Normalize("%d bytes of snapshots are in flight, adding %d bytes exceeds the limit %d bytes", errors.RFCCodeText("PD:schedule:ErrSnapshotBytesExceeded"))
 )
 
 // scheduler errors
@@ -100,12 +105,31 @@ var (
 	ErrBuildRuleList = errors.Normalize("build rule list failed, %s", errors.RFCCodeText("PD:placement:ErrBuildRuleList"))
 )
 
+// keyspace errors
+var (
+	ErrKeyspaceNotFound = errors.Normalize("keyspace %d not found", errors.RFCCodeText("PD:keyspace:ErrKeyspaceNotFound"))
+	ErrKeyspaceExists   = errors.Normalize("keyspace %s already exists", errors.RFCCodeText("PD:keyspace:ErrKeyspaceExists"))
+	ErrKeyspaceContent  = errors.Normalize("invalid keyspace content, %s", errors.RFCCodeText("PD:keyspace:ErrKeyspaceContent"))
+)
+
+// replication mode errors
+var (
+	ErrDRAutoSyncNotEnabled = errors.Normalize("replication mode is %s, not dr-auto-sync", errors.RFCCodeText("PD:replication:ErrDRAutoSyncNotEnabled"))
+	ErrDRAutoSyncState      = errors.Normalize("invalid dr-auto-sync state, %s", errors.RFCCodeText("PD:replication:ErrDRAutoSyncState"))
+)
+
 // region label errors
 var (
 	ErrRegionRuleContent  = errors.Normalize("invalid region rule content, %s", errors.RFCCodeText("PD:region:ErrRegionRuleContent"))
 	ErrRegionRuleNotFound = errors.Normalize("region label rule not found for id %s", errors.RFCCodeText("PD:region:ErrRegionRuleNotFound"))
 )
 
+// region quota errors
+var (
+	ErrRegionQuotaContent  = errors.Normalize("invalid region quota content, %s", errors.RFCCodeText("PD:quota:ErrRegionQuotaContent"))
+	ErrRegionQuotaNotFound = errors.Normalize("region quota not found for id %s", errors.RFCCodeText("PD:quota:ErrRegionQuotaNotFound"))
+)
+
 // cluster errors
 var (
 	ErrNotBootstrapped = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
@@ -146,6 +170,14 @@ var (
 	ErrCancelStartEtcd       = errors.Normalize("etcd start canceled", errors.RFCCodeText("PD:server:ErrCancelStartEtcd"))
 	ErrConfigItem            = errors.Normalize("cannot set invalid configuration", errors.RFCCodeText("PD:server:ErrConfiguration"))
 	ErrServerNotStarted      = errors.Normalize("server not started", errors.RFCCodeText("PD:server:ErrServerNotStarted"))
+	ErrStartGRPCWeb          = errors.Normalize("start gRPC-Web server failed", errors.RFCCodeText("PD:server:ErrStartGRPCWeb"))
+)
+
+// store config errors
+var (
+	ErrStoreUnreachable  = errors.Normalize("store %s is unreachable, %v", errors.RFCCodeText("PD:config:ErrStoreUnreachable"))
+	ErrCircuitOpen       = errors.Normalize("circuit breaker is open, skip loading the config of store %s", errors.RFCCodeText("PD:config:ErrCircuitOpen"))
+	ErrStoreConfigStatus = errors.Normalize("unexpected status code %d from %s, body: %s", errors.RFCCodeText("PD:config:ErrStoreConfigStatus"))
 )
 
 // logutil errors
@@ -0,0 +1,110 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipfilter
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/pingcap/errors"
+)
+
+// Filter decides whether a remote address is allowed to access the APIs by
+// the CIDR lists. The denied networks take precedence over the allowed ones,
+// and all addresses are allowed if the allowed list is empty. A nil Filter
+// allows all addresses.
+type Filter struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// New parses the CIDR lists, it returns nil if both lists are empty.
+func New(allowCIDRs, denyCIDRs []string) (*Filter, error) {
+	if len(allowCIDRs) == 0 && len(denyCIDRs) == 0 {
+		return nil, nil
+	}
+	allow, err := parseCIDRs(allowCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parseCIDRs(denyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{allow: allow, deny: deny}, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Errorf("invalid CIDR %s", cidr)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// Allows returns whether the IP is allowed.
+func (f *Filter) Allows(ip net.IP) bool {
+	if f == nil {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	if contains(f.deny, ip) {
+		return false
+	}
+	return len(f.allow) == 0 || contains(f.allow, ip)
+}
+
+// AllowsAddr returns whether the remote address is allowed, the address is
+// either an IP or a host:port pair.
+func (f *Filter) AllowsAddr(addr string) bool {
+	if f == nil {
+		return true
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return f.Allows(net.ParseIP(addr))
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler rejects the HTTP requests from the addresses which are not allowed.
+// It checks the address of the connection rather than the forwarding headers,
+// which can be forged by the clients.
+func (f *Filter) Handler(h http.Handler) http.Handler {
+	if f == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.AllowsAddr(r.RemoteAddr) {
+			http.Error(w, "access denied", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
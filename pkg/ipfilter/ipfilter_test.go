@@ -0,0 +1,95 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipfilter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestIPFilter(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testIPFilterSuite{})
+
+type testIPFilterSuite struct{}
+
+func (s *testIPFilterSuite) TestNew(c *C) {
+	f, err := New(nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(f, IsNil)
+	c.Assert(f.AllowsAddr("10.0.0.1:2379"), IsTrue)
+
+	_, err = New([]string{"10.0.0.0/8", "10.0.0.1"}, nil)
+	c.Assert(err, NotNil)
+	_, err = New(nil, []string{"fd00::/129"})
+	c.Assert(err, NotNil)
+}
+
+func (s *testIPFilterSuite) TestAllows(c *C) {
+	f, err := New([]string{"10.0.0.0/8", "fd00::/8", "127.0.0.1/32"}, []string{"10.1.0.0/16", "fd00:1::/32"})
+	c.Assert(err, IsNil)
+	testCases := []struct {
+		addr    string
+		allowed bool
+	}{
+		// IPv4
+		{"10.0.0.1:2379", true},
+		{"10.0.0.1", true},
+		{"192.168.0.1:2379", false},
+		// IPv6
+		{"[fd00::1]:2379", true},
+		{"fd00::1", true},
+		{"[fe80::1]:2379", false},
+		// loopback
+		{"127.0.0.1:2379", true},
+		{"127.0.0.2:2379", false},
+		{"[::1]:2379", false},
+		// the denied networks take precedence over the allowed ones
+		{"10.1.0.1:2379", false},
+		{"[fd00:1::1]:2379", false},
+		// invalid address
+		{"unknown", false},
+	}
+	for _, t := range testCases {
+		c.Assert(f.AllowsAddr(t.addr), Equals, t.allowed, Commentf("%s", t.addr))
+	}
+
+	// All the addresses except the denied ones are allowed if the allowed
+	// list is empty.
+	f, err = New(nil, []string{"::1/128"})
+	c.Assert(err, IsNil)
+	c.Assert(f.AllowsAddr("[::1]:2379"), IsFalse)
+	c.Assert(f.AllowsAddr("127.0.0.1:2379"), IsTrue)
+}
+
+func (s *testIPFilterSuite) TestHandler(c *C) {
+	f, err := New([]string{"127.0.0.1/32"}, nil)
+	c.Assert(err, IsNil)
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for addr, code := range map[string]int{"127.0.0.1:1234": http.StatusOK, "10.0.0.1:1234": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, "/pd/api/v1/config", nil)
+		req.RemoteAddr = addr
+		// The forwarding headers are ignored.
+		req.Header.Set("X-Forwarded-For", "127.0.0.1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		c.Assert(w.Code, Equals, code, Commentf("%s", addr))
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pingcap/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const keySize = 32
+
+// Claims are the claims of the tokens issued by PD.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.StandardClaims
+}
+
+// Signer signs and verifies the tokens by a HMAC key. The key only lives in
+// the memory of the PD leader, so the tokens issued by the previous leaders
+// are rejected once the key is rotated.
+type Signer struct {
+	mu    sync.RWMutex
+	key   []byte
+	keyID string
+}
+
+// NewSigner returns a signer with a random key.
+func NewSigner() (*Signer, error) {
+	s := &Signer{}
+	if err := s.Rotate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Rotate replaces the key by a new random one, the tokens signed by the old
+// key can not be verified anymore.
+func (s *Signer) Rotate() error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return errors.WithStack(err)
+	}
+	sum := sha256.Sum256(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = key
+	s.keyID = hex.EncodeToString(sum[:8])
+	return nil
+}
+
+// Sign issues a token of the user which expires after the given duration.
+func (s *Signer) Sign(username, role string, expiry time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(expiry)
+	claims := &Claims{
+		Role: role,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   username,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, errors.WithStack(err)
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify checks the signature and the expiry of the token, and returns its
+// claims.
+func (s *Signer) Verify(token string) (*Claims, error) {
+	s.mu.RLock()
+	key, keyID := s.key, s.keyID
+	s.mu.RUnlock()
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		if kid, _ := t.Header["kid"].(string); kid != keyID {
+			return nil, errors.New("the token is signed by an unknown key")
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return claims, nil
+}
+
+// HashPassword hashes the password to be stored.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword checks whether the password matches the stored hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtauth
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+func TestJWTAuth(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testSignerSuite{})
+
+type testSignerSuite struct{}
+
+func (s *testSignerSuite) TestSign(c *C) {
+	signer, err := NewSigner()
+	c.Assert(err, IsNil)
+	token, expiresAt, err := signer.Sign("alice", "operator", time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(expiresAt.After(time.Now()), IsTrue)
+	claims, err := signer.Verify(token)
+	c.Assert(err, IsNil)
+	c.Assert(claims.Subject, Equals, "alice")
+	c.Assert(claims.Role, Equals, "operator")
+
+	_, err = signer.Verify(token + "x")
+	c.Assert(err, NotNil)
+	_, err = signer.Verify("not-a-token")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSignerSuite) TestExpiry(c *C) {
+	signer, err := NewSigner()
+	c.Assert(err, IsNil)
+	token, _, err := signer.Sign("alice", "viewer", -time.Minute)
+	c.Assert(err, IsNil)
+	_, err = signer.Verify(token)
+	c.Assert(err, NotNil)
+}
+
+func (s *testSignerSuite) TestRotate(c *C) {
+	signer, err := NewSigner()
+	c.Assert(err, IsNil)
+	old, _, err := signer.Sign("alice", "admin", time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(signer.Rotate(), IsNil)
+	_, err = signer.Verify(old)
+	c.Assert(err, NotNil)
+	token, _, err := signer.Sign("alice", "admin", time.Hour)
+	c.Assert(err, IsNil)
+	_, err = signer.Verify(token)
+	c.Assert(err, IsNil)
+
+	// The token signed by another signer is rejected.
+	other, err := NewSigner()
+	c.Assert(err, IsNil)
+	_, err = other.Verify(token)
+	c.Assert(err, NotNil)
+}
+
+func (s *testSignerSuite) TestPassword(c *C) {
+	hash, err := HashPassword("secret")
+	c.Assert(err, IsNil)
+	c.Assert(hash, Not(Equals), "secret")
+	c.Assert(CheckPassword(hash, "secret"), IsTrue)
+	c.Assert(CheckPassword(hash, "wrong"), IsFalse)
+}
@@ -36,6 +36,44 @@ func (mc *Cluster) SetSplitMergeInterval(v time.Duration) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.SplitMergeInterval = typeutil.NewDuration(v) })
 }
 
+// SetMaxOperatorsPerStore updates the MaxOperatorsPerStore configuration.
+func (mc *Cluster) SetMaxOperatorsPerStore(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxOperatorsPerStore = uint64(v) })
+}
+
+// SetMaxSnapshotBytesInFlight updates the MaxSnapshotBytesInFlight configuration.
+func (mc *Cluster) SetMaxSnapshotBytesInFlight(v uint64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxSnapshotBytesInFlight = typeutil.ByteSize(v) })
+}
+
+// SetOperatorAuditLogCapacity updates the OperatorAuditLogCapacity configuration.
+func (mc *Cluster) SetOperatorAuditLogCapacity(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.OperatorAuditLogCapacity = uint64(v) })
+}
+
+// SetSplitQPSThreshold updates the SplitQPSThreshold configuration.
+func (mc *Cluster) SetSplitQPSThreshold(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.SplitQPSThreshold = v })
+}
+
+// SetSplitWriteQPSThreshold updates the SplitWriteQPSThreshold configuration.
+func (mc *Cluster) SetSplitWriteQPSThreshold(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.SplitWriteQPSThreshold = v })
+}
+
+// SetSplitWriteBytesThreshold updates the SplitWriteBytesThreshold configuration.
+func (mc *Cluster) SetSplitWriteBytesThreshold(v uint64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.SplitWriteBytesThreshold = v })
+}
+
+// SetPinReadHotRegions updates the PinReadHotRegions and ReadHotPinThreshold configurations.
+func (mc *Cluster) SetPinReadHotRegions(enable bool, threshold float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) {
+		s.PinReadHotRegions = enable
+		s.ReadHotPinThreshold = threshold
+	})
+}
+
 // SetEnableOneWayMerge updates the EnableOneWayMerge configuration.
 func (mc *Cluster) SetEnableOneWayMerge(v bool) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableOneWayMerge = v })
@@ -66,6 +104,16 @@ func (mc *Cluster) SetEnableRemoveDownReplica(v bool) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableRemoveDownReplica = v })
 }
 
+// SetEnableStoreFlowConflict updates the EnableStoreFlowConflict configuration.
+func (mc *Cluster) SetEnableStoreFlowConflict(v bool) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableStoreFlowConflict = v })
+}
+
+// SetEnableRemoveOrphanLearner updates the EnableRemoveOrphanLearner configuration.
+func (mc *Cluster) SetEnableRemoveOrphanLearner(v bool) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableRemoveOrphanLearner = v })
+}
+
 // SetEnableReplaceOfflineReplica updates the EnableReplaceOfflineReplica configuration.
 func (mc *Cluster) SetEnableReplaceOfflineReplica(v bool) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableReplaceOfflineReplica = v })
@@ -76,11 +124,21 @@ func (mc *Cluster) SetLeaderSchedulePolicy(v string) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.LeaderSchedulePolicy = v })
 }
 
+// SetRegionIOImbalanceThreshold updates the RegionIOImbalanceThreshold configuration.
+func (mc *Cluster) SetRegionIOImbalanceThreshold(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.RegionIOImbalanceThreshold = v })
+}
+
 // SetTolerantSizeRatio updates the TolerantSizeRatio configuration.
 func (mc *Cluster) SetTolerantSizeRatio(v float64) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.TolerantSizeRatio = v })
 }
 
+// SetCompactionReservedSpace updates the CompactionReservedSpace configuration.
+func (mc *Cluster) SetCompactionReservedSpace(v uint64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.CompactionReservedSpace = typeutil.ByteSize(v) })
+}
+
 // SetRegionScoreFormulaVersion updates the RegionScoreFormulaVersion configuration.
 func (mc *Cluster) SetRegionScoreFormulaVersion(v string) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.RegionScoreFormulaVersion = v })
@@ -134,6 +192,16 @@ func (mc *Cluster) SetIsolationLevel(v string) {
 	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.IsolationLevel = v })
 }
 
+// SetRackLabelKey updates the RackLabelKey configuration.
+func (mc *Cluster) SetRackLabelKey(v string) {
+	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.RackLabelKey = v })
+}
+
+// SetEnableRackIsolation updates the EnableRackIsolation configuration.
+func (mc *Cluster) SetEnableRackIsolation(v bool) {
+	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.EnableRackIsolation = v })
+}
+
 func (mc *Cluster) updateScheduleConfig(f func(*config.ScheduleConfig)) {
 	s := mc.GetScheduleConfig().Clone()
 	f(s)
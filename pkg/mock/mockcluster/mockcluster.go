@@ -30,6 +30,7 @@ import (
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
 	"github.com/tikv/pd/server/id"
+	"github.com/tikv/pd/server/keyspace"
 	"github.com/tikv/pd/server/schedule/labeler"
 	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/tikv/pd/server/statistics"
@@ -54,6 +55,7 @@ type Cluster struct {
 	ID             uint64
 	suspectRegions map[uint64]struct{}
 	*config.StoreConfigManager
+	keyspaceAffinity *keyspace.AffinityManager
 }
 
 // NewCluster creates a new Cluster
@@ -72,6 +74,7 @@ func NewCluster(ctx context.Context, opts *config.PersistOptions) *Cluster {
 	// It should be updated to the latest feature version.
 	clus.PersistOptions.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.HotScheduleWithQuery))
 	clus.RegionLabeler, _ = labeler.NewRegionLabeler(storage.NewStorageWithMemoryBackend())
+	clus.keyspaceAffinity, _ = keyspace.NewAffinityManager(storage.NewStorageWithMemoryBackend())
 	return clus
 }
 
@@ -123,16 +126,24 @@ func (mc *Cluster) IsRegionHot(region *core.RegionInfo) bool {
 	return mc.HotCache.IsRegionHot(region, mc.GetHotRegionCacheHitsThreshold())
 }
 
+func (mc *Cluster) updateHotCacheConfig() {
+	mc.HotCache.SetExpiry(mc.GetHotCacheEntryTTL(), mc.GetHotCacheDecayRate())
+	short, medium, long := mc.GetHotWindows()
+	mc.HotCache.SetWindows(short, medium, long, mc.GetHotWindowWeights())
+}
+
 // RegionReadStats returns hot region's read stats.
 // The result only includes peers that are hot enough.
 func (mc *Cluster) RegionReadStats() map[uint64][]*statistics.HotPeerStat {
 	// We directly use threshold for read stats for mockCluster
+	mc.updateHotCacheConfig()
 	return mc.HotCache.RegionStats(statistics.Read, mc.GetHotRegionCacheHitsThreshold())
 }
 
 // RegionWriteStats returns hot region's write stats.
 // The result only includes peers that are hot enough.
 func (mc *Cluster) RegionWriteStats() map[uint64][]*statistics.HotPeerStat {
+	mc.updateHotCacheConfig()
 	return mc.HotCache.RegionStats(statistics.Write, mc.GetHotRegionCacheHitsThreshold())
 }
 
@@ -188,6 +199,11 @@ func (mc *Cluster) GetRegionLabeler() *labeler.RegionLabeler {
 	return mc.RegionLabeler
 }
 
+// GetKeyspaceAffinity returns the keyspace affinity manager of the cluster.
+func (mc *Cluster) GetKeyspaceAffinity() *keyspace.AffinityManager {
+	return mc.keyspaceAffinity
+}
+
 // SetStoreUp sets store state to be up.
 func (mc *Cluster) SetStoreUp(storeID uint64) {
 	store := mc.GetStore(storeID)
@@ -477,6 +493,13 @@ func (mc *Cluster) UpdateStoreRegionWeight(storeID uint64, weight float64) {
 	mc.PutStore(newStore)
 }
 
+// SetStoreWarmUp sets the warm-up of the store.
+func (mc *Cluster) SetStoreWarmUp(storeID uint64, startTime time.Time, duration time.Duration) {
+	store := mc.GetStore(storeID)
+	newStore := store.Clone(core.SetStoreWarmUp(startTime, duration))
+	mc.PutStore(newStore)
+}
+
 // UpdateStoreLeaderSize updates store leader size.
 func (mc *Cluster) UpdateStoreLeaderSize(storeID uint64, size int64) {
 	store := mc.GetStore(storeID)
@@ -560,6 +583,14 @@ func (mc *Cluster) UpdateStorageReadStats(storeID, bytesRead, keysRead uint64) {
 	})
 }
 
+// UpdateStorageIORates updates the disk read and write rates of the store.
+func (mc *Cluster) UpdateStorageIORates(storeID uint64, readRate, writeRate uint64) {
+	mc.updateStorageStatistics(storeID, func(newStats *pdpb.StoreStats) {
+		newStats.ReadIoRates = []*pdpb.RecordPair{{Key: "disk", Value: readRate}}
+		newStats.WriteIoRates = []*pdpb.RecordPair{{Key: "disk", Value: writeRate}}
+	})
+}
+
 // UpdateStorageWrittenBytes updates store written bytes.
 func (mc *Cluster) UpdateStorageWrittenBytes(storeID uint64, bytesWritten uint64) {
 	mc.updateStorageStatistics(storeID, func(newStats *pdpb.StoreStats) {
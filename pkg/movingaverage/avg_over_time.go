@@ -59,6 +59,15 @@ func (aot *AvgOverTime) Get() float64 {
 	return (aot.deltaSum - marginDelta) / aot.avgInterval.Seconds()
 }
 
+// GetPartial returns change rate in the last interval like Get, but regards
+// the time not covered by the changes yet as no change instead of returning 0.
+func (aot *AvgOverTime) GetPartial() float64 {
+	if aot.intervalSum < aot.avgInterval {
+		return aot.deltaSum / aot.avgInterval.Seconds()
+	}
+	return aot.Get()
+}
+
 // Clear clears the AvgOverTime.
 func (aot *AvgOverTime) Clear() {
 	aot.que.Init()
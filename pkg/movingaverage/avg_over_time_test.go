@@ -90,6 +90,19 @@ func (t *testAvgOverTimeSuite) TestMinFilled(c *C) {
 	}
 }
 
+func (t *testAvgOverTimeSuite) TestPartial(c *C) {
+	aot := NewAvgOverTime(10 * time.Second)
+	for i := 0; i < 5; i++ {
+		aot.Add(1000, time.Second)
+	}
+	c.Assert(aot.Get(), Equals, 0.)
+	c.Assert(aot.GetPartial(), Equals, 500.)
+	for i := 0; i < 10; i++ {
+		aot.Add(1000, time.Second)
+	}
+	c.Assert(aot.GetPartial(), Equals, aot.Get())
+}
+
 func (t *testAvgOverTimeSuite) TestUnstableInterval(c *C) {
 	aot := NewAvgOverTime(5 * time.Second)
 	c.Assert(aot.Get(), Equals, 0.)
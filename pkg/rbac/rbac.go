@@ -0,0 +1,64 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"github.com/pingcap/errors"
+)
+
+// Role is the role of a client of the HTTP API. A role is allowed to do
+// everything the lower roles are allowed to do.
+type Role int
+
+// The roles from the lowest to the highest.
+const (
+	// Viewer can only read the cluster, e.g. the dashboard.
+	Viewer Role = iota + 1
+	// Operator can also tune the scheduling, e.g. changing the configs and
+	// adding the schedulers or operators.
+	Operator
+	// Admin can also do the destructive operations, e.g. deleting the stores
+	// and the members.
+	Admin
+)
+
+var roleNames = map[Role]string{
+	Viewer:   "viewer",
+	Operator: "operator",
+	Admin:    "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseRole parses the role from its name.
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, nil
+		}
+	}
+	return 0, errors.Errorf("unknown role %s", name)
+}
+
+// Allows returns whether the role is allowed to do what requires the role
+// required.
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestRBAC(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testRoleSuite{})
+
+type testRoleSuite struct{}
+
+func (s *testRoleSuite) TestParseRole(c *C) {
+	for _, role := range []Role{Viewer, Operator, Admin} {
+		parsed, err := ParseRole(role.String())
+		c.Assert(err, IsNil)
+		c.Assert(parsed, Equals, role)
+	}
+	_, err := ParseRole("root")
+	c.Assert(err, NotNil)
+	c.Assert(Role(0).String(), Equals, "unknown")
+}
+
+func (s *testRoleSuite) TestAllows(c *C) {
+	c.Assert(Admin.Allows(Viewer), IsTrue)
+	c.Assert(Admin.Allows(Admin), IsTrue)
+	c.Assert(Operator.Allows(Viewer), IsTrue)
+	c.Assert(Operator.Allows(Admin), IsFalse)
+	c.Assert(Viewer.Allows(Operator), IsFalse)
+}
@@ -37,4 +37,6 @@ var LeakOptions = []goleak.Option{
 	goleak.IgnoreTopFunction("runtime.goparkunlock"),
 	// natefinch/lumberjack#56, It's a goroutine leak bug. Another ignore option PR https://github.com/pingcap/tidb/pull/27405/
 	goleak.IgnoreTopFunction("gopkg.in/natefinch/lumberjack%2ev2.(*Logger).millRun"),
+	// The global timer used by grpc-web is started in init.
+	goleak.IgnoreTopFunction("github.com/desertbit/timer.timerRoutine"),
 }
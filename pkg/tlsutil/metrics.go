@@ -0,0 +1,40 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	certReloadCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "tls",
+			Name:      "cert_reload_total",
+			Help:      "Counter of the reloads of the TLS certificate.",
+		}, []string{"result"})
+
+	certExpiryGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "tls",
+			Name:      "cert_expiry_seconds",
+			Help:      "The seconds until the TLS certificate in use expires.",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(certReloadCounter)
+	prometheus.MustRegister(certExpiryGauge)
+}
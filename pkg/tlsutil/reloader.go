@@ -0,0 +1,202 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"go.uber.org/zap"
+)
+
+const expiryUpdateInterval = time.Minute
+
+// CertReloader keeps the certificate loaded from the files, and reloads it
+// once the files are changed. The certificate is swapped atomically, so the
+// new connections use the new certificate while the existing ones are not
+// affected.
+type CertReloader struct {
+	certPath string
+	keyPath  string
+	// cert is a *tls.Certificate.
+	cert atomic.Value
+	// digest is the []byte digest of the files the certificate is loaded
+	// from, which is used to check if the files are changed.
+	digest atomic.Value
+}
+
+// NewCertReloader loads the certificate from the given files and returns
+// a reloader of it.
+func NewCertReloader(certPath, keyPath string) (*CertReloader, error) {
+	r := &CertReloader{
+		certPath: filepath.Clean(certPath),
+		keyPath:  filepath.Clean(keyPath),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate from the files. The old certificate is kept
+// if it fails.
+func (r *CertReloader) Reload() error {
+	certPEM, keyPEM, err := r.readFiles()
+	if err != nil {
+		certReloadCounter.WithLabelValues("fail").Inc()
+		return errs.ErrCryptoX509KeyPair.Wrap(err).GenWithStackByCause()
+	}
+	return r.load(certPEM, keyPEM)
+}
+
+func (r *CertReloader) readFiles() (certPEM, keyPEM []byte, err error) {
+	if certPEM, err = ioutil.ReadFile(r.certPath); err != nil {
+		return nil, nil, err
+	}
+	if keyPEM, err = ioutil.ReadFile(r.keyPath); err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+func digestFiles(certPEM, keyPEM []byte) []byte {
+	h := sha256.New()
+	h.Write(certPEM)
+	h.Write(keyPEM)
+	return h.Sum(nil)
+}
+
+func (r *CertReloader) load(certPEM, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		certReloadCounter.WithLabelValues("fail").Inc()
+		return errs.ErrCryptoX509KeyPair.Wrap(err).GenWithStackByCause()
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			certReloadCounter.WithLabelValues("fail").Inc()
+			return errs.ErrCryptoX509KeyPair.Wrap(err).GenWithStackByCause()
+		}
+	}
+	r.cert.Store(&cert)
+	r.digest.Store(digestFiles(certPEM, keyPEM))
+	certReloadCounter.WithLabelValues("success").Inc()
+	r.updateExpiry()
+	return nil
+}
+
+// Certificate returns the certificate in use.
+func (r *CertReloader) Certificate() *tls.Certificate {
+	return r.cert.Load().(*tls.Certificate)
+}
+
+// GetCertificate is used as tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate is used as tls.Config.GetClientCertificate.
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// Apply makes the config use the certificate of the reloader for both the
+// server and the client sides.
+func (r *CertReloader) Apply(cfg *tls.Config) {
+	cfg.Certificates = nil
+	cfg.GetCertificate = r.GetCertificate
+	cfg.GetClientCertificate = r.GetClientCertificate
+}
+
+// reloadIfChanged reloads the certificate if the content of the files is
+// changed. It returns whether the certificate is reloaded.
+func (r *CertReloader) reloadIfChanged() (bool, error) {
+	certPEM, keyPEM, err := r.readFiles()
+	if err != nil {
+		certReloadCounter.WithLabelValues("fail").Inc()
+		return false, errs.ErrCryptoX509KeyPair.Wrap(err).GenWithStackByCause()
+	}
+	if bytes.Equal(digestFiles(certPEM, keyPEM), r.digest.Load().([]byte)) {
+		return false, nil
+	}
+	return true, r.load(certPEM, keyPEM)
+}
+
+// Run watches the files and reloads the certificate once they are changed
+// until the context is done. The directories are watched rather than the
+// files, and the content of the files is compared on any event in them, since
+// the files are usually replaced by renaming, or by swapping the symbolic link
+// of the parent directory as Kubernetes does for the mounted secrets. The
+// files are also compared periodically in case an event is missed.
+func (r *CertReloader) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errs.ErrSecurityConfig.Wrap(err).GenWithStackByCause()
+	}
+	defer watcher.Close()
+	dirs := map[string]struct{}{
+		filepath.Dir(r.certPath): {},
+		filepath.Dir(r.keyPath):  {},
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return errs.ErrSecurityConfig.Wrap(err).GenWithStackByCause()
+		}
+	}
+
+	ticker := time.NewTicker(expiryUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-watcher.Events:
+			r.checkFiles()
+		case err := <-watcher.Errors:
+			log.Warn("failed to watch the certificate", zap.String("cert-path", r.certPath), errs.ZapError(err))
+		case <-ticker.C:
+			r.checkFiles()
+			r.updateExpiry()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (r *CertReloader) checkFiles() {
+	// The certificate and the key may be written one by one, the mismatched
+	// pair is rejected and reloaded again by the next event.
+	reloaded, err := r.reloadIfChanged()
+	if err != nil {
+		log.Warn("failed to reload the certificate", zap.String("cert-path", r.certPath), errs.ZapError(err))
+		return
+	}
+	if reloaded {
+		log.Info("the certificate is reloaded", zap.String("cert-path", r.certPath),
+			zap.Time("not-after", r.Certificate().Leaf.NotAfter))
+	}
+}
+
+func (r *CertReloader) updateExpiry() {
+	certExpiryGauge.Set(time.Until(r.Certificate().Leaf.NotAfter).Seconds())
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/testutil"
+)
+
+func TestTLSUtil(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testCertReloaderSuite{})
+
+type testCertReloaderSuite struct{}
+
+func writeCert(c *C, certPath, keyPath string, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "pd"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	// Write to temporary files and rename them, as the cert managers do.
+	certTemp, keyTemp := certPath+".tmp", keyPath+".tmp"
+	c.Assert(ioutil.WriteFile(certTemp, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(keyTemp, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600), IsNil)
+	c.Assert(os.Rename(keyTemp, keyPath), IsNil)
+	c.Assert(os.Rename(certTemp, certPath), IsNil)
+}
+
+func servedSerial(c *C, addr string) int64 {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	c.Assert(conn.Handshake(), IsNil)
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func (s *testCertReloaderSuite) TestReload(c *C) {
+	dir := c.MkDir()
+	certPath, keyPath := filepath.Join(dir, "pd.pem"), filepath.Join(dir, "pd-key.pem")
+
+	_, err := NewCertReloader(certPath, keyPath)
+	c.Assert(err, NotNil)
+
+	writeCert(c, certPath, keyPath, 1)
+	r, err := NewCertReloader(certPath, keyPath)
+	c.Assert(err, IsNil)
+	c.Assert(r.Certificate().Leaf.SerialNumber.Int64(), Equals, int64(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	cfg := &tls.Config{}
+	r.Apply(cfg)
+	l, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	c.Assert(err, IsNil)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+	addr := l.Addr().String()
+	c.Assert(servedSerial(c, addr), Equals, int64(1))
+
+	// An existing connection keeps the old certificate.
+	old, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	c.Assert(err, IsNil)
+	defer old.Close()
+
+	writeCert(c, certPath, keyPath, 2)
+	testutil.WaitUntil(c, func() bool {
+		return servedSerial(c, addr) == 2
+	})
+	c.Assert(old.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), Equals, int64(1))
+
+	// A broken file does not replace the certificate in use.
+	c.Assert(ioutil.WriteFile(certPath, []byte("broken"), 0600), IsNil)
+	c.Assert(r.Reload(), NotNil)
+	c.Assert(servedSerial(c, addr), Equals, int64(2))
+}
+
+func (s *testCertReloaderSuite) TestReloadSymlinkSwap(c *C) {
+	// Kubernetes mounts the secrets as symbolic links to the files in the
+	// "..data" directory, which is itself a symbolic link swapped on update.
+	dir := c.MkDir()
+	writeVersion := func(version string, serial int64) {
+		versionDir := filepath.Join(dir, version)
+		c.Assert(os.Mkdir(versionDir, 0700), IsNil)
+		writeCert(c, filepath.Join(versionDir, "pd.pem"), filepath.Join(versionDir, "pd-key.pem"), serial)
+		c.Assert(os.Symlink(version, filepath.Join(dir, "..data_tmp")), IsNil)
+		c.Assert(os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")), IsNil)
+	}
+	writeVersion("..v1", 1)
+	certPath, keyPath := filepath.Join(dir, "pd.pem"), filepath.Join(dir, "pd-key.pem")
+	c.Assert(os.Symlink(filepath.Join("..data", "pd.pem"), certPath), IsNil)
+	c.Assert(os.Symlink(filepath.Join("..data", "pd-key.pem"), keyPath), IsNil)
+
+	r, err := NewCertReloader(certPath, keyPath)
+	c.Assert(err, IsNil)
+	c.Assert(r.Certificate().Leaf.SerialNumber.Int64(), Equals, int64(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	// Wait for the watcher to be added.
+	time.Sleep(100 * time.Millisecond)
+	writeVersion("..v2", 2)
+	testutil.WaitUntil(c, func() bool {
+		return r.Certificate().Leaf.SerialNumber.Int64() == 2
+	})
+}
@@ -23,6 +23,9 @@ import (
 const (
 	physicalShiftBits = 18
 	logicalBits       = (1 << physicalShiftBits) - 1
+
+	hlcPhysicalShiftBits = 16
+	hlcLogicalBits       = (1 << hlcPhysicalShiftBits) - 1
 )
 
 // ParseTS parses the ts to (physical,logical).
@@ -33,6 +36,14 @@ func ParseTS(ts uint64) (time.Time, uint64) {
 	return physicalTime, logical
 }
 
+// ParseHLC parses the ts in the HLC layout to (physical,logical).
+func ParseHLC(ts uint64) (time.Time, uint64) {
+	logical := ts & hlcLogicalBits
+	physical := ts >> hlcPhysicalShiftBits
+	physicalTime := time.Unix(int64(physical/1000), int64(physical)%1000*time.Millisecond.Nanoseconds())
+	return physicalTime, logical
+}
+
 // ParseTimestamp parses `pdpb.Timestamp` to `time.Time`
 func ParseTimestamp(ts pdpb.Timestamp) (time.Time, uint64) {
 	logical := uint64(ts.GetLogical())
@@ -50,6 +61,22 @@ func ComposeTS(physical, logical int64) uint64 {
 	return uint64(physical)<<18 | uint64(logical)&0x3FFFF
 }
 
+// ComposeHLC generate an `uint64` TS in the HLC layout by passing the physical and logical parts.
+func ComposeHLC(physical, logical int64) uint64 {
+	return uint64(physical)<<hlcPhysicalShiftBits | uint64(logical)&hlcLogicalBits
+}
+
+// EncodeHLCTimestamp encodes the `pdpb.Timestamp` in the HLC layout. The result is still split in the
+// standard layout, so the clients get the TS in the HLC layout by GenerateTS.
+func EncodeHLCTimestamp(ts *pdpb.Timestamp) *pdpb.Timestamp {
+	hlc := ComposeHLC(ts.GetPhysical(), ts.GetLogical())
+	return &pdpb.Timestamp{
+		Physical:   int64(hlc >> physicalShiftBits),
+		Logical:    int64(hlc & logicalBits),
+		SuffixBits: ts.GetSuffixBits(),
+	}
+}
+
 // GenerateTimestamp generate a `pdpb.Timestamp` by passing `time.Time` and `uint64`
 func GenerateTimestamp(physical time.Time, logical uint64) *pdpb.Timestamp {
 	return &pdpb.Timestamp{
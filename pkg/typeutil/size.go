@@ -34,6 +34,16 @@ func ParseMBFromText(text string, value uint64) uint64 {
 	return uint64(b / units.MiB)
 }
 
+// ParseBytesFromText parses bytes from text.
+func ParseBytesFromText(text string, value uint64) uint64 {
+	b := ByteSize(0)
+	err := b.UnmarshalText([]byte(text))
+	if err != nil {
+		return value
+	}
+	return uint64(b)
+}
+
 // MarshalJSON returns the size as a JSON string.
 func (b ByteSize) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + units.BytesSize(float64(b)) + `"`), nil
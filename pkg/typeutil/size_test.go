@@ -61,3 +61,28 @@ func (s *testSizeSuite) TestParseMbFromText(c *C) {
 		}
 	}
 }
+
+func (s *testSizeSuite) TestParseBytesFromText(c *C) {
+	testdata := []struct {
+		body []string
+		size uint64
+	}{{
+		body: []string{"10Mib", "10MiB", "10M", "10MB"},
+		size: uint64(10 * 1024 * 1024),
+	}, {
+		body: []string{"10KiB", "10K", "10KB"},
+		size: uint64(10 * 1024),
+	}, {
+		body: []string{"10240"},
+		size: uint64(10240),
+	}, {
+		body: []string{"10yiB", "10aib"},
+		size: uint64(1),
+	}}
+
+	for _, t := range testdata {
+		for _, b := range t.body {
+			c.Assert(ParseBytesFromText(b, 1), Equals, t.size)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pinregion is an example of the scheduler plugin. It pins the leaders
+// of some regions to the specified stores, which can be used to co-locate the
+// regions with the application tier they serve.
+//
+// To enable it, register the plugin in the init() func of a package linked
+// into the PD binary:
+//
+//	func init() {
+//		schedule.RegisterPlugin(pinregion.NewPlugin(map[uint64]uint64{regionID: storeID}))
+//	}
+package pinregion
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/operator"
+	"go.uber.org/zap"
+)
+
+// Name is the name of the pin region plugin.
+const Name = "pin-region-plugin"
+
+// Config is the config of the pin region plugin.
+type Config struct {
+	// Pins maps the region ID to the ID of the store which the region leader
+	// is pinned to.
+	Pins map[uint64]uint64 `json:"pins"`
+}
+
+// Plugin is a scheduler plugin which keeps the leaders of the pinned regions
+// on the specified stores. If the store has no peer of the region, a peer is
+// moved to it first.
+type Plugin struct {
+	mu   sync.RWMutex
+	pins map[uint64]uint64
+}
+
+// NewPlugin creates a pin region plugin with the region ID to store ID pins.
+func NewPlugin(pins map[uint64]uint64) *Plugin {
+	p := &Plugin{pins: make(map[uint64]uint64, len(pins))}
+	for regionID, storeID := range pins {
+		p.pins[regionID] = storeID
+	}
+	return p
+}
+
+// Pin pins the leader of the region to the store.
+func (p *Plugin) Pin(regionID, storeID uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pins[regionID] = storeID
+}
+
+// Unpin removes the pin of the region.
+func (p *Plugin) Unpin(regionID uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pins, regionID)
+}
+
+// Name implements schedule.SchedulerPlugin.
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// Config implements schedule.SchedulerPlugin.
+func (p *Plugin) Config() interface{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	conf := &Config{Pins: make(map[uint64]uint64, len(p.pins))}
+	for regionID, storeID := range p.pins {
+		conf.Pins[regionID] = storeID
+	}
+	return conf
+}
+
+// Schedule implements schedule.SchedulerPlugin.
+func (p *Plugin) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	pins := p.Config().(*Config).Pins
+	regionIDs := make([]uint64, 0, len(pins))
+	for regionID := range pins {
+		regionIDs = append(regionIDs, regionID)
+	}
+	sort.Slice(regionIDs, func(i, j int) bool { return regionIDs[i] < regionIDs[j] })
+
+	var ops []*operator.Operator
+	for _, regionID := range regionIDs {
+		if op := p.pinRegion(cluster, regionID, pins[regionID]); op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+func (p *Plugin) pinRegion(cluster schedule.Cluster, regionID, storeID uint64) *operator.Operator {
+	region := cluster.GetRegion(regionID)
+	if region == nil || region.GetLeader() == nil {
+		return nil
+	}
+	leaderStoreID := region.GetLeader().GetStoreId()
+	if leaderStoreID == storeID {
+		return nil
+	}
+	if store := cluster.GetStore(storeID); store == nil || store.IsRemoving() || store.IsRemoved() || store.IsDisconnected() {
+		log.Debug("the pinned store is not available", zap.String("plugin", Name), zap.Uint64("region-id", regionID), zap.Uint64("store-id", storeID))
+		return nil
+	}
+
+	var (
+		op  *operator.Operator
+		err error
+	)
+	if peer := region.GetStorePeer(storeID); peer != nil {
+		op, err = operator.CreateTransferLeaderOperator(Name, cluster, region, leaderStoreID, storeID, []uint64{}, operator.OpLeader)
+	} else {
+		// Move a follower to the pinned store, the leader will be transferred later.
+		followers := region.GetFollowers()
+		if len(followers) == 0 {
+			return nil
+		}
+		var oldStoreID uint64
+		for id := range followers {
+			if oldStoreID == 0 || id < oldStoreID {
+				oldStoreID = id
+			}
+		}
+		op, err = operator.CreateMovePeerOperator(Name, cluster, region, operator.OpRegion, oldStoreID, &metapb.Peer{StoreId: storeID})
+	}
+	if err != nil {
+		log.Debug("fail to create pin region operator", zap.Uint64("region-id", regionID), errs.ZapError(err))
+		return nil
+	}
+	return op
+}
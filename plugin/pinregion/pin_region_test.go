@@ -0,0 +1,76 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pinregion
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testPinRegionSuite{})
+
+type testPinRegionSuite struct{}
+
+func (s *testPinRegionSuite) TestPinRegion(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, config.NewTestOptions())
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderStore(i, 0)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 2, 1, 3)
+
+	p := NewPlugin(map[uint64]uint64{1: 1})
+	c.Assert(p.Name(), Equals, Name)
+	// The leader is on the pinned store already.
+	c.Assert(p.Schedule(tc), HasLen, 0)
+
+	// Transfer the leader to the pinned store.
+	p.Pin(2, 3)
+	ops := p.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 2, 3)
+
+	// Move a peer to the pinned store, then transfer the leader.
+	p.Pin(1, 4)
+	ops = p.Schedule(tc)
+	c.Assert(ops, HasLen, 2)
+	c.Assert(ops[0].Kind()&operator.OpRegion, Equals, operator.OpRegion)
+	c.Assert(ops[0].Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+	tc.AddLeaderRegion(1, 1, 3, 4)
+	ops = p.Schedule(tc)
+	c.Assert(ops, HasLen, 2)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 1, 4)
+
+	// The unavailable store is ignored.
+	tc.SetStoreOffline(4)
+	c.Assert(p.Schedule(tc), HasLen, 1)
+
+	p.Unpin(1)
+	p.Unpin(2)
+	c.Assert(p.Schedule(tc), HasLen, 0)
+	c.Assert(p.Config().(*Config).Pins, HasLen, 0)
+}
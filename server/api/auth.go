@@ -0,0 +1,154 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/jwtauth"
+	"github.com/tikv/pd/pkg/rbac"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"github.com/unrolled/render"
+)
+
+const errJWTAuthDisabled = "jwt auth is disabled"
+
+type authHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newAuthHandler(svr *server.Server, rd *render.Render) *authHandler {
+	return &authHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+type tokenRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type tokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires-at"`
+}
+
+// @Tags auth
+// @Summary Issue a token of the HTTP API by the username and the password.
+// @Accept json
+// @Param body body tokenRequest true "The credential of the user"
+// @Produce json
+// @Success 200 {object} tokenResponse
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 401 {string} string "The username or the password is wrong."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /auth/token [post]
+func (h *authHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	signer := h.svr.GetJWTSigner()
+	if signer == nil {
+		h.rd.JSON(w, http.StatusBadRequest, errJWTAuthDisabled)
+		return
+	}
+	var input tokenRequest
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	credential, err := h.svr.GetStorage().LoadAPICredential(input.Username)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if input.Username == "" || credential == nil || !jwtauth.CheckPassword(credential.PasswordHash, input.Password) {
+		h.rd.JSON(w, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+	token, expiresAt, err := signer.Sign(credential.Username, credential.Role, h.svr.GetJWTExpiry())
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, &tokenResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+type userRequest struct {
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// @Tags auth
+// @Summary Create or update a user of the HTTP API.
+// @Accept json
+// @Param name path string true "The name of the user"
+// @Param body body userRequest true "The password and the role of the user"
+// @Produce json
+// @Success 200 {string} string "The user is saved."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /auth/users/{name} [put]
+func (h *authHandler) SetUser(w http.ResponseWriter, r *http.Request) {
+	if h.svr.GetJWTSigner() == nil {
+		h.rd.JSON(w, http.StatusBadRequest, errJWTAuthDisabled)
+		return
+	}
+	var input userRequest
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.Password == "" {
+		h.rd.JSON(w, http.StatusBadRequest, "the password is required")
+		return
+	}
+	if _, err := rbac.ParseRole(input.Role); err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hash, err := jwtauth.HashPassword(input.Password)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	credential := &endpoint.APICredential{
+		Username:     mux.Vars(r)["name"],
+		Role:         input.Role,
+		PasswordHash: hash,
+	}
+	if err := h.svr.GetStorage().SaveAPICredential(credential); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The user is saved.")
+}
+
+// @Tags auth
+// @Summary Delete a user of the HTTP API, the issued tokens are valid until they expire.
+// @Param name path string true "The name of the user"
+// @Produce json
+// @Success 200 {string} string "The user is deleted."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /auth/users/{name} [delete]
+func (h *authHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.svr.GetStorage().RemoveAPICredential(mux.Vars(r)["name"]); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The user is deleted.")
+}
@@ -55,9 +55,12 @@ func (s *testCheckerSuite) TestAPI(c *C) {
 		name string
 	}{
 		{name: "learner"},
+		{name: "orphan-learner"},
+		{name: "keyspace-affinity"},
 		{name: "replica"},
 		{name: "rule"},
 		{name: "split"},
+		{name: "access-split"},
 		{name: "merge"},
 		{name: "joint-state"},
 	}
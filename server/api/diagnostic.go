@@ -0,0 +1,43 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+type diagnosticHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newDiagnosticHandler(svr *server.Server, rd *render.Render) *diagnosticHandler {
+	return &diagnosticHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags diagnostic
+// @Summary Get the states of the circuit breakers of loading the store config.
+// @Produce json
+// @Success 200 {object} map[string]string "The state of every store, the key is the status address."
+// @Router /diagnostic/store-config/breakers [get]
+func (h *diagnosticHandler) GetStoreConfigBreakers(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetStoreConfigManager().BreakerStates())
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+type eventsHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newEventsHandler(svr *server.Server, rd *render.Render) *eventsHandler {
+	return &eventsHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags event
+// @Summary Subscribe the cluster events, the events are pushed as server-sent events until the client disconnects.
+// @Produce text/event-stream
+// @Success 200 {object} events.ClusterEvent
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /events [get]
+func (h *eventsHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.rd.JSON(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	hub := h.svr.GetEventHub()
+	sub := hub.Subscribe(h.svr.GetPDServerConfig().EventBufferSize)
+	defer hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-sub.Events():
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
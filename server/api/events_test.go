@@ -0,0 +1,101 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/events"
+)
+
+var _ = Suite(&testEventsSuite{})
+
+type testEventsSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testEventsSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+}
+
+func (s *testEventsSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+// readEvent reads the next server-sent event from the stream.
+func readEvent(c *C, r *bufio.Reader) *events.ClusterEvent {
+	var typ, data string
+	for {
+		line, err := r.ReadString('\n')
+		c.Assert(err, IsNil)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			e := &events.ClusterEvent{}
+			c.Assert(json.Unmarshal([]byte(data), e), IsNil)
+			c.Assert(string(e.Type), Equals, typ)
+			return e
+		}
+	}
+}
+
+func (s *testEventsSuite) TestSubscribe(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.urlPrefix+"/events", nil)
+	c.Assert(err, IsNil)
+	resp, err := testDialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "text/event-stream")
+	r := bufio.NewReader(resp.Body)
+
+	data, err := json.Marshal(map[string]interface{}{"leader-schedule-limit": 8})
+	c.Assert(err, IsNil)
+	c.Assert(apiutil.PostJSON(testDialClient, s.urlPrefix+"/config", data), IsNil)
+	e := readEvent(c, r)
+	c.Assert(e.Type, Equals, events.ConfigChange)
+	c.Assert(e.Message, Equals, "schedule")
+
+	c.Assert(s.svr.GetRaftCluster().RemoveStore(2, false), IsNil)
+	e = readEvent(c, r)
+	c.Assert(e.Type, Equals, events.StoreStateChange)
+	c.Assert(e.StoreID, Equals, uint64(2))
+	c.Assert(e.Message, Equals, "Serving -> Removing")
+}
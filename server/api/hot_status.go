@@ -15,12 +15,17 @@
 package api
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/pingcap/errors"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/statistics"
@@ -65,13 +70,42 @@ func newHotStatusHandler(handler *server.Handler, rd *render.Render) *hotStatusH
 
 // @Tags hotspot
 // @Summary List the hot write regions.
+// @Param store-id query integer false "Only list the hot regions on the stores, store_id is also accepted"
+// @Param key-range query string false "Only list the hot regions overlapping with the key range in the format of hex(start)-hex(end), either key can be empty"
+// @Param sort-by query string false "Sort the hot regions by bytes, keys or qps"
+// @Param asc query boolean false "Sort in ascending order, default is descending"
+// @Param limit query integer false "Only list the top N hot regions across all the stores"
 // @Produce json
 // @Success 200 {object} statistics.StoreHotPeersInfos
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
 // @Router /hotspot/regions/write [get]
 func (h *hotStatusHandler) GetHotWriteRegions(w http.ResponseWriter, r *http.Request) {
-	storeIDs := r.URL.Query()["store_id"]
-	if len(storeIDs) < 1 {
-		h.rd.JSON(w, http.StatusOK, h.Handler.GetHotWriteRegions())
+	h.getHotRegions(w, r, statistics.Write)
+}
+
+// @Tags hotspot
+// @Summary List the hot read regions.
+// @Param store-id query integer false "Only list the hot regions on the stores, store_id is also accepted"
+// @Param key-range query string false "Only list the hot regions overlapping with the key range in the format of hex(start)-hex(end), either key can be empty"
+// @Param sort-by query string false "Sort the hot regions by bytes, keys or qps"
+// @Param asc query boolean false "Sort in ascending order, default is descending"
+// @Param limit query integer false "Only list the top N hot regions across all the stores"
+// @Produce json
+// @Success 200 {object} statistics.StoreHotPeersInfos
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /hotspot/regions/read [get]
+func (h *hotStatusHandler) GetHotReadRegions(w http.ResponseWriter, r *http.Request) {
+	h.getHotRegions(w, r, statistics.Read)
+}
+
+func (h *hotStatusHandler) getHotRegions(w http.ResponseWriter, r *http.Request, typ statistics.RWType) {
+	filter, err := parseHotRegionsFilter(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -82,7 +116,9 @@ func (h *hotStatusHandler) GetHotWriteRegions(w http.ResponseWriter, r *http.Req
 	}
 
 	var ids []uint64
-	for _, storeID := range storeIDs {
+	// store_id is kept for compatibility.
+	query := r.URL.Query()
+	for _, storeID := range append(query["store_id"], query["store-id"]...) {
 		id, err := strconv.ParseUint(storeID, 10, 64)
 		if err != nil {
 			h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid store id: %s", storeID))
@@ -96,43 +132,144 @@ func (h *hotStatusHandler) GetHotWriteRegions(w http.ResponseWriter, r *http.Req
 		ids = append(ids, id)
 	}
 
-	h.rd.JSON(w, http.StatusOK, rc.GetHotWriteRegions(ids...))
+	var infos *statistics.StoreHotPeersInfos
+	switch typ {
+	case statistics.Write:
+		infos = rc.GetHotWriteRegions(ids...)
+	case statistics.Read:
+		infos = rc.GetHotReadRegions(ids...)
+	}
+	if filter != nil {
+		filter.apply(infos, rc.GetRegion)
+	}
+	h.rd.JSON(w, http.StatusOK, infos)
 }
 
-// @Tags hotspot
-// @Summary List the hot read regions.
-// @Produce json
-// @Success 200 {object} statistics.StoreHotPeersInfos
-// @Router /hotspot/regions/read [get]
-func (h *hotStatusHandler) GetHotReadRegions(w http.ResponseWriter, r *http.Request) {
-	storeIDs := r.URL.Query()["store_id"]
-	if len(storeIDs) < 1 {
-		h.rd.JSON(w, http.StatusOK, h.Handler.GetHotReadRegions())
-		return
+// hotRegionsFilter filters and sorts the hot regions across all the stores.
+type hotRegionsFilter struct {
+	startKey, endKey []byte
+	sortBy           string
+	asc              bool
+	limit            int
+}
+
+// parseHotRegionsFilter parses the filter from the query. It returns nil if
+// the query has no filter. The keys of key-range are hex encoded, so the
+// separator "-" is not ambiguous.
+func parseHotRegionsFilter(r *http.Request) (*hotRegionsFilter, error) {
+	query := r.URL.Query()
+	f := &hotRegionsFilter{sortBy: query.Get("sort-by")}
+	if keyRange := query.Get("key-range"); keyRange != "" {
+		keys := strings.Split(keyRange, "-")
+		if len(keys) != 2 {
+			return nil, errors.Errorf("invalid key-range: %s", keyRange)
+		}
+		var err error
+		if f.startKey, err = hex.DecodeString(keys[0]); err != nil {
+			return nil, errors.Errorf("invalid key-range: %s", keyRange)
+		}
+		if f.endKey, err = hex.DecodeString(keys[1]); err != nil {
+			return nil, errors.Errorf("invalid key-range: %s", keyRange)
+		}
+	}
+	switch f.sortBy {
+	case "", "bytes", "keys", "qps":
+	default:
+		return nil, errors.Errorf("invalid sort-by: %s", f.sortBy)
+	}
+	if ascStr := query.Get("asc"); ascStr != "" {
+		asc, err := strconv.ParseBool(ascStr)
+		if err != nil {
+			return nil, errors.Errorf("invalid asc: %s", ascStr)
+		}
+		f.asc = asc
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return nil, errors.Errorf("invalid limit: %s", limitStr)
+		}
+		f.limit = limit
+	}
+	if len(f.startKey) == 0 && len(f.endKey) == 0 && f.sortBy == "" && f.limit == 0 {
+		return nil, nil
 	}
+	// The hottest regions are listed when limited.
+	if f.sortBy == "" && f.limit > 0 {
+		f.sortBy = "bytes"
+	}
+	return f, nil
+}
 
-	rc, err := h.GetRaftCluster()
-	if rc == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+// apply filters the hot regions in place. The regions are sorted and limited
+// across all the stores, and then grouped by the stores again in the sorted
+// order. The summaries of the stores are not affected.
+func (f *hotRegionsFilter) apply(infos *statistics.StoreHotPeersInfos, getRegion func(uint64) *core.RegionInfo) {
+	if infos == nil {
 		return
 	}
+	for _, stats := range []statistics.StoreHotPeersStat{infos.AsPeer, infos.AsLeader} {
+		f.applyStores(stats, getRegion)
+	}
+}
 
-	var ids []uint64
+type storeHotPeer struct {
+	storeID uint64
+	peer    statistics.HotPeerStatShow
+}
+
+func (f *hotRegionsFilter) applyStores(stats statistics.StoreHotPeersStat, getRegion func(uint64) *core.RegionInfo) {
+	storeIDs := make([]uint64, 0, len(stats))
+	for storeID := range stats {
+		storeIDs = append(storeIDs, storeID)
+	}
+	// Sort the stores to make the order of the regions with the same load stable.
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	var peers []storeHotPeer
 	for _, storeID := range storeIDs {
-		id, err := strconv.ParseUint(storeID, 10, 64)
-		if err != nil {
-			h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid store id: %s", storeID))
-			return
+		for _, peer := range stats[storeID].Stats {
+			if len(f.startKey) > 0 || len(f.endKey) > 0 {
+				region := getRegion(peer.RegionID)
+				if region == nil || !f.inRange(region) {
+					continue
+				}
+			}
+			peers = append(peers, storeHotPeer{storeID: storeID, peer: peer})
 		}
-		store := rc.GetStore(id)
-		if store == nil {
-			h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(id).Error())
-			return
+	}
+	if f.sortBy != "" {
+		load := func(peer *statistics.HotPeerStatShow) float64 {
+			switch f.sortBy {
+			case "keys":
+				return peer.KeyRate
+			case "qps":
+				return peer.QueryRate
+			default:
+				return peer.ByteRate
+			}
 		}
-		ids = append(ids, id)
+		sort.SliceStable(peers, func(i, j int) bool {
+			if f.asc {
+				return load(&peers[i].peer) < load(&peers[j].peer)
+			}
+			return load(&peers[i].peer) > load(&peers[j].peer)
+		})
 	}
+	if f.limit > 0 && len(peers) > f.limit {
+		peers = peers[:f.limit]
+	}
+	for _, stat := range stats {
+		stat.Stats = []statistics.HotPeerStatShow{}
+	}
+	for _, p := range peers {
+		stats[p.storeID].Stats = append(stats[p.storeID].Stats, p.peer)
+	}
+}
 
-	h.rd.JSON(w, http.StatusOK, rc.GetHotReadRegions(ids...))
+// inRange checks whether the region overlaps with [startKey, endKey).
+func (f *hotRegionsFilter) inRange(region *core.RegionInfo) bool {
+	return (len(region.GetEndKey()) == 0 || bytes.Compare(region.GetEndKey(), f.startKey) > 0) &&
+		(len(f.endKey) == 0 || bytes.Compare(region.GetStartKey(), f.endKey) < 0)
 }
 
 // @Tags hotspot
@@ -15,15 +15,20 @@
 package api
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/core"
 	_ "github.com/tikv/pd/server/schedulers"
+	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage"
 	"github.com/tikv/pd/server/storage/kv"
 )
@@ -56,6 +61,96 @@ func (s testHotStatusSuite) TestGetHotStore(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s testHotStatusSuite) TestGetHotRegionsWithFilter(c *C) {
+	for _, typ := range []string{"write", "read"} {
+		url := fmt.Sprintf("%s/regions/%s", s.urlPrefix, typ)
+		infos := &statistics.StoreHotPeersInfos{}
+		err := readJSON(testDialClient, url+"?limit=10&sort-by=keys&asc=true&key-range=61-62&store-id=1", infos)
+		c.Assert(err, IsNil)
+		for _, query := range []string{"limit=0", "limit=x", "sort-by=size", "asc=x", "store-id=x", "store_id=x", "key-range=61", "key-range=x-62"} {
+			err = getJSON(testDialClient, url+"?"+query, nil,
+				func(_ []byte, code int) { c.Assert(code, Equals, http.StatusBadRequest) })
+			c.Assert(err, NotNil)
+		}
+	}
+}
+
+func (s testHotStatusSuite) TestHotRegionsFilter(c *C) {
+	regions := map[uint64]*core.RegionInfo{}
+	for i, keys := range [][2]string{{"", "b"}, {"b", "d"}, {"d", "f"}, {"f", ""}} {
+		id := uint64(i + 1)
+		regions[id] = core.NewRegionInfo(&metapb.Region{Id: id, StartKey: []byte(keys[0]), EndKey: []byte(keys[1])}, nil)
+	}
+	getRegion := func(id uint64) *core.RegionInfo { return regions[id] }
+	// The regions 1 and 3 are on store 1, and the regions 2 and 4 are on store 2.
+	newInfos := func() *statistics.StoreHotPeersInfos {
+		store1 := []statistics.HotPeerStatShow{
+			{RegionID: 1, ByteRate: 100, KeyRate: 40, QueryRate: 1},
+			{RegionID: 3, ByteRate: 200, KeyRate: 30, QueryRate: 4},
+		}
+		store2 := []statistics.HotPeerStatShow{
+			{RegionID: 2, ByteRate: 300, KeyRate: 10, QueryRate: 3},
+			{RegionID: 4, ByteRate: 400, KeyRate: 20, QueryRate: 2},
+		}
+		return &statistics.StoreHotPeersInfos{
+			AsPeer: statistics.StoreHotPeersStat{
+				1: {Count: 2, Stats: store1},
+				2: {Count: 2, Stats: store2},
+			},
+			AsLeader: statistics.StoreHotPeersStat{
+				1: {Count: 2, Stats: append(store1[:0:0], store1...)},
+				2: {Count: 2, Stats: append(store2[:0:0], store2...)},
+			},
+		}
+	}
+	// regionIDs returns the regions of all the stores in the order of the
+	// filter, which is checked by the loads.
+	regionIDs := func(infos *statistics.StoreHotPeersInfos) [2][]uint64 {
+		var ids [2][]uint64
+		for i, storeID := range []uint64{1, 2} {
+			for _, peer := range infos.AsPeer[storeID].Stats {
+				ids[i] = append(ids[i], peer.RegionID)
+			}
+			c.Assert(infos.AsLeader[storeID].Stats, DeepEquals, infos.AsPeer[storeID].Stats)
+			c.Assert(infos.AsPeer[storeID].Count, Equals, 2)
+		}
+		return ids
+	}
+	hexKey := func(key string) string { return hex.EncodeToString([]byte(key)) }
+
+	testCases := []struct {
+		query    string
+		expected [2][]uint64
+	}{
+		{"", [2][]uint64{{1, 3}, {2, 4}}},
+		// The limit is applied across all the stores.
+		{"limit=2", [2][]uint64{nil, {4, 2}}},
+		{"limit=3", [2][]uint64{{3}, {4, 2}}},
+		{"sort-by=bytes", [2][]uint64{{3, 1}, {4, 2}}},
+		{"sort-by=keys&limit=2", [2][]uint64{{1, 3}, nil}},
+		{"sort-by=qps&asc=true&limit=2", [2][]uint64{{1}, {4}}},
+		{"sort-by=keys&limit=3&asc=true", [2][]uint64{{3}, {2, 4}}},
+		{"key-range=" + hexKey("c") + "-", [2][]uint64{{3}, {2, 4}}},
+		{"key-range=-" + hexKey("d"), [2][]uint64{{1}, {2}}},
+		{"key-range=" + hexKey("c") + "-" + hexKey("e"), [2][]uint64{{3}, {2}}},
+		{"key-range=" + hexKey("c") + "-" + hexKey("e") + "&sort-by=qps&limit=1", [2][]uint64{{3}, nil}},
+		{"key-range=" + hexKey("b") + "-&limit=1", [2][]uint64{nil, {4}}},
+	}
+	for _, t := range testCases {
+		req, err := http.NewRequest(http.MethodGet, "/hotspot/regions/write?"+t.query, nil)
+		c.Assert(err, IsNil)
+		filter, err := parseHotRegionsFilter(req)
+		c.Assert(err, IsNil)
+		infos := newInfos()
+		if filter != nil {
+			filter.apply(infos, getRegion)
+		} else {
+			c.Assert(t.query, Equals, "")
+		}
+		c.Assert(regionIDs(infos), DeepEquals, t.expected, Commentf("query: %s", t.query))
+	}
+}
+
 func (s testHotStatusSuite) TestGetHistoryHotRegionsBasic(c *C) {
 	request := HistoryHotRegionsRequest{
 		StartTime: 0,
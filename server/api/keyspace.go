@@ -0,0 +1,419 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"github.com/unrolled/render"
+)
+
+type keyspaceHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newKeyspaceHandler(svr *server.Server, rd *render.Render) *keyspaceHandler {
+	return &keyspaceHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *keyspaceHandler) getKeyspaceID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil || id > placement.MaxKeyspaceID {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid keyspace id")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+func (h *keyspaceHandler) respondKeyspaceError(w http.ResponseWriter, err error) {
+	switch {
+	case errs.ErrKeyspaceNotFound.Equal(err):
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+	case errs.ErrKeyspaceExists.Equal(err):
+		h.rd.JSON(w, http.StatusConflict, err.Error())
+	case errs.ErrKeyspaceContent.Equal(err), errs.ErrHexDecodingString.Equal(err):
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// @Tags keyspace
+// @Summary List all the keyspaces, including the ones marked for deletion.
+// @Produce json
+// @Success 200 {array} endpoint.KeyspaceMeta
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces [get]
+func (h *keyspaceHandler) GetKeyspaces(w http.ResponseWriter, r *http.Request) {
+	keyspaces, err := h.svr.GetKeyspaceManager().LoadAllKeyspaces()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, keyspaces)
+}
+
+// CreateKeyspaceParams is the input to create a keyspace.
+type CreateKeyspaceParams struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config"`
+}
+
+// @Tags keyspace
+// @Summary Create a keyspace with the next unused ID.
+// @Accept json
+// @Param body body CreateKeyspaceParams true "The name and the config of the keyspace"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 409 {string} string "The keyspace name is used."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces [post]
+func (h *keyspaceHandler) CreateKeyspace(w http.ResponseWriter, r *http.Request) {
+	var params CreateKeyspaceParams
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &params); err != nil {
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().CreateKeyspace(params.Name, params.Config)
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// @Tags keyspace
+// @Summary Get a keyspace by ID.
+// @Param id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id} [get]
+func (h *keyspaceHandler) GetKeyspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().LoadKeyspace(id)
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// @Tags keyspace
+// @Summary Update the config of a keyspace, an item set to null is removed.
+// @Accept json
+// @Param id path integer true "Keyspace Id"
+// @Param body body object true "The config items to update"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id} [patch]
+func (h *keyspaceHandler) UpdateKeyspaceConfig(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	var patch map[string]*string
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &patch); err != nil {
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().UpdateKeyspaceConfig(id, patch)
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// @Tags keyspace
+// @Summary Mark a keyspace for deletion.
+// @Param id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id} [delete]
+func (h *keyspaceHandler) DeleteKeyspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().DeleteKeyspace(id)
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// @Tags keyspace
+// @Summary Split the key range of a keyspace, the upper half is routed to a new keyspace. Splitting at the same key again returns the keyspace created before.
+// @Param id path integer true "Keyspace Id"
+// @Param split-key query string true "The hex-encoded split key"
+// @Param name query string false "The name of the new keyspace"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 409 {string} string "The keyspace name is used."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id}/split [post]
+func (h *keyspaceHandler) SplitKeyspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	splitKeyStr := r.URL.Query().Get("split-key")
+	splitKey, err := hex.DecodeString(splitKeyStr)
+	if err != nil || len(splitKey) == 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid split key")
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().SplitKeyspace(id, splitKey, r.URL.Query().Get("name"))
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// MergeKeyspacesParams is the input to merge two keyspaces.
+type MergeKeyspacesParams struct {
+	KeyspaceIDs []uint32 `json:"keyspace_ids"`
+}
+
+// @Tags keyspace
+// @Summary Merge two keyspaces with contiguous key ranges, the keyspace with the lower range takes the merged range and the other one is marked for deletion.
+// @Accept json
+// @Param body body MergeKeyspacesParams true "The IDs of the two keyspaces"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/merge [post]
+func (h *keyspaceHandler) MergeKeyspaces(w http.ResponseWriter, r *http.Request) {
+	var params MergeKeyspacesParams
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &params); err != nil {
+		return
+	}
+	if len(params.KeyspaceIDs) != 2 {
+		h.rd.JSON(w, http.StatusBadRequest, "two keyspace IDs are required")
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().MergeKeyspaces(params.KeyspaceIDs[0], params.KeyspaceIDs[1])
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// SetKeyspaceTTLParams is the input to set the TTL of a keyspace.
+type SetKeyspaceTTLParams struct {
+	TTLSeconds uint64 `json:"ttl_seconds"`
+}
+
+// @Tags keyspace
+// @Summary Set the time to live of the data in a keyspace. The GC safe point of the keyspace is published as min(GC safe point, now - TTL), and the regions not written since it are cleaned up, 0 disables the expiry.
+// @Param id path integer true "Keyspace Id"
+// @Accept json
+// @Param body body SetKeyspaceTTLParams true "The TTL in seconds"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceMeta
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id}/ttl [post]
+func (h *keyspaceHandler) SetKeyspaceTTL(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	var params SetKeyspaceTTLParams
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &params); err != nil {
+		return
+	}
+	meta, err := h.svr.GetKeyspaceManager().SetKeyspaceTTL(id, params.TTLSeconds)
+	if err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, meta)
+}
+
+// KeyspaceGCSafePoint is the GC safe point of a keyspace.
+type KeyspaceGCSafePoint struct {
+	SafePoint uint64 `json:"safe_point"`
+}
+
+// @Tags keyspace
+// @Summary Get the GC safe point of a keyspace with TTL, the data before it is expired. It is 0 if the keyspace has no TTL.
+// @Param id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {object} KeyspaceGCSafePoint
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id}/gc-safe-point [get]
+func (h *keyspaceHandler) GetKeyspaceGCSafePoint(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.svr.GetKeyspaceManager().LoadKeyspace(id); err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	safePoint, err := h.svr.GetStorage().LoadKeyspaceGCSafePoint(id)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, &KeyspaceGCSafePoint{SafePoint: safePoint})
+}
+
+// @Tags keyspace
+// @Summary Get the store affinity of a keyspace.
+// @Param id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceAffinity
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace or its affinity does not exist."
+// @Router /keyspaces/{id}/affinity [get]
+func (h *keyspaceHandler) GetKeyspaceAffinity(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	affinity := rc.GetKeyspaceAffinity().GetAffinity(id)
+	if affinity == nil {
+		h.rd.JSON(w, http.StatusNotFound, "the keyspace affinity is not set")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, affinity)
+}
+
+// @Tags keyspace
+// @Summary Set the store affinity of a keyspace, the regions of the keyspace are only placed on the stores with all the required labels. An affinity without any required label removes the affinity.
+// @Accept json
+// @Param id path integer true "Keyspace Id"
+// @Param body body endpoint.KeyspaceAffinity true "The required store labels"
+// @Produce json
+// @Success 200 {object} endpoint.KeyspaceAffinity
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The keyspace does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id}/affinity [post]
+func (h *keyspaceHandler) SetKeyspaceAffinity(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	id, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	var affinity endpoint.KeyspaceAffinity
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &affinity); err != nil {
+		return
+	}
+	affinity.KeyspaceID = id
+	if _, err := h.svr.GetKeyspaceManager().LoadKeyspace(id); err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	if err := rc.GetKeyspaceAffinity().SetAffinity(&affinity); err != nil {
+		h.respondKeyspaceError(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, affinity)
+}
+
+// @Tags keyspace
+// @Summary Get the region count, size and store distribution of a keyspace. If the interval is set, the stats are pushed as server-sent events every interval until the client disconnects.
+// @Param id path integer true "Keyspace Id"
+// @Param interval query string false "The interval to push the stats, e.g. 10s"
+// @Produce json
+// @Produce text/event-stream
+// @Success 200 {object} cluster.KeyspaceStats
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /keyspaces/{id}/stats [get]
+func (h *keyspaceHandler) GetKeyspaceStats(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	keyspaceID, ok := h.getKeyspaceID(w, r)
+	if !ok {
+		return
+	}
+	intervalStr := r.URL.Query().Get("interval")
+	if len(intervalStr) == 0 {
+		h.rd.JSON(w, http.StatusOK, rc.GetKeyspaceStats(keyspaceID))
+		return
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid interval")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.rd.JSON(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, err := json.Marshal(rc.GetKeyspaceStats(keyspaceID))
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: stats\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/docker/go-units"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
+	"github.com/tikv/pd/server/keyspace"
+	"github.com/tikv/pd/server/storage/endpoint"
+)
+
+var _ = Suite(&testKeyspaceSuite{})
+
+type testKeyspaceSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testKeyspaceSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+}
+
+func (s *testKeyspaceSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func keyspaceKey(keyspaceID uint32, key string) []byte {
+	return codec.EncodeBytes(append([]byte{'x', byte(keyspaceID >> 16), byte(keyspaceID >> 8), byte(keyspaceID)}, key...))
+}
+
+func (s *testKeyspaceSuite) TestKeyspaceStats(c *C) {
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(10, 1, keyspaceKey(1, "a"), keyspaceKey(1, "b")))
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(11, 2, keyspaceKey(1, "b"), keyspaceKey(1, "c")))
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(12, 1, keyspaceKey(2, "a"), keyspaceKey(2, "b")))
+
+	var stats cluster.KeyspaceStats
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/1/stats", &stats), IsNil)
+	c.Assert(stats.RegionCount, Equals, 2)
+	c.Assert(stats.ApproximateSizeBytes, Equals, int64(20*units.MiB))
+	c.Assert(stats.ApproximateKeys, Equals, int64(20))
+	c.Assert(stats.StoreDistribution, DeepEquals, map[uint64]int{1: 1, 2: 1})
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/3/stats", &stats), IsNil)
+	c.Assert(stats.RegionCount, Equals, 0)
+
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/abc/stats", &stats), NotNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/1/stats?interval=abc", &stats), NotNil)
+}
+
+func (s *testKeyspaceSuite) TestKeyspaceStatsStream(c *C) {
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(20, 1, keyspaceKey(5, "a"), keyspaceKey(5, "b")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.urlPrefix+"/keyspaces/5/stats?interval=50ms", nil)
+	c.Assert(err, IsNil)
+	resp, err := testDialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "text/event-stream")
+	r := bufio.NewReader(resp.Body)
+	readStats := func() *cluster.KeyspaceStats {
+		for {
+			line, err := r.ReadString('\n')
+			c.Assert(err, IsNil)
+			if strings.HasPrefix(line, "data: ") {
+				stats := &cluster.KeyspaceStats{}
+				c.Assert(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), stats), IsNil)
+				return stats
+			}
+		}
+	}
+
+	c.Assert(readStats().RegionCount, Equals, 1)
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(21, 1, keyspaceKey(5, "b"), keyspaceKey(5, "c")))
+	// The stats are pushed again after the interval.
+	for i := 0; i < 10; i++ {
+		if readStats().RegionCount == 2 {
+			return
+		}
+	}
+	c.Fatal("the stats are not updated")
+}
+
+func (s *testKeyspaceSuite) TestKeyspaceLifecycle(c *C) {
+	var meta endpoint.KeyspaceMeta
+	data := []byte(`{"name":"lifecycle","config":{"a":"1","b":"2"}}`)
+	err := postJSON(testDialClient, s.urlPrefix+"/keyspaces", data, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &meta), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(meta.Name, Equals, "lifecycle")
+	c.Assert(meta.State, Equals, keyspace.StateEnabled)
+	c.Assert(meta.Config, DeepEquals, map[string]string{"a": "1", "b": "2"})
+	c.Assert(meta.CreatedAt, Greater, int64(0))
+	id := meta.ID
+	url := fmt.Sprintf("%s/keyspaces/%d", s.urlPrefix, id)
+
+	// The name is in use.
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces", data), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces", []byte(`{"name":""}`)), NotNil)
+
+	var got endpoint.KeyspaceMeta
+	c.Assert(readJSON(testDialClient, url, &got), IsNil)
+	c.Assert(got, DeepEquals, meta)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/100000", &got), NotNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/abc", &got), NotNil)
+
+	c.Assert(patchJSON(testDialClient, url, []byte(`{"a":null,"b":"3","c":"4"}`)), IsNil)
+	got = endpoint.KeyspaceMeta{}
+	c.Assert(readJSON(testDialClient, url, &got), IsNil)
+	c.Assert(got.Config, DeepEquals, map[string]string{"b": "3", "c": "4"})
+
+	var keyspaces []*endpoint.KeyspaceMeta
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces", &keyspaces), IsNil)
+	found := false
+	for _, k := range keyspaces {
+		found = found || k.ID == id
+	}
+	c.Assert(found, IsTrue)
+
+	code, err := doDelete(testDialClient, url)
+	c.Assert(err, IsNil)
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, url, &got), IsNil)
+	c.Assert(got.State, Equals, keyspace.StateTombstone)
+	// A keyspace marked for deletion can not be updated.
+	c.Assert(patchJSON(testDialClient, url, []byte(`{"a":"1"}`)), NotNil)
+	code, err = doDelete(testDialClient, s.urlPrefix+"/keyspaces/100000")
+	c.Assert(err, IsNil)
+	c.Assert(code, Equals, http.StatusNotFound)
+}
+
+func (s *testKeyspaceSuite) TestConcurrentCreateKeyspace(c *C) {
+	const n = 10
+	var wg sync.WaitGroup
+	metas := make([]*endpoint.KeyspaceMeta, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := []byte(`{"name":"race"}`)
+			postJSON(testDialClient, s.urlPrefix+"/keyspaces", data, func(res []byte, code int) {
+				metas[i] = &endpoint.KeyspaceMeta{}
+				json.Unmarshal(res, metas[i])
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, meta := range metas {
+		if meta != nil {
+			created++
+			c.Assert(meta.Name, Equals, "race")
+		}
+	}
+	c.Assert(created, Equals, 1)
+}
+
+func (s *testKeyspaceSuite) TestKeyspaceAffinity(c *C) {
+	var meta endpoint.KeyspaceMeta
+	err := postJSON(testDialClient, s.urlPrefix+"/keyspaces", []byte(`{"name":"affinity"}`), func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &meta), IsNil)
+	})
+	c.Assert(err, IsNil)
+	url := fmt.Sprintf("%s/keyspaces/%d/affinity", s.urlPrefix, meta.ID)
+
+	var affinity endpoint.KeyspaceAffinity
+	c.Assert(readJSON(testDialClient, url, &affinity), NotNil)
+	c.Assert(postJSON(testDialClient, url, []byte(`{"required_store_labels":{"disk":"nvme"}}`)), IsNil)
+	c.Assert(readJSON(testDialClient, url, &affinity), IsNil)
+	c.Assert(affinity.KeyspaceID, Equals, meta.ID)
+	c.Assert(affinity.RequiredStoreLabels, DeepEquals, map[string]string{"disk": "nvme"})
+	c.Assert(postJSON(testDialClient, url, []byte(`{"required_store_labels":{"disk":""}}`)), NotNil)
+	// The affinity is removed without any required label.
+	c.Assert(postJSON(testDialClient, url, []byte(`{}`)), IsNil)
+	c.Assert(readJSON(testDialClient, url, &affinity), NotNil)
+
+	err = postJSON(testDialClient, s.urlPrefix+"/keyspaces/100000/affinity", []byte(`{"required_store_labels":{"disk":"nvme"}}`))
+	c.Assert(err, NotNil)
+}
+
+func (s *testKeyspaceSuite) TestSplitMergeKeyspace(c *C) {
+	var parent, child, merged endpoint.KeyspaceMeta
+	err := postJSON(testDialClient, s.urlPrefix+"/keyspaces", []byte(`{"name":"reshard"}`), func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &parent), IsNil)
+	})
+	c.Assert(err, IsNil)
+	splitKey := parent.StartKey + hex.EncodeToString([]byte("m"))
+	splitURL := fmt.Sprintf("%s/keyspaces/%d/split?split-key=%s&name=reshard-upper", s.urlPrefix, parent.ID, splitKey)
+	err = postJSON(testDialClient, splitURL, nil, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &child), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(child.Name, Equals, "reshard-upper")
+	c.Assert(child.StartKey, Equals, splitKey)
+	c.Assert(child.EndKey, Equals, parent.EndKey)
+	// Splitting again is idempotent.
+	var again endpoint.KeyspaceMeta
+	err = postJSON(testDialClient, splitURL, nil, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &again), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(again.ID, Equals, child.ID)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/keyspaces/%d/split?split-key=zz", s.urlPrefix, parent.ID), nil), NotNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/keyspaces/%d/split?split-key=%s", s.urlPrefix, parent.ID, parent.EndKey), nil), NotNil)
+
+	mergeData := []byte(fmt.Sprintf(`{"keyspace_ids":[%d,%d]}`, child.ID, parent.ID))
+	err = postJSON(testDialClient, s.urlPrefix+"/keyspaces/merge", mergeData, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &merged), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(merged.ID, Equals, parent.ID)
+	c.Assert(merged.EndKey, Equals, parent.EndKey)
+	// Merging again is idempotent.
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces/merge", mergeData), IsNil)
+	var got endpoint.KeyspaceMeta
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/keyspaces/%d", s.urlPrefix, child.ID), &got), IsNil)
+	c.Assert(got.State, Equals, keyspace.StateTombstone)
+
+	// The key ranges of the keyspaces created one by one are not contiguous.
+	var other endpoint.KeyspaceMeta
+	err = postJSON(testDialClient, s.urlPrefix+"/keyspaces", []byte(`{"name":"reshard-other"}`), func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &other), IsNil)
+	})
+	c.Assert(err, IsNil)
+	mergeData = []byte(fmt.Sprintf(`{"keyspace_ids":[%d,%d]}`, parent.ID, other.ID))
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces/merge", mergeData), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces/merge", []byte(`{"keyspace_ids":[1]}`)), NotNil)
+}
+
+func (s *testKeyspaceSuite) TestKeyspaceTTL(c *C) {
+	var meta endpoint.KeyspaceMeta
+	err := postJSON(testDialClient, s.urlPrefix+"/keyspaces", []byte(`{"name":"ttl"}`), func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &meta), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(meta.TTLSeconds, Equals, uint64(0))
+	ttlURL := fmt.Sprintf("%s/keyspaces/%d/ttl", s.urlPrefix, meta.ID)
+	c.Assert(postJSON(testDialClient, ttlURL, []byte(`{"ttl_seconds":3600}`)), IsNil)
+	var got endpoint.KeyspaceMeta
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/keyspaces/%d", s.urlPrefix, meta.ID), &got), IsNil)
+	c.Assert(got.TTLSeconds, Equals, uint64(3600))
+	c.Assert(postJSON(testDialClient, ttlURL, []byte(`{"ttl_seconds":-1}`)), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/keyspaces/10000/ttl", []byte(`{"ttl_seconds":60}`)), NotNil)
+
+	// The safe point is published by the background job.
+	c.Assert(s.svr.GetStorage().SaveKeyspaceGCSafePoint(meta.ID, 100), IsNil)
+	safePoint := &KeyspaceGCSafePoint{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/keyspaces/%d/gc-safe-point", s.urlPrefix, meta.ID), safePoint), IsNil)
+	c.Assert(safePoint.SafePoint, Equals, uint64(100))
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/keyspaces/10000/gc-safe-point", safePoint), NotNil)
+}
@@ -15,20 +15,29 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/audit"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/rbac"
 	"github.com/tikv/pd/pkg/requestutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/unrolled/render"
 	"github.com/urfave/negroni"
+	"go.uber.org/zap"
 )
 
 // requestInfoMiddleware is used to gather info from requsetInfo
@@ -91,6 +100,85 @@ func getCluster(r *http.Request) *cluster.RaftCluster {
 	return r.Context().Value(clusterCtxKey{}).(*cluster.RaftCluster)
 }
 
+// rbacMiddleware checks whether the client is allowed to access the route by
+// the role of its token, if the API tokens or JWT auth are configured.
+type rbacMiddleware struct {
+	s  *server.Server
+	rd *render.Render
+	// roles are the roles required by the routes. The routes without a role
+	// require the viewer role to read and the operator role to write.
+	roles map[*mux.Route]rbac.Role
+	// public are the routes accessible without a token.
+	public map[*mux.Route]struct{}
+}
+
+func newRBACMiddleware(s *server.Server, roles map[*mux.Route]rbac.Role, public map[*mux.Route]struct{}) rbacMiddleware {
+	return rbacMiddleware{
+		s:      s,
+		rd:     render.New(render.Options{IndentJSON: true}),
+		roles:  roles,
+		public: public,
+	}
+}
+
+func (m rbacMiddleware) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens := m.s.GetAPITokens()
+		signer := m.s.GetJWTSigner()
+		if len(tokens) == 0 && signer == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if _, ok := m.public[mux.CurrentRoute(r)]; ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		name, ok := lookupToken(tokens, token)
+		if !ok && token != "" && signer != nil {
+			// The roles of the users are validated when they are saved.
+			if claims, err := signer.Verify(token); err == nil {
+				name, ok = claims.Role, true
+				setAuthSubject(r, claims.Subject)
+			}
+		}
+		if token == "" || !ok {
+			m.rd.JSON(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		// The roles are validated with the config.
+		role, _ := rbac.ParseRole(name)
+		if required := m.requiredRole(r); !role.Allows(required) {
+			m.rd.JSON(w, http.StatusForbidden, fmt.Sprintf("the %s role is required", required))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// lookupToken returns the role of the token. The token is compared with all
+// the tokens in constant time, so the time taken reveals nothing about them.
+func lookupToken(tokens map[string]string, token string) (role string, ok bool) {
+	for t, r := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			role, ok = r, true
+		}
+	}
+	return role, ok
+}
+
+func (m rbacMiddleware) requiredRole(r *http.Request) rbac.Role {
+	if role, ok := m.roles[mux.CurrentRoute(r)]; ok {
+		return role
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return rbac.Viewer
+	default:
+		return rbac.Operator
+	}
+}
+
 type auditMiddleware struct {
 	svr *server.Server
 }
@@ -146,3 +234,81 @@ func (s *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next
 		backend.ProcessHTTPRequest(r)
 	}
 }
+
+// auditLogMiddleware writes an audit entry for every mutating call, including
+// the calls rejected by the other middlewares, if the audit log is enabled.
+type auditLogMiddleware struct {
+	s *server.Server
+}
+
+func newAuditLogMiddleware(s *server.Server) auditLogMiddleware {
+	return auditLogMiddleware{s: s}
+}
+
+func (m auditLogMiddleware) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := m.s.GetAuditLogger()
+		if logger == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			h.ServeHTTP(w, r)
+			return
+		}
+		var body []byte
+		if r.Body != nil {
+			var err error
+			if body, err = ioutil.ReadAll(r.Body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		// The subject is set by the RBAC middleware once the token is verified.
+		r = r.WithContext(context.WithValue(r.Context(), authSubjectCtxKey{}, new(string)))
+		entry := &audit.Entry{
+			Time:       time.Now(),
+			IP:         apiutil.GetIPAddrFromHTTPRequest(r),
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			URL:        r.URL.String(),
+			BodyHash:   fmt.Sprintf("%x", sha256.Sum256(body)),
+		}
+		sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		entry.User = auditUser(r)
+		entry.StatusCode = sw.status
+		if err := logger.Log(entry); err != nil {
+			log.Warn("write audit log failed", zap.String("url", entry.URL), errs.ZapError(err))
+		}
+	})
+}
+
+type authSubjectCtxKey struct{}
+
+// setAuthSubject records the subject of the verified token for the audit log.
+func setAuthSubject(r *http.Request, subject string) {
+	if s, ok := r.Context().Value(authSubjectCtxKey{}).(*string); ok {
+		*s = subject
+	}
+}
+
+// auditUser returns the subject of the JWT, the CN of the client certificate,
+// or the component name if the client uses neither.
+func auditUser(r *http.Request) string {
+	if s, ok := r.Context().Value(authSubjectCtxKey{}).(*string); ok && *s != "" {
+		return *s
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return apiutil.GetComponentNameOnHTTP(r)
+}
+
+// statusResponseWriter records the status code of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
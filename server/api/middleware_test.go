@@ -0,0 +1,317 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/audit"
+	"github.com/tikv/pd/pkg/rbac"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/config"
+)
+
+var _ = Suite(&testRBACSuite{})
+
+type testRBACSuite struct {
+	svr     *server.Server
+	cleanup cleanUpFunc
+	router  *mux.Router
+}
+
+var roleTokens = map[rbac.Role]string{
+	rbac.Viewer:   "viewer-token",
+	rbac.Operator: "operator-token",
+	rbac.Admin:    "admin-token",
+}
+
+func (s *testRBACSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) {
+		cfg.Security.APITokens = make(map[string]string)
+		for role, token := range roleTokens {
+			cfg.Security.APITokens[token] = role.String()
+		}
+	})
+	mustWaitLeader(c, []*server.Server{s.svr})
+	mustBootstrapCluster(c, s.svr)
+	s.router = createRouter(apiPrefix, s.svr)
+}
+
+func (s *testRBACSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRBACSuite) serve(method, url, token string) int {
+	req := httptest.NewRequest(method, url, strings.NewReader("{}"))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func (s *testRBACSuite) TestToken(c *C) {
+	url := apiPrefix + "/api/v1/config"
+	c.Assert(s.serve(http.MethodGet, url, ""), Equals, http.StatusUnauthorized)
+	c.Assert(s.serve(http.MethodGet, url, "unknown-token"), Equals, http.StatusUnauthorized)
+	c.Assert(s.serve(http.MethodGet, url, roleTokens[rbac.Viewer]), Equals, http.StatusOK)
+	c.Assert(s.serve(http.MethodPost, url, roleTokens[rbac.Viewer]), Equals, http.StatusForbidden)
+	c.Assert(s.serve(http.MethodPost, url, roleTokens[rbac.Operator]), Equals, http.StatusOK)
+}
+
+// adminRoutes are the routes which require the admin role.
+var adminRoutes = map[string]string{
+	"/config/cluster-version":            http.MethodPost,
+	"/config/replication-mode":           http.MethodPost,
+	"/store/{id}":                        http.MethodDelete,
+	"/store/{id}/state":                  http.MethodPost,
+	"/stores/remove-tombstone":           http.MethodDelete,
+	"/stores/tombstones":                 http.MethodDelete,
+	"/stores/{id}/drain":                 http.MethodPost + "," + http.MethodDelete,
+	"/stores/{id}/labels":                http.MethodPatch,
+	"/members/name/{name}":               http.MethodDelete + "," + http.MethodPost,
+	"/members/id/{id}":                   http.MethodDelete,
+	"/leader/resign":                     http.MethodPost,
+	"/leader/transfer/{next_leader}":     http.MethodPost,
+	"/admin/cache/region/{id}":           http.MethodDelete,
+	"/admin/reset-ts":                    http.MethodPost,
+	"/admin/persist-file/{file_name}":    http.MethodPost,
+	"/admin/replication_mode/wait-async": http.MethodPost,
+	"/replication_mode/state":            http.MethodPost,
+	"/admin/audit-middleware":            http.MethodPost,
+	"/admin/log":                         http.MethodPost,
+	"/plugin":                            http.MethodPost + "," + http.MethodDelete,
+	"/tso/allocator/transfer/{name}":     http.MethodPost,
+	"/gc/safepoint/{service_id}":         http.MethodDelete,
+	"/admin/unsafe/remove-failed-stores": http.MethodPost,
+	"/auth/users/{name}":                 http.MethodPut + "," + http.MethodDelete,
+	"/quotas":                            http.MethodPost,
+	"/quotas/{id}":                       http.MethodDelete,
+	"/keyspaces/merge":                   http.MethodPost,
+	"/keyspaces/{id}":                    http.MethodDelete,
+	"/keyspaces/{id}/split":              http.MethodPost,
+}
+
+// publicRoutes are the routes accessible without a token.
+var publicRoutes = map[string]string{
+	"/auth/token": http.MethodPost,
+}
+
+var routeVar = regexp.MustCompile(`\{[^}]+\}`)
+
+func (s *testRBACSuite) TestRejectLowerRoles(c *C) {
+	checked := 0
+	err := s.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || route.GetHandler() == nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet, http.MethodPost}
+		}
+		url := routeVar.ReplaceAllString(path, "1")
+		if queries, err := route.GetQueriesTemplates(); err == nil && len(queries) > 0 {
+			url += "?" + routeVar.ReplaceAllString(strings.Join(queries, "&"), "1")
+		}
+		for _, method := range methods {
+			if strings.Contains(publicRoutes[strings.TrimPrefix(path, apiPrefix+"/api/v1")], method) {
+				continue
+			}
+			required := rbac.Operator
+			if method == http.MethodGet {
+				required = rbac.Viewer
+			}
+			if strings.Contains(adminRoutes[strings.TrimPrefix(path, apiPrefix+"/api/v1")], method) {
+				required = rbac.Admin
+			}
+			c.Assert(s.serve(method, url, ""), Equals, http.StatusUnauthorized, Commentf("%s %s", method, path))
+			for role, token := range roleTokens {
+				if !role.Allows(required) {
+					c.Assert(s.serve(method, url, token), Equals, http.StatusForbidden, Commentf("%s %s %s", method, path, role))
+					checked++
+				}
+			}
+		}
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(checked, Greater, 2*len(adminRoutes))
+}
+
+var _ = Suite(&testJWTAuthSuite{})
+
+type testJWTAuthSuite struct {
+	svr     *server.Server
+	cleanup cleanUpFunc
+	router  *mux.Router
+}
+
+const jwtAdminToken = "admin-token"
+
+func (s *testJWTAuthSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) {
+		cfg.Security.APITokens = map[string]string{jwtAdminToken: rbac.Admin.String()}
+		cfg.Security.EnableJWTAuth = true
+	})
+	mustWaitLeader(c, []*server.Server{s.svr})
+	mustBootstrapCluster(c, s.svr)
+	s.router = createRouter(apiPrefix, s.svr)
+}
+
+func (s *testJWTAuthSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testJWTAuthSuite) serve(method, url, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, apiPrefix+"/api/v1"+url, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *testJWTAuthSuite) issueToken(c *C, username, password string) (string, int) {
+	w := s.serve(http.MethodPost, "/auth/token", "", fmt.Sprintf(`{"username": %q, "password": %q}`, username, password))
+	if w.Code != http.StatusOK {
+		return "", w.Code
+	}
+	resp := &tokenResponse{}
+	c.Assert(json.Unmarshal(w.Body.Bytes(), resp), IsNil)
+	c.Assert(resp.ExpiresAt.After(time.Now()), IsTrue)
+	return resp.Token, w.Code
+}
+
+func (s *testJWTAuthSuite) TestIssueToken(c *C) {
+	body := `{"password": "secret", "role": "viewer"}`
+	c.Assert(s.serve(http.MethodPut, "/auth/users/alice", "", body).Code, Equals, http.StatusUnauthorized)
+	c.Assert(s.serve(http.MethodPut, "/auth/users/alice", jwtAdminToken, `{"password": "secret", "role": "root"}`).Code, Equals, http.StatusBadRequest)
+	c.Assert(s.serve(http.MethodPut, "/auth/users/alice", jwtAdminToken, body).Code, Equals, http.StatusOK)
+	credential, err := s.svr.GetStorage().LoadAPICredential("alice")
+	c.Assert(err, IsNil)
+	c.Assert(credential.PasswordHash, Not(Equals), "secret")
+
+	_, code := s.issueToken(c, "alice", "wrong")
+	c.Assert(code, Equals, http.StatusUnauthorized)
+	_, code = s.issueToken(c, "bob", "secret")
+	c.Assert(code, Equals, http.StatusUnauthorized)
+	token, code := s.issueToken(c, "alice", "secret")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(s.serve(http.MethodGet, "/config", token, "").Code, Equals, http.StatusOK)
+	c.Assert(s.serve(http.MethodPost, "/config", token, "{}").Code, Equals, http.StatusForbidden)
+
+	c.Assert(s.serve(http.MethodDelete, "/auth/users/alice", token, "").Code, Equals, http.StatusForbidden)
+	c.Assert(s.serve(http.MethodDelete, "/auth/users/alice", jwtAdminToken, "").Code, Equals, http.StatusOK)
+	_, code = s.issueToken(c, "alice", "secret")
+	c.Assert(code, Equals, http.StatusUnauthorized)
+}
+
+func (s *testJWTAuthSuite) TestExpiry(c *C) {
+	token, _, err := s.svr.GetJWTSigner().Sign("alice", rbac.Admin.String(), -time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(s.serve(http.MethodGet, "/config", token, "").Code, Equals, http.StatusUnauthorized)
+}
+
+func (s *testJWTAuthSuite) TestRotateKey(c *C) {
+	signer := s.svr.GetJWTSigner()
+	token, _, err := signer.Sign("alice", rbac.Operator.String(), time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(s.serve(http.MethodPost, "/config", token, "{}").Code, Equals, http.StatusOK)
+	// The key is rotated once the leader changes.
+	c.Assert(signer.Rotate(), IsNil)
+	c.Assert(s.serve(http.MethodPost, "/config", token, "{}").Code, Equals, http.StatusUnauthorized)
+}
+
+var _ = Suite(&testAuditLogSuite{})
+
+type testAuditLogSuite struct {
+	svr      *server.Server
+	cleanup  cleanUpFunc
+	router   *mux.Router
+	filename string
+}
+
+func (s *testAuditLogSuite) SetUpSuite(c *C) {
+	s.filename = filepath.Join(c.MkDir(), "audit.log")
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) {
+		cfg.Security.AuditLog.Writer = "file"
+		cfg.Security.AuditLog.Filename = s.filename
+		cfg.Security.APITokens = map[string]string{jwtAdminToken: rbac.Admin.String()}
+		cfg.Security.EnableJWTAuth = true
+	})
+	mustWaitLeader(c, []*server.Server{s.svr})
+	mustBootstrapCluster(c, s.svr)
+	s.router = createRouter(apiPrefix, s.svr)
+}
+
+func (s *testAuditLogSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testAuditLogSuite) TestMutatingCalls(c *C) {
+	url := apiPrefix + "/api/v1/config"
+	body := `{"leader-schedule-limit": 8}`
+	token, _, err := s.svr.GetJWTSigner().Sign("alice", rbac.Admin.String(), time.Hour)
+	c.Assert(err, IsNil)
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		c.Assert(w.Code, Equals, http.StatusOK)
+	}
+
+	// Only the POST is audited.
+	f, err := os.Open(s.filename)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	var entries []*audit.Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &audit.Entry{}
+		c.Assert(json.Unmarshal(scanner.Bytes(), e), IsNil)
+		entries = append(entries, e)
+	}
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Method, Equals, http.MethodPost)
+	c.Assert(entries[0].URL, Equals, url)
+	c.Assert(entries[0].StatusCode, Equals, http.StatusOK)
+	// The subject of the token is recorded.
+	c.Assert(entries[0].User, Equals, "alice")
+	// The default remote address of httptest.
+	c.Assert(entries[0].RemoteAddr, Equals, "192.0.2.1:1234")
+	c.Assert(entries[0].BodyHash, Equals, fmt.Sprintf("%x", sha256.Sum256([]byte(body))))
+
+	_, err = f.Seek(0, 0)
+	c.Assert(err, IsNil)
+	head, err := audit.ReadHead(filepath.Join(s.svr.GetConfig().DataDir, "audit-log-head"))
+	c.Assert(err, IsNil)
+	c.Assert(audit.Verify(f, nil, head), IsNil)
+}
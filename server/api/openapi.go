@@ -0,0 +1,40 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/docs/openapi"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+type openAPIHandler struct{}
+
+func newOpenAPIHandler() *openAPIHandler {
+	return &openAPIHandler{}
+}
+
+// @Summary Get the OpenAPI 3.0 specification of the HTTP API.
+// @Produce json
+// @Success 200 {string} string "The specification of the HTTP API."
+// @Router /openapi.json [get]
+func (h *openAPIHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openapi.Spec()); err != nil {
+		log.Error("failed to write the OpenAPI specification", errs.ZapError(err))
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/docs/openapi"
+)
+
+var _ = Suite(&testOpenAPISuite{})
+
+type testOpenAPISuite struct{}
+
+func (s *testOpenAPISuite) TestGetSpec(c *C) {
+	cfgs, _, clean := mustNewCluster(c, 1)
+	defer clean()
+
+	resp, err := testDialClient.Get(cfgs[0].ClientUrls + apiPrefix + "/api/v1/openapi.json")
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "application/json")
+	buf, err := io.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	spec := struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]json.RawMessage `json:"paths"`
+	}{}
+	c.Assert(json.Unmarshal(buf, &spec), IsNil)
+	c.Assert(spec.OpenAPI, Equals, openapi.Version)
+	c.Assert(spec.Servers, HasLen, 1)
+	c.Assert(spec.Servers[0].URL, Equals, "/pd/api/v1")
+	for _, path := range []string{"/openapi.json", "/status", "/config"} {
+		_, ok := spec.Paths[path]
+		c.Assert(ok, IsTrue, Commentf("path %s", path))
+	}
+}
@@ -15,17 +15,21 @@
 package api
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/events"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/unrolled/render"
+	"golang.org/x/net/websocket"
 )
 
 type operatorHandler struct {
@@ -66,6 +70,89 @@ func (h *operatorHandler) GetOperatorsByRegion(w http.ResponseWriter, r *http.Re
 	h.r.JSON(w, http.StatusOK, op)
 }
 
+// @Tags operator
+// @Summary Watch the progress of a Region's pending operator through WebSocket. The events are sent when the steps start and finish, and the connection is closed once the operator finishes.
+// @Param region_id path int true "A Region's Id"
+// @Produce json
+// @Success 101 {object} events.ClusterEvent
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The operator does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /operators/{region_id}/watch [get]
+func (h *operatorHandler) WatchOperator(w http.ResponseWriter, r *http.Request) {
+	regionID, err := strconv.ParseUint(mux.Vars(r)["region_id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	oc := rc.GetOperatorController()
+	// Subscribe before getting the operator, so that no event is missed.
+	hub := oc.GetEventHub()
+	sub := hub.SubscribeRegion(regionID, rc.GetOpts().GetPDServerConfig().EventBufferSize)
+	defer hub.Unsubscribe(sub)
+	op := oc.GetOperator(regionID)
+	if op == nil {
+		h.r.JSON(w, http.StatusNotFound, server.ErrOperatorNotFound.Error())
+		return
+	}
+
+	websocket.Server{
+		Handshake: checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			// The client is not expected to send anything, the read fails once
+			// the client disconnects.
+			closed := make(chan struct{})
+			go func() {
+				_, _ = io.Copy(io.Discard, ws)
+				close(closed)
+			}()
+			// The operator is also checked periodically, in case the finish
+			// event is dropped because the buffer is full.
+			ticker := time.NewTicker(watchOperatorCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-closed:
+					return
+				case e := <-sub.Events():
+					if err := websocket.JSON.Send(ws, e); err != nil || e.Type == events.OperatorFinish {
+						return
+					}
+				case <-ticker.C:
+					if op.IsEnd() {
+						return
+					}
+				}
+			}
+		},
+	}.ServeHTTP(w, r)
+}
+
+// watchOperatorCheckInterval is the interval to check if the watched operator
+// has ended.
+const watchOperatorCheckInterval = time.Second
+
+// checkWebSocketOrigin accepts the WebSocket requests without the Origin
+// header, which are not sent by the browsers, and the ones from the same
+// origin, so that other web pages can not watch the cluster.
+func checkWebSocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin != nil && origin.Host != r.Host {
+		return errors.Errorf("cross origin WebSocket request from %s is not allowed", origin)
+	}
+	config.Origin = origin
+	return nil
+}
+
 // @Tags operator
 // @Summary List pending operators.
 // @Param kind query string false "Specify the operator kind." Enums(admin, leader, region)
@@ -371,6 +458,95 @@ func (h *operatorHandler) GetOperatorRecords(w http.ResponseWriter, r *http.Requ
 	h.r.JSON(w, http.StatusOK, records)
 }
 
+const defaultOperatorAuditLimit = 100
+
+// @Tags operator
+// @Summary lists the finished operators in the audit log from the newest to the oldest.
+// @Param limit query integer false "The max number of the operators" default(100)
+// @Param type query string false "The type of the operators, e.g. transfer-leader"
+// @Produce json
+// @Success 200 {array} schedule.OperatorAuditEntry
+// @Failure 400 {string} string "The request is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /operators/audit [get]
+func (h *operatorHandler) GetOperatorAuditLog(w http.ResponseWriter, r *http.Request) {
+	limit := defaultOperatorAuditLimit
+	if limitStr := r.URL.Query().Get("limit"); len(limitStr) > 0 {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit <= 0 {
+			h.r.JSON(w, http.StatusBadRequest, "limit should be positive")
+			return
+		}
+	}
+	entries, err := h.GetOperatorAuditEntries(limit, r.URL.Query().Get("type"))
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, entries)
+}
+
+// @Tags operator
+// @Summary Get the count of the finished operators and steps by type and result.
+// @Produce json
+// @Success 200 {object} schedule.OperatorResultStats
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /operators/stats [get]
+func (h *operatorHandler) GetOperatorResultStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.Handler.GetOperatorResultStats()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, stats)
+}
+
+// StoreOperatorCount is the count of the operators moving peers or leaders
+// into a store.
+type StoreOperatorCount struct {
+	StoreID uint64 `json:"store_id"`
+	Count   uint64 `json:"count"`
+	// Limit is the max count of the operators, 0 means no limit.
+	Limit uint64 `json:"limit"`
+}
+
+// @Tags operator
+// @Summary Get the count of the pending operators moving peers or leaders into a store.
+// @Param store_id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} StoreOperatorCount
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /operators/stores/{store_id} [get]
+func (h *operatorHandler) GetOperatorCountByStore(w http.ResponseWriter, r *http.Request) {
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "store_id")
+	if errParse != nil {
+		h.r.JSON(w, http.StatusBadRequest, errParse.Error())
+		return
+	}
+
+	count, err := h.GetStoreOperatorCount(storeID)
+	if err != nil {
+		if errors.ErrorEqual(err, server.ErrStoreNotFound(storeID)) {
+			h.r.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.r.JSON(w, http.StatusOK, &StoreOperatorCount{
+		StoreID: storeID,
+		Count:   count,
+		Limit:   h.GetScheduleConfig().MaxOperatorsPerStore,
+	})
+}
+
 func parseStoreIDsAndPeerRole(ids interface{}, roles interface{}) (map[uint64]placement.PeerRoleType, bool) {
 	items, ok := ids.([]interface{})
 	if !ok {
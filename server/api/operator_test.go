@@ -31,9 +31,12 @@ import (
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/events"
+	"github.com/tikv/pd/server/schedule"
 	pdoperator "github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/tikv/pd/server/versioninfo"
+	"golang.org/x/net/websocket"
 )
 
 var _ = Suite(&testOperatorSuite{})
@@ -108,12 +111,39 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 	records = mustReadURL(c, recordURL)
 	c.Assert(strings.Contains(records, "admin-remove-peer {rm peer: store [2]}"), IsTrue)
 
+	var entries []*schedule.OperatorAuditEntry
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/audit", s.urlPrefix), &entries), IsNil)
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].Type, Equals, "admin-remove-peer")
+	c.Assert(entries[0].Status, Equals, "Canceled")
+	c.Assert(entries[0].Steps[0].Step, Equals, "remove peer on store 2")
+	c.Assert(entries[1].Type, Equals, "admin-add-peer")
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/audit?limit=1", s.urlPrefix), &entries), IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/audit?type=admin-add-peer", s.urlPrefix), &entries), IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Type, Equals, "admin-add-peer")
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/audit?limit=0", s.urlPrefix), &entries), NotNil)
+
+	stats := &schedule.OperatorResultStats{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/stats", s.urlPrefix), stats), IsNil)
+	c.Assert(stats.Operators, HasLen, 2)
+	c.Assert(stats.Operators["admin-remove-peer"], DeepEquals, map[string]uint64{schedule.OperatorResultCancelled: 1})
+	c.Assert(stats.Steps["RemovePeer"][schedule.OperatorResultCancelled], Equals, uint64(1))
+
 	mustPutStore(c, s.svr, 4, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
 	err = postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), []byte(`{"name":"add-learner", "region_id": 1, "store_id": 4}`))
 	c.Assert(err, IsNil)
 	operator = mustReadURL(c, regionURL)
 	c.Assert(strings.Contains(operator, "add learner peer 2 on store 4"), IsTrue)
 
+	var count StoreOperatorCount
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/stores/4", s.urlPrefix), &count), IsNil)
+	c.Assert(count, DeepEquals, StoreOperatorCount{StoreID: 4, Count: 1, Limit: 4})
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/operators/stores/2", s.urlPrefix), &count), IsNil)
+	c.Assert(count.Count, Equals, uint64(0))
+	c.Assert(strings.Contains(mustReadURL(c, fmt.Sprintf("%s/operators/stores/10", s.urlPrefix)), "store 10 not found"), IsTrue)
+
 	// Fail to add peer to tombstone store.
 	err = s.svr.GetRaftCluster().RemoveStore(3, true)
 	c.Assert(err, IsNil)
@@ -390,6 +420,86 @@ func (s *testTransferRegionOperatorSuite) TestTransferRegionWithPlacementRule(c
 	}
 }
 
+var _ = Suite(&testWatchOperatorSuite{})
+
+type testWatchOperatorSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testWatchOperatorSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) { cfg.Replication.MaxReplicas = 1 })
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testWatchOperatorSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testWatchOperatorSuite) watch(regionID uint64) (*websocket.Conn, error) {
+	url := fmt.Sprintf("%s/operators/%d/watch", s.urlPrefix, regionID)
+	return websocket.Dial(strings.Replace(url, "http", "ws", 1), "", s.svr.GetAddr())
+}
+
+func (s *testWatchOperatorSuite) TestWatchOperator(c *C) {
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	peer1 := &metapb.Peer{Id: 1, StoreId: 1}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          1,
+		Peers:       []*metapb.Peer{peer1},
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, peer1)
+	mustRegionHeartbeat(c, s.svr, region)
+
+	_, err := s.watch(1)
+	c.Assert(err, NotNil)
+
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), []byte(`{"name":"add-peer", "region_id": 1, "store_id": 2}`)), IsNil)
+	op := s.svr.GetRaftCluster().GetOperatorController().GetOperator(1)
+	c.Assert(op, NotNil)
+	c.Assert(op.Len(), Equals, 2)
+	peerID := op.Step(0).(pdoperator.AddLearner).PeerID
+	// The requests from other origins are refused.
+	url := fmt.Sprintf("%s/operators/%d/watch", s.urlPrefix, 1)
+	_, err = websocket.Dial(strings.Replace(url, "http", "ws", 1), "", "http://example.com")
+	c.Assert(err, NotNil)
+	ws, err := s.watch(1)
+	c.Assert(err, IsNil)
+	defer ws.Close()
+	receive := func(typ events.EventType) *events.ClusterEvent {
+		e := &events.ClusterEvent{}
+		c.Assert(websocket.JSON.Receive(ws, e), IsNil)
+		c.Assert(e.Type, Equals, typ)
+		c.Assert(e.RegionID, Equals, uint64(1))
+		return e
+	}
+
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: peerID, StoreId: 2, Role: metapb.PeerRole_Learner}), core.WithIncConfVer())
+	mustRegionHeartbeat(c, s.svr, region)
+	e := receive(events.OperatorStepFinish)
+	c.Assert(e.Step.Index, Equals, 0)
+	c.Assert(e.Step.Type, Equals, "AddLearner")
+	c.Assert(e.Step.StoreID, Equals, uint64(2))
+	e = receive(events.OperatorStepStart)
+	c.Assert(e.Step.Index, Equals, 1)
+	c.Assert(e.Step.Type, Equals, "PromoteLearner")
+
+	region = region.Clone(core.WithPromoteLearner(peerID), core.WithIncConfVer())
+	mustRegionHeartbeat(c, s.svr, region)
+	e = receive(events.OperatorStepFinish)
+	c.Assert(e.Step.Index, Equals, 1)
+	receive(events.OperatorFinish)
+	// The connection is closed once the operator finishes.
+	c.Assert(websocket.JSON.Receive(ws, &events.ClusterEvent{}), Equals, io.EOF)
+}
+
 func mustPutStore(c *C, svr *server.Server, id uint64, state metapb.StoreState, nodeState metapb.NodeState, labels []*metapb.StoreLabel) {
 	s := &server.GrpcServer{Server: svr}
 	_, err := s.PutStore(context.Background(), &pdpb.PutStoreRequest{
@@ -0,0 +1,122 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule/quota"
+	"github.com/unrolled/render"
+)
+
+type quotaHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newQuotaHandler(s *server.Server, rd *render.Render) *quotaHandler {
+	return &quotaHandler{
+		svr: s,
+		rd:  rd,
+	}
+}
+
+// @Tags quota
+// @Summary List all region quotas of cluster.
+// @Produce json
+// @Success 200 {array} quota.RegionQuota
+// @Router /quotas [get]
+func (h *quotaHandler) GetQuotas(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, cluster.GetRegionQuotas().GetQuotas())
+}
+
+// @Tags quota
+// @Summary Update region quota of cluster.
+// @Accept json
+// @Param quota body quota.RegionQuota true "Parameters of region quota"
+// @Produce json
+// @Success 200 {string} string "Update region quota successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /quotas [post]
+func (h *quotaHandler) SetQuota(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	var q quota.RegionQuota
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &q); err != nil {
+		return
+	}
+	if err := cluster.GetRegionQuotas().SetQuota(&q); err != nil {
+		if errs.ErrRegionQuotaContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Update region quota successfully.")
+}
+
+// @Tags quota
+// @Summary List the regions exceeding their quotas in the last heartbeats.
+// @Produce json
+// @Success 200 {array} quota.Violation
+// @Router /quotas/violations [get]
+func (h *quotaHandler) GetViolations(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, cluster.GetRegionQuotas().GetViolations())
+}
+
+// @Tags quota
+// @Summary Get region quota of cluster by id.
+// @Param id path string true "Quota Id"
+// @Produce json
+// @Success 200 {object} quota.RegionQuota
+// @Failure 404 {string} string "The quota does not exist."
+// @Router /quotas/{id} [get]
+func (h *quotaHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	q := cluster.GetRegionQuotas().GetQuota(mux.Vars(r)["id"])
+	if q == nil {
+		h.rd.JSON(w, http.StatusNotFound, nil)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, q)
+}
+
+// @Tags quota
+// @Summary Delete region quota of cluster by id.
+// @Param id path string true "Quota Id"
+// @Produce json
+// @Success 200 {string} string "Delete region quota successfully."
+// @Failure 404 {string} string "The quota does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /quotas/{id} [delete]
+func (h *quotaHandler) DeleteQuota(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if err := cluster.GetRegionQuotas().DeleteQuota(mux.Vars(r)["id"]); err != nil {
+		if errs.ErrRegionQuotaNotFound.Equal(err) {
+			h.rd.JSON(w, http.StatusNotFound, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Delete region quota successfully.")
+}
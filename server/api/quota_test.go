@@ -0,0 +1,97 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/mock/mockhbstream"
+	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/quota"
+)
+
+var _ = Suite(&testQuotaSuite{})
+
+type testQuotaSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testQuotaSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/quotas", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testQuotaSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testQuotaSuite) TestQuota(c *C) {
+	var quotas []*quota.RegionQuota
+	c.Assert(readJSON(testDialClient, s.urlPrefix, &quotas), IsNil)
+	c.Assert(quotas, HasLen, 0)
+
+	q := &quota.RegionQuota{ID: "q1", StartKeyHex: "61", EndKeyHex: "62", WriteBytesPerSecond: 1024}
+	data, _ := json.Marshal(q)
+	c.Assert(postJSON(testDialClient, s.urlPrefix, data), IsNil)
+	var got quota.RegionQuota
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/q1", &got), IsNil)
+	c.Assert(got.WriteBytesPerSecond, Equals, uint64(1024))
+	c.Assert(readJSON(testDialClient, s.urlPrefix, &quotas), IsNil)
+	c.Assert(quotas, HasLen, 1)
+
+	data, _ = json.Marshal(&quota.RegionQuota{ID: "q2", StartKeyHex: "61"})
+	c.Assert(postJSON(testDialClient, s.urlPrefix, data), NotNil)
+
+	// The region writes 10 MiB in 10s.
+	stream := mockhbstream.NewHeartbeatStream()
+	s.svr.GetRaftCluster().GetHeartbeatStreams().BindStream(1, stream)
+	region := newTestRegionInfo(100, 1, []byte("a1"), []byte("a2"), core.SetWrittenBytes(10*1024*1024), core.SetReportInterval(10))
+	var hint *quota.ThrottleRegion
+	testutil.WaitUntil(c, func() bool {
+		mustRegionHeartbeat(c, s.svr, region)
+		if resp := stream.Recv(); resp != nil && resp.GetRegionId() == region.GetID() {
+			hint = quota.GetThrottleRegion(resp)
+		}
+		return hint != nil
+	})
+	c.Assert(hint, DeepEquals, &quota.ThrottleRegion{WriteBytesPerSecond: 1024})
+	var violations []*quota.Violation
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/violations", &violations), IsNil)
+	c.Assert(violations, HasLen, 1)
+	c.Assert(violations[0].RegionID, Equals, uint64(100))
+	c.Assert(violations[0].QuotaID, Equals, "q1")
+	c.Assert(violations[0].WriteBytesPerSecond, Equals, uint64(1024*1024))
+
+	code, err := doDelete(testDialClient, s.urlPrefix+"/q1")
+	c.Assert(err, IsNil)
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/violations", &violations), IsNil)
+	c.Assert(violations, HasLen, 0)
+	code, err = doDelete(testDialClient, s.urlPrefix+"/q1")
+	c.Assert(err, IsNil)
+	c.Assert(code, Equals, http.StatusNotFound)
+}
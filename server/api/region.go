@@ -15,21 +15,30 @@
 package api
 
 import (
+	"bytes"
 	"container/heap"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/docker/go-units"
+	"github.com/gogo/protobuf/proto"
 	"github.com/gorilla/mux"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/kvproto/pkg/replication_modepb"
 	"github.com/pingcap/log"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/core"
@@ -355,6 +364,244 @@ func (h *regionsHandler) ScanRegions(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
 }
 
+// RegionsPage is a page of the regions in a key range.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionsPage struct {
+	RegionsInfo
+	// NextPageToken is used to get the next page, it is empty on the last page.
+	NextPageToken string `json:"next-page-token,omitempty"`
+}
+
+// @Tags region
+// @Summary List regions in a given range [start-key, end-key) page by page.
+// @Param start-key query string false "Region range start key, hex encoded"
+// @Param end-key query string false "Region range end key, hex encoded"
+// @Param limit query integer false "Limit count" default(1000)
+// @Param page-token query string false "The next-page-token returned by the previous page"
+// @Produce json
+// @Produce application/x-ndjson
+// @Success 200 {object} RegionsPage
+// @Failure 400 {string} string "The input is invalid."
+// @Router /regions/range [get]
+func (h *regionsHandler) GetRegionsPage(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	query := r.URL.Query()
+	startKey, err := hex.DecodeString(query.Get("start-key"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	endKey, err := hex.DecodeString(query.Get("end-key"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit := defaultRegionPageLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "limit should be positive")
+			return
+		}
+	}
+	if limit > maxRegionLimit {
+		limit = maxRegionLimit
+	}
+	// The page token is the ID of the first region of the page, the page starts
+	// from the start key of the region.
+	if token := query.Get("page-token"); token != "" {
+		regionID, err := strconv.ParseUint(token, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid page token")
+			return
+		}
+		region := rc.GetRegion(regionID)
+		if region == nil {
+			h.rd.JSON(w, http.StatusBadRequest, "the page token is expired because the region has been merged")
+			return
+		}
+		startKey = region.GetStartKey()
+	}
+
+	regions := rc.ScanRegions(startKey, endKey, limit+1)
+	page := &RegionsPage{}
+	if len(regions) > limit {
+		page.NextPageToken = strconv.FormatUint(regions[limit].GetID(), 10)
+		regions = regions[:limit]
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+		w.WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(w)
+		var regionInfo RegionInfo
+		for _, region := range regions {
+			InitRegion(region, &regionInfo)
+			if err := encoder.Encode(&regionInfo); err != nil {
+				log.Warn("failed to write the region", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+				return
+			}
+		}
+		return
+	}
+	page.RegionsInfo = *convertToAPIRegions(regions)
+	h.rd.JSON(w, http.StatusOK, page)
+}
+
+// RegionsSizeEstimation is the estimated size of the regions in a key range.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionsSizeEstimation struct {
+	ApproximateSizeBytes uint64 `json:"approximate_size_bytes"`
+	ApproximateKeys      uint64 `json:"approximate_keys"`
+	RegionCount          int    `json:"region_count"`
+	// Accuracy is "exact" if the key range is aligned to the region boundaries,
+	// otherwise it is "approximate" since the regions at the edges are counted
+	// as a whole.
+	Accuracy string `json:"accuracy"`
+}
+
+const (
+	estimationExact       = "exact"
+	estimationApproximate = "approximate"
+)
+
+// @Tags region
+// @Summary Estimate the size and the keys of the regions overlapping a given range [start-key, end-key).
+// @Param start-key query string false "Region range start key, hex encoded"
+// @Param end-key query string false "Region range end key, hex encoded"
+// @Produce json
+// @Success 200 {object} RegionsSizeEstimation
+// @Failure 400 {string} string "The input is invalid."
+// @Router /regions/size-estimation [get]
+func (h *regionsHandler) GetRegionsSizeEstimation(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	query := r.URL.Query()
+	startKey, err := hex.DecodeString(query.Get("start-key"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	endKey, err := hex.DecodeString(query.Get("end-key"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	regions := rc.ScanRegions(startKey, endKey, 0)
+	estimation := &RegionsSizeEstimation{
+		RegionCount: len(regions),
+		Accuracy:    estimationExact,
+	}
+	for _, region := range regions {
+		estimation.ApproximateSizeBytes += uint64(region.GetApproximateSize()) * units.MiB
+		estimation.ApproximateKeys += uint64(region.GetApproximateKeys())
+	}
+	if len(regions) > 0 {
+		first, last := regions[0], regions[len(regions)-1]
+		if !bytes.Equal(first.GetStartKey(), startKey) || !bytes.Equal(last.GetEndKey(), endKey) {
+			estimation.Accuracy = estimationApproximate
+		}
+	}
+	h.rd.JSON(w, http.StatusOK, estimation)
+}
+
+// RegionSizeBucket is a bucket of the region size histogram.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionSizeBucket struct {
+	// UpperBound is the inclusive upper bound of the region sizes in MiB, it
+	// is omitted in the last bucket which has no upper bound.
+	UpperBound *int64 `json:"upper_bound,omitempty"`
+	Count      int    `json:"count"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+var defaultRegionSizeBuckets = []int64{10, 50, 100, 144, 256, 512}
+
+// @Tags region
+// @Summary Get the histogram of the region sizes.
+// @Param buckets query string false "Comma separated upper bounds of the buckets in MiB, in ascending order" default(10,50,100,144,256,512)
+// @Produce json
+// @Produce text/plain
+// @Success 200 {array} RegionSizeBucket
+// @Failure 400 {string} string "The input is invalid."
+// @Router /regions/size-histogram [get]
+func (h *regionsHandler) GetRegionSizeHistogram(w http.ResponseWriter, r *http.Request) {
+	bounds := defaultRegionSizeBuckets
+	if bucketsStr := r.URL.Query().Get("buckets"); bucketsStr != "" {
+		bounds = bounds[:0:0]
+		for _, s := range strings.Split(bucketsStr, ",") {
+			bound, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			if err != nil {
+				h.rd.JSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if bound <= 0 || (len(bounds) > 0 && bound <= bounds[len(bounds)-1]) {
+				h.rd.JSON(w, http.StatusBadRequest, "the buckets should be positive and in ascending order")
+				return
+			}
+			bounds = append(bounds, bound)
+		}
+	}
+
+	buckets := make([]RegionSizeBucket, len(bounds)+1)
+	for i := range bounds {
+		buckets[i].UpperBound = &bounds[i]
+	}
+	for _, region := range getCluster(r).GetRegions() {
+		size := region.GetApproximateSize()
+		i := sort.Search(len(bounds), func(i int) bool { return size <= bounds[i] })
+		buckets[i].Count++
+		buckets[i].TotalBytes += uint64(size) * units.MiB
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/plain") {
+		w.Header().Set("Content-Type", string(expfmt.FmtText))
+		w.WriteHeader(http.StatusOK)
+		if _, err := expfmt.MetricFamilyToText(w, regionSizeHistogramMetric(buckets)); err != nil {
+			log.Warn("failed to write the region size histogram", errs.ZapError(err))
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, buckets)
+}
+
+// regionSizeHistogramMetric converts the buckets to a Prometheus histogram
+// in bytes.
+func regionSizeHistogramMetric(buckets []RegionSizeBucket) *dto.MetricFamily {
+	var (
+		count uint64
+		sum   float64
+	)
+	promBuckets := make([]*dto.Bucket, 0, len(buckets)-1)
+	for _, b := range buckets {
+		count += uint64(b.Count)
+		sum += float64(b.TotalBytes)
+		if b.UpperBound != nil {
+			promBuckets = append(promBuckets, &dto.Bucket{
+				CumulativeCount: proto.Uint64(count),
+				UpperBound:      proto.Float64(float64(*b.UpperBound * units.MiB)),
+			})
+		}
+	}
+	return &dto.MetricFamily{
+		Name: proto.String("pd_region_size_bytes"),
+		Help: proto.String("Histogram of the approximate sizes of the regions."),
+		Type: dto.MetricType_HISTOGRAM.Enum(),
+		Metric: []*dto.Metric{{
+			Histogram: &dto.Histogram{
+				SampleCount: proto.Uint64(count),
+				SampleSum:   proto.Float64(sum),
+				Bucket:      promBuckets,
+			},
+		}},
+	}
+}
+
 // @Tags region
 // @Summary Get count of regions.
 // @Produce json
@@ -647,8 +894,128 @@ func (h *regionsHandler) GetRegionSiblings(w http.ResponseWriter, r *http.Reques
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
 }
 
+// @Tags region
+// @Summary Get the splits and the merges which a region is derived from and derived to.
+// @Param id path integer true "Region Id"
+// @Produce json
+// @Success 200 {object} genealogy.Chain
+// @Failure 400 {string} string "The input is invalid."
+// @Router /regions/{id}/genealogy [get]
+func (h *regionsHandler) GetRegionGenealogy(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+
+	vars := mux.Vars(r)
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The region may not exist since it has been merged.
+	h.rd.JSON(w, http.StatusOK, rc.GetGenealogy().GetChain(id))
+}
+
+// @Tags region
+// @Summary Get the union of the labels of the stores holding the replicas of a region.
+// @Param id path integer true "Region Id"
+// @Produce json
+// @Success 200 {object} map[string][]string
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The region does not exist."
+// @Router /regions/{id}/labels [get]
+func (h *regionsHandler) GetRegionStoreLabels(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+
+	vars := mux.Vars(r)
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	labels := rc.GetBasicCluster().RegionStoreLabels(id)
+	if labels == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrRegionNotFound(id).Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, labels)
+}
+
+// @Tags region
+// @Summary Explain how the placement rules are evaluated for a region.
+// @Param id path integer true "Region Id"
+// @Produce json
+// @Success 200 {object} placement.RuleTrace
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The region does not exist."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Router /regions/{id}/rule-trace [get]
+func (h *regionsHandler) GetRegionRuleTrace(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	if !rc.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	trace := rc.GetRuleManager().TraceRuleEvaluation(id)
+	if trace == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrRegionNotFound(id).Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, trace)
+}
+
+// defaultRegionTrafficWindow is 5 heartbeats of a region by default.
+const defaultRegionTrafficWindow = 5 * time.Minute
+
+// @Tags region
+// @Summary Get the recent read and write traffic of a region reported by the heartbeats.
+// @Param id path integer true "Region Id"
+// @Param window query string false "The duration of the recent traffic" default(5m)
+// @Param resolution query string false "The duration to sum the traffic, every heartbeat is returned if it is empty"
+// @Produce json
+// @Success 200 {array} statistics.TrafficSample
+// @Failure 400 {string} string "The input is invalid."
+// @Router /regions/{id}/traffic [get]
+func (h *regionsHandler) GetRegionTraffic(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+
+	vars := mux.Vars(r)
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	window := defaultRegionTrafficWindow
+	if windowStr := query.Get("window"); windowStr != "" {
+		if window, err = time.ParseDuration(windowStr); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var resolution time.Duration
+	if resolutionStr := query.Get("resolution"); resolutionStr != "" {
+		if resolution, err = time.ParseDuration(resolutionStr); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	samples := rc.GetRegionTraffic().GetSamples(id, time.Now().Add(-window))
+	samples = statistics.AggregateTraffic(samples, resolution)
+	if samples == nil {
+		samples = []statistics.TrafficSample{}
+	}
+	h.rd.JSON(w, http.StatusOK, samples)
+}
+
 const (
 	defaultRegionLimit     = 16
+	defaultRegionPageLimit = 1000
 	maxRegionLimit         = 10240
 	minRegionHistogramSize = 1
 	minRegionHistogramKeys = 1000
@@ -15,15 +15,21 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -31,6 +37,7 @@ import (
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/statistics"
 )
 
 var _ = Suite(&testRegionStructSuite{})
@@ -150,6 +157,53 @@ func (s *testRegionSuite) TestRegion(c *C) {
 	c.Assert(r2, DeepEquals, NewRegionInfo(r))
 }
 
+func (s *testRegionSuite) TestRegionTraffic(c *C) {
+	// The samples are in the same minute, and within the default window.
+	now := time.Now().Unix()
+	base := now - now%60 - 180
+	for _, ts := range []int64{base, base + 10} {
+		mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b"), core.SetReportInterval(uint64(ts))))
+	}
+	// The heartbeats of the other tests are not older than the test suite.
+	readSamples := func(url string) []statistics.TrafficSample {
+		var samples, res []statistics.TrafficSample
+		c.Assert(readJSON(testDialClient, url, &samples), IsNil)
+		for _, sample := range samples {
+			if sample.Timestamp <= base+10 {
+				res = append(res, sample)
+			}
+		}
+		return res
+	}
+
+	url := fmt.Sprintf("%s/regions/2/traffic", s.urlPrefix)
+	samples := readSamples(url)
+	c.Assert(samples, HasLen, 2)
+	for i, sample := range samples {
+		c.Assert(sample, DeepEquals, statistics.TrafficSample{
+			Timestamp:  base + int64(i)*10,
+			ReadBytes:  200 * 1024 * 1024,
+			WriteBytes: 100 * 1024 * 1024,
+			ReadKeys:   2 * 1024 * 1024,
+			WriteKeys:  1 * 1024 * 1024,
+		})
+	}
+	c.Assert(readSamples(url+"?window=10m&resolution=1m"), DeepEquals, []statistics.TrafficSample{{
+		Timestamp:  base,
+		ReadBytes:  400 * 1024 * 1024,
+		WriteBytes: 200 * 1024 * 1024,
+		ReadKeys:   4 * 1024 * 1024,
+		WriteKeys:  2 * 1024 * 1024,
+	}})
+	c.Assert(readSamples(url+"?window=1s"), HasLen, 0)
+	c.Assert(readSamples(fmt.Sprintf("%s/regions/999/traffic", s.urlPrefix)), HasLen, 0)
+
+	var samplesOrErr []statistics.TrafficSample
+	for _, query := range []string{"?window=x", "?resolution=x"} {
+		c.Assert(readJSON(testDialClient, url+query, &samplesOrErr), NotNil)
+	}
+}
+
 func (s *testRegionSuite) TestRegionCheck(c *C) {
 	r := newTestRegionInfo(2, 1, []byte("a"), []byte("b"))
 	downPeer := &metapb.Peer{Id: 13, StoreId: 2}
@@ -544,6 +598,281 @@ func (s *testGetRegionRangeHolesSuite) TestRegionRangeHoles(c *C) {
 	})
 }
 
+var _ = Suite(&testGetRegionsPageSuite{})
+
+type testGetRegionsPageSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testGetRegionsPageSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	// The bootstrapped region 1 is [, ), so split it into [, "a") and the
+	// regions [a0, a1), [a1, a2) ... [a9, b) and [b, ).
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(1, 1, []byte(""), []byte("a0"), core.SetRegionVersion(2)))
+	for i := 0; i < 10; i++ {
+		endKey := []byte(fmt.Sprintf("a%d", i+1))
+		if i == 9 {
+			endKey = []byte("b")
+		}
+		mustRegionHeartbeat(c, s.svr, newTestRegionInfo(uint64(i+100), 1, []byte(fmt.Sprintf("a%d", i)), endKey, core.SetRegionVersion(2)))
+	}
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(200, 1, []byte("b"), []byte(""), core.SetRegionVersion(2)))
+}
+
+func (s *testGetRegionsPageSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testGetRegionsPageSuite) TestIteratePages(c *C) {
+	for _, limit := range []int{1, 3, 5, 10, 20} {
+		var ids []uint64
+		token := ""
+		for pages := 0; ; pages++ {
+			c.Assert(pages, Less, 20)
+			url := fmt.Sprintf("%s/regions/range?start-key=%s&end-key=%s&limit=%d&page-token=%s", s.urlPrefix,
+				hex.EncodeToString([]byte("a0")), hex.EncodeToString([]byte("b")), limit, token)
+			page := &RegionsPage{}
+			c.Assert(readJSON(testDialClient, url, page), IsNil)
+			c.Assert(page.Count, LessEqual, limit)
+			for _, region := range page.Regions {
+				ids = append(ids, region.ID)
+			}
+			if token = page.NextPageToken; token == "" {
+				break
+			}
+		}
+		c.Assert(ids, HasLen, 10)
+		for i, id := range ids {
+			c.Assert(id, Equals, uint64(i+100))
+		}
+	}
+
+	// All regions are returned without the key range.
+	page := &RegionsPage{}
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/range", page), IsNil)
+	c.Assert(page.Count, Equals, 12)
+	c.Assert(page.NextPageToken, Equals, "")
+
+	for _, query := range []string{"start-key=zz", "end-key=zz", "limit=0", "limit=x", "page-token=x", "page-token=999"} {
+		c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/range?"+query, page), NotNil)
+	}
+}
+
+func (s *testGetRegionsPageSuite) TestNDJSON(c *C) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/regions/range?start-key=%s&limit=4", s.urlPrefix, hex.EncodeToString([]byte("a0"))), nil)
+	c.Assert(err, IsNil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	resp, err := testDialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "application/x-ndjson")
+	c.Assert(resp.Header.Get("X-Next-Page-Token"), Equals, "104")
+
+	scanner := bufio.NewScanner(resp.Body)
+	var ids []uint64
+	for scanner.Scan() {
+		region := &RegionInfo{}
+		c.Assert(json.Unmarshal(scanner.Bytes(), region), IsNil)
+		ids = append(ids, region.ID)
+	}
+	c.Assert(scanner.Err(), IsNil)
+	c.Assert(ids, DeepEquals, []uint64{100, 101, 102, 103})
+}
+
+func (s *testGetRegionsPageSuite) TestSizeEstimation(c *C) {
+	// Every region is 10MiB with 10 keys.
+	testCases := []struct {
+		startKey, endKey string
+		count            int
+		accuracy         string
+	}{
+		{"a0", "b", 10, "exact"},
+		{"", "", 12, "exact"},
+		{"b", "", 1, "exact"},
+		{"a05", "b", 10, "approximate"},
+		{"a0", "a55", 6, "approximate"},
+		{"", "a", 1, "approximate"},
+	}
+	for _, t := range testCases {
+		url := fmt.Sprintf("%s/regions/size-estimation?start-key=%s&end-key=%s", s.urlPrefix,
+			hex.EncodeToString([]byte(t.startKey)), hex.EncodeToString([]byte(t.endKey)))
+		estimation := &RegionsSizeEstimation{}
+		c.Assert(readJSON(testDialClient, url, estimation), IsNil)
+		c.Assert(estimation.RegionCount, Equals, t.count)
+		c.Assert(estimation.ApproximateSizeBytes, Equals, uint64(t.count)*10*units.MiB)
+		c.Assert(estimation.ApproximateKeys, Equals, uint64(t.count)*10)
+		c.Assert(estimation.Accuracy, Equals, t.accuracy)
+	}
+
+	estimation := &RegionsSizeEstimation{}
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/size-estimation?start-key=zz", estimation), NotNil)
+}
+
+var _ = Suite(&testRegionSizeHistogramSuite{})
+
+type testRegionSizeHistogramSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testRegionSizeHistogramSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	sizes := []int64{5, 30, 60, 96, 144, 200, 600}
+	for i, size := range sizes {
+		startKey, endKey := []byte(fmt.Sprintf("a%d", i)), []byte(fmt.Sprintf("a%d", i+1))
+		if i == 0 {
+			startKey = []byte("")
+		}
+		if i == len(sizes)-1 {
+			endKey = []byte("")
+		}
+		regionID := uint64(i + 100)
+		if i == 0 {
+			regionID = 1
+		}
+		mustRegionHeartbeat(c, s.svr, newTestRegionInfo(regionID, 1, startKey, endKey, core.SetRegionVersion(2), core.SetApproximateSize(size)))
+	}
+}
+
+func (s *testRegionSizeHistogramSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRegionSizeHistogramSuite) TestSizeHistogram(c *C) {
+	var buckets []RegionSizeBucket
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/size-histogram?buckets=10,50,100,144,256,512", &buckets), IsNil)
+	c.Assert(buckets, HasLen, 7)
+	expected := []struct {
+		upperBound int64
+		count      int
+		totalMiB   uint64
+	}{
+		{10, 1, 5},
+		{50, 1, 30},
+		{100, 2, 156},
+		{144, 1, 144},
+		{256, 1, 200},
+		{512, 0, 0},
+		{0, 1, 600},
+	}
+	for i, e := range expected {
+		if e.upperBound == 0 {
+			c.Assert(buckets[i].UpperBound, IsNil)
+		} else {
+			c.Assert(*buckets[i].UpperBound, Equals, e.upperBound)
+		}
+		c.Assert(buckets[i].Count, Equals, e.count)
+		c.Assert(buckets[i].TotalBytes, Equals, e.totalMiB*units.MiB)
+	}
+
+	// The default buckets are used without the buckets.
+	buckets = nil
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/size-histogram", &buckets), IsNil)
+	c.Assert(buckets, HasLen, 7)
+
+	buckets = nil
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/size-histogram?buckets=100", &buckets), IsNil)
+	c.Assert(buckets, HasLen, 2)
+	c.Assert(buckets[0].Count, Equals, 4)
+	c.Assert(buckets[1].Count, Equals, 3)
+
+	for _, invalid := range []string{"a", "0", "50,10", "10,10"} {
+		c.Assert(readJSON(testDialClient, s.urlPrefix+"/regions/size-histogram?buckets="+invalid, &buckets), NotNil)
+	}
+}
+
+func (s *testRegionSizeHistogramSuite) TestSizeHistogramText(c *C) {
+	req, err := http.NewRequest(http.MethodGet, s.urlPrefix+"/regions/size-histogram?buckets=50,200", nil)
+	c.Assert(err, IsNil)
+	req.Header.Set("Accept", "text/plain")
+	resp, err := testDialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Matches, "text/plain.*")
+	body, err := io.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	for _, line := range []string{
+		"# TYPE pd_region_size_bytes histogram",
+		`pd_region_size_bytes_bucket{le="5.24288e+07"} 2`,
+		`pd_region_size_bytes_bucket{le="2.097152e+08"} 6`,
+		`pd_region_size_bytes_bucket{le="+Inf"} 7`,
+		"pd_region_size_bytes_sum 1.19013376e+09",
+		"pd_region_size_bytes_count 7",
+	} {
+		c.Assert(strings.Contains(string(body), line+"\n"), IsTrue, Commentf("%s", body))
+	}
+}
+
+var _ = Suite(&testRegionStoreLabelsSuite{})
+
+type testRegionStoreLabelsSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testRegionStoreLabelsSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testRegionStoreLabelsSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRegionStoreLabelsSuite) TestRegionStoreLabels(c *C) {
+	labels := []*metapb.StoreLabel{{Key: "zone", Value: "z1"}, {Key: "host", Value: "h1"}}
+	mustPutStore(c, s.svr, 100, metapb.StoreState_Up, metapb.NodeState_Serving, labels)
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(100, 100, []byte("x1"), []byte("x2")))
+
+	var res map[string][]string
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/regions/100/labels", s.urlPrefix), &res), IsNil)
+	c.Assert(res, DeepEquals, map[string][]string{"zone": {"z1"}, "host": {"h1"}})
+
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/regions/999/labels", s.urlPrefix), &res), NotNil)
+}
+
+func (s *testRegionStoreLabelsSuite) TestRegionRuleTrace(c *C) {
+	labels := []*metapb.StoreLabel{{Key: "zone", Value: "z1"}, {Key: "host", Value: "h1"}}
+	mustPutStore(c, s.svr, 100, metapb.StoreState_Up, metapb.NodeState_Serving, labels)
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(100, 100, []byte("x1"), []byte("x2")))
+
+	var trace placement.RuleTrace
+	url := fmt.Sprintf("%s/regions/100/rule-trace", s.urlPrefix)
+	c.Assert(readJSON(testDialClient, url, &trace), IsNil)
+	c.Assert(trace.RegionID, Equals, uint64(100))
+	c.Assert(trace.Satisfied, IsFalse)
+	c.Assert(trace.Rules, HasLen, 1)
+	c.Assert(trace.Rules[0].Rule, Equals, "pd/default")
+	c.Assert(trace.Rules[0].Status, Equals, placement.RuleTraceApplied)
+	c.Assert(trace.Rules[0].SelectedPeers, HasLen, 1)
+	c.Assert(trace.Rules[0].SelectedPeers[0].StoreID, Equals, uint64(100))
+
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/regions/999/rule-trace", s.urlPrefix), &trace), NotNil)
+}
+
 var _ = Suite(&testRegionsReplicatedSuite{})
 
 type testRegionsReplicatedSuite struct {
@@ -16,7 +16,10 @@ package api
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
 )
@@ -41,3 +44,45 @@ func newReplicationModeHandler(svr *server.Server, rd *render.Render) *replicati
 func (h *replicationModeHandler) GetReplicationModeStatus(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, getCluster(r).GetReplicationMode().GetReplicationStatusHTTP())
 }
+
+// @Tags replication_mode
+// @Summary Get the replication lag of the stores in the dr zone and the replication peers, which is estimated from the pending peers of the stores.
+// @Produce json
+// @Success 200 {object} replication.ReplicationLag
+// @Router /replication_mode/lag [get]
+func (h *replicationModeHandler) GetReplicationLag(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, getCluster(r).GetReplicationMode().GetReplicationLag())
+}
+
+// SetDRStateParams is the input to switch the dr-auto-sync state manually.
+type SetDRStateParams struct {
+	State    string     `json:"state"`
+	Pin      bool       `json:"pin"`
+	PinUntil *time.Time `json:"pin_until"`
+}
+
+// @Tags replication_mode
+// @Summary Switch the dr-auto-sync state manually. If the state is pinned, the automatic transitions are skipped until pin_until, or until it is unpinned if pin_until is not set. An empty state only updates the pin.
+// @Accept json
+// @Param body body SetDRStateParams true "The state and the pin"
+// @Produce json
+// @Success 200 {object} replication.HTTPReplicationStatus
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /replication_mode/state [post]
+func (h *replicationModeHandler) SetDRState(w http.ResponseWriter, r *http.Request) {
+	var params SetDRStateParams
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &params); err != nil {
+		return
+	}
+	modeManager := getCluster(r).GetReplicationMode()
+	if err := modeManager.SetDRState(params.State, params.Pin, params.PinUntil); err != nil {
+		if errs.ErrDRAutoSyncNotEnabled.Equal(err) || errs.ErrDRAutoSyncState.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, modeManager.GetReplicationStatusHTTP())
+}
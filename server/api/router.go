@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/audit"
+	"github.com/tikv/pd/pkg/rbac"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
 	"github.com/urfave/negroni"
@@ -130,11 +131,31 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 		}
 	}
 
+	// The routes without a role are accessible to the viewers to read and to
+	// the operators to write, the destructive routes require the admins.
+	roles := make(map[*mux.Route]rbac.Role)
+	setRole := func(role rbac.Role) createRouteOption {
+		return func(route *mux.Route) {
+			roles[route] = role
+		}
+	}
+	// The public routes are accessible without a token, e.g. the one to get
+	// a token.
+	public := make(map[*mux.Route]struct{})
+	setPublic := func() createRouteOption {
+		return func(route *mux.Route) {
+			public[route] = struct{}{}
+		}
+	}
+
 	localLog := audit.LocalLogLabel
 	// Please don't use PrometheusHistogram in the hot path.
 	prometheus := audit.PrometheusHistogram
 
 	rootRouter := mux.NewRouter().PathPrefix(prefix).Subrouter()
+	// The audit log goes first to record the calls denied by RBAC.
+	rootRouter.Use(newAuditLogMiddleware(svr).Middleware)
+	rootRouter.Use(newRBACMiddleware(svr, roles, public).Middleware)
 	handler := svr.GetHandler()
 
 	apiPrefix := "/api/v1"
@@ -153,7 +174,11 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	registerFunc(apiRouter, "/operators", operatorHandler.GetOperators, setMethods("GET"))
 	registerFunc(apiRouter, "/operators", operatorHandler.CreateOperator, setMethods("POST"), setAuditBackend(prometheus))
 	registerFunc(apiRouter, "/operators/records", operatorHandler.GetOperatorRecords, setMethods("GET"))
+	registerFunc(apiRouter, "/operators/audit", operatorHandler.GetOperatorAuditLog, setMethods("GET"))
+	registerFunc(apiRouter, "/operators/stats", operatorHandler.GetOperatorResultStats, setMethods("GET"))
+	registerFunc(apiRouter, "/operators/stores/{store_id}", operatorHandler.GetOperatorCountByStore, setMethods("GET"))
 	registerFunc(apiRouter, "/operators/{region_id}", operatorHandler.GetOperatorsByRegion, setMethods("GET"))
+	registerFunc(apiRouter, "/operators/{region_id}/watch", operatorHandler.WatchOperator, setMethods("GET"))
 	registerFunc(apiRouter, "/operators/{region_id}", operatorHandler.DeleteOperatorByRegion, setMethods("DELETE"))
 
 	checkerHandler := newCheckerHandler(svr, rd)
@@ -165,6 +190,10 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	registerFunc(apiRouter, "/schedulers", schedulerHandler.CreateScheduler, setMethods("POST"))
 	registerFunc(apiRouter, "/schedulers/{name}", schedulerHandler.DeleteScheduler, setMethods("DELETE"))
 	registerFunc(apiRouter, "/schedulers/{name}", schedulerHandler.PauseOrResumeScheduler, setMethods("POST"))
+	registerFunc(apiRouter, "/schedulers/{name}/pause", schedulerHandler.PauseScheduler, setMethods("POST"))
+	registerFunc(apiRouter, "/schedulers/{name}/resume", schedulerHandler.ResumeScheduler, setMethods("POST"))
+	registerFunc(apiRouter, "/schedulers/{name}/time-window", schedulerHandler.SetSchedulerTimeWindow, setMethods("POST"))
+	registerFunc(apiRouter, "/schedulers/{name}/time-window", schedulerHandler.GetSchedulerTimeWindow, setMethods("GET"))
 
 	schedulerConfigHandler := newSchedulerConfigHandler(svr, rd)
 	registerPrefix(apiRouter, "/scheduler-config", schedulerConfigHandler.GetSchedulerConfig)
@@ -185,14 +214,20 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	registerFunc(apiRouter, "/config/label-property", confHandler.GetLabelPropertyConfig, setMethods("GET"))
 	registerFunc(apiRouter, "/config/label-property", confHandler.SetLabelPropertyConfig, setMethods("POST"))
 	registerFunc(apiRouter, "/config/cluster-version", confHandler.GetClusterVersion, setMethods("GET"))
-	registerFunc(apiRouter, "/config/cluster-version", confHandler.SetClusterVersion, setMethods("POST"))
+	registerFunc(apiRouter, "/config/cluster-version", confHandler.SetClusterVersion, setMethods("POST"), setRole(rbac.Admin))
 	registerFunc(apiRouter, "/config/replication-mode", confHandler.GetReplicationModeConfig, setMethods("GET"))
-	registerFunc(apiRouter, "/config/replication-mode", confHandler.SetReplicationModeConfig, setMethods("POST"))
+	registerFunc(apiRouter, "/config/replication-mode", confHandler.SetReplicationModeConfig, setMethods("POST"), setRole(rbac.Admin))
 
 	rulesHandler := newRulesHandler(svr, rd)
 	registerFunc(clusterRouter, "/config/rules", rulesHandler.GetAllRules, setMethods("GET"))
 	registerFunc(clusterRouter, "/config/rules", rulesHandler.SetAllRules, setMethods("POST"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/config/rules/batch", rulesHandler.BatchRules, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/config/rules/simulate", rulesHandler.SimulateRule, setMethods("POST"))
+	registerFunc(clusterRouter, "/config/rules/templates", rulesHandler.GetRuleTemplates, setMethods("GET"))
+	registerFunc(clusterRouter, "/config/rules/apply-template", rulesHandler.ApplyRuleTemplate, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/config/rules/keyspace/{keyspace-id}", rulesHandler.GetKeyspaceRules, setMethods("GET"))
+	registerFunc(clusterRouter, "/config/rules/keyspace/{keyspace-id}", rulesHandler.SetKeyspaceRules, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/config/rules/keyspace/{keyspace-id}", rulesHandler.DeleteKeyspaceRules, setMethods("DELETE"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/config/rules/group/{group}", rulesHandler.GetRuleByGroup, setMethods("GET"))
 	registerFunc(clusterRouter, "/config/rules/region/{region}", rulesHandler.GetRulesByRegion, setMethods("GET"))
 	registerFunc(clusterRouter, "/config/rules/key/{key}", rulesHandler.GetRulesByKey, setMethods("GET"))
@@ -226,19 +261,30 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 
 	storeHandler := newStoreHandler(handler, rd)
 	registerFunc(clusterRouter, "/store/{id}", storeHandler.GetStore, setMethods("GET"))
-	registerFunc(clusterRouter, "/store/{id}", storeHandler.DeleteStore, setMethods("DELETE"), setAuditBackend(localLog))
-	registerFunc(clusterRouter, "/store/{id}/state", storeHandler.SetStoreState, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/store/{id}", storeHandler.DeleteStore, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/store/{id}/state", storeHandler.SetStoreState, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 	registerFunc(clusterRouter, "/store/{id}/label", storeHandler.SetStoreLabel, setMethods("POST"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/store/{id}/weight", storeHandler.SetStoreWeight, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/store/{id}/warm-up", storeHandler.SetStoreWarmUp, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/store/{id}/bandwidth", storeHandler.SetStoreBandwidth, setMethods("POST"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/store/{id}/limit", storeHandler.SetStoreLimit, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/stores/{id}/drain", storeHandler.DrainStore, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/stores/{id}/drain", storeHandler.GetStoreDrainStatus, setMethods("GET"))
+	registerFunc(clusterRouter, "/stores/{id}/drain", storeHandler.StopDrainStore, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/stores/{id}/health", storeHandler.GetStoreHealth, setMethods("GET"))
+	registerFunc(clusterRouter, "/stores/{id}/amplification", storeHandler.GetStoreAmplification, setMethods("GET"))
+	registerFunc(clusterRouter, "/stores/{id}/io", storeHandler.GetStoreIO, setMethods("GET"))
+	registerFunc(clusterRouter, "/stores/{id}/labels", storeHandler.PatchStoreLabels, setMethods("PATCH"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	storesHandler := newStoresHandler(handler, rd)
 	registerFunc(clusterRouter, "/stores", storesHandler.GetStores, setMethods("GET"))
-	registerFunc(clusterRouter, "/stores/remove-tombstone", storesHandler.RemoveTombStone, setMethods("DELETE"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/stores/remove-tombstone", storesHandler.RemoveTombStone, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/stores/tombstones", storesHandler.RemoveOldTombStones, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
 	registerFunc(clusterRouter, "/stores/limit", storesHandler.GetAllStoresLimit, setMethods("GET"))
 	registerFunc(clusterRouter, "/stores/limit", storesHandler.SetAllStoresLimit, setMethods("POST"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/stores/limit/scene", storesHandler.SetStoreLimitScene, setMethods("POST"), setAuditBackend(localLog))
 	registerFunc(clusterRouter, "/stores/limit/scene", storesHandler.GetStoreLimitScene, setMethods("GET"))
+	registerFunc(clusterRouter, "/stores/slow", storesHandler.GetSlowStores, setMethods("GET"))
 
 	labelsHandler := newLabelsHandler(svr, rd)
 	registerFunc(clusterRouter, "/labels", labelsHandler.GetLabels, setMethods("GET"))
@@ -260,7 +306,10 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 
 	regionsHandler := newRegionsHandler(svr, rd)
 	registerFunc(clusterRouter, "/regions/key", regionsHandler.ScanRegions, setMethods("GET"), setAuditBackend(prometheus))
+	registerFunc(clusterRouter, "/regions/range", regionsHandler.GetRegionsPage, setMethods("GET"), setAuditBackend(prometheus))
 	registerFunc(clusterRouter, "/regions/count", regionsHandler.GetRegionCount, setMethods("GET"), setAuditBackend(prometheus))
+	registerFunc(clusterRouter, "/regions/size-estimation", regionsHandler.GetRegionsSizeEstimation, setMethods("GET"), setAuditBackend(prometheus))
+	registerFunc(clusterRouter, "/regions/size-histogram", regionsHandler.GetRegionSizeHistogram, setMethods("GET"), setAuditBackend(prometheus))
 	registerFunc(clusterRouter, "/regions/store/{id}", regionsHandler.GetStoreRegions, setMethods("GET"))
 	registerFunc(clusterRouter, "/regions/writeflow", regionsHandler.GetTopWriteFlowRegions, setMethods("GET"))
 	registerFunc(clusterRouter, "/regions/readflow", regionsHandler.GetTopReadFlowRegions, setMethods("GET"))
@@ -278,6 +327,10 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	registerFunc(clusterRouter, "/regions/check/hist-size", regionsHandler.GetSizeHistogram, setMethods("GET"))
 	registerFunc(clusterRouter, "/regions/check/hist-keys", regionsHandler.GetKeysHistogram, setMethods("GET"))
 	registerFunc(clusterRouter, "/regions/sibling/{id}", regionsHandler.GetRegionSiblings, setMethods("GET"))
+	registerFunc(clusterRouter, "/regions/{id}/genealogy", regionsHandler.GetRegionGenealogy, setMethods("GET"))
+	registerFunc(clusterRouter, "/regions/{id}/traffic", regionsHandler.GetRegionTraffic, setMethods("GET"))
+	registerFunc(clusterRouter, "/regions/{id}/labels", regionsHandler.GetRegionStoreLabels, setMethods("GET"))
+	registerFunc(clusterRouter, "/regions/{id}/rule-trace", regionsHandler.GetRegionRuleTrace, setMethods("GET"))
 	registerFunc(clusterRouter, "/regions/accelerate-schedule", regionsHandler.AccelerateRegionsScheduleInRange, setMethods("POST"), setAuditBackend(localLog, prometheus))
 	registerFunc(clusterRouter, "/regions/scatter", regionsHandler.ScatterRegions, setMethods("POST"), setAuditBackend(localLog, prometheus))
 	registerFunc(clusterRouter, "/regions/split", regionsHandler.SplitRegions, setMethods("POST"), setAuditBackend(localLog))
@@ -285,52 +338,60 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	registerFunc(clusterRouter, "/regions/replicated", regionsHandler.CheckRegionsReplicated, setMethods("GET"), setQueries("startKey", "{startKey}", "endKey", "{endKey}"))
 
 	registerFunc(apiRouter, "/version", newVersionHandler(rd).GetVersion, setMethods("GET"))
+	registerFunc(apiRouter, "/openapi.json", newOpenAPIHandler().GetSpec, setMethods("GET"))
 	registerFunc(apiRouter, "/status", newStatusHandler(svr, rd).GetPDStatus, setMethods("GET"))
 
 	memberHandler := newMemberHandler(svr, rd)
 	registerFunc(apiRouter, "/members", memberHandler.GetMembers, setMethods("GET"))
-	registerFunc(apiRouter, "/members/name/{name}", memberHandler.DeleteMemberByName, setMethods("DELETE"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/members/id/{id}", memberHandler.DeleteMemberByID, setMethods("DELETE"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/members/name/{name}", memberHandler.SetMemberPropertyByName, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/members/name/{name}", memberHandler.DeleteMemberByName, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/members/id/{id}", memberHandler.DeleteMemberByID, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/members/name/{name}", memberHandler.SetMemberPropertyByName, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	leaderHandler := newLeaderHandler(svr, rd)
 	registerFunc(apiRouter, "/leader", leaderHandler.GetLeader, setMethods("GET"))
-	registerFunc(apiRouter, "/leader/resign", leaderHandler.ResignLeader, setMethods("POST"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/leader/transfer/{next_leader}", leaderHandler.TransferLeader, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/leader/resign", leaderHandler.ResignLeader, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/leader/transfer/{next_leader}", leaderHandler.TransferLeader, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	statsHandler := newStatsHandler(svr, rd)
 	registerFunc(clusterRouter, "/stats/region", statsHandler.GetRegionStatus, setMethods("GET"))
+	registerFunc(clusterRouter, "/stats/cross-dc", statsHandler.GetCrossDCTraffic, setMethods("GET"))
+	registerFunc(clusterRouter, "/stats/snapshots", statsHandler.GetSnapshotStats, setMethods("GET"))
 
 	trendHandler := newTrendHandler(svr, rd)
 	registerFunc(apiRouter, "/trend", trendHandler.GetTrend, setMethods("GET"), setAuditBackend(prometheus))
 
 	adminHandler := newAdminHandler(svr, rd)
-	registerFunc(clusterRouter, "/admin/cache/region/{id}", adminHandler.DeleteRegionCache, setMethods("DELETE"), setAuditBackend(localLog))
-	registerFunc(clusterRouter, "/admin/reset-ts", adminHandler.ResetTS, setMethods("POST"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/admin/persist-file/{file_name}", adminHandler.SavePersistFile, setMethods("POST"), setAuditBackend(localLog))
-	registerFunc(clusterRouter, "/admin/replication_mode/wait-async", adminHandler.UpdateWaitAsyncTime, setMethods("POST"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/admin/audit-middleware", adminHandler.SwitchAuditMiddleware, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/admin/cache/region/{id}", adminHandler.DeleteRegionCache, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/admin/reset-ts", adminHandler.ResetTS, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/admin/persist-file/{file_name}", adminHandler.SavePersistFile, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/admin/replication_mode/wait-async", adminHandler.UpdateWaitAsyncTime, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/admin/audit-middleware", adminHandler.SwitchAuditMiddleware, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	logHandler := newLogHandler(svr, rd)
-	registerFunc(apiRouter, "/admin/log", logHandler.SetLogLevel, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/admin/log", logHandler.SetLogLevel, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 	replicationModeHandler := newReplicationModeHandler(svr, rd)
 	registerFunc(clusterRouter, "/replication_mode/status", replicationModeHandler.GetReplicationModeStatus)
+	registerFunc(clusterRouter, "/replication_mode/lag", replicationModeHandler.GetReplicationLag, setMethods("GET"))
+	registerFunc(clusterRouter, "/replication_mode/state", replicationModeHandler.SetDRState, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	pluginHandler := newPluginHandler(handler, rd)
-	registerFunc(apiRouter, "/plugin", pluginHandler.LoadPlugin, setMethods("POST"))
-	registerFunc(apiRouter, "/plugin", pluginHandler.UnloadPlugin, setMethods("DELETE"))
+	registerFunc(apiRouter, "/plugin", pluginHandler.LoadPlugin, setMethods("POST"), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/plugin", pluginHandler.UnloadPlugin, setMethods("DELETE"), setRole(rbac.Admin))
 
 	healthHandler := newHealthHandler(svr, rd)
 	registerFunc(apiRouter, "/health", healthHandler.GetHealthStatus, setMethods("GET"))
 	registerFunc(apiRouter, "/ping", healthHandler.Ping, setMethods("GET"))
 
+	diagnosticHandler := newDiagnosticHandler(svr, rd)
+	registerFunc(apiRouter, "/diagnostic/store-config/breakers", diagnosticHandler.GetStoreConfigBreakers, setMethods("GET"))
+
 	// metric query use to query metric data, the protocol is compatible with prometheus.
 	registerFunc(apiRouter, "/metric/query", newQueryMetric(svr).QueryMetric, setMethods("GET", "POST"))
 	registerFunc(apiRouter, "/metric/query_range", newQueryMetric(svr).QueryMetric, setMethods("GET", "POST"))
 
 	// tso API
 	tsoHandler := newTSOHandler(svr, rd)
-	registerFunc(apiRouter, "/tso/allocator/transfer/{name}", tsoHandler.TransferLocalTSOAllocator, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/tso/allocator/transfer/{name}", tsoHandler.TransferLocalTSOAllocator, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	pprofHandler := newPprofHandler(svr, rd)
 	// profile API
@@ -348,21 +409,53 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	// service GC safepoint API
 	serviceGCSafepointHandler := newServiceGCSafepointHandler(svr, rd)
 	registerFunc(apiRouter, "/gc/safepoint", serviceGCSafepointHandler.GetGCSafePoint, setMethods("GET"), setAuditBackend(localLog))
-	registerFunc(apiRouter, "/gc/safepoint/{service_id}", serviceGCSafepointHandler.DeleteGCSafePoint, setMethods("DELETE"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/gc/safepoint/{service_id}", serviceGCSafepointHandler.DeleteGCSafePoint, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
 
 	// min resolved ts API
 	minResolvedTSHandler := newMinResolvedTSHandler(svr, rd)
 	registerFunc(apiRouter, "/min-resolved-ts", minResolvedTSHandler.GetMinResolvedTS, setMethods("GET"))
 
+	// cluster events API
+	eventsHandler := newEventsHandler(svr, rd)
+	registerFunc(clusterRouter, "/events", eventsHandler.Subscribe, setMethods("GET"))
+
+	// region quota API
+	quotaHandler := newQuotaHandler(svr, rd)
+	registerFunc(clusterRouter, "/quotas", quotaHandler.GetQuotas, setMethods("GET"))
+	registerFunc(clusterRouter, "/quotas", quotaHandler.SetQuota, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(clusterRouter, "/quotas/violations", quotaHandler.GetViolations, setMethods("GET"))
+	registerFunc(clusterRouter, "/quotas/{id}", quotaHandler.GetQuota, setMethods("GET"))
+	registerFunc(clusterRouter, "/quotas/{id}", quotaHandler.DeleteQuota, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+
+	// keyspace API
+	keyspaceHandler := newKeyspaceHandler(svr, rd)
+	registerFunc(apiRouter, "/keyspaces", keyspaceHandler.GetKeyspaces, setMethods("GET"))
+	registerFunc(apiRouter, "/keyspaces", keyspaceHandler.CreateKeyspace, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/keyspaces/merge", keyspaceHandler.MergeKeyspaces, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/keyspaces/{id}", keyspaceHandler.GetKeyspace, setMethods("GET"))
+	registerFunc(apiRouter, "/keyspaces/{id}", keyspaceHandler.UpdateKeyspaceConfig, setMethods("PATCH"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/keyspaces/{id}", keyspaceHandler.DeleteKeyspace, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/keyspaces/{id}/split", keyspaceHandler.SplitKeyspace, setMethods("POST"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/keyspaces/{id}/ttl", keyspaceHandler.SetKeyspaceTTL, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(apiRouter, "/keyspaces/{id}/gc-safe-point", keyspaceHandler.GetKeyspaceGCSafePoint, setMethods("GET"))
+	registerFunc(clusterRouter, "/keyspaces/{id}/affinity", keyspaceHandler.GetKeyspaceAffinity, setMethods("GET"))
+	registerFunc(clusterRouter, "/keyspaces/{id}/affinity", keyspaceHandler.SetKeyspaceAffinity, setMethods("POST"), setAuditBackend(localLog))
+	registerFunc(clusterRouter, "/keyspaces/{id}/stats", keyspaceHandler.GetKeyspaceStats, setMethods("GET"))
+
 	// unsafe admin operation API
 	unsafeOperationHandler := newUnsafeOperationHandler(svr, rd)
 	registerFunc(clusterRouter, "/admin/unsafe/remove-failed-stores",
-		unsafeOperationHandler.RemoveFailedStores, setMethods("POST"))
+		unsafeOperationHandler.RemoveFailedStores, setMethods("POST"), setRole(rbac.Admin))
 	registerFunc(clusterRouter, "/admin/unsafe/remove-failed-stores/show",
 		unsafeOperationHandler.GetFailedStoresRemovalStatus, setMethods("GET"))
 	registerFunc(clusterRouter, "/admin/unsafe/remove-failed-stores/history",
 		unsafeOperationHandler.GetFailedStoresRemovalHistory, setMethods("GET"))
 
+	authHandler := newAuthHandler(svr, rd)
+	registerFunc(apiRouter, "/auth/token", authHandler.IssueToken, setMethods("POST"), setPublic())
+	registerFunc(apiRouter, "/auth/users/{name}", authHandler.SetUser, setMethods("PUT"), setAuditBackend(localLog), setRole(rbac.Admin))
+	registerFunc(apiRouter, "/auth/users/{name}", authHandler.DeleteUser, setMethods("DELETE"), setAuditBackend(localLog), setRole(rbac.Admin))
+
 	// API to set or unset failpoints
 	failpoint.Inject("enableFailpointAPI", func() {
 		// this function will be named to "func2". It may be used in test
@@ -67,6 +67,7 @@ func (h *ruleHandler) GetAllRules(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {string} string "Update rules successfully."
 // @Failure 400 {string} string "The input is invalid."
 // @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 422 {array} placement.RuleConflict "The rules conflict."
 // @Failure 500 {string} string "PD server failed to proceed the request."
 // @Router /config/rules [get]
 func (h *ruleHandler) SetAllRules(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +80,10 @@ func (h *ruleHandler) SetAllRules(w http.ResponseWriter, r *http.Request) {
 	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rules); err != nil {
 		return
 	}
+	if conflicts := cluster.GetRuleManager().DetectConflicts(rules); len(conflicts) > 0 {
+		h.rd.JSON(w, http.StatusUnprocessableEntity, conflicts)
+		return
+	}
 	for _, v := range rules {
 		if err := h.syncReplicateConfigWithDefaultRule(v); err != nil {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
@@ -234,6 +239,213 @@ func (h *ruleHandler) SetRule(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "Update rule successfully.")
 }
 
+// @Tags rule
+// @Summary Simulate a rule without applying it, and report the regions which need to move.
+// @Accept json
+// @Param rule body placement.Rule true "Parameters of rule"
+// @Produce json
+// @Success 200 {object} cluster.RuleSimulationReport
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/simulate [post]
+func (h *ruleHandler) SimulateRule(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	var rule placement.Rule
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rule); err != nil {
+		return
+	}
+	cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType)
+	report, err := cluster.SimulateRule(&rule)
+	if err != nil {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrBuildRuleList.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, report)
+}
+
+// @Tags rule
+// @Summary List the rule templates for the common cluster topologies.
+// @Produce json
+// @Success 200 {object} map[string]placement.RuleTemplate
+// @Router /config/rules/templates [get]
+func (h *ruleHandler) GetRuleTemplates(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, placement.RuleTemplates())
+}
+
+// @Tags rule
+// @Summary Set the rules of a rule template with the label values of its parameters.
+// @Param name query string true "The name of the template"
+// @Param values body object true "The label values of the template parameters"
+// @Produce json
+// @Success 200 {array} placement.Rule
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The template does not exist."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 422 {array} placement.RuleConflict "The rules conflict."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/apply-template [post]
+func (h *ruleHandler) ApplyRuleTemplate(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	template, ok := placement.RuleTemplates()[r.URL.Query().Get("name")]
+	if !ok {
+		h.rd.JSON(w, http.StatusNotFound, "template not found")
+		return
+	}
+	var values map[string]string
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &values); err != nil {
+		return
+	}
+	rules, err := template.Instantiate(values)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if conflicts := cluster.GetRuleManager().DetectConflicts(rules); len(conflicts) > 0 {
+		h.rd.JSON(w, http.StatusUnprocessableEntity, conflicts)
+		return
+	}
+	for _, v := range rules {
+		if err := h.syncReplicateConfigWithDefaultRule(v); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
+		SetRules(rules); err != nil {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rules)
+}
+
+func parseKeyspaceID(r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["keyspace-id"], 10, 32)
+	if err != nil || id == 0 || id > placement.MaxKeyspaceID {
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// @Tags rule
+// @Summary List all rules of a keyspace.
+// @Param keyspace-id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {array} placement.Rule
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Router /config/rules/keyspace/{keyspace-id} [get]
+func (h *ruleHandler) GetKeyspaceRules(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	keyspaceID, ok := parseKeyspaceID(r)
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid keyspace id")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetRuleManager().GetRulesByKeyspace(keyspaceID))
+}
+
+// @Tags rule
+// @Summary Set the rules of a keyspace, which take precedence over the cluster-wide rules for the data of the keyspace.
+// @Param keyspace-id path integer true "Keyspace Id"
+// @Param rules body []placement.Rule true "Parameters of rules"
+// @Produce json
+// @Success 200 {string} string "Update rules successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 422 {array} placement.RuleConflict "The rules conflict."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/keyspace/{keyspace-id} [post]
+func (h *ruleHandler) SetKeyspaceRules(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	keyspaceID, ok := parseKeyspaceID(r)
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid keyspace id")
+		return
+	}
+	var rules []*placement.Rule
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rules); err != nil {
+		return
+	}
+	for _, rule := range rules {
+		if rule.KeyspaceID != 0 && rule.KeyspaceID != keyspaceID {
+			h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("rule keyspace %d does not match keyspace %d", rule.KeyspaceID, keyspaceID))
+			return
+		}
+		rule.KeyspaceID = keyspaceID
+	}
+	if conflicts := cluster.GetRuleManager().DetectConflicts(rules); len(conflicts) > 0 {
+		h.rd.JSON(w, http.StatusUnprocessableEntity, conflicts)
+		return
+	}
+	if err := cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
+		SetRules(rules); err != nil {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrBuildRuleList.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	for _, rule := range rules {
+		cluster.AddSuspectKeyRange(rule.StartKey, rule.EndKey)
+	}
+	h.rd.JSON(w, http.StatusOK, "Update rules successfully.")
+}
+
+// @Tags rule
+// @Summary Delete all rules of a keyspace.
+// @Param keyspace-id path integer true "Keyspace Id"
+// @Produce json
+// @Success 200 {string} string "Delete rules successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/keyspace/{keyspace-id} [delete]
+func (h *ruleHandler) DeleteKeyspaceRules(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	keyspaceID, ok := parseKeyspaceID(r)
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid keyspace id")
+		return
+	}
+	if err := cluster.GetRuleManager().DeleteKeyspaceRules(keyspaceID); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	start, end := placement.KeyspaceKeyRange(keyspaceID)
+	cluster.AddSuspectKeyRange(start, end)
+	h.rd.JSON(w, http.StatusOK, "Delete rules successfully.")
+}
+
 // sync replicate config with default-rule
 func (h *ruleHandler) syncReplicateConfigWithDefaultRule(rule *placement.Rule) error {
 	// sync default rule with replicate config
@@ -474,7 +686,7 @@ func (h *ruleHandler) GetPlacementRuleByGroup(w http.ResponseWriter, r *http.Req
 // @Success 200 {string} string "Delete group and rules successfully."
 // @Failure 400 {string} string "Bad request."
 // @Failure 412 {string} string "Placement rules feature is disabled."
-// @Router /config/placement-rule [delete]
+// @Router /config/placement-rule/{group} [delete]
 func (h *ruleHandler) DeletePlacementRuleByGroup(w http.ResponseWriter, r *http.Request) {
 	cluster := getCluster(r)
 	if !cluster.GetOpts().IsPlacementRulesEnabled() {
@@ -497,6 +709,8 @@ func (h *ruleHandler) DeletePlacementRuleByGroup(w http.ResponseWriter, r *http.
 
 // @Tags rule
 // @Summary Update group and all rules belong to it.
+// @Param group path string true "The name of group"
+// @Param group body placement.GroupBundle true "Parameters of group and rules"
 // @Produce json
 // @Success 200 {string} string "Update group and rules successfully."
 // @Failure 400 {string} string "The input is invalid."
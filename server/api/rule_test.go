@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/schedule/placement"
 )
@@ -175,6 +176,97 @@ func (s *testRuleSuite) TestSet(c *C) {
 	}
 }
 
+func (s *testRuleSuite) TestSimulate(c *C) {
+	rule := placement.Rule{GroupID: "a", ID: "simulate", Role: "learner", Count: 1}
+	data, err := json.Marshal(rule)
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/simulate", data, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusOK)
+		report := make(map[string]interface{})
+		c.Assert(json.Unmarshal(res, &report), IsNil)
+		for _, field := range []string{"affected_region_count", "required_operator_count", "estimated_data_movement_bytes"} {
+			c.Assert(report[field], NotNil)
+		}
+	})
+	c.Assert(err, IsNil)
+	// The rule is not applied.
+	var resp placement.Rule
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rule/a/simulate", &resp), NotNil)
+
+	rule.Count = -1
+	data, err = json.Marshal(rule)
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/simulate", data, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusBadRequest)
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *testRuleSuite) TestTemplates(c *C) {
+	var templates map[string]*placement.RuleTemplate
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/templates", &templates), IsNil)
+	for _, name := range []string{"single-dc", "two-dc-with-witness", "three-az", "five-az"} {
+		c.Assert(templates[name], NotNil)
+		c.Assert(templates[name].Rules, Not(HasLen), 0)
+	}
+
+	mustPutStore(c, s.svr, 100, metapb.StoreState_Up, metapb.NodeState_Serving, []*metapb.StoreLabel{{Key: "zone", Value: "a"}, {Key: "host", Value: "h100"}})
+	values := []byte(`{"az1": "a", "az2": "b", "az3": "c"}`)
+	err := postJSON(testDialClient, s.urlPrefix+"/rules/apply-template?name=three-az", values, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusOK)
+		var rules []*placement.Rule
+		c.Assert(json.Unmarshal(res, &rules), IsNil)
+		c.Assert(rules, HasLen, 1)
+	})
+	c.Assert(err, IsNil)
+	var rule placement.Rule
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rule/pd/default", &rule), IsNil)
+	c.Assert(rule.LabelConstraints, DeepEquals, []placement.LabelConstraint{{Key: "zone", Op: "in", Values: []string{"a", "b", "c"}}})
+	c.Assert(rule.IsolationLevel, Equals, "zone")
+
+	// The template does not exist.
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/apply-template?name=unknown", values, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusNotFound)
+	})
+	c.Assert(err, NotNil)
+	// The value of a parameter is missing.
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/apply-template?name=five-az", values, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusBadRequest)
+		c.Assert(string(res), Matches, ".*az4.*")
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *testRuleSuite) TestKeyspaceRules(c *C) {
+	rules := []*placement.Rule{{GroupID: "tenant", ID: "a", Role: "voter", Count: 3}}
+	data, err := json.Marshal(rules)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/keyspace/1", data), IsNil)
+	var resp []*placement.Rule
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/keyspace/1", &resp), IsNil)
+	c.Assert(resp, HasLen, 1)
+	c.Assert(resp[0].KeyspaceID, Equals, uint32(1))
+	start, end := placement.KeyspaceKeyRange(1)
+	c.Assert(resp[0].StartKeyHex, Equals, hex.EncodeToString(start))
+	c.Assert(resp[0].EndKeyHex, Equals, hex.EncodeToString(end))
+
+	// The keyspace ID is invalid or does not match the rule.
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/keyspace/0", data), NotNil)
+	rules[0].KeyspaceID = 2
+	data, err = json.Marshal(rules)
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/keyspace/1", data, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusBadRequest)
+	})
+	c.Assert(err, NotNil)
+
+	statusCode, err := doDelete(testDialClient, s.urlPrefix+"/rules/keyspace/1")
+	c.Assert(err, IsNil)
+	c.Assert(statusCode, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/keyspace/1", &resp), IsNil)
+	c.Assert(resp, HasLen, 0)
+}
+
 func (s *testRuleSuite) TestGet(c *C) {
 	rule := placement.Rule{GroupID: "a", ID: "20", StartKeyHex: "1111", EndKeyHex: "3333", Role: "voter", Count: 1}
 	data, err := json.Marshal(rule)
@@ -336,6 +428,28 @@ func (s *testRuleSuite) TestSetAll(c *C) {
 	}
 }
 
+func (s *testRuleSuite) TestSetAllConflict(c *C) {
+	rules := []*placement.Rule{
+		{GroupID: "conflict", ID: "1", Role: "follower", Count: 1,
+			LabelConstraints: []placement.LabelConstraint{{Key: "rack", Op: "in", Values: []string{"A"}}}},
+		{GroupID: "conflict", ID: "2", Role: "follower", Count: 1,
+			LabelConstraints: []placement.LabelConstraint{{Key: "rack", Op: "in", Values: []string{"B"}}}},
+	}
+	data, err := json.Marshal(rules)
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules", data, func(res []byte, code int) {
+		c.Assert(code, Equals, http.StatusUnprocessableEntity)
+		var conflicts []placement.RuleConflict
+		c.Assert(json.Unmarshal(res, &conflicts), IsNil)
+		c.Assert(conflicts, HasLen, 1)
+		c.Assert(conflicts[0].Rules, DeepEquals, []string{"conflict/1", "conflict/2"})
+	})
+	c.Assert(err, NotNil)
+	var resp []*placement.Rule
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/group/conflict", &resp), IsNil)
+	c.Assert(resp, HasLen, 0)
+}
+
 func (s *testRuleSuite) TestGetAllByGroup(c *C) {
 	rule := placement.Rule{GroupID: "c", ID: "20", StartKeyHex: "1111", EndKeyHex: "3333", Role: "voter", Count: 1}
 	data, err := json.Marshal(rule)
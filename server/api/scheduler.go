@@ -18,12 +18,14 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedulers"
 	"github.com/unrolled/render"
 )
@@ -136,6 +138,42 @@ func (h *schedulerHandler) CreateScheduler(w http.ResponseWriter, r *http.Reques
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	case schedulers.LabelBalanceLeaderName:
+		labelKey, ok := input["balance_label_key"].(string)
+		if !ok {
+			h.r.JSON(w, http.StatusBadRequest, "missing balance label key")
+			return
+		}
+		tolerance := schedulers.DefaultLabelBalanceTolerance
+		if t, ok := input["tolerance"].(float64); ok {
+			tolerance = t
+		}
+		if err := h.AddLabelBalanceLeaderScheduler(labelKey, tolerance); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	case schedulers.TieredBalanceName:
+		leaderTolerance := schedulers.DefaultTieredLeaderTolerance
+		if t, ok := input["leader_tolerance"].(float64); ok {
+			leaderTolerance = t
+		}
+		peerTolerance := schedulers.DefaultTieredPeerTolerance
+		if t, ok := input["peer_tolerance"].(float64); ok {
+			peerTolerance = t
+		}
+		if err := h.AddTieredBalanceScheduler(leaderTolerance, peerTolerance); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	case schedulers.ZoneAwareLeaderName:
+		zoneLabelKey := schedulers.DefaultZoneLabelKey
+		if key, ok := input["zone_label_key"].(string); ok {
+			zoneLabelKey = key
+		}
+		if err := h.AddZoneAwareLeaderScheduler(zoneLabelKey); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	case schedulers.ScatterRangeName:
 		var args []string
 
@@ -306,6 +344,91 @@ func (h *schedulerHandler) PauseOrResumeScheduler(w http.ResponseWriter, r *http
 	h.r.JSON(w, http.StatusOK, "Pause or resume the scheduler successfully.")
 }
 
+// @Tags scheduler
+// @Summary Pause a scheduler for a duration, it is resumed automatically after the duration.
+// @Param name path string true "The name of the scheduler."
+// @Param duration query string true "The pause duration, e.g. 300s."
+// @Produce json
+// @Success 200 {string} string "Pause the scheduler successfully."
+// @Failure 400 {string} string "Bad format request."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/pause [post]
+func (h *schedulerHandler) PauseScheduler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	durationStr := r.URL.Query().Get("duration")
+	if len(durationStr) == 0 {
+		h.r.JSON(w, http.StatusBadRequest, "missing pause duration")
+		return
+	}
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration <= 0 {
+		h.r.JSON(w, http.StatusBadRequest, "invalid pause duration")
+		return
+	}
+	if err := h.Handler.PauseScheduler(name, duration); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Pause the scheduler successfully.")
+}
+
+// @Tags scheduler
+// @Summary Resume a paused scheduler.
+// @Param name path string true "The name of the scheduler."
+// @Produce json
+// @Success 200 {string} string "Resume the scheduler successfully."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/resume [post]
+func (h *schedulerHandler) ResumeScheduler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := h.Handler.ResumeScheduler(name); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Resume the scheduler successfully.")
+}
+
+// @Tags scheduler
+// @Summary Restrict a scheduler to schedule only inside a daily time window.
+// @Accept json
+// @Param name path string true "The name of the scheduler."
+// @Param body body object true "json params, e.g. {\"start\": \"12:00:00\", \"end\": \"16:00:00\", \"location\": \"Asia/Shanghai\"}"
+// @Produce json
+// @Success 200 {string} string "Set the time window successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/time-window [post]
+func (h *schedulerHandler) SetSchedulerTimeWindow(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	var tw schedule.TimeWindow
+	if err := apiutil.ReadJSON(r.Body, &tw); err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Handler.SetSchedulerTimeWindow(name, tw); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Set the time window successfully.")
+}
+
+// @Tags scheduler
+// @Summary Get the time window of a scheduler.
+// @Param name path string true "The name of the scheduler."
+// @Produce json
+// @Success 200 {object} schedule.TimeWindow
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/time-window [get]
+func (h *schedulerHandler) GetSchedulerTimeWindow(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	tw, err := h.Handler.GetSchedulerTimeWindow(name)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, tw)
+}
+
 type schedulerConfigHandler struct {
 	svr *server.Server
 	rd  *render.Render
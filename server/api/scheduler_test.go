@@ -213,6 +213,34 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 			},
 		},
 		{name: "balance-region-scheduler"},
+		{
+			name: "label-balance-leader-scheduler",
+			args: []arg{{"balance_label_key", "zone"}, {"tolerance", 0.1}},
+		},
+		{
+			name: "tiered-balance-scheduler",
+			args: []arg{{"leader_tolerance", 0.2}, {"peer_tolerance", 0.1}},
+		},
+		{
+			name: "zone-aware-leader-scheduler",
+			args: []arg{{"zone_label_key", "zone"}},
+			extraTestFunc: func(name string, c *C) {
+				resp := make(map[string]interface{})
+				listURL := fmt.Sprintf("%s%s%s/%s/list", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["zone-label-key"], Equals, "zone")
+				hintsURL := fmt.Sprintf("%s%s%s/%s/hints", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				body := []byte(`[{"start-key":"a","end-key":"b","zone-requests":{"z1":10,"z2":20}}]`)
+				c.Assert(postJSON(testDialClient, hintsURL, body), IsNil)
+				var hints []map[string]interface{}
+				c.Assert(readJSON(testDialClient, hintsURL, &hints), IsNil)
+				c.Assert(hints, HasLen, 1)
+				c.Assert(hints[0]["start-key"], Equals, "a")
+				c.Assert(hints[0]["zone-requests"], DeepEquals, map[string]interface{}{"z1": 10.0, "z2": 20.0})
+				body = []byte(`[{"start-key":"b","end-key":"a","zone-requests":{"z1":10}}]`)
+				c.Assert(postJSON(testDialClient, hintsURL, body), NotNil)
+			},
+		},
 		{name: "shuffle-leader-scheduler"},
 		{name: "shuffle-region-scheduler"},
 		{
@@ -276,6 +304,29 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 				c.Assert(resp["start-key"], Equals, "a_00")
 				c.Assert(resp["end-key"], Equals, "a_99")
 				c.Assert(resp["range-name"], Equals, "test")
+
+				// Test adding and removing ranges.
+				rangesURL := fmt.Sprintf("%s%s%s/%s/ranges", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				c.Assert(postJSON(testDialClient, rangesURL, []byte(`{"start-key":"a_50","end-key":"b_00"}`)), NotNil)
+				c.Assert(postJSON(testDialClient, rangesURL, []byte(`{"start-key":"b_00","end-key":"b_99"}`)), IsNil)
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["ranges"], HasLen, 2)
+				progress := make([]map[string]interface{}, 0)
+				progressURL := fmt.Sprintf("%s%s%s/%s/progress", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				c.Assert(readJSON(testDialClient, progressURL, &progress), IsNil)
+				c.Assert(progress, HasLen, 2)
+				statusCode, err := doDelete(testDialClient, rangesURL+"?start-key=a_00")
+				c.Assert(err, IsNil)
+				c.Assert(statusCode, Equals, 200)
+				statusCode, err = doDelete(testDialClient, rangesURL+"?start-key=b_00")
+				c.Assert(err, IsNil)
+				c.Assert(statusCode, Equals, 400)
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["start-key"], Equals, "b_00")
+				c.Assert(resp["end-key"], Equals, "b_99")
+				c.Assert(resp["ranges"], HasLen, 1)
 			},
 		},
 		{
@@ -514,6 +565,30 @@ func (s *testScheduleSuite) testPauseOrResume(name, createdName string, body []b
 	c.Assert(err, IsNil)
 	c.Assert(isPaused, IsFalse)
 
+	// test pause with duration and resume.
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/"+createdName+"/pause", nil), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/"+createdName+"/pause?duration=abc", nil), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/"+createdName+"/pause?duration=-1s", nil), NotNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/"+createdName+"/pause?duration=300s", nil), IsNil)
+	isPaused, err = handler.IsSchedulerPaused(createdName)
+	c.Assert(err, IsNil)
+	c.Assert(isPaused, IsTrue)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/"+createdName+"/resume", nil), IsNil)
+	isPaused, err = handler.IsSchedulerPaused(createdName)
+	c.Assert(err, IsNil)
+	c.Assert(isPaused, IsFalse)
+
+	// test the time window.
+	timeWindowURL := s.urlPrefix + "/" + createdName + "/time-window"
+	c.Assert(postJSON(testDialClient, timeWindowURL, []byte(`{"start":"12:00","end":"16:00:00"}`)), NotNil)
+	c.Assert(postJSON(testDialClient, timeWindowURL, []byte(`{"start":"12:00:00","end":"16:00:00","location":"UTC"}`)), IsNil)
+	tw := make(map[string]interface{})
+	c.Assert(readJSON(testDialClient, timeWindowURL, &tw), IsNil)
+	c.Assert(tw["start"], Equals, "12:00:00")
+	c.Assert(tw["end"], Equals, "16:00:00")
+	c.Assert(tw["location"], Equals, "UTC")
+	c.Assert(postJSON(testDialClient, timeWindowURL, []byte(`{"start":"00:00:00","end":"00:00:00"}`)), IsNil)
+
 	if extraTest != nil {
 		extraTest(createdName, c)
 	}
@@ -46,3 +46,23 @@ func (h *statsHandler) GetRegionStatus(w http.ResponseWriter, r *http.Request) {
 	stats := rc.GetRegionStats([]byte(startKey), []byte(endKey))
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// @Tags stats
+// @Summary Get the snapshots in flight of the cluster.
+// @Produce json
+// @Success 200 {object} cluster.SnapshotStats
+// @Router /stats/snapshots [get]
+func (h *statsHandler) GetSnapshotStats(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, rc.GetSnapshotStats())
+}
+
+// @Tags stats
+// @Summary Get the current rate and the recent history of the data moved across and within the zones by the operators.
+// @Produce json
+// @Success 200 {object} schedule.CrossDCTraffic
+// @Router /stats/cross-dc [get]
+func (h *statsHandler) GetCrossDCTraffic(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, rc.GetOperatorController().GetCrossDCTraffic())
+}
@@ -22,7 +22,9 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/statistics"
 )
 
@@ -179,3 +181,21 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(stats, DeepEquals, stats23)
 }
+
+func (s *testStatsSuite) TestCrossDCTraffic(c *C) {
+	traffic := &schedule.CrossDCTraffic{}
+	err := readJSON(testDialClient, s.urlPrefix+"/stats/cross-dc", traffic)
+	c.Assert(err, IsNil)
+	c.Assert(traffic.CrossDCBytesRate, Equals, 0.0)
+	c.Assert(traffic.SameDCBytesRate, Equals, 0.0)
+	c.Assert(traffic.History, HasLen, 0)
+}
+
+func (s *testStatsSuite) TestSnapshotStats(c *C) {
+	stats := &cluster.SnapshotStats{}
+	err := readJSON(testDialClient, s.urlPrefix+"/stats/snapshots", stats)
+	c.Assert(err, IsNil)
+	c.Assert(stats.SnapshotSendingCount, Equals, 0)
+	c.Assert(stats.SnapshotSendingBytes, Equals, uint64(0))
+	c.Assert(stats.Stores, HasLen, len(s.svr.GetRaftCluster().GetMetaStores()))
+}
@@ -59,6 +59,8 @@ type StoreStatus struct {
 	SendingSnapCount   uint32             `json:"sending_snap_count,omitempty"`
 	ReceivingSnapCount uint32             `json:"receiving_snap_count,omitempty"`
 	IsBusy             bool               `json:"is_busy,omitempty"`
+	IsWarmingUp        bool               `json:"is_warming_up,omitempty"`
+	WarmUpRatio        float64            `json:"warm_up_ratio,omitempty"`
 	StartTS            *time.Time         `json:"start_ts,omitempty"`
 	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
 	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
@@ -68,6 +70,9 @@ type StoreStatus struct {
 type StoreInfo struct {
 	Store  *MetaStore   `json:"store"`
 	Status *StoreStatus `json:"status"`
+	// LabelWarnings are the warnings of the store labels not matching the
+	// location labels of the cluster.
+	LabelWarnings []string `json:"label_warnings,omitempty"`
 }
 
 const (
@@ -98,12 +103,17 @@ func newStoreInfo(opt *config.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 			ReceivingSnapCount: store.GetReceivingSnapCount(),
 			IsBusy:             store.IsBusy(),
 		},
+		LabelWarnings: store.GetLabelWarnings(),
 	}
 
 	if store.GetStoreStats() != nil {
 		startTS := store.GetStartTime()
 		s.Status.StartTS = &startTS
 	}
+	if store.IsWarmingUp() {
+		s.Status.IsWarmingUp = true
+		s.Status.WarmUpRatio = store.GetWarmUpRatio()
+	}
 	if lastHeartbeat := store.GetLastHeartbeatTS(); !lastHeartbeat.IsZero() {
 		s.Status.LastHeartbeatTS = &lastHeartbeat
 	}
@@ -298,6 +308,188 @@ func (h *storeHandler) SetStoreLabel(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "The store's label is updated.")
 }
 
+// @Tags store
+// @Summary Patch the store's labels without re-registering the store, the label with an empty value is deleted.
+// @Param id path integer true "Store Id"
+// @Param body body object true "Labels to be patched in json format, such as {"zone": "z1"}"
+// @Produce json
+// @Success 200 {object} StoreInfo
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /stores/{id}/labels [patch]
+func (h *storeHandler) PatchStoreLabels(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var patch map[string]string
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &patch); err != nil {
+		return
+	}
+
+	if err := rc.PatchStoreLabels(storeID, patch); err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, newStoreInfo(h.handler.GetScheduleConfig(), rc.GetStore(storeID)))
+}
+
+// @Tags store
+// @Summary Drain the store, all of its regions are moved away before it is removed.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The store is draining."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 410 {string} string "The store has been removed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /stores/{id}/drain [post]
+func (h *storeHandler) DrainStore(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := rc.DrainStore(storeID); err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, "The store is draining.")
+}
+
+// @Tags store
+// @Summary Get the progress of draining the store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} cluster.StoreDrainStatus
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /stores/{id}/drain [get]
+func (h *storeHandler) GetStoreDrainStatus(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	status, err := rc.GetStoreDrainStatus(storeID)
+	if err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
+// @Tags store
+// @Summary Get the health score of the store aggregated from multiple health signals.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} statistics.StoreHealthScore
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /stores/{id}/health [get]
+func (h *storeHandler) GetStoreHealth(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	health, err := rc.GetStoreHealthScore(storeID)
+	if err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, health)
+}
+
+// @Tags store
+// @Summary Get the rolling average of the disk and the client IO rates of the store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} statistics.StoreIO
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /stores/{id}/io [get]
+func (h *storeHandler) GetStoreIO(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	io, err := rc.GetStoreIO(storeID)
+	if err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, io)
+}
+
+// @Tags store
+// @Summary Get the current and the recent read and write amplification of the store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} statistics.StoreAmplificationInfo
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /stores/{id}/amplification [get]
+func (h *storeHandler) GetStoreAmplification(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	amplification, err := rc.GetStoreAmplification(storeID)
+	if err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, amplification)
+}
+
+// @Tags store
+// @Summary Stop draining the store, the regions moved away are not moved back.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The store stops draining."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /stores/{id}/drain [delete]
+func (h *storeHandler) StopDrainStore(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := rc.StopDrainStore(storeID); err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, "The store stops draining.")
+}
+
 // FIXME: details of input json body params
 // @Tags store
 // @Summary Set the store's leader/region weight.
@@ -351,6 +543,49 @@ func (h *storeHandler) SetStoreWeight(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "The store's label is updated.")
 }
 
+// FIXME: details of input json body params
+// @Tags store
+// @Summary Restart the warm-up of the store with the given duration, 0s stops the warm-up.
+// @Param id path integer true "Store Id"
+// @Param body body object true "json params"
+// @Produce json
+// @Success 200 {string} string "The store's warm-up is updated."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 410 {string} string "The store has been removed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/warm-up [post]
+func (h *storeHandler) SetStoreWarmUp(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	durationStr, ok := input["duration"].(string)
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "warm-up duration unset")
+		return
+	}
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration < 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "bad format warm-up duration")
+		return
+	}
+
+	if err := rc.SetStoreWarmUp(storeID, duration); err != nil {
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, "The store's warm-up is updated.")
+}
+
 // FIXME: details of input json body params
 // @Tags store
 // @Summary Set the store's limit.
@@ -424,6 +659,52 @@ func (h *storeHandler) SetStoreLimit(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "The store's label is updated.")
 }
 
+// FIXME: details of input json body params
+// @Tags store
+// @Summary Set the store's bandwidth in MB/s for sending and receiving snapshots, 0 means no limit.
+// @Param id path integer true "Store Id"
+// @Param body body object true "json params"
+// @Produce json
+// @Success 200 {string} string "The store's bandwidth is updated."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/bandwidth [post]
+func (h *storeHandler) SetStoreBandwidth(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	send, ok := input["send"].(float64)
+	if !ok || send < 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "bad format send bandwidth")
+		return
+	}
+	recv, ok := input["recv"].(float64)
+	if !ok || recv < 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "bad format recv bandwidth")
+		return
+	}
+
+	if err := rc.SetStoreBandwidth(storeID, send, recv); err != nil {
+		if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+			h.rd.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, "The store's bandwidth is updated.")
+}
+
 type storesHandler struct {
 	*server.Handler
 	rd *render.Render
@@ -452,6 +733,59 @@ func (h *storesHandler) RemoveTombStone(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, "Remove tombstone successfully.")
 }
 
+// @Tags store
+// @Summary Remove the tombstone stores which have not sent heartbeats for a period of time.
+// @Param older-than query string false "The period of time, such as 30d or 12h. Default to tombstone-store-retention-days."
+// @Param dry-run query bool false "Only return the stores to be removed. Default to tombstone-store-cleanup-dry-run."
+// @Produce json
+// @Success 200 {array} uint64
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /stores/tombstones [delete]
+func (h *storesHandler) RemoveOldTombStones(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	query := r.URL.Query()
+	olderThan := rc.GetOpts().GetTombstoneStoreRetention()
+	if olderThanStr := query.Get("older-than"); olderThanStr != "" {
+		var err error
+		if olderThan, err = parseDays(olderThanStr); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	dryRun := rc.GetOpts().IsTombstoneStoreCleanupDryRun()
+	if dryRunStr := query.Get("dry-run"); dryRunStr != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunStr); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	removed, err := rc.RemoveTombStoneRecordsOlderThan(olderThan, dryRun)
+	if err != nil {
+		apiutil.ErrorResp(h.rd, w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, removed)
+}
+
+// parseDays parses a duration which also supports the unit "d" of days, such as "30d".
+func parseDays(s string) (time.Duration, error) {
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.ParseUint(days, 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errors.Errorf("invalid duration %s", s)
+	}
+	return d, nil
+}
+
 // FIXME: details of input json body params
 // @Tags store
 // @Summary Set limit of all stores in the cluster.
@@ -612,7 +946,7 @@ func (h *storesHandler) GetStoreLimitScene(w http.ResponseWriter, r *http.Reques
 
 // @Tags store
 // @Summary Get stores in the cluster.
-// @Param state query array true "Specify accepted store states."
+// @Param state query []int false "Specify accepted store states."
 // @Produce json
 // @Success 200 {object} StoresInfo
 // @Failure 500 {string} string "PD server failed to proceed the request."
@@ -647,6 +981,42 @@ func (h *storesHandler) GetStores(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, StoresInfo)
 }
 
+// @Tags store
+// @Summary Get the stores whose p99 latency reported by the last heartbeat exceeds the thresholds.
+// @Param read-p99-ms query integer false "The threshold of the p99 read latency in milliseconds"
+// @Param write-p99-ms query integer false "The threshold of the p99 write latency in milliseconds"
+// @Produce json
+// @Success 200 {array} uint64
+// @Failure 400 {string} string "The input is invalid."
+// @Router /stores/slow [get]
+func (h *storesHandler) GetSlowStores(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	readThresholdMs, err := parseLatencyThreshold(r.URL.Query().Get("read-p99-ms"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid read-p99-ms: "+err.Error())
+		return
+	}
+	writeThresholdMs, err := parseLatencyThreshold(r.URL.Query().Get("write-p99-ms"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid write-p99-ms: "+err.Error())
+		return
+	}
+	if readThresholdMs == 0 && writeThresholdMs == 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "read-p99-ms or write-p99-ms is required")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rc.GetSlowStores(readThresholdMs*1000, writeThresholdMs*1000))
+}
+
+// parseLatencyThreshold parses a latency threshold in milliseconds, an empty
+// string means no threshold.
+func parseLatencyThreshold(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
 type storeStateFilter struct {
 	accepts []metapb.StoreState
 }
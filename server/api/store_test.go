@@ -30,8 +30,10 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/statistics"
 )
 
 var _ = Suite(&testStoreSuite{})
@@ -241,6 +243,44 @@ func (s *testStoreSuite) TestStoreLabel(c *C) {
 	s.stores[0].Labels = info.Store.Labels
 }
 
+func (s *testStoreSuite) TestPatchStoreLabels(c *C) {
+	url := fmt.Sprintf("%s/stores/4/labels", s.urlPrefix)
+	ll, _ := json.Marshal(map[string]string{"location-labels": "zone,host"})
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/config", ll), IsNil)
+	defer func() {
+		ll, _ := json.Marshal(map[string]string{"location-labels": ""})
+		c.Assert(postJSON(testDialClient, s.urlPrefix+"/config", ll), IsNil)
+	}()
+
+	b, err := json.Marshal(map[string]string{"zone": "z1", "host": "h1"})
+	c.Assert(err, IsNil)
+	c.Assert(patchJSON(testDialClient, url, b), IsNil)
+	var info StoreInfo
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/store/4", s.urlPrefix), &info), IsNil)
+	c.Assert(info.Store.Labels, DeepEquals, []*metapb.StoreLabel{{Key: "host", Value: "h1"}, {Key: "zone", Value: "z1"}})
+	c.Assert(info.LabelWarnings, HasLen, 0)
+
+	b, err = json.Marshal(map[string]string{"zone": "z2", "host": ""})
+	c.Assert(err, IsNil)
+	c.Assert(patchJSON(testDialClient, url, b), IsNil)
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/store/4", s.urlPrefix), &info), IsNil)
+	c.Assert(info.Store.Labels, DeepEquals, []*metapb.StoreLabel{{Key: "zone", Value: "z2"}})
+	c.Assert(info.LabelWarnings, DeepEquals, []string{"missing the location label host"})
+
+	// The labels not in the location labels and the unknown stores are rejected.
+	b, err = json.Marshal(map[string]string{"rack": "r1"})
+	c.Assert(err, IsNil)
+	c.Assert(patchJSON(testDialClient, url, b), NotNil)
+	c.Assert(patchJSON(testDialClient, fmt.Sprintf("%s/stores/10086/labels", s.urlPrefix), b), NotNil)
+
+	b, err = json.Marshal(map[string]string{"zone": ""})
+	c.Assert(err, IsNil)
+	c.Assert(patchJSON(testDialClient, url, b), IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/store/4", s.urlPrefix), &info), IsNil)
+	c.Assert(info.Store.Labels, HasLen, 0)
+}
+
 func (s *testStoreSuite) TestStoreDelete(c *C) {
 	table := []struct {
 		id     int
@@ -336,6 +376,213 @@ func (s *testStoreSuite) TestStoreSetState(c *C) {
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
 }
 
+func (s *testStoreSuite) TestStoreSetWarmUp(c *C) {
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	// The newly joined store warms up.
+	info := &StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, info), IsNil)
+	c.Assert(info.Status.IsWarmingUp, IsTrue)
+	c.Assert(info.Status.WarmUpRatio, Less, 1.0)
+
+	data, err := json.Marshal(map[string]interface{}{"duration": "0s"})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, url+"/warm-up", data), IsNil)
+	info = &StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, info), IsNil)
+	c.Assert(info.Status.IsWarmingUp, IsFalse)
+
+	data, err = json.Marshal(map[string]interface{}{"duration": "1h"})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, url+"/warm-up", data), IsNil)
+	info = &StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, info), IsNil)
+	c.Assert(info.Status.IsWarmingUp, IsTrue)
+
+	for _, input := range []map[string]interface{}{{}, {"duration": 10}, {"duration": "-1m"}, {"duration": "foo"}} {
+		data, err = json.Marshal(input)
+		c.Assert(err, IsNil)
+		c.Assert(postJSON(testDialClient, url+"/warm-up", data), NotNil)
+	}
+	data, err = json.Marshal(map[string]interface{}{"duration": "1h"})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/store/10086/warm-up", data), NotNil)
+}
+
+func (s *testStoreSuite) TestStoreSetBandwidth(c *C) {
+	url := fmt.Sprintf("%s/store/1/bandwidth", s.urlPrefix)
+	data, err := json.Marshal(map[string]interface{}{"send": 100, "recv": 50})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, url, data), IsNil)
+	bandwidth := s.svr.GetRaftCluster().GetOpts().GetStoreBandwidth(1)
+	c.Assert(bandwidth.MaxSendBandwidthMBps, Equals, 100.0)
+	c.Assert(bandwidth.MaxRecvBandwidthMBps, Equals, 50.0)
+
+	for _, input := range []map[string]interface{}{{}, {"send": 100}, {"send": -1, "recv": 50}, {"send": "100", "recv": 50}} {
+		data, err = json.Marshal(input)
+		c.Assert(err, IsNil)
+		c.Assert(postJSON(testDialClient, url, data), NotNil)
+	}
+	data, err = json.Marshal(map[string]interface{}{"send": 100, "recv": 50})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/store/10086/bandwidth", data), NotNil)
+
+	// The zero bandwidth removes the limit.
+	data, err = json.Marshal(map[string]interface{}{"send": 0, "recv": 0})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, url, data), IsNil)
+	c.Assert(s.svr.GetRaftCluster().GetOpts().GetScheduleConfig().StoreBandwidth, HasLen, 0)
+}
+
+func (s *testStoreSuite) TestStoreDrain(c *C) {
+	url := fmt.Sprintf("%s/stores/4/drain", s.urlPrefix)
+	status := &cluster.StoreDrainStatus{}
+	c.Assert(readJSON(testDialClient, url, status), IsNil)
+	c.Assert(status.Draining, IsFalse)
+	c.Assert(status.StartTime, IsNil)
+
+	c.Assert(postJSON(testDialClient, url, nil), IsNil)
+	status = &cluster.StoreDrainStatus{}
+	c.Assert(readJSON(testDialClient, url, status), IsNil)
+	c.Assert(status.StoreID, Equals, uint64(4))
+	c.Assert(status.Draining, IsTrue)
+	c.Assert(status.StartTime, NotNil)
+	c.Assert(status.RegionCount, Equals, 0)
+	c.Assert(status.Finished, IsTrue)
+	// Draining again is a no-op.
+	c.Assert(postJSON(testDialClient, url, nil), IsNil)
+
+	// The removed and the unknown stores can not be drained.
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/stores/7/drain", nil), NotNil)
+	code := requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/10086/drain")
+	c.Assert(code, Equals, http.StatusNotFound)
+
+	_, err := doDelete(testDialClient, url)
+	c.Assert(err, IsNil)
+	status = &cluster.StoreDrainStatus{}
+	c.Assert(readJSON(testDialClient, url, status), IsNil)
+	c.Assert(status.Draining, IsFalse)
+}
+
+func (s *testStoreSuite) TestStoreHealth(c *C) {
+	health := &statistics.StoreHealthScore{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/stores/1/health", s.urlPrefix), health), IsNil)
+	c.Assert(health.StoreID, Equals, uint64(1))
+	for _, component := range []string{"disk", "cpu", "network", "latency", "leader_count"} {
+		_, ok := health.Components[component]
+		c.Assert(ok, IsTrue)
+	}
+	c.Assert(health.Status, Not(Equals), "")
+
+	code := requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/10086/health")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code = requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/abc/health")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
+func (s *testStoreSuite) TestStoreAmplification(c *C) {
+	stats := &pdpb.StoreStats{
+		StoreId:      1,
+		Interval:     &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+		ReadIoRates:  []*pdpb.RecordPair{{Key: "raftstore", Value: 200}},
+		BytesRead:    1000,
+		WriteIoRates: []*pdpb.RecordPair{{Key: "raftstore", Value: 500}},
+		BytesWritten: 1000,
+	}
+	c.Assert(s.svr.GetRaftCluster().HandleStoreHeartbeat(stats), IsNil)
+
+	info := &statistics.StoreAmplificationInfo{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/stores/1/amplification", s.urlPrefix), info), IsNil)
+	c.Assert(info.StoreID, Equals, uint64(1))
+	c.Assert(info.Current, NotNil)
+	c.Assert(info.Current.ReadAmplification, Equals, 2.0)
+	c.Assert(info.Current.WriteAmplification, Equals, 5.0)
+	c.Assert(info.History, HasLen, 1)
+
+	code := requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/10086/amplification")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code = requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/abc/amplification")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
+func (s *testStoreSuite) TestStoreIO(c *C) {
+	stats := &pdpb.StoreStats{
+		StoreId:      4,
+		Interval:     &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+		ReadIoRates:  []*pdpb.RecordPair{{Key: "raftstore", Value: 200}, {Key: "apply", Value: 100}},
+		WriteIoRates: []*pdpb.RecordPair{{Key: "raftstore", Value: 500}},
+	}
+	// Fill the median filter of the rates.
+	for i := 0; i < 3; i++ {
+		c.Assert(s.svr.GetRaftCluster().HandleStoreHeartbeat(stats), IsNil)
+	}
+
+	io := &statistics.StoreIO{}
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/stores/4/io", s.urlPrefix), io), IsNil)
+	c.Assert(io.StoreID, Equals, uint64(4))
+	c.Assert(io.DiskReadRate, Equals, 300.0)
+	c.Assert(io.DiskWriteRate, Equals, 500.0)
+
+	code := requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/10086/io")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code = requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/abc/io")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
+func (s *testStoreSuite) TestSlowStores(c *C) {
+	latencies := map[uint64][2]uint64{4: {80000, 20000}, 6: {10000, 150000}}
+	for storeID, latency := range latencies {
+		stats := &pdpb.StoreStats{
+			StoreId:  storeID,
+			Interval: &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+			OpLatencies: []*pdpb.RecordPair{
+				{Key: core.P99ReadLatencyKey, Value: latency[0]},
+				{Key: core.P99WriteLatencyKey, Value: latency[1]},
+			},
+		}
+		c.Assert(s.svr.GetRaftCluster().HandleStoreHeartbeat(stats), IsNil)
+	}
+
+	getSlowStores := func(query string) []uint64 {
+		var storeIDs []uint64
+		c.Assert(readJSON(testDialClient, s.urlPrefix+"/stores/slow?"+query, &storeIDs), IsNil)
+		return storeIDs
+	}
+	c.Assert(getSlowStores("read-p99-ms=50"), DeepEquals, []uint64{4})
+	c.Assert(getSlowStores("write-p99-ms=100"), DeepEquals, []uint64{6})
+	c.Assert(getSlowStores("read-p99-ms=50&write-p99-ms=100"), DeepEquals, []uint64{4, 6})
+	c.Assert(getSlowStores("read-p99-ms=5"), DeepEquals, []uint64{4, 6})
+	c.Assert(getSlowStores("read-p99-ms=100&write-p99-ms=200"), DeepEquals, []uint64{})
+
+	code := requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/slow")
+	c.Assert(code, Equals, http.StatusBadRequest)
+	code = requestStatusBody(c, testDialClient, http.MethodGet, s.urlPrefix+"/stores/slow?read-p99-ms=abc")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
+func (s *testStoreSuite) TestRemoveOldTombStones(c *C) {
+	removeTombStones := func(query string) []uint64 {
+		req, err := http.NewRequest(http.MethodDelete, s.urlPrefix+"/stores/tombstones?"+query, nil)
+		c.Assert(err, IsNil)
+		resp, err := testDialClient.Do(req)
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, Equals, http.StatusOK)
+		var removed []uint64
+		c.Assert(json.NewDecoder(resp.Body).Decode(&removed), IsNil)
+		return removed
+	}
+
+	// Store 7 is a tombstone which has never sent heartbeats.
+	c.Assert(removeTombStones("older-than=30d&dry-run=true"), DeepEquals, []uint64{7})
+	c.Assert(removeTombStones("older-than=12h&dry-run=true"), DeepEquals, []uint64{7})
+	c.Assert(s.svr.GetRaftCluster().GetStore(7), NotNil)
+
+	code := requestStatusBody(c, testDialClient, http.MethodDelete, s.urlPrefix+"/stores/tombstones?older-than=abc")
+	c.Assert(code, Equals, http.StatusBadRequest)
+	code = requestStatusBody(c, testDialClient, http.MethodDelete, s.urlPrefix+"/stores/tombstones?dry-run=abc")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
 func (s *testStoreSuite) TestUrlStoreFilter(c *C) {
 	table := []struct {
 		u    string
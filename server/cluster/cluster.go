@@ -15,10 +15,13 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -33,11 +36,15 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/etcdutil"
 	"github.com/tikv/pd/pkg/logutil"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/events"
+	"github.com/tikv/pd/server/genealogy"
 	"github.com/tikv/pd/server/id"
+	"github.com/tikv/pd/server/keyspace"
 	syncer "github.com/tikv/pd/server/region_syncer"
 	"github.com/tikv/pd/server/replication"
 	"github.com/tikv/pd/server/schedule"
@@ -45,6 +52,7 @@ import (
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/labeler"
 	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/schedule/quota"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage"
 	"github.com/tikv/pd/server/storage/endpoint"
@@ -56,6 +64,10 @@ import (
 // backgroundJobInterval is the interval to run background jobs.
 var backgroundJobInterval = 10 * time.Second
 
+// tombstoneCleanupInterval is the interval to remove the tombstone stores
+// exceeding the retention.
+var tombstoneCleanupInterval = time.Hour
+
 // DefaultMinResolvedTSPersistenceInterval is the default value of min resolved ts persistence interval.
 var DefaultMinResolvedTSPersistenceInterval = 10 * time.Second
 
@@ -128,6 +140,20 @@ type RaftCluster struct {
 	replicationMode *replication.ModeManager
 
 	unsafeRecoveryController *unsafeRecoveryController
+
+	eventHub *events.Hub
+	// regionQuotas detects the regions exceeding the quotas of their key ranges.
+	regionQuotas *quota.Manager
+	// genealogy records the splits and the merges of the regions.
+	genealogy *genealogy.Genealogy
+	// keyspaceAffinity restricts the stores serving the keyspace regions.
+	keyspaceAffinity *keyspace.AffinityManager
+	// keyspaceTTL cleans up the expired regions of the keyspaces with TTL.
+	keyspaceTTL *keyspace.TTLWorker
+	// regionTraffic records the recent traffic of the regions.
+	regionTraffic *statistics.RegionTraffic
+	// storeAmplification records the recent amplification of the stores.
+	storeAmplification *statistics.StoreAmplification
 }
 
 // Status saves some state information.
@@ -149,6 +175,7 @@ func NewRaftCluster(ctx context.Context, clusterID uint64, regionSyncer *syncer.
 		httpClient:         httpClient,
 		etcdClient:         etcdClient,
 		storeConfigManager: manager,
+		eventHub:           events.NewHub(),
 	}
 }
 
@@ -219,6 +246,8 @@ func (c *RaftCluster) InitCluster(
 	c.ctx, c.cancel = context.WithCancel(c.serverCtx)
 	c.labelLevelStats = statistics.NewLabelStatistics()
 	c.hotStat = statistics.NewHotStat(c.ctx)
+	c.regionTraffic = statistics.NewRegionTraffic(opt.GetPDServerConfig().RegionTrafficSamples)
+	c.storeAmplification = statistics.NewStoreAmplification()
 	c.changedRegions = make(chan *core.RegionInfo, defaultChangedRegionsLimit)
 }
 
@@ -255,6 +284,27 @@ func (c *RaftCluster) Start(s Server) error {
 		return err
 	}
 
+	c.regionQuotas, err = quota.NewManager(c.storage)
+	if err != nil {
+		return err
+	}
+
+	c.genealogy, err = genealogy.NewGenealogy(c.storage, c.opt.GetPDServerConfig().MaxGenealogyEntries)
+	if err != nil {
+		return err
+	}
+
+	c.keyspaceAffinity, err = keyspace.NewAffinityManager(c.storage)
+	if err != nil {
+		return err
+	}
+
+	tlsCfg, err := s.GetConfig().Security.ToTLSConfig()
+	if err != nil {
+		return err
+	}
+	c.keyspaceTTL = keyspace.NewTTLWorker(c.storage, &regionRangeDeleter{cluster: c, tlsCfg: tlsCfg})
+
 	c.replicationMode, err = replication.NewReplicationModeManager(s.GetConfig().ReplicationMode, c.storage, cluster, s)
 	if err != nil {
 		return err
@@ -265,7 +315,7 @@ func (c *RaftCluster) Start(s Server) error {
 	c.limiter = NewStoreLimiter(s.GetPersistOptions())
 	c.unsafeRecoveryController = newUnsafeRecoveryController(cluster)
 
-	c.wg.Add(6)
+	c.wg.Add(8)
 	go c.runCoordinator()
 	failpoint.Inject("highFrequencyClusterJobs", func() {
 		backgroundJobInterval = 100 * time.Microsecond
@@ -275,6 +325,8 @@ func (c *RaftCluster) Start(s Server) error {
 	go c.syncRegions()
 	go c.runReplicationMode()
 	go c.runMinResolvedTSJob()
+	go c.runTombstoneCleanupJob()
+	go c.runKeyspaceTTLJob()
 	c.running = true
 
 	return nil
@@ -543,7 +595,27 @@ func (c *RaftCluster) HandleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	if store == nil {
 		return errors.Errorf("store %v not found", storeID)
 	}
+	c.updateHotCacheConfig()
 	newStore := store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(time.Now()))
+	if newStore.ExceedLatencyThreshold(c.opt.GetSlowStoreReadLatencyThresholdUs(), c.opt.GetSlowStoreWriteLatencyThresholdUs()) {
+		newStore = newStore.Clone(core.SetLatencySlowTimes(store.GetLatencySlowTimes() + 1))
+		if newStore.IsLatencySlow() {
+			log.Warn("store p99 latency exceeds the threshold",
+				zap.Uint64("store-id", newStore.GetID()),
+				zap.Uint64("p99-read-latency-us", newStore.GetP99ReadLatencyUs()),
+				zap.Uint64("p99-write-latency-us", newStore.GetP99WriteLatencyUs()))
+		}
+	} else if store.GetLatencySlowTimes() > 0 {
+		newStore = newStore.Clone(core.SetLatencySlowTimes(0))
+	}
+	if warnings := c.checkStoreLabelWarnings(newStore); !reflect.DeepEqual(warnings, store.GetLabelWarnings()) {
+		for _, warning := range warnings {
+			log.Warn("store labels do not match the location labels",
+				zap.Uint64("store-id", newStore.GetID()),
+				zap.String("warning", warning))
+		}
+		newStore = newStore.Clone(core.SetLabelWarnings(warnings))
+	}
 	if newStore.IsLowSpace(c.opt.GetLowSpaceRatio()) {
 		log.Warn("store does not have enough disk space",
 			zap.Uint64("store-id", newStore.GetID()),
@@ -562,6 +634,7 @@ func (c *RaftCluster) HandleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	}
 	c.core.PutStore(newStore)
 	c.hotStat.Observe(newStore.GetID(), newStore.GetStoreStats())
+	c.storeAmplification.Observe(stats)
 	c.hotStat.FilterUnhealthyStore(c)
 	reportInterval := stats.GetInterval()
 	interval := reportInterval.GetEndTimestamp() - reportInterval.GetStartTimestamp()
@@ -614,6 +687,9 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	storage := c.storage
 	coreCluster := c.core
 	hotStat := c.hotStat
+	regionQuotas := c.regionQuotas
+	regionTraffic := c.regionTraffic
+	keyspaceTTL := c.keyspaceTTL
 	c.RUnlock()
 
 	origin, err := coreCluster.PreCheckPutRegion(region)
@@ -621,6 +697,11 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 		return err
 	}
 	region.CorrectApproximateSize(origin)
+	if v := regionQuotas.Observe(region); v != nil {
+		c.sendThrottleHint(region, v)
+	}
+	regionTraffic.Observe(region)
+	keyspaceTTL.Observe(region, time.Now())
 
 	hotStat.CheckWriteAsync(statistics.NewCheckExpiredItemTask(region))
 	hotStat.CheckReadAsync(statistics.NewCheckExpiredItemTask(region))
@@ -643,7 +724,7 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 		time.Sleep(500 * time.Millisecond)
 	})
 
-	var overlaps []*core.RegionInfo
+	var overlaps, merged []*core.RegionInfo
 	c.Lock()
 	if saveCache {
 		// To prevent a concurrent heartbeat of another region from overriding the up-to-date region info by a stale one,
@@ -660,7 +741,12 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 				c.regionStats.ClearDefunctRegion(item.GetID())
 			}
 			c.labelLevelStats.ClearDefunctRegion(item.GetID())
+			c.regionQuotas.Remove(item.GetID())
+			c.regionTraffic.Remove(item.GetID())
+			c.keyspaceTTL.Remove(item.GetID())
 		}
+		merged = mergedRegions(region, overlaps)
+		c.publishRegionMerge(region, merged)
 
 		// Update related stores.
 		storeMap := make(map[uint64]struct{})
@@ -687,9 +773,18 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	}
 
 	changedRegions := c.changedRegions
+	regionGenealogy := c.genealogy
 
 	c.Unlock()
 
+	if len(merged) > 0 {
+		sourceIDs := make([]uint64, 0, len(merged))
+		for _, item := range merged {
+			sourceIDs = append(sourceIDs, item.GetID())
+		}
+		regionGenealogy.RecordMerge(sourceIDs, region.GetID())
+	}
+
 	if storage != nil {
 		// If there are concurrent heartbeats from the same region, the last write will win even if
 		// writes to storage in the critical area. So don't use mutex to protect it.
@@ -724,6 +819,43 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	return nil
 }
 
+// sendThrottleHint asks the leader of the region to throttle the flow of the
+// region to the quota it exceeds.
+func (c *RaftCluster) sendThrottleHint(region *core.RegionInfo, v *quota.Violation) {
+	hbStreams := c.GetHeartbeatStreams()
+	if hbStreams == nil {
+		return
+	}
+	resp := &pdpb.RegionHeartbeatResponse{}
+	quota.SetThrottleRegion(resp, v.Hint())
+	hbStreams.SendMsg(region, resp)
+}
+
+// mergedRegions returns the overlapped regions covered by the region, they
+// are merged into it. The overlapped regions which are not covered are
+// replaced by the regions split from them.
+func mergedRegions(region *core.RegionInfo, overlaps []*core.RegionInfo) []*core.RegionInfo {
+	var merged []*core.RegionInfo
+	for _, item := range overlaps {
+		if bytes.Compare(item.GetStartKey(), region.GetStartKey()) < 0 {
+			continue
+		}
+		if len(region.GetEndKey()) > 0 && (len(item.GetEndKey()) == 0 || bytes.Compare(item.GetEndKey(), region.GetEndKey()) > 0) {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// publishRegionMerge publishes the merge events of the regions merged into the region.
+func (c *RaftCluster) publishRegionMerge(region *core.RegionInfo, merged []*core.RegionInfo) {
+	for _, item := range merged {
+		message := fmt.Sprintf("region %d is merged into region %d", item.GetID(), region.GetID())
+		c.eventHub.Publish(events.NewClusterEvent(events.RegionMerge, message).WithRegion(region.GetID()))
+	}
+}
+
 func (c *RaftCluster) updateStoreStatusLocked(id uint64) {
 	leaderCount := c.core.GetStoreLeaderCount(id)
 	regionCount := c.core.GetStoreRegionCount(id)
@@ -910,6 +1042,49 @@ func (c *RaftCluster) UpdateStoreLabels(storeID uint64, labels []*metapb.StoreLa
 	return c.putStoreImpl(newStore, force)
 }
 
+// PatchStoreLabels updates the labels of the store by the patch, the label
+// with an empty value is deleted. If the location labels are configured, only
+// they can be patched.
+func (c *RaftCluster) PatchStoreLabels(storeID uint64, patch map[string]string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if locationLabels := c.opt.GetLocationLabels(); len(locationLabels) > 0 {
+		for key := range patch {
+			if slice.NoneOf(locationLabels, func(i int) bool { return locationLabels[i] == key }) {
+				return errors.Errorf("label key %s is not in the location labels %v", key, locationLabels)
+			}
+		}
+	}
+
+	labels := make([]*metapb.StoreLabel, 0, len(store.GetLabels())+len(patch))
+	for _, label := range store.GetLabels() {
+		if _, ok := patch[label.GetKey()]; !ok {
+			labels = append(labels, label)
+		}
+	}
+	for key, value := range patch {
+		if value != "" {
+			labels = append(labels, &metapb.StoreLabel{Key: key, Value: value})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].GetKey() < labels[j].GetKey() })
+	if err := config.ValidateLabels(labels); err != nil {
+		return err
+	}
+
+	newStore := store.Clone(core.SetStoreLabels(labels))
+	if err := c.checkStoreLabels(newStore); err != nil {
+		return err
+	}
+	newStore = newStore.Clone(core.SetLabelWarnings(c.checkStoreLabelWarnings(newStore)))
+	return c.putStoreLocked(newStore)
+}
+
 // PutStore puts a store.
 func (c *RaftCluster) PutStore(store *metapb.Store) error {
 	if err := c.putStoreImpl(store, false); err != nil {
@@ -947,8 +1122,8 @@ func (c *RaftCluster) putStoreImpl(store *metapb.Store, force bool) error {
 
 	s := c.GetStore(store.GetId())
 	if s == nil {
-		// Add a new store.
-		s = core.NewStoreInfo(store)
+		// Add a new store, it warms up before receiving regions at full speed.
+		s = core.NewStoreInfo(store, core.SetStoreWarmUp(time.Now(), c.opt.GetStoreWarmUpDuration()))
 	} else {
 		// Use the given labels to update the store.
 		labels := store.GetLabels()
@@ -968,6 +1143,7 @@ func (c *RaftCluster) putStoreImpl(store *metapb.Store, force bool) error {
 	if err := c.checkStoreLabels(s); err != nil {
 		return err
 	}
+	s = s.Clone(core.SetLabelWarnings(c.checkStoreLabelWarnings(s)))
 	return c.putStoreLocked(s)
 }
 
@@ -1010,6 +1186,18 @@ func (c *RaftCluster) checkStoreLabels(s *core.StoreInfo) error {
 	return nil
 }
 
+// checkStoreLabelWarnings returns the warnings of the location labels missing
+// in the store labels. They are not errors to allow the legacy stores.
+func (c *RaftCluster) checkStoreLabelWarnings(s *core.StoreInfo) []string {
+	var warnings []string
+	for _, k := range c.opt.GetLocationLabels() {
+		if v := s.GetLabelValue(k); len(v) == 0 {
+			warnings = append(warnings, fmt.Sprintf("missing the location label %s", k))
+		}
+	}
+	return warnings
+}
+
 // RemoveStore marks a store as offline in cluster.
 // State transition: Up -> Offline.
 func (c *RaftCluster) RemoveStore(storeID uint64, physicallyDestroyed bool) error {
@@ -1084,6 +1272,7 @@ func (c *RaftCluster) BuryStore(storeID uint64, forceBury bool) error {
 		// clean up the residual information.
 		c.RemoveStoreLimit(storeID)
 		c.hotStat.RemoveRollingStoreStats(storeID)
+		c.storeAmplification.Remove(storeID)
 	}
 	return err
 }
@@ -1161,12 +1350,127 @@ func (c *RaftCluster) SetStoreWeight(storeID uint64, leaderWeight, regionWeight
 	return c.putStoreLocked(newStore)
 }
 
+// SetStoreWarmUp restarts the warm-up of the store with the given duration,
+// the zero duration stops the warm-up. The warm-up is not persisted, the
+// stores loaded by a new PD leader do not warm up.
+func (c *RaftCluster) SetStoreWarmUp(storeID uint64, duration time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsRemoved() {
+		return errs.ErrStoreRemoved.FastGenByArgs(storeID)
+	}
+
+	log.Info("set the warm-up of store", zap.Uint64("store-id", storeID), zap.Duration("duration", duration))
+	return c.putStoreLocked(store.Clone(core.SetStoreWarmUp(time.Now(), duration)))
+}
+
+// DrainStore marks the store as draining. The checkers move all regions away
+// from it and the schedulers do not select it as the target, while it keeps
+// serving until the regions are moved away, unlike the removing store.
+func (c *RaftCluster) DrainStore(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsRemoved() {
+		return errs.ErrStoreRemoved.FastGenByArgs(storeID)
+	}
+	if store.IsDraining() {
+		return nil
+	}
+
+	startTime := time.Now()
+	if err := c.storage.SaveStoreDrainStartTime(storeID, startTime); err != nil {
+		return err
+	}
+	log.Warn("store starts draining", zap.Uint64("store-id", storeID), zap.String("store-address", store.GetAddress()))
+	return c.putStoreLocked(store.Clone(core.SetDrainStartTime(startTime)))
+}
+
+// StopDrainStore cleans the draining state of the store, the regions moved
+// away are not moved back.
+func (c *RaftCluster) StopDrainStore(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if !store.IsDraining() {
+		return nil
+	}
+
+	if err := c.storage.DeleteStoreDrainStartTime(storeID); err != nil {
+		return err
+	}
+	log.Info("store stops draining", zap.Uint64("store-id", storeID))
+	return c.putStoreLocked(store.Clone(core.SetDrainStartTime(time.Time{})))
+}
+
+// StoreDrainStatus is the progress of draining a store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreDrainStatus struct {
+	StoreID   uint64     `json:"store_id"`
+	Draining  bool       `json:"draining"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	// RegionCount and LeaderCount are the regions and the leaders remaining
+	// on the store.
+	RegionCount int  `json:"region_count"`
+	LeaderCount int  `json:"leader_count"`
+	Finished    bool `json:"finished"`
+}
+
+// GetStoreDrainStatus returns the progress of draining the store.
+func (c *RaftCluster) GetStoreDrainStatus(storeID uint64) (*StoreDrainStatus, error) {
+	store := c.GetStore(storeID)
+	if store == nil {
+		return nil, errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	status := &StoreDrainStatus{
+		StoreID:     storeID,
+		Draining:    store.IsDraining(),
+		RegionCount: c.core.GetStoreRegionCount(storeID),
+		LeaderCount: c.core.GetStoreLeaderCount(storeID),
+	}
+	if status.Draining {
+		startTime := store.GetDrainStartTime()
+		status.StartTime = &startTime
+		status.Finished = status.RegionCount == 0
+	}
+	return status, nil
+}
+
+// GetStoreHealthScore returns the health score of the store.
+func (c *RaftCluster) GetStoreHealthScore(storeID uint64) (*statistics.StoreHealthScore, error) {
+	store := c.GetStore(storeID)
+	if store == nil {
+		return nil, errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	return statistics.CalcStoreHealthScore(store, c.GetStores(), statistics.NewHealthScorers(c.opt)), nil
+}
+
 func (c *RaftCluster) putStoreLocked(store *core.StoreInfo) error {
 	if c.storage != nil {
 		if err := c.storage.SaveStore(store.GetMeta()); err != nil {
 			return err
 		}
 	}
+	if old := c.core.GetStore(store.GetID()); old == nil || old.GetNodeState() != store.GetNodeState() {
+		message := store.GetNodeState().String()
+		if old != nil {
+			message = fmt.Sprintf("%s -> %s", old.GetNodeState(), store.GetNodeState())
+		}
+		c.eventHub.Publish(events.NewClusterEvent(events.StoreStateChange, message).WithStore(store.GetID()))
+	}
 	c.core.PutStore(store)
 	c.hotStat.GetOrCreateRollingStoreStats(store.GetID())
 	return nil
@@ -1217,29 +1521,77 @@ func (c *RaftCluster) checkStores() {
 
 // RemoveTombStoneRecords removes the tombStone Records.
 func (c *RaftCluster) RemoveTombStoneRecords() error {
+	_, err := c.RemoveTombStoneRecordsOlderThan(0, false)
+	return err
+}
+
+// RemoveTombStoneRecordsOlderThan removes the tombstone stores which have not
+// sent heartbeats for the duration, and returns the removed stores. If dryRun
+// is true, the stores are only logged and returned without being removed.
+func (c *RaftCluster) RemoveTombStoneRecordsOlderThan(olderThan time.Duration, dryRun bool) ([]uint64, error) {
 	c.Lock()
 	defer c.Unlock()
 
+	removed := make([]uint64, 0)
 	for _, store := range c.GetStores() {
-		if store.IsRemoved() {
-			if c.core.GetStoreRegionCount(store.GetID()) > 0 {
-				log.Warn("skip removing tombstone", zap.Stringer("store", store.GetMeta()))
-				continue
-			}
-			// the store has already been tombstone
-			err := c.deleteStoreLocked(store)
-			if err != nil {
-				log.Error("delete store failed",
-					zap.Stringer("store", store.GetMeta()),
-					errs.ZapError(err))
-				return err
-			}
-			c.RemoveStoreLimit(store.GetID())
-			log.Info("delete store succeeded",
-				zap.Stringer("store", store.GetMeta()))
+		if !store.IsRemoved() || store.DownTime() < olderThan {
+			continue
+		}
+		if c.core.GetStoreRegionCount(store.GetID()) > 0 {
+			log.Warn("skip removing tombstone", zap.Stringer("store", store.GetMeta()))
+			continue
+		}
+		if dryRun {
+			log.Info("tombstone store would be deleted",
+				zap.Stringer("store", store.GetMeta()),
+				zap.Duration("down-time", store.DownTime()))
+			removed = append(removed, store.GetID())
+			continue
+		}
+		// the store has already been tombstone
+		err := c.deleteStoreLocked(store)
+		if err != nil {
+			log.Error("delete store failed",
+				zap.Stringer("store", store.GetMeta()),
+				errs.ZapError(err))
+			return removed, err
 		}
+		c.RemoveStoreLimit(store.GetID())
+		removed = append(removed, store.GetID())
+		log.Info("delete store succeeded",
+			zap.Stringer("store", store.GetMeta()))
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	return removed, nil
+}
+
+func (c *RaftCluster) runTombstoneCleanupJob() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(tombstoneCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Info("tombstone cleanup background jobs has been stopped")
+			return
+		case <-ticker.C:
+			c.cleanupTombstoneStores()
+		}
+	}
+}
+
+// cleanupTombstoneStores removes the tombstone stores exceeding the retention.
+func (c *RaftCluster) cleanupTombstoneStores() {
+	retention := c.opt.GetTombstoneStoreRetention()
+	if retention == 0 {
+		return
+	}
+	if _, err := c.RemoveTombStoneRecordsOlderThan(retention, c.opt.IsTombstoneStoreCleanupDryRun()); err != nil {
+		log.Error("failed to clean up the tombstone stores", errs.ZapError(err))
 	}
-	return nil
 }
 
 func (c *RaftCluster) deleteStoreLocked(store *core.StoreInfo) error {
@@ -1264,6 +1616,7 @@ func (c *RaftCluster) collectMetrics() {
 	c.coordinator.collectHotSpotMetrics()
 	c.collectClusterMetrics()
 	c.collectHealthStatus()
+	c.collectSnapshotMetrics()
 }
 
 func (c *RaftCluster) resetMetrics() {
@@ -1274,6 +1627,7 @@ func (c *RaftCluster) resetMetrics() {
 	c.coordinator.resetHotSpotMetrics()
 	c.resetClusterMetrics()
 	c.resetHealthStatus()
+	c.resetSnapshotMetrics()
 }
 
 func (c *RaftCluster) collectClusterMetrics() {
@@ -1450,6 +1804,14 @@ func (c *RaftCluster) GetStoresLoads() map[uint64][]float64 {
 	return c.hotStat.GetStoresLoads()
 }
 
+// updateHotCacheConfig applies the config of the hot cache.
+func (c *RaftCluster) updateHotCacheConfig() {
+	opts := c.GetOpts()
+	c.hotStat.SetExpiry(opts.GetHotCacheEntryTTL(), opts.GetHotCacheDecayRate())
+	short, medium, long := opts.GetHotWindows()
+	c.hotStat.SetWindows(short, medium, long, opts.GetHotWindowWeights())
+}
+
 // RegionReadStats returns hot region's read stats.
 // The result only includes peers that are hot enough.
 // RegionStats is a thread-safe method
@@ -1457,6 +1819,7 @@ func (c *RaftCluster) RegionReadStats() map[uint64][]*statistics.HotPeerStat {
 	// As read stats are reported by store heartbeat, the threshold needs to be adjusted.
 	threshold := c.GetOpts().GetHotRegionCacheHitsThreshold() *
 		(statistics.RegionHeartBeatReportInterval / statistics.StoreHeartBeatReportInterval)
+	c.updateHotCacheConfig()
 	return c.hotStat.RegionStats(statistics.Read, threshold)
 }
 
@@ -1464,6 +1827,7 @@ func (c *RaftCluster) RegionReadStats() map[uint64][]*statistics.HotPeerStat {
 // The result only includes peers that are hot enough.
 func (c *RaftCluster) RegionWriteStats() map[uint64][]*statistics.HotPeerStat {
 	// RegionStats is a thread-safe method
+	c.updateHotCacheConfig()
 	return c.hotStat.RegionStats(statistics.Write, c.GetOpts().GetHotRegionCacheHitsThreshold())
 }
 
@@ -1488,6 +1852,67 @@ func (c *RaftCluster) GetRuleManager() *placement.RuleManager {
 	return c.ruleManager
 }
 
+// GetRegionQuotas returns the region quota manager.
+func (c *RaftCluster) GetRegionQuotas() *quota.Manager {
+	c.RLock()
+	defer c.RUnlock()
+	return c.regionQuotas
+}
+
+// GetRegionTraffic returns the recent traffic of the regions.
+func (c *RaftCluster) GetRegionTraffic() *statistics.RegionTraffic {
+	c.RLock()
+	defer c.RUnlock()
+	return c.regionTraffic
+}
+
+// GetStoreIO returns the IO rates of the store.
+func (c *RaftCluster) GetStoreIO(storeID uint64) (*statistics.StoreIO, error) {
+	if c.GetStore(storeID) == nil {
+		return nil, errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	return c.hotStat.GetStoreIO(storeID), nil
+}
+
+// GetSlowStores returns the IDs of the stores whose p99 read or write latency
+// reported by the last heartbeat exceeds the thresholds in microseconds, 0
+// means no threshold.
+func (c *RaftCluster) GetSlowStores(readThresholdUs, writeThresholdUs uint64) []uint64 {
+	storeIDs := []uint64{}
+	for _, store := range c.GetStores() {
+		if store.IsRemoved() {
+			continue
+		}
+		if store.ExceedLatencyThreshold(readThresholdUs, writeThresholdUs) {
+			storeIDs = append(storeIDs, store.GetID())
+		}
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	return storeIDs
+}
+
+// GetStoreAmplification returns the recent amplification of the store.
+func (c *RaftCluster) GetStoreAmplification(storeID uint64) (*statistics.StoreAmplificationInfo, error) {
+	if c.GetStore(storeID) == nil {
+		return nil, errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	return c.storeAmplification.GetAmplification(storeID), nil
+}
+
+// GetGenealogy returns the region genealogy.
+func (c *RaftCluster) GetGenealogy() *genealogy.Genealogy {
+	c.RLock()
+	defer c.RUnlock()
+	return c.genealogy
+}
+
+// GetKeyspaceAffinity returns the keyspace affinity manager.
+func (c *RaftCluster) GetKeyspaceAffinity() *keyspace.AffinityManager {
+	c.RLock()
+	defer c.RUnlock()
+	return c.keyspaceAffinity
+}
+
 // GetRegionLabeler returns the region labeler.
 func (c *RaftCluster) GetRegionLabeler() *labeler.RegionLabeler {
 	c.RLock()
@@ -1495,6 +1920,11 @@ func (c *RaftCluster) GetRegionLabeler() *labeler.RegionLabeler {
 	return c.regionLabeler
 }
 
+// GetEventHub returns the hub of the cluster events.
+func (c *RaftCluster) GetEventHub() *events.Hub {
+	return c.eventHub
+}
+
 // GetHotWriteRegions gets hot write regions' info.
 func (c *RaftCluster) GetHotWriteRegions(storeIDs ...uint64) *statistics.StoreHotPeersInfos {
 	hotWriteRegions := c.coordinator.getHotRegionsByType(statistics.Write)
@@ -1561,6 +1991,34 @@ func (c *RaftCluster) PauseOrResumeScheduler(name string, t int64) error {
 	return c.coordinator.pauseOrResumeScheduler(name, t)
 }
 
+// PauseScheduler pauses a scheduler for the duration.
+func (c *RaftCluster) PauseScheduler(name string, duration time.Duration) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.pauseScheduler(name, duration)
+}
+
+// ResumeScheduler resumes a paused scheduler.
+func (c *RaftCluster) ResumeScheduler(name string) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.resumeScheduler(name)
+}
+
+// SetSchedulerTimeWindow restricts a scheduler to schedule only inside the time window.
+func (c *RaftCluster) SetSchedulerTimeWindow(name string, tw schedule.TimeWindow) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.setSchedulerTimeWindow(name, tw)
+}
+
+// GetSchedulerTimeWindow returns the time window of a scheduler.
+func (c *RaftCluster) GetSchedulerTimeWindow(name string) (schedule.TimeWindow, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.getSchedulerTimeWindow(name)
+}
+
 // IsSchedulerPaused checks if a scheduler is paused.
 func (c *RaftCluster) IsSchedulerPaused(name string) (bool, error) {
 	c.RLock()
@@ -1771,6 +2229,23 @@ func (c *RaftCluster) SetStoreLimit(storeID uint64, typ storelimit.Type, ratePer
 	return nil
 }
 
+// SetStoreBandwidth sets the network bandwidth of a store.
+func (c *RaftCluster) SetStoreBandwidth(storeID uint64, sendMBps, recvMBps float64) error {
+	if c.GetStore(storeID) == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	old := c.opt.GetScheduleConfig().Clone()
+	c.opt.SetStoreBandwidth(storeID, sendMBps, recvMBps)
+	if err := c.opt.Persist(c.storage); err != nil {
+		// roll back the store bandwidth
+		c.opt.SetScheduleConfig(old)
+		log.Error("persist store bandwidth meet error", errs.ZapError(err))
+		return err
+	}
+	log.Info("store bandwidth changed", zap.Uint64("store-id", storeID), zap.Float64("send-mbps", sendMBps), zap.Float64("recv-mbps", recvMBps))
+	return nil
+}
+
 // SetAllStoresLimit sets all store limit for a given type and rate.
 func (c *RaftCluster) SetAllStoresLimit(typ storelimit.Type, ratePerMin float64) error {
 	old := c.opt.GetScheduleConfig().Clone()
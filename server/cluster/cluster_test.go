@@ -179,6 +179,39 @@ func (s *testClusterInfoSuite) TestStoreHeartbeat(c *C) {
 	c.Assert(storeStats[1][0].RegionID, Equals, uint64(1))
 }
 
+func (s *testClusterInfoSuite) TestStoreLabelWarnings(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	rep := opt.GetReplicationConfig().Clone()
+	rep.LocationLabels = []string{"zone", "rack", "host"}
+	opt.SetReplicationConfig(rep)
+	cluster := newTestRaftCluster(s.ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend(), core.NewBasicCluster())
+
+	labels := [][]*metapb.StoreLabel{
+		{{Key: "zone", Value: "z1"}, {Key: "rack", Value: "r1"}, {Key: "host", Value: "h1"}},
+		{{Key: "zone", Value: "z1"}},
+		{{Key: "disk", Value: "ssd"}},
+	}
+	expected := [][]string{
+		nil,
+		{"missing the location label rack", "missing the location label host"},
+		{"missing the location label zone", "missing the location label rack", "missing the location label host"},
+	}
+	for i, store := range newTestStores(3, "2.0.0") {
+		c.Assert(cluster.putStoreLocked(store.Clone(core.SetStoreLabels(labels[i]))), IsNil)
+		c.Assert(cluster.GetStore(store.GetID()).GetLabelWarnings(), HasLen, 0)
+		c.Assert(cluster.HandleStoreHeartbeat(&pdpb.StoreStats{StoreId: store.GetID(), Capacity: 100, Available: 50}), IsNil)
+		c.Assert(cluster.GetStore(store.GetID()).GetLabelWarnings(), DeepEquals, expected[i])
+	}
+
+	// The warnings are updated with the labels.
+	c.Assert(cluster.UpdateStoreLabels(2, []*metapb.StoreLabel{{Key: "rack", Value: "r1"}, {Key: "host", Value: "h2"}}, false), IsNil)
+	c.Assert(cluster.GetStore(2).GetLabelWarnings(), HasLen, 0)
+	// The heartbeat does not fail with the missing labels.
+	c.Assert(cluster.HandleStoreHeartbeat(&pdpb.StoreStats{StoreId: 3, Capacity: 100, Available: 50}), IsNil)
+	c.Assert(cluster.GetStore(3).GetLabelWarnings(), HasLen, 3)
+}
+
 func (s *testClusterInfoSuite) TestFilterUnhealthyStore(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
@@ -211,6 +244,53 @@ func (s *testClusterInfoSuite) TestFilterUnhealthyStore(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestSlowStoreLatency(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	opt.GetScheduleConfig().SlowStoreReadLatencyThresholdUs = 1000
+	opt.GetScheduleConfig().SlowStoreWriteLatencyThresholdUs = 2000
+	cluster := newTestRaftCluster(s.ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend(), core.NewBasicCluster())
+
+	store := newTestStores(1, "2.0.0")[0]
+	c.Assert(cluster.putStoreLocked(store), IsNil)
+	heartbeat := func(readUs, writeUs uint64) {
+		storeStats := &pdpb.StoreStats{
+			StoreId:     store.GetID(),
+			Capacity:    100,
+			Available:   50,
+			RegionCount: 1,
+			OpLatencies: []*pdpb.RecordPair{
+				{Key: core.P99ReadLatencyKey, Value: readUs},
+				{Key: core.P99WriteLatencyKey, Value: writeUs},
+			},
+		}
+		c.Assert(cluster.HandleStoreHeartbeat(storeStats), IsNil)
+	}
+
+	heartbeat(500, 500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsFalse)
+	// A single heartbeat exceeding the threshold is not enough.
+	heartbeat(1500, 500)
+	c.Assert(cluster.GetStore(store.GetID()).GetP99ReadLatencyUs(), Equals, uint64(1500))
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsFalse)
+	heartbeat(500, 500)
+	heartbeat(1500, 500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsFalse)
+	heartbeat(500, 2500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsTrue)
+	heartbeat(1500, 2500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsTrue)
+	// The store recovers once the latency falls below the thresholds.
+	heartbeat(500, 500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsFalse)
+
+	// 0 means no threshold.
+	opt.GetScheduleConfig().SlowStoreReadLatencyThresholdUs = 0
+	heartbeat(1500, 500)
+	heartbeat(1500, 500)
+	c.Assert(cluster.GetStore(store.GetID()).IsLatencySlow(), IsFalse)
+}
+
 func (s *testClusterInfoSuite) TestSetOfflineStore(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
@@ -272,6 +352,52 @@ func (s *testClusterInfoSuite) TestForceBuryStore(c *C) {
 	c.Assert(errors.ErrorEqual(cluster.BuryStore(uint64(3), true), errs.ErrStoreNotFound.FastGenByArgs(uint64(3))), IsTrue)
 }
 
+func (s *testClusterInfoSuite) TestCleanupTombstoneStores(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(s.ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend(), core.NewBasicCluster())
+	c.Assert(opt.GetTombstoneStoreRetention(), Equals, 30*24*time.Hour)
+
+	stores := newTestStores(4, "5.3.0")
+	// Store 1 is up, store 2 is a recent tombstone, store 3 and 4 are old tombstones.
+	stores[0] = stores[0].Clone(core.SetLastHeartbeatTS(time.Now().Add(-40 * 24 * time.Hour)))
+	stores[1] = stores[1].Clone(core.TombstoneStore(), core.SetLastHeartbeatTS(time.Now().Add(-24*time.Hour)))
+	stores[2] = stores[2].Clone(core.TombstoneStore(), core.SetLastHeartbeatTS(time.Now().Add(-31*24*time.Hour)))
+	stores[3] = stores[3].Clone(core.TombstoneStore())
+	for _, store := range stores {
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+
+	// Dry run does not remove the stores.
+	opt.GetScheduleConfig().TombstoneStoreCleanupDryRun = true
+	cluster.cleanupTombstoneStores()
+	c.Assert(cluster.GetStoreCount(), Equals, 4)
+	removed, err := cluster.RemoveTombStoneRecordsOlderThan(opt.GetTombstoneStoreRetention(), true)
+	c.Assert(err, IsNil)
+	c.Assert(removed, HasLen, 2)
+
+	opt.GetScheduleConfig().TombstoneStoreCleanupDryRun = false
+	cluster.cleanupTombstoneStores()
+	c.Assert(cluster.GetStoreCount(), Equals, 2)
+	c.Assert(cluster.GetStore(1), NotNil)
+	c.Assert(cluster.GetStore(2), NotNil)
+	meta := &metapb.Store{}
+	ok, err := cluster.storage.LoadStore(3, meta)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+
+	// 0 means never.
+	opt.GetScheduleConfig().TombstoneStoreRetentionDays = 0
+	c.Assert(cluster.putStoreLocked(stores[3]), IsNil)
+	cluster.cleanupTombstoneStores()
+	c.Assert(cluster.GetStoreCount(), Equals, 3)
+
+	removed, err = cluster.RemoveTombStoneRecordsOlderThan(time.Hour, false)
+	c.Assert(err, IsNil)
+	c.Assert(removed, DeepEquals, []uint64{2, 4})
+	c.Assert(cluster.GetStoreCount(), Equals, 1)
+}
+
 func (s *testClusterInfoSuite) TestReuseAddress(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
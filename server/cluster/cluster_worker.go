@@ -16,6 +16,7 @@ package cluster
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/errors"
@@ -25,6 +26,7 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/events"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/versioninfo"
 	"go.uber.org/zap"
@@ -199,6 +201,8 @@ func (c *RaftCluster) HandleReportSplit(request *pdpb.ReportSplitRequest) (*pdpb
 	log.Info("region split, generate new region",
 		zap.Uint64("region-id", originRegion.GetId()),
 		logutil.ZapRedactStringer("region-meta", core.RegionToHexMeta(left)))
+	c.publishRegionSplit(originRegion.GetId(), []*metapb.Region{left})
+	c.GetGenealogy().RecordSplit(originRegion.GetId(), []uint64{left.GetId()}, [][]byte{left.GetEndKey()})
 	return &pdpb.ReportSplitResponse{}, nil
 }
 
@@ -221,5 +225,25 @@ func (c *RaftCluster) HandleBatchReportSplit(request *pdpb.ReportBatchSplitReque
 		zap.Uint64("region-id", originRegion.GetId()),
 		zap.Stringer("origin", hrm),
 		zap.Int("total", last))
+	c.publishRegionSplit(originRegion.GetId(), regions[:last])
+	childIDs := make([]uint64, 0, last)
+	for _, region := range regions[:last] {
+		childIDs = append(childIDs, region.GetId())
+	}
+	splitKeys := make([][]byte, 0, last)
+	for _, region := range regions[1:] {
+		splitKeys = append(splitKeys, region.GetStartKey())
+	}
+	c.GetGenealogy().RecordSplit(originRegion.GetId(), childIDs, splitKeys)
 	return &pdpb.ReportBatchSplitResponse{}, nil
 }
+
+// publishRegionSplit publishes the split event of the origin region.
+func (c *RaftCluster) publishRegionSplit(originID uint64, newRegions []*metapb.Region) {
+	ids := make([]uint64, 0, len(newRegions))
+	for _, region := range newRegions {
+		ids = append(ids, region.GetId())
+	}
+	message := fmt.Sprintf("region %d is split, new regions %v", originID, ids)
+	c.eventHub.Publish(events.NewClusterEvent(events.RegionSplit, message).WithRegion(originID))
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/mock/mockid"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/events"
+	"github.com/tikv/pd/server/genealogy"
 	_ "github.com/tikv/pd/server/schedulers"
 	"github.com/tikv/pd/server/storage"
 )
@@ -45,10 +47,15 @@ func (s *testClusterWorkerSuite) TestReportSplit(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
 	cluster := newTestRaftCluster(s.ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend(), core.NewBasicCluster())
+	cluster.eventHub = events.NewHub()
+	sub := cluster.GetEventHub().Subscribe(1)
 	left := &metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("b")}
 	right := &metapb.Region{Id: 2, StartKey: []byte("b"), EndKey: []byte("c")}
 	_, err = cluster.HandleReportSplit(&pdpb.ReportSplitRequest{Left: left, Right: right})
 	c.Assert(err, IsNil)
+	e := <-sub.Events()
+	c.Assert(e.Type, Equals, events.RegionSplit)
+	c.Assert(e.RegionID, Equals, uint64(2))
 	_, err = cluster.HandleReportSplit(&pdpb.ReportSplitRequest{Left: right, Right: left})
 	c.Assert(err, NotNil)
 }
@@ -66,3 +73,59 @@ func (s *testClusterWorkerSuite) TestReportBatchSplit(c *C) {
 	_, err = cluster.HandleBatchReportSplit(&pdpb.ReportBatchSplitRequest{Regions: regions})
 	c.Assert(err, IsNil)
 }
+
+func (s *testClusterWorkerSuite) TestRegionGenealogy(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(s.ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend(), core.NewBasicCluster())
+	cluster.coordinator = newCoordinator(s.ctx, cluster, nil)
+	cluster.genealogy, err = genealogy.NewGenealogy(cluster.storage, 100)
+	c.Assert(err, IsNil)
+	for _, store := range newTestStores(1, "2.0.0") {
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	newRegion := func(id uint64, startKey, endKey string, version uint64) *metapb.Region {
+		return &metapb.Region{
+			Id:          id,
+			StartKey:    []byte(startKey),
+			EndKey:      []byte(endKey),
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: version},
+			Peers:       []*metapb.Peer{{Id: id + 100, StoreId: 1}},
+		}
+	}
+
+	// region 3 is split into 1, 2 and 3.
+	regions := []*metapb.Region{
+		newRegion(1, "", "a", 2),
+		newRegion(2, "a", "b", 2),
+		newRegion(3, "b", "", 2),
+	}
+	_, err = cluster.HandleBatchReportSplit(&pdpb.ReportBatchSplitRequest{Regions: regions})
+	c.Assert(err, IsNil)
+	for _, region := range regions {
+		c.Assert(cluster.processRegionHeartbeat(core.NewRegionInfo(region, region.GetPeers()[0])), IsNil)
+	}
+	// region 2 is merged into region 3.
+	merged := newRegion(3, "a", "", 3)
+	c.Assert(cluster.processRegionHeartbeat(core.NewRegionInfo(merged, merged.GetPeers()[0])), IsNil)
+	// region 1 is split again.
+	_, err = cluster.HandleReportSplit(&pdpb.ReportSplitRequest{Left: newRegion(4, "", "0", 3), Right: newRegion(1, "0", "a", 3)})
+	c.Assert(err, IsNil)
+
+	chain := cluster.GetGenealogy().GetChain(2)
+	c.Assert(chain.Ancestors, HasLen, 1)
+	c.Assert(chain.Ancestors[0].Type, Equals, genealogy.Split)
+	c.Assert(chain.Ancestors[0].ParentID, Equals, uint64(3))
+	c.Assert(chain.Ancestors[0].ChildIDs, DeepEquals, []uint64{1, 2})
+	c.Assert(chain.Ancestors[0].SplitKeys, DeepEquals, []string{"61", "62"})
+	c.Assert(chain.Descendants, HasLen, 1)
+	c.Assert(chain.Descendants[0].Type, Equals, genealogy.Merge)
+	c.Assert(chain.Descendants[0].SourceIDs, DeepEquals, []uint64{2})
+	c.Assert(chain.Descendants[0].ResultID, Equals, uint64(3))
+
+	chain = cluster.GetGenealogy().GetChain(4)
+	c.Assert(chain.Ancestors, HasLen, 2)
+	c.Assert(chain.Ancestors[1].ParentID, Equals, uint64(1))
+	c.Assert(chain.Ancestors[1].ChildIDs, DeepEquals, []uint64{4})
+	c.Assert(chain.Ancestors[1].SplitKeys, DeepEquals, []string{"30"})
+}
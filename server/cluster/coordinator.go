@@ -17,7 +17,9 @@ package cluster
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -35,6 +37,7 @@ import (
 	"github.com/tikv/pd/server/schedule/checker"
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedulers"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage"
 	"go.uber.org/zap"
@@ -399,6 +402,9 @@ func (c *coordinator) run() {
 		log.Error("cannot persist schedule config", errs.ZapError(err))
 	}
 
+	// The scheduler plugins are added after all built-in schedulers.
+	c.addPluginSchedulers()
+
 	c.wg.Add(3)
 	// Starts to patrol regions.
 	go c.patrolRegions()
@@ -609,9 +615,36 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	c.Lock()
 	defer c.Unlock()
 
+	if err := c.startSchedulerLocked(scheduler); err != nil {
+		return err
+	}
+	c.cluster.opt.AddSchedulerCfg(scheduler.GetType(), args)
+	return nil
+}
+
+// addPluginSchedulers adds the schedulers of all registered scheduler plugins.
+// They are not recorded in the schedule config since they can not be created
+// by the scheduler type.
+func (c *coordinator) addPluginSchedulers() {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, p := range schedule.GetPlugins() {
+		s := schedulers.NewPluginScheduler(c.opController, p)
+		if err := c.startSchedulerLocked(s); err != nil {
+			log.Error("can not add scheduler plugin", zap.String("scheduler-name", s.GetName()), errs.ZapError(err))
+			continue
+		}
+		log.Info("add scheduler plugin", zap.String("scheduler-name", s.GetName()))
+	}
+}
+
+func (c *coordinator) startSchedulerLocked(scheduler schedule.Scheduler) error {
 	if _, ok := c.schedulers[scheduler.GetName()]; ok {
 		return errs.ErrSchedulerExisted.FastGenByArgs()
 	}
+	// Always wrap the scheduler so that its time window can be changed on the fly.
+	scheduler = schedule.WithTimeWindow(c.loadSchedulerTimeWindow(scheduler.GetName()))(scheduler)
 
 	s := newScheduleController(c, scheduler)
 	if err := s.Prepare(c.cluster); err != nil {
@@ -621,7 +654,6 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	c.wg.Add(1)
 	go c.runScheduler(s)
 	c.schedulers[s.GetName()] = s
-	c.cluster.opt.AddSchedulerCfg(s.GetType(), args)
 	return nil
 }
 
@@ -651,6 +683,10 @@ func (c *coordinator) removeScheduler(name string) error {
 		log.Error("can not remove the scheduler config", errs.ZapError(err))
 		return err
 	}
+	if err := c.cluster.storage.RemoveScheduleTimeWindow(name); err != nil {
+		log.Error("can not remove the scheduler time window", errs.ZapError(err))
+		return err
+	}
 
 	s.Stop()
 	schedulerStatusGauge.WithLabelValues(name, "allow").Set(0)
@@ -711,6 +747,84 @@ func (c *coordinator) pauseOrResumeScheduler(name string, t int64) error {
 	return err
 }
 
+// pauseScheduler pauses the scheduler for the duration. The paused scheduler
+// keeps its state and skips scheduling until it is resumed or the duration
+// expires.
+func (c *coordinator) pauseScheduler(name string, duration time.Duration) error {
+	if duration <= 0 {
+		return errs.ErrSchedulerConfig.FastGenByArgs("pause duration")
+	}
+	return c.pauseOrResumeScheduler(name, int64(math.Ceil(duration.Seconds())))
+}
+
+// resumeScheduler resumes the paused scheduler.
+func (c *coordinator) resumeScheduler(name string) error {
+	return c.pauseOrResumeScheduler(name, 0)
+}
+
+// loadSchedulerTimeWindow loads the persisted time window of the scheduler.
+// The zero TimeWindow is returned if there is no valid one.
+func (c *coordinator) loadSchedulerTimeWindow(name string) schedule.TimeWindow {
+	var tw schedule.TimeWindow
+	data, err := c.cluster.storage.LoadScheduleTimeWindow(name)
+	if err != nil {
+		log.Error("can not load the scheduler time window", zap.String("scheduler-name", name), errs.ZapError(err))
+		return tw
+	}
+	if len(data) == 0 {
+		return tw
+	}
+	if err := json.Unmarshal([]byte(data), &tw); err != nil {
+		log.Error("invalid scheduler time window", zap.String("scheduler-name", name), zap.String("time-window", data), errs.ZapError(err))
+		return schedule.TimeWindow{}
+	}
+	return tw
+}
+
+// setSchedulerTimeWindow restricts the scheduler to schedule only inside the
+// time window, and persists the time window. The zero TimeWindow removes the
+// restriction.
+func (c *coordinator) setSchedulerTimeWindow(name string, tw schedule.TimeWindow) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.cluster == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	if tw.IsZero() {
+		if err := c.cluster.storage.RemoveScheduleTimeWindow(name); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(tw)
+		if err != nil {
+			return errs.ErrJSONMarshal.Wrap(err).FastGenWithCause()
+		}
+		if err := c.cluster.storage.SaveScheduleTimeWindow(name, data); err != nil {
+			return err
+		}
+	}
+	schedule.WithTimeWindow(tw)(s.Scheduler)
+	return nil
+}
+
+// getSchedulerTimeWindow returns the time window of the scheduler.
+func (c *coordinator) getSchedulerTimeWindow(name string) (schedule.TimeWindow, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return schedule.TimeWindow{}, errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return schedule.TimeWindow{}, errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	return schedule.GetTimeWindow(s.Scheduler), nil
+}
+
 func (c *coordinator) isSchedulerPaused(name string) (bool, error) {
 	c.RLock()
 	defer c.RUnlock()
@@ -16,12 +16,15 @@ package cluster
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/eraftpb"
@@ -37,6 +40,7 @@ import (
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/labeler"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/tikv/pd/server/schedulers"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage"
@@ -311,6 +315,59 @@ func (s *testCoordinatorSuite) checkRegion(c *C, tc *testCluster, co *coordinato
 	}
 }
 
+func (s *testCoordinatorSuite) TestSimulateRule(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	for i := uint64(1); i <= 5; i++ {
+		c.Assert(tc.addRegionStore(i, 1), IsNil)
+	}
+	c.Assert(tc.addLeaderRegion(1, 1, 2, 3), IsNil)
+	c.Assert(tc.addLeaderRegion(2, 2, 3, 4), IsNil)
+	c.Assert(tc.addLeaderRegion(3, 3, 4, 5), IsNil)
+	// The rule adds a learner to the region 1 and 2.
+	rule := &placement.Rule{
+		GroupID:     "pd",
+		ID:          "learner",
+		StartKeyHex: hex.EncodeToString(newTestRegionMeta(1).GetStartKey()),
+		EndKeyHex:   hex.EncodeToString(newTestRegionMeta(3).GetStartKey()),
+		Role:        placement.Learner,
+		Count:       1,
+	}
+
+	report, err := tc.SimulateRule(rule.Clone())
+	c.Assert(err, IsNil)
+	c.Assert(report.AffectedRegionCount, Equals, 2)
+	c.Assert(report.RequiredOperatorCount, Equals, 2)
+	c.Assert(report.EstimatedDataMovementBytes, Equals, uint64(2*10*units.MiB))
+	c.Assert(report.SampleRegions, HasLen, 2)
+	for i, region := range report.SampleRegions {
+		c.Assert(region.ID, Equals, uint64(i+1))
+		c.Assert(region.Operator, Not(Equals), "")
+	}
+	// The rule is not applied.
+	c.Assert(tc.GetRuleManager().GetRule("pd", "learner"), IsNil)
+	for i := uint64(1); i <= 3; i++ {
+		s.checkRegion(c, tc, co, i, 0)
+	}
+
+	// The report matches the operators created after the rule is applied.
+	c.Assert(tc.GetRuleManager().SetRule(rule.Clone()), IsNil)
+	opCount := 0
+	for i := uint64(1); i <= 3; i++ {
+		if ops := co.checkers.CheckRegion(tc.GetRegion(i)); len(ops) > 0 {
+			c.Assert(ops[0].Len(), Equals, 1)
+			c.Assert(ops[0].Step(0), FitsTypeOf, operator.AddLearner{})
+			opCount++
+		}
+	}
+	c.Assert(opCount, Equals, report.RequiredOperatorCount)
+
+	// The invalid rule is rejected.
+	_, err = tc.SimulateRule(&placement.Rule{GroupID: "pd", ID: "invalid", Role: placement.Leader, Count: 2})
+	c.Assert(err, NotNil)
+}
+
 func (s *testCoordinatorSuite) TestCheckRegion(c *C) {
 	tc, co, cleanup := prepare(nil, nil, nil, c)
 	hbStreams, opt := co.hbStreams, tc.opt
@@ -352,6 +409,51 @@ func (s *testCoordinatorSuite) TestCheckRegion(c *C) {
 	s.checkRegion(c, tc, co, 1, 0)
 }
 
+func (s *testCoordinatorSuite) TestPatchStoreLabels(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+	rep := tc.opt.GetReplicationConfig().Clone()
+	rep.LocationLabels = []string{"zone"}
+	tc.opt.SetReplicationConfig(rep)
+	c.Assert(tc.GetRuleManager().SetRule(&placement.Rule{
+		GroupID: "pd",
+		ID:      "default",
+		Role:    placement.Voter,
+		Count:   3,
+		LabelConstraints: []placement.LabelConstraint{
+			{Key: "zone", Op: placement.NotIn, Values: []string{"z4"}},
+		},
+	}), IsNil)
+
+	for i := uint64(1); i <= 4; i++ {
+		c.Assert(tc.addRegionStore(i, 1), IsNil)
+		c.Assert(tc.PatchStoreLabels(i, map[string]string{"zone": fmt.Sprintf("z%d", i+4)}), IsNil)
+	}
+	c.Assert(tc.addLeaderRegion(1, 1, 2, 3), IsNil)
+	s.checkRegion(c, tc, co, 1, 0)
+
+	// The labels not in the location labels are rejected.
+	c.Assert(tc.PatchStoreLabels(3, map[string]string{"host": "h3"}), NotNil)
+	c.Assert(tc.PatchStoreLabels(3, map[string]string{"zone": "z 4"}), NotNil)
+	c.Assert(tc.PatchStoreLabels(5, map[string]string{"zone": "z4"}), NotNil)
+	c.Assert(tc.GetStore(3).GetLabelValue("zone"), Equals, "z7")
+
+	// The peer on the store patched to z4 is replaced immediately.
+	c.Assert(tc.PatchStoreLabels(3, map[string]string{"zone": "z4"}), IsNil)
+	c.Assert(tc.GetStore(3).GetLabelValue("zone"), Equals, "z4")
+	meta := &metapb.Store{}
+	ok, err := tc.storage.LoadStore(3, meta)
+	c.Assert(ok, IsTrue)
+	c.Assert(err, IsNil)
+	c.Assert(meta.GetLabels(), DeepEquals, []*metapb.StoreLabel{{Key: "zone", Value: "z4"}})
+	s.checkRegion(c, tc, co, 1, 1)
+	testutil.CheckAddPeer(c, co.opController.GetOperator(1), operator.OpReplica, 4)
+
+	// The empty value deletes the label.
+	c.Assert(tc.PatchStoreLabels(3, map[string]string{"zone": ""}), IsNil)
+	c.Assert(tc.GetStore(3).GetLabels(), HasLen, 0)
+}
+
 func (s *testCoordinatorSuite) TestCheckRegionWithScheduleDeny(c *C) {
 	tc, co, cleanup := prepare(nil, nil, nil, c)
 
@@ -860,6 +962,85 @@ func (s *testCoordinatorSuite) TestRemoveScheduler(c *C) {
 	co.wg.Wait()
 }
 
+func (s *testCoordinatorSuite) TestPauseScheduler(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+
+	c.Assert(co.removeScheduler(schedulers.BalanceLeaderName), IsNil)
+	c.Assert(co.removeScheduler(schedulers.BalanceRegionName), IsNil)
+	c.Assert(co.removeScheduler(schedulers.HotRegionName), IsNil)
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+	c.Assert(tc.addLeaderStore(2, 1), IsNil)
+	c.Assert(tc.addLeaderStore(3, 1), IsNil)
+
+	sl, err := schedule.CreateScheduler(schedulers.ShuffleLeaderType, co.opController, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(schedulers.ShuffleLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	c.Assert(co.addScheduler(sl), IsNil)
+	c.Assert(co.pauseScheduler(sl.GetName(), 0), NotNil)
+	c.Assert(co.pauseScheduler("unknown", time.Second), NotNil)
+	c.Assert(co.pauseScheduler(sl.GetName(), 3*time.Second), IsNil)
+	isPaused, err := co.isSchedulerPaused(sl.GetName())
+	c.Assert(err, IsNil)
+	c.Assert(isPaused, IsTrue)
+
+	// No operator is generated during the pause.
+	c.Assert(tc.addLeaderRegion(1, 1, 2, 3), IsNil)
+	time.Sleep(time.Second)
+	c.Assert(co.opController.GetOperator(1), IsNil)
+	// The scheduler is resumed automatically after the duration.
+	waitOperator(c, co, 1)
+	isPaused, err = co.isSchedulerPaused(sl.GetName())
+	c.Assert(err, IsNil)
+	c.Assert(isPaused, IsFalse)
+
+	// The scheduler can be resumed manually.
+	c.Assert(co.pauseScheduler(sl.GetName(), time.Minute), IsNil)
+	c.Assert(tc.addLeaderRegion(2, 2, 1, 3), IsNil)
+	time.Sleep(time.Second)
+	c.Assert(co.opController.GetOperator(2), IsNil)
+	c.Assert(co.resumeScheduler(sl.GetName()), IsNil)
+	waitOperator(c, co, 2)
+}
+
+func (s *testCoordinatorSuite) TestSchedulerTimeWindow(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	hbStreams := co.hbStreams
+	defer cleanup()
+
+	loc := time.UTC
+	tw := schedule.TimeWindow{
+		Start:    time.Date(0, 1, 1, 12, 0, 0, 0, loc),
+		End:      time.Date(0, 1, 1, 16, 0, 0, 0, loc),
+		Location: loc,
+	}
+	c.Assert(co.setSchedulerTimeWindow("unknown", tw), NotNil)
+	c.Assert(co.setSchedulerTimeWindow(schedulers.BalanceLeaderName, tw), IsNil)
+	got, err := co.getSchedulerTimeWindow(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, tw)
+
+	// The time window survives the restart.
+	co.stop()
+	co.wg.Wait()
+	co = newCoordinator(s.ctx, tc.RaftCluster, hbStreams)
+	co.run()
+	got, err = co.getSchedulerTimeWindow(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(got.Contains(time.Date(2021, 6, 1, 2, 0, 0, 0, loc)), IsFalse)
+	c.Assert(got.Contains(time.Date(2021, 6, 1, 13, 0, 0, 0, loc)), IsTrue)
+	got, err = co.getSchedulerTimeWindow(schedulers.BalanceRegionName)
+	c.Assert(err, IsNil)
+	c.Assert(got.IsZero(), IsTrue)
+
+	// The time window is removed along with the scheduler.
+	c.Assert(co.removeScheduler(schedulers.BalanceLeaderName), IsNil)
+	data, err := tc.storage.LoadScheduleTimeWindow(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(data, Equals, "")
+	co.stop()
+	co.wg.Wait()
+}
+
 func (s *testCoordinatorSuite) TestRestart(c *C) {
 	tc, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
 		// Turn off balance, we test add replica only.
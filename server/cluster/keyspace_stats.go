@@ -0,0 +1,46 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/docker/go-units"
+	"github.com/tikv/pd/server/schedule/placement"
+)
+
+// KeyspaceStats is the capacity consumed by a keyspace.
+type KeyspaceStats struct {
+	RegionCount          int   `json:"region_count"`
+	ApproximateSizeBytes int64 `json:"approximate_size_bytes"`
+	ApproximateKeys      int64 `json:"approximate_keys"`
+	// StoreDistribution is the number of the replicas of the keyspace regions
+	// on each store.
+	StoreDistribution map[uint64]int `json:"store_distribution"`
+}
+
+// GetKeyspaceStats sums up the regions in the key range of the keyspace. A
+// region crossing the boundary of the keyspace is counted as a whole.
+func (c *RaftCluster) GetKeyspaceStats(keyspaceID uint32) *KeyspaceStats {
+	startKey, endKey := placement.KeyspaceKeyRange(keyspaceID)
+	stats := &KeyspaceStats{StoreDistribution: make(map[uint64]int)}
+	for _, region := range c.ScanRegions(startKey, endKey, -1) {
+		stats.RegionCount++
+		stats.ApproximateSizeBytes += region.GetApproximateSize() * units.MiB
+		stats.ApproximateKeys += region.GetApproximateKeys()
+		for _, peer := range region.GetPeers() {
+			stats.StoreDistribution[peer.GetStoreId()]++
+		}
+	}
+	return stats
+}
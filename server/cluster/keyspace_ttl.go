@@ -0,0 +1,102 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/kvproto/pkg/tikvpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/grpcutil"
+	"github.com/tikv/pd/pkg/logutil"
+	"github.com/tikv/pd/server/core"
+)
+
+// keyspaceTTLInterval is the interval to clean up the expired regions of the
+// keyspaces with TTL.
+var keyspaceTTLInterval = time.Minute
+
+const deleteRangeTimeout = 30 * time.Second
+
+// regionRangeDeleter deletes a key range of a region by sending KvDeleteRange
+// to the leader of the region, which deletes it through Raft. The request is
+// rejected by the leader if the region is changed since the heartbeat.
+type regionRangeDeleter struct {
+	cluster *RaftCluster
+	tlsCfg  *tls.Config
+}
+
+// DeleteRange implements keyspace.RangeDeleter.
+func (d *regionRangeDeleter) DeleteRange(ctx context.Context, region *core.RegionInfo, startKey, endKey []byte) error {
+	leader := region.GetLeader()
+	if leader == nil {
+		return errors.Errorf("region %d has no leader", region.GetID())
+	}
+	store := d.cluster.GetStore(leader.GetStoreId())
+	if store == nil {
+		return errors.Errorf("store %d of the leader of region %d is not found", leader.GetStoreId(), region.GetID())
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteRangeTimeout)
+	defer cancel()
+	conn, err := grpcutil.GetClientConn(ctx, "http://"+store.GetAddress(), d.tlsCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := tikvpb.NewTikvClient(conn).KvDeleteRange(ctx, &kvrpcpb.DeleteRangeRequest{
+		Context: &kvrpcpb.Context{
+			RegionId:    region.GetID(),
+			RegionEpoch: region.GetRegionEpoch(),
+			Peer:        leader,
+		},
+		StartKey: startKey,
+		EndKey:   endKey,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if resp.GetRegionError() != nil {
+		return errors.Errorf("region %d: %s", region.GetID(), resp.GetRegionError().String())
+	}
+	if resp.GetError() != "" {
+		return errors.Errorf("region %d: %s", region.GetID(), resp.GetError())
+	}
+	return nil
+}
+
+func (c *RaftCluster) runKeyspaceTTLJob() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(keyspaceTTLInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Info("keyspace ttl background jobs has been stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.keyspaceTTL.RunOnce(c.ctx, c.core, time.Now()); err != nil {
+				log.Error("failed to clean up the expired regions of keyspaces", errs.ZapError(err))
+			}
+		}
+	}
+}
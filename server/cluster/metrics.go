@@ -25,6 +25,22 @@ var (
 			Help:      "Status of the cluster.",
 		}, []string{"name"})
 
+	snapshotSendingCountGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "snapshot_sending_count",
+			Help:      "The number of the sending snapshots reported by the stores.",
+		})
+
+	snapshotSendingBytesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "snapshot_sending_bytes",
+			Help:      "The estimated size of the snapshots sent by the running operators.",
+		})
+
 	regionEventCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "pd",
@@ -84,6 +100,8 @@ var (
 func init() {
 	prometheus.MustRegister(regionEventCounter)
 	prometheus.MustRegister(healthStatusGauge)
+	prometheus.MustRegister(snapshotSendingCountGauge)
+	prometheus.MustRegister(snapshotSendingBytesGauge)
 	prometheus.MustRegister(schedulerStatusGauge)
 	prometheus.MustRegister(hotSpotStatusGauge)
 	prometheus.MustRegister(patrolCheckRegionsGauge)
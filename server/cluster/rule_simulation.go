@@ -0,0 +1,121 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/docker/go-units"
+	"github.com/tikv/pd/pkg/cache"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/checker"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/placement"
+)
+
+// ruleSimulationSampleLimit is the max number of the affected regions listed
+// in the report of a rule simulation.
+const ruleSimulationSampleLimit = 16
+
+// RuleSimulationReport is the impact of a placement rule on the cluster if it
+// is applied.
+type RuleSimulationReport struct {
+	// AffectedRegionCount is the number of the regions not satisfying the
+	// rules after the rule is applied.
+	AffectedRegionCount int `json:"affected_region_count"`
+	// RequiredOperatorCount is the number of the operators the rule checker
+	// creates for the affected regions.
+	RequiredOperatorCount int `json:"required_operator_count"`
+	// EstimatedDataMovementBytes is the size of the replicas the operators add.
+	EstimatedDataMovementBytes uint64                  `json:"estimated_data_movement_bytes"`
+	SampleRegions              []*RuleSimulationRegion `json:"sample_regions"`
+}
+
+// RuleSimulationRegion is an affected region of a rule simulation.
+type RuleSimulationRegion struct {
+	ID       uint64 `json:"id"`
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// Operator is the description of the operator created for the region, it
+	// is empty if the rule checker can not fix the region now.
+	Operator string `json:"operator,omitempty"`
+}
+
+// ruleSimulationCluster is the cluster seen through the simulated rules.
+type ruleSimulationCluster struct {
+	*RaftCluster
+	ruleManager *placement.RuleManager
+}
+
+func (c *ruleSimulationCluster) GetRuleManager() *placement.RuleManager {
+	return c.ruleManager
+}
+
+// SimulateRule evaluates the impact of inserting or updating the placement
+// rule without applying it. The regions in the key ranges of both the rule
+// and the rule it replaces are checked by the rule checker as if the rule were
+// applied.
+func (c *RaftCluster) SimulateRule(rule *placement.Rule) (*RuleSimulationReport, error) {
+	ruleManager := c.GetRuleManager()
+	oldRule := ruleManager.GetRule(rule.GroupID, rule.ID)
+	sim, err := ruleManager.SimulateRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	cluster := &ruleSimulationCluster{RaftCluster: c, ruleManager: sim}
+	ruleChecker := checker.NewRuleChecker(cluster, sim, cache.NewDefaultCache(checker.DefaultCacheSize))
+
+	regions := c.ScanRegions(rule.StartKey, rule.EndKey, -1)
+	if oldRule != nil {
+		regions = append(regions, c.ScanRegions(oldRule.StartKey, oldRule.EndKey, -1)...)
+	}
+	report := &RuleSimulationReport{}
+	checked := make(map[uint64]struct{}, len(regions))
+	for _, region := range regions {
+		if _, ok := checked[region.GetID()]; ok {
+			continue
+		}
+		checked[region.GetID()] = struct{}{}
+		if sim.FitRegion(cluster, region).IsSatisfied() {
+			continue
+		}
+		report.AffectedRegionCount++
+		sample := &RuleSimulationRegion{
+			ID:       region.GetID(),
+			StartKey: core.HexRegionKeyStr(region.GetStartKey()),
+			EndKey:   core.HexRegionKeyStr(region.GetEndKey()),
+		}
+		if op := ruleChecker.Check(region); op != nil {
+			report.RequiredOperatorCount++
+			report.EstimatedDataMovementBytes += uint64(addedPeerCount(op)) * uint64(region.GetApproximateSize()) * units.MiB
+			sample.Operator = op.String()
+		}
+		if len(report.SampleRegions) < ruleSimulationSampleLimit {
+			report.SampleRegions = append(report.SampleRegions, sample)
+		}
+	}
+	return report, nil
+}
+
+// addedPeerCount returns the number of the peers the operator adds, each of
+// which receives a snapshot of the region.
+func addedPeerCount(op *operator.Operator) int {
+	count := 0
+	for i := 0; i < op.Len(); i++ {
+		switch op.Step(i).(type) {
+		case operator.AddPeer, operator.AddLearner:
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// StoreSnapshotStats is the snapshots reported by the heartbeat of a store.
+type StoreSnapshotStats struct {
+	StoreID        uint64 `json:"store_id"`
+	SendingCount   int    `json:"sending_count"`
+	ReceivingCount int    `json:"receiving_count"`
+}
+
+// SnapshotStats is the snapshots in flight of the cluster.
+type SnapshotStats struct {
+	// SnapshotSendingCount is the sum of the sending snapshots reported by the
+	// store heartbeats.
+	SnapshotSendingCount int `json:"snapshot_sending_count"`
+	// SnapshotSendingBytes is the size of the snapshots sent by the running
+	// operators, estimated by the approximate size of the regions.
+	SnapshotSendingBytes uint64               `json:"snapshot_sending_bytes"`
+	Stores               []StoreSnapshotStats `json:"stores"`
+}
+
+// GetSnapshotStats returns the snapshots in flight of the cluster.
+func (c *RaftCluster) GetSnapshotStats() *SnapshotStats {
+	stats := &SnapshotStats{
+		SnapshotSendingBytes: c.GetOperatorController().SnapshotBytesInFlight(),
+		Stores:               []StoreSnapshotStats{},
+	}
+	for _, store := range c.GetStores() {
+		if store.IsRemoved() {
+			continue
+		}
+		storeStats := StoreSnapshotStats{
+			StoreID:        store.GetID(),
+			SendingCount:   int(store.GetSendingSnapCount()),
+			ReceivingCount: int(store.GetReceivingSnapCount()),
+		}
+		stats.SnapshotSendingCount += storeStats.SendingCount
+		stats.Stores = append(stats.Stores, storeStats)
+	}
+	return stats
+}
+
+func (c *RaftCluster) collectSnapshotMetrics() {
+	stats := c.GetSnapshotStats()
+	snapshotSendingCountGauge.Set(float64(stats.SnapshotSendingCount))
+	snapshotSendingBytesGauge.Set(float64(stats.SnapshotSendingBytes))
+}
+
+func (c *RaftCluster) resetSnapshotMetrics() {
+	snapshotSendingCountGauge.Set(0)
+	snapshotSendingBytesGauge.Set(0)
+}
@@ -30,8 +30,10 @@ import (
 	"github.com/tikv/pd/pkg/encryption"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/grpcutil"
+	"github.com/tikv/pd/pkg/ipfilter"
 	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/pkg/metricutil"
+	"github.com/tikv/pd/pkg/rbac"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/core/storelimit"
 	"github.com/tikv/pd/server/versioninfo"
@@ -40,11 +42,13 @@ import (
 	"github.com/coreos/go-semver/semver"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
 	"go.etcd.io/etcd/embed"
 	"go.etcd.io/etcd/pkg/transport"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 )
 
 // Config is the pd server configuration.
@@ -66,6 +70,23 @@ type Config struct {
 	ForceNewCluster   bool   `json:"force-new-cluster"`
 	EnableGRPCGateway bool   `json:"enable-grpc-gateway"`
 
+	// EnableGRPCWeb serves the gRPC-Web requests of the browsers on
+	// GRPCWebAddr, which are translated to the gRPC requests. The TLS config
+	// is the same as the gRPC server.
+	EnableGRPCWeb bool `toml:"enable-grpc-web" json:"enable-grpc-web"`
+	// GRPCWebAddr is the address to listen for the gRPC-Web requests.
+	GRPCWebAddr string `toml:"grpc-web-addr" json:"grpc-web-addr"`
+	// GRPCWebAllowedOrigins are the origins of the web pages allowed to send
+	// the cross-origin gRPC-Web requests, such as "https://dashboard.example.com",
+	// "*" allows any origin. Only the same-origin requests are allowed if it is
+	// empty.
+	GRPCWebAllowedOrigins []string `toml:"grpc-web-allowed-origins" json:"grpc-web-allowed-origins"`
+
+	// RPCDeadlines are the max deadlines of the unary gRPC methods, such as
+	// "GetRegion". The longer deadlines provided by the clients are truncated,
+	// and they are used if the clients provide no deadline.
+	RPCDeadlines map[string]typeutil.Duration `toml:"rpc-deadlines" json:"rpc-deadlines"`
+
 	InitialCluster      string `toml:"initial-cluster" json:"initial-cluster"`
 	InitialClusterState string `toml:"initial-cluster-state" json:"initial-cluster-state"`
 	InitialClusterToken string `toml:"initial-cluster-token" json:"initial-cluster-token"`
@@ -95,6 +116,31 @@ type Config struct {
 	// be automatically clamped to the range.
 	TSOUpdatePhysicalInterval typeutil.Duration `toml:"tso-update-physical-interval" json:"tso-update-physical-interval"`
 
+	// TSOClockDriftWarningThreshold is the max drift between the system time and the physical
+	// part of TSO before a warning is logged. The drift usually happens when the system time
+	// falls back, e.g., due to NTP corrections.
+	TSOClockDriftWarningThreshold typeutil.Duration `toml:"tso-clock-drift-warning-threshold" json:"tso-clock-drift-warning-threshold"`
+	// TSOClockDriftFatalThreshold is the max drift between the system time and the physical part
+	// of TSO before the TSO allocator steps down from the leadership. 0 means never step down.
+	TSOClockDriftFatalThreshold typeutil.Duration `toml:"tso-clock-drift-fatal-threshold" json:"tso-clock-drift-fatal-threshold"`
+
+	// TSOClientRateLimit is the max number of TSO requests per second of every client,
+	// which is identified by the CN of its certificate or its IP address. The requests
+	// forwarded by a follower are limited as the ones of the follower. 0 means no limit.
+	TSOClientRateLimit float64 `toml:"tso-client-rate-limit" json:"tso-client-rate-limit"`
+	// TSOClientBurst is the max burst of TSO requests of every client.
+	TSOClientBurst int `toml:"tso-client-burst" json:"tso-client-burst"`
+
+	// TSOHLCMode makes the TSO allocator work as a hybrid logical clock, which advances the physical
+	// part with the system time on allocation instead of waiting for the periodical update, and limits
+	// the logical part to 16 bits. It's enough for causally consistent reads rather than linearizable ones.
+	TSOHLCMode bool `toml:"tso-hlc-mode" json:"tso-hlc-mode"`
+	// TSOHLCCompatible keeps the standard layout of the timestamps in HLC mode, which has the physical
+	// part in the high 46 bits and the logical part in the low 18 bits, for the clients which parse the
+	// physical time from the timestamps. Otherwise the timestamps are encoded in the HLC layout, which
+	// has the physical part in the high 48 bits and the logical part in the low 16 bits.
+	TSOHLCCompatible bool `toml:"tso-hlc-compatible" json:"tso-hlc-compatible"`
+
 	// EnableLocalTSO is used to enable the Local TSO Allocator feature,
 	// which allows the PD server to generate Local TSO for certain DC-level transactions.
 	// To make this feature meaningful, user has to set the "zone" label for the PD server
@@ -228,6 +274,8 @@ const (
 
 	defaultLeaderPriorityCheckInterval = time.Minute
 
+	defaultJWTExpiry = time.Hour
+
 	defaultUseRegionStorage                 = true
 	defaultTraceRegionFlow                  = true
 	defaultFlowRoundByDigit                 = 3 // KB
@@ -235,8 +283,12 @@ const (
 	defaultMaxResetTSGap                    = 24 * time.Hour
 	defaultMinResolvedTSPersistenceInterval = 0
 	defaultKeyType                          = "table"
+	defaultEventBufferSize                  = 1024
+	defaultMaxGenealogyEntries              = 100000
+	defaultRegionTrafficSamples             = 360
 
 	defaultStrictlyMatchLabel   = false
+	defaultRackLabelKey         = "rack"
 	defaultEnablePlacementRules = true
 	defaultEnableGRPCGateway    = true
 	defaultDisableErrorVerbose  = true
@@ -253,6 +305,8 @@ const (
 	maxTSOUpdatePhysicalInterval     = 10 * time.Second
 	minTSOUpdatePhysicalInterval     = 50 * time.Millisecond
 
+	defaultTSOClockDriftWarningThreshold = 500 * time.Millisecond
+
 	defaultLogFormat = "text"
 )
 
@@ -444,10 +498,57 @@ func (c *Config) Validate() error {
 	if !strings.HasPrefix(rel, "..") {
 		return errors.New("log directory shouldn't be the subdirectory of data directory")
 	}
+	hasAdminToken := false
+	for _, role := range c.Security.APITokens {
+		r, err := rbac.ParseRole(role)
+		if err != nil {
+			return err
+		}
+		hasAdminToken = hasAdminToken || r == rbac.Admin
+	}
+	// The users are created with an admin token, otherwise the API is locked
+	// out once JWT auth is enabled.
+	if c.Security.EnableJWTAuth && !hasAdminToken {
+		return errors.New("an admin api-token is required to create the users if enable-jwt-auth is true")
+	}
+	if err := c.Security.AuditLog.Validate(); err != nil {
+		return err
+	}
+	if _, err := ipfilter.New(c.Security.APIAllowCIDRs, c.Security.APIDenyCIDRs); err != nil {
+		return err
+	}
+	if c.EnableGRPCWeb && c.GRPCWebAddr == "" {
+		return errors.New("grpc-web-addr is required if enable-grpc-web is true")
+	}
+	unaryMethods := pdUnaryMethods()
+	for method, deadline := range c.RPCDeadlines {
+		if _, ok := unaryMethods[method]; !ok {
+			return errors.Errorf("rpc-deadlines of %s is not a unary method of the PD service", method)
+		}
+		if deadline.Duration <= 0 {
+			return errors.Errorf("rpc-deadlines of %s should be positive", method)
+		}
+	}
 
 	return nil
 }
 
+// pdUnaryMethods returns the names of the unary methods of the PD service,
+// which are taken from the service registered to a gRPC server.
+func pdUnaryMethods() map[string]struct{} {
+	gs := grpc.NewServer()
+	pdpb.RegisterPDServer(gs, struct{ pdpb.PDServer }{})
+	methods := make(map[string]struct{})
+	for _, info := range gs.GetServiceInfo() {
+		for _, m := range info.Methods {
+			if !m.IsClientStream && !m.IsServerStream {
+				methods[m.Name] = struct{}{}
+			}
+		}
+	}
+	return methods
+}
+
 // Utility to test if a configuration is defined.
 type configMetaData struct {
 	meta *toml.MetaData
@@ -551,6 +652,18 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 		c.TSOUpdatePhysicalInterval.Duration = minTSOUpdatePhysicalInterval
 	}
 
+	adjustDuration(&c.TSOClockDriftWarningThreshold, defaultTSOClockDriftWarningThreshold)
+	// A new leader starts from the saved time window, which is ahead of the system time by up to the
+	// save interval, so it would step down at once with a smaller fatal threshold.
+	if c.TSOClockDriftFatalThreshold.Duration > 0 && c.TSOClockDriftFatalThreshold.Duration <= c.TSOSaveInterval.Duration {
+		return errors.Errorf("tso-clock-drift-fatal-threshold %s should be greater than tso-save-interval %s",
+			c.TSOClockDriftFatalThreshold.Duration, c.TSOSaveInterval.Duration)
+	}
+
+	if c.TSOClientBurst <= 0 {
+		c.TSOClientBurst = int(math.Ceil(c.TSOClientRateLimit))
+	}
+
 	if c.Labels == nil {
 		c.Labels = make(map[string]string)
 	}
@@ -593,9 +706,12 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 
 	c.Dashboard.adjust(configMetaData.Child("dashboard"))
 
-	c.ReplicationMode.adjust(configMetaData.Child("replication-mode"))
+	if err := c.ReplicationMode.adjust(configMetaData.Child("replication-mode")); err != nil {
+		return err
+	}
 
 	c.Security.Encryption.Adjust()
+	adjustDuration(&c.Security.JWTExpiry, defaultJWTExpiry)
 
 	if len(c.Log.Format) == 0 {
 		c.Log.Format = defaultLogFormat
@@ -644,6 +760,14 @@ type ScheduleConfig struct {
 	MaxMergeRegionKeys uint64 `toml:"max-merge-region-keys" json:"max-merge-region-keys"`
 	// SplitMergeInterval is the minimum interval time to permit merge after split.
 	SplitMergeInterval typeutil.Duration `toml:"split-merge-interval" json:"split-merge-interval"`
+	// If the read QPS of a region exceeds SplitQPSThreshold or the write QPS
+	// exceeds SplitWriteQPSThreshold, it will be split to distribute the load.
+	// 0 means the region is never split by the QPS.
+	SplitQPSThreshold      float64 `toml:"split-qps-threshold" json:"split-qps-threshold"`
+	SplitWriteQPSThreshold float64 `toml:"split-write-qps-threshold" json:"split-write-qps-threshold"`
+	// If the write byte rate of a region exceeds SplitWriteBytesThreshold, it
+	// will be split even if it has few keys. 0 means never.
+	SplitWriteBytesThreshold uint64 `toml:"split-write-bytes-threshold" json:"split-write-bytes-threshold"`
 	// EnableOneWayMerge is the option to enable one way merge. This means a Region can only be merged into the next region of it.
 	EnableOneWayMerge bool `toml:"enable-one-way-merge" json:"enable-one-way-merge,string"`
 	// EnableCrossTableMerge is the option to enable cross table merge. This means two Regions can be merged with different table IDs.
@@ -654,6 +778,10 @@ type ScheduleConfig struct {
 	// MaxStoreDownTime is the max duration after which
 	// a store will be considered to be down if it hasn't reported heartbeats.
 	MaxStoreDownTime typeutil.Duration `toml:"max-store-down-time" json:"max-store-down-time"`
+	// StoreWarmUpDuration is the duration during which the usable capacity of
+	// a newly joined store ramps up, so that it does not receive too many
+	// regions at once. 0 means the new stores do not warm up.
+	StoreWarmUpDuration typeutil.Duration `toml:"store-warm-up-duration" json:"store-warm-up-duration"`
 	// LeaderScheduleLimit is the max coexist leader schedules.
 	LeaderScheduleLimit uint64 `toml:"leader-schedule-limit" json:"leader-schedule-limit"`
 	// LeaderSchedulePolicy is the option to balance leader, there are some policies supported: ["count", "size"], default: "count"
@@ -670,13 +798,41 @@ type ScheduleConfig struct {
 	// If the number of times a region hits the hot cache is greater than this
 	// threshold, it is considered a hot region.
 	HotRegionCacheHitsThreshold uint64 `toml:"hot-region-cache-hits-threshold" json:"hot-region-cache-hits-threshold"`
+	// HotCacheEntryTTL is the time after which the loads of a hot peer not
+	// updated start to decay, 0 means the loads never decay.
+	HotCacheEntryTTL typeutil.Duration `toml:"hot-cache-entry-ttl" json:"hot-cache-entry-ttl"`
+	// HotCacheDecayRate is the rate per second the loads of a hot peer decay
+	// exponentially after HotCacheEntryTTL.
+	HotCacheDecayRate float64 `toml:"hot-cache-decay-rate" json:"hot-cache-decay-rate"`
+	// HotShortWindow, HotMediumWindow and HotLongWindow are the windows to
+	// calculate the heat scores of the hot peers, which are the weighted
+	// averages of the loads in the windows by HotWindowWeights. They help to
+	// distinguish the sustained hotspots from the traffic bursts.
+	HotShortWindow   typeutil.Duration `toml:"hot-short-window" json:"hot-short-window"`
+	HotMediumWindow  typeutil.Duration `toml:"hot-medium-window" json:"hot-medium-window"`
+	HotLongWindow    typeutil.Duration `toml:"hot-long-window" json:"hot-long-window"`
+	HotWindowWeights [3]float64        `toml:"hot-window-weights" json:"hot-window-weights"`
+	// PinReadHotRegions is the option to pin the leaders of the regions whose
+	// leader read QPS exceeds ReadHotPinThreshold to their current stores. The
+	// hot region scheduler does not move these leaders, to avoid the latency
+	// spike caused by the leader election.
+	PinReadHotRegions   bool    `toml:"pin-read-hot-regions" json:"pin-read-hot-regions,string"`
+	ReadHotPinThreshold float64 `toml:"read-hot-pin-threshold" json:"read-hot-pin-threshold"`
 	// StoreBalanceRate is the maximum of balance rate for each store.
 	// WARN: StoreBalanceRate is deprecated.
 	StoreBalanceRate float64 `toml:"store-balance-rate" json:"store-balance-rate,omitempty"`
 	// StoreLimit is the limit of scheduling for stores.
 	StoreLimit map[uint64]StoreLimitConfig `toml:"store-limit" json:"store-limit"`
+	// StoreBandwidth is the network bandwidth of the stores, which is used to
+	// estimate the max number of the concurrent snapshots of a store.
+	StoreBandwidth map[uint64]StoreBandwidthConfig `toml:"store-bandwidth" json:"store-bandwidth"`
 	// TolerantSizeRatio is the ratio of buffer size for balance scheduler.
 	TolerantSizeRatio float64 `toml:"tolerant-size-ratio" json:"tolerant-size-ratio"`
+	// If the disk IO rate of a store exceeds (1 + RegionIOImbalanceThreshold)
+	// times that of another store, the balance region scheduler moves regions
+	// between them even if their region scores are balanced. 0 means the disk
+	// IO rates are not considered.
+	RegionIOImbalanceThreshold float64 `toml:"region-io-imbalance-threshold" json:"region-io-imbalance-threshold"`
 	//
 	//      high space stage         transition stage           low space stage
 	//   |--------------------|-----------------------------|-------------------------|
@@ -689,10 +845,32 @@ type ScheduleConfig struct {
 	// HighSpaceRatio is the highest usage ratio of store which regraded as high space.
 	// High space means there is a lot of spare capacity, and store region score varies directly with used size.
 	HighSpaceRatio float64 `toml:"high-space-ratio" json:"high-space-ratio"`
+	// CriticalSpaceRatio is the usage ratio of store which regarded as critical space.
+	// When in critical space, the peers on the store are moved away aggressively.
+	CriticalSpaceRatio float64 `toml:"critical-space-ratio" json:"critical-space-ratio"`
+	// CompactionReservedSpace is the space reserved for the compaction of a
+	// store, it is regarded as used when checking the critical space.
+	CompactionReservedSpace typeutil.ByteSize `toml:"compaction-reserved-space" json:"compaction-reserved-space"`
+	// SlowStoreReadLatencyThresholdUs and SlowStoreWriteLatencyThresholdUs
+	// are the p99 latency thresholds in microseconds of the store. A store
+	// exceeding them for consecutive heartbeats is regarded as slow and is
+	// not selected as the target of leaders. 0 means no threshold.
+	SlowStoreReadLatencyThresholdUs  uint64 `toml:"slow-store-read-latency-threshold-us" json:"slow-store-read-latency-threshold-us"`
+	SlowStoreWriteLatencyThresholdUs uint64 `toml:"slow-store-write-latency-threshold-us" json:"slow-store-write-latency-threshold-us"`
 	// RegionScoreFormulaVersion is used to control the formula used to calculate region score.
 	RegionScoreFormulaVersion string `toml:"region-score-formula-version" json:"region-score-formula-version"`
 	// SchedulerMaxWaitingOperator is the max coexist operators for each scheduler.
 	SchedulerMaxWaitingOperator uint64 `toml:"scheduler-max-waiting-operator" json:"scheduler-max-waiting-operator"`
+	// MaxOperatorsPerStore is the max coexist operators which move peers or
+	// leaders into the same store, except the ones repairing the replicas or
+	// created by the administrator. 0 means no limit.
+	MaxOperatorsPerStore uint64 `toml:"max-operators-per-store" json:"max-operators-per-store"`
+	// MaxSnapshotBytesInFlight is the max total size of the snapshots sent by
+	// the running operators which add peers. 0 means no limit.
+	MaxSnapshotBytesInFlight typeutil.ByteSize `toml:"max-snapshot-bytes-in-flight" json:"max-snapshot-bytes-in-flight"`
+	// OperatorAuditLogCapacity is the max number of the finished operators
+	// kept in the operator audit log. 0 means the audit log is disabled.
+	OperatorAuditLogCapacity uint64 `toml:"operator-audit-log-capacity" json:"operator-audit-log-capacity"`
 	// WARN: DisableLearner is deprecated.
 	// DisableLearner is the option to disable using AddLearnerNode instead of AddNode.
 	DisableLearner bool `toml:"disable-raft-learner" json:"disable-raft-learner,string,omitempty"`
@@ -727,6 +905,13 @@ type ScheduleConfig struct {
 	EnableRemoveExtraReplica bool `toml:"enable-remove-extra-replica" json:"enable-remove-extra-replica,string"`
 	// EnableLocationReplacement is the option to enable replica checker to move replica to a better location.
 	EnableLocationReplacement bool `toml:"enable-location-replacement" json:"enable-location-replacement,string"`
+	// EnableRemoveOrphanLearner is the option to enable orphan learner checker to remove the learners on
+	// the tombstone stores or the stores which have been down for a long time.
+	EnableRemoveOrphanLearner bool `toml:"enable-remove-orphan-learner" json:"enable-remove-orphan-learner,string"`
+	// EnableStoreFlowConflict is the option to refuse the operators which move
+	// the leaders or the peers of a store in the opposite direction of the
+	// operators generated by other schedulers.
+	EnableStoreFlowConflict bool `toml:"enable-store-flow-conflict" json:"enable-store-flow-conflict,string"`
 	// EnableDebugMetrics is the option to enable debug metrics.
 	EnableDebugMetrics bool `toml:"enable-debug-metrics" json:"enable-debug-metrics,string"`
 	// EnableJointConsensus is the option to enable using joint consensus as a operator step.
@@ -751,6 +936,17 @@ type ScheduleConfig struct {
 
 	// The day of hot regions data to be reserved. 0 means close.
 	HotRegionsReservedDays uint64 `toml:"hot-regions-reserved-days" json:"hot-regions-reserved-days"`
+
+	// The days after which the tombstone stores are removed. 0 means never.
+	TombstoneStoreRetentionDays uint64 `toml:"tombstone-store-retention-days" json:"tombstone-store-retention-days"`
+	// TombstoneStoreCleanupDryRun makes the cleanup of the tombstone stores
+	// only log the stores to be removed. It is true by default, so the old
+	// tombstone stores are not removed after upgrading unless it is disabled.
+	TombstoneStoreCleanupDryRun bool `toml:"tombstone-store-cleanup-dry-run" json:"tombstone-store-cleanup-dry-run,string"`
+
+	// EnableZoneAwareLeader is the option to allow the zone-aware-leader
+	// scheduler to move the leaders to the zones sending the most requests.
+	EnableZoneAwareLeader bool `toml:"enable-zone-aware-leader" json:"enable-zone-aware-leader,string"`
 }
 
 // Clone returns a cloned scheduling configuration.
@@ -763,8 +959,16 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 			storeLimit[k] = v
 		}
 	}
+	var storeBandwidth map[uint64]StoreBandwidthConfig
+	if c.StoreBandwidth != nil {
+		storeBandwidth = make(map[uint64]StoreBandwidthConfig, len(c.StoreBandwidth))
+		for k, v := range c.StoreBandwidth {
+			storeBandwidth[k] = v
+		}
+	}
 	cfg := *c
 	cfg.StoreLimit = storeLimit
+	cfg.StoreBandwidth = storeBandwidth
 	cfg.Schedulers = schedulers
 	cfg.SchedulersPayload = nil
 	return &cfg
@@ -779,6 +983,7 @@ const (
 	defaultSplitMergeInterval        = 1 * time.Hour
 	defaultPatrolRegionInterval      = 10 * time.Millisecond
 	defaultMaxStoreDownTime          = 30 * time.Minute
+	defaultStoreWarmUpDuration       = 30 * time.Minute
 	defaultLeaderScheduleLimit       = 4
 	defaultRegionScheduleLimit       = 2048
 	defaultReplicaScheduleLimit      = 64
@@ -787,19 +992,32 @@ const (
 	defaultTolerantSizeRatio         = 0
 	defaultLowSpaceRatio             = 0.8
 	defaultHighSpaceRatio            = 0.7
+	defaultCriticalSpaceRatio        = 0.9
 	defaultRegionScoreFormulaVersion = "v2"
 	// defaultHotRegionCacheHitsThreshold is the low hit number threshold of the
 	// hot region.
 	defaultHotRegionCacheHitsThreshold = 3
+	defaultHotCacheEntryTTL            = 10 * time.Minute
+	defaultHotCacheDecayRate           = 0.01
+	defaultHotShortWindow              = time.Minute
+	defaultHotMediumWindow             = 5 * time.Minute
+	defaultHotLongWindow               = time.Hour
 	defaultSchedulerMaxWaitingOperator = 5
+	defaultMaxOperatorsPerStore        = 4
+	defaultOperatorAuditLogCapacity    = 10000
 	defaultLeaderSchedulePolicy        = "count"
 	defaultStoreLimitMode              = "manual"
 	defaultEnableJointConsensus        = true
 	defaultEnableCrossTableMerge       = true
+	defaultEnableRemoveOrphanLearner   = true
 	defaultHotRegionsWriteInterval     = 10 * time.Minute
 	defaultHotRegionsReservedDays      = 7
+	defaultTombstoneStoreRetentionDays = 30
+	defaultTombstoneStoreCleanupDryRun = true
 )
 
+var defaultHotWindowWeights = [3]float64{0.5, 0.3, 0.2}
+
 func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	if !meta.IsDefined("max-snapshot-count") {
 		adjustUint64(&c.MaxSnapshotCount, defaultMaxSnapshotCount)
@@ -816,6 +1034,9 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	adjustDuration(&c.SplitMergeInterval, defaultSplitMergeInterval)
 	adjustDuration(&c.PatrolRegionInterval, defaultPatrolRegionInterval)
 	adjustDuration(&c.MaxStoreDownTime, defaultMaxStoreDownTime)
+	if !meta.IsDefined("store-warm-up-duration") {
+		adjustDuration(&c.StoreWarmUpDuration, defaultStoreWarmUpDuration)
+	}
 	adjustDuration(&c.HotRegionsWriteInterval, defaultHotRegionsWriteInterval)
 	if !meta.IsDefined("leader-schedule-limit") {
 		adjustUint64(&c.LeaderScheduleLimit, defaultLeaderScheduleLimit)
@@ -835,12 +1056,30 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	if !meta.IsDefined("hot-region-cache-hits-threshold") {
 		adjustUint64(&c.HotRegionCacheHitsThreshold, defaultHotRegionCacheHitsThreshold)
 	}
+	if !meta.IsDefined("hot-cache-entry-ttl") {
+		adjustDuration(&c.HotCacheEntryTTL, defaultHotCacheEntryTTL)
+	}
+	if !meta.IsDefined("hot-cache-decay-rate") {
+		adjustFloat64(&c.HotCacheDecayRate, defaultHotCacheDecayRate)
+	}
+	adjustDuration(&c.HotShortWindow, defaultHotShortWindow)
+	adjustDuration(&c.HotMediumWindow, defaultHotMediumWindow)
+	adjustDuration(&c.HotLongWindow, defaultHotLongWindow)
+	if !meta.IsDefined("hot-window-weights") && c.HotWindowWeights == [3]float64{} {
+		c.HotWindowWeights = defaultHotWindowWeights
+	}
 	if !meta.IsDefined("tolerant-size-ratio") {
 		adjustFloat64(&c.TolerantSizeRatio, defaultTolerantSizeRatio)
 	}
 	if !meta.IsDefined("scheduler-max-waiting-operator") {
 		adjustUint64(&c.SchedulerMaxWaitingOperator, defaultSchedulerMaxWaitingOperator)
 	}
+	if !meta.IsDefined("max-operators-per-store") {
+		adjustUint64(&c.MaxOperatorsPerStore, defaultMaxOperatorsPerStore)
+	}
+	if !meta.IsDefined("operator-audit-log-capacity") {
+		adjustUint64(&c.OperatorAuditLogCapacity, defaultOperatorAuditLogCapacity)
+	}
 	if !meta.IsDefined("leader-schedule-policy") {
 		adjustString(&c.LeaderSchedulePolicy, defaultLeaderSchedulePolicy)
 	}
@@ -853,8 +1092,12 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	if !meta.IsDefined("enable-cross-table-merge") {
 		c.EnableCrossTableMerge = defaultEnableCrossTableMerge
 	}
+	if !meta.IsDefined("enable-remove-orphan-learner") {
+		c.EnableRemoveOrphanLearner = defaultEnableRemoveOrphanLearner
+	}
 	adjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	adjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
+	adjustFloat64(&c.CriticalSpaceRatio, defaultCriticalSpaceRatio)
 
 	// new cluster:v2, old cluster:v1
 	if !meta.IsDefined("region-score-formula-version") && !reloading {
@@ -880,10 +1123,21 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 		c.StoreLimit = make(map[uint64]StoreLimitConfig)
 	}
 
+	if c.StoreBandwidth == nil {
+		c.StoreBandwidth = make(map[uint64]StoreBandwidthConfig)
+	}
+
 	if !meta.IsDefined("hot-regions-reserved-days") {
 		adjustUint64(&c.HotRegionsReservedDays, defaultHotRegionsReservedDays)
 	}
 
+	if !meta.IsDefined("tombstone-store-retention-days") {
+		adjustUint64(&c.TombstoneStoreRetentionDays, defaultTombstoneStoreRetentionDays)
+	}
+	if !meta.IsDefined("tombstone-store-cleanup-dry-run") {
+		c.TombstoneStoreCleanupDryRun = defaultTombstoneStoreCleanupDryRun
+	}
+
 	return c.Validate()
 }
 
@@ -936,6 +1190,9 @@ func (c *ScheduleConfig) Validate() error {
 	if c.TolerantSizeRatio < 0 {
 		return errors.New("tolerant-size-ratio should be non-negative")
 	}
+	if c.RegionIOImbalanceThreshold < 0 {
+		return errors.New("region-io-imbalance-threshold should be non-negative")
+	}
 	if c.LowSpaceRatio < 0 || c.LowSpaceRatio > 1 {
 		return errors.New("low-space-ratio should between 0 and 1")
 	}
@@ -945,6 +1202,39 @@ func (c *ScheduleConfig) Validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	if c.CriticalSpaceRatio < 0 || c.CriticalSpaceRatio > 1 {
+		return errors.New("critical-space-ratio should between 0 and 1")
+	}
+	if c.CriticalSpaceRatio < c.LowSpaceRatio {
+		return errors.New("critical-space-ratio should not be smaller than low-space-ratio")
+	}
+	for storeID, bandwidth := range c.StoreBandwidth {
+		if bandwidth.MaxSendBandwidthMBps < 0 || bandwidth.MaxRecvBandwidthMBps < 0 {
+			return errors.Errorf("the bandwidth of store %d should be non-negative", storeID)
+		}
+	}
+	if c.HotCacheDecayRate <= 0 {
+		return errors.New("hot-cache-decay-rate should be positive")
+	}
+	if c.HotShortWindow.Duration > c.HotMediumWindow.Duration || c.HotMediumWindow.Duration > c.HotLongWindow.Duration {
+		return errors.New("hot-short-window, hot-medium-window and hot-long-window should be in ascending order")
+	}
+	var weightSum float64
+	for _, w := range c.HotWindowWeights {
+		if w < 0 {
+			return errors.New("hot-window-weights should be non-negative")
+		}
+		weightSum += w
+	}
+	if weightSum == 0 {
+		return errors.New("hot-window-weights should not be all 0")
+	}
+	if c.ReadHotPinThreshold < 0 {
+		return errors.New("read-hot-pin-threshold should be non-negative")
+	}
+	if c.SplitQPSThreshold < 0 || c.SplitWriteQPSThreshold < 0 {
+		return errors.New("split-qps-threshold and split-write-qps-threshold should be non-negative")
+	}
 	for _, scheduleConfig := range c.Schedulers {
 		if !IsSchedulerRegistered(scheduleConfig.Type) {
 			return errors.Errorf("create func of %v is not registered, maybe misspelled", scheduleConfig.Type)
@@ -985,6 +1275,14 @@ type StoreLimitConfig struct {
 	RemovePeer float64 `toml:"remove-peer" json:"remove-peer"`
 }
 
+// StoreBandwidthConfig is the network bandwidth of a store.
+type StoreBandwidthConfig struct {
+	// MaxSendBandwidthMBps and MaxRecvBandwidthMBps are the bandwidth in MB/s
+	// for sending and receiving the snapshots, 0 means no limit.
+	MaxSendBandwidthMBps float64 `toml:"max-send-bandwidth-mbps" json:"max-send-bandwidth-mbps"`
+	MaxRecvBandwidthMBps float64 `toml:"max-recv-bandwidth-mbps" json:"max-recv-bandwidth-mbps"`
+}
+
 // SchedulerConfigs is a slice of customized scheduler configuration.
 type SchedulerConfigs []SchedulerConfig
 
@@ -1044,6 +1342,13 @@ type ReplicationConfig struct {
 	// Even if a zone is down, PD will not try to make up replicas in other zone
 	// because other zones already have replicas on it.
 	IsolationLevel string `toml:"isolation-level" json:"isolation-level"`
+
+	// RackLabelKey is the label key of the store specifying the rack, racks can
+	// be a sub-label of other location labels, e.g. "zone".
+	RackLabelKey string `toml:"rack-label-key" json:"rack-label-key"`
+	// EnableRackIsolation is the option to move the replicas sharing a rack
+	// to different racks if it is possible.
+	EnableRackIsolation bool `toml:"enable-rack-isolation" json:"enable-rack-isolation,string"`
 }
 
 // Clone makes a deep copy of the config.
@@ -1070,6 +1375,11 @@ func (c *ReplicationConfig) Validate() error {
 	if c.IsolationLevel != "" && !foundIsolationLevel {
 		return errors.New("isolation-level must be one of location-labels or empty")
 	}
+	if c.RackLabelKey != "" {
+		if err := ValidateLabels([]*metapb.StoreLabel{{Key: c.RackLabelKey}}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1084,6 +1394,9 @@ func (c *ReplicationConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("location-labels") {
 		c.LocationLabels = defaultLocationLabels
 	}
+	if !meta.IsDefined("rack-label-key") {
+		c.RackLabelKey = defaultRackLabelKey
+	}
 	return c.Validate()
 }
 
@@ -1111,6 +1424,15 @@ type PDServerConfig struct {
 	FlowRoundByDigit int `toml:"flow-round-by-digit" json:"flow-round-by-digit"`
 	// MinResolvedTSPersistenceInterval is the interval to save the min resolved ts.
 	MinResolvedTSPersistenceInterval typeutil.Duration `toml:"min-resolved-ts-persistence-interval" json:"min-resolved-ts-persistence-interval"`
+	// EventBufferSize is the number of the cluster events buffered for each
+	// subscriber of the events API, the events are dropped if the buffer is full.
+	EventBufferSize int `toml:"event-buffer-size" json:"event-buffer-size"`
+	// MaxGenealogyEntries is the max number of the splits and merges kept in
+	// the region genealogy, the oldest ones are removed.
+	MaxGenealogyEntries int `toml:"max-genealogy-entries" json:"max-genealogy-entries"`
+	// RegionTrafficSamples is the number of the recent heartbeats whose traffic
+	// is kept for each region, the traffic is not kept if it is 0.
+	RegionTrafficSamples int `toml:"region-traffic-samples" json:"region-traffic-samples"`
 }
 
 func (c *PDServerConfig) adjust(meta *configMetaData) error {
@@ -1136,6 +1458,15 @@ func (c *PDServerConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("min-resolved-ts-persistence-interval") {
 		adjustDuration(&c.MinResolvedTSPersistenceInterval, defaultMinResolvedTSPersistenceInterval)
 	}
+	if !meta.IsDefined("event-buffer-size") {
+		adjustInt(&c.EventBufferSize, defaultEventBufferSize)
+	}
+	if !meta.IsDefined("max-genealogy-entries") {
+		adjustInt(&c.MaxGenealogyEntries, defaultMaxGenealogyEntries)
+	}
+	if !meta.IsDefined("region-traffic-samples") {
+		adjustInt(&c.RegionTrafficSamples, defaultRegionTrafficSamples)
+	}
 	c.migrateConfigurationFromFile(meta)
 	return c.Validate()
 }
@@ -1186,6 +1517,15 @@ func (c *PDServerConfig) Validate() error {
 	if c.FlowRoundByDigit < 0 {
 		return errs.ErrConfigItem.GenWithStack("flow round by digit cannot be negative number")
 	}
+	if c.EventBufferSize < 0 {
+		return errs.ErrConfigItem.GenWithStack("event buffer size cannot be negative number")
+	}
+	if c.MaxGenealogyEntries <= 0 {
+		return errs.ErrConfigItem.GenWithStack("max genealogy entries should be positive")
+	}
+	if c.RegionTrafficSamples < 0 {
+		return errs.ErrConfigItem.GenWithStack("region traffic samples cannot be negative number")
+	}
 
 	return nil
 }
@@ -1347,19 +1687,64 @@ func (c *DashboardConfig) adjust(meta *configMetaData) {
 type ReplicationModeConfig struct {
 	ReplicationMode string                      `toml:"replication-mode" json:"replication-mode"` // can be 'dr-auto-sync' or 'majority', default value is 'majority'
 	DRAutoSync      DRAutoSyncReplicationConfig `toml:"dr-auto-sync" json:"dr-auto-sync"`         // used when ReplicationMode is 'dr-auto-sync'
+	// ReplicationPeers are the spokes in a hub-and-spoke topology, whose
+	// replication lag is tracked in every replication mode.
+	ReplicationPeers []ReplicationPeer `toml:"replication-peers" json:"replication-peers"`
 }
 
 // Clone returns a copy of replication mode config.
 func (c *ReplicationModeConfig) Clone() *ReplicationModeConfig {
+	peers := append(c.ReplicationPeers[:0:0], c.ReplicationPeers...)
 	cfg := *c
+	cfg.ReplicationPeers = peers
 	return &cfg
 }
 
-func (c *ReplicationModeConfig) adjust(meta *configMetaData) {
+func (c *ReplicationModeConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("replication-mode") || NormalizeReplicationMode(c.ReplicationMode) == "" {
 		c.ReplicationMode = "majority"
 	}
 	c.DRAutoSync.adjust(meta.Child("dr-auto-sync"))
+	for i := range c.ReplicationPeers {
+		if c.ReplicationPeers[i].Role == "" {
+			c.ReplicationPeers[i].Role = ReplicationPeerFollower
+		}
+	}
+	return c.Validate()
+}
+
+// Validate is used to validate if the replication peers are right.
+func (c *ReplicationModeConfig) Validate() error {
+	addresses := make(map[string]struct{}, len(c.ReplicationPeers))
+	for _, peer := range c.ReplicationPeers {
+		if peer.Address == "" {
+			return errors.New("the address of a replication peer is required")
+		}
+		if _, ok := addresses[peer.Address]; ok {
+			return errors.Errorf("duplicated replication peer %s", peer.Address)
+		}
+		addresses[peer.Address] = struct{}{}
+		if peer.Role != ReplicationPeerFollower && peer.Role != ReplicationPeerObserver {
+			return errors.Errorf("invalid role %s of replication peer %s, it must be %s or %s",
+				peer.Role, peer.Address, ReplicationPeerFollower, ReplicationPeerObserver)
+		}
+	}
+	return nil
+}
+
+// The roles of the replication peers.
+const (
+	ReplicationPeerFollower = "follower"
+	ReplicationPeerObserver = "observer"
+)
+
+// ReplicationPeer is a spoke the data is replicated to, which is the store
+// with the address.
+type ReplicationPeer struct {
+	Address string `toml:"address" json:"address"`
+	Role    string `toml:"role" json:"role"`
+	// MaxAllowedLagMs is the lag tolerance of the peer, 0 means no limit.
+	MaxAllowedLagMs uint64 `toml:"max-allowed-lag-ms" json:"max-allowed-lag-ms"`
 }
 
 // NormalizeReplicationMode converts user's input mode to internal use.
@@ -1382,6 +1767,9 @@ type DRAutoSyncReplicationConfig struct {
 	WaitStoreTimeout typeutil.Duration `toml:"wait-store-timeout" json:"wait-store-timeout"`
 	WaitSyncTimeout  typeutil.Duration `toml:"wait-sync-timeout" json:"wait-sync-timeout"`
 	WaitAsyncTimeout typeutil.Duration `toml:"wait-async-timeout" json:"wait-async-timeout"`
+	// MaxAllowedLagBytes blocks switching from sync to async if the dr stores
+	// lag behind more than it, 0 means no limit.
+	MaxAllowedLagBytes typeutil.ByteSize `toml:"max-allowed-lag-bytes" json:"max-allowed-lag-bytes"`
 }
 
 func (c *DRAutoSyncReplicationConfig) adjust(meta *configMetaData) {
@@ -1402,4 +1790,57 @@ type SecurityConfig struct {
 	// RedactInfoLog indicates that whether enabling redact log
 	RedactInfoLog bool              `toml:"redact-info-log" json:"redact-info-log"`
 	Encryption    encryption.Config `toml:"encryption" json:"encryption"`
+	// APITokens maps the tokens to the roles of the HTTP API clients, the
+	// clients send the token in the `Authorization: Bearer <token>` header.
+	// All clients are allowed to do anything if it is empty.
+	APITokens map[string]string `toml:"api-tokens" json:"-"`
+	// AuditLog is the audit log of the mutating HTTP API calls.
+	AuditLog AuditLogConfig `toml:"audit-log" json:"audit-log"`
+	// APIAllowCIDRs are the networks allowed to access the HTTP and gRPC APIs,
+	// all networks are allowed if it is empty. Note that the other PD members
+	// must be allowed to forward the requests and sync the regions.
+	APIAllowCIDRs []string `toml:"api-allow-cidrs" json:"api-allow-cidrs"`
+	// APIDenyCIDRs are the networks denied to access the HTTP and gRPC APIs,
+	// they take precedence over APIAllowCIDRs.
+	APIDenyCIDRs []string `toml:"api-deny-cidrs" json:"api-deny-cidrs"`
+	// InternalSharedSecret is used to sign the requests sent by PD to the
+	// status server of the stores, the requests are not signed if it is empty.
+	InternalSharedSecret string `toml:"internal-shared-secret" json:"-"`
+	// EnableJWTAuth enables the users stored in PD to get the tokens from
+	// `/pd/api/v1/auth/token`, the tokens are accepted by the HTTP API like the
+	// APITokens. It requires an admin token in APITokens to create the users.
+	EnableJWTAuth bool `toml:"enable-jwt-auth" json:"enable-jwt-auth"`
+	// JWTExpiry is how long the issued tokens are valid.
+	JWTExpiry typeutil.Duration `toml:"jwt-expiry" json:"jwt-expiry"`
+	// EnableGRPCReflection registers the gRPC reflection service for the tools
+	// like grpcurl to discover the RPCs. It only takes effect if TLS is enabled.
+	EnableGRPCReflection bool `toml:"enable-grpc-reflection" json:"enable-grpc-reflection"`
+}
+
+// AuditLogConfig is the config of the audit log of the mutating HTTP API calls.
+type AuditLogConfig struct {
+	// Writer is where the audit log is written to.
+	// There are some values supported: "file", "syslog", or "" which disables
+	// the audit log, default: "".
+	Writer string `toml:"writer" json:"writer"`
+	// Filename is the file of the audit log if the writer is "file".
+	Filename string `toml:"filename" json:"filename"`
+	// HMACKeyPath is the file of the key to chain the entries by the HMAC, so
+	// the entries can not be forged without the key. The entries are chained
+	// by the SHA-256 if it is empty.
+	HMACKeyPath string `toml:"hmac-key-path" json:"hmac-key-path"`
+}
+
+// Validate is used to validate if the audit log config is right.
+func (c *AuditLogConfig) Validate() error {
+	switch c.Writer {
+	case "", "syslog":
+	case "file":
+		if c.Filename == "" {
+			return errors.New("the filename of the audit log is required")
+		}
+	default:
+		return errors.Errorf("unknown audit log writer %s", c.Writer)
+	}
+	return nil
 }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/storage"
 )
 
@@ -153,6 +154,14 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	c.Assert(cfg.Schedule.Validate(), NotNil)
 	cfg.Schedule.LowSpaceRatio = 0.8
 	c.Assert(cfg.Schedule.Validate(), IsNil)
+	cfg.Schedule.CriticalSpaceRatio = 0.7
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.CriticalSpaceRatio = 0.9
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	cfg.Schedule.StoreBandwidth[1] = StoreBandwidthConfig{MaxSendBandwidthMBps: -1}
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.StoreBandwidth[1] = StoreBandwidthConfig{MaxSendBandwidthMBps: 100}
+	c.Assert(cfg.Schedule.Validate(), IsNil)
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	c.Assert(cfg.Schedule.Validate(), NotNil)
 	// check quota
@@ -464,6 +473,36 @@ wait-store-timeout = "120s"
 	err = cfg.Adjust(&meta, false)
 	c.Assert(err, IsNil)
 	c.Assert(cfg.ReplicationMode.ReplicationMode, Equals, "majority")
+
+	cfgData = `
+[[replication-mode.replication-peers]]
+address = "spoke1:20160"
+max-allowed-lag-ms = 1000
+[[replication-mode.replication-peers]]
+address = "spoke2:20160"
+role = "observer"
+`
+	cfg = NewConfig()
+	meta, err = toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	err = cfg.Adjust(&meta, false)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.ReplicationMode.ReplicationPeers, DeepEquals, []ReplicationPeer{
+		{Address: "spoke1:20160", Role: ReplicationPeerFollower, MaxAllowedLagMs: 1000},
+		{Address: "spoke2:20160", Role: ReplicationPeerObserver},
+	})
+	clone := cfg.ReplicationMode.Clone()
+	clone.ReplicationPeers[0].Role = ReplicationPeerObserver
+	c.Assert(cfg.ReplicationMode.ReplicationPeers[0].Role, Equals, ReplicationPeerFollower)
+
+	for _, peers := range [][]ReplicationPeer{
+		{{Role: ReplicationPeerFollower}},
+		{{Address: "spoke1:20160", Role: "leader"}},
+		{{Address: "spoke1:20160", Role: ReplicationPeerFollower}, {Address: "spoke1:20160", Role: ReplicationPeerObserver}},
+	} {
+		replicationMode := ReplicationModeConfig{ReplicationPeers: peers}
+		c.Assert(replicationMode.Validate(), NotNil)
+	}
 }
 
 func (s *testConfigSuite) TestHotHistoryRegionConfig(c *C) {
@@ -487,6 +526,60 @@ hot-regions-write-interval= "30m"
 	c.Assert(cfg.Schedule.HotRegionsReservedDays, Equals, uint64(7))
 }
 
+func (s *testConfigSuite) TestTombstoneStoreCleanupConfig(c *C) {
+	// The cleanup is dry run by default.
+	cfg := NewConfig()
+	c.Assert(cfg.Adjust(nil, false), IsNil)
+	c.Assert(cfg.Schedule.TombstoneStoreRetentionDays, Equals, uint64(30))
+	c.Assert(cfg.Schedule.TombstoneStoreCleanupDryRun, IsTrue)
+
+	cfgData := `
+[schedule]
+tombstone-store-cleanup-dry-run = false
+`
+	cfg = NewConfig()
+	meta, err := toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta, false), IsNil)
+	c.Assert(cfg.Schedule.TombstoneStoreCleanupDryRun, IsFalse)
+}
+
+func (s *testConfigSuite) TestJWTAuthConfig(c *C) {
+	cfg := NewConfig()
+	c.Assert(cfg.Adjust(nil, false), IsNil)
+	cfg.Security.EnableJWTAuth = true
+	// An admin token is required to create the users.
+	c.Assert(cfg.Validate(), NotNil)
+	cfg.Security.APITokens = map[string]string{"token": "operator"}
+	c.Assert(cfg.Validate(), NotNil)
+	cfg.Security.APITokens["admin-token"] = "admin"
+	c.Assert(cfg.Validate(), IsNil)
+}
+
+func (s *testConfigSuite) TestRPCDeadlines(c *C) {
+	cfg := NewConfig()
+	c.Assert(cfg.Adjust(nil, false), IsNil)
+	cfg.RPCDeadlines = map[string]typeutil.Duration{"GetRegion": typeutil.NewDuration(time.Second)}
+	c.Assert(cfg.Validate(), IsNil)
+	// The unknown methods and the streaming methods are rejected.
+	for _, method := range []string{"GetRegions", "Tso", "WatchGlobalConfig", "ReportBuckets"} {
+		cfg.RPCDeadlines = map[string]typeutil.Duration{method: typeutil.NewDuration(time.Second)}
+		c.Assert(cfg.Validate(), NotNil)
+	}
+	cfg.RPCDeadlines = map[string]typeutil.Duration{"GetRegion": typeutil.NewDuration(0)}
+	c.Assert(cfg.Validate(), NotNil)
+}
+
+func (s *testConfigSuite) TestClockDriftFatalThreshold(c *C) {
+	cfg := NewConfig()
+	cfg.TSOClockDriftFatalThreshold = typeutil.NewDuration(time.Second)
+	c.Assert(cfg.Adjust(nil, false), NotNil)
+	cfg = NewConfig()
+	cfg.TSOClockDriftFatalThreshold = typeutil.NewDuration(time.Second)
+	cfg.TSOSaveInterval = typeutil.NewDuration(500 * time.Millisecond)
+	c.Assert(cfg.Adjust(nil, false), IsNil)
+}
+
 func (s *testConfigSuite) TestConfigClone(c *C) {
 	cfg := &Config{}
 	cfg.Adjust(nil, false)
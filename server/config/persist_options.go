@@ -140,6 +140,16 @@ func (o *PersistOptions) GetIsolationLevel() string {
 	return o.GetReplicationConfig().IsolationLevel
 }
 
+// GetRackLabelKey returns the label key specifying the rack of a store.
+func (o *PersistOptions) GetRackLabelKey() string {
+	return o.GetReplicationConfig().RackLabelKey
+}
+
+// IsRackIsolationEnabled returns if the replicas are isolated by racks.
+func (o *PersistOptions) IsRackIsolationEnabled() bool {
+	return o.GetReplicationConfig().EnableRackIsolation
+}
+
 // IsPlacementRulesEnabled returns if the placement rules is enabled.
 func (o *PersistOptions) IsPlacementRulesEnabled() bool {
 	return o.GetReplicationConfig().EnablePlacementRules
@@ -241,6 +251,21 @@ func (o *PersistOptions) GetMaxMergeRegionKeys() uint64 {
 	return o.getTTLUintOr(maxMergeRegionKeysKey, o.GetScheduleConfig().MaxMergeRegionKeys)
 }
 
+// GetSplitQPSThreshold returns the read QPS threshold to split a region.
+func (o *PersistOptions) GetSplitQPSThreshold() float64 {
+	return o.GetScheduleConfig().SplitQPSThreshold
+}
+
+// GetSplitWriteQPSThreshold returns the write QPS threshold to split a region.
+func (o *PersistOptions) GetSplitWriteQPSThreshold() float64 {
+	return o.GetScheduleConfig().SplitWriteQPSThreshold
+}
+
+// GetSplitWriteBytesThreshold returns the write byte rate threshold to split a region.
+func (o *PersistOptions) GetSplitWriteBytesThreshold() uint64 {
+	return o.GetScheduleConfig().SplitWriteBytesThreshold
+}
+
 // GetSplitMergeInterval returns the interval between finishing split and starting to merge.
 func (o *PersistOptions) GetSplitMergeInterval() time.Duration {
 	return o.GetScheduleConfig().SplitMergeInterval.Duration
@@ -278,6 +303,26 @@ func (o *PersistOptions) SetStoreLimit(storeID uint64, typ storelimit.Type, rate
 	o.SetScheduleConfig(v)
 }
 
+// GetStoreBandwidth returns the network bandwidth of a store.
+func (o *PersistOptions) GetStoreBandwidth(storeID uint64) StoreBandwidthConfig {
+	return o.GetScheduleConfig().StoreBandwidth[storeID]
+}
+
+// SetStoreBandwidth sets the network bandwidth of a store, the zero bandwidth
+// means no limit.
+func (o *PersistOptions) SetStoreBandwidth(storeID uint64, sendMBps, recvMBps float64) {
+	v := o.GetScheduleConfig().Clone()
+	if v.StoreBandwidth == nil {
+		v.StoreBandwidth = make(map[uint64]StoreBandwidthConfig)
+	}
+	if sendMBps == 0 && recvMBps == 0 {
+		delete(v.StoreBandwidth, storeID)
+	} else {
+		v.StoreBandwidth[storeID] = StoreBandwidthConfig{MaxSendBandwidthMBps: sendMBps, MaxRecvBandwidthMBps: recvMBps}
+	}
+	o.SetScheduleConfig(v)
+}
+
 // SetAllStoresLimit sets all store limit for a given type and rate.
 func (o *PersistOptions) SetAllStoresLimit(typ storelimit.Type, ratePerMin float64) {
 	v := o.GetScheduleConfig().Clone()
@@ -299,6 +344,16 @@ func (o *PersistOptions) SetAllStoresLimit(typ storelimit.Type, ratePerMin float
 	o.SetScheduleConfig(v)
 }
 
+// IsPinReadHotRegionsEnabled returns if the leaders of the read hot regions are pinned.
+func (o *PersistOptions) IsPinReadHotRegionsEnabled() bool {
+	return o.GetScheduleConfig().PinReadHotRegions
+}
+
+// GetReadHotPinThreshold returns the leader read QPS threshold to pin a region.
+func (o *PersistOptions) GetReadHotPinThreshold() float64 {
+	return o.GetScheduleConfig().ReadHotPinThreshold
+}
+
 // IsOneWayMergeEnabled returns if a region can only be merged into the next region of it.
 func (o *PersistOptions) IsOneWayMergeEnabled() bool {
 	return o.GetScheduleConfig().EnableOneWayMerge
@@ -314,6 +369,11 @@ func (o *PersistOptions) GetPatrolRegionInterval() time.Duration {
 	return o.GetScheduleConfig().PatrolRegionInterval.Duration
 }
 
+// GetStoreWarmUpDuration returns the warm-up duration of a newly joined store.
+func (o *PersistOptions) GetStoreWarmUpDuration() time.Duration {
+	return o.GetScheduleConfig().StoreWarmUpDuration.Duration
+}
+
 // GetMaxStoreDownTime returns the max down time of a store.
 func (o *PersistOptions) GetMaxStoreDownTime() time.Duration {
 	return o.GetScheduleConfig().MaxStoreDownTime.Duration
@@ -419,6 +479,12 @@ func (o *PersistOptions) GetTolerantSizeRatio() float64 {
 	return o.GetScheduleConfig().TolerantSizeRatio
 }
 
+// GetRegionIOImbalanceThreshold returns the ratio of the disk IO rates of two
+// stores above which the regions are balanced between them.
+func (o *PersistOptions) GetRegionIOImbalanceThreshold() float64 {
+	return o.GetScheduleConfig().RegionIOImbalanceThreshold
+}
+
 // GetLowSpaceRatio returns the low space ratio.
 func (o *PersistOptions) GetLowSpaceRatio() float64 {
 	return o.GetScheduleConfig().LowSpaceRatio
@@ -429,6 +495,26 @@ func (o *PersistOptions) GetHighSpaceRatio() float64 {
 	return o.GetScheduleConfig().HighSpaceRatio
 }
 
+// GetCriticalSpaceRatio returns the critical space ratio.
+func (o *PersistOptions) GetCriticalSpaceRatio() float64 {
+	return o.GetScheduleConfig().CriticalSpaceRatio
+}
+
+// GetCompactionReservedSpace returns the space reserved for the compaction of a store.
+func (o *PersistOptions) GetCompactionReservedSpace() uint64 {
+	return uint64(o.GetScheduleConfig().CompactionReservedSpace)
+}
+
+// GetSlowStoreReadLatencyThresholdUs returns the p99 read latency threshold of a slow store.
+func (o *PersistOptions) GetSlowStoreReadLatencyThresholdUs() uint64 {
+	return o.GetScheduleConfig().SlowStoreReadLatencyThresholdUs
+}
+
+// GetSlowStoreWriteLatencyThresholdUs returns the p99 write latency threshold of a slow store.
+func (o *PersistOptions) GetSlowStoreWriteLatencyThresholdUs() uint64 {
+	return o.GetScheduleConfig().SlowStoreWriteLatencyThresholdUs
+}
+
 // GetRegionScoreFormulaVersion returns the formula version config.
 func (o *PersistOptions) GetRegionScoreFormulaVersion() string {
 	return o.GetScheduleConfig().RegionScoreFormulaVersion
@@ -439,6 +525,23 @@ func (o *PersistOptions) GetSchedulerMaxWaitingOperator() uint64 {
 	return o.getTTLUintOr(schedulerMaxWaitingOperatorKey, o.GetScheduleConfig().SchedulerMaxWaitingOperator)
 }
 
+// GetMaxOperatorsPerStore returns the number of the max operators moving peers
+// or leaders into the same store.
+func (o *PersistOptions) GetMaxOperatorsPerStore() uint64 {
+	return o.GetScheduleConfig().MaxOperatorsPerStore
+}
+
+// GetMaxSnapshotBytesInFlight returns the max total size of the snapshots sent
+// by the running operators.
+func (o *PersistOptions) GetMaxSnapshotBytesInFlight() uint64 {
+	return uint64(o.GetScheduleConfig().MaxSnapshotBytesInFlight)
+}
+
+// GetOperatorAuditLogCapacity returns the capacity of the operator audit log.
+func (o *PersistOptions) GetOperatorAuditLogCapacity() uint64 {
+	return o.GetScheduleConfig().OperatorAuditLogCapacity
+}
+
 // GetLeaderSchedulePolicy is to get leader schedule policy.
 func (o *PersistOptions) GetLeaderSchedulePolicy() core.SchedulePolicy {
 	return core.StringToSchedulePolicy(o.GetScheduleConfig().LeaderSchedulePolicy)
@@ -496,6 +599,17 @@ func (o *PersistOptions) IsLocationReplacementEnabled() bool {
 	return o.GetScheduleConfig().EnableLocationReplacement
 }
 
+// IsStoreFlowConflictEnabled returns if the operators moving a store in the
+// opposite directions conflict with each other.
+func (o *PersistOptions) IsStoreFlowConflictEnabled() bool {
+	return o.GetScheduleConfig().EnableStoreFlowConflict
+}
+
+// IsRemoveOrphanLearnerEnabled returns if remove orphan learner is enabled.
+func (o *PersistOptions) IsRemoveOrphanLearnerEnabled() bool {
+	return o.GetScheduleConfig().EnableRemoveOrphanLearner
+}
+
 // IsDebugMetricsEnabled returns if debug metrics is enabled.
 func (o *PersistOptions) IsDebugMetricsEnabled() bool {
 	return o.GetScheduleConfig().EnableDebugMetrics
@@ -517,6 +631,31 @@ func (o *PersistOptions) GetHotRegionCacheHitsThreshold() int {
 	return int(o.GetScheduleConfig().HotRegionCacheHitsThreshold)
 }
 
+// GetHotCacheEntryTTL returns the time after which the loads of a hot peer
+// not updated start to decay.
+func (o *PersistOptions) GetHotCacheEntryTTL() time.Duration {
+	return o.GetScheduleConfig().HotCacheEntryTTL.Duration
+}
+
+// GetHotCacheDecayRate returns the rate per second the loads of an expired
+// hot peer decay.
+func (o *PersistOptions) GetHotCacheDecayRate() float64 {
+	return o.GetScheduleConfig().HotCacheDecayRate
+}
+
+// GetHotWindows returns the short, medium and long windows to calculate the
+// heat scores of the hot peers.
+func (o *PersistOptions) GetHotWindows() (short, medium, long time.Duration) {
+	cfg := o.GetScheduleConfig()
+	return cfg.HotShortWindow.Duration, cfg.HotMediumWindow.Duration, cfg.HotLongWindow.Duration
+}
+
+// GetHotWindowWeights returns the weights of the windows to calculate the heat
+// scores of the hot peers.
+func (o *PersistOptions) GetHotWindowWeights() [3]float64 {
+	return o.GetScheduleConfig().HotWindowWeights
+}
+
 // GetStoresLimit gets the stores' limit.
 func (o *PersistOptions) GetStoresLimit() map[uint64]StoreLimitConfig {
 	return o.GetScheduleConfig().StoreLimit
@@ -537,6 +676,24 @@ func (o *PersistOptions) GetHotRegionsReservedDays() uint64 {
 	return o.GetScheduleConfig().HotRegionsReservedDays
 }
 
+// GetTombstoneStoreRetention returns the duration after which the tombstone
+// stores are removed, 0 means never.
+func (o *PersistOptions) GetTombstoneStoreRetention() time.Duration {
+	return time.Duration(o.GetScheduleConfig().TombstoneStoreRetentionDays) * 24 * time.Hour
+}
+
+// IsTombstoneStoreCleanupDryRun returns whether the cleanup of the tombstone
+// stores only logs the stores to be removed.
+func (o *PersistOptions) IsTombstoneStoreCleanupDryRun() bool {
+	return o.GetScheduleConfig().TombstoneStoreCleanupDryRun
+}
+
+// IsZoneAwareLeaderEnabled returns whether the leaders can be moved to the
+// zones sending the most requests.
+func (o *PersistOptions) IsZoneAwareLeaderEnabled() bool {
+	return o.GetScheduleConfig().EnableZoneAwareLeader
+}
+
 // AddSchedulerCfg adds the scheduler configurations.
 func (o *PersistOptions) AddSchedulerCfg(tp string, args []string) {
 	v := o.GetScheduleConfig().Clone()
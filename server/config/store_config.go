@@ -15,14 +15,27 @@
 package config
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
+	"github.com/docker/go-units"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/pkg/typeutil"
 	"go.uber.org/zap"
 )
@@ -36,28 +49,119 @@ var (
 	defaultRegionMaxKey = uint64(1440000)
 	// default region split key is 960000
 	defaultRegionSplitKey = uint64(960000)
+	// default raft log gc threshold is 50
+	defaultRaftLogGCThreshold = uint64(50)
+	// default split region check tick interval is 10s
+	defaultSplitRegionCheckTickInterval = 10 * time.Second
+	// default region split check diff is 6MB
+	defaultRegionSplitCheckDiff = uint64(6 * units.MiB)
+	// default raft entry max size is 8MB
+	defaultRaftEntryMaxSize = uint64(8 * units.MiB)
+	// default store config ttl is 5 minutes
+	defaultStoreConfigTTL = 5 * time.Minute
 )
 
+// StoreConfigSignatureHeader is the header of the signature of the store
+// config request, its value is like `t=<unix timestamp>,sig=<hex HMAC-SHA256>`.
+// The HMAC is computed with the internal shared secret over the timestamp,
+// the url and the body of the request.
+const StoreConfigSignatureHeader = "X-PD-Signature"
+
 // StoreConfigManager is used to manage the store config.
 type StoreConfigManager struct {
+	// config is the config of the most recently updated store.
 	config unsafe.Pointer
+	// stores is the config of every store, the key is the status address
+	// and the value is *storeConfigEntry.
+	stores sync.Map
+	ttl    time.Duration
 	client http.Client
 	schema string
+
+	// maxAttempts is the max times to request the store, at least once.
+	maxAttempts int
+	// backoff is the wait duration before the first retry, it doubles for every retry.
+	backoff time.Duration
+
+	// breakerConfig is the config of the circuit breakers.
+	breakerConfig CircuitBreakerConfig
+	// breakers is the circuit breaker of every store, the key is the status
+	// address and the value is *circuitBreaker.
+	breakers sync.Map
+
+	// secret is used to sign the requests, the requests are not signed if it
+	// is empty.
+	secret []byte
+
+	// refreshMu protects the auto refresh goroutine.
+	refreshMu struct {
+		sync.Mutex
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+}
+
+type storeConfigEntry struct {
+	config   *StoreConfig
+	loadTime time.Time
+	// etag and lastModified are the validators of the last response,
+	// they are used to send the conditional requests.
+	etag         string
+	lastModified string
+}
+
+// StoreConfigManagerOption is used to create the StoreConfigManager with options.
+type StoreConfigManagerOption func(m *StoreConfigManager)
+
+// WithTimeout sets the timeout of every HTTP request to the store.
+func WithTimeout(d time.Duration) StoreConfigManagerOption {
+	return func(m *StoreConfigManager) {
+		m.client.Timeout = d
+	}
+}
+
+// WithRetry makes the manager retry on the transient HTTP errors with the exponential back-off.
+func WithRetry(maxAttempts int, backoff time.Duration) StoreConfigManagerOption {
+	return func(m *StoreConfigManager) {
+		if maxAttempts > 0 {
+			m.maxAttempts = maxAttempts
+		}
+		m.backoff = backoff
+	}
+}
+
+// WithConfigTTL sets how long the config of a store is kept if it's not refreshed.
+func WithConfigTTL(ttl time.Duration) StoreConfigManagerOption {
+	return func(m *StoreConfigManager) {
+		m.ttl = ttl
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker to stop requesting the unreachable stores.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) StoreConfigManagerOption {
+	return func(m *StoreConfigManager) {
+		m.breakerConfig = cfg
+	}
 }
 
 // NewStoreConfigManager creates a new StoreConfigManager.
-func NewStoreConfigManager(config *SecurityConfig) *StoreConfigManager {
+func NewStoreConfigManager(config *SecurityConfig, opts ...StoreConfigManagerOption) *StoreConfigManager {
 	manager := &StoreConfigManager{
-		schema: "http",
+		ttl:         defaultStoreConfigTTL,
+		schema:      "http",
+		maxAttempts: 1,
 	}
-	if config == nil {
-		return manager
-	}
-	if cfg, err := config.ToTLSConfig(); err == nil && cfg != nil {
-		manager.client = http.Client{
-			Transport: &http.Transport{TLSClientConfig: cfg},
+	if config != nil {
+		if cfg, err := config.ToTLSConfig(); err == nil && cfg != nil {
+			manager.client = http.Client{
+				Transport: &http.Transport{TLSClientConfig: cfg},
+			}
+			manager.schema = "https"
 		}
-		manager.schema = "https"
+		manager.secret = []byte(config.InternalSharedSecret)
+	}
+	for _, opt := range opts {
+		opt(manager)
 	}
 	return manager
 }
@@ -67,6 +171,7 @@ func NewStoreConfigManager(config *SecurityConfig) *StoreConfigManager {
 // nolint
 type StoreConfig struct {
 	Coprocessor `json:"coprocessor"`
+	RaftStore   RaftStore `json:"raftstore"`
 }
 
 // Coprocessor is the config of coprocessor.
@@ -77,6 +182,14 @@ type Coprocessor struct {
 	RegionSplitKeys int    `json:"region-split-keys"`
 }
 
+// RaftStore is the config of raftstore.
+type RaftStore struct {
+	RaftLogGCThreshold           int    `json:"raft-log-gc-threshold"`
+	SplitRegionCheckTickInterval string `json:"split-region-check-tick-interval"`
+	RegionSplitCheckDiff         string `json:"region-split-check-diff"`
+	RaftEntryMaxSize             string `json:"raft-entry-max-size"`
+}
+
 // String implements fmt.Stringer interface.
 func (c *StoreConfig) String() string {
 	data, err := json.MarshalIndent(c, "", "  ")
@@ -102,22 +215,169 @@ func (c *StoreConfig) GetRegionSplitSize() uint64 {
 	return typeutil.ParseMBFromText(c.Coprocessor.RegionSplitSize, defaultRegionSplitSize)
 }
 
-// GetRegionSplitKeys returns the region split keys
+// GetRegionMaxSizeBytes returns the max region size in bytes.
+func (c *StoreConfig) GetRegionMaxSizeBytes() uint64 {
+	if c == nil || len(c.Coprocessor.RegionMaxSize) == 0 {
+		return defaultRegionMaxSize * units.MiB
+	}
+	return typeutil.ParseBytesFromText(c.Coprocessor.RegionMaxSize, defaultRegionMaxSize*units.MiB)
+}
+
+// GetRegionSplitSizeBytes returns the region split size in bytes.
+func (c *StoreConfig) GetRegionSplitSizeBytes() uint64 {
+	if c == nil || len(c.Coprocessor.RegionSplitSize) == 0 {
+		return defaultRegionSplitSize * units.MiB
+	}
+	return typeutil.ParseBytesFromText(c.Coprocessor.RegionSplitSize, defaultRegionSplitSize*units.MiB)
+}
+
+// GetRegionSplitKeys returns the region split keys, the default value is used if it's not positive.
 func (c *StoreConfig) GetRegionSplitKeys() uint64 {
-	if c == nil || c.Coprocessor.RegionSplitKeys == 0 {
+	if c == nil || c.Coprocessor.RegionSplitKeys <= 0 {
 		return defaultRegionSplitKey
 	}
 	return uint64(c.Coprocessor.RegionSplitKeys)
 }
 
-// GetRegionMaxKeys returns the region split keys
+// GetRegionMaxKeys returns the region max keys, the default value is used if it's not positive.
 func (c *StoreConfig) GetRegionMaxKeys() uint64 {
-	if c == nil || c.Coprocessor.RegionMaxKeys == 0 {
+	if c == nil || c.Coprocessor.RegionMaxKeys <= 0 {
 		return defaultRegionMaxKey
 	}
 	return uint64(c.Coprocessor.RegionMaxKeys)
 }
 
+// GetRaftLogGCThreshold returns the raft log gc threshold.
+func (c *StoreConfig) GetRaftLogGCThreshold() uint64 {
+	if c == nil || c.RaftStore.RaftLogGCThreshold <= 0 {
+		return defaultRaftLogGCThreshold
+	}
+	return uint64(c.RaftStore.RaftLogGCThreshold)
+}
+
+// GetSplitRegionCheckTickInterval returns the interval of checking whether the region needs to split.
+func (c *StoreConfig) GetSplitRegionCheckTickInterval() time.Duration {
+	if c == nil || len(c.RaftStore.SplitRegionCheckTickInterval) == 0 {
+		return defaultSplitRegionCheckTickInterval
+	}
+	d, err := time.ParseDuration(c.RaftStore.SplitRegionCheckTickInterval)
+	if err != nil {
+		return defaultSplitRegionCheckTickInterval
+	}
+	return d
+}
+
+// GetRegionSplitCheckDiff returns the region split check diff in bytes.
+func (c *StoreConfig) GetRegionSplitCheckDiff() uint64 {
+	if c == nil || len(c.RaftStore.RegionSplitCheckDiff) == 0 {
+		return defaultRegionSplitCheckDiff
+	}
+	return typeutil.ParseBytesFromText(c.RaftStore.RegionSplitCheckDiff, defaultRegionSplitCheckDiff)
+}
+
+// GetRaftEntryMaxSize returns the raft entry max size in bytes.
+func (c *StoreConfig) GetRaftEntryMaxSize() uint64 {
+	if c == nil || len(c.RaftStore.RaftEntryMaxSize) == 0 {
+		return defaultRaftEntryMaxSize
+	}
+	return typeutil.ParseBytesFromText(c.RaftStore.RaftEntryMaxSize, defaultRaftEntryMaxSize)
+}
+
+// ConfigChange is a changed field between two store configs.
+type ConfigChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old-value"`
+	NewValue interface{} `json:"new-value"`
+}
+
+// Diff returns the changed fields from c to other, the field is named by the json tag
+// like `coprocessor.region-max-size`. A nil config is treated as an empty one.
+func (c *StoreConfig) Diff(other *StoreConfig) []ConfigChange {
+	var changes []ConfigChange
+	oldConfig, newConfig := &StoreConfig{}, &StoreConfig{}
+	if c != nil {
+		oldConfig = c
+	}
+	if other != nil {
+		newConfig = other
+	}
+	diffStruct("", reflect.ValueOf(oldConfig).Elem(), reflect.ValueOf(newConfig).Elem(), &changes)
+	return changes
+}
+
+func diffStruct(prefix string, oldValue, newValue reflect.Value, changes *[]ConfigChange) {
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			diffStruct(name, oldField, newField, changes)
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			*changes = append(*changes, ConfigChange{
+				Field:    name,
+				OldValue: oldField.Interface(),
+				NewValue: newField.Interface(),
+			})
+		}
+	}
+}
+
+// Validate is used to validate if some store configurations are right.
+func (c *StoreConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Coprocessor.RegionMaxKeys < 0 {
+		return errors.New("region-max-keys should be non-negative")
+	}
+	if c.Coprocessor.RegionSplitKeys < 0 {
+		return errors.New("region-split-keys should be non-negative")
+	}
+	if c.RaftStore.RaftLogGCThreshold < 0 {
+		return errors.New("raft-log-gc-threshold should be non-negative")
+	}
+	if interval := c.RaftStore.SplitRegionCheckTickInterval; len(interval) > 0 {
+		if _, err := time.ParseDuration(interval); err != nil {
+			return errors.Errorf("split-region-check-tick-interval %q is not a valid duration", interval)
+		}
+	}
+	for _, item := range []struct{ name, size string }{
+		{"region-max-size", c.Coprocessor.RegionMaxSize},
+		{"region-split-size", c.Coprocessor.RegionSplitSize},
+		{"region-split-check-diff", c.RaftStore.RegionSplitCheckDiff},
+		{"raft-entry-max-size", c.RaftStore.RaftEntryMaxSize},
+	} {
+		if len(item.size) == 0 {
+			continue
+		}
+		var b typeutil.ByteSize
+		if err := b.UnmarshalText([]byte(item.size)); err != nil {
+			return errors.Errorf("%s %q is not a valid size", item.name, item.size)
+		}
+	}
+	if c.GetRegionSplitSizeBytes() > c.GetRegionMaxSizeBytes() {
+		return errors.Errorf("region-split-size %s should not be larger than region-max-size %s",
+			c.Coprocessor.RegionSplitSize, c.Coprocessor.RegionMaxSize)
+	}
+	if c.GetRegionSplitKeys() > c.GetRegionMaxKeys() {
+		return errors.Errorf("region-split-keys %d should not be larger than region-max-keys %d",
+			c.GetRegionSplitKeys(), c.GetRegionMaxKeys())
+	}
+	if c.GetRaftEntryMaxSize() > c.GetRegionSplitSizeBytes() {
+		return errors.Errorf("raft-entry-max-size %s should not be larger than region-split-size %s",
+			c.RaftStore.RaftEntryMaxSize, c.Coprocessor.RegionSplitSize)
+	}
+	return nil
+}
+
 // UpdateConfig updates the config with given config map.
 func (m *StoreConfigManager) UpdateConfig(c *StoreConfig) {
 	if c == nil || m == nil {
@@ -126,32 +386,384 @@ func (m *StoreConfigManager) UpdateConfig(c *StoreConfig) {
 	atomic.StorePointer(&m.config, unsafe.Pointer(c))
 }
 
-// GetStoreConfig returns the current store configuration.
+// GetStoreConfig returns the configuration of the most recently updated store.
 func (m *StoreConfigManager) GetStoreConfig() *StoreConfig {
-	if m == nil || m.config == nil {
+	if m == nil {
 		return nil
 	}
 	config := atomic.LoadPointer(&m.config)
 	return (*StoreConfig)(config)
 }
 
+// GetStoreConfigFor returns the configuration of the store with the given status address.
+func (m *StoreConfigManager) GetStoreConfigFor(addr string) *StoreConfig {
+	if m == nil {
+		return nil
+	}
+	if entry := m.getStoreConfigEntry(addr); entry != nil {
+		return entry.config
+	}
+	return nil
+}
+
+func (m *StoreConfigManager) getStoreConfigEntry(addr string) *storeConfigEntry {
+	if entry, ok := m.stores.Load(addr); ok {
+		return entry.(*storeConfigEntry)
+	}
+	return nil
+}
+
+func (m *StoreConfigManager) updateStoreConfig(addr string, c *StoreConfig, header http.Header) {
+	m.stores.Store(addr, &storeConfigEntry{
+		config:       c,
+		loadTime:     time.Now(),
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+	})
+	m.UpdateConfig(c)
+}
+
+// pruneStoreConfigs removes the configs which are not refreshed within the ttl.
+func (m *StoreConfigManager) pruneStoreConfigs() {
+	ttl := m.ttl
+	if ttl <= 0 {
+		ttl = defaultStoreConfigTTL
+	}
+	m.stores.Range(func(key, value interface{}) bool {
+		if time.Since(value.(*storeConfigEntry).loadTime) > ttl {
+			log.Info("remove expired store config", zap.String("status-address", key.(string)))
+			m.stores.Delete(key)
+		}
+		return true
+	})
+}
+
+// getBreaker returns the circuit breaker of the store with the given status
+// address, it returns nil if the circuit breaker is disabled.
+func (m *StoreConfigManager) getBreaker(addr string) *circuitBreaker {
+	if m.breakerConfig.FailureThreshold <= 0 {
+		return nil
+	}
+	if b, ok := m.breakers.Load(addr); ok {
+		return b.(*circuitBreaker)
+	}
+	b, _ := m.breakers.LoadOrStore(addr, &circuitBreaker{config: m.breakerConfig})
+	return b.(*circuitBreaker)
+}
+
+// BreakerState returns the current state of the circuit breaker of the store
+// with the given status address.
+func (m *StoreConfigManager) BreakerState(statusAddress string) CircuitState {
+	if b, ok := m.breakers.Load(statusAddress); ok {
+		return b.(*circuitBreaker).state()
+	}
+	return CircuitClosed
+}
+
+// BreakerStates returns the states of the circuit breakers of all the stores
+// which have been requested, the key is the status address.
+func (m *StoreConfigManager) BreakerStates() map[string]CircuitState {
+	states := make(map[string]CircuitState)
+	m.breakers.Range(func(key, value interface{}) bool {
+		states[key.(string)] = value.(*circuitBreaker).state()
+		return true
+	})
+	return states
+}
+
 // Load Loads the store configuration.
+// It returns ErrStoreUnreachable if the store can't be reached after all the retries,
+// ErrStoreConfigStatus if the store responds a non-retryable error status,
+// and ErrCircuitOpen without any request if the circuit breaker is open.
 func (m *StoreConfigManager) Load(statusAddress string) error {
+	defer m.pruneStoreConfigs()
+	breaker := m.getBreaker(statusAddress)
+	ok, probe := breaker.allow(statusAddress)
+	if !ok {
+		return errs.ErrCircuitOpen.FastGenByArgs(statusAddress)
+	}
 	url := fmt.Sprintf("%s://%s/config", m.schema, statusAddress)
-	resp, err := m.client.Get(url)
+	entry := m.getStoreConfigEntry(statusAddress)
+	resp, err := m.fetch(url, entry)
+	breaker.record(statusAddress, probe, err == nil)
 	if err != nil {
-		return err
+		if errs.ErrStoreConfigStatus.Equal(err) {
+			return err
+		}
+		return errs.ErrStoreUnreachable.GenWithStackByArgs(statusAddress, err)
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if resp.notModified {
+		if entry != nil {
+			refreshed := *entry
+			refreshed.loadTime = time.Now()
+			m.stores.Store(statusAddress, &refreshed)
+		}
+		return nil
 	}
 	var cfg StoreConfig
-	if err := json.Unmarshal(body, &cfg); err != nil {
+	if err := json.Unmarshal(resp.body, &cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
 		return err
 	}
+	if changes := m.GetStoreConfigFor(statusAddress).Diff(&cfg); len(changes) > 0 {
+		log.Info("store config is changed", zap.String("status-url", url), zap.Any("changes", changes))
+	}
 	log.Info("update store config successful", zap.String("status-url", url), zap.Stringer("config", &cfg))
-	m.UpdateConfig(&cfg)
+	m.updateStoreConfig(statusAddress, &cfg, resp.header)
 	return nil
 }
+
+type fetchResult struct {
+	body   []byte
+	header http.Header
+	// notModified is true if the store responds 304 to the conditional request.
+	notModified bool
+}
+
+// fetch requests the given url and retries if the request fails with the transient errors.
+// The statuses other than 2xx and 304 are not retried.
+// The conditional headers are sent if the validators of the last response are in the entry.
+func (m *StoreConfigManager) fetch(url string, entry *storeConfigEntry) (*fetchResult, error) {
+	var lastErr error
+	backoff := m.backoff
+	attempts := m.maxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			log.Warn("failed to request the store config, retrying", zap.String("status-url", url), zap.Int("attempt", i), zap.Error(lastErr))
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, errs.ErrNewHTTPRequest.Wrap(err).GenWithStackByCause()
+		}
+		if len(m.secret) > 0 {
+			req.Header.Set(StoreConfigSignatureHeader, signStoreConfigRequest(m.secret, time.Now().Unix(), url, nil))
+		}
+		if entry != nil && entry.config != nil {
+			if len(entry.etag) > 0 {
+				req.Header.Set("If-None-Match", entry.etag)
+			}
+			if len(entry.lastModified) > 0 {
+				req.Header.Set("If-Modified-Since", entry.lastModified)
+			}
+		}
+		resp, err := m.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = errors.Errorf("unexpected status code %d, body: %s", resp.StatusCode, body)
+			continue
+		}
+		if resp.StatusCode != http.StatusNotModified &&
+			(resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices) {
+			return nil, errs.ErrStoreConfigStatus.FastGenByArgs(resp.StatusCode, url, body)
+		}
+		return &fetchResult{
+			body:        body,
+			header:      resp.Header,
+			notModified: resp.StatusCode == http.StatusNotModified,
+		}, nil
+	}
+	return nil, lastErr
+}
+
+// signStoreConfigRequest returns the value of StoreConfigSignatureHeader.
+func signStoreConfigRequest(secret []byte, timestamp int64, url string, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte(url))
+	mac.Write(body)
+	return "t=" + ts + ",sig=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// StartAutoRefresh starts a goroutine to load the store config from the given
+// address periodically. It exits when the context is canceled or StopAutoRefresh
+// is called. The previous auto refresh goroutine will be stopped if exists.
+func (m *StoreConfigManager) StartAutoRefresh(ctx context.Context, statusAddress string, interval time.Duration) {
+	m.StopAutoRefresh()
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	ctx, cancel := context.WithCancel(ctx)
+	m.refreshMu.cancel = cancel
+	m.refreshMu.wg.Add(1)
+	go m.autoRefresh(ctx, statusAddress, interval)
+}
+
+// StopAutoRefresh stops the auto refresh goroutine and waits for it to exit.
+func (m *StoreConfigManager) StopAutoRefresh() {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	if m.refreshMu.cancel == nil {
+		return
+	}
+	m.refreshMu.cancel()
+	m.refreshMu.cancel = nil
+	m.refreshMu.wg.Wait()
+}
+
+func (m *StoreConfigManager) autoRefresh(ctx context.Context, statusAddress string, interval time.Duration) {
+	defer logutil.LogPanic()
+	defer m.refreshMu.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("store config auto refresh is stopped", zap.String("status-address", statusAddress))
+			return
+		case <-ticker.C:
+			if err := m.Load(statusAddress); err != nil {
+				log.Error("refresh store config failed", zap.String("status-address", statusAddress), errs.ZapError(err))
+			}
+		}
+	}
+}
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+// The states of the circuit breaker.
+const (
+	// CircuitClosed means the requests are allowed.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means the requests are rejected.
+	CircuitOpen
+	// CircuitHalfOpen means the requests are allowed to probe whether the store is recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// MarshalJSON returns the name of the state.
+func (s CircuitState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// CircuitBreakerConfig is the config of the circuit breaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures to open the breaker.
+	// The breaker is disabled if it's not positive.
+	FailureThreshold int
+	// RecoveryTimeout is the duration to wait before probing the store after the breaker is opened.
+	RecoveryTimeout time.Duration
+	// SuccessThreshold is the number of consecutive successes in half-open state to close the breaker.
+	SuccessThreshold int
+}
+
+// circuitBreaker is the circuit breaker of a store. A nil circuitBreaker
+// allows all the requests.
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu        sync.Mutex
+	current   CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+	// probing is true if a probe request is in flight in half-open state.
+	probing bool
+}
+
+func (b *circuitBreaker) state() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current
+}
+
+// allow checks whether a request to the store with the given status address is
+// allowed, and turns the breaker to half-open if it has been opened for longer
+// than the recovery timeout. Only one probe request is allowed at a time in
+// half-open state, probe reports whether the allowed request is the probe.
+func (b *circuitBreaker) allow(addr string) (ok, probe bool) {
+	if b == nil {
+		return true, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.config.RecoveryTimeout {
+			return false, false
+		}
+		log.Info("circuit breaker turns to half-open", zap.String("status-address", addr))
+		b.current = CircuitHalfOpen
+		b.successes = 0
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, false
+		}
+	default:
+		return true, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// record records the result of a request to the store with the given status
+// address. The results of the requests other than the probe are ignored in
+// half-open state.
+func (b *circuitBreaker) record(addr string, probe, success bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current {
+	case CircuitClosed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.open(addr)
+		}
+	case CircuitHalfOpen:
+		if !probe {
+			return
+		}
+		b.probing = false
+		if !success {
+			b.open(addr)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.SuccessThreshold {
+			log.Info("circuit breaker is closed", zap.String("status-address", addr))
+			b.current = CircuitClosed
+			b.failures = 0
+		}
+	}
+}
+
+func (b *circuitBreaker) open(addr string) {
+	log.Warn("circuit breaker is opened", zap.String("status-address", addr), zap.Int("failures", b.failures), zap.Duration("recovery-timeout", b.config.RecoveryTimeout))
+	b.current = CircuitOpen
+	b.openedAt = time.Now()
+}
@@ -15,10 +15,23 @@
 package config
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/errs"
 )
 
 var _ = Suite(&testTiKVConfigSuite{})
@@ -61,6 +74,221 @@ func (t *testTiKVConfigSuite) TestTiKVConfig(c *C) {
 	}
 }
 
+func (t *testTiKVConfigSuite) TestRaftStoreConfig(c *C) {
+	// captured from the `/config` API of TiKV, some irrelevant items are omitted.
+	body := `{
+  "log-level": "info",
+  "raftstore": {
+    "prevote": true,
+    "raftdb-path": "/var/lib/tikv/raft",
+    "capacity": "0KiB",
+    "raft-base-tick-interval": "1s",
+    "raft-heartbeat-ticks": 2,
+    "raft-election-timeout-ticks": 10,
+    "raft-min-election-timeout-ticks": 10,
+    "raft-max-election-timeout-ticks": 20,
+    "raft-max-size-per-msg": "1MiB",
+    "raft-max-inflight-msgs": 256,
+    "raft-entry-max-size": "8MiB",
+    "raft-log-gc-tick-interval": "10s",
+    "raft-log-gc-threshold": 50,
+    "raft-log-gc-count-limit": 73728,
+    "raft-log-gc-size-limit": "72MiB",
+    "split-region-check-tick-interval": "10s",
+    "region-split-check-diff": "6MiB",
+    "region-compact-check-interval": "5m",
+    "pd-heartbeat-tick-interval": "1m",
+    "pd-store-heartbeat-tick-interval": "10s"
+  },
+  "coprocessor": {
+    "split-region-on-table": false,
+    "batch-split-limit": 10,
+    "region-max-size": "144MiB",
+    "region-split-size": "96MiB",
+    "region-max-keys": 1440000,
+    "region-split-keys": 960000,
+    "consistency-check-method": "mvcc"
+  }
+}`
+	var config StoreConfig
+	c.Assert(json.Unmarshal([]byte(body), &config), IsNil)
+	c.Assert(config.Validate(), IsNil)
+	c.Assert(config.GetRaftLogGCThreshold(), Equals, uint64(50))
+	c.Assert(config.GetSplitRegionCheckTickInterval(), Equals, 10*time.Second)
+	c.Assert(config.GetRegionSplitCheckDiff(), Equals, uint64(6*units.MiB))
+	c.Assert(config.GetRaftEntryMaxSize(), Equals, uint64(8*units.MiB))
+	c.Assert(config.GetRegionMaxSize(), Equals, uint64(144))
+	c.Assert(config.GetRegionSplitKeys(), Equals, uint64(960000))
+
+	// defaults.
+	var empty *StoreConfig
+	c.Assert(empty.GetRaftLogGCThreshold(), Equals, uint64(50))
+	c.Assert(empty.GetSplitRegionCheckTickInterval(), Equals, 10*time.Second)
+	c.Assert(empty.GetRegionSplitCheckDiff(), Equals, uint64(6*units.MiB))
+	c.Assert(empty.GetRaftEntryMaxSize(), Equals, uint64(8*units.MiB))
+	invalid := &StoreConfig{RaftStore: RaftStore{
+		RaftLogGCThreshold:           -1,
+		SplitRegionCheckTickInterval: "abc",
+		RegionSplitCheckDiff:         "abc",
+		RaftEntryMaxSize:             "abc",
+	}}
+	c.Assert(invalid.GetRaftLogGCThreshold(), Equals, uint64(50))
+	c.Assert(invalid.GetSplitRegionCheckTickInterval(), Equals, 10*time.Second)
+	c.Assert(invalid.GetRegionSplitCheckDiff(), Equals, uint64(6*units.MiB))
+	c.Assert(invalid.GetRaftEntryMaxSize(), Equals, uint64(8*units.MiB))
+
+	// the raftstore fields are reported by Diff.
+	changed := config
+	c.Assert(config.Diff(&changed), HasLen, 0)
+	changed.RaftStore.RaftEntryMaxSize = "16MiB"
+	changes := config.Diff(&changed)
+	c.Assert(changes, DeepEquals, []ConfigChange{
+		{Field: "raftstore.raft-entry-max-size", OldValue: "8MiB", NewValue: "16MiB"},
+	})
+}
+
+func (t *testTiKVConfigSuite) TestRegionKeysBoundary(c *C) {
+	testdata := []struct {
+		keys      int
+		maxKeys   uint64
+		splitKeys uint64
+		isValid   bool
+	}{
+		{math.MinInt32, defaultRegionMaxKey, defaultRegionSplitKey, false},
+		{-1, defaultRegionMaxKey, defaultRegionSplitKey, false},
+		{0, defaultRegionMaxKey, defaultRegionSplitKey, true},
+		{1, 1, 1, true},
+		{math.MaxInt32, math.MaxInt32, math.MaxInt32, true},
+	}
+	for _, data := range testdata {
+		config := &StoreConfig{Coprocessor: Coprocessor{RegionMaxKeys: data.keys, RegionSplitKeys: data.keys}}
+		c.Assert(config.GetRegionMaxKeys(), Equals, data.maxKeys, Commentf("keys: %d", data.keys))
+		c.Assert(config.GetRegionSplitKeys(), Equals, data.splitKeys, Commentf("keys: %d", data.keys))
+		c.Assert(config.Validate() == nil, Equals, data.isValid, Commentf("keys: %d", data.keys))
+	}
+}
+
+func (t *testTiKVConfigSuite) TestRegionSizeBytes(c *C) {
+	testdata := []struct {
+		size  string
+		bytes uint64
+	}{
+		{"144MiB", 144 * units.MiB},
+		{"512MB", 512 * units.MiB},
+		{"10GiB", 10 * units.GiB},
+		{"512KB", 512 * units.KiB},
+		{"1048577", 1048577},
+		{"", 144 * units.MiB},
+		{"invalid", 144 * units.MiB},
+	}
+	for _, data := range testdata {
+		config := &StoreConfig{Coprocessor: Coprocessor{RegionMaxSize: data.size, RegionSplitSize: data.size}}
+		c.Assert(config.GetRegionMaxSizeBytes(), Equals, data.bytes, Commentf("size: %s", data.size))
+	}
+	var config *StoreConfig
+	c.Assert(config.GetRegionMaxSizeBytes(), Equals, uint64(144*units.MiB))
+	c.Assert(config.GetRegionSplitSizeBytes(), Equals, uint64(96*units.MiB))
+	config = &StoreConfig{Coprocessor: Coprocessor{RegionSplitSize: "512KB"}}
+	c.Assert(config.GetRegionSplitSizeBytes(), Equals, uint64(512*units.KiB))
+	c.Assert(config.GetRegionSplitSize(), Equals, uint64(0))
+}
+
+func (t *testTiKVConfigSuite) TestValidate(c *C) {
+	testdata := []struct {
+		config  Coprocessor
+		isValid bool
+	}{
+		{Coprocessor{}, true},
+		{Coprocessor{RegionMaxSize: "15GiB", RegionSplitSize: "10GiB", RegionMaxKeys: 144000000, RegionSplitKeys: 96000000}, true},
+		{Coprocessor{RegionMaxSize: "96MiB", RegionSplitSize: "96MiB", RegionMaxKeys: 960000, RegionSplitKeys: 960000}, true},
+		// split size is larger than max size.
+		{Coprocessor{RegionMaxSize: "96MiB", RegionSplitSize: "144MiB"}, false},
+		// split size is larger than the default max size.
+		{Coprocessor{RegionSplitSize: "1GiB"}, false},
+		// split keys is larger than max keys.
+		{Coprocessor{RegionMaxKeys: 960000, RegionSplitKeys: 1440000}, false},
+		// sizes can't be parsed.
+		{Coprocessor{RegionMaxSize: "abc"}, false},
+		{Coprocessor{RegionSplitSize: "10XiB"}, false},
+		// negative keys.
+		{Coprocessor{RegionMaxKeys: -1}, false},
+		{Coprocessor{RegionSplitKeys: -1}, false},
+	}
+	for i, data := range testdata {
+		config := &StoreConfig{Coprocessor: data.config}
+		err := config.Validate()
+		if data.isValid {
+			c.Assert(err, IsNil, Commentf("case %d", i))
+		} else {
+			c.Assert(err, NotNil, Commentf("case %d", i))
+		}
+	}
+
+	raftStoreTestdata := []struct {
+		config  RaftStore
+		isValid bool
+	}{
+		{RaftStore{}, true},
+		{RaftStore{RaftLogGCThreshold: 50, SplitRegionCheckTickInterval: "10s", RegionSplitCheckDiff: "6MiB", RaftEntryMaxSize: "8MiB"}, true},
+		// raft entry max size is equal to the default region split size.
+		{RaftStore{RaftEntryMaxSize: "96MiB"}, true},
+		// raft entry max size is larger than the region split size.
+		{RaftStore{RaftEntryMaxSize: "128MiB"}, false},
+		{RaftStore{RaftLogGCThreshold: -1}, false},
+		{RaftStore{SplitRegionCheckTickInterval: "10"}, false},
+		{RaftStore{RegionSplitCheckDiff: "abc"}, false},
+		{RaftStore{RaftEntryMaxSize: "abc"}, false},
+	}
+	for i, data := range raftStoreTestdata {
+		config := &StoreConfig{RaftStore: data.config}
+		err := config.Validate()
+		if data.isValid {
+			c.Assert(err, IsNil, Commentf("case %d", i))
+		} else {
+			c.Assert(err, NotNil, Commentf("case %d", i))
+		}
+	}
+	var config *StoreConfig
+	c.Assert(config.Validate(), IsNil)
+}
+
+func (t *testTiKVConfigSuite) TestLoadInvalidConfig(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"96MiB","region-split-size":"144MiB"}}`))
+	}))
+	defer server.Close()
+	manager := NewStoreConfigManager(nil)
+	manager.UpdateConfig(&StoreConfig{Coprocessor: Coprocessor{RegionMaxSize: "15GiB"}})
+	c.Assert(manager.Load(strings.TrimPrefix(server.URL, "http://")), NotNil)
+	c.Assert(manager.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(15*1024))
+}
+
+func (t *testTiKVConfigSuite) TestDiff(c *C) {
+	old := &StoreConfig{Coprocessor: Coprocessor{RegionMaxSize: "144MiB", RegionMaxKeys: 1440000}}
+	// zero diff.
+	c.Assert(old.Diff(&StoreConfig{Coprocessor: Coprocessor{RegionMaxSize: "144MiB", RegionMaxKeys: 1440000}}), HasLen, 0)
+	// single field diff.
+	changes := old.Diff(&StoreConfig{Coprocessor: Coprocessor{RegionMaxSize: "15GiB", RegionMaxKeys: 1440000}})
+	c.Assert(changes, DeepEquals, []ConfigChange{
+		{Field: "coprocessor.region-max-size", OldValue: "144MiB", NewValue: "15GiB"},
+	})
+	// multiple fields diff.
+	changes = old.Diff(&StoreConfig{Coprocessor: Coprocessor{RegionSplitSize: "96MiB", RegionMaxKeys: 1440000, RegionSplitKeys: 960000}})
+	c.Assert(changes, DeepEquals, []ConfigChange{
+		{Field: "coprocessor.region-max-size", OldValue: "144MiB", NewValue: ""},
+		{Field: "coprocessor.region-split-size", OldValue: "", NewValue: "96MiB"},
+		{Field: "coprocessor.region-split-keys", OldValue: 0, NewValue: 960000},
+	})
+	// nil receiver and nil argument.
+	var empty *StoreConfig
+	c.Assert(empty.Diff(nil), HasLen, 0)
+	c.Assert(empty.Diff(&StoreConfig{}), HasLen, 0)
+	changes = empty.Diff(old)
+	c.Assert(changes, HasLen, 2)
+	c.Assert(changes[0], DeepEquals, ConfigChange{Field: "coprocessor.region-max-size", OldValue: "", NewValue: "144MiB"})
+	c.Assert(old.Diff(nil), HasLen, 2)
+}
+
 func (t *testTiKVConfigSuite) TestUpdateConfig(c *C) {
 	manager := NewStoreConfigManager(nil)
 	c.Assert(manager.schema, Equals, "http")
@@ -68,7 +296,7 @@ func (t *testTiKVConfigSuite) TestUpdateConfig(c *C) {
 	manager = NewStoreConfigManager(tlsConfig)
 	c.Assert(manager.schema, Equals, "http")
 	config := &StoreConfig{
-		Coprocessor{
+		Coprocessor: Coprocessor{
 			RegionMaxSize: "15GiB",
 		},
 	}
@@ -80,3 +308,379 @@ func (t *testTiKVConfigSuite) TestUpdateConfig(c *C) {
 	m.UpdateConfig(nil)
 	c.Assert(m.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(144))
 }
+
+func (t *testTiKVConfigSuite) TestLoadWithRetry(c *C) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	// no retry by default.
+	manager := NewStoreConfigManager(nil, WithTimeout(time.Second))
+	err := manager.Load(addr)
+	c.Assert(errs.ErrStoreUnreachable.Equal(err), IsTrue)
+	c.Assert(manager.GetStoreConfig(), IsNil)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(1))
+
+	// succeed at the third attempt.
+	atomic.StoreInt32(&count, 0)
+	manager = NewStoreConfigManager(nil, WithTimeout(time.Second), WithRetry(3, time.Millisecond))
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(3))
+	c.Assert(manager.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(15*1024))
+
+	// all the retries are exhausted.
+	atomic.StoreInt32(&count, 0)
+	manager = NewStoreConfigManager(nil, WithRetry(2, time.Millisecond))
+	err = manager.Load(addr)
+	c.Assert(errs.ErrStoreUnreachable.Equal(err), IsTrue)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+
+	// the request times out.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	manager = NewStoreConfigManager(nil, WithTimeout(10*time.Millisecond))
+	err = manager.Load(strings.TrimPrefix(slow.URL, "http://"))
+	c.Assert(errs.ErrStoreUnreachable.Equal(err), IsTrue)
+
+	// the client errors are not retried.
+	atomic.StoreInt32(&count, 0)
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+	defer notFound.Close()
+	manager = NewStoreConfigManager(nil, WithRetry(3, time.Millisecond))
+	err = manager.Load(strings.TrimPrefix(notFound.URL, "http://"))
+	c.Assert(errs.ErrStoreConfigStatus.Equal(err), IsTrue)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(1))
+	c.Assert(manager.GetStoreConfig(), IsNil)
+}
+
+func (t *testTiKVConfigSuite) TestAutoRefresh(c *C) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	manager := NewStoreConfigManager(nil)
+
+	// stop by canceling the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	manager.StartAutoRefresh(ctx, addr, 10*time.Millisecond)
+	waitRequests(c, &count, 3)
+	c.Assert(manager.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(15*1024))
+	cancel()
+	manager.refreshMu.wg.Wait()
+	stopped := atomic.LoadInt32(&count)
+	time.Sleep(50 * time.Millisecond)
+	c.Assert(atomic.LoadInt32(&count), Equals, stopped)
+	manager.StopAutoRefresh()
+
+	// stop explicitly.
+	manager.StartAutoRefresh(context.Background(), addr, 10*time.Millisecond)
+	waitRequests(c, &count, stopped+3)
+	manager.StopAutoRefresh()
+	stopped = atomic.LoadInt32(&count)
+	time.Sleep(50 * time.Millisecond)
+	c.Assert(atomic.LoadInt32(&count), Equals, stopped)
+	// stop again is no-op.
+	manager.StopAutoRefresh()
+}
+
+func waitRequests(c *C, count *int32, expect int32) {
+	for i := 0; i < 100; i++ {
+		if atomic.LoadInt32(count) >= expect {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Fatalf("wait for %d requests timeout, got %d", expect, atomic.LoadInt32(count))
+}
+
+func (t *testTiKVConfigSuite) TestStoreConfigTTL(c *C) {
+	newServer := func(size string) (*httptest.Server, string) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(fmt.Sprintf(`{"coprocessor":{"region-max-size":"%s"}}`, size)))
+		}))
+		return server, strings.TrimPrefix(server.URL, "http://")
+	}
+	server1, addr1 := newServer("15GiB")
+	defer server1.Close()
+	server2, addr2 := newServer("10GiB")
+	defer server2.Close()
+
+	manager := NewStoreConfigManager(nil, WithConfigTTL(time.Minute))
+	c.Assert(manager.GetStoreConfigFor(addr1), IsNil)
+	c.Assert(manager.Load(addr1), IsNil)
+	c.Assert(manager.Load(addr2), IsNil)
+	c.Assert(manager.GetStoreConfigFor(addr1).GetRegionMaxSize(), Equals, uint64(15*1024))
+	c.Assert(manager.GetStoreConfigFor(addr2).GetRegionMaxSize(), Equals, uint64(10*1024))
+	// returns the most recently updated one.
+	c.Assert(manager.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(10*1024))
+
+	// make the config of store1 expired.
+	entry, ok := manager.stores.Load(addr1)
+	c.Assert(ok, IsTrue)
+	entry.(*storeConfigEntry).loadTime = time.Now().Add(-2 * time.Minute)
+	c.Assert(manager.Load(addr2), IsNil)
+	c.Assert(manager.GetStoreConfigFor(addr1), IsNil)
+	c.Assert(manager.GetStoreConfigFor(addr2), NotNil)
+
+	// reload makes it available again.
+	c.Assert(manager.Load(addr1), IsNil)
+	c.Assert(manager.GetStoreConfigFor(addr1).GetRegionMaxSize(), Equals, uint64(15*1024))
+	c.Assert(manager.GetStoreConfig().GetRegionMaxSize(), Equals, uint64(15*1024))
+}
+
+func (t *testTiKVConfigSuite) TestCircuitBreaker(c *C) {
+	var count int32
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	manager := NewStoreConfigManager(nil, WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		RecoveryTimeout:  50 * time.Millisecond,
+		SuccessThreshold: 2,
+	}))
+	c.Assert(manager.BreakerState(addr), Equals, CircuitClosed)
+	for i := 0; i < 3; i++ {
+		c.Assert(errs.ErrStoreUnreachable.Equal(manager.Load(addr)), IsTrue)
+	}
+	c.Assert(manager.BreakerState(addr), Equals, CircuitOpen)
+	// no request is sent when the breaker is open.
+	c.Assert(errs.ErrCircuitOpen.Equal(manager.Load(addr)), IsTrue)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(3))
+
+	// the probe fails and the breaker is opened again.
+	time.Sleep(60 * time.Millisecond)
+	c.Assert(errs.ErrStoreUnreachable.Equal(manager.Load(addr)), IsTrue)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(4))
+	c.Assert(manager.BreakerState(addr), Equals, CircuitOpen)
+	c.Assert(errs.ErrCircuitOpen.Equal(manager.Load(addr)), IsTrue)
+
+	// the store is recovered.
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(manager.BreakerState(addr), Equals, CircuitHalfOpen)
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(manager.BreakerState(addr), Equals, CircuitClosed)
+	c.Assert(manager.BreakerState(addr).String(), Equals, "closed")
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(6))
+
+	// the breaker is disabled by default.
+	atomic.StoreInt32(&healthy, 0)
+	manager = NewStoreConfigManager(nil)
+	for i := 0; i < 5; i++ {
+		c.Assert(errs.ErrStoreUnreachable.Equal(manager.Load(addr)), IsTrue)
+	}
+	c.Assert(manager.BreakerState(addr), Equals, CircuitClosed)
+	c.Assert(manager.BreakerStates(), HasLen, 0)
+}
+
+func (t *testTiKVConfigSuite) TestCircuitBreakerPerStore(c *C) {
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer healthy.Close()
+	addr1 := strings.TrimPrefix(unhealthy.URL, "http://")
+	addr2 := strings.TrimPrefix(healthy.URL, "http://")
+
+	manager := NewStoreConfigManager(nil, WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		RecoveryTimeout:  time.Minute,
+		SuccessThreshold: 1,
+	}))
+	for i := 0; i < 2; i++ {
+		c.Assert(errs.ErrStoreUnreachable.Equal(manager.Load(addr1)), IsTrue)
+	}
+	c.Assert(errs.ErrCircuitOpen.Equal(manager.Load(addr1)), IsTrue)
+	// the open breaker of a store doesn't block the other stores.
+	c.Assert(manager.Load(addr2), IsNil)
+	c.Assert(manager.BreakerState(addr1), Equals, CircuitOpen)
+	c.Assert(manager.BreakerState(addr2), Equals, CircuitClosed)
+	c.Assert(manager.BreakerStates(), DeepEquals, map[string]CircuitState{
+		addr1: CircuitOpen,
+		addr2: CircuitClosed,
+	})
+	data, err := json.Marshal(manager.BreakerStates())
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Matches, `\{.*"open".*\}`)
+}
+
+func (t *testTiKVConfigSuite) TestCircuitBreakerSingleProbe(c *C) {
+	var count, healthy int32
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-block
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	manager := NewStoreConfigManager(nil, WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		RecoveryTimeout:  50 * time.Millisecond,
+		SuccessThreshold: 1,
+	}))
+	c.Assert(errs.ErrStoreUnreachable.Equal(manager.Load(addr)), IsTrue)
+	c.Assert(manager.BreakerState(addr), Equals, CircuitOpen)
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	probeCh := make(chan error, 1)
+	go func() {
+		probeCh <- manager.Load(addr)
+	}()
+	for i := 0; i < 100 && atomic.LoadInt32(&count) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+	// the other requests are rejected while the probe is in flight.
+	c.Assert(manager.BreakerState(addr), Equals, CircuitHalfOpen)
+	for i := 0; i < 3; i++ {
+		c.Assert(errs.ErrCircuitOpen.Equal(manager.Load(addr)), IsTrue)
+	}
+	close(block)
+	c.Assert(<-probeCh, IsNil)
+	c.Assert(manager.BreakerState(addr), Equals, CircuitClosed)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+}
+
+func (t *testTiKVConfigSuite) TestConditionalRequest(c *C) {
+	const (
+		etag         = `"v1"`
+		lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+	)
+	var requests, conditional, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			atomic.AddInt32(&conditional, 1)
+			if atomic.LoadInt32(&notModified) == 1 {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	manager := NewStoreConfigManager(nil)
+	// the first request is unconditional.
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(atomic.LoadInt32(&conditional), Equals, int32(0))
+	config := manager.GetStoreConfigFor(addr)
+	c.Assert(config.GetRegionMaxSize(), Equals, uint64(15*1024))
+
+	// the store responds the full config.
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(atomic.LoadInt32(&conditional), Equals, int32(1))
+	c.Assert(manager.GetStoreConfigFor(addr) == config, IsFalse)
+	config = manager.GetStoreConfigFor(addr)
+
+	// the store responds 304 and the cached config is kept.
+	atomic.StoreInt32(&notModified, 1)
+	c.Assert(manager.Load(addr), IsNil)
+	c.Assert(atomic.LoadInt32(&conditional), Equals, int32(2))
+	c.Assert(manager.GetStoreConfigFor(addr) == config, IsTrue)
+	c.Assert(manager.GetStoreConfig() == config, IsTrue)
+	c.Assert(atomic.LoadInt32(&requests), Equals, int32(3))
+}
+
+// verifyStoreConfigSignature verifies the signature as the store does.
+func verifyStoreConfigSignature(secret []byte, signature, url string, body []byte) bool {
+	var ts, sig string
+	for _, part := range strings.Split(signature, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return false
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "sig":
+			sig = kv[1]
+		}
+	}
+	if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+		return false
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + url))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func (t *testTiKVConfigSuite) TestSignRequest(c *C) {
+	secret := []byte("secret")
+	var signature atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature.Store(r.Header.Get(StoreConfigSignatureHeader))
+		w.Write([]byte(`{"coprocessor":{"region-max-size":"15GiB"}}`))
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	url := fmt.Sprintf("http://%s/config", addr)
+
+	// the request is not signed without the secret.
+	c.Assert(NewStoreConfigManager(&SecurityConfig{}).Load(addr), IsNil)
+	c.Assert(signature.Load(), Equals, "")
+
+	before := time.Now().Unix()
+	manager := NewStoreConfigManager(&SecurityConfig{InternalSharedSecret: string(secret)})
+	c.Assert(manager.Load(addr), IsNil)
+	header := signature.Load().(string)
+	c.Assert(header, Matches, "t=[0-9]+,sig=[0-9a-f]{64}")
+	ts, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(header, ",")[0], "t="), 10, 64)
+	c.Assert(err, IsNil)
+	c.Assert(ts >= before && ts <= time.Now().Unix(), IsTrue)
+	c.Assert(verifyStoreConfigSignature(secret, header, url, nil), IsTrue)
+
+	// the tampered requests are detected.
+	c.Assert(verifyStoreConfigSignature(secret, header, url, []byte("{}")), IsFalse)
+	c.Assert(verifyStoreConfigSignature(secret, header, url+"?x=1", nil), IsFalse)
+	c.Assert(verifyStoreConfigSignature([]byte("other"), header, url, nil), IsFalse)
+	body := []byte(`{"region-max-size":"15GiB"}`)
+	signed := signStoreConfigRequest(secret, ts, url, body)
+	c.Assert(verifyStoreConfigSignature(secret, signed, url, body), IsTrue)
+	c.Assert(verifyStoreConfigSignature(secret, signed, url, []byte(`{"region-max-size":"1GiB"}`)), IsFalse)
+	c.Assert(verifyStoreConfigSignature(secret, strings.Replace(signed, "t=", "t=1", 1), url, body), IsFalse)
+}
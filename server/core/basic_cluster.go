@@ -103,6 +103,16 @@ func (bc *BasicCluster) GetRegionStores(region *RegionInfo) []*StoreInfo {
 	return Stores
 }
 
+// RegionStoreLabels returns the union of the labels of the stores holding the
+// replicas of the region, nil if the region does not exist.
+func (bc *BasicCluster) RegionStoreLabels(regionID uint64) map[string][]string {
+	region := bc.GetRegion(regionID)
+	if region == nil {
+		return nil
+	}
+	return UnionStoreLabels(bc.GetRegionStores(region))
+}
+
 // GetFollowerStores returns all Stores that contains the region's follower peer.
 func (bc *BasicCluster) GetFollowerStores(region *RegionInfo) []*StoreInfo {
 	bc.RLock()
@@ -497,6 +497,12 @@ func (r *RegionInfo) GetPeers() []*metapb.Peer {
 	return r.meta.GetPeers()
 }
 
+// InJointState returns true if the region is in the joint configuration, i.e.
+// some of its peers are incoming or demoting voters.
+func (r *RegionInfo) InJointState() bool {
+	return IsInJointState(r.GetPeers()...)
+}
+
 // GetRegionEpoch returns the region epoch of the region.
 func (r *RegionInfo) GetRegionEpoch() *metapb.RegionEpoch {
 	return r.meta.RegionEpoch
@@ -16,6 +16,7 @@ package core
 
 import (
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -35,6 +36,9 @@ const (
 	initialMaxRegionCounts = 30      // exclude storage Threshold Filter when region less than 30
 	initialMinSpace        = 1 << 33 // 2^33=8GB
 	slowStoreThreshold     = 80
+	// slowLatencyHeartbeats is the number of the consecutive heartbeats
+	// exceeding the latency thresholds to mark a store as slow.
+	slowLatencyHeartbeats = 2
 
 	// EngineKey is the label key used to indicate engine.
 	EngineKey = "engine"
@@ -42,6 +46,13 @@ const (
 	EngineTiFlash = "tiflash"
 	// EngineTiKV indicates the tikv engine in metrics
 	EngineTiKV = "tikv"
+
+	// P99ReadLatencyKey is the key of the p99 read latency in microseconds
+	// in the op latencies of the store heartbeat.
+	P99ReadLatencyKey = "p99_read_latency_us"
+	// P99WriteLatencyKey is the key of the p99 write latency in microseconds
+	// in the op latencies of the store heartbeat.
+	P99WriteLatencyKey = "p99_write_latency_us"
 )
 
 // StoreInfo contains information about a store.
@@ -61,6 +72,19 @@ type StoreInfo struct {
 	regionWeight        float64
 	limiter             map[storelimit.Type]*storelimit.StoreLimit
 	minResolvedTS       uint64
+	// drainStartTime is the time when the store starts draining, it is zero
+	// if the store is not draining.
+	drainStartTime time.Time
+	// warmUpStartTime and warmUpDuration control the warm-up of a newly
+	// joined store, during which its usable capacity ramps up linearly.
+	warmUpStartTime time.Time
+	warmUpDuration  time.Duration
+	// latencySlowTimes is the number of the consecutive heartbeats whose p99
+	// latency exceeds the thresholds.
+	latencySlowTimes int
+	// labelWarnings are the warnings of the labels not matching the location
+	// labels, which are checked when the store heartbeats.
+	labelWarnings []string
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -87,6 +111,11 @@ func (s *StoreInfo) Clone(opts ...StoreCreateOption) *StoreInfo {
 		storeStats:          s.storeStats,
 		pauseLeaderTransfer: s.pauseLeaderTransfer,
 		slowStoreEvicted:    s.slowStoreEvicted,
+		drainStartTime:      s.drainStartTime,
+		warmUpStartTime:     s.warmUpStartTime,
+		warmUpDuration:      s.warmUpDuration,
+		latencySlowTimes:    s.latencySlowTimes,
+		labelWarnings:       s.labelWarnings,
 		leaderCount:         s.leaderCount,
 		regionCount:         s.regionCount,
 		leaderSize:          s.leaderSize,
@@ -112,6 +141,11 @@ func (s *StoreInfo) ShallowClone(opts ...StoreCreateOption) *StoreInfo {
 		storeStats:          s.storeStats,
 		pauseLeaderTransfer: s.pauseLeaderTransfer,
 		slowStoreEvicted:    s.slowStoreEvicted,
+		drainStartTime:      s.drainStartTime,
+		warmUpStartTime:     s.warmUpStartTime,
+		warmUpDuration:      s.warmUpDuration,
+		latencySlowTimes:    s.latencySlowTimes,
+		labelWarnings:       s.labelWarnings,
 		leaderCount:         s.leaderCount,
 		regionCount:         s.regionCount,
 		leaderSize:          s.leaderSize,
@@ -141,6 +175,49 @@ func (s *StoreInfo) EvictedAsSlowStore() bool {
 	return s.slowStoreEvicted
 }
 
+// IsDraining returns if all regions are being moved away from the store, it
+// should not be selected as the target of new peers and leaders.
+func (s *StoreInfo) IsDraining() bool {
+	return !s.drainStartTime.IsZero()
+}
+
+// GetDrainStartTime returns the time when the store starts draining.
+func (s *StoreInfo) GetDrainStartTime() time.Time {
+	return s.drainStartTime
+}
+
+// IsWarmingUp returns if the store is warming up, the capacity of it is not
+// fully considered usable yet.
+func (s *StoreInfo) IsWarmingUp() bool {
+	return s.GetWarmUpRatio() < 1
+}
+
+// GetWarmUpRatio returns the ratio of the capacity which is considered usable,
+// it ramps linearly from 0 to 1 during the warm-up.
+func (s *StoreInfo) GetWarmUpRatio() float64 {
+	if s.warmUpDuration <= 0 {
+		return 1
+	}
+	elapsed := time.Since(s.warmUpStartTime)
+	if elapsed >= s.warmUpDuration {
+		return 1
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(elapsed) / float64(s.warmUpDuration)
+}
+
+// GetWarmUpStartTime returns the time when the store starts warming up.
+func (s *StoreInfo) GetWarmUpStartTime() time.Time {
+	return s.warmUpStartTime
+}
+
+// GetWarmUpDuration returns the duration of the warm-up.
+func (s *StoreInfo) GetWarmUpDuration() time.Duration {
+	return s.warmUpDuration
+}
+
 // IsAvailable returns if the store bucket of limitation is available
 func (s *StoreInfo) IsAvailable(limitType storelimit.Type) bool {
 	s.mu.RLock()
@@ -185,6 +262,31 @@ func (s *StoreInfo) IsSlow() bool {
 	return s.rawStats.GetSlowScore() >= slowStoreThreshold
 }
 
+// ExceedLatencyThreshold checks if the p99 read or write latency reported by
+// the last heartbeat exceeds the thresholds, 0 means no threshold.
+func (s *StoreInfo) ExceedLatencyThreshold(readThresholdUs, writeThresholdUs uint64) bool {
+	return (readThresholdUs > 0 && s.GetP99ReadLatencyUs() > readThresholdUs) ||
+		(writeThresholdUs > 0 && s.GetP99WriteLatencyUs() > writeThresholdUs)
+}
+
+// GetLatencySlowTimes returns the number of the consecutive heartbeats whose
+// p99 latency exceeds the thresholds.
+func (s *StoreInfo) GetLatencySlowTimes() int {
+	return s.latencySlowTimes
+}
+
+// IsLatencySlow checks if the p99 latency of the store has exceeded the
+// thresholds for consecutive heartbeats.
+func (s *StoreInfo) IsLatencySlow() bool {
+	return s.latencySlowTimes >= slowLatencyHeartbeats
+}
+
+// GetLabelWarnings returns the warnings of the labels not matching the
+// location labels.
+func (s *StoreInfo) GetLabelWarnings() []string {
+	return s.labelWarnings
+}
+
 // IsPhysicallyDestroyed checks if the store's physically destroyed.
 func (s *StoreInfo) IsPhysicallyDestroyed() bool {
 	return s.GetMeta().GetPhysicallyDestroyed()
@@ -315,7 +417,7 @@ func (s *StoreInfo) RegionScore(version string, highSpaceRatio, lowSpaceRatio fl
 func (s *StoreInfo) regionScoreV1(highSpaceRatio, lowSpaceRatio float64, delta int64) float64 {
 	var score float64
 	var amplification float64
-	available := float64(s.GetAvailable()) / mb
+	available := float64(s.warmUpAvailable(s.GetAvailable())) / mb
 	used := float64(s.GetUsedSize()) / mb
 	capacity := float64(s.GetCapacity()) / mb
 
@@ -358,7 +460,7 @@ func (s *StoreInfo) regionScoreV1(highSpaceRatio, lowSpaceRatio float64, delta i
 }
 
 func (s *StoreInfo) regionScoreV2(delta int64, lowSpaceRatio float64) float64 {
-	A := float64(s.GetAvgAvailable()) / gb
+	A := float64(s.warmUpAvailable(s.GetAvgAvailable())) / gb
 	C := float64(s.GetCapacity()) / gb
 	R := float64(s.GetRegionSize() + delta)
 	if R < 0 {
@@ -392,6 +494,21 @@ func (s *StoreInfo) regionScoreV2(delta int64, lowSpaceRatio float64) float64 {
 	return score / math.Max(s.GetRegionWeight(), minWeight)
 }
 
+// warmUpAvailable returns the available size which is considered usable. The
+// capacity which is not usable yet during the warm-up is excluded, so that the
+// warming-up store looks fuller and receives fewer regions.
+func (s *StoreInfo) warmUpAvailable(available uint64) uint64 {
+	ratio := s.GetWarmUpRatio()
+	if ratio >= 1 {
+		return available
+	}
+	reserved := uint64(float64(s.GetCapacity()) * (1 - ratio))
+	if available <= reserved {
+		return 0
+	}
+	return available - reserved
+}
+
 // StorageSize returns store's used storage size reported from tikv.
 func (s *StoreInfo) StorageSize() uint64 {
 	return s.GetUsedSize()
@@ -418,6 +535,21 @@ func (s *StoreInfo) IsLowSpace(lowSpaceRatio float64) bool {
 	return s.AvailableRatio() < 1-lowSpaceRatio
 }
 
+// IsCriticalSpace checks if the store is running out of space, the space
+// reserved for compaction is regarded as used.
+func (s *StoreInfo) IsCriticalSpace(criticalSpaceRatio float64, reservedSpace uint64) bool {
+	if s.GetStoreStats() == nil || s.GetCapacity() == 0 {
+		return false
+	}
+	available := s.GetAvailable()
+	if available > reservedSpace {
+		available -= reservedSpace
+	} else {
+		available = 0
+	}
+	return float64(available)/float64(s.GetCapacity()) < 1-criticalSpaceRatio
+}
+
 // ResourceCount returns count of leader/region in the store.
 func (s *StoreInfo) ResourceCount(kind ResourceKind) uint64 {
 	switch kind {
@@ -739,3 +871,23 @@ func IsAvailableForMinResolvedTS(s *StoreInfo) bool {
 	// And we will skip tiflash, because it does not report min resolved ts.
 	return !s.IsRemoved() && !IsStoreContainLabel(s.GetMeta(), EngineKey, EngineTiFlash) && s.GetLeaderCount() != 0
 }
+
+// UnionStoreLabels returns the union of the labels of the stores, the values
+// of a label key are sorted and distinct.
+func UnionStoreLabels(stores []*StoreInfo) map[string][]string {
+	labels := make(map[string][]string)
+	for _, store := range stores {
+		for _, label := range store.GetLabels() {
+			values := labels[label.GetKey()]
+			i := sort.SearchStrings(values, label.GetValue())
+			if i < len(values) && values[i] == label.GetValue() {
+				continue
+			}
+			values = append(values, "")
+			copy(values[i+1:], values[i:])
+			values[i] = label.GetValue()
+			labels[label.GetKey()] = values
+		}
+	}
+	return labels
+}
@@ -136,6 +136,39 @@ func SlowStoreRecovered() StoreCreateOption {
 	}
 }
 
+// SetDrainStartTime sets the time when the store starts draining, the zero
+// time means the store is not draining.
+func SetDrainStartTime(startTime time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.drainStartTime = startTime
+	}
+}
+
+// SetStoreWarmUp sets the warm-up of the store, the zero duration means the
+// store does not warm up.
+func SetStoreWarmUp(startTime time.Time, duration time.Duration) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.warmUpStartTime = startTime
+		store.warmUpDuration = duration
+	}
+}
+
+// SetLatencySlowTimes sets the number of the consecutive heartbeats whose p99
+// latency exceeds the thresholds.
+func SetLatencySlowTimes(times int) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.latencySlowTimes = times
+	}
+}
+
+// SetLabelWarnings sets the warnings of the labels not matching the location
+// labels.
+func SetLabelWarnings(warnings []string) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.labelWarnings = warnings
+	}
+}
+
 // SetLeaderCount sets the leader count for the store.
 func SetLeaderCount(leaderCount int) StoreCreateOption {
 	return func(store *StoreInfo) {
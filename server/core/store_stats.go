@@ -137,6 +137,29 @@ func (ss *storeStats) GetReceivingSnapCount() uint32 {
 	return ss.rawStats.GetReceivingSnapCount()
 }
 
+// GetP99ReadLatencyUs returns the p99 read latency in microseconds reported
+// in the op latencies of the store.
+func (ss *storeStats) GetP99ReadLatencyUs() uint64 {
+	return ss.getOpLatency(P99ReadLatencyKey)
+}
+
+// GetP99WriteLatencyUs returns the p99 write latency in microseconds reported
+// in the op latencies of the store.
+func (ss *storeStats) GetP99WriteLatencyUs() uint64 {
+	return ss.getOpLatency(P99WriteLatencyKey)
+}
+
+func (ss *storeStats) getOpLatency(key string) uint64 {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	for _, pair := range ss.rawStats.GetOpLatencies() {
+		if pair.GetKey() == key {
+			return pair.GetValue()
+		}
+	}
+	return 0
+}
+
 // GetAvgAvailable returns available size after the spike changes has been smoothed.
 func (ss *storeStats) GetAvgAvailable() uint64 {
 	ss.mu.RLock()
@@ -116,6 +116,35 @@ func (s *testStoreSuite) TestRegionScore(c *C) {
 	c.Assert(math.IsNaN(score), IsFalse)
 }
 
+func (s *testStoreSuite) TestRegionStoreLabels(c *C) {
+	bc := NewBasicCluster()
+	bc.PutStore(NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1", "env": "prod"}))
+	bc.PutStore(NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z2", "env": "prod"}))
+	bc.PutStore(NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z2", "env": "test"}))
+	bc.PutStore(NewStoreInfoWithLabel(4, 0, map[string]string{"disk": "ssd"}))
+	// The regions split from the same region are on the stores with different labels.
+	newRegion := func(id uint64, startKey, endKey string, storeIDs ...uint64) *RegionInfo {
+		meta := &metapb.Region{Id: id, StartKey: []byte(startKey), EndKey: []byte(endKey), RegionEpoch: &metapb.RegionEpoch{}}
+		for _, storeID := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id*10 + storeID, StoreId: storeID})
+		}
+		return NewRegionInfo(meta, meta.Peers[0])
+	}
+	bc.PutRegion(newRegion(1, "", "b", 1, 2))
+	bc.PutRegion(newRegion(2, "b", "", 2, 3, 4))
+
+	c.Assert(bc.RegionStoreLabels(1), DeepEquals, map[string][]string{
+		"zone": {"z1", "z2"},
+		"env":  {"prod"},
+	})
+	c.Assert(bc.RegionStoreLabels(2), DeepEquals, map[string][]string{
+		"zone": {"z2"},
+		"env":  {"prod", "test"},
+		"disk": {"ssd"},
+	})
+	c.Assert(bc.RegionStoreLabels(3), IsNil)
+}
+
 func (s *testStoreSuite) TestLowSpaceRatio(c *C) {
 	store := NewStoreInfoWithLabel(1, 20, nil)
 	store.rawStats.Capacity = initialMinSpace << 4
@@ -171,3 +200,32 @@ func (s *testStoreSuite) TestLowSpaceScoreV2(c *C) {
 		c.Assert(score1, Greater, score2)
 	}
 }
+
+func (s *testStoreSuite) TestWarmUpRegionScore(c *C) {
+	duration := 30 * time.Minute
+	normal := NewStoreInfoWithAvailable(1, 90*gb, 100*gb, 1)
+	newStore := func(elapsed time.Duration) *StoreInfo {
+		return NewStoreInfoWithAvailable(2, 100*gb, 100*gb, 1).Clone(SetStoreWarmUp(time.Now().Add(-elapsed), duration))
+	}
+
+	for _, version := range []string{"v1", "v2"} {
+		normalScore := normal.RegionScore(version, 0.7, 0.8, 0)
+		// The new store looks full at the beginning of the warm-up, and its
+		// score drops gradually while its usable capacity ramps up.
+		lastScore := newStore(0).RegionScore(version, 0.7, 0.8, 0)
+		c.Assert(lastScore, Greater, normalScore)
+		for elapsed := 3 * time.Minute; elapsed < duration; elapsed += 3 * time.Minute {
+			store := newStore(elapsed)
+			c.Assert(store.IsWarmingUp(), IsTrue)
+			score := store.RegionScore(version, 0.7, 0.8, 0)
+			c.Assert(score, LessEqual, lastScore)
+			lastScore = score
+		}
+		c.Assert(lastScore, Less, newStore(0).RegionScore(version, 0.7, 0.8, 0))
+
+		store := newStore(duration)
+		c.Assert(store.IsWarmingUp(), IsFalse)
+		c.Assert(store.GetWarmUpRatio(), Equals, 1.0)
+		c.Assert(store.RegionScore(version, 0.7, 0.8, 0), Less, normalScore)
+	}
+}
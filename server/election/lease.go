@@ -19,6 +19,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/etcdutil"
@@ -99,7 +100,8 @@ func (l *lease) KeepAlive(ctx context.Context) {
 	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	timeCh := l.keepAliveWorker(ctx, l.leaseTimeout/3)
+	interval := l.leaseTimeout / 3
+	timeCh := l.keepAliveWorker(ctx, interval)
 
 	var maxExpire time.Time
 	for {
@@ -109,6 +111,7 @@ func (l *lease) KeepAlive(ctx context.Context) {
 				maxExpire = t
 				l.expireTime.Store(t)
 			}
+			l.checkRemaining(maxExpire, interval)
 		case <-time.After(l.leaseTimeout):
 			log.Info("lease timeout", zap.Time("expire", l.expireTime.Load().(time.Time)), zap.String("purpose", l.Purpose))
 			return
@@ -118,6 +121,22 @@ func (l *lease) KeepAlive(ctx context.Context) {
 	}
 }
 
+// checkRemaining records the remaining time of the lease and warns if it is
+// less than two renewal intervals, which means the renewal is falling behind
+// and the lease may expire before the next renewal succeeds.
+func (l *lease) checkRemaining(expire time.Time, interval time.Duration) {
+	remaining := time.Until(expire)
+	leaseRemaining.WithLabelValues(l.Purpose).Set(remaining.Seconds())
+	if remaining < 2*interval {
+		leaseNearExpiry.WithLabelValues(l.Purpose).Inc()
+		log.Warn("lease is close to expiry",
+			zap.String("purpose", l.Purpose),
+			zap.Duration("remaining", remaining),
+			zap.Duration("renewal-interval", interval),
+			zap.Time("expire", expire))
+	}
+}
+
 // Periodically call `lease.KeepAliveOnce` and post back latest received expire time into the channel.
 func (l *lease) keepAliveWorker(ctx context.Context, interval time.Duration) <-chan time.Time {
 	ch := make(chan time.Time)
@@ -134,11 +153,15 @@ func (l *lease) keepAliveWorker(ctx context.Context, interval time.Duration) <-c
 				start := time.Now()
 				ctx1, cancel := context.WithTimeout(ctx, l.leaseTimeout)
 				defer cancel()
+				failpoint.Inject("slowLeaseRenewal", func(val failpoint.Value) {
+					time.Sleep(time.Duration(val.(int)) * time.Millisecond)
+				})
 				res, err := l.lease.KeepAliveOnce(ctx1, l.ID)
 				if err != nil {
 					log.Warn("lease keep alive failed", zap.String("purpose", l.Purpose), errs.ZapError(err))
 					return
 				}
+				leaseRenewalDuration.WithLabelValues(l.Purpose).Observe(time.Since(start).Seconds())
 				if res.TTL > 0 {
 					expire := start.Add(time.Duration(res.TTL) * time.Second)
 					select {
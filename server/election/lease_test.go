@@ -15,10 +15,17 @@
 package election
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/tikv/pd/pkg/etcdutil"
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/embed"
@@ -104,3 +111,84 @@ func (s *testLeaseSuite) TestLease(c *C) {
 	time.Sleep((defaultLeaseTimeout + 1) * time.Second)
 	c.Check(lease1.IsExpired(), IsTrue)
 }
+
+// testingWriter is a WriteSyncer that records the written log messages.
+type testingWriter struct {
+	sync.Mutex
+	messages []string
+}
+
+func (w *testingWriter) Write(p []byte) (n int, err error) {
+	w.Lock()
+	defer w.Unlock()
+	w.messages = append(w.messages, string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
+
+func (w *testingWriter) Sync() error {
+	return nil
+}
+
+func (w *testingWriter) contains(substr string) bool {
+	w.Lock()
+	defer w.Unlock()
+	for _, m := range w.messages {
+		if strings.Contains(m, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *testLeaseSuite) TestLeaseNearExpiry(c *C) {
+	cfg := etcdutil.NewTestSingleConfig()
+	etcd, err := embed.StartEtcd(cfg)
+	defer func() {
+		etcd.Close()
+		etcdutil.CleanConfig(cfg)
+	}()
+	c.Assert(err, IsNil)
+
+	ep := cfg.LCUrls[0].String()
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints: []string{ep},
+	})
+	c.Assert(err, IsNil)
+
+	<-etcd.Server.ReadyNotify()
+
+	writer := &testingWriter{}
+	lg, p, err := log.InitLoggerWithWriteSyncer(&log.Config{Level: "info"}, writer)
+	c.Assert(err, IsNil)
+	restore := log.ReplaceGlobals(lg, p)
+	defer restore()
+
+	purpose := "test_lease_near_expiry"
+	l := &lease{
+		Purpose: purpose,
+		client:  client,
+		lease:   clientv3.NewLease(client),
+	}
+	defer l.Close()
+	// Use a longer lease timeout since etcd may extend a TTL which is too short.
+	leaseTimeout := int64(3)
+	c.Assert(l.Grant(leaseTimeout), IsNil)
+
+	// The renewal interval is 1/3 of the lease timeout, so a renewal slower
+	// than one interval leaves less than two intervals of the lease.
+	delay := leaseTimeout * 1000 / 2
+	c.Assert(failpoint.Enable("github.com/tikv/pd/server/election/slowLeaseRenewal", fmt.Sprintf("return(%d)", delay)), IsNil)
+	defer failpoint.Disable("github.com/tikv/pd/server/election/slowLeaseRenewal")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.KeepAlive(ctx)
+
+	for i := 0; i < 100 && testutil.ToFloat64(leaseNearExpiry.WithLabelValues(purpose)) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	c.Assert(testutil.ToFloat64(leaseNearExpiry.WithLabelValues(purpose)), Greater, float64(0))
+	c.Assert(writer.contains("lease is close to expiry"), IsTrue)
+	c.Assert(writer.contains(purpose), IsTrue)
+	c.Assert(testutil.ToFloat64(leaseRemaining.WithLabelValues(purpose)), Less, float64(leaseTimeout)*2/3)
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package election
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const purposeLabel = "purpose"
+
+var (
+	leaseRenewalDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "tso",
+			Name:      "lease_renewal_duration_seconds",
+			Help:      "Bucketed histogram of the duration of the lease renewal.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13), // 0.5ms ~ 2s
+		}, []string{purposeLabel})
+
+	leaseRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "tso",
+			Name:      "lease_remaining_seconds",
+			Help:      "The remaining time of the lease after the latest renewal.",
+		}, []string{purposeLabel})
+
+	leaseNearExpiry = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "tso",
+			Name:      "lease_near_expiry_total",
+			Help:      "Counter of the lease renewals which leave less than two renewal intervals.",
+		}, []string{purposeLabel})
+)
+
+func init() {
+	prometheus.MustRegister(leaseRenewalDuration)
+	prometheus.MustRegister(leaseRemaining)
+	prometheus.MustRegister(leaseNearExpiry)
+}
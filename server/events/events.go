@@ -0,0 +1,165 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tikv/pd/pkg/typeutil"
+)
+
+// EventType is the type of a cluster event.
+type EventType string
+
+// The types of the cluster events.
+const (
+	StoreStateChange EventType = "store-state-change"
+	OperatorFinish   EventType = "operator-finish"
+	RegionSplit      EventType = "region-split"
+	RegionMerge      EventType = "region-merge"
+	ConfigChange     EventType = "config-change"
+	// The operator step events are only published to the event hub of the
+	// operator controller.
+	OperatorStepStart  EventType = "operator-step-start"
+	OperatorStepFinish EventType = "operator-step-finish"
+)
+
+// ClusterEvent is an event happened in the cluster.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ClusterEvent struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// StoreID is set if the event is about a store.
+	StoreID uint64 `json:"store_id,omitempty"`
+	// RegionID is set if the event is about a region.
+	RegionID uint64 `json:"region_id,omitempty"`
+	Message  string `json:"message"`
+	// Step is set if the event is about an operator step.
+	Step *OperatorStep `json:"step,omitempty"`
+}
+
+// OperatorStep is the progress of an operator step.
+type OperatorStep struct {
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	// StoreID is the store which the step works on, it is 0 if the step is
+	// not about a single store, e.g. merging regions.
+	StoreID uint64 `json:"store_id,omitempty"`
+	// Elapsed is the time since the operator started.
+	Elapsed typeutil.Duration `json:"elapsed"`
+}
+
+// NewClusterEvent creates a ClusterEvent happened now.
+func NewClusterEvent(typ EventType, message string) *ClusterEvent {
+	return &ClusterEvent{Type: typ, Time: time.Now(), Message: message}
+}
+
+// WithStore sets the store of the event.
+func (e *ClusterEvent) WithStore(storeID uint64) *ClusterEvent {
+	e.StoreID = storeID
+	return e
+}
+
+// WithRegion sets the region of the event.
+func (e *ClusterEvent) WithRegion(regionID uint64) *ClusterEvent {
+	e.RegionID = regionID
+	return e
+}
+
+// WithStep sets the operator step of the event.
+func (e *ClusterEvent) WithStep(step *OperatorStep) *ClusterEvent {
+	e.Step = step
+	return e
+}
+
+// Subscriber receives the events published after it subscribes.
+type Subscriber struct {
+	ch      chan *ClusterEvent
+	dropped uint64
+	// regionID is the region whose events are received, 0 means all events.
+	regionID uint64
+}
+
+// Events returns the channel of the events.
+func (s *Subscriber) Events() <-chan *ClusterEvent {
+	return s.ch
+}
+
+// Dropped returns the number of the events dropped because the subscriber
+// did not receive them in time.
+func (s *Subscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Hub dispatches the cluster events to the subscribers. Publishing never
+// blocks, the events are dropped for the subscribers whose buffer is full, so
+// a slow subscriber cannot slow down the cluster. A nil Hub discards all
+// events.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+}
+
+// NewHub creates a Hub without any subscriber.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[*Subscriber]struct{})}
+}
+
+// Subscribe creates a subscriber which buffers at most bufferSize events.
+func (h *Hub) Subscribe(bufferSize int) *Subscriber {
+	return h.subscribe(&Subscriber{ch: make(chan *ClusterEvent, bufferSize)})
+}
+
+// SubscribeRegion creates a subscriber which only receives the events of the
+// region and buffers at most bufferSize events, so the events of the region
+// are not dropped because of the events of the other regions.
+func (h *Hub) SubscribeRegion(regionID uint64, bufferSize int) *Subscriber {
+	return h.subscribe(&Subscriber{ch: make(chan *ClusterEvent, bufferSize), regionID: regionID})
+}
+
+func (h *Hub) subscribe(s *Subscriber) *Subscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[s] = struct{}{}
+	return s
+}
+
+// Unsubscribe removes the subscriber, it does not receive events any more.
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, s)
+}
+
+// Publish sends the event to all subscribers.
+func (h *Hub) Publish(e *ClusterEvent) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subscribers {
+		if s.regionID != 0 && s.regionID != e.RegionID {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
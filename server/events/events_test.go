@@ -0,0 +1,68 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestEvents(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testEventsSuite{})
+
+type testEventsSuite struct{}
+
+func (s *testEventsSuite) TestHub(c *C) {
+	var nilHub *Hub
+	nilHub.Publish(NewClusterEvent(ConfigChange, "schedule"))
+
+	h := NewHub()
+	fast, slow := h.Subscribe(10), h.Subscribe(2)
+	for i := uint64(1); i <= 5; i++ {
+		h.Publish(NewClusterEvent(RegionSplit, "split").WithRegion(i))
+	}
+	for i := uint64(1); i <= 5; i++ {
+		c.Assert((<-fast.Events()).RegionID, Equals, i)
+	}
+	c.Assert(fast.Dropped(), Equals, uint64(0))
+	// The events are dropped for the slow subscriber once its buffer is full.
+	c.Assert((<-slow.Events()).RegionID, Equals, uint64(1))
+	c.Assert((<-slow.Events()).RegionID, Equals, uint64(2))
+	c.Assert(slow.Dropped(), Equals, uint64(3))
+
+	h.Unsubscribe(slow)
+	h.Publish(NewClusterEvent(StoreStateChange, "Serving -> Removing").WithStore(1))
+	e := <-fast.Events()
+	c.Assert(e.Type, Equals, StoreStateChange)
+	c.Assert(e.StoreID, Equals, uint64(1))
+	c.Assert(slow.Events(), HasLen, 0)
+}
+
+func (s *testEventsSuite) TestSubscribeRegion(c *C) {
+	h := NewHub()
+	sub := h.SubscribeRegion(3, 1)
+	for i := uint64(1); i <= 5; i++ {
+		h.Publish(NewClusterEvent(OperatorFinish, "finish").WithRegion(i))
+	}
+	h.Publish(NewClusterEvent(StoreStateChange, "Serving -> Removing").WithStore(1))
+	// The events of the other regions do not fill the buffer.
+	c.Assert((<-sub.Events()).RegionID, Equals, uint64(3))
+	c.Assert(sub.Events(), HasLen, 0)
+	c.Assert(sub.Dropped(), Equals, uint64(0))
+}
@@ -0,0 +1,295 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genealogy
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// EntryType is the type of a genealogy entry.
+type EntryType string
+
+const (
+	// Split means a region is split into several regions.
+	Split EntryType = "split"
+	// Merge means some regions are merged into a region.
+	Merge EntryType = "merge"
+)
+
+// Entry is a split or a merge in the region genealogy.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type Entry struct {
+	Seq  uint64    `json:"seq"`
+	Type EntryType `json:"type"`
+	// ParentID is the region which is split, it keeps one of the ranges.
+	ParentID uint64 `json:"parent-id,omitempty"`
+	// ChildIDs are the new regions split from the parent.
+	ChildIDs []uint64 `json:"child-ids,omitempty"`
+	// SplitKeys are the hex encoded keys where the parent is split.
+	SplitKeys []string `json:"split-keys,omitempty"`
+	// SourceIDs are the regions merged into the result.
+	SourceIDs []uint64 `json:"source-ids,omitempty"`
+	// ResultID is the region which the sources are merged into.
+	ResultID  uint64    `json:"result-id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ancestors returns the regions which the given region is derived from by the entry.
+func (e *Entry) ancestors(regionID uint64) []uint64 {
+	switch e.Type {
+	case Split:
+		if containsID(e.ChildIDs, regionID) {
+			return []uint64{e.ParentID}
+		}
+	case Merge:
+		if e.ResultID == regionID {
+			return e.SourceIDs
+		}
+	}
+	return nil
+}
+
+// descendants returns the regions which are derived from the given region by the entry.
+func (e *Entry) descendants(regionID uint64) []uint64 {
+	switch e.Type {
+	case Split:
+		if e.ParentID == regionID {
+			return e.ChildIDs
+		}
+	case Merge:
+		if containsID(e.SourceIDs, regionID) {
+			return []uint64{e.ResultID}
+		}
+	}
+	return nil
+}
+
+func containsID(ids []uint64, id uint64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain is the ancestors and the descendants of a region.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type Chain struct {
+	RegionID uint64 `json:"region-id"`
+	// Ancestors are the entries which the region is derived from, in the
+	// order of the sequence.
+	Ancestors []*Entry `json:"ancestors"`
+	// Descendants are the entries derived from the region, in the order of
+	// the sequence.
+	Descendants []*Entry `json:"descendants"`
+}
+
+// Genealogy records the splits and the merges of the regions, only the most
+// recent entries are kept.
+type Genealogy struct {
+	storage    endpoint.GenealogyStorage
+	maxEntries int
+
+	sync.RWMutex
+	nextSeq uint64
+	// entries are sorted by the sequence.
+	entries []*Entry
+	// regions indexes the entries by the regions involved.
+	regions map[uint64][]*Entry
+}
+
+// NewGenealogy creates a Genealogy which keeps at most maxEntries entries.
+func NewGenealogy(storage endpoint.GenealogyStorage, maxEntries int) (*Genealogy, error) {
+	g := &Genealogy{
+		storage:    storage,
+		maxEntries: maxEntries,
+		nextSeq:    1,
+		regions:    make(map[uint64][]*Entry),
+	}
+	if err := g.load(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *Genealogy) load() error {
+	err := g.storage.LoadGenealogy(func(k, v string) {
+		var e Entry
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			log.Error("failed to unmarshal region genealogy entry", zap.String("key", k), errs.ZapError(errs.ErrJSONUnmarshal, err))
+			return
+		}
+		g.addLocked(&e)
+	})
+	if err != nil {
+		return err
+	}
+	return g.trimLocked()
+}
+
+// RecordSplit records that the parent is split into the children.
+func (g *Genealogy) RecordSplit(parentID uint64, childIDs []uint64, splitKeys [][]byte) {
+	keys := make([]string, 0, len(splitKeys))
+	for _, key := range splitKeys {
+		keys = append(keys, core.HexRegionKeyStr(key))
+	}
+	g.record(&Entry{
+		Type:      Split,
+		ParentID:  parentID,
+		ChildIDs:  childIDs,
+		SplitKeys: keys,
+	})
+}
+
+// RecordMerge records that the sources are merged into the result.
+func (g *Genealogy) RecordMerge(sourceIDs []uint64, resultID uint64) {
+	g.record(&Entry{
+		Type:      Merge,
+		SourceIDs: sourceIDs,
+		ResultID:  resultID,
+	})
+}
+
+func (g *Genealogy) record(e *Entry) {
+	if g == nil {
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	e.Seq = g.nextSeq
+	e.Timestamp = time.Now()
+	if err := g.storage.SaveGenealogy(e.Seq, e); err != nil {
+		log.Error("failed to save region genealogy entry", zap.Uint64("seq", e.Seq), errs.ZapError(err))
+		return
+	}
+	g.addLocked(e)
+	if err := g.trimLocked(); err != nil {
+		log.Error("failed to delete region genealogy entry", errs.ZapError(err))
+	}
+}
+
+func (g *Genealogy) addLocked(e *Entry) {
+	g.entries = append(g.entries, e)
+	if e.Seq >= g.nextSeq {
+		g.nextSeq = e.Seq + 1
+	}
+	for _, id := range g.involved(e) {
+		g.regions[id] = append(g.regions[id], e)
+	}
+}
+
+// trimLocked removes the oldest entries beyond the limit.
+func (g *Genealogy) trimLocked() error {
+	for g.maxEntries > 0 && len(g.entries) > g.maxEntries {
+		e := g.entries[0]
+		if err := g.storage.DeleteGenealogy(e.Seq); err != nil {
+			return err
+		}
+		g.entries[0] = nil
+		g.entries = g.entries[1:]
+		for _, id := range g.involved(e) {
+			// The oldest entry is always the first one of the region.
+			if entries := g.regions[id][1:]; len(entries) > 0 {
+				g.regions[id] = entries
+			} else {
+				delete(g.regions, id)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Genealogy) involved(e *Entry) []uint64 {
+	if e.Type == Split {
+		return append([]uint64{e.ParentID}, e.ChildIDs...)
+	}
+	return append([]uint64{e.ResultID}, e.SourceIDs...)
+}
+
+// GetChain returns the ancestors and the descendants of the region. An
+// ancestor is an entry before the one deriving the region, and a descendant
+// is an entry after the one derived from the region.
+func (g *Genealogy) GetChain(regionID uint64) *Chain {
+	chain := &Chain{RegionID: regionID, Ancestors: []*Entry{}, Descendants: []*Entry{}}
+	if g == nil {
+		return chain
+	}
+	g.RLock()
+	defer g.RUnlock()
+
+	visited := make(map[uint64]struct{})
+	var walkUp func(id, before uint64)
+	walkUp = func(id, before uint64) {
+		for _, e := range g.regions[id] {
+			if e.Seq >= before {
+				break
+			}
+			ancestors := e.ancestors(id)
+			if len(ancestors) == 0 {
+				continue
+			}
+			if _, ok := visited[e.Seq]; ok {
+				continue
+			}
+			visited[e.Seq] = struct{}{}
+			chain.Ancestors = append(chain.Ancestors, e)
+			for _, ancestor := range ancestors {
+				walkUp(ancestor, e.Seq)
+			}
+		}
+	}
+	walkUp(regionID, g.nextSeq)
+
+	visited = make(map[uint64]struct{})
+	var walkDown func(id, after uint64)
+	walkDown = func(id, after uint64) {
+		for _, e := range g.regions[id] {
+			if e.Seq <= after {
+				continue
+			}
+			descendants := e.descendants(id)
+			if len(descendants) == 0 {
+				continue
+			}
+			if _, ok := visited[e.Seq]; ok {
+				continue
+			}
+			visited[e.Seq] = struct{}{}
+			chain.Descendants = append(chain.Descendants, e)
+			for _, descendant := range descendants {
+				walkDown(descendant, e.Seq)
+			}
+		}
+	}
+	walkDown(regionID, 0)
+
+	sortEntries(chain.Ancestors)
+	sortEntries(chain.Descendants)
+	return chain
+}
+
+func sortEntries(entries []*Entry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Seq < entries[j].Seq })
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genealogy
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/storage"
+)
+
+func TestGenealogy(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testGenealogySuite{})
+
+type testGenealogySuite struct{}
+
+func entrySeqs(entries []*Entry) []uint64 {
+	seqs := make([]uint64, 0, len(entries))
+	for _, e := range entries {
+		seqs = append(seqs, e.Seq)
+	}
+	return seqs
+}
+
+func (s *testGenealogySuite) TestChain(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	g, err := NewGenealogy(store, 100)
+	c.Assert(err, IsNil)
+
+	// 1 -> 1, 2 -> 1, 2, 3, then 2 and 3 are merged into 4, which is split again.
+	g.RecordSplit(1, []uint64{2}, [][]byte{[]byte("b")})
+	g.RecordSplit(2, []uint64{3}, [][]byte{[]byte("c")})
+	g.RecordMerge([]uint64{2, 3}, 4)
+	g.RecordSplit(4, []uint64{5}, [][]byte{[]byte("d")})
+
+	chain := g.GetChain(1)
+	c.Assert(chain.Ancestors, HasLen, 0)
+	c.Assert(entrySeqs(chain.Descendants), DeepEquals, []uint64{1, 2, 3, 4})
+
+	chain = g.GetChain(3)
+	c.Assert(entrySeqs(chain.Ancestors), DeepEquals, []uint64{1, 2})
+	c.Assert(entrySeqs(chain.Descendants), DeepEquals, []uint64{3, 4})
+	c.Assert(chain.Ancestors[1].ParentID, Equals, uint64(2))
+	c.Assert(chain.Ancestors[1].SplitKeys, DeepEquals, []string{"63"})
+
+	chain = g.GetChain(4)
+	c.Assert(entrySeqs(chain.Ancestors), DeepEquals, []uint64{1, 2, 3})
+	c.Assert(chain.Ancestors[2].SourceIDs, DeepEquals, []uint64{2, 3})
+	c.Assert(entrySeqs(chain.Descendants), DeepEquals, []uint64{4})
+
+	// The entries before a region is derived are not its descendants.
+	chain = g.GetChain(5)
+	c.Assert(entrySeqs(chain.Ancestors), DeepEquals, []uint64{1, 2, 3, 4})
+	c.Assert(chain.Descendants, HasLen, 0)
+
+	chain = g.GetChain(6)
+	c.Assert(chain.Ancestors, HasLen, 0)
+	c.Assert(chain.Descendants, HasLen, 0)
+
+	// The entries are loaded from the storage.
+	g, err = NewGenealogy(store, 100)
+	c.Assert(err, IsNil)
+	c.Assert(entrySeqs(g.GetChain(5).Ancestors), DeepEquals, []uint64{1, 2, 3, 4})
+	g.RecordMerge([]uint64{5}, 1)
+	c.Assert(entrySeqs(g.GetChain(1).Ancestors), DeepEquals, []uint64{1, 2, 3, 4, 5})
+}
+
+func (s *testGenealogySuite) TestMaxEntries(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	g, err := NewGenealogy(store, 2)
+	c.Assert(err, IsNil)
+	g.RecordSplit(1, []uint64{2}, [][]byte{[]byte("b")})
+	g.RecordSplit(2, []uint64{3}, [][]byte{[]byte("c")})
+	g.RecordSplit(3, []uint64{4}, [][]byte{[]byte("d")})
+	c.Assert(entrySeqs(g.GetChain(4).Ancestors), DeepEquals, []uint64{2, 3})
+	c.Assert(g.GetChain(1).Descendants, HasLen, 0)
+
+	// The oldest entries are removed from the storage too.
+	g, err = NewGenealogy(store, 1)
+	c.Assert(err, IsNil)
+	c.Assert(entrySeqs(g.GetChain(4).Ancestors), DeepEquals, []uint64{3})
+	g, err = NewGenealogy(store, 100)
+	c.Assert(err, IsNil)
+	c.Assert(entrySeqs(g.GetChain(4).Ancestors), DeepEquals, []uint64{3})
+
+	// The nil genealogy records nothing.
+	g = nil
+	g.RecordMerge([]uint64{1}, 2)
+	c.Assert(g.GetChain(2).Ancestors, HasLen, 0)
+}
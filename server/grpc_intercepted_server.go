@@ -0,0 +1,493 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"google.golang.org/grpc"
+)
+
+// pdServiceName is the full name of the PD service.
+const pdServiceName = "pdpb.PD"
+
+// interceptedPDServer implements the PD service by calling the interceptors
+// before the methods of the GrpcServer. It holds the GrpcServer in a named
+// field rather than embedding it, so that a method added to the PD service
+// fails to compile until it is intercepted as well.
+type interceptedPDServer struct {
+	srv    *GrpcServer
+	unaryI grpc.UnaryServerInterceptor
+	// streamI is called with the grpc.ServerStream of the RPC, the typed
+	// stream of the method is built from the stream it passes on.
+	streamI grpc.StreamServerInterceptor
+}
+
+var _ pdpb.PDServer = (*interceptedPDServer)(nil)
+
+func (s *interceptedPDServer) unary(ctx context.Context, request interface{}, method string, handler grpc.UnaryHandler) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{
+		Server:     s.srv,
+		FullMethod: "/" + pdServiceName + "/" + method,
+	}
+	return s.unaryI(ctx, request, info, handler)
+}
+
+func (s *interceptedPDServer) stream(stream grpc.ServerStream, method string, clientStream, serverStream bool, handler grpc.StreamHandler) error {
+	info := &grpc.StreamServerInfo{
+		FullMethod:     "/" + pdServiceName + "/" + method,
+		IsClientStream: clientStream,
+		IsServerStream: serverStream,
+	}
+	return s.streamI(s.srv, stream, info, handler)
+}
+
+// Tso implements gRPC PDServer.
+func (s *interceptedPDServer) Tso(stream pdpb.PD_TsoServer) error {
+	return s.stream(stream, "Tso", true, true, func(_ interface{}, stream grpc.ServerStream) error {
+		return s.srv.Tso(&tsoServer{stream})
+	})
+}
+
+// RegionHeartbeat implements gRPC PDServer.
+func (s *interceptedPDServer) RegionHeartbeat(stream pdpb.PD_RegionHeartbeatServer) error {
+	return s.stream(stream, "RegionHeartbeat", true, true, func(_ interface{}, stream grpc.ServerStream) error {
+		return s.srv.RegionHeartbeat(&regionHeartbeatServer{stream})
+	})
+}
+
+// SyncRegions implements gRPC PDServer.
+func (s *interceptedPDServer) SyncRegions(stream pdpb.PD_SyncRegionsServer) error {
+	return s.stream(stream, "SyncRegions", true, true, func(_ interface{}, stream grpc.ServerStream) error {
+		return s.srv.SyncRegions(&syncRegionsServer{stream})
+	})
+}
+
+// WatchGlobalConfig implements gRPC PDServer.
+func (s *interceptedPDServer) WatchGlobalConfig(request *pdpb.WatchGlobalConfigRequest, stream pdpb.PD_WatchGlobalConfigServer) error {
+	return s.stream(stream, "WatchGlobalConfig", false, true, func(_ interface{}, stream grpc.ServerStream) error {
+		return s.srv.WatchGlobalConfig(request, &watchGlobalConfigServer{stream})
+	})
+}
+
+// ReportBuckets implements gRPC PDServer.
+func (s *interceptedPDServer) ReportBuckets(stream pdpb.PD_ReportBucketsServer) error {
+	return s.stream(stream, "ReportBuckets", true, false, func(_ interface{}, stream grpc.ServerStream) error {
+		return s.srv.ReportBuckets(&reportBucketsServer{stream})
+	})
+}
+
+// GetMembers implements gRPC PDServer.
+func (s *interceptedPDServer) GetMembers(ctx context.Context, request *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
+	resp, err := s.unary(ctx, request, "GetMembers", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetMembers(ctx, request.(*pdpb.GetMembersRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetMembersResponse), nil
+}
+
+// Bootstrap implements gRPC PDServer.
+func (s *interceptedPDServer) Bootstrap(ctx context.Context, request *pdpb.BootstrapRequest) (*pdpb.BootstrapResponse, error) {
+	resp, err := s.unary(ctx, request, "Bootstrap", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.Bootstrap(ctx, request.(*pdpb.BootstrapRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.BootstrapResponse), nil
+}
+
+// IsBootstrapped implements gRPC PDServer.
+func (s *interceptedPDServer) IsBootstrapped(ctx context.Context, request *pdpb.IsBootstrappedRequest) (*pdpb.IsBootstrappedResponse, error) {
+	resp, err := s.unary(ctx, request, "IsBootstrapped", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.IsBootstrapped(ctx, request.(*pdpb.IsBootstrappedRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.IsBootstrappedResponse), nil
+}
+
+// AllocID implements gRPC PDServer.
+func (s *interceptedPDServer) AllocID(ctx context.Context, request *pdpb.AllocIDRequest) (*pdpb.AllocIDResponse, error) {
+	resp, err := s.unary(ctx, request, "AllocID", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.AllocID(ctx, request.(*pdpb.AllocIDRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.AllocIDResponse), nil
+}
+
+// GetStore implements gRPC PDServer.
+func (s *interceptedPDServer) GetStore(ctx context.Context, request *pdpb.GetStoreRequest) (*pdpb.GetStoreResponse, error) {
+	resp, err := s.unary(ctx, request, "GetStore", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetStore(ctx, request.(*pdpb.GetStoreRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetStoreResponse), nil
+}
+
+// PutStore implements gRPC PDServer.
+func (s *interceptedPDServer) PutStore(ctx context.Context, request *pdpb.PutStoreRequest) (*pdpb.PutStoreResponse, error) {
+	resp, err := s.unary(ctx, request, "PutStore", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.PutStore(ctx, request.(*pdpb.PutStoreRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.PutStoreResponse), nil
+}
+
+// GetAllStores implements gRPC PDServer.
+func (s *interceptedPDServer) GetAllStores(ctx context.Context, request *pdpb.GetAllStoresRequest) (*pdpb.GetAllStoresResponse, error) {
+	resp, err := s.unary(ctx, request, "GetAllStores", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetAllStores(ctx, request.(*pdpb.GetAllStoresRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetAllStoresResponse), nil
+}
+
+// StoreHeartbeat implements gRPC PDServer.
+func (s *interceptedPDServer) StoreHeartbeat(ctx context.Context, request *pdpb.StoreHeartbeatRequest) (*pdpb.StoreHeartbeatResponse, error) {
+	resp, err := s.unary(ctx, request, "StoreHeartbeat", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.StoreHeartbeat(ctx, request.(*pdpb.StoreHeartbeatRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.StoreHeartbeatResponse), nil
+}
+
+// GetRegion implements gRPC PDServer.
+func (s *interceptedPDServer) GetRegion(ctx context.Context, request *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	resp, err := s.unary(ctx, request, "GetRegion", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetRegion(ctx, request.(*pdpb.GetRegionRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetRegionResponse), nil
+}
+
+// GetPrevRegion implements gRPC PDServer.
+func (s *interceptedPDServer) GetPrevRegion(ctx context.Context, request *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	resp, err := s.unary(ctx, request, "GetPrevRegion", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetPrevRegion(ctx, request.(*pdpb.GetRegionRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetRegionResponse), nil
+}
+
+// GetRegionByID implements gRPC PDServer.
+func (s *interceptedPDServer) GetRegionByID(ctx context.Context, request *pdpb.GetRegionByIDRequest) (*pdpb.GetRegionResponse, error) {
+	resp, err := s.unary(ctx, request, "GetRegionByID", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetRegionByID(ctx, request.(*pdpb.GetRegionByIDRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetRegionResponse), nil
+}
+
+// ScanRegions implements gRPC PDServer.
+func (s *interceptedPDServer) ScanRegions(ctx context.Context, request *pdpb.ScanRegionsRequest) (*pdpb.ScanRegionsResponse, error) {
+	resp, err := s.unary(ctx, request, "ScanRegions", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.ScanRegions(ctx, request.(*pdpb.ScanRegionsRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.ScanRegionsResponse), nil
+}
+
+// AskSplit implements gRPC PDServer.
+func (s *interceptedPDServer) AskSplit(ctx context.Context, request *pdpb.AskSplitRequest) (*pdpb.AskSplitResponse, error) {
+	resp, err := s.unary(ctx, request, "AskSplit", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.AskSplit(ctx, request.(*pdpb.AskSplitRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.AskSplitResponse), nil
+}
+
+// ReportSplit implements gRPC PDServer.
+func (s *interceptedPDServer) ReportSplit(ctx context.Context, request *pdpb.ReportSplitRequest) (*pdpb.ReportSplitResponse, error) {
+	resp, err := s.unary(ctx, request, "ReportSplit", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.ReportSplit(ctx, request.(*pdpb.ReportSplitRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.ReportSplitResponse), nil
+}
+
+// AskBatchSplit implements gRPC PDServer.
+func (s *interceptedPDServer) AskBatchSplit(ctx context.Context, request *pdpb.AskBatchSplitRequest) (*pdpb.AskBatchSplitResponse, error) {
+	resp, err := s.unary(ctx, request, "AskBatchSplit", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.AskBatchSplit(ctx, request.(*pdpb.AskBatchSplitRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.AskBatchSplitResponse), nil
+}
+
+// ReportBatchSplit implements gRPC PDServer.
+func (s *interceptedPDServer) ReportBatchSplit(ctx context.Context, request *pdpb.ReportBatchSplitRequest) (*pdpb.ReportBatchSplitResponse, error) {
+	resp, err := s.unary(ctx, request, "ReportBatchSplit", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.ReportBatchSplit(ctx, request.(*pdpb.ReportBatchSplitRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.ReportBatchSplitResponse), nil
+}
+
+// GetClusterConfig implements gRPC PDServer.
+func (s *interceptedPDServer) GetClusterConfig(ctx context.Context, request *pdpb.GetClusterConfigRequest) (*pdpb.GetClusterConfigResponse, error) {
+	resp, err := s.unary(ctx, request, "GetClusterConfig", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetClusterConfig(ctx, request.(*pdpb.GetClusterConfigRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetClusterConfigResponse), nil
+}
+
+// PutClusterConfig implements gRPC PDServer.
+func (s *interceptedPDServer) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterConfigRequest) (*pdpb.PutClusterConfigResponse, error) {
+	resp, err := s.unary(ctx, request, "PutClusterConfig", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.PutClusterConfig(ctx, request.(*pdpb.PutClusterConfigRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.PutClusterConfigResponse), nil
+}
+
+// ScatterRegion implements gRPC PDServer.
+func (s *interceptedPDServer) ScatterRegion(ctx context.Context, request *pdpb.ScatterRegionRequest) (*pdpb.ScatterRegionResponse, error) {
+	resp, err := s.unary(ctx, request, "ScatterRegion", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.ScatterRegion(ctx, request.(*pdpb.ScatterRegionRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.ScatterRegionResponse), nil
+}
+
+// GetGCSafePoint implements gRPC PDServer.
+func (s *interceptedPDServer) GetGCSafePoint(ctx context.Context, request *pdpb.GetGCSafePointRequest) (*pdpb.GetGCSafePointResponse, error) {
+	resp, err := s.unary(ctx, request, "GetGCSafePoint", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetGCSafePoint(ctx, request.(*pdpb.GetGCSafePointRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetGCSafePointResponse), nil
+}
+
+// UpdateGCSafePoint implements gRPC PDServer.
+func (s *interceptedPDServer) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSafePointRequest) (*pdpb.UpdateGCSafePointResponse, error) {
+	resp, err := s.unary(ctx, request, "UpdateGCSafePoint", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.UpdateGCSafePoint(ctx, request.(*pdpb.UpdateGCSafePointRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.UpdateGCSafePointResponse), nil
+}
+
+// UpdateServiceGCSafePoint implements gRPC PDServer.
+func (s *interceptedPDServer) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb.UpdateServiceGCSafePointRequest) (*pdpb.UpdateServiceGCSafePointResponse, error) {
+	resp, err := s.unary(ctx, request, "UpdateServiceGCSafePoint", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.UpdateServiceGCSafePoint(ctx, request.(*pdpb.UpdateServiceGCSafePointRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.UpdateServiceGCSafePointResponse), nil
+}
+
+// GetOperator implements gRPC PDServer.
+func (s *interceptedPDServer) GetOperator(ctx context.Context, request *pdpb.GetOperatorRequest) (*pdpb.GetOperatorResponse, error) {
+	resp, err := s.unary(ctx, request, "GetOperator", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetOperator(ctx, request.(*pdpb.GetOperatorRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetOperatorResponse), nil
+}
+
+// SyncMaxTS implements gRPC PDServer.
+func (s *interceptedPDServer) SyncMaxTS(ctx context.Context, request *pdpb.SyncMaxTSRequest) (*pdpb.SyncMaxTSResponse, error) {
+	resp, err := s.unary(ctx, request, "SyncMaxTS", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.SyncMaxTS(ctx, request.(*pdpb.SyncMaxTSRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.SyncMaxTSResponse), nil
+}
+
+// SplitRegions implements gRPC PDServer.
+func (s *interceptedPDServer) SplitRegions(ctx context.Context, request *pdpb.SplitRegionsRequest) (*pdpb.SplitRegionsResponse, error) {
+	resp, err := s.unary(ctx, request, "SplitRegions", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.SplitRegions(ctx, request.(*pdpb.SplitRegionsRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.SplitRegionsResponse), nil
+}
+
+// SplitAndScatterRegions implements gRPC PDServer.
+func (s *interceptedPDServer) SplitAndScatterRegions(ctx context.Context, request *pdpb.SplitAndScatterRegionsRequest) (*pdpb.SplitAndScatterRegionsResponse, error) {
+	resp, err := s.unary(ctx, request, "SplitAndScatterRegions", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.SplitAndScatterRegions(ctx, request.(*pdpb.SplitAndScatterRegionsRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.SplitAndScatterRegionsResponse), nil
+}
+
+// GetDCLocationInfo implements gRPC PDServer.
+func (s *interceptedPDServer) GetDCLocationInfo(ctx context.Context, request *pdpb.GetDCLocationInfoRequest) (*pdpb.GetDCLocationInfoResponse, error) {
+	resp, err := s.unary(ctx, request, "GetDCLocationInfo", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.GetDCLocationInfo(ctx, request.(*pdpb.GetDCLocationInfoRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.GetDCLocationInfoResponse), nil
+}
+
+// StoreGlobalConfig implements gRPC PDServer.
+func (s *interceptedPDServer) StoreGlobalConfig(ctx context.Context, request *pdpb.StoreGlobalConfigRequest) (*pdpb.StoreGlobalConfigResponse, error) {
+	resp, err := s.unary(ctx, request, "StoreGlobalConfig", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.StoreGlobalConfig(ctx, request.(*pdpb.StoreGlobalConfigRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.StoreGlobalConfigResponse), nil
+}
+
+// LoadGlobalConfig implements gRPC PDServer.
+func (s *interceptedPDServer) LoadGlobalConfig(ctx context.Context, request *pdpb.LoadGlobalConfigRequest) (*pdpb.LoadGlobalConfigResponse, error) {
+	resp, err := s.unary(ctx, request, "LoadGlobalConfig", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.LoadGlobalConfig(ctx, request.(*pdpb.LoadGlobalConfigRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.LoadGlobalConfigResponse), nil
+}
+
+// ReportMinResolvedTS implements gRPC PDServer.
+func (s *interceptedPDServer) ReportMinResolvedTS(ctx context.Context, request *pdpb.ReportMinResolvedTsRequest) (*pdpb.ReportMinResolvedTsResponse, error) {
+	resp, err := s.unary(ctx, request, "ReportMinResolvedTS", func(ctx context.Context, request interface{}) (interface{}, error) {
+		return s.srv.ReportMinResolvedTS(ctx, request.(*pdpb.ReportMinResolvedTsRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pdpb.ReportMinResolvedTsResponse), nil
+}
+
+type tsoServer struct {
+	grpc.ServerStream
+}
+
+func (s *tsoServer) Send(m *pdpb.TsoResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *tsoServer) Recv() (*pdpb.TsoRequest, error) {
+	m := new(pdpb.TsoRequest)
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type regionHeartbeatServer struct {
+	grpc.ServerStream
+}
+
+func (s *regionHeartbeatServer) Send(m *pdpb.RegionHeartbeatResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *regionHeartbeatServer) Recv() (*pdpb.RegionHeartbeatRequest, error) {
+	m := new(pdpb.RegionHeartbeatRequest)
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type syncRegionsServer struct {
+	grpc.ServerStream
+}
+
+func (s *syncRegionsServer) Send(m *pdpb.SyncRegionResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *syncRegionsServer) Recv() (*pdpb.SyncRegionRequest, error) {
+	m := new(pdpb.SyncRegionRequest)
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type watchGlobalConfigServer struct {
+	grpc.ServerStream
+}
+
+func (s *watchGlobalConfigServer) Send(m *pdpb.WatchGlobalConfigResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+type reportBucketsServer struct {
+	grpc.ServerStream
+}
+
+func (s *reportBucketsServer) SendAndClose(m *pdpb.ReportBucketsResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *reportBucketsServer) Recv() (*pdpb.ReportBucketsRequest, error) {
+	m := new(pdpb.ReportBucketsRequest)
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
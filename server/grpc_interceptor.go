@@ -0,0 +1,129 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// registerPDServer registers the PD service to the gRPC server with the
+// interceptors of PD. The embedded etcd owns the gRPC server and accepts no
+// server option, so the interceptors are called by the registered service,
+// after the ones of the gRPC server.
+func registerPDServer(gs *grpc.Server, srv *GrpcServer) {
+	pdpb.RegisterPDServer(gs, &interceptedPDServer{
+		srv:     srv,
+		unaryI:  chainUnaryInterceptors(srv.peerUnaryInterceptor, srv.deadlineUnaryInterceptor),
+		streamI: chainStreamInterceptors(srv.peerStreamInterceptor),
+	})
+}
+
+// chainUnaryInterceptors returns an interceptor which calls the interceptors
+// in order, the last one calls the handler.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors returns an interceptor which calls the interceptors
+// in order, the last one calls the handler.
+func chainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return chained(srv, stream)
+	}
+}
+
+// peerUnaryInterceptor rejects the unary RPCs of the peers which are not
+// allowed to access the gRPC API.
+func (s *GrpcServer) peerUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkPeer(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// deadlineUnaryInterceptor bounds the deadlines of the unary RPCs by the
+// rpc-deadlines config.
+func (s *GrpcServer) deadlineUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, cancel := s.withDeadline(ctx, path.Base(info.FullMethod))
+	defer cancel()
+	return handler(ctx, req)
+}
+
+// peerStreamInterceptor rejects the streaming RPCs of the peers which are not
+// allowed to access the gRPC API.
+func (s *GrpcServer) peerStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkPeer(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// checkPeer checks whether the peer is allowed to access the gRPC API.
+func (s *GrpcServer) checkPeer(ctx context.Context) error {
+	if s.ipFilter == nil {
+		return nil
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil && s.ipFilter.AllowsAddr(p.Addr.String()) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "access denied")
+}
+
+// withDeadline bounds the deadline of the RPC by the max deadline of the method
+// in the rpc-deadlines config, which is used if the client provides no
+// deadline.
+func (s *GrpcServer) withDeadline(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	maxTimeout, ok := s.cfg.RPCDeadlines[method]
+	if !ok || maxTimeout.Duration <= 0 {
+		return ctx, func() {}
+	}
+	deadline := time.Now().Add(maxTimeout.Duration)
+	if clientDeadline, ok := ctx.Deadline(); ok {
+		if !clientDeadline.After(deadline) {
+			return ctx, func() {}
+		}
+		log.Warn("truncate the deadline of the RPC",
+			zap.String("method", method),
+			zap.Duration("client-timeout", time.Until(clientDeadline)),
+			zap.Duration("max-timeout", maxTimeout.Duration))
+	}
+	return context.WithDeadline(ctx, deadline)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// registerGRPCReflection registers the gRPC reflection service which lists
+// the services registered on the gRPC server before.
+func registerGRPCReflection(gs *grpc.Server) {
+	for _, info := range gs.GetServiceInfo() {
+		if file, ok := info.Metadata.(string); ok {
+			registerGogoFileDescriptor(file)
+		}
+	}
+	reflection.Register(gs)
+}
+
+// registerGogoFileDescriptor copies the file descriptor and its dependencies
+// from the registry of gogoproto, which the protos of kvproto are generated
+// by, to the registry of golang/protobuf, which the reflection service looks
+// up.
+func registerGogoFileDescriptor(file string) {
+	if proto.FileDescriptor(file) != nil {
+		return
+	}
+	gz := gogoproto.FileDescriptor(file)
+	if gz == nil {
+		return
+	}
+	proto.RegisterFile(file, gz)
+	fd, err := decodeFileDescriptor(gz)
+	if err != nil {
+		return
+	}
+	for _, dep := range fd.GetDependency() {
+		registerGogoFileDescriptor(dep)
+	}
+}
+
+func decodeFileDescriptor(gz []byte) (*descriptor.FileDescriptorProto, error) {
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	fd := &descriptor.FileDescriptorProto{}
+	if err := proto.Unmarshal(b, fd); err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -28,22 +29,25 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/grpcutil"
 	"github.com/tikv/pd/pkg/logutil"
+	"github.com/tikv/pd/pkg/ratelimit"
 	"github.com/tikv/pd/pkg/tsoutil"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedulers"
 	"github.com/tikv/pd/server/storage/endpoint"
 	"github.com/tikv/pd/server/storage/kv"
-	"github.com/tikv/pd/server/tso"
 	"github.com/tikv/pd/server/versioninfo"
 	"go.etcd.io/etcd/clientv3"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -61,7 +65,7 @@ var (
 )
 
 // GetMembers implements gRPC PDServer.
-func (s *GrpcServer) GetMembers(context.Context, *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
+func (s *GrpcServer) GetMembers(ctx context.Context, _ *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
 	// Here we purposely do not check the cluster ID because the client does not know the correct cluster ID
 	// at startup and needs to get the cluster ID with the first request (i.e. GetMembers).
 	members, err := s.Server.GetMembers()
@@ -155,7 +159,11 @@ func (s *GrpcServer) Tso(stream pdpb.PD_TsoServer) error {
 		if request.GetHeader().GetClusterId() != s.clusterID {
 			return status.Errorf(codes.FailedPrecondition, "mismatch cluster id, need %d but got %d", s.clusterID, request.GetHeader().GetClusterId())
 		}
+		if client := getClientIdentity(streamCtx); !s.tsoClientLimiter.allow(client) {
+			return status.Errorf(codes.ResourceExhausted, "tso requests of client %s exceed the rate limit", client)
+		}
 		count := request.GetCount()
+		// The response carries the largest timestamp of the batch.
 		ts, err := s.tsoAllocatorManager.HandleTSORequest(request.GetDcLocation(), count)
 		if err != nil {
 			return status.Errorf(codes.Unknown, err.Error())
@@ -166,6 +174,9 @@ func (s *GrpcServer) Tso(stream pdpb.PD_TsoServer) error {
 			Timestamp: &ts,
 			Count:     count,
 		}
+		if s.cfg.TSOHLCMode && !s.cfg.TSOHLCCompatible {
+			response.Timestamp = tsoutil.EncodeHLCTimestamp(&ts)
+		}
 		if err := stream.Send(response); err != nil {
 			return errors.WithStack(err)
 		}
@@ -1350,11 +1361,11 @@ func (s *GrpcServer) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb
 		}
 	}
 
-	nowTSO, err := s.tsoAllocatorManager.HandleTSORequest(tso.GlobalDCLocation, 1)
+	physical, _, err := s.tsoAllocatorManager.BatchAllocTimestamps(ctx, 1)
 	if err != nil {
 		return nil, err
 	}
-	now, _ := tsoutil.ParseTimestamp(nowTSO)
+	now := time.Unix(0, physical*int64(time.Millisecond))
 	min, err := storage.LoadMinServiceGCSafePoint(now)
 	if err != nil {
 		return nil, err
@@ -1436,6 +1447,7 @@ func (s *GrpcServer) GetOperator(ctx context.Context, request *pdpb.GetOperatorR
 
 // validateRequest checks if Server is leader and clusterID is matched.
 // TODO: Call it in gRPC interceptor.
+
 func (s *GrpcServer) validateRequest(header *pdpb.RequestHeader) error {
 	if s.IsClosed() || !s.member.IsLeader() {
 		return errors.WithStack(ErrNotLeader)
@@ -1663,6 +1675,64 @@ func getForwardedHost(ctx context.Context) string {
 	return ""
 }
 
+// getClientIdentity returns the identity of the client, which is the CN of
+// the verified client certificate, or the IP address of the peer. The
+// identity provided by the client is not trusted.
+func getClientIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+		if cn := info.State.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+			return cn
+		}
+	}
+	if p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// tsoClientLimiterTTL is how long the limiter of a client is kept.
+const tsoClientLimiterTTL = time.Minute
+
+// tsoClientLimiter is a token bucket rate limiter for the TSO requests of every client.
+type tsoClientLimiter struct {
+	limit float64
+	burst int
+	// limiters is a map from the client identity to *ratelimit.RateLimiter.
+	// The limiters expire after tsoClientLimiterTTL, so the map only keeps
+	// the clients seen recently.
+	limiters *cache.TTLString
+}
+
+func newTSOClientLimiter(ctx context.Context, limit float64, burst int) *tsoClientLimiter {
+	return &tsoClientLimiter{
+		limit:    limit,
+		burst:    burst,
+		limiters: cache.NewStringTTL(ctx, tsoClientLimiterTTL, tsoClientLimiterTTL),
+	}
+}
+
+// allow returns whether the TSO request of the given client is allowed.
+func (l *tsoClientLimiter) allow(client string) bool {
+	if l == nil {
+		return true
+	}
+	limiter, ok := l.limiters.Get(client)
+	if !ok {
+		// The limiter is replaced by a full one once it expires, which allows
+		// at most one more burst of the client in every TTL.
+		limiter = ratelimit.NewRateLimiter(l.limit, l.burst)
+		l.limiters.Put(client, limiter)
+	}
+	return limiter.(*ratelimit.RateLimiter).Allow()
+}
+
 func (s *GrpcServer) isLocalRequest(forwardedHost string) bool {
 	if forwardedHost == "" {
 		return true
@@ -0,0 +1,99 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/pingcap/kvproto/pkg/diagnosticspb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"go.etcd.io/etcd/pkg/transport"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// grpcWebServer serves the gRPC-Web requests of the browsers, which are
+// translated to the requests of a gRPC server with the same services as the
+// one embedded in etcd.
+type grpcWebServer struct {
+	grpcServer *grpc.Server
+	httpServer *http.Server
+}
+
+func (s *Server) startGRPCWebServer() error {
+	gs := grpc.NewServer()
+	registerPDServer(gs, &GrpcServer{Server: s})
+	diagnosticspb.RegisterDiagnosticsServer(gs, s)
+	handler := grpcweb.WrapServer(gs, grpcweb.WithOriginFunc(newOriginFunc(s.cfg.GRPCWebAllowedOrigins)))
+
+	l, err := net.Listen("tcp", s.cfg.GRPCWebAddr)
+	if err != nil {
+		return errs.ErrStartGRPCWeb.Wrap(err).GenWithStackByCause()
+	}
+	if len(s.cfg.Security.CertPath) != 0 {
+		tlsInfo := transport.TLSInfo{
+			CertFile:       s.cfg.Security.CertPath,
+			KeyFile:        s.cfg.Security.KeyPath,
+			TrustedCAFile:  s.cfg.Security.CAPath,
+			ClientCertAuth: len(s.cfg.Security.CAPath) != 0,
+		}
+		tlsConfig, err := tlsInfo.ServerConfig()
+		if err != nil {
+			l.Close()
+			return errs.ErrStartGRPCWeb.Wrap(err).GenWithStackByCause()
+		}
+		l = tls.NewListener(l, tlsConfig)
+	}
+
+	hs := &http.Server{Handler: s.ipFilter.Handler(handler)}
+	s.grpcWebServer = &grpcWebServer{grpcServer: gs, httpServer: hs}
+	go func() {
+		if err := hs.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Error("gRPC-Web server exits", zap.String("addr", s.cfg.GRPCWebAddr), errs.ZapError(err))
+		}
+	}()
+	log.Info("start gRPC-Web server", zap.String("addr", s.cfg.GRPCWebAddr))
+	return nil
+}
+
+// newOriginFunc returns whether the cross-origin requests from the origin are
+// allowed, "*" allows any origin.
+func newOriginFunc(allowedOrigins []string) func(origin string) bool {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = struct{}{}
+	}
+	return func(origin string) bool {
+		if _, ok := allowed["*"]; ok {
+			return true
+		}
+		_, ok := allowed[origin]
+		return ok
+	}
+}
+
+func (s *Server) closeGRPCWebServer() {
+	if s.grpcWebServer == nil {
+		return
+	}
+	if err := s.grpcWebServer.httpServer.Close(); err != nil {
+		log.Error("close gRPC-Web server meet error", errs.ZapError(err))
+	}
+	s.grpcWebServer.grpcServer.Stop()
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/encryption"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/tsoutil"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -275,6 +276,60 @@ func (h *Handler) PauseOrResumeScheduler(name string, t int64) error {
 	return err
 }
 
+// PauseScheduler pauses a scheduler for the duration, it is resumed
+// automatically after the duration.
+func (h *Handler) PauseScheduler(name string, duration time.Duration) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.PauseScheduler(name, duration); err != nil {
+		log.Error("can not pause scheduler", zap.String("scheduler-name", name), errs.ZapError(err))
+		return err
+	}
+	log.Info("pause scheduler successfully", zap.String("scheduler-name", name), zap.Duration("duration", duration))
+	return nil
+}
+
+// ResumeScheduler resumes a paused scheduler.
+func (h *Handler) ResumeScheduler(name string) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.ResumeScheduler(name); err != nil {
+		log.Error("can not resume scheduler", zap.String("scheduler-name", name), errs.ZapError(err))
+		return err
+	}
+	log.Info("resume scheduler successfully", zap.String("scheduler-name", name))
+	return nil
+}
+
+// SetSchedulerTimeWindow restricts a scheduler to schedule only inside the
+// time window. The zero time window removes the restriction.
+func (h *Handler) SetSchedulerTimeWindow(name string, tw schedule.TimeWindow) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.SetSchedulerTimeWindow(name, tw); err != nil {
+		log.Error("can not set scheduler time window", zap.String("scheduler-name", name), errs.ZapError(err))
+		return err
+	}
+	log.Info("set scheduler time window successfully", zap.String("scheduler-name", name),
+		zap.Time("start", tw.Start), zap.Time("end", tw.End))
+	return nil
+}
+
+// GetSchedulerTimeWindow returns the time window of a scheduler.
+func (h *Handler) GetSchedulerTimeWindow(name string) (schedule.TimeWindow, error) {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return schedule.TimeWindow{}, err
+	}
+	return c.GetSchedulerTimeWindow(name)
+}
+
 // PauseOrResumeChecker pauses checker for delay seconds or resume checker
 // t == 0 : resume checker.
 // t > 0 : checker delays t seconds.
@@ -313,6 +368,22 @@ func (h *Handler) AddLabelScheduler() error {
 	return h.AddScheduler(schedulers.LabelType)
 }
 
+// AddLabelBalanceLeaderScheduler adds a label-balance-leader-scheduler.
+func (h *Handler) AddLabelBalanceLeaderScheduler(labelKey string, tolerance float64) error {
+	return h.AddScheduler(schedulers.LabelBalanceLeaderType, labelKey, strconv.FormatFloat(tolerance, 'f', -1, 64))
+}
+
+// AddTieredBalanceScheduler adds a tiered-balance-scheduler.
+func (h *Handler) AddTieredBalanceScheduler(leaderTolerance, peerTolerance float64) error {
+	return h.AddScheduler(schedulers.TieredBalanceType,
+		strconv.FormatFloat(leaderTolerance, 'f', -1, 64), strconv.FormatFloat(peerTolerance, 'f', -1, 64))
+}
+
+// AddZoneAwareLeaderScheduler adds a zone-aware-leader-scheduler.
+func (h *Handler) AddZoneAwareLeaderScheduler(zoneLabelKey string) error {
+	return h.AddScheduler(schedulers.ZoneAwareLeaderType, zoneLabelKey)
+}
+
 // AddScatterRangeScheduler adds a balance-range-leader-scheduler
 func (h *Handler) AddScatterRangeScheduler(args ...string) error {
 	return h.AddScheduler(schedulers.ScatterRangeType, args...)
@@ -373,6 +444,40 @@ func (h *Handler) GetOperator(regionID uint64) (*operator.Operator, error) {
 	return op, nil
 }
 
+// GetStoreOperatorCount returns the count of the operators moving peers or
+// leaders into the store.
+func (h *Handler) GetStoreOperatorCount(storeID uint64) (uint64, error) {
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		return 0, err
+	}
+	if rc.GetStore(storeID) == nil {
+		return 0, ErrStoreNotFound(storeID)
+	}
+	return rc.GetOperatorController().StoreOperatorCount(storeID), nil
+}
+
+// GetOperatorAuditEntries returns at most limit finished operators from the newest
+// to the oldest. If typ is not empty, only the operators of the type are
+// returned.
+func (h *Handler) GetOperatorAuditEntries(limit int, typ string) ([]*schedule.OperatorAuditEntry, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetOperatorAuditLog().List(limit, typ), nil
+}
+
+// GetOperatorResultStats returns the count of the finished operators and
+// steps by type and result.
+func (h *Handler) GetOperatorResultStats() (*schedule.OperatorResultStats, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetOperatorResultStats(), nil
+}
+
 // GetOperatorStatus returns the status of the region operator.
 func (h *Handler) GetOperatorStatus(regionID uint64) (*schedule.OperatorWithStatus, error) {
 	c, err := h.GetOperatorController()
@@ -921,6 +1026,11 @@ func (h *Handler) ResetTS(ts uint64) error {
 	if tsoAllocator == nil {
 		return ErrServerNotStarted
 	}
+	// The clients get the ts in the HLC layout, see EncodeHLCTimestamp.
+	if h.s.cfg.TSOHLCMode && !h.s.cfg.TSOHLCCompatible {
+		physical, logical := tsoutil.ParseHLC(ts)
+		ts = tsoutil.ComposeTS(physical.UnixNano()/int64(time.Millisecond), int64(logical))
+	}
 	return tsoAllocator.SetTSO(ts)
 }
 
@@ -0,0 +1,119 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// AffinityManager keeps the store affinities of the keyspaces in memory, so
+// the checkers and the schedulers can look them up for every region.
+type AffinityManager struct {
+	sync.RWMutex
+	storage    endpoint.KeyspaceStorage
+	affinities map[uint32]*endpoint.KeyspaceAffinity
+}
+
+// NewAffinityManager creates an AffinityManager and loads the affinities from
+// the storage.
+func NewAffinityManager(storage endpoint.KeyspaceStorage) (*AffinityManager, error) {
+	m := &AffinityManager{
+		storage:    storage,
+		affinities: make(map[uint32]*endpoint.KeyspaceAffinity),
+	}
+	err := storage.LoadKeyspaceAffinities(func(k, v string) {
+		affinity := &endpoint.KeyspaceAffinity{}
+		if err := json.Unmarshal([]byte(v), affinity); err != nil {
+			log.Error("failed to unmarshal keyspace affinity", zap.String("key", k), errs.ZapError(errs.ErrJSONUnmarshal, err))
+			return
+		}
+		m.affinities[affinity.KeyspaceID] = affinity
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetAffinity returns the affinity of the keyspace, nil if it is not set.
+func (m *AffinityManager) GetAffinity(id uint32) *endpoint.KeyspaceAffinity {
+	m.RLock()
+	defer m.RUnlock()
+	return m.affinities[id]
+}
+
+// SetAffinity sets the affinity of the keyspace, an affinity without any
+// required label removes the one set before.
+func (m *AffinityManager) SetAffinity(affinity *endpoint.KeyspaceAffinity) error {
+	for k, v := range affinity.RequiredStoreLabels {
+		if k == "" || v == "" {
+			return errs.ErrKeyspaceContent.FastGenByArgs("empty store label key or value")
+		}
+	}
+	m.Lock()
+	defer m.Unlock()
+	if len(affinity.RequiredStoreLabels) == 0 {
+		if err := m.storage.RemoveKeyspaceAffinity(affinity.KeyspaceID); err != nil {
+			return err
+		}
+		delete(m.affinities, affinity.KeyspaceID)
+		return nil
+	}
+	if err := m.storage.SaveKeyspaceAffinity(affinity); err != nil {
+		return err
+	}
+	m.affinities[affinity.KeyspaceID] = affinity
+	return nil
+}
+
+// GetRegionConstraints returns the label constraints of the stores allowed to
+// serve the region, nil if the region is not restricted.
+func (m *AffinityManager) GetRegionConstraints(region *core.RegionInfo) []placement.LabelConstraint {
+	if m == nil {
+		return nil
+	}
+	id, ok := placement.KeyspaceOfRange(region.GetStartKey(), region.GetEndKey())
+	if !ok {
+		return nil
+	}
+	affinity := m.GetAffinity(id)
+	if affinity == nil {
+		return nil
+	}
+	constraints := make([]placement.LabelConstraint, 0, len(affinity.RequiredStoreLabels))
+	for k, v := range affinity.RequiredStoreLabels {
+		constraints = append(constraints, placement.LabelConstraint{Key: k, Op: placement.In, Values: []string{v}})
+	}
+	sort.Slice(constraints, func(i, j int) bool { return constraints[i].Key < constraints[j].Key })
+	return constraints
+}
+
+// GetRegionAffinityConstraints returns the affinity constraints of the region
+// if the cluster keeps the keyspace affinities.
+func GetRegionAffinityConstraints(cluster interface{}, region *core.RegionInfo) []placement.LabelConstraint {
+	if cl, ok := cluster.(interface{ GetKeyspaceAffinity() *AffinityManager }); ok {
+		return cl.GetKeyspaceAffinity().GetRegionConstraints(region)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// The states of a keyspace.
+const (
+	// StateEnabled is the state of a keyspace in service.
+	StateEnabled = "enabled"
+	// StateTombstone is the state of a keyspace marked for deletion, its data
+	// is left to be cleaned up.
+	StateTombstone = "tombstone"
+)
+
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Manager manages the metadata of the keyspaces.
+type Manager struct {
+	// mu serializes the modifications of the keyspaces, so the IDs and the
+	// names are allocated without races.
+	mu      sync.Mutex
+	storage endpoint.KeyspaceStorage
+}
+
+// NewManager creates a keyspace Manager.
+func NewManager(storage endpoint.KeyspaceStorage) *Manager {
+	return &Manager{storage: storage}
+}
+
+// CreateKeyspace creates a keyspace with the next unused ID. The names of the
+// keyspaces are unique, including the ones marked for deletion.
+func (m *Manager) CreateKeyspace(name string, config map[string]string) (*endpoint.KeyspaceMeta, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("invalid name %q", name))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keyspaces, err := m.LoadAllKeyspaces()
+	if err != nil {
+		return nil, err
+	}
+	var maxID uint32
+	for _, k := range keyspaces {
+		if k.Name == name {
+			return nil, errs.ErrKeyspaceExists.FastGenByArgs(name)
+		}
+		if k.ID > maxID {
+			maxID = k.ID
+		}
+	}
+	if maxID >= placement.MaxKeyspaceID {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs("keyspace ID is exhausted")
+	}
+	if config == nil {
+		config = make(map[string]string)
+	}
+	meta := &endpoint.KeyspaceMeta{
+		ID:        maxID + 1,
+		Name:      name,
+		State:     StateEnabled,
+		Config:    config,
+		CreatedAt: time.Now().Unix(),
+	}
+	adjustKeyRange(meta)
+	if err := m.storage.SaveKeyspace(meta); err != nil {
+		return nil, err
+	}
+	log.Info("keyspace created", zap.Uint32("id", meta.ID), zap.String("name", name))
+	return meta, nil
+}
+
+// LoadKeyspace returns the keyspace of the ID.
+func (m *Manager) LoadKeyspace(id uint32) (*endpoint.KeyspaceMeta, error) {
+	meta, err := m.storage.LoadKeyspace(id)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, errs.ErrKeyspaceNotFound.FastGenByArgs(id)
+	}
+	adjustKeyRange(meta)
+	return meta, nil
+}
+
+// adjustKeyRange routes the key range of the keyspace ID to the keyspace if
+// the keyspace has never been split or merged.
+func adjustKeyRange(meta *endpoint.KeyspaceMeta) {
+	if meta.StartKey == "" && meta.EndKey == "" {
+		startKey, endKey := placement.KeyspaceKeyRange(meta.ID)
+		meta.StartKey, meta.EndKey = hex.EncodeToString(startKey), hex.EncodeToString(endKey)
+	}
+}
+
+// LoadAllKeyspaces returns all the keyspaces in the order of the ID.
+func (m *Manager) LoadAllKeyspaces() ([]*endpoint.KeyspaceMeta, error) {
+	keyspaces := make([]*endpoint.KeyspaceMeta, 0)
+	err := m.storage.LoadKeyspaces(func(k, v string) {
+		meta := &endpoint.KeyspaceMeta{}
+		if err := json.Unmarshal([]byte(v), meta); err != nil {
+			log.Error("failed to unmarshal keyspace", zap.String("key", k), errs.ZapError(errs.ErrJSONUnmarshal, err))
+			return
+		}
+		adjustKeyRange(meta)
+		keyspaces = append(keyspaces, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keyspaces, nil
+}
+
+// UpdateKeyspaceConfig merges the patch into the config of the keyspace, an
+// item patched with nil is removed.
+func (m *Manager) UpdateKeyspaceConfig(id uint32, patch map[string]*string) (*endpoint.KeyspaceMeta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, err := m.LoadKeyspace(id)
+	if err != nil {
+		return nil, err
+	}
+	if meta.State == StateTombstone {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("keyspace %d is marked for deletion", id))
+	}
+	if meta.Config == nil {
+		meta.Config = make(map[string]string)
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(meta.Config, k)
+		} else {
+			meta.Config[k] = *v
+		}
+	}
+	if err := m.storage.SaveKeyspace(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// SetKeyspaceTTL sets the time to live of the data in the keyspace, 0 means
+// the data never expires.
+func (m *Manager) SetKeyspaceTTL(id uint32, ttlSeconds uint64) (*endpoint.KeyspaceMeta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, err := m.LoadKeyspace(id)
+	if err != nil {
+		return nil, err
+	}
+	if meta.State == StateTombstone {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("keyspace %d is marked for deletion", id))
+	}
+	meta.TTLSeconds = ttlSeconds
+	if err := m.storage.SaveKeyspace(meta); err != nil {
+		return nil, err
+	}
+	log.Info("keyspace ttl updated", zap.Uint32("id", id), zap.Uint64("ttl-seconds", ttlSeconds))
+	return meta, nil
+}
+
+// DeleteKeyspace marks the keyspace for deletion, the metadata is kept so the
+// ID and the name are not reused. Deleting a keyspace twice is a no-op.
+func (m *Manager) DeleteKeyspace(id uint32) (*endpoint.KeyspaceMeta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, err := m.LoadKeyspace(id)
+	if err != nil {
+		return nil, err
+	}
+	if meta.State == StateTombstone {
+		return meta, nil
+	}
+	meta.State = StateTombstone
+	if err := m.storage.SaveKeyspace(meta); err != nil {
+		return nil, err
+	}
+	log.Info("keyspace marked for deletion", zap.Uint32("id", id), zap.String("name", meta.Name))
+	return meta, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage"
+	"github.com/tikv/pd/server/storage/endpoint"
+)
+
+func TestKeyspace(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testKeyspaceSuite{})
+
+type testKeyspaceSuite struct{}
+
+func (s *testKeyspaceSuite) TestLifecycle(c *C) {
+	m := NewManager(storage.NewStorageWithMemoryBackend())
+	meta, err := m.CreateKeyspace("ks1", map[string]string{"a": "1", "b": "2"})
+	c.Assert(err, IsNil)
+	c.Assert(meta.ID, Equals, uint32(1))
+	c.Assert(meta.State, Equals, StateEnabled)
+	c.Assert(meta.CreatedAt, Greater, int64(0))
+	_, err = m.CreateKeyspace("ks1", nil)
+	c.Assert(errs.ErrKeyspaceExists.Equal(err), IsTrue)
+	_, err = m.CreateKeyspace("ks/2", nil)
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+	meta, err = m.CreateKeyspace("ks2", nil)
+	c.Assert(err, IsNil)
+	c.Assert(meta.ID, Equals, uint32(2))
+	c.Assert(meta.Config, NotNil)
+
+	v := "3"
+	meta, err = m.UpdateKeyspaceConfig(1, map[string]*string{"a": nil, "b": &v, "c": &v})
+	c.Assert(err, IsNil)
+	c.Assert(meta.Config, DeepEquals, map[string]string{"b": "3", "c": "3"})
+	meta, err = m.LoadKeyspace(1)
+	c.Assert(err, IsNil)
+	c.Assert(meta.Config, DeepEquals, map[string]string{"b": "3", "c": "3"})
+	_, err = m.LoadKeyspace(3)
+	c.Assert(errs.ErrKeyspaceNotFound.Equal(err), IsTrue)
+
+	meta, err = m.DeleteKeyspace(1)
+	c.Assert(err, IsNil)
+	c.Assert(meta.State, Equals, StateTombstone)
+	meta, err = m.DeleteKeyspace(1)
+	c.Assert(err, IsNil)
+	c.Assert(meta.State, Equals, StateTombstone)
+	_, err = m.UpdateKeyspaceConfig(1, map[string]*string{"a": &v})
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+	// The name of a deleted keyspace is not reused.
+	_, err = m.CreateKeyspace("ks1", nil)
+	c.Assert(errs.ErrKeyspaceExists.Equal(err), IsTrue)
+
+	keyspaces, err := m.LoadAllKeyspaces()
+	c.Assert(err, IsNil)
+	c.Assert(keyspaces, HasLen, 2)
+	c.Assert(keyspaces[0].Name, Equals, "ks1")
+	c.Assert(keyspaces[1].Name, Equals, "ks2")
+}
+
+func (s *testKeyspaceSuite) TestConcurrentCreate(c *C) {
+	m := NewManager(storage.NewStorageWithMemoryBackend())
+	const n = 20
+	var wg sync.WaitGroup
+	ids := make([]uint32, n)
+	createErrs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every two goroutines race for the same name.
+			meta, err := m.CreateKeyspace(fmt.Sprintf("ks%d", i/2), nil)
+			if err == nil {
+				ids[i] = meta.ID
+			}
+			createErrs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[uint32]bool)
+	for i := 0; i < n; i += 2 {
+		// Exactly one of the two creations succeeds.
+		c.Assert((createErrs[i] == nil) != (createErrs[i+1] == nil), IsTrue)
+		for _, j := range []int{i, i + 1} {
+			if createErrs[j] == nil {
+				c.Assert(seen[ids[j]], IsFalse)
+				seen[ids[j]] = true
+			} else {
+				c.Assert(errs.ErrKeyspaceExists.Equal(createErrs[j]), IsTrue)
+			}
+		}
+	}
+	c.Assert(seen, HasLen, n/2)
+}
+
+// keyAfter returns a key in the key range starting from the start key.
+func keyAfter(startKey []byte, suffix byte) []byte {
+	return append(append([]byte{}, startKey...), suffix)
+}
+
+func (s *testKeyspaceSuite) TestSplitMerge(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	m := NewManager(store)
+	ks1, err := m.CreateKeyspace("ks1", map[string]string{"a": "1"})
+	c.Assert(err, IsNil)
+	startKey, endKey := placement.KeyspaceKeyRange(1)
+	c.Assert(ks1.StartKey, Equals, hex.EncodeToString(startKey))
+	c.Assert(ks1.EndKey, Equals, hex.EncodeToString(endKey))
+
+	splitKey := keyAfter(startKey, 'm')
+	child, err := m.SplitKeyspace(1, splitKey, "")
+	c.Assert(err, IsNil)
+	c.Assert(child.ID, Equals, uint32(2))
+	c.Assert(child.Name, Equals, "ks1-2")
+	c.Assert(child.Config, DeepEquals, map[string]string{"a": "1"})
+	c.Assert(child.StartKey, Equals, hex.EncodeToString(splitKey))
+	c.Assert(child.EndKey, Equals, ks1.EndKey)
+	ks1, err = m.LoadKeyspace(1)
+	c.Assert(err, IsNil)
+	c.Assert(ks1.EndKey, Equals, hex.EncodeToString(splitKey))
+	// Splitting at the same key again returns the same keyspace.
+	again, err := m.SplitKeyspace(1, splitKey, "")
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, child)
+	// The split key must be in the key range of the keyspace.
+	_, err = m.SplitKeyspace(1, endKey, "")
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+	_, err = m.SplitKeyspace(1, startKey, "")
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+
+	// Keyspace 3 is not contiguous to keyspace 1.
+	_, err = m.CreateKeyspace("ks3", nil)
+	c.Assert(err, IsNil)
+	_, err = m.MergeKeyspaces(1, 3)
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+	_, err = m.MergeKeyspaces(1, 1)
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+
+	merged, err := m.MergeKeyspaces(2, 1)
+	c.Assert(err, IsNil)
+	c.Assert(merged.ID, Equals, uint32(1))
+	c.Assert(merged.StartKey, Equals, hex.EncodeToString(startKey))
+	c.Assert(merged.EndKey, Equals, hex.EncodeToString(endKey))
+	child, err = m.LoadKeyspace(2)
+	c.Assert(err, IsNil)
+	c.Assert(child.State, Equals, StateTombstone)
+	// Merging the keyspaces again returns the merged keyspace.
+	again, err = m.MergeKeyspaces(1, 2)
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, merged)
+}
+
+func (s *testKeyspaceSuite) TestOverlappingRanges(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	m := NewManager(store)
+	_, err := m.CreateKeyspace("ks1", nil)
+	c.Assert(err, IsNil)
+	startKey, _ := placement.KeyspaceKeyRange(1)
+	// A keyspace routed with the upper part of keyspace 1.
+	overlapped := &endpoint.KeyspaceMeta{
+		ID:       2,
+		Name:     "ks2",
+		State:    StateEnabled,
+		StartKey: hex.EncodeToString(keyAfter(startKey, 'x')),
+		EndKey:   hex.EncodeToString(keyAfter(startKey, 'z')),
+	}
+	c.Assert(store.SaveKeyspace(overlapped), IsNil)
+	_, err = m.SplitKeyspace(1, keyAfter(startKey, 'm'), "")
+	c.Assert(errs.ErrKeyspaceContent.Equal(err), IsTrue)
+	ks1, err := m.LoadKeyspace(1)
+	c.Assert(err, IsNil)
+	_, endKey := placement.KeyspaceKeyRange(1)
+	c.Assert(ks1.EndKey, Equals, hex.EncodeToString(endKey))
+}
@@ -0,0 +1,251 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"github.com/tikv/pd/server/storage/kv"
+	"go.uber.org/zap"
+)
+
+// SplitKeyspace splits the key range of the keyspace at the split key, the
+// upper half is routed to a new keyspace with the same config. The new
+// keyspace is named after the split one if the name is empty. Splitting a
+// keyspace at the same key again returns the keyspace created before.
+func (m *Manager) SplitKeyspace(id uint32, splitKey []byte, name string) (*endpoint.KeyspaceMeta, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keyspaces, err := m.LoadAllKeyspaces()
+	if err != nil {
+		return nil, err
+	}
+	parent := findKeyspace(keyspaces, id)
+	if parent == nil {
+		return nil, errs.ErrKeyspaceNotFound.FastGenByArgs(id)
+	}
+	if parent.State != StateEnabled {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("keyspace %d is marked for deletion", id))
+	}
+	splitKeyStr := hex.EncodeToString(splitKey)
+	if parent.EndKey == splitKeyStr {
+		for _, k := range keyspaces {
+			if k.StartKey == splitKeyStr && k.State == StateEnabled {
+				return k, nil
+			}
+		}
+	}
+	startKey, endKey, err := decodeKeyRange(parent)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(splitKey, startKey) <= 0 || (len(endKey) > 0 && bytes.Compare(splitKey, endKey) >= 0) {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("split key %s is not in keyspace %d", splitKeyStr, id))
+	}
+
+	var maxID uint32
+	for _, k := range keyspaces {
+		if k.ID > maxID {
+			maxID = k.ID
+		}
+	}
+	if maxID >= placement.MaxKeyspaceID {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs("keyspace ID is exhausted")
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s-%d", parent.Name, maxID+1)
+	}
+	if !nameRegexp.MatchString(name) {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("invalid name %q", name))
+	}
+	for _, k := range keyspaces {
+		if k.Name == name {
+			return nil, errs.ErrKeyspaceExists.FastGenByArgs(name)
+		}
+	}
+	config := make(map[string]string, len(parent.Config))
+	for k, v := range parent.Config {
+		config[k] = v
+	}
+	child := &endpoint.KeyspaceMeta{
+		ID:        maxID + 1,
+		Name:      name,
+		State:     StateEnabled,
+		Config:    config,
+		CreatedAt: time.Now().Unix(),
+		StartKey:  splitKeyStr,
+		EndKey:    parent.EndKey,
+	}
+	updated := *parent
+	updated.EndKey = splitKeyStr
+	if err := checkKeyRanges(append(keyspaces, child), &updated); err != nil {
+		return nil, err
+	}
+
+	err = m.storage.RunInTxn(func(txn kv.Txn) error {
+		if err := checkUnchangedInTxn(txn, parent); err != nil {
+			return err
+		}
+		if meta, err := endpoint.LoadKeyspaceInTxn(txn, child.ID); err != nil || meta != nil {
+			return errs.ErrEtcdTxnConflict.FastGenByArgs()
+		}
+		if err := endpoint.SaveKeyspaceInTxn(txn, &updated); err != nil {
+			return err
+		}
+		return endpoint.SaveKeyspaceInTxn(txn, child)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("keyspace split", zap.Uint32("id", id), zap.Uint32("new-id", child.ID), zap.String("split-key", splitKeyStr))
+	return child, nil
+}
+
+// MergeKeyspaces merges the key ranges of two keyspaces, which must be
+// contiguous. The keyspace with the lower range takes the merged range and the
+// other one is marked for deletion. Merging the keyspaces again returns the
+// merged keyspace.
+func (m *Manager) MergeKeyspaces(id1, id2 uint32) (*endpoint.KeyspaceMeta, error) {
+	if id1 == id2 {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs("can not merge a keyspace with itself")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keyspaces, err := m.LoadAllKeyspaces()
+	if err != nil {
+		return nil, err
+	}
+	target, source := findKeyspace(keyspaces, id1), findKeyspace(keyspaces, id2)
+	if target == nil {
+		return nil, errs.ErrKeyspaceNotFound.FastGenByArgs(id1)
+	}
+	if source == nil {
+		return nil, errs.ErrKeyspaceNotFound.FastGenByArgs(id2)
+	}
+	if source.StartKey < target.StartKey {
+		target, source = source, target
+	}
+	if target.State == StateEnabled && source.State == StateTombstone &&
+		target.StartKey <= source.StartKey && source.EndKey == target.EndKey {
+		return target, nil
+	}
+	if target.State != StateEnabled || source.State != StateEnabled {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs("can not merge the keyspaces marked for deletion")
+	}
+	if target.EndKey != source.StartKey {
+		return nil, errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("the key ranges of keyspace %d and %d are not contiguous", target.ID, source.ID))
+	}
+
+	updatedTarget, updatedSource := *target, *source
+	updatedTarget.EndKey = source.EndKey
+	updatedSource.State = StateTombstone
+	if err := checkKeyRanges(keyspaces, &updatedTarget, &updatedSource); err != nil {
+		return nil, err
+	}
+
+	err = m.storage.RunInTxn(func(txn kv.Txn) error {
+		if err := checkUnchangedInTxn(txn, target); err != nil {
+			return err
+		}
+		if err := checkUnchangedInTxn(txn, source); err != nil {
+			return err
+		}
+		if err := endpoint.SaveKeyspaceInTxn(txn, &updatedTarget); err != nil {
+			return err
+		}
+		return endpoint.SaveKeyspaceInTxn(txn, &updatedSource)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("keyspaces merged", zap.Uint32("id", updatedTarget.ID), zap.Uint32("merged-id", updatedSource.ID))
+	return &updatedTarget, nil
+}
+
+func findKeyspace(keyspaces []*endpoint.KeyspaceMeta, id uint32) *endpoint.KeyspaceMeta {
+	for _, k := range keyspaces {
+		if k.ID == id {
+			return k
+		}
+	}
+	return nil
+}
+
+func decodeKeyRange(meta *endpoint.KeyspaceMeta) (startKey, endKey []byte, err error) {
+	if startKey, err = hex.DecodeString(meta.StartKey); err != nil {
+		return nil, nil, errs.ErrHexDecodingString.FastGenByArgs(meta.StartKey)
+	}
+	if endKey, err = hex.DecodeString(meta.EndKey); err != nil {
+		return nil, nil, errs.ErrHexDecodingString.FastGenByArgs(meta.EndKey)
+	}
+	return startKey, endKey, nil
+}
+
+// checkKeyRanges checks that the key ranges of the enabled keyspaces do not
+// overlap after the keyspaces are updated.
+func checkKeyRanges(keyspaces []*endpoint.KeyspaceMeta, updated ...*endpoint.KeyspaceMeta) error {
+	type keyRange struct {
+		id         uint32
+		start, end []byte
+	}
+	var ranges []keyRange
+	for _, k := range keyspaces {
+		if u := findKeyspace(updated, k.ID); u != nil {
+			k = u
+		}
+		if k.State != StateEnabled {
+			continue
+		}
+		start, end, err := decodeKeyRange(k)
+		if err != nil {
+			return err
+		}
+		ranges = append(ranges, keyRange{id: k.ID, start: start, end: end})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return bytes.Compare(ranges[i].start, ranges[j].start) < 0 })
+	for i := 1; i < len(ranges); i++ {
+		prev := ranges[i-1]
+		if len(prev.end) == 0 || bytes.Compare(prev.end, ranges[i].start) > 0 {
+			return errs.ErrKeyspaceContent.FastGenByArgs(fmt.Sprintf("the key ranges of keyspace %d and %d overlap", prev.id, ranges[i].id))
+		}
+	}
+	return nil
+}
+
+// checkUnchangedInTxn checks that the keyspace is not changed since it is
+// loaded, the keys loaded in the transaction are guarded against the
+// concurrent writes.
+func checkUnchangedInTxn(txn kv.Txn, meta *endpoint.KeyspaceMeta) error {
+	current, err := endpoint.LoadKeyspaceInTxn(txn, meta.ID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return errs.ErrEtcdTxnConflict.FastGenByArgs()
+	}
+	adjustKeyRange(current)
+	if current.State != meta.State || current.StartKey != meta.StartKey || current.EndKey != meta.EndKey {
+		return errs.ErrEtcdTxnConflict.FastGenByArgs()
+	}
+	return nil
+}
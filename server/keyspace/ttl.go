@@ -0,0 +1,203 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/tsoutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// GCSafePoint returns the GC safe point of a keyspace, the data of a keyspace
+// with TTL expires once it is older than the TTL, but not before the global
+// safe point.
+func GCSafePoint(globalSafePoint, ttlSeconds uint64, now time.Time) uint64 {
+	if ttlSeconds == 0 {
+		return globalSafePoint
+	}
+	expired := now.Add(-time.Duration(ttlSeconds) * time.Second)
+	ttlSafePoint := tsoutil.ComposeTS(expired.UnixNano()/int64(time.Millisecond), 0)
+	if ttlSafePoint < globalSafePoint {
+		return ttlSafePoint
+	}
+	return globalSafePoint
+}
+
+// RangeDeleter deletes the data in the raw key range of the region through
+// Raft, so all the replicas of the region delete it consistently.
+type RangeDeleter interface {
+	DeleteRange(ctx context.Context, region *core.RegionInfo, startKey, endKey []byte) error
+}
+
+// RegionScanner scans the regions in the key range.
+type RegionScanner interface {
+	ScanRange(startKey, endKey []byte, limit int) []*core.RegionInfo
+}
+
+// TTLStorage is the storage of the keyspaces and the GC safe point.
+type TTLStorage interface {
+	endpoint.KeyspaceStorage
+	endpoint.GCSafePointStorage
+}
+
+// TTLWorker expires the data of the keyspaces with TTL. It publishes the GC
+// safe point of every keyspace, so the GC removes the expired versions. The
+// last write time of every region is also observed from the heartbeats, and a
+// region not written since the GC safe point of its keyspace is cleaned up.
+type TTLWorker struct {
+	manager *Manager
+	storage TTLStorage
+	deleter RangeDeleter
+
+	mu sync.Mutex
+	// lastWrites is the time when the regions are written last time, the
+	// regions are regarded as written when they are first observed.
+	lastWrites map[uint64]time.Time
+}
+
+// NewTTLWorker creates a TTLWorker.
+func NewTTLWorker(storage TTLStorage, deleter RangeDeleter) *TTLWorker {
+	return &TTLWorker{
+		manager:    NewManager(storage),
+		storage:    storage,
+		deleter:    deleter,
+		lastWrites: make(map[uint64]time.Time),
+	}
+}
+
+// Observe records the writes of the region reported by the heartbeat.
+func (w *TTLWorker) Observe(region *core.RegionInfo, now time.Time) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.lastWrites[region.GetID()]; !ok || region.GetBytesWritten() > 0 || region.GetKeysWritten() > 0 {
+		w.lastWrites[region.GetID()] = now
+	}
+}
+
+// Remove forgets the region which is merged or overlapped.
+func (w *TTLWorker) Remove(regionID uint64) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.lastWrites, regionID)
+}
+
+// RunOnce publishes the GC safe points of the keyspaces with TTL and cleans up
+// their expired regions, it returns the number of the regions cleaned up.
+func (w *TTLWorker) RunOnce(ctx context.Context, regions RegionScanner, now time.Time) (int, error) {
+	keyspaces, err := w.manager.LoadAllKeyspaces()
+	if err != nil {
+		return 0, err
+	}
+	globalSafePoint, err := w.storage.LoadGCSafePoint()
+	if err != nil {
+		return 0, err
+	}
+	cleaned := 0
+	for _, k := range keyspaces {
+		if k.State != StateEnabled || k.TTLSeconds == 0 {
+			continue
+		}
+		safePoint, err := w.updateGCSafePoint(k.ID, GCSafePoint(globalSafePoint, k.TTLSeconds, now))
+		if err != nil {
+			return cleaned, err
+		}
+		if safePoint == 0 {
+			continue
+		}
+		expireBefore, _ := tsoutil.ParseTS(safePoint)
+		startKey, endKey, err := decodeKeyRange(k)
+		if err != nil {
+			return cleaned, err
+		}
+		for _, region := range regions.ScanRange(startKey, endKey, -1) {
+			if !w.expired(region.GetID(), expireBefore) {
+				continue
+			}
+			if err := w.deleteRegion(ctx, region, startKey, endKey); err != nil {
+				log.Warn("failed to clean up the expired region of keyspace",
+					zap.Uint32("keyspace-id", k.ID), zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+				continue
+			}
+			log.Info("expired region of keyspace cleaned up",
+				zap.Uint32("keyspace-id", k.ID), zap.Uint64("region-id", region.GetID()), zap.Uint64("safe-point", safePoint))
+			w.mu.Lock()
+			w.lastWrites[region.GetID()] = now
+			w.mu.Unlock()
+			cleaned++
+		}
+	}
+	return cleaned, nil
+}
+
+// updateGCSafePoint publishes the GC safe point of the keyspace, it returns
+// the published one. The safe point never goes back, e.g. once the TTL is
+// increased, since the data before it may have been removed.
+func (w *TTLWorker) updateGCSafePoint(id uint32, safePoint uint64) (uint64, error) {
+	old, err := w.storage.LoadKeyspaceGCSafePoint(id)
+	if err != nil {
+		return 0, err
+	}
+	if safePoint <= old {
+		return old, nil
+	}
+	if err := w.storage.SaveKeyspaceGCSafePoint(id, safePoint); err != nil {
+		return 0, err
+	}
+	log.Info("keyspace gc safe point updated", zap.Uint32("keyspace-id", id), zap.Uint64("safe-point", safePoint))
+	return safePoint, nil
+}
+
+func (w *TTLWorker) expired(regionID uint64, expireBefore time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	lastWrite, ok := w.lastWrites[regionID]
+	return ok && lastWrite.Before(expireBefore)
+}
+
+// deleteRegion deletes the data of the region in the key range of the
+// keyspace, the region keys are encoded while the deleter takes raw keys.
+func (w *TTLWorker) deleteRegion(ctx context.Context, region *core.RegionInfo, startKey, endKey []byte) error {
+	start, end := region.GetStartKey(), region.GetEndKey()
+	if bytes.Compare(start, startKey) < 0 {
+		start = startKey
+	}
+	if len(end) == 0 || bytes.Compare(end, endKey) > 0 {
+		end = endKey
+	}
+	_, rawStart, err := codec.DecodeBytes(start)
+	if err != nil {
+		return err
+	}
+	_, rawEnd, err := codec.DecodeBytes(end)
+	if err != nil {
+		return err
+	}
+	return w.deleter.DeleteRange(ctx, region, rawStart, rawEnd)
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"context"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/tsoutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/storage"
+)
+
+type mockRangeDeleter struct {
+	ranges [][2]string
+}
+
+func (d *mockRangeDeleter) DeleteRange(_ context.Context, _ *core.RegionInfo, startKey, endKey []byte) error {
+	d.ranges = append(d.ranges, [2]string{string(startKey), string(endKey)})
+	return nil
+}
+
+func composeTS(t time.Time) uint64 {
+	return tsoutil.ComposeTS(t.UnixNano()/int64(time.Millisecond), 0)
+}
+
+func (s *testKeyspaceSuite) TestGCSafePoint(c *C) {
+	now := time.Now()
+	global := composeTS(now.Add(-time.Minute))
+	c.Assert(GCSafePoint(global, 0, now), Equals, global)
+	c.Assert(GCSafePoint(global, 3600, now), Equals, composeTS(now.Add(-time.Hour)))
+	c.Assert(GCSafePoint(global, 10, now), Equals, global)
+	c.Assert(GCSafePoint(0, 3600, now), Equals, uint64(0))
+}
+
+func (s *testKeyspaceSuite) TestTTLWorker(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	m := NewManager(store)
+	_, err := m.CreateKeyspace("ks1", nil)
+	c.Assert(err, IsNil)
+	_, err = m.CreateKeyspace("ks2", nil)
+	c.Assert(err, IsNil)
+	_, err = m.SetKeyspaceTTL(1, 3600)
+	c.Assert(err, IsNil)
+	_, err = m.SetKeyspaceTTL(3, 3600)
+	c.Assert(errs.ErrKeyspaceNotFound.Equal(err), IsTrue)
+
+	// Regions [start1, mid1), [mid1, start2) in ks1 and [start2, end2) in ks2.
+	start1, _ := placement.KeyspaceKeyRange(1)
+	start2, end2 := placement.KeyspaceKeyRange(2)
+	_, rawStart1, err := codec.DecodeBytes(start1)
+	c.Assert(err, IsNil)
+	_, rawStart2, err := codec.DecodeBytes(start2)
+	c.Assert(err, IsNil)
+	rawMid1 := append(append([]byte{}, rawStart1...), 'm')
+	mid1 := codec.EncodeBytes(rawMid1)
+	keys := [][]byte{start1, mid1, start2, end2}
+	regions := core.NewBasicCluster()
+	for i := 0; i < len(keys)-1; i++ {
+		regions.PutRegion(core.NewRegionInfo(&metapb.Region{Id: uint64(i + 1), StartKey: keys[i], EndKey: keys[i+1]}, nil))
+	}
+
+	deleter := &mockRangeDeleter{}
+	w := NewTTLWorker(store, deleter)
+	now := time.Now()
+	// Region 1 and region 3 are written 2 hours ago, region 2 is written 10 minutes ago.
+	w.Observe(regions.GetRegion(1), now.Add(-2*time.Hour))
+	w.Observe(regions.GetRegion(2), now.Add(-2*time.Hour))
+	w.Observe(regions.GetRegion(3), now.Add(-2*time.Hour))
+	w.Observe(regions.GetRegion(2).Clone(core.SetWrittenBytes(1)), now.Add(-10*time.Minute))
+	// A heartbeat without writes keeps the last write time.
+	w.Observe(regions.GetRegion(1), now)
+
+	// No cleanup before the global safe point is set.
+	cleaned, err := w.RunOnce(context.Background(), regions, now)
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 0)
+	safePoint, err := store.LoadKeyspaceGCSafePoint(1)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(0))
+
+	// The data is kept if the global safe point is behind the TTL.
+	c.Assert(store.SaveGCSafePoint(composeTS(now.Add(-3*time.Hour))), IsNil)
+	cleaned, err = w.RunOnce(context.Background(), regions, now)
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 0)
+
+	c.Assert(store.SaveGCSafePoint(composeTS(now)), IsNil)
+	cleaned, err = w.RunOnce(context.Background(), regions, now)
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 1)
+	c.Assert(deleter.ranges, DeepEquals, [][2]string{{string(rawStart1), string(rawMid1)}})
+	// The safe point of the keyspace is published for the GC.
+	safePoint, err = store.LoadKeyspaceGCSafePoint(1)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, composeTS(now.Add(-time.Hour)))
+	safePoint, err = store.LoadKeyspaceGCSafePoint(2)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(0))
+
+	// The cleaned region is not cleaned again until it expires again.
+	cleaned, err = w.RunOnce(context.Background(), regions, now.Add(time.Minute))
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 0)
+
+	// A region crossing the keyspace boundary is clipped to the keyspace.
+	w.Remove(2)
+	w.Remove(3)
+	regions.PutRegion(core.NewRegionInfo(&metapb.Region{Id: 4, StartKey: mid1, EndKey: end2}, nil))
+	w.Observe(regions.GetRegion(4), now.Add(-2*time.Hour))
+	deleter.ranges = nil
+	cleaned, err = w.RunOnce(context.Background(), regions, now)
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 1)
+	c.Assert(deleter.ranges, DeepEquals, [][2]string{{string(rawMid1), string(rawStart2)}})
+
+	// The safe point does not go back once the TTL is increased.
+	_, err = m.SetKeyspaceTTL(1, 2*3600)
+	c.Assert(err, IsNil)
+	_, err = w.RunOnce(context.Background(), regions, now)
+	c.Assert(err, IsNil)
+	safePoint, err = store.LoadKeyspaceGCSafePoint(1)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, composeTS(now.Add(time.Minute-time.Hour)))
+
+	// Disabling the TTL stops the cleanup.
+	_, err = m.SetKeyspaceTTL(1, 0)
+	c.Assert(err, IsNil)
+	cleaned, err = w.RunOnce(context.Background(), regions, now.Add(2*time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(cleaned, Equals, 0)
+}
@@ -32,4 +32,54 @@ var (
 			Name:      "dr_recover_progress",
 			Help:      "Progress of sync_recover process",
 		})
+
+	drLagBytesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "dr_sync",
+			Name:      "replication_lag_bytes",
+			Help:      "Max estimated replication lag of the stores in the dr zone",
+		})
+
+	drLagDurationGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "replication",
+			Name:      "dr_lag_duration_seconds",
+			Help:      "Max duration the stores in the dr zone have been lagging",
+		})
+
+	drLaggingStoreGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "replication",
+			Name:      "dr_lagging_store_count",
+			Help:      "Number of the lagging stores in the dr zone",
+		})
+
+	peerLagBytesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "replication",
+			Name:      "peer_lag_bytes",
+			Help:      "Estimated replication lag of the replication peers",
+		}, []string{"address", "role"})
+
+	peerLagDurationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "replication",
+			Name:      "peer_lag_duration_seconds",
+			Help:      "Duration the replication peers have been lagging",
+		}, []string{"address", "role"})
 )
+
+func init() {
+	prometheus.MustRegister(drTickCounter)
+	prometheus.MustRegister(drRecoverProgressGauge)
+	prometheus.MustRegister(drLagBytesGauge)
+	prometheus.MustRegister(drLagDurationGauge)
+	prometheus.MustRegister(drLaggingStoreGauge)
+	prometheus.MustRegister(peerLagBytesGauge)
+	prometheus.MustRegister(peerLagDurationGauge)
+}
@@ -18,12 +18,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	pb "github.com/pingcap/kvproto/pkg/replication_modepb"
 	"github.com/pingcap/log"
@@ -87,6 +89,14 @@ type ModeManager struct {
 
 	drMemberWaitAsyncTime map[uint64]time.Time // last sync time with follower nodes
 	drStoreStatus         sync.Map
+
+	drLag    ReplicationLag
+	peerLags []PeerLag
+	// drLagSince and peerLagSince are the time when the dr stores and the
+	// replication peers start lagging, they're only accessed by the
+	// background job.
+	drLagSince   map[uint64]time.Time
+	peerLagSince map[string]time.Time
 }
 
 // NewReplicationModeManager creates the replicate mode manager.
@@ -98,6 +108,8 @@ func NewReplicationModeManager(config config.ReplicationModeConfig, storage endp
 		cluster:               cluster,
 		fileReplicater:        fileReplicater,
 		drMemberWaitAsyncTime: make(map[uint64]time.Time),
+		drLagSince:            make(map[uint64]time.Time),
+		peerLagSince:          make(map[string]time.Time),
 	}
 	switch config.ReplicationMode {
 	case modeMajority:
@@ -163,12 +175,14 @@ func (m *ModeManager) GetReplicationStatus() *pb.ReplicationStatus {
 type HTTPReplicationStatus struct {
 	Mode       string `json:"mode"`
 	DrAutoSync struct {
-		LabelKey        string  `json:"label_key"`
-		State           string  `json:"state"`
-		StateID         uint64  `json:"state_id,omitempty"`
-		TotalRegions    int     `json:"total_regions,omitempty"`
-		SyncedRegions   int     `json:"synced_regions,omitempty"`
-		RecoverProgress float32 `json:"recover_progress,omitempty"`
+		LabelKey        string     `json:"label_key"`
+		State           string     `json:"state"`
+		StateID         uint64     `json:"state_id,omitempty"`
+		TotalRegions    int        `json:"total_regions,omitempty"`
+		SyncedRegions   int        `json:"synced_regions,omitempty"`
+		RecoverProgress float32    `json:"recover_progress,omitempty"`
+		Pinned          bool       `json:"pinned,omitempty"`
+		PinUntil        *time.Time `json:"pin_until,omitempty"`
 	} `json:"dr-auto-sync,omitempty"`
 }
 
@@ -187,6 +201,8 @@ func (m *ModeManager) GetReplicationStatusHTTP() *HTTPReplicationStatus {
 		status.DrAutoSync.RecoverProgress = m.drAutoSync.RecoverProgress
 		status.DrAutoSync.TotalRegions = m.drAutoSync.TotalRegions
 		status.DrAutoSync.SyncedRegions = m.drAutoSync.SyncedRegions
+		status.DrAutoSync.Pinned = m.drAutoSync.Pinned
+		status.DrAutoSync.PinUntil = m.drAutoSync.PinUntil
 	}
 	return &status
 }
@@ -212,6 +228,10 @@ type drAutoSyncStatus struct {
 	SyncedRegions    int        `json:"synced_regions,omitempty"`
 	RecoverProgress  float32    `json:"recover_progress,omitempty"`
 	AvailableStores  []uint64   `json:"available_stores,omitempty"`
+	// Pinned is true if the state is set manually and the automatic
+	// transitions are skipped until PinUntil, or forever if it's nil.
+	Pinned   bool       `json:"pinned,omitempty"`
+	PinUntil *time.Time `json:"pin_until,omitempty"`
 }
 
 func (m *ModeManager) loadDRAutoSync() error {
@@ -330,6 +350,80 @@ func (m *ModeManager) drSwitchToSync() error {
 	return nil
 }
 
+// SetDRState switches the dr-auto-sync state manually, which is used during
+// the planned maintenance. If pin is true, the automatic transitions are
+// skipped until pinUntil, or until the state is unpinned if pinUntil is nil.
+// An empty state only updates the pin.
+func (m *ModeManager) SetDRState(state string, pin bool, pinUntil *time.Time) error {
+	if mode := m.getModeName(); mode != modeDRAutoSync {
+		return errs.ErrDRAutoSyncNotEnabled.FastGenByArgs(mode)
+	}
+	switch state {
+	case "", drStateSync, drStateAsyncWait, drStateAsync, drStateSyncRecover:
+	default:
+		return errs.ErrDRAutoSyncState.FastGenByArgs(fmt.Sprintf("unknown state %s", state))
+	}
+	if !pin {
+		pinUntil = nil
+	} else if pinUntil != nil && !pinUntil.After(time.Now()) {
+		return errs.ErrDRAutoSyncState.FastGenByArgs("pin_until is in the past")
+	}
+	var availableStores []uint64
+	if state == drStateAsyncWait || state == drStateAsync {
+		availableStores = m.checkStoreStatus()[primaryUp]
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	dr := m.drAutoSync
+	if state != "" && state != dr.State {
+		id, err := m.cluster.GetAllocator().Alloc()
+		if err != nil {
+			log.Warn("failed to switch state manually", zap.String("replicate-mode", modeDRAutoSync), zap.String("new-state", state), errs.ZapError(err))
+			return err
+		}
+		dr = drAutoSyncStatus{State: state, StateID: id, AvailableStores: availableStores}
+		if state == drStateSyncRecover {
+			now := time.Now()
+			dr.RecoverStartTime = &now
+		}
+		m.drPersistStatusWithLock(dr)
+	}
+	dr.Pinned, dr.PinUntil = pin, pinUntil
+	if err := m.storage.SaveReplicationStatus(modeDRAutoSync, dr); err != nil {
+		log.Warn("failed to switch state manually", zap.String("replicate-mode", modeDRAutoSync), zap.String("new-state", dr.State), errs.ZapError(err))
+		return err
+	}
+	if dr.State == drStateSyncRecover && dr.StateID != m.drAutoSync.StateID {
+		m.drRecoverKey, m.drRecoverCount = nil, 0
+	}
+	m.drAutoSync = dr
+	log.Info("switched state manually", zap.String("replicate-mode", modeDRAutoSync), zap.String("state", dr.State),
+		zap.Bool("pinned", dr.Pinned), zap.Timep("pin-until", dr.PinUntil))
+	return nil
+}
+
+// drCheckPinned checks if the state is pinned, the expired pin is removed.
+func (m *ModeManager) drCheckPinned() bool {
+	m.Lock()
+	defer m.Unlock()
+	if !m.drAutoSync.Pinned {
+		return false
+	}
+	if m.drAutoSync.PinUntil == nil || time.Now().Before(*m.drAutoSync.PinUntil) {
+		return true
+	}
+	dr := m.drAutoSync
+	dr.Pinned, dr.PinUntil = false, nil
+	if err := m.storage.SaveReplicationStatus(modeDRAutoSync, dr); err != nil {
+		log.Warn("failed to unpin state", zap.String("replicate-mode", modeDRAutoSync), errs.ZapError(err))
+		return true
+	}
+	m.drAutoSync = dr
+	log.Info("state unpinned", zap.String("replicate-mode", modeDRAutoSync), zap.String("state", dr.State))
+	return false
+}
+
 func (m *ModeManager) drPersistStatusWithLock(status drAutoSyncStatus) {
 	ctx, cancel := context.WithTimeout(context.Background(), persistFileTimeout)
 	defer cancel()
@@ -399,6 +493,7 @@ func (m *ModeManager) Run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		}
+		m.updatePeerLag()
 		m.tickDR()
 	}
 }
@@ -410,6 +505,13 @@ func (m *ModeManager) tickDR() {
 
 	drTickCounter.Inc()
 
+	lag := m.drUpdateLag()
+
+	if m.drCheckPinned() {
+		m.checkReplicateFile()
+		return
+	}
+
 	totalPrimaryPeers, totalDrPeers := m.config.DRAutoSync.PrimaryReplicas, m.config.DRAutoSync.DRReplicas
 	stores := m.checkStoreStatus()
 
@@ -458,7 +560,7 @@ func (m *ModeManager) tickDR() {
 	switch m.drGetState() {
 	case drStateSync:
 		// If hasMajority is false, the cluster is always unavailable. Switch to async won't help.
-		if !canSync && hasMajority && m.drCheckAsyncTimeout() {
+		if !canSync && hasMajority && m.drCheckAsyncTimeout() && !m.drCheckLagExceeded(lag) {
 			m.drSwitchToAsyncWait(stores[primaryUp])
 		}
 	case drStateAsyncWait:
@@ -565,6 +667,148 @@ func (m *ModeManager) drCheckStoreStateUpdated(stores []uint64) bool {
 	return true
 }
 
+// ReplicationLag is the replication lag of the stores in the dr zone and the
+// replication peers.
+type ReplicationLag struct {
+	MaxLagBytes       uint64    `json:"max_lag_bytes"`
+	MaxLagDurationMs  uint64    `json:"max_lag_duration_ms"`
+	LaggingStoreCount int       `json:"lagging_store_count"`
+	Peers             []PeerLag `json:"peers,omitempty"`
+}
+
+// PeerLag is the replication lag of a replication peer.
+type PeerLag struct {
+	Address string `json:"address"`
+	Role    string `json:"role"`
+	// StoreID is 0 if there is no store with the address.
+	StoreID         uint64 `json:"store_id"`
+	LagBytes        uint64 `json:"lag_bytes"`
+	LagDurationMs   uint64 `json:"lag_duration_ms"`
+	MaxAllowedLagMs uint64 `json:"max_allowed_lag_ms"`
+	// Exceeded is true if the peer lags longer than its lag tolerance.
+	Exceeded bool `json:"exceeded"`
+}
+
+// GetReplicationLag returns the replication lag of the stores in the dr zone
+// and the replication peers updated by the background job.
+func (m *ModeManager) GetReplicationLag() ReplicationLag {
+	m.RLock()
+	defer m.RUnlock()
+	lag := m.drLag
+	lag.Peers = append(m.peerLags[:0:0], m.peerLags...)
+	return lag
+}
+
+// storeLag estimates the replication lag of the store as the size of its
+// pending peers, since the store heartbeats carry no raft log progress.
+func storeLag(store *core.StoreInfo, regionSize uint64) uint64 {
+	return uint64(store.GetPendingPeerCount()) * regionSize
+}
+
+// updatePeerLag tracks the replication lag of every replication peer
+// independently.
+func (m *ModeManager) updatePeerLag() {
+	m.RLock()
+	peers := append(m.config.ReplicationPeers[:0:0], m.config.ReplicationPeers...)
+	m.RUnlock()
+
+	stores := make(map[string]*core.StoreInfo)
+	for _, s := range m.cluster.GetStores() {
+		if !s.IsRemoved() {
+			stores[s.GetAddress()] = s
+		}
+	}
+	now := time.Now()
+	regionSize := uint64(m.cluster.GetAverageRegionSize()) * units.MiB
+	lagSince := make(map[string]time.Time)
+	lags := make([]PeerLag, 0, len(peers))
+	peerLagBytesGauge.Reset()
+	peerLagDurationGauge.Reset()
+	for _, peer := range peers {
+		lag := PeerLag{Address: peer.Address, Role: peer.Role, MaxAllowedLagMs: peer.MaxAllowedLagMs}
+		if s, ok := stores[peer.Address]; ok {
+			lag.StoreID = s.GetID()
+			lag.LagBytes = storeLag(s, regionSize)
+		}
+		if lag.LagBytes > 0 {
+			since, ok := m.peerLagSince[peer.Address]
+			if !ok {
+				since = now
+			}
+			lagSince[peer.Address] = since
+			lag.LagDurationMs = uint64(now.Sub(since).Milliseconds())
+		}
+		lag.Exceeded = peer.MaxAllowedLagMs > 0 && lag.LagDurationMs > peer.MaxAllowedLagMs
+		if lag.Exceeded {
+			log.Warn("replication peer lags behind longer than allowed",
+				zap.String("address", peer.Address), zap.String("role", peer.Role),
+				zap.Uint64("lag-duration-ms", lag.LagDurationMs), zap.Uint64("max-allowed-lag-ms", peer.MaxAllowedLagMs))
+		}
+		peerLagBytesGauge.WithLabelValues(peer.Address, peer.Role).Set(float64(lag.LagBytes))
+		peerLagDurationGauge.WithLabelValues(peer.Address, peer.Role).Set(float64(lag.LagDurationMs) / 1000)
+		lags = append(lags, lag)
+	}
+	m.peerLagSince = lagSince
+
+	m.Lock()
+	m.peerLags = lags
+	m.Unlock()
+}
+
+// drUpdateLag estimates the replication lag of the stores in the dr zone. A
+// store is lagging if it has pending peers.
+func (m *ModeManager) drUpdateLag() ReplicationLag {
+	m.RLock()
+	labelKey, drLabel := m.config.DRAutoSync.LabelKey, m.config.DRAutoSync.DR
+	m.RUnlock()
+
+	now := time.Now()
+	regionSize := uint64(m.cluster.GetAverageRegionSize()) * units.MiB
+	lagSince := make(map[uint64]time.Time)
+	var lag ReplicationLag
+	for _, s := range m.cluster.GetStores() {
+		if s.IsRemoved() || s.GetLabelValue(labelKey) != drLabel || s.GetPendingPeerCount() == 0 {
+			continue
+		}
+		since, ok := m.drLagSince[s.GetID()]
+		if !ok {
+			since = now
+		}
+		lagSince[s.GetID()] = since
+		lag.LaggingStoreCount++
+		if bytes := storeLag(s, regionSize); bytes > lag.MaxLagBytes {
+			lag.MaxLagBytes = bytes
+		}
+		if d := uint64(now.Sub(since).Milliseconds()); d > lag.MaxLagDurationMs {
+			lag.MaxLagDurationMs = d
+		}
+	}
+	m.drLagSince = lagSince
+
+	drLagBytesGauge.Set(float64(lag.MaxLagBytes))
+	drLagDurationGauge.Set(float64(lag.MaxLagDurationMs) / 1000)
+	drLaggingStoreGauge.Set(float64(lag.LaggingStoreCount))
+
+	m.Lock()
+	m.drLag = lag
+	m.Unlock()
+	return lag
+}
+
+// drCheckLagExceeded checks if the dr stores lag behind too much to switch to
+// async, which may lose the data not replicated to the dr zone.
+func (m *ModeManager) drCheckLagExceeded(lag ReplicationLag) bool {
+	m.RLock()
+	defer m.RUnlock()
+	maxLag := uint64(m.config.DRAutoSync.MaxAllowedLagBytes)
+	if maxLag == 0 || lag.MaxLagBytes <= maxLag {
+		return false
+	}
+	log.Warn("replication lag exceeds the limit, skip switching to async",
+		zap.String("replicate-mode", modeDRAutoSync), zap.Uint64("lag-bytes", lag.MaxLagBytes), zap.Uint64("max-allowed-lag-bytes", maxLag))
+	return true
+}
+
 func (m *ModeManager) checkReplicateFile() {
 	members, err := m.fileReplicater.GetMembers()
 	if err != nil {
@@ -21,9 +21,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	pb "github.com/pingcap/kvproto/pkg/replication_modepb"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
@@ -429,6 +431,208 @@ func (s *testReplicationMode) TestAsynctimeout(c *C) {
 	c.Assert(rep.drGetState(), Equals, drStateAsyncWait)
 }
 
+func (s *testReplicationMode) TestManualState(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	conf := config.ReplicationModeConfig{ReplicationMode: modeMajority}
+	cluster := mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	replicator := newMockReplicator([]uint64{1})
+	rep, err := NewReplicationModeManager(conf, store, cluster, replicator)
+	c.Assert(err, IsNil)
+	c.Assert(errs.ErrDRAutoSyncNotEnabled.Equal(rep.SetDRState(drStateAsync, false, nil)), IsTrue)
+
+	conf = config.ReplicationModeConfig{ReplicationMode: modeDRAutoSync, DRAutoSync: config.DRAutoSyncReplicationConfig{
+		LabelKey:         "zone",
+		Primary:          "zone1",
+		DR:               "zone2",
+		PrimaryReplicas:  2,
+		DRReplicas:       1,
+		WaitStoreTimeout: typeutil.Duration{Duration: time.Minute},
+		WaitSyncTimeout:  typeutil.Duration{Duration: time.Minute},
+	}}
+	rep, err = NewReplicationModeManager(conf, store, cluster, replicator)
+	c.Assert(err, IsNil)
+	cluster.AddLabelsStore(1, 1, map[string]string{"zone": "zone1"})
+	cluster.AddLabelsStore(2, 1, map[string]string{"zone": "zone1"})
+
+	c.Assert(errs.ErrDRAutoSyncState.Equal(rep.SetDRState("unknown", false, nil)), IsTrue)
+	past := time.Now().Add(-time.Minute)
+	c.Assert(errs.ErrDRAutoSyncState.Equal(rep.SetDRState(drStateAsync, true, &past)), IsTrue)
+
+	// The pinned sync state is kept though the dr zone is down.
+	stateID := rep.drAutoSync.StateID
+	c.Assert(rep.SetDRState("", true, nil), IsNil)
+	c.Assert(rep.drAutoSync.StateID, Equals, stateID)
+	rep.tickDR()
+	c.Assert(rep.drGetState(), Equals, drStateSync)
+
+	// Force the async state until an hour later.
+	pinUntil := time.Now().Add(time.Hour)
+	c.Assert(rep.SetDRState(drStateAsync, true, &pinUntil), IsNil)
+	c.Assert(rep.drGetState(), Equals, drStateAsync)
+	c.Assert(rep.drAutoSync.StateID, Not(Equals), stateID)
+	stateID = rep.drAutoSync.StateID
+	c.Assert(replicator.lastData[1], Equals, fmt.Sprintf(`{"state":"async","state_id":%d,"available_stores":[1,2]}`, stateID))
+	status := rep.GetReplicationStatusHTTP()
+	c.Assert(status.DrAutoSync.Pinned, IsTrue)
+	c.Assert(status.DrAutoSync.PinUntil.Equal(pinUntil), IsTrue)
+
+	// The pin is kept after the manager is reloaded.
+	rep, err = NewReplicationModeManager(conf, store, cluster, replicator)
+	c.Assert(err, IsNil)
+	c.Assert(rep.drAutoSync.Pinned, IsTrue)
+
+	// The dr zone is up, but the pinned state is not switched to sync_recover.
+	cluster.AddLabelsStore(3, 1, map[string]string{"zone": "zone2"})
+	rep.tickDR()
+	c.Assert(rep.drGetState(), Equals, drStateAsync)
+	c.Assert(rep.drAutoSync.StateID, Equals, stateID)
+
+	// The state is unpinned after the pin expires.
+	rep.drAutoSync.PinUntil = &past
+	rep.tickDR()
+	c.Assert(rep.drGetState(), Equals, drStateSyncRecover)
+	c.Assert(rep.drAutoSync.Pinned, IsFalse)
+	c.Assert(rep.drAutoSync.PinUntil, IsNil)
+
+	// Unpin manually.
+	c.Assert(rep.SetDRState(drStateAsync, true, nil), IsNil)
+	rep.tickDR()
+	c.Assert(rep.drGetState(), Equals, drStateAsync)
+	c.Assert(rep.SetDRState("", false, nil), IsNil)
+	rep.tickDR()
+	c.Assert(rep.drGetState(), Equals, drStateSyncRecover)
+}
+
+func (s *testReplicationMode) TestReplicationLag(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	conf := config.ReplicationModeConfig{ReplicationMode: modeDRAutoSync, DRAutoSync: config.DRAutoSyncReplicationConfig{
+		LabelKey:           "zone",
+		Primary:            "zone1",
+		DR:                 "zone2",
+		PrimaryReplicas:    2,
+		DRReplicas:         1,
+		WaitStoreTimeout:   typeutil.Duration{Duration: time.Minute},
+		WaitSyncTimeout:    typeutil.Duration{Duration: time.Minute},
+		MaxAllowedLagBytes: typeutil.ByteSize(units.GiB),
+	}}
+	cluster := mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	rep, err := NewReplicationModeManager(conf, store, cluster, newMockReplicator([]uint64{1}))
+	c.Assert(err, IsNil)
+
+	cluster.AddLabelsStore(1, 1, map[string]string{"zone": "zone1"})
+	cluster.AddLabelsStore(2, 1, map[string]string{"zone": "zone1"})
+	cluster.AddLabelsStore(3, 1, map[string]string{"zone": "zone2"})
+	cluster.AddLeaderRegion(1, 1, 2, 3)
+	regionSize := uint64(cluster.GetAverageRegionSize()) * units.MiB
+	c.Assert(regionSize, Greater, uint64(0))
+	setPendingPeers := func(n int) {
+		cluster.PutStore(cluster.GetStore(3).Clone(core.SetPendingPeerCount(n)))
+	}
+
+	rep.tickDR()
+	c.Assert(rep.GetReplicationLag(), DeepEquals, ReplicationLag{})
+
+	// The dr store lags behind more than the limit.
+	lagging := int(units.GiB/regionSize) + 1
+	setPendingPeers(lagging)
+	s.setStoreState(cluster, "up", "up", "down")
+	rep.initTime = time.Now().Add(-time.Hour)
+	rep.tickDR()
+	lag := rep.GetReplicationLag()
+	c.Assert(lag.LaggingStoreCount, Equals, 1)
+	c.Assert(lag.MaxLagBytes, Equals, uint64(lagging)*regionSize)
+	c.Assert(rep.drGetState(), Equals, drStateSync)
+
+	// The lag duration is counted from the first time the store lags.
+	rep.drLagSince[3] = time.Now().Add(-time.Minute)
+	rep.tickDR()
+	c.Assert(rep.GetReplicationLag().MaxLagDurationMs, GreaterEqual, uint64(time.Minute.Milliseconds()))
+	c.Assert(rep.drGetState(), Equals, drStateSync)
+
+	// Switch to async once the lag is within the limit.
+	setPendingPeers(1)
+	rep.tickDR()
+	c.Assert(rep.GetReplicationLag().MaxLagBytes, Equals, regionSize)
+	c.Assert(rep.drGetState(), Equals, drStateAsyncWait)
+
+	setPendingPeers(0)
+	rep.tickDR()
+	c.Assert(rep.GetReplicationLag(), DeepEquals, ReplicationLag{})
+	c.Assert(rep.drLagSince, HasLen, 0)
+}
+
+func (s *testReplicationMode) TestPeerLag(c *C) {
+	store := storage.NewStorageWithMemoryBackend()
+	conf := config.ReplicationModeConfig{ReplicationMode: modeMajority, ReplicationPeers: []config.ReplicationPeer{
+		{Address: "spoke1", Role: config.ReplicationPeerFollower, MaxAllowedLagMs: 1000},
+		{Address: "spoke2", Role: config.ReplicationPeerFollower, MaxAllowedLagMs: 60000},
+		{Address: "spoke3", Role: config.ReplicationPeerObserver},
+	}}
+	cluster := mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	rep, err := NewReplicationModeManager(conf, store, cluster, newMockReplicator([]uint64{1}))
+	c.Assert(err, IsNil)
+
+	// A hub and three spokes.
+	for i, addr := range []string{"hub", "spoke1", "spoke2", "spoke3"} {
+		id := uint64(i + 1)
+		cluster.AddLabelsStore(id, 1, nil)
+		cluster.PutStore(cluster.GetStore(id).Clone(core.SetStoreAddress(addr, "", "")))
+	}
+	cluster.AddLeaderRegion(1, 1, 2, 3, 4)
+	regionSize := uint64(cluster.GetAverageRegionSize()) * units.MiB
+	setPendingPeers := func(id uint64, n int) {
+		cluster.PutStore(cluster.GetStore(id).Clone(core.SetPendingPeerCount(n)))
+	}
+	getPeerLag := func(addr string) PeerLag {
+		for _, lag := range rep.GetReplicationLag().Peers {
+			if lag.Address == addr {
+				return lag
+			}
+		}
+		c.Fatalf("no lag of %s", addr)
+		return PeerLag{}
+	}
+
+	rep.updatePeerLag()
+	c.Assert(rep.GetReplicationLag().Peers, HasLen, 3)
+	c.Assert(getPeerLag("spoke1"), DeepEquals, PeerLag{Address: "spoke1", Role: config.ReplicationPeerFollower, StoreID: 2, MaxAllowedLagMs: 1000})
+
+	// spoke1 and spoke2 have lagged for 10 seconds, spoke3 starts lagging.
+	setPendingPeers(2, 1)
+	setPendingPeers(3, 2)
+	rep.updatePeerLag()
+	rep.peerLagSince["spoke1"] = time.Now().Add(-10 * time.Second)
+	rep.peerLagSince["spoke2"] = time.Now().Add(-10 * time.Second)
+	setPendingPeers(4, 3)
+	rep.updatePeerLag()
+
+	spoke1, spoke2, spoke3 := getPeerLag("spoke1"), getPeerLag("spoke2"), getPeerLag("spoke3")
+	c.Assert(spoke1.LagBytes, Equals, regionSize)
+	c.Assert(spoke1.LagDurationMs, GreaterEqual, uint64(10000))
+	c.Assert(spoke1.Exceeded, IsTrue)
+	c.Assert(spoke2.LagBytes, Equals, 2*regionSize)
+	c.Assert(spoke2.LagDurationMs, GreaterEqual, uint64(10000))
+	c.Assert(spoke2.Exceeded, IsFalse)
+	c.Assert(spoke3.LagBytes, Equals, 3*regionSize)
+	c.Assert(spoke3.LagDurationMs, Less, uint64(10000))
+	c.Assert(spoke3.Exceeded, IsFalse)
+
+	// spoke1 catches up without affecting the others.
+	setPendingPeers(2, 0)
+	rep.updatePeerLag()
+	c.Assert(getPeerLag("spoke1").LagDurationMs, Equals, uint64(0))
+	c.Assert(getPeerLag("spoke1").Exceeded, IsFalse)
+	c.Assert(getPeerLag("spoke2").LagDurationMs, GreaterEqual, uint64(10000))
+	c.Assert(getPeerLag("spoke3").LagBytes, Equals, 3*regionSize)
+
+	// The peer without a store is reported as well.
+	c.Assert(rep.UpdateConfig(config.ReplicationModeConfig{ReplicationMode: modeMajority, ReplicationPeers: []config.ReplicationPeer{
+		{Address: "spoke4", Role: config.ReplicationPeerFollower},
+	}}), IsNil)
+	rep.updatePeerLag()
+	c.Assert(rep.GetReplicationLag().Peers, DeepEquals, []PeerLag{{Address: "spoke4", Role: config.ReplicationPeerFollower}})
+}
+
 func (s *testReplicationMode) setStoreState(cluster *mockcluster.Cluster, states ...string) {
 	for i, state := range states {
 		store := cluster.GetStore(uint64(i + 1))
@@ -0,0 +1,118 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/statistics"
+	"go.uber.org/zap"
+)
+
+// AccessFrequencySplitChecker splits the regions whose read or write QPS or
+// write byte rate is too high, even if they are not large, to distribute the
+// load.
+type AccessFrequencySplitChecker struct {
+	PauseController
+	cluster schedule.Cluster
+	opts    *config.PersistOptions
+}
+
+// NewAccessFrequencySplitChecker creates a new AccessFrequencySplitChecker.
+func NewAccessFrequencySplitChecker(cluster schedule.Cluster) *AccessFrequencySplitChecker {
+	return &AccessFrequencySplitChecker{
+		cluster: cluster,
+		opts:    cluster.GetOpts(),
+	}
+}
+
+// GetType returns the checker type.
+func (c *AccessFrequencySplitChecker) GetType() string {
+	return "access-frequency-split-checker"
+}
+
+// Check checks whether the region is too hot and returns an Operator to split it.
+func (c *AccessFrequencySplitChecker) Check(region *core.RegionInfo) *operator.Operator {
+	checkerCounter.WithLabelValues("access_split_checker", "check").Inc()
+
+	if c.IsPaused() {
+		checkerCounter.WithLabelValues("access_split_checker", "paused").Inc()
+		return nil
+	}
+
+	readThreshold, writeThreshold := c.opts.GetSplitQPSThreshold(), c.opts.GetSplitWriteQPSThreshold()
+	writeBytesThreshold := float64(c.opts.GetSplitWriteBytesThreshold())
+	if readThreshold <= 0 && writeThreshold <= 0 && writeBytesThreshold <= 0 {
+		return nil
+	}
+	// The merge checker does not merge the hot regions, but the halves will
+	// be merged back once they cool down if the region is too small.
+	if region.GetApproximateSize() <= int64(c.opts.GetMaxMergeRegionSize()) {
+		return nil
+	}
+
+	desc := ""
+	var load float64
+	if readThreshold > 0 {
+		if load = c.leaderLoad(region, c.cluster.RegionReadStats(), statistics.RegionReadQuery); load > readThreshold {
+			desc = "read-hot-split-region"
+		}
+	}
+	if desc == "" && (writeThreshold > 0 || writeBytesThreshold > 0) {
+		writeStats := c.cluster.RegionWriteStats()
+		if writeThreshold > 0 {
+			if load = c.leaderLoad(region, writeStats, statistics.RegionWriteQuery); load > writeThreshold {
+				desc = "write-hot-split-region"
+			}
+		}
+		// A few large values may generate much write traffic with a low QPS.
+		if desc == "" && writeBytesThreshold > 0 {
+			if load = c.leaderLoad(region, writeStats, statistics.RegionWriteBytes); load > writeBytesThreshold {
+				desc = "write-bytes-hot-split-region"
+			}
+		}
+	}
+	if desc == "" {
+		return nil
+	}
+
+	// PD does not know how the accesses are distributed inside the region, so
+	// TiKV is asked to split the region in the middle, the same as splitting a
+	// region by the load.
+	op, err := operator.CreateSplitRegionOperator(desc, region, 0, pdpb.CheckPolicy_APPROXIMATE, nil)
+	if err != nil {
+		log.Debug("create split region operator failed", errs.ZapError(err))
+		return nil
+	}
+	log.Debug("try to split the hot region", zap.Uint64("region-id", region.GetID()), zap.String("desc", desc), zap.Float64("load", load))
+	checkerCounter.WithLabelValues("access_split_checker", "new-operator").Inc()
+	return op
+}
+
+// leaderLoad returns the load of the region leader, the load is 0 if the leader
+// is not hot.
+func (c *AccessFrequencySplitChecker) leaderLoad(region *core.RegionInfo, stats map[uint64][]*statistics.HotPeerStat, kind statistics.RegionStatKind) float64 {
+	for _, stat := range stats[region.GetLeader().GetStoreId()] {
+		if stat.RegionID == region.GetID() {
+			return stat.GetLoad(kind)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/statistics"
+)
+
+var _ = Suite(&testAccessSplitCheckerSuite{})
+
+type testAccessSplitCheckerSuite struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cluster *mockcluster.Cluster
+	sc      *AccessFrequencySplitChecker
+}
+
+func (s *testAccessSplitCheckerSuite) SetUpTest(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.cluster = mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	s.cluster.SetHotRegionCacheHitsThreshold(0)
+	s.sc = NewAccessFrequencySplitChecker(s.cluster)
+	for id := uint64(1); id <= 3; id++ {
+		s.cluster.AddRegionStore(id, 10)
+	}
+}
+
+func (s *testAccessSplitCheckerSuite) TearDownTest(c *C) {
+	s.cancel()
+}
+
+// putRegionSize sets the regions large enough to split.
+func (s *testAccessSplitCheckerSuite) putRegionSize(regionIDs ...uint64) {
+	for _, id := range regionIDs {
+		s.cluster.PutRegion(s.cluster.GetRegion(id).Clone(core.SetApproximateSize(96)))
+	}
+}
+
+func (s *testAccessSplitCheckerSuite) checkSplit(c *C, op *operator.Operator, desc string) {
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, desc)
+	c.Assert(op.Kind(), Equals, operator.OpSplit)
+	c.Assert(op.Len(), Equals, 1)
+	step := op.Step(0).(operator.SplitRegion)
+	c.Assert(step.Policy, Equals, pdpb.CheckPolicy_APPROXIMATE)
+	c.Assert(step.SplitKeys, HasLen, 0)
+}
+
+func (s *testAccessSplitCheckerSuite) TestReadHotRegion(c *C) {
+	s.cluster.AddRegionWithReadInfo(1, 1, 512*KB*statistics.ReadReportInterval, 0,
+		1000*statistics.ReadReportInterval, statistics.ReadReportInterval, []uint64{2, 3})
+	s.cluster.AddRegionWithReadInfo(2, 1, 512*KB*statistics.ReadReportInterval, 0,
+		100*statistics.ReadReportInterval, statistics.ReadReportInterval, []uint64{2, 3})
+	s.cluster.AddLeaderRegion(3, 1, 2, 3)
+	s.putRegionSize(1, 2, 3)
+
+	// It is disabled by default.
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	s.cluster.SetSplitQPSThreshold(500)
+	s.checkSplit(c, s.sc.Check(s.cluster.GetRegion(1)), "read-hot-split-region")
+	c.Assert(s.sc.Check(s.cluster.GetRegion(2)), IsNil)
+	c.Assert(s.sc.Check(s.cluster.GetRegion(3)), IsNil)
+
+	// The small region is not split.
+	s.cluster.PutRegion(s.cluster.GetRegion(1).Clone(core.SetApproximateSize(10)))
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+}
+
+func (s *testAccessSplitCheckerSuite) TestWriteHotRegion(c *C) {
+	s.cluster.AddLeaderRegionWithWriteInfo(1, 1, 512*KB*statistics.WriteReportInterval, 0,
+		1000*statistics.WriteReportInterval, statistics.WriteReportInterval, []uint64{2, 3})
+	s.putRegionSize(1)
+
+	s.cluster.SetSplitQPSThreshold(500)
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	s.cluster.SetSplitWriteQPSThreshold(500)
+	s.checkSplit(c, s.sc.Check(s.cluster.GetRegion(1)), "write-hot-split-region")
+	s.cluster.SetSplitWriteQPSThreshold(2000)
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+}
+
+func (s *testAccessSplitCheckerSuite) TestWriteBytesHotRegion(c *C) {
+	// The region has a high write byte rate with few keys and queries.
+	s.cluster.AddLeaderRegionWithWriteInfo(1, 1, 2*MB*statistics.WriteReportInterval, 0,
+		10*statistics.WriteReportInterval, statistics.WriteReportInterval, []uint64{2, 3})
+	s.putRegionSize(1)
+
+	s.cluster.SetSplitWriteQPSThreshold(500)
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	s.cluster.SetSplitWriteBytesThreshold(1 * MB)
+	s.checkSplit(c, s.sc.Check(s.cluster.GetRegion(1)), "write-bytes-hot-split-region")
+	s.cluster.SetSplitWriteBytesThreshold(4 * MB)
+	c.Assert(s.sc.Check(s.cluster.GetRegion(1)), IsNil)
+}
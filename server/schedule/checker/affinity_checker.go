@@ -0,0 +1,104 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/keyspace"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/placement"
+	"go.uber.org/zap"
+)
+
+const affinityCheckerName = "keyspace-affinity-checker"
+
+// AffinityChecker moves the peers of the keyspace regions out of the stores
+// which do not have the labels required by the keyspace affinity.
+type AffinityChecker struct {
+	PauseController
+	cluster schedule.Cluster
+}
+
+// NewAffinityChecker creates a keyspace affinity checker.
+func NewAffinityChecker(cluster schedule.Cluster) *AffinityChecker {
+	return &AffinityChecker{
+		cluster: cluster,
+	}
+}
+
+// GetType return AffinityChecker's type.
+func (a *AffinityChecker) GetType() string {
+	return affinityCheckerName
+}
+
+// Check verifies the stores of a region, creating an Operator if need.
+func (a *AffinityChecker) Check(region *core.RegionInfo) *operator.Operator {
+	checkerCounter.WithLabelValues("affinity_checker", "check").Inc()
+	if a.IsPaused() {
+		checkerCounter.WithLabelValues("affinity_checker", "paused").Inc()
+		return nil
+	}
+	constraints := keyspace.GetRegionAffinityConstraints(a.cluster, region)
+	if len(constraints) == 0 {
+		return nil
+	}
+	for _, peer := range region.GetPeers() {
+		storeID := peer.GetStoreId()
+		store := a.cluster.GetStore(storeID)
+		if store == nil {
+			log.Warn("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", storeID))
+			return nil
+		}
+		if placement.MatchLabelConstraints(store, constraints) {
+			continue
+		}
+		strategy := &ReplicaStrategy{
+			checkerName:  affinityCheckerName,
+			cluster:      a.cluster,
+			region:       region,
+			extraFilters: []filter.Filter{filter.NewLabelConstaintFilter(affinityCheckerName, constraints)},
+		}
+		target := strategy.SelectStoreToFix(a.cluster.GetRegionStores(region), storeID)
+		if target == 0 {
+			checkerCounter.WithLabelValues("affinity_checker", "no-target-store").Inc()
+			continue
+		}
+		newPeer := &metapb.Peer{StoreId: target, Role: peer.GetRole()}
+		op, err := operator.CreateMovePeerOperator("move-to-affinity-store", a.cluster, region, operator.OpReplica, storeID, newPeer)
+		if err != nil {
+			log.Debug("fail to create move to affinity store operator", errs.ZapError(err))
+			checkerCounter.WithLabelValues("affinity_checker", "create-operator-fail").Inc()
+			continue
+		}
+		checkerCounter.WithLabelValues("affinity_checker", "new-operator").Inc()
+		return op
+	}
+	return nil
+}
+
+// affinityFilters returns the filters keeping the targets of the region in
+// the stores required by the keyspace affinity.
+func affinityFilters(cluster schedule.Cluster, scope string, region *core.RegionInfo) []filter.Filter {
+	constraints := keyspace.GetRegionAffinityConstraints(cluster, region)
+	if len(constraints) == 0 {
+		return nil
+	}
+	return []filter.Filter{filter.NewLabelConstaintFilter(scope, constraints)}
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/cache"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/storage/endpoint"
+)
+
+var _ = Suite(&testAffinityCheckerSuite{})
+
+type testAffinityCheckerSuite struct {
+	cluster *mockcluster.Cluster
+	ac      *AffinityChecker
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (s *testAffinityCheckerSuite) SetUpTest(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.cluster = mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	s.ac = NewAffinityChecker(s.cluster)
+	for id := uint64(1); id <= 3; id++ {
+		s.cluster.AddLabelsStore(id, 0, map[string]string{"disk": "hdd"})
+	}
+	s.cluster.AddLabelsStore(4, 0, map[string]string{"disk": "nvme"})
+	s.cluster.AddLabelsStore(5, 0, map[string]string{"disk": "nvme"})
+}
+
+func (s *testAffinityCheckerSuite) TearDownTest(c *C) {
+	s.cancel()
+}
+
+func newKeyspaceRegion(id uint64, keyspaceID uint32, storeIDs ...uint64) *core.RegionInfo {
+	prefix := []byte{'x', byte(keyspaceID >> 16), byte(keyspaceID >> 8), byte(keyspaceID)}
+	peers := make([]*metapb.Peer, 0, len(storeIDs))
+	for i, storeID := range storeIDs {
+		peers = append(peers, &metapb.Peer{Id: id*10 + uint64(i), StoreId: storeID})
+	}
+	return core.NewRegionInfo(
+		&metapb.Region{
+			Id:          id,
+			StartKey:    codec.EncodeBytes(append(prefix, 'a')),
+			EndKey:      codec.EncodeBytes(append(prefix, 'b')),
+			Peers:       peers,
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+		}, peers[0])
+}
+
+func (s *testAffinityCheckerSuite) TestMoveToAffinityStore(c *C) {
+	region := newKeyspaceRegion(1, 1, 1, 4, 5)
+	s.cluster.PutRegion(region)
+	c.Assert(s.ac.Check(region), IsNil)
+
+	err := s.cluster.GetKeyspaceAffinity().SetAffinity(&endpoint.KeyspaceAffinity{
+		KeyspaceID:          1,
+		RequiredStoreLabels: map[string]string{"disk": "nvme"},
+	})
+	c.Assert(err, IsNil)
+	// There is no other store with NVMe disks to move the peer on store 1 to.
+	c.Assert(s.ac.Check(region), IsNil)
+
+	s.cluster.AddLabelsStore(6, 0, map[string]string{"disk": "nvme"})
+	op := s.ac.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "move-to-affinity-store")
+	c.Assert(op.Kind()&operator.OpReplica, Not(Equals), operator.OpKind(0))
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(6))
+	c.Assert(op.Step(op.Len()-1).(operator.RemovePeer).FromStore, Equals, uint64(1))
+
+	// The regions of the other keyspaces are not restricted.
+	c.Assert(s.ac.Check(newKeyspaceRegion(2, 2, 1, 2, 3)), IsNil)
+	// The affinity is removed without any required label.
+	c.Assert(s.cluster.GetKeyspaceAffinity().SetAffinity(&endpoint.KeyspaceAffinity{KeyspaceID: 1}), IsNil)
+	c.Assert(s.ac.Check(region), IsNil)
+}
+
+func (s *testAffinityCheckerSuite) TestReplicaCheckerTarget(c *C) {
+	err := s.cluster.GetKeyspaceAffinity().SetAffinity(&endpoint.KeyspaceAffinity{
+		KeyspaceID:          1,
+		RequiredStoreLabels: map[string]string{"disk": "nvme"},
+	})
+	c.Assert(err, IsNil)
+	// The missing replica is only added to the stores with NVMe disks.
+	region := newKeyspaceRegion(1, 1, 4, 5)
+	s.cluster.PutRegion(region)
+	rc := NewReplicaChecker(s.cluster, cache.NewDefaultCache(10))
+	c.Assert(rc.Check(region), IsNil)
+	s.cluster.AddLabelsStore(6, 0, map[string]string{"disk": "nvme"})
+	op := rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(6))
+}
@@ -34,19 +34,22 @@ const DefaultCacheSize = 1000
 
 // Controller is used to manage all checkers.
 type Controller struct {
-	cluster           schedule.Cluster
-	opts              *config.PersistOptions
-	opController      *schedule.OperatorController
-	learnerChecker    *LearnerChecker
-	replicaChecker    *ReplicaChecker
-	ruleChecker       *RuleChecker
-	splitChecker      *SplitChecker
-	mergeChecker      *MergeChecker
-	jointStateChecker *JointStateChecker
-	priorityInspector *PriorityInspector
-	regionWaitingList cache.Cache
-	suspectRegions    *cache.TTLUint64 // suspectRegions are regions that may need fix
-	suspectKeyRanges  *cache.TTLString // suspect key-range regions that may need fix
+	cluster              schedule.Cluster
+	opts                 *config.PersistOptions
+	opController         *schedule.OperatorController
+	learnerChecker       *LearnerChecker
+	orphanLearnerChecker *OrphanLearnerChecker
+	affinityChecker      *AffinityChecker
+	replicaChecker       *ReplicaChecker
+	ruleChecker          *RuleChecker
+	splitChecker         *SplitChecker
+	accessSplitChecker   *AccessFrequencySplitChecker
+	mergeChecker         *MergeChecker
+	jointStateChecker    *JointStateChecker
+	priorityInspector    *PriorityInspector
+	regionWaitingList    cache.Cache
+	suspectRegions       *cache.TTLUint64 // suspectRegions are regions that may need fix
+	suspectKeyRanges     *cache.TTLString // suspect key-range regions that may need fix
 }
 
 // NewController create a new Controller.
@@ -54,19 +57,22 @@ type Controller struct {
 func NewController(ctx context.Context, cluster schedule.Cluster, ruleManager *placement.RuleManager, labeler *labeler.RegionLabeler, opController *schedule.OperatorController) *Controller {
 	regionWaitingList := cache.NewDefaultCache(DefaultCacheSize)
 	return &Controller{
-		cluster:           cluster,
-		opts:              cluster.GetOpts(),
-		opController:      opController,
-		learnerChecker:    NewLearnerChecker(cluster),
-		replicaChecker:    NewReplicaChecker(cluster, regionWaitingList),
-		ruleChecker:       NewRuleChecker(cluster, ruleManager, regionWaitingList),
-		splitChecker:      NewSplitChecker(cluster, ruleManager, labeler),
-		mergeChecker:      NewMergeChecker(ctx, cluster),
-		jointStateChecker: NewJointStateChecker(cluster),
-		priorityInspector: NewPriorityInspector(cluster),
-		regionWaitingList: regionWaitingList,
-		suspectRegions:    cache.NewIDTTL(ctx, time.Minute, 3*time.Minute),
-		suspectKeyRanges:  cache.NewStringTTL(ctx, time.Minute, 3*time.Minute),
+		cluster:              cluster,
+		opts:                 cluster.GetOpts(),
+		opController:         opController,
+		learnerChecker:       NewLearnerChecker(cluster),
+		orphanLearnerChecker: NewOrphanLearnerChecker(cluster),
+		affinityChecker:      NewAffinityChecker(cluster),
+		replicaChecker:       NewReplicaChecker(cluster, regionWaitingList),
+		ruleChecker:          NewRuleChecker(cluster, ruleManager, regionWaitingList),
+		splitChecker:         NewSplitChecker(cluster, ruleManager, labeler),
+		accessSplitChecker:   NewAccessFrequencySplitChecker(cluster),
+		mergeChecker:         NewMergeChecker(ctx, cluster),
+		jointStateChecker:    NewJointStateChecker(cluster),
+		priorityInspector:    NewPriorityInspector(cluster),
+		regionWaitingList:    regionWaitingList,
+		suspectRegions:       cache.NewIDTTL(ctx, time.Minute, 3*time.Minute),
+		suspectKeyRanges:     cache.NewStringTTL(ctx, time.Minute, 3*time.Minute),
 	}
 }
 
@@ -76,6 +82,8 @@ func (c *Controller) CheckRegion(region *core.RegionInfo) []*operator.Operator {
 	// Don't check isRaftLearnerEnabled cause it maybe disable learner feature but there are still some learners to promote.
 	opController := c.opController
 
+	// The region in the joint state cannot be changed by the other checkers
+	// until it leaves the joint state.
 	if op := c.jointStateChecker.Check(region); op != nil {
 		return []*operator.Operator{op}
 	}
@@ -87,10 +95,30 @@ func (c *Controller) CheckRegion(region *core.RegionInfo) []*operator.Operator {
 		}
 	}
 
+	// The orphan learners are removed before the other checkers try to
+	// promote or replace them, unless the region is being scheduled.
+	if opController.GetOperator(region.GetID()) == nil {
+		if op := c.orphanLearnerChecker.Check(region); op != nil {
+			return []*operator.Operator{op}
+		}
+	}
+
 	if op := c.splitChecker.Check(region); op != nil {
 		return []*operator.Operator{op}
 	}
 
+	if op := c.accessSplitChecker.Check(region); op != nil {
+		return []*operator.Operator{op}
+	}
+
+	if op := c.affinityChecker.Check(region); op != nil {
+		if opController.OperatorCount(operator.OpReplica) < c.opts.GetReplicaScheduleLimit() {
+			return []*operator.Operator{op}
+		}
+		operator.OperatorLimitCounter.WithLabelValues(c.affinityChecker.GetType(), operator.OpReplica.String()).Inc()
+		c.regionWaitingList.Put(region.GetID(), nil)
+	}
+
 	if c.opts.IsPlacementRulesEnabled() {
 		fit := c.priorityInspector.Inspect(region)
 		if op := c.ruleChecker.CheckWithFit(region, fit); op != nil {
@@ -209,12 +237,18 @@ func (c *Controller) GetPauseController(name string) (*PauseController, error) {
 	switch name {
 	case "learner":
 		return &c.learnerChecker.PauseController, nil
+	case "orphan-learner":
+		return &c.orphanLearnerChecker.PauseController, nil
+	case "keyspace-affinity":
+		return &c.affinityChecker.PauseController, nil
 	case "replica":
 		return &c.replicaChecker.PauseController, nil
 	case "rule":
 		return &c.ruleChecker.PauseController, nil
 	case "split":
 		return &c.splitChecker.PauseController, nil
+	case "access-split":
+		return &c.accessSplitChecker.PauseController, nil
 	case "merge":
 		return &c.mergeChecker.PauseController, nil
 	case "joint-state":
@@ -42,7 +42,7 @@ func (c *JointStateChecker) Check(region *core.RegionInfo) *operator.Operator {
 		checkerCounter.WithLabelValues("joint_state_checker", "paused").Inc()
 		return nil
 	}
-	if !core.IsInJointState(region.GetPeers()...) {
+	if !region.InJointState() {
 		return nil
 	}
 	op, err := operator.CreateLeaveJointStateOperator(operator.OpDescLeaveJointState, c.cluster, region)
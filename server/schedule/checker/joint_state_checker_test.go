@@ -22,6 +22,8 @@ import (
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
 )
 
@@ -135,6 +137,45 @@ func (s *testJointStateCheckerSuite) TestLeaveJointState(c *C) {
 	}
 }
 
+func (s *testJointStateCheckerSuite) TestResolveStuckRegion(c *C) {
+	stream := hbstream.NewTestHeartbeatStreams(s.ctx, s.cluster.ID, s.cluster, false /* no need to run */)
+	opController := schedule.NewOperatorController(s.ctx, s.cluster, stream)
+	controller := NewController(s.ctx, s.cluster, s.cluster.GetRuleManager(), s.cluster.GetRegionLabeler(), opController)
+	peers := []*metapb.Peer{
+		{Id: 101, StoreId: 1, Role: metapb.PeerRole_Voter},
+		{Id: 102, StoreId: 2, Role: metapb.PeerRole_Voter},
+		{Id: 103, StoreId: 3, Role: metapb.PeerRole_IncomingVoter},
+		{Id: 104, StoreId: 4, Role: metapb.PeerRole_DemotingVoter},
+	}
+	// The region is stuck in the joint configuration, e.g. the PD leader
+	// changes before the region leaves it.
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	c.Assert(region.InJointState(), IsTrue)
+	s.cluster.PutRegion(region)
+
+	// It is resolved within one check cycle, no matter whether the other
+	// checkers want to change the region.
+	ops := controller.CheckRegion(region)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].Desc(), Equals, operator.OpDescLeaveJointState)
+	c.Assert(ops[0].GetPriorityLevel(), Equals, core.HighPriority)
+	c.Assert(opController.AddOperator(ops...), IsTrue)
+
+	leftPeers := []*metapb.Peer{
+		peers[0],
+		peers[1],
+		{Id: 103, StoreId: 3, Role: metapb.PeerRole_Voter},
+		{Id: 104, StoreId: 4, Role: metapb.PeerRole_Learner},
+	}
+	left := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: leftPeers}, leftPeers[0])
+	c.Assert(left.InJointState(), IsFalse)
+	c.Assert(ops[0].Check(left), IsNil)
+	c.Assert(ops[0].Status(), Equals, operator.SUCCESS)
+	for _, op := range controller.CheckRegion(left) {
+		c.Assert(op.Desc(), Not(Equals), operator.OpDescLeaveJointState)
+	}
+}
+
 func (s *testJointStateCheckerSuite) checkSteps(c *C, op *operator.Operator, steps []operator.OpStep) {
 	if len(steps) == 0 {
 		c.Assert(op, IsNil)
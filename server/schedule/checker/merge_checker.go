@@ -30,6 +30,7 @@ import (
 	"github.com/tikv/pd/server/schedule/labeler"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/placement"
+	"go.uber.org/zap"
 )
 
 const (
@@ -109,8 +110,7 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	}
 
 	// region is not small enough
-	if region.GetApproximateSize() > int64(m.opts.GetMaxMergeRegionSize()) ||
-		region.GetApproximateKeys() > int64(m.opts.GetMaxMergeRegionKeys()) {
+	if !m.isSmallRegion(region) {
 		checkerCounter.WithLabelValues("merge_checker", "no-need").Inc()
 		return nil
 	}
@@ -175,6 +175,26 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	return ops
 }
 
+// isSmallRegion checks if the region is small enough to be merged, both the
+// size and the keys of the region must not exceed the thresholds.
+func (m *MergeChecker) isSmallRegion(region *core.RegionInfo) bool {
+	maxSize, maxKeys := m.opts.GetMaxMergeRegionSize(), m.opts.GetMaxMergeRegionKeys()
+	sizeExceeded := region.GetApproximateSize() > int64(maxSize)
+	keysExceeded := region.GetApproximateKeys() > int64(maxKeys)
+	if sizeExceeded || keysExceeded {
+		log.Debug("region is too large to merge",
+			zap.Uint64("region-id", region.GetID()),
+			zap.Int64("size", region.GetApproximateSize()),
+			zap.Bool("size-exceeded", sizeExceeded),
+			zap.Uint64("max-merge-region-size", maxSize),
+			zap.Int64("keys", region.GetApproximateKeys()),
+			zap.Bool("keys-exceeded", keysExceeded),
+			zap.Uint64("max-merge-region-keys", maxKeys))
+		return false
+	}
+	return true
+}
+
 func (m *MergeChecker) checkTarget(region, adjacent *core.RegionInfo) bool {
 	if adjacent == nil {
 		checkerCounter.WithLabelValues("merge_checker", "adj-not-exist").Inc()
@@ -268,7 +288,7 @@ func isTableIDSame(region, adjacent *core.RegionInfo) bool {
 	return codec.Key(region.GetStartKey()).TableID() == codec.Key(adjacent.GetStartKey()).TableID()
 }
 
-// Check whether there is a peer of the adjacent region on an offline store,
+// Check whether there is a peer of the adjacent region on an offline or draining store,
 // while the source region has no peer on it. This is to prevent from bringing
 // any other peer into an offline store to slow down the offline process.
 func checkPeerStore(cluster schedule.Cluster, region, adjacent *core.RegionInfo) bool {
@@ -276,7 +296,7 @@ func checkPeerStore(cluster schedule.Cluster, region, adjacent *core.RegionInfo)
 	for _, peer := range adjacent.GetPeers() {
 		storeID := peer.GetStoreId()
 		store := cluster.GetStore(storeID)
-		if store == nil || store.IsRemoving() {
+		if store == nil || store.IsRemoving() || store.IsDraining() {
 			if _, ok := regionStoreIDs[storeID]; !ok {
 				return false
 			}
@@ -253,6 +253,34 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	c.Assert(ops, NotNil)
 }
 
+func (s *testMergeCheckerSuite) TestSizeAndKeysThreshold(c *C) {
+	s.cluster.SetSplitMergeInterval(0)
+	s.cluster.SetMaxMergeRegionSize(10)
+	s.cluster.SetMaxMergeRegionKeys(100)
+	testCases := []struct {
+		size      int64
+		keys      int64
+		mergeable bool
+	}{
+		{10, 100, true},
+		{10, 101, false},
+		{11, 100, false},
+		{11, 101, false},
+	}
+	for _, t := range testCases {
+		region := s.regions[2].Clone(core.SetApproximateSize(t.size), core.SetApproximateKeys(t.keys))
+		s.cluster.PutRegion(region)
+		c.Assert(s.mc.isSmallRegion(region), Equals, t.mergeable)
+		ops := s.mc.Check(region)
+		if t.mergeable {
+			c.Assert(ops, HasLen, 2)
+			c.Assert(ops[0].RegionID(), Equals, region.GetID())
+		} else {
+			c.Assert(ops, IsNil)
+		}
+	}
+}
+
 func (s *testMergeCheckerSuite) checkSteps(c *C, op *operator.Operator, steps []operator.OpStep) {
 	c.Assert(op.Kind()&operator.OpMerge, Not(Equals), 0)
 	c.Assert(steps, NotNil)
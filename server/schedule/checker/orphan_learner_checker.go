@@ -0,0 +1,85 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/operator"
+	"go.uber.org/zap"
+)
+
+// OrphanLearnerChecker removes the learners left on the stores which have
+// been tombstoned or down for a long time. These learners can never catch up,
+// so they are neither promoted nor removed by the other checkers.
+type OrphanLearnerChecker struct {
+	PauseController
+	cluster schedule.Cluster
+	opts    *config.PersistOptions
+}
+
+// NewOrphanLearnerChecker creates an orphan learner checker.
+func NewOrphanLearnerChecker(cluster schedule.Cluster) *OrphanLearnerChecker {
+	return &OrphanLearnerChecker{
+		cluster: cluster,
+		opts:    cluster.GetOpts(),
+	}
+}
+
+// GetType return OrphanLearnerChecker's type.
+func (o *OrphanLearnerChecker) GetType() string {
+	return "orphan-learner-checker"
+}
+
+// Check verifies the learners of a region, creating an Operator if need.
+func (o *OrphanLearnerChecker) Check(region *core.RegionInfo) *operator.Operator {
+	checkerCounter.WithLabelValues("orphan_learner_checker", "check").Inc()
+	if o.IsPaused() {
+		checkerCounter.WithLabelValues("orphan_learner_checker", "paused").Inc()
+		return nil
+	}
+	if !o.opts.IsRemoveOrphanLearnerEnabled() {
+		return nil
+	}
+	// The learners of the region in the joint state are handled by the joint
+	// state checker.
+	if region.InJointState() {
+		return nil
+	}
+	for _, learner := range region.GetLearners() {
+		storeID := learner.GetStoreId()
+		store := o.cluster.GetStore(storeID)
+		if store == nil {
+			log.Warn("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", storeID))
+			return nil
+		}
+		if !store.IsRemoved() && store.DownTime() < o.opts.GetMaxStoreDownTime() {
+			continue
+		}
+		op, err := operator.CreateRemovePeerOperator("remove-orphan-learner", o.cluster, operator.OpReplica, region, storeID)
+		if err != nil {
+			log.Debug("fail to create remove orphan learner operator", errs.ZapError(err))
+			checkerCounter.WithLabelValues("orphan_learner_checker", "create-operator-fail").Inc()
+			continue
+		}
+		checkerCounter.WithLabelValues("orphan_learner_checker", "new-operator").Inc()
+		op.SetPriorityLevel(core.HighPriority)
+		return op
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/hbstream"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/versioninfo"
+)
+
+var _ = Suite(&testOrphanLearnerCheckerSuite{})
+
+type testOrphanLearnerCheckerSuite struct {
+	cluster *mockcluster.Cluster
+	oc      *OrphanLearnerChecker
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (s *testOrphanLearnerCheckerSuite) SetUpTest(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.cluster = mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	s.cluster.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	s.oc = NewOrphanLearnerChecker(s.cluster)
+	for id := uint64(1); id <= 5; id++ {
+		s.cluster.PutStoreWithLabels(id)
+	}
+}
+
+func (s *testOrphanLearnerCheckerSuite) TearDownTest(c *C) {
+	s.cancel()
+}
+
+func newOrphanLearnerRegion(id uint64) *core.RegionInfo {
+	return core.NewRegionInfo(
+		&metapb.Region{
+			Id: id,
+			Peers: []*metapb.Peer{
+				{Id: id*10 + 1, StoreId: 1},
+				{Id: id*10 + 2, StoreId: 2},
+				{Id: id*10 + 3, StoreId: 3},
+				{Id: id*10 + 4, StoreId: 4, Role: metapb.PeerRole_Learner},
+			},
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+		}, &metapb.Peer{Id: id*10 + 1, StoreId: 1})
+}
+
+func (s *testOrphanLearnerCheckerSuite) TestTombstoneStore(c *C) {
+	region := newOrphanLearnerRegion(1)
+	c.Assert(s.oc.Check(region), IsNil)
+
+	s.cluster.PutStore(s.cluster.GetStore(4).Clone(core.TombstoneStore()))
+	op := s.oc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-orphan-learner")
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+	testutil.CheckRemovePeer(c, op, 4)
+
+	// The voters on the tombstone store are left to the replica checker.
+	region = region.Clone(core.WithRemoveStorePeer(4))
+	s.cluster.PutStore(s.cluster.GetStore(3).Clone(core.TombstoneStore()))
+	c.Assert(s.oc.Check(region), IsNil)
+
+	s.cluster.SetEnableRemoveOrphanLearner(false)
+	c.Assert(s.oc.Check(newOrphanLearnerRegion(2)), IsNil)
+}
+
+func (s *testOrphanLearnerCheckerSuite) TestDownStore(c *C) {
+	region := newOrphanLearnerRegion(1)
+	s.cluster.SetStoreDisconnect(4)
+	c.Assert(s.oc.Check(region), IsNil)
+
+	s.cluster.SetStoreDown(4)
+	testutil.CheckRemovePeer(c, s.oc.Check(region), 4)
+}
+
+func (s *testOrphanLearnerCheckerSuite) TestSkipScheduling(c *C) {
+	s.cluster.PutStore(s.cluster.GetStore(4).Clone(core.TombstoneStore()))
+	s.cluster.PutRegion(newOrphanLearnerRegion(1))
+	region := s.cluster.GetRegion(1)
+
+	stream := hbstream.NewTestHeartbeatStreams(s.ctx, s.cluster.ID, s.cluster, false)
+	opController := schedule.NewOperatorController(s.ctx, s.cluster, stream)
+	controller := NewController(s.ctx, s.cluster, s.cluster.GetRuleManager(), s.cluster.GetRegionLabeler(), opController)
+
+	// The region undergoing the other operator is skipped.
+	op := operator.NewOperator("test", "test", 1, region.GetRegionEpoch(), operator.OpLeader, 0,
+		operator.TransferLeader{FromStore: 1, ToStore: 2})
+	c.Assert(opController.AddOperator(op), IsTrue)
+	for _, op := range controller.CheckRegion(region) {
+		c.Assert(op.Desc(), Not(Equals), "remove-orphan-learner")
+	}
+
+	c.Assert(opController.RemoveOperator(op), IsTrue)
+	ops := controller.CheckRegion(region)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].Desc(), Equals, "remove-orphan-learner")
+}
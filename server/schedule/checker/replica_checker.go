@@ -16,6 +16,8 @@ package checker
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
@@ -24,7 +26,9 @@ import (
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/placement"
 	"go.uber.org/zap"
 )
 
@@ -33,8 +37,10 @@ const (
 )
 
 const (
-	offlineStatus = "offline"
-	downStatus    = "down"
+	offlineStatus       = "offline"
+	downStatus          = "down"
+	drainingStatus      = "draining"
+	criticalSpaceStatus = "critical-space"
 )
 
 // ReplicaChecker ensures region has the best replicas.
@@ -85,10 +91,23 @@ func (r *ReplicaChecker) Check(region *core.RegionInfo) *operator.Operator {
 		op.SetPriorityLevel(core.HighPriority)
 		return op
 	}
+	if op := r.checkDrainingPeer(region); op != nil {
+		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		return op
+	}
+	if op := r.checkCriticalSpacePeer(region); op != nil {
+		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		op.SetPriorityLevel(core.HighPriority)
+		return op
+	}
 	if op := r.checkRemoveExtraReplica(region); op != nil {
 		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
 		return op
 	}
+	if op := r.checkRackIsolation(region); op != nil {
+		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		return op
+	}
 	if op := r.checkLocationReplacement(region); op != nil {
 		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
 		return op
@@ -148,6 +167,41 @@ func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo) *operator.Ope
 	return nil
 }
 
+// checkDrainingPeer moves the peers away from the draining stores. It is not
+// controlled by the replace offline replica switch since draining is
+// requested explicitly.
+func (r *ReplicaChecker) checkDrainingPeer(region *core.RegionInfo) *operator.Operator {
+	// just skip learner
+	if len(region.GetLearners()) != 0 {
+		return nil
+	}
+
+	for _, peer := range region.GetPeers() {
+		store := r.cluster.GetStore(peer.GetStoreId())
+		if store != nil && store.IsDraining() {
+			return r.fixPeer(region, store.GetID(), drainingStatus)
+		}
+	}
+	return nil
+}
+
+// checkCriticalSpacePeer moves the peers away from the stores which are
+// running out of space.
+func (r *ReplicaChecker) checkCriticalSpacePeer(region *core.RegionInfo) *operator.Operator {
+	// just skip learner
+	if len(region.GetLearners()) != 0 {
+		return nil
+	}
+
+	for _, peer := range region.GetPeers() {
+		store := r.cluster.GetStore(peer.GetStoreId())
+		if store != nil && store.IsCriticalSpace(r.opts.GetCriticalSpaceRatio(), r.opts.GetCompactionReservedSpace()) {
+			return r.fixPeer(region, store.GetID(), criticalSpaceStatus)
+		}
+	}
+	return nil
+}
+
 func (r *ReplicaChecker) checkMakeUpReplica(region *core.RegionInfo) *operator.Operator {
 	if !r.opts.IsMakeUpReplicaEnabled() {
 		return nil
@@ -226,6 +280,91 @@ func (r *ReplicaChecker) checkLocationReplacement(region *core.RegionInfo) *oper
 	return op
 }
 
+// checkRackIsolation moves a replica sharing the rack with other replicas to
+// a rack without any replica of the region, as long as the location placement
+// is not worse after moving.
+func (r *ReplicaChecker) checkRackIsolation(region *core.RegionInfo) *operator.Operator {
+	if !r.opts.IsRackIsolationEnabled() {
+		return nil
+	}
+	rackLabels := r.rackLabels()
+	if len(rackLabels) == 0 {
+		return nil
+	}
+	rackKey := rackLabels[len(rackLabels)-1]
+
+	regionStores := r.cluster.GetRegionStores(region)
+	sort.Slice(regionStores, func(i, j int) bool { return regionStores[i].GetID() < regionStores[j].GetID() })
+	racks := make(map[string]int)
+	for _, store := range regionStores {
+		if rack := rackOf(store, rackLabels); rack != "" {
+			racks[rack]++
+		}
+	}
+
+	strategy := r.strategy(region)
+	for _, store := range regionStores {
+		// The leader is not moved to avoid transferring the leader.
+		if racks[rackOf(store, rackLabels)] < 2 || store.GetID() == region.GetLeader().GetStoreId() {
+			continue
+		}
+		others := make([]*core.StoreInfo, 0, len(regionStores)-1)
+		for _, s := range regionStores {
+			if s.GetID() != store.GetID() {
+				others = append(others, s)
+			}
+		}
+		target := strategy.SelectStoreToAdd(others,
+			filter.NewLabelConstaintFilter(replicaCheckerName, []placement.LabelConstraint{{Key: rackKey, Op: placement.Exists}}),
+			filter.NewIsolationFilter(replicaCheckerName, rackKey, rackLabels, others),
+			filter.NewLocationSafeguard(replicaCheckerName, strategy.locationLabels, regionStores, store),
+		)
+		if target == 0 {
+			continue
+		}
+		newPeer := &metapb.Peer{StoreId: target}
+		op, err := operator.CreateMovePeerOperator("move-to-different-rack", r.cluster, region, operator.OpReplica, store.GetID(), newPeer)
+		if err != nil {
+			checkerCounter.WithLabelValues("replica_checker", "create-operator-fail").Inc()
+			return nil
+		}
+		return op
+	}
+	checkerCounter.WithLabelValues("replica_checker", "no-rack-to-isolate").Inc()
+	return nil
+}
+
+// rackLabels returns the labels identifying a rack. If the rack label is one
+// of the location labels, the rack is identified by the labels from the top
+// level to the rack, since the racks of different zones may have the same
+// name.
+func (r *ReplicaChecker) rackLabels() []string {
+	rackKey := r.opts.GetRackLabelKey()
+	if rackKey == "" {
+		return nil
+	}
+	locationLabels := r.opts.GetLocationLabels()
+	for i, label := range locationLabels {
+		if label == rackKey {
+			return locationLabels[:i+1]
+		}
+	}
+	return []string{rackKey}
+}
+
+// rackOf returns the rack of the store, it returns an empty string if the
+// store does not have the rack label.
+func rackOf(store *core.StoreInfo, rackLabels []string) string {
+	if store.GetLabelValue(rackLabels[len(rackLabels)-1]) == "" {
+		return ""
+	}
+	values := make([]string, 0, len(rackLabels))
+	for _, label := range rackLabels {
+		values = append(values, store.GetLabelValue(label))
+	}
+	return strings.Join(values, "/")
+}
+
 func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status string) *operator.Operator {
 	// Check the number of replicas first.
 	if len(region.GetVoters()) > r.opts.GetMaxReplicas() {
@@ -266,5 +405,6 @@ func (r *ReplicaChecker) strategy(region *core.RegionInfo) *ReplicaStrategy {
 		locationLabels: r.opts.GetLocationLabels(),
 		isolationLevel: r.opts.GetIsolationLevel(),
 		region:         region,
+		extraFilters:   affinityFilters(r.cluster, replicaCheckerName, region),
 	}
 }
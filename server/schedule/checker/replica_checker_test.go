@@ -16,6 +16,7 @@ package checker
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -26,6 +27,7 @@ import (
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/versioninfo"
 )
@@ -459,6 +461,73 @@ func (s *testReplicaCheckerSuite) TestDistinctScore2(c *C) {
 	c.Assert(rc.Check(region), IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestRackIsolation(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetLocationLabels([]string{"zone", "host"})
+
+	rc := NewReplicaChecker(tc, cache.NewDefaultCache(10))
+
+	// The racks are not a part of the location labels.
+	racks := map[uint64]string{1: "r1", 2: "r1", 3: "r2", 4: "r2", 5: "r3", 6: "r3"}
+	for storeID := uint64(1); storeID <= 6; storeID++ {
+		tc.AddLabelsStore(storeID, 1, map[string]string{
+			"zone": "z1",
+			"rack": racks[storeID],
+			"host": fmt.Sprintf("h%d", storeID),
+		})
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	region := tc.GetRegion(1)
+
+	// The replicas share the rack if the rack isolation is disabled.
+	c.Assert(rc.Check(region), IsNil)
+
+	tc.SetEnableRackIsolation(true)
+	op := rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "move-to-different-rack")
+	target := op.Step(0).(operator.AddLearner).ToStore
+	c.Assert(racks[target], Equals, "r3")
+	testutil.CheckTransferPeer(c, op, operator.OpReplica, 2, target)
+	peer, _ := tc.AllocPeer(target)
+	region = region.Clone(core.WithAddPeer(peer), core.WithRemoveStorePeer(2))
+
+	usedRacks := make(map[string]struct{})
+	for _, p := range region.GetPeers() {
+		usedRacks[racks[p.GetStoreId()]] = struct{}{}
+	}
+	c.Assert(usedRacks, HasLen, 3)
+	c.Assert(rc.Check(region), IsNil)
+
+	// The replicas cannot be moved if there is no rack without replicas.
+	tc.SetMaxReplicas(4)
+	tc.AddLeaderRegion(2, 1, 2, 3, 5)
+	c.Assert(rc.Check(tc.GetRegion(2)), IsNil)
+
+	// The racks of different zones are different even if they have the same
+	// name when the rack is a location label.
+	tc.SetMaxReplicas(3)
+	tc.SetLocationLabels([]string{"zone", "rack", "host"})
+	for storeID := uint64(7); storeID <= 8; storeID++ {
+		tc.AddLabelsStore(storeID, 1, map[string]string{
+			"zone": "z2",
+			"rack": "r1",
+			"host": fmt.Sprintf("h%d", storeID),
+		})
+	}
+	tc.AddLeaderRegion(3, 1, 3, 7)
+	c.Assert(rc.Check(tc.GetRegion(3)), IsNil)
+	// The zone isolation is preferred.
+	tc.AddLeaderRegion(4, 1, 3, 4)
+	op = rc.Check(tc.GetRegion(4))
+	c.Assert(op, NotNil)
+	target = op.Step(0).(operator.AddLearner).ToStore
+	c.Assert(target == 7 || target == 8, IsTrue)
+	testutil.CheckTransferPeer(c, op, operator.OpReplica, 3, target)
+}
+
 func (s *testReplicaCheckerSuite) TestStorageThreshold(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(s.ctx, opt)
@@ -583,3 +652,76 @@ func (s *testReplicaCheckerSuite) TestFixOfflinePeer(c *C) {
 	tc.SetIsolationLevel("zone")
 	c.Assert(rc.Check(region), IsNil)
 }
+
+func (s *testReplicaCheckerSuite) TestDrainStore(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	rc := NewReplicaChecker(tc, cache.NewDefaultCache(10))
+
+	for id := uint64(1); id <= 6; id++ {
+		tc.AddRegionStore(id, 0)
+	}
+	for id := uint64(1); id <= 12; id++ {
+		stores := []uint64{(id-1)%6 + 1, id%6 + 1, (id+1)%6 + 1}
+		tc.AddLeaderRegion(id, stores[0], stores[1:]...)
+	}
+	c.Assert(tc.GetStoreRegionCount(1), Equals, 6)
+	c.Assert(tc.GetStoreLeaderCount(1), Equals, 2)
+
+	tc.PutStore(tc.GetStore(1).Clone(core.SetDrainStartTime(time.Now())))
+	for i := 0; i < 100 && tc.GetStoreRegionCount(1) > 0; i++ {
+		for _, region := range tc.GetRegions() {
+			if op := rc.Check(region); op != nil {
+				c.Assert(op.Desc(), Equals, "replace-draining-replica")
+				schedule.ApplyOperator(tc, op)
+			}
+		}
+	}
+	c.Assert(tc.GetStoreRegionCount(1), Equals, 0)
+	c.Assert(tc.GetStoreLeaderCount(1), Equals, 0)
+	// No region loses its replicas after draining.
+	for _, region := range tc.GetRegions() {
+		c.Assert(region.GetVoters(), HasLen, 3)
+		c.Assert(region.GetLeader(), NotNil)
+		c.Assert(region.GetStorePeer(1), IsNil)
+	}
+}
+
+func (s *testReplicaCheckerSuite) TestCriticalSpace(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	rc := NewReplicaChecker(tc, cache.NewDefaultCache(10))
+
+	for id := uint64(1); id <= 4; id++ {
+		tc.AddRegionStore(id, 1)
+		tc.UpdateStorageRatio(id, 0.1, 0.9)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 2, 1, 3)
+
+	// The store in low space is not evacuated.
+	tc.UpdateStorageRatio(1, 0.85, 0.15)
+	c.Assert(rc.Check(tc.GetRegion(1)), IsNil)
+	c.Assert(rc.Check(tc.GetRegion(2)), IsNil)
+
+	// The store at 95% disk usage is evacuated aggressively.
+	tc.UpdateStorageRatio(1, 0.95, 0.05)
+	op := rc.Check(tc.GetRegion(1))
+	testutil.CheckTransferPeerWithLeaderTransfer(c, op, operator.OpReplica, 1, 4)
+	c.Assert(op.Desc(), Equals, "replace-critical-space-replica")
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+	op = rc.Check(tc.GetRegion(2))
+	testutil.CheckTransferPeer(c, op, operator.OpReplica, 1, 4)
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+
+	// The space reserved for compaction is regarded as used.
+	tc.UpdateStorageRatio(1, 0.85, 0.15)
+	tc.SetCompactionReservedSpace(10 * (1 << 30))
+	testutil.CheckTransferPeer(c, rc.Check(tc.GetRegion(2)), operator.OpReplica, 1, 4)
+
+	// No peer is moved if all the other stores are out of space as well.
+	tc.UpdateStorageRatio(4, 0.95, 0.05)
+	c.Assert(rc.Check(tc.GetRegion(2)), IsNil)
+}
@@ -137,6 +137,14 @@ func (c *RuleChecker) fixRulePeer(region *core.RegionInfo, fit *placement.Region
 			checkerCounter.WithLabelValues("rule_checker", "replace-offline").Inc()
 			return c.replaceUnexpectRulePeer(region, rf, fit, peer, offlineStatus)
 		}
+		if c.isDrainingPeer(peer) {
+			checkerCounter.WithLabelValues("rule_checker", "replace-draining").Inc()
+			return c.replaceUnexpectRulePeer(region, rf, fit, peer, drainingStatus)
+		}
+		if c.isCriticalSpacePeer(peer) {
+			checkerCounter.WithLabelValues("rule_checker", "replace-critical-space").Inc()
+			return c.replaceUnexpectRulePeer(region, rf, fit, peer, criticalSpaceStatus)
+		}
 	}
 	// fix loose matched peers.
 	for _, peer := range rf.PeersWithDifferentRole {
@@ -350,6 +358,17 @@ func (c *RuleChecker) isOfflinePeer(peer *metapb.Peer) bool {
 	return !store.IsPreparing() && !store.IsServing()
 }
 
+func (c *RuleChecker) isDrainingPeer(peer *metapb.Peer) bool {
+	store := c.cluster.GetStore(peer.GetStoreId())
+	return store != nil && store.IsDraining()
+}
+
+func (c *RuleChecker) isCriticalSpacePeer(peer *metapb.Peer) bool {
+	store := c.cluster.GetStore(peer.GetStoreId())
+	opts := c.cluster.GetOpts()
+	return store != nil && store.IsCriticalSpace(opts.GetCriticalSpaceRatio(), opts.GetCompactionReservedSpace())
+}
+
 func (c *RuleChecker) strategy(region *core.RegionInfo, rule *placement.Rule) *ReplicaStrategy {
 	return &ReplicaStrategy{
 		checkerName:    c.name,
@@ -357,7 +376,7 @@ func (c *RuleChecker) strategy(region *core.RegionInfo, rule *placement.Rule) *R
 		isolationLevel: rule.IsolationLevel,
 		locationLabels: rule.LocationLabels,
 		region:         region,
-		extraFilters:   []filter.Filter{filter.NewLabelConstaintFilter(c.name, rule.LabelConstraints)},
+		extraFilters:   append([]filter.Filter{filter.NewLabelConstaintFilter(c.name, rule.LabelConstraints)}, affinityFilters(c.cluster, c.name, region)...),
 	}
 }
 
@@ -16,6 +16,7 @@ package checker
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
@@ -820,3 +821,20 @@ func (s *testRuleCheckerSuite) TestOfflineAndDownStore(c *C) {
 	c.Assert(op, NotNil)
 	c.Assert(op.Desc(), Equals, "replace-rule-down-peer")
 }
+
+func (s *testRuleCheckerSuite) TestFixCriticalSpacePeer(c *C) {
+	for id := uint64(1); id <= 4; id++ {
+		s.cluster.AddLabelsStore(id, 1, map[string]string{"zone": fmt.Sprintf("z%d", id)})
+		s.cluster.UpdateStorageRatio(id, 0.1, 0.9)
+	}
+	s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	region := s.cluster.GetRegion(1)
+	c.Assert(s.rc.Check(region), IsNil)
+
+	s.cluster.UpdateStorageRatio(2, 0.95, 0.05)
+	op := s.rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "replace-rule-critical-space-peer")
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+}
@@ -162,6 +162,31 @@ func (f *excludedFilter) Target(opt *config.PersistOptions, store *core.StoreInf
 	return !ok
 }
 
+type warmUpFilter struct{ scope string }
+
+// NewWarmUpFilter creates a Filter that filters all warming-up stores as the
+// source. Their region scores are raised during the warm-up, which should not
+// move the regions away from them.
+func NewWarmUpFilter(scope string) Filter {
+	return &warmUpFilter{scope: scope}
+}
+
+func (f *warmUpFilter) Scope() string {
+	return f.scope
+}
+
+func (f *warmUpFilter) Type() string {
+	return "warm-up-filter"
+}
+
+func (f *warmUpFilter) Source(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !store.IsWarmingUp()
+}
+
+func (f *warmUpFilter) Target(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return true
+}
+
 type storageThresholdFilter struct{ scope string }
 
 // NewStorageThresholdFilter creates a Filter that filters all stores that are
@@ -309,6 +334,11 @@ func (f *StoreStateFilter) isRemoving(opt *config.PersistOptions, store *core.St
 	return store.IsRemoving()
 }
 
+func (f *StoreStateFilter) isDraining(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	f.Reason = "draining"
+	return store.IsDraining()
+}
+
 func (f *StoreStateFilter) pauseLeaderTransfer(opt *config.PersistOptions, store *core.StoreInfo) bool {
 	f.Reason = "pause-leader"
 	return !store.AllowLeaderTransfer()
@@ -319,6 +349,11 @@ func (f *StoreStateFilter) slowStoreEvicted(opt *config.PersistOptions, store *c
 	return store.EvictedAsSlowStore()
 }
 
+func (f *StoreStateFilter) isLatencySlow(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	f.Reason = "slow-latency"
+	return store.IsLatencySlow()
+}
+
 func (f *StoreStateFilter) isDisconnected(opt *config.PersistOptions, store *core.StoreInfo) bool {
 	f.Reason = "disconnected"
 	return !f.AllowTemporaryStates && store.IsDisconnected()
@@ -362,13 +397,13 @@ func (f *StoreStateFilter) hasRejectLeaderProperty(opts *config.PersistOptions,
 // N: the condition is expected to be true for a long time.
 // X means when the condition is true, the store CANNOT be selected.
 //
-// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject
-// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N
+// Condition    Down Offline Tomb Drain Pause Disconn Busy RmLimit AddLimit Snap Pending Reject
+// IsTemporary  N    N       N    N     N     Y       Y    Y       Y        Y    Y       N
 //
-// LeaderSource X            X    X     X
-// RegionSource                                 X    X                X
-// LeaderTarget X    X       X    X     X       X                                  X
-// RegionTarget X    X       X          X       X            X        X    X
+// LeaderSource X            X          X     X
+// RegionSource                                       X    X                X
+// LeaderTarget X    X       X    X     X     X       X                                  X
+// RegionTarget X    X       X    X           X       X            X        X    X
 
 const (
 	leaderSource = iota
@@ -386,13 +421,13 @@ func (f *StoreStateFilter) anyConditionMatch(typ int, opt *config.PersistOptions
 	case regionSource:
 		funcs = []conditionFunc{f.isBusy, f.exceedRemoveLimit, f.tooManySnapshots}
 	case leaderTarget:
-		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.pauseLeaderTransfer,
-			f.slowStoreEvicted, f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty}
+		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDraining, f.isDown, f.pauseLeaderTransfer,
+			f.slowStoreEvicted, f.isLatencySlow, f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty}
 	case regionTarget:
-		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.isDisconnected, f.isBusy,
+		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDraining, f.isDown, f.isDisconnected, f.isBusy,
 			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers}
 	case scatterRegionTarget:
-		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.isDisconnected, f.isBusy}
+		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDraining, f.isDown, f.isDisconnected, f.isBusy}
 	}
 	for _, cf := range funcs {
 		if cf(opt, store) {
@@ -193,6 +193,15 @@ func (s *testFiltersSuite) TestStoreStateFilter(c *C) {
 		{3, true, true},
 	}
 	check(store, testCases)
+
+	// Slow latency
+	store = store.Clone(core.SetStoreStats(&pdpb.StoreStats{}), core.SetLatencySlowTimes(2))
+	testCases = []testCase{
+		{0, true, false},
+		{1, true, true},
+		{2, true, false},
+	}
+	check(store, testCases)
 }
 
 func (s *testFiltersSuite) TestIsolationFilter(c *C) {
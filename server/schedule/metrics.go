@@ -51,6 +51,38 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
 		}, []string{"type"})
 
+	operatorResultCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "result_total",
+			Help:      "Counter of the finished operators by result.",
+		}, []string{"type", "result"})
+
+	operatorStepResultCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "step_result_total",
+			Help:      "Counter of the steps of the finished operators by result.",
+		}, []string{"type", "result"})
+
+	operatorCrossDCBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "cross_dc_bytes_total",
+			Help:      "Counter of the bytes of the regions moved across the zones by the finished operators.",
+		}, []string{"direction"})
+
+	operatorSameDCBytesCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "same_dc_bytes_total",
+			Help:      "Counter of the bytes of the regions moved within the zones by the finished operators.",
+		})
+
 	storeLimitCostCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "pd",
@@ -80,6 +112,10 @@ func init() {
 	prometheus.MustRegister(operatorCounter)
 	prometheus.MustRegister(operatorDuration)
 	prometheus.MustRegister(operatorWaitDuration)
+	prometheus.MustRegister(operatorResultCounter)
+	prometheus.MustRegister(operatorStepResultCounter)
+	prometheus.MustRegister(operatorCrossDCBytesCounter)
+	prometheus.MustRegister(operatorSameDCBytesCounter)
 	prometheus.MustRegister(storeLimitCostCounter)
 	prometheus.MustRegister(operatorWaitCounter)
 	prometheus.MustRegister(scatterCounter)
@@ -16,6 +16,9 @@ package operator
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// durationBuckets are the buckets of the region move durations.
+var durationBuckets = []float64{1, 5, 10, 30, 60, 120, 300}
+
 var (
 	operatorStepDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -26,6 +29,33 @@ var (
 			Buckets:   []float64{0.5, 1, 2, 4, 8, 16, 20, 40, 60, 90, 120, 180, 240, 300, 480, 600, 720, 900, 1200, 1800, 3600},
 		}, []string{"type"})
 
+	operatorFinishDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "finish_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) from the start to the finish of operator.",
+			Buckets:   durationBuckets,
+		}, []string{"type"})
+
+	operatorStepFinishDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "step_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) from the start to the finish of operator step.",
+			Buckets:   durationBuckets,
+		}, []string{"type"})
+
+	operatorTransferDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "operator",
+			Name:      "transfer_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) from the start to the finish of operator moving region, by region size.",
+			Buckets:   durationBuckets,
+		}, []string{"size_bucket"})
+
 	// OperatorLimitCounter exposes the counter when meeting limit.
 	OperatorLimitCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -38,5 +68,22 @@ var (
 
 func init() {
 	prometheus.MustRegister(operatorStepDuration)
+	prometheus.MustRegister(operatorFinishDuration)
+	prometheus.MustRegister(operatorStepFinishDuration)
+	prometheus.MustRegister(operatorTransferDuration)
 	prometheus.MustRegister(OperatorLimitCounter)
 }
+
+// regionSizeBucket returns the size bucket of the region size in MiB.
+func regionSizeBucket(size int64) string {
+	switch {
+	case size < 10:
+		return "<10MB"
+	case size < 50:
+		return "10-50MB"
+	case size <= 144:
+		return "50-144MB"
+	default:
+		return ">144MB"
+	}
+}
@@ -56,6 +56,9 @@ type Operator struct {
 	FinishedCounters []prometheus.Counter
 	AdditionalInfos  map[string]string
 	ApproximateSize  int64
+	// RollbackSteps are the steps compensating for the steps, the i-th one is
+	// for the i-th step, nil means nothing needs to be compensated.
+	RollbackSteps []OpStep
 }
 
 // NewOperator creates a new operator.
@@ -76,6 +79,7 @@ func NewOperator(desc, brief string, regionID uint64, regionEpoch *metapb.Region
 		level:           level,
 		AdditionalInfos: make(map[string]string),
 		ApproximateSize: approximateSize,
+		RollbackSteps:   newRollbackSteps(steps),
 	}
 }
 
@@ -188,6 +192,24 @@ func (o *Operator) GetStartTime() time.Time {
 	return o.status.ReachTimeOf(STARTED)
 }
 
+// GetStepFinishTime returns the time when the i-th step finished, the zero time
+// is returned if the step has not finished yet.
+func (o *Operator) GetStepFinishTime(i int) time.Time {
+	if i < 0 || i >= len(o.stepsTime) {
+		return time.Time{}
+	}
+	if t := atomic.LoadInt64(&o.stepsTime[i]); t != 0 {
+		return time.Unix(0, t)
+	}
+	return time.Time{}
+}
+
+// CurrentStepIndex returns the index of the step being executed, it equals
+// Len() once all steps are finished.
+func (o *Operator) CurrentStepIndex() int {
+	return int(atomic.LoadInt32(&o.currentStep))
+}
+
 // RunningTime returns duration since it started.
 func (o *Operator) RunningTime() time.Duration {
 	if o.HasStarted() {
@@ -277,8 +299,17 @@ func (o *Operator) Check(region *core.RegionInfo) OpStep {
 		if o.steps[int(step)].IsFinish(region) {
 			if atomic.CompareAndSwapInt64(&(o.stepsTime[step]), 0, time.Now().UnixNano()) {
 				startTime, _ := o.getCurrentTimeAndStep()
-				operatorStepDuration.WithLabelValues(reflect.TypeOf(o.steps[int(step)]).Name()).
-					Observe(time.Unix(0, o.stepsTime[step]).Sub(startTime).Seconds())
+				finishTime := time.Unix(0, o.stepsTime[step])
+				stepType := reflect.TypeOf(o.steps[int(step)]).Name()
+				operatorStepDuration.WithLabelValues(stepType).Observe(finishTime.Sub(startTime).Seconds())
+				operatorStepFinishDuration.WithLabelValues(stepType).Observe(finishTime.Sub(startTime).Seconds())
+				if int(step) == len(o.steps)-1 {
+					duration := finishTime.Sub(o.GetStartTime()).Seconds()
+					operatorFinishDuration.WithLabelValues(o.desc).Observe(duration)
+					if o.kind&OpRegion != 0 {
+						operatorTransferDuration.WithLabelValues(regionSizeBucket(o.ApproximateSize)).Observe(duration)
+					}
+				}
 			}
 			atomic.StoreInt32(&o.currentStep, step+1)
 		} else {
@@ -17,12 +17,15 @@ package operator
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -392,6 +395,78 @@ func (s *testOperatorSuite) TestCheck(c *C) {
 	}
 }
 
+func (s *testOperatorSuite) TestDurationHistogram(c *C) {
+	operatorFinishDuration.Reset()
+	operatorStepFinishDuration.Reset()
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1})
+	durations := []time.Duration{500 * time.Millisecond, 3 * time.Second, 20 * time.Second, 50 * time.Second, 200 * time.Second, 10 * time.Minute}
+	for _, d := range durations {
+		steps := []OpStep{
+			AddPeer{ToStore: 1, PeerID: 1},
+			TransferLeader{FromStore: 2, ToStore: 1},
+		}
+		op := NewOperator("test-duration", "test", 1, &metapb.RegionEpoch{}, OpRegion, 0, steps...)
+		c.Assert(op.Start(), IsTrue)
+		op.status.setTime(STARTED, time.Now().Add(-d))
+		c.Assert(op.Check(region), IsNil)
+		c.Assert(op.Status(), Equals, SUCCESS)
+	}
+
+	// The cumulative counts of the buckets 1s, 5s, 10s, 30s, 60s, 120s and 300s.
+	checkBuckets := func(h prometheus.Histogram, expect []uint64) {
+		m := &dto.Metric{}
+		c.Assert(h.Write(m), IsNil)
+		c.Assert(m.GetHistogram().GetSampleCount(), Equals, uint64(len(durations)))
+		buckets := m.GetHistogram().GetBucket()
+		c.Assert(buckets, HasLen, len(expect))
+		for i, b := range buckets {
+			c.Assert(b.GetCumulativeCount(), Equals, expect[i])
+		}
+	}
+	checkBuckets(operatorFinishDuration.WithLabelValues("test-duration").(prometheus.Histogram), []uint64{1, 2, 2, 3, 4, 4, 5})
+	checkBuckets(operatorStepFinishDuration.WithLabelValues("AddPeer").(prometheus.Histogram), []uint64{1, 2, 2, 3, 4, 4, 5})
+	// The leader is transferred at once after the peer is added.
+	checkBuckets(operatorStepFinishDuration.WithLabelValues("TransferLeader").(prometheus.Histogram), []uint64{6, 6, 6, 6, 6, 6, 6})
+}
+
+func (s *testOperatorSuite) TestTransferDurationHistogram(c *C) {
+	operatorTransferDuration.Reset()
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1})
+	testCases := []struct {
+		kind     OpKind
+		size     int64
+		duration time.Duration
+	}{
+		{OpRegion, 1, 3 * time.Second},
+		{OpRegion, 9, 3 * time.Second},
+		{OpRegion, 10, 20 * time.Second},
+		{OpRegion, 49, 20 * time.Second},
+		{OpRegion, 50, 50 * time.Second},
+		{OpRegion, 144, 100 * time.Second},
+		{OpRegion, 145, 200 * time.Second},
+		{OpRegion, 1024, 200 * time.Second},
+		// The operators not moving the regions are not observed.
+		{OpLeader, 1024, time.Second},
+	}
+	for _, t := range testCases {
+		op := NewOperator("test-transfer", "test", 1, &metapb.RegionEpoch{}, t.kind, t.size, AddPeer{ToStore: 1, PeerID: 1})
+		c.Assert(op.Start(), IsTrue)
+		op.status.setTime(STARTED, time.Now().Add(-t.duration))
+		c.Assert(op.Check(region), IsNil)
+	}
+
+	checkBucket := func(sizeBucket string, count uint64, sum float64) {
+		m := &dto.Metric{}
+		c.Assert(operatorTransferDuration.WithLabelValues(sizeBucket).(prometheus.Histogram).Write(m), IsNil)
+		c.Assert(m.GetHistogram().GetSampleCount(), Equals, count)
+		c.Assert(math.Round(m.GetHistogram().GetSampleSum()), Equals, sum)
+	}
+	checkBucket("<10MB", 2, 6)
+	checkBucket("10-50MB", 2, 40)
+	checkBucket("50-144MB", 2, 150)
+	checkBucket(">144MB", 2, 400)
+}
+
 func (s *testOperatorSuite) TestSchedulerKind(c *C) {
 	testdata := []struct {
 		op     *Operator
@@ -520,3 +595,69 @@ func (s *testOperatorSuite) TestRecord(c *C) {
 	c.Assert(ob.FinishTime, Equals, now)
 	c.Assert(ob.duration.Seconds(), Greater, time.Second.Seconds())
 }
+
+type mockRollbackCluster struct {
+	region *core.RegionInfo
+	ops    []*Operator
+}
+
+func (mc *mockRollbackCluster) GetRegion(regionID uint64) *core.RegionInfo {
+	return mc.region
+}
+
+func (mc *mockRollbackCluster) AddOperator(ops ...*Operator) bool {
+	mc.ops = append(mc.ops, ops...)
+	return true
+}
+
+func (s *testOperatorSuite) TestRollback(c *C) {
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
+	op := s.newTestOperator(1, OpLeader|OpRegion,
+		AddLearner{ToStore: 3, PeerID: 3},
+		PromoteLearner{ToStore: 3, PeerID: 3},
+		TransferLeader{FromStore: 1, ToStore: 3},
+		RemovePeer{FromStore: 1, PeerID: 1},
+	)
+	c.Assert(op.RollbackSteps, DeepEquals, []OpStep{
+		RemovePeer{FromStore: 3, PeerID: 3},
+		nil,
+		TransferLeader{ToStore: 1},
+		nil,
+	})
+
+	// Nothing to roll back before any step finishes.
+	cluster := &mockRollbackCluster{region: region}
+	c.Assert(op.Start(), IsTrue)
+	c.Assert(op.Check(region), DeepEquals, op.Step(0))
+	c.Assert(op.Rollback(cluster), IsNil)
+	c.Assert(cluster.ops, HasLen, 0)
+
+	// The learner is added and promoted, and the leader is transferred.
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner}))
+	c.Assert(op.Check(region), DeepEquals, op.Step(1))
+	region = region.Clone(core.WithPromoteLearner(3))
+	c.Assert(op.Check(region), DeepEquals, op.Step(2))
+	region = region.Clone(core.WithLeader(region.GetStorePeer(3)))
+	c.Assert(op.Check(region), DeepEquals, op.Step(3))
+	cluster.region = region
+	c.Assert(op.Rollback(cluster), IsNil)
+	c.Assert(cluster.ops, HasLen, 1)
+	rollback := cluster.ops[0]
+	c.Assert(rollback.Desc(), Equals, "rollback-test")
+	c.Assert(rollback.GetPriorityLevel(), Equals, core.HighPriority)
+	c.Assert(rollback.Kind(), Equals, OpLeader|OpRegion)
+	s.checkSteps(c, rollback, []OpStep{
+		TransferLeader{FromStore: 3, ToStore: 1},
+		RemovePeer{FromStore: 3, PeerID: 3},
+	})
+
+	// The removed peers cannot be restored.
+	region = s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2}, [2]uint64{3, 3})
+	op = s.newTestOperator(1, OpRegion, RemovePeer{FromStore: 3, PeerID: 3}, AddPeer{ToStore: 4, PeerID: 4})
+	c.Assert(op.Start(), IsTrue)
+	region = region.Clone(core.WithRemoveStorePeer(3))
+	cluster = &mockRollbackCluster{region: region}
+	c.Assert(op.Check(region), DeepEquals, op.Step(1))
+	c.Assert(op.Rollback(cluster), NotNil)
+	c.Assert(cluster.ops, HasLen, 0)
+}
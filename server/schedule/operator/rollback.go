@@ -0,0 +1,111 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+)
+
+// RollbackCluster is the cluster which the rollback operators are added to.
+type RollbackCluster interface {
+	GetRegion(regionID uint64) *core.RegionInfo
+	AddOperator(ops ...*Operator) bool
+}
+
+// newRollbackSteps creates the steps compensating for the steps.
+func newRollbackSteps(steps []OpStep) []OpStep {
+	rollbackSteps := make([]OpStep, len(steps))
+	for i, step := range steps {
+		switch s := step.(type) {
+		case AddPeer:
+			rollbackSteps[i] = RemovePeer{FromStore: s.ToStore, PeerID: s.PeerID}
+		case AddLearner:
+			rollbackSteps[i] = RemovePeer{FromStore: s.ToStore, PeerID: s.PeerID}
+		case TransferLeader:
+			// FromStore is decided by the leader when rolling back, since the
+			// target may be any of ToStores.
+			rollbackSteps[i] = TransferLeader{ToStore: s.FromStore}
+		}
+		// A promoted learner is removed by the step compensating for adding it.
+	}
+	return rollbackSteps
+}
+
+// isIrreversibleStep checks if the step cannot be compensated for.
+func isIrreversibleStep(step OpStep) bool {
+	switch step.(type) {
+	case RemovePeer, MergeRegion, SplitRegion, ChangePeerV2Enter, ChangePeerV2Leave:
+		return true
+	}
+	return false
+}
+
+// Rollback issues an operator compensating for the finished steps in reverse
+// order, so that the region returns to the state before the operator started.
+// It is used when the operator fails midway, e.g. a peer is added while the
+// leader cannot be transferred, which leaves the region with an extra replica.
+func (o *Operator) Rollback(cluster RollbackCluster) error {
+	finished := int(atomic.LoadInt32(&o.currentStep))
+	if finished > len(o.steps) {
+		finished = len(o.steps)
+	}
+	if finished == 0 {
+		return nil
+	}
+	region := cluster.GetRegion(o.regionID)
+	if region == nil {
+		return errs.ErrRollbackOperator.FastGenByArgs("region not found")
+	}
+
+	var (
+		steps []OpStep
+		kind  OpKind
+	)
+	for i := finished - 1; i >= 0; i-- {
+		if isIrreversibleStep(o.steps[i]) {
+			return errs.ErrRollbackOperator.FastGenByArgs(fmt.Sprintf("step %s is irreversible", o.steps[i]))
+		}
+		step := o.RollbackSteps[i]
+		if step == nil {
+			continue
+		}
+		if tl, ok := step.(TransferLeader); ok {
+			tl.FromStore = region.GetLeader().GetStoreId()
+			step = tl
+		}
+		if step.IsFinish(region) {
+			continue
+		}
+		if _, ok := step.(TransferLeader); ok {
+			kind |= OpLeader
+		} else {
+			kind |= OpRegion
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	op := NewOperator("rollback-"+o.desc, o.brief, o.regionID, region.GetRegionEpoch(), kind, o.ApproximateSize, steps...)
+	op.SetPriorityLevel(core.HighPriority)
+	if !cluster.AddOperator(op) {
+		return errs.ErrRollbackOperator.FastGenByArgs("cannot add the rollback operator")
+	}
+	return nil
+}
@@ -15,6 +15,7 @@
 package operator
 
 import (
+	"sync/atomic"
 	"time"
 )
 
@@ -23,3 +24,9 @@ import (
 func SetOperatorStatusReachTime(op *Operator, st OpStatus, t time.Time) {
 	op.status.setTime(st, t)
 }
+
+// SetOperatorStepFinishTime sets the finish time of the i-th step of the operator.
+// NOTE: Should only use in test.
+func SetOperatorStepFinishTime(op *Operator, i int, t time.Time) {
+	atomic.StoreInt64(&op.stepsTime[i], t.UnixNano())
+}
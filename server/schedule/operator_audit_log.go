@@ -0,0 +1,162 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// OperatorAuditStep is the audit record of an operator step.
+type OperatorAuditStep struct {
+	Step string `json:"step"`
+	// StartTime is nil if the step has not started.
+	StartTime *time.Time `json:"start_time,omitempty"`
+	// EndTime is nil if the step has not finished.
+	EndTime *time.Time `json:"end_time,omitempty"`
+}
+
+// OperatorAuditEntry is the audit record of a finished operator.
+type OperatorAuditEntry struct {
+	// ID is the sequence number of the operator in the audit log.
+	ID         uint64              `json:"id"`
+	Type       string              `json:"type"`
+	Kind       string              `json:"kind"`
+	RegionID   uint64              `json:"region_id"`
+	CreateTime time.Time           `json:"create_time"`
+	Steps      []OperatorAuditStep `json:"steps"`
+	Status     string              `json:"status"`
+	FinishTime time.Time           `json:"finish_time"`
+}
+
+func newOperatorAuditEntry(op *operator.Operator, finishTime time.Time) *OperatorAuditEntry {
+	entry := &OperatorAuditEntry{
+		Type:       op.Desc(),
+		Kind:       op.Kind().String(),
+		RegionID:   op.RegionID(),
+		CreateTime: op.GetCreateTime(),
+		Steps:      make([]OperatorAuditStep, 0, op.Len()),
+		Status:     operator.OpStatusToString(op.Status()),
+		FinishTime: finishTime,
+	}
+	// The step starts when the previous step finishes, and the first step
+	// starts with the operator.
+	start := op.GetStartTime()
+	for i := 0; i < op.Len(); i++ {
+		step := OperatorAuditStep{Step: op.Step(i).String()}
+		if !start.IsZero() {
+			startTime := start
+			step.StartTime = &startTime
+		}
+		end := op.GetStepFinishTime(i)
+		if !end.IsZero() {
+			step.EndTime = &end
+		}
+		entry.Steps = append(entry.Steps, step)
+		start = end
+	}
+	return entry
+}
+
+// OperatorAuditLog is a ring buffer of the finished operators, which is used
+// to find out what happened to the operators after scheduling incidents. The
+// oldest entries are overwritten once the buffer is full.
+type OperatorAuditLog struct {
+	mu      sync.RWMutex
+	entries []*OperatorAuditEntry
+	// next is the position where the next entry is written.
+	next   int
+	count  int
+	nextID uint64
+}
+
+// NewOperatorAuditLog creates an OperatorAuditLog which keeps at most
+// capacity entries.
+func NewOperatorAuditLog(capacity int) *OperatorAuditLog {
+	return &OperatorAuditLog{entries: make([]*OperatorAuditEntry, capacity)}
+}
+
+// Record records the finished operator.
+func (l *OperatorAuditLog) Record(op *operator.Operator) {
+	entry := newOperatorAuditEntry(op, time.Now())
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	l.nextID++
+	entry.ID = l.nextID
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.count < len(l.entries) {
+		l.count++
+	}
+}
+
+// Resize changes the capacity of the audit log, the newest entries are kept.
+func (l *OperatorAuditLog) Resize(capacity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if capacity == len(l.entries) {
+		return
+	}
+	entries := l.listLocked(capacity, "")
+	l.entries = make([]*OperatorAuditEntry, capacity)
+	l.count = len(entries)
+	for i, entry := range entries {
+		// entries are sorted from the newest to the oldest.
+		l.entries[len(entries)-1-i] = entry
+	}
+	l.next = 0
+	if capacity > 0 {
+		l.next = l.count % capacity
+	}
+}
+
+// Capacity returns the max number of the entries.
+func (l *OperatorAuditLog) Capacity() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// Len returns the number of the entries.
+func (l *OperatorAuditLog) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.count
+}
+
+// List returns at most limit entries from the newest to the oldest. If typ is
+// not empty, only the operators of the type are returned.
+func (l *OperatorAuditLog) List(limit int, typ string) []*OperatorAuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.listLocked(limit, typ)
+}
+
+func (l *OperatorAuditLog) listLocked(limit int, typ string) []*OperatorAuditEntry {
+	var entries []*OperatorAuditEntry
+	for i := 1; i <= l.count && len(entries) < limit; i++ {
+		entry := l.entries[(l.next-i+len(l.entries))%len(l.entries)]
+		if len(typ) > 0 && entry.Type != typ {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/schedule/hbstream"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testOperatorAuditLogSuite{})
+
+type testOperatorAuditLogSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *testOperatorAuditLogSuite) SetUpSuite(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *testOperatorAuditLogSuite) TearDownSuite(c *C) {
+	s.cancel()
+}
+
+func newAuditTestOperator(regionID uint64, desc string) *operator.Operator {
+	op := operator.NewOperator(desc, desc, regionID, &metapb.RegionEpoch{}, operator.OpLeader, 0,
+		operator.TransferLeader{FromStore: 1, ToStore: 2})
+	_ = op.Cancel()
+	return op
+}
+
+func (s *testOperatorAuditLogSuite) TestRingBuffer(c *C) {
+	l := NewOperatorAuditLog(3)
+	c.Assert(l.List(10, ""), HasLen, 0)
+	for i := uint64(1); i <= 5; i++ {
+		desc := "transfer-leader"
+		if i%2 == 0 {
+			desc = "balance-region"
+		}
+		l.Record(newAuditTestOperator(i, desc))
+	}
+	c.Assert(l.Len(), Equals, 3)
+
+	// The oldest entries are overwritten.
+	entries := l.List(10, "")
+	c.Assert(entries, HasLen, 3)
+	for i, regionID := range []uint64{5, 4, 3} {
+		c.Assert(entries[i].RegionID, Equals, regionID)
+		c.Assert(entries[i].ID, Equals, regionID)
+	}
+	c.Assert(l.List(2, ""), HasLen, 2)
+	entries = l.List(10, "transfer-leader")
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].RegionID, Equals, uint64(5))
+	c.Assert(entries[1].RegionID, Equals, uint64(3))
+
+	// The newest entries are kept after resizing.
+	l.Resize(2)
+	entries = l.List(10, "")
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].RegionID, Equals, uint64(5))
+	c.Assert(entries[1].RegionID, Equals, uint64(4))
+	l.Record(newAuditTestOperator(6, "transfer-leader"))
+	entries = l.List(10, "")
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].RegionID, Equals, uint64(6))
+	c.Assert(entries[1].RegionID, Equals, uint64(5))
+	l.Resize(4)
+	l.Record(newAuditTestOperator(7, "transfer-leader"))
+	entries = l.List(10, "")
+	c.Assert(entries, HasLen, 3)
+	c.Assert(entries[0].RegionID, Equals, uint64(7))
+	c.Assert(entries[2].RegionID, Equals, uint64(5))
+
+	// Nothing is recorded if the capacity is 0.
+	l.Resize(0)
+	l.Record(newAuditTestOperator(8, "transfer-leader"))
+	c.Assert(l.Len(), Equals, 0)
+	c.Assert(l.List(10, ""), HasLen, 0)
+}
+
+func (s *testOperatorAuditLogSuite) TestConcurrent(c *C) {
+	l := NewOperatorAuditLog(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Record(newAuditTestOperator(uint64(i*100+j), "transfer-leader"))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				for _, entry := range l.List(10, "transfer-leader") {
+					c.Assert(entry, NotNil)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(l.Len(), Equals, 100)
+	entries := l.List(200, "")
+	c.Assert(entries, HasLen, 100)
+	for i := 1; i < len(entries); i++ {
+		c.Assert(entries[i-1].ID, Equals, entries[i].ID+1)
+	}
+}
+
+func (s *testOperatorAuditLogSuite) TestOperatorController(c *C) {
+	tc := mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(s.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(s.ctx, tc, stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	region := tc.GetRegion(1)
+
+	op := operator.NewOperator("test", "test", 1, region.GetRegionEpoch(), operator.OpRegion|operator.OpLeader, 0,
+		operator.AddLearner{ToStore: 3, PeerID: 3},
+		operator.PromoteLearner{ToStore: 3, PeerID: 3},
+		operator.TransferLeader{FromStore: 1, ToStore: 3},
+	)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	region = ApplyOperatorStep(region, op)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(oc.GetOperatorAuditLog().Len(), Equals, 0)
+	c.Assert(oc.RemoveOperator(op), IsTrue)
+
+	c.Assert(oc.GetOperatorAuditLog().Capacity(), Equals, 10000)
+	entries := oc.GetOperatorAuditLog().List(10, "")
+	c.Assert(entries, HasLen, 1)
+	entry := entries[0]
+	c.Assert(entry.Type, Equals, "test")
+	c.Assert(entry.RegionID, Equals, uint64(1))
+	c.Assert(entry.Status, Equals, "Canceled")
+	c.Assert(entry.CreateTime, Equals, op.GetCreateTime())
+	c.Assert(entry.Steps, HasLen, 3)
+	// The first step is finished, the second one is running.
+	c.Assert(*entry.Steps[0].StartTime, Equals, op.GetStartTime())
+	c.Assert(*entry.Steps[0].EndTime, Equals, op.GetStepFinishTime(0))
+	c.Assert(*entry.Steps[1].StartTime, Equals, op.GetStepFinishTime(0))
+	c.Assert(entry.Steps[1].EndTime, IsNil)
+	c.Assert(entry.Steps[2].StartTime, IsNil)
+
+	data, err := json.Marshal(entries)
+	c.Assert(err, IsNil)
+	var decoded []*OperatorAuditEntry
+	c.Assert(json.Unmarshal(data, &decoded), IsNil)
+	c.Assert(decoded, HasLen, 1)
+	c.Assert(decoded[0].Steps[0].Step, Equals, "add learner peer 3 on store 3")
+	c.Assert(decoded[0].Steps[1].EndTime, IsNil)
+	c.Assert(decoded[0].FinishTime.Equal(entry.FinishTime), IsTrue)
+
+	// The capacity can be changed online.
+	tc.SetOperatorAuditLogCapacity(0)
+	op = operator.NewOperator("test", "test", 1, region.GetRegionEpoch(), operator.OpLeader, 0,
+		operator.TransferLeader{FromStore: 1, ToStore: 2})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.RemoveOperator(op), IsTrue)
+	c.Assert(oc.GetOperatorAuditLog().Len(), Equals, 0)
+}
@@ -18,10 +18,12 @@ import (
 	"container/heap"
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/eraftpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -29,8 +31,10 @@ import (
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/events"
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/labeler"
 	"github.com/tikv/pd/server/schedule/operator"
@@ -58,16 +62,30 @@ var (
 // OperatorController is used to limit the speed of scheduling.
 type OperatorController struct {
 	sync.RWMutex
-	ctx             context.Context
-	cluster         Cluster
-	operators       map[uint64]*operator.Operator
-	hbStreams       *hbstream.HeartbeatStreams
-	fastOperators   *cache.TTLUint64
-	counts          map[operator.OpKind]uint64
+	ctx           context.Context
+	cluster       Cluster
+	operators     map[uint64]*operator.Operator
+	dag           *OperatorDAG
+	hbStreams     *hbstream.HeartbeatStreams
+	fastOperators *cache.TTLUint64
+	counts        map[operator.OpKind]uint64
+	storeCounts   map[uint64]uint64
+	// storeSendSnaps and storeRecvSnaps are the number of the operators
+	// which send or receive snapshots by the stores.
+	storeSendSnaps  map[uint64]uint64
+	storeRecvSnaps  map[uint64]uint64
 	opRecords       *OperatorRecords
+	auditLog        *OperatorAuditLog
+	resultRecorder  *operatorResultRecorder
+	crossDCTraffic  *crossDCTrafficRecorder
 	wop             WaitingOperator
 	wopStatus       *WaitingOperatorStatus
 	opNotifierQueue operatorQueue
+	// eventHub publishes the progress of the operators.
+	eventHub *events.Hub
+	// snapshotBytes is the estimated size of the snapshots sent by the
+	// running operators.
+	snapshotBytes uint64
 }
 
 // NewOperatorController creates a OperatorController.
@@ -76,13 +94,21 @@ func NewOperatorController(ctx context.Context, cluster Cluster, hbStreams *hbst
 		ctx:             ctx,
 		cluster:         cluster,
 		operators:       make(map[uint64]*operator.Operator),
+		dag:             NewOperatorDAG(),
 		hbStreams:       hbStreams,
 		fastOperators:   cache.NewIDTTL(ctx, time.Minute, FastOperatorFinishTime),
 		counts:          make(map[operator.OpKind]uint64),
+		storeCounts:     make(map[uint64]uint64),
+		storeSendSnaps:  make(map[uint64]uint64),
+		storeRecvSnaps:  make(map[uint64]uint64),
 		opRecords:       NewOperatorRecords(ctx),
+		auditLog:        NewOperatorAuditLog(0),
+		resultRecorder:  newOperatorResultRecorder(),
+		crossDCTraffic:  &crossDCTrafficRecorder{},
 		wop:             NewRandBuckets(),
 		wopStatus:       NewWaitingOperatorStatus(),
 		opNotifierQueue: make(operatorQueue, 0),
+		eventHub:        events.NewHub(),
 	}
 }
 
@@ -92,6 +118,29 @@ func (oc *OperatorController) Ctx() context.Context {
 	return oc.ctx
 }
 
+// GetOperatorDAG returns the dependency graph of the running operators, which
+// can be used by the schedulers to find out why an operator is refused.
+func (oc *OperatorController) GetOperatorDAG() *OperatorDAG {
+	return oc.dag
+}
+
+// GetOperatorAuditLog returns the audit log of the finished operators.
+func (oc *OperatorController) GetOperatorAuditLog() *OperatorAuditLog {
+	return oc.auditLog
+}
+
+// GetOperatorResultStats returns the count of the finished operators and
+// steps by type and result.
+func (oc *OperatorController) GetOperatorResultStats() *OperatorResultStats {
+	return oc.resultRecorder.get()
+}
+
+// GetCrossDCTraffic returns the data moved across and within the zones by the
+// finished operators.
+func (oc *OperatorController) GetCrossDCTraffic() *CrossDCTraffic {
+	return oc.crossDCTraffic.get(time.Now())
+}
+
 // GetCluster exports cluster to evict-scheduler for check store status.
 func (oc *OperatorController) GetCluster() Cluster {
 	oc.RLock()
@@ -110,10 +159,18 @@ func (oc *OperatorController) Dispatch(region *core.RegionInfo, source string) {
 		// Update operator status:
 		// The operator status should be STARTED.
 		// Check will call CheckSuccess and CheckTimeout.
+		current := op.CurrentStepIndex()
 		step := op.Check(region)
+		oc.publishStepProgress(op, current)
 		switch op.Status() {
 		case operator.STARTED:
 			operatorCounter.WithLabelValues(op.Desc(), "check").Inc()
+			if op.CurrentStepIndex() != current {
+				// The finished steps no longer occupy the stores.
+				oc.Lock()
+				oc.updateCounts(oc.operators)
+				oc.Unlock()
+			}
 			if source == DispatchFromHeartBeat && oc.checkStaleOperator(op, step, region) {
 				return
 			}
@@ -130,6 +187,7 @@ func (oc *OperatorController) Dispatch(region *core.RegionInfo, source string) {
 		case operator.TIMEOUT:
 			if oc.RemoveOperator(op) {
 				operatorCounter.WithLabelValues(op.Desc(), "promote-timeout").Inc()
+				oc.rollbackOperator(op)
 				oc.PromoteWaitingOperator()
 			}
 		default:
@@ -156,6 +214,9 @@ func (oc *OperatorController) Dispatch(region *core.RegionInfo, source string) {
 func (oc *OperatorController) checkStaleOperator(op *operator.Operator, step operator.OpStep, region *core.RegionInfo) bool {
 	err := step.CheckInProgress(oc.cluster, region)
 	if err != nil {
+		// The region or the stores have changed and the step can not be
+		// executed anymore, the operator is not rolled back since the
+		// rollback steps are built for the region before the changes.
 		if oc.RemoveOperator(op, zap.String("reason", err.Error())) {
 			operatorCounter.WithLabelValues(op.Desc(), "stale").Inc()
 			operatorWaitCounter.WithLabelValues(op.Desc(), "promote-stale").Inc()
@@ -187,6 +248,33 @@ func (oc *OperatorController) checkStaleOperator(op *operator.Operator, step ope
 	return false
 }
 
+// rollbackOperator rolls back the operator which times out midway, so that the
+// region will not be left in an intermediate state.
+func (oc *OperatorController) rollbackOperator(op *operator.Operator) {
+	if err := op.Rollback(rollbackCluster{oc}); err != nil {
+		log.Warn("failed to roll back operator",
+			zap.Uint64("region-id", op.RegionID()),
+			zap.Reflect("operator", op),
+			errs.ZapError(err))
+		operatorCounter.WithLabelValues(op.Desc(), "rollback-failed").Inc()
+		return
+	}
+	operatorCounter.WithLabelValues(op.Desc(), "rollback").Inc()
+}
+
+// rollbackCluster adds the rollback operators to the operator controller.
+type rollbackCluster struct {
+	oc *OperatorController
+}
+
+func (c rollbackCluster) GetRegion(regionID uint64) *core.RegionInfo {
+	return c.oc.cluster.GetRegion(regionID)
+}
+
+func (c rollbackCluster) AddOperator(ops ...*operator.Operator) bool {
+	return c.oc.AddOperator(ops...)
+}
+
 func (oc *OperatorController) getNextPushOperatorTime(step operator.OpStep, now time.Time) time.Time {
 	nextTime := slowNotifyInterval
 	switch step.(type) {
@@ -241,6 +329,9 @@ func (oc *OperatorController) pollNeedDispatchRegion() (r *core.RegionInfo, next
 
 // PushOperators periodically pushes the unfinished operator to the executor(TiKV).
 func (oc *OperatorController) PushOperators() {
+	// The operators are removed from the dependency graph once they end, GC
+	// is just a safeguard against leaks.
+	oc.dag.GC()
 	for {
 		r, next := oc.pollNeedDispatchRegion()
 		if !next {
@@ -371,6 +462,8 @@ func (oc *OperatorController) PromoteWaitingOperator() {
 // - There is no such region in the cluster
 // - The epoch of the operator and the epoch of the corresponding region are no longer consistent.
 // - The region already has a higher priority or same priority operator.
+// - It conflicts with the running operators, see OperatorDAG for details.
+// - Its target store has too many operators moving peers or leaders into it.
 // - Exceed the max number of waiting operators
 // - At least one operator is expired.
 func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
@@ -398,6 +491,21 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 			operatorWaitCounter.WithLabelValues(op.Desc(), "already-have").Inc()
 			return false
 		}
+		reasons := oc.dag.Conflicts(op)
+		if !oc.cluster.GetOpts().IsStoreFlowConflictEnabled() {
+			reasons = regionConflicts(reasons)
+		}
+		if len(reasons) > 0 {
+			desc := make([]string, 0, len(reasons))
+			for _, reason := range reasons {
+				desc = append(desc, reason.String())
+			}
+			log.Debug("conflict with running operators, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				zap.Strings("reasons", desc))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "conflict-"+reasons[0].Kind.String()).Inc()
+			return false
+		}
 		if op.Status() != operator.CREATED {
 			log.Error("trying to add operator with unexpected status",
 				zap.Uint64("region-id", op.RegionID()),
@@ -418,6 +526,27 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 		if op.SchedulerKind() == operator.OpAdmin || op.IsLeaveJointStateOperator() {
 			continue
 		}
+		if err := oc.checkStoreBusy(op); err != nil {
+			log.Debug("target store is busy, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				errs.ZapError(err))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "store-busy").Inc()
+			return false
+		}
+		if err := oc.checkStoreBandwidth(op, region); err != nil {
+			log.Debug("store bandwidth is exhausted, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				errs.ZapError(err))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "store-bandwidth").Inc()
+			return false
+		}
+		if err := oc.checkSnapshotBytes(op, region); err != nil {
+			log.Debug("too many snapshots in flight, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				errs.ZapError(err))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "snapshot-bytes").Inc()
+			return false
+		}
 		if cl, ok := oc.cluster.(interface{ GetRegionLabeler() *labeler.RegionLabeler }); ok {
 			l := cl.GetRegionLabeler()
 			if l.ScheduleDisabled(region) {
@@ -437,6 +566,124 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 	return !expired
 }
 
+// checkStoreBusy returns ErrStoreBusy if any target store of the operator has
+// reached the max number of the operators moving peers or leaders into it.
+// The operators repairing the replicas or created by the administrator are not
+// limited, so the recovery from a store failure is not slowed down.
+func (oc *OperatorController) checkStoreBusy(op *operator.Operator) error {
+	limit := oc.cluster.GetOpts().GetMaxOperatorsPerStore()
+	if limit == 0 || op.Kind()&(operator.OpAdmin|operator.OpReplica) != 0 {
+		return nil
+	}
+	for _, storeID := range targetStores(op) {
+		if count := oc.storeCounts[storeID]; count >= limit {
+			return errs.ErrStoreBusy.FastGenByArgs(storeID, count)
+		}
+	}
+	return nil
+}
+
+// checkStoreBandwidth returns ErrStoreSnapshotBusy if the snapshots sent or
+// received by any store of the operator exceed the bandwidth of the store.
+func (oc *OperatorController) checkStoreBandwidth(op *operator.Operator, region *core.RegionInfo) error {
+	avgRegionSize := oc.cluster.GetAverageRegionSize()
+	if avgRegionSize <= 0 {
+		return nil
+	}
+	opts := oc.cluster.GetOpts()
+	sendStore, recvStores := snapshotStores(op, region)
+	for _, storeID := range recvStores {
+		bandwidth := opts.GetStoreBandwidth(storeID).MaxRecvBandwidthMBps
+		if count := oc.storeRecvSnaps[storeID]; bandwidth > 0 && count >= snapshotLimit(bandwidth, avgRegionSize) {
+			return errs.ErrStoreSnapshotBusy.FastGenByArgs(storeID, count, "received")
+		}
+	}
+	if len(recvStores) > 0 {
+		bandwidth := opts.GetStoreBandwidth(sendStore).MaxSendBandwidthMBps
+		if count := oc.storeSendSnaps[sendStore]; bandwidth > 0 && count >= snapshotLimit(bandwidth, avgRegionSize) {
+			return errs.ErrStoreSnapshotBusy.FastGenByArgs(sendStore, count, "sent")
+		}
+	}
+	return nil
+}
+
+// checkSnapshotBytes returns ErrSnapshotBytesExceeded if the snapshots sent by
+// the operator make the snapshots in flight exceed the limit. An operator is
+// always allowed if there is no snapshot in flight.
+func (oc *OperatorController) checkSnapshotBytes(op *operator.Operator, region *core.RegionInfo) error {
+	limit := oc.cluster.GetOpts().GetMaxSnapshotBytesInFlight()
+	if limit == 0 || oc.snapshotBytes == 0 {
+		return nil
+	}
+	if bytes := snapshotBytes(op, region); bytes > 0 && oc.snapshotBytes+bytes > limit {
+		return errs.ErrSnapshotBytesExceeded.FastGenByArgs(oc.snapshotBytes, bytes, limit)
+	}
+	return nil
+}
+
+// snapshotBytes returns the estimated size of the snapshots sent by the
+// operator, that is the size of the region for each added peer.
+func snapshotBytes(op *operator.Operator, region *core.RegionInfo) uint64 {
+	_, recvStores := snapshotStores(op, region)
+	return uint64(len(recvStores)) * uint64(region.GetApproximateSize()) * units.MiB
+}
+
+// snapshotLimit returns the max number of the concurrent snapshots which can
+// be transferred with the bandwidth in MB/s, at least one snapshot is allowed.
+func snapshotLimit(bandwidthMBps float64, avgRegionSize int64) uint64 {
+	limit := uint64(bandwidthMBps / float64(avgRegionSize))
+	if limit < 1 {
+		return 1
+	}
+	return limit
+}
+
+// snapshotStores returns the store which sends the snapshots of the operator,
+// that is the leader of the region, and the stores which receive them. The
+// finished steps are skipped as their snapshots have been applied.
+func snapshotStores(op *operator.Operator, region *core.RegionInfo) (uint64, []uint64) {
+	var recvStores []uint64
+	for i := op.CurrentStepIndex(); i < op.Len(); i++ {
+		switch step := op.Step(i).(type) {
+		case operator.AddPeer:
+			recvStores = append(recvStores, step.ToStore)
+		case operator.AddLearner:
+			recvStores = append(recvStores, step.ToStore)
+		}
+	}
+	return region.GetLeader().GetStoreId(), recvStores
+}
+
+// targetStores returns the stores which the operator moves peers or leaders
+// into by the unfinished steps.
+func targetStores(op *operator.Operator) []uint64 {
+	var stores []uint64
+	add := func(storeID uint64) {
+		for _, id := range stores {
+			if id == storeID {
+				return
+			}
+		}
+		stores = append(stores, storeID)
+	}
+	for i := op.CurrentStepIndex(); i < op.Len(); i++ {
+		switch step := op.Step(i).(type) {
+		case operator.AddPeer:
+			add(step.ToStore)
+		case operator.AddLearner:
+			add(step.ToStore)
+		case operator.TransferLeader:
+			if len(step.ToStores) == 0 {
+				add(step.ToStore)
+			}
+			for _, storeID := range step.ToStores {
+				add(storeID)
+			}
+		}
+	}
+	return stores
+}
+
 func isHigherPriorityOperator(new, old *operator.Operator) bool {
 	return new.GetPriorityLevel() > old.GetPriorityLevel()
 }
@@ -469,6 +716,8 @@ func (oc *OperatorController) addOperatorLocked(op *operator.Operator) bool {
 		return false
 	}
 	oc.operators[regionID] = op
+	oc.dag.Add(op)
+	oc.publishStepStart(op, 0)
 	operatorCounter.WithLabelValues(op.Desc(), "start").Inc()
 	operatorWaitDuration.WithLabelValues(op.Desc()).Observe(op.ElapsedTime().Seconds())
 	opInfluence := NewTotalOpInfluence([]*operator.Operator{op}, oc.cluster)
@@ -535,6 +784,7 @@ func (oc *OperatorController) removeOperatorLocked(op *operator.Operator) bool {
 	regionID := op.RegionID()
 	if cur := oc.operators[regionID]; cur == op {
 		delete(oc.operators, regionID)
+		oc.dag.Remove(op)
 		oc.updateCounts(oc.operators)
 		operatorCounter.WithLabelValues(op.Desc(), "remove").Inc()
 		return true
@@ -569,6 +819,9 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 		for _, counter := range op.FinishedCounters {
 			counter.Inc()
 		}
+		if oc.cluster != nil {
+			oc.crossDCTraffic.record(oc.cluster, op, time.Now())
+		}
 	case operator.REPLACED:
 		log.Info("replace old operator",
 			zap.Uint64("region-id", op.RegionID()),
@@ -600,7 +853,85 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 		operatorCounter.WithLabelValues(op.Desc(), "cancel").Inc()
 	}
 
+	oc.resultRecorder.record(op, st)
 	oc.opRecords.Put(op)
+	oc.recordOperatorAudit(op)
+	oc.publishOperatorFinish(op)
+}
+
+func (oc *OperatorController) recordOperatorAudit(op *operator.Operator) {
+	if oc.cluster == nil {
+		return
+	}
+	// The capacity is applied lazily since it may be changed online.
+	if capacity := int(oc.cluster.GetOpts().GetOperatorAuditLogCapacity()); capacity != oc.auditLog.Capacity() {
+		oc.auditLog.Resize(capacity)
+	}
+	oc.auditLog.Record(op)
+}
+
+// GetEventHub returns the hub of the operator progress events, which includes
+// the events of the operator steps and the finished operators.
+func (oc *OperatorController) GetEventHub() *events.Hub {
+	return oc.eventHub
+}
+
+// publishOperatorFinish publishes the event of the finished operator, it is
+// also published to the cluster events if the cluster supports them.
+func (oc *OperatorController) publishOperatorFinish(op *operator.Operator) {
+	message := fmt.Sprintf("%s %s", op.Desc(), operator.OpStatusToString(op.Status()))
+	e := events.NewClusterEvent(events.OperatorFinish, message).WithRegion(op.RegionID())
+	oc.eventHub.Publish(e)
+	if c, ok := oc.cluster.(interface{ GetEventHub() *events.Hub }); ok {
+		c.GetEventHub().Publish(e)
+	}
+}
+
+// publishStepProgress publishes the events of the steps finished since the
+// step from, and the start of the next step.
+func (oc *OperatorController) publishStepProgress(op *operator.Operator, from int) {
+	to := op.CurrentStepIndex()
+	for i := from; i < to; i++ {
+		oc.eventHub.Publish(newStepEvent(events.OperatorStepFinish, op, i, op.GetStepFinishTime(i)))
+	}
+	if to > from && to < op.Len() {
+		oc.publishStepStart(op, to)
+	}
+}
+
+func (oc *OperatorController) publishStepStart(op *operator.Operator, i int) {
+	start := op.GetStartTime()
+	if i > 0 {
+		start = op.GetStepFinishTime(i - 1)
+	}
+	oc.eventHub.Publish(newStepEvent(events.OperatorStepStart, op, i, start))
+}
+
+func newStepEvent(typ events.EventType, op *operator.Operator, i int, t time.Time) *events.ClusterEvent {
+	step := op.Step(i)
+	return events.NewClusterEvent(typ, step.String()).WithRegion(op.RegionID()).WithStep(&events.OperatorStep{
+		Index:   i,
+		Type:    reflect.TypeOf(step).Name(),
+		StoreID: stepStoreID(step),
+		Elapsed: typeutil.NewDuration(t.Sub(op.GetStartTime())),
+	})
+}
+
+// stepStoreID returns the store which the step works on.
+func stepStoreID(step operator.OpStep) uint64 {
+	switch s := step.(type) {
+	case operator.TransferLeader:
+		return s.ToStore
+	case operator.AddPeer:
+		return s.ToStore
+	case operator.AddLearner:
+		return s.ToStore
+	case operator.PromoteLearner:
+		return s.ToStore
+	case operator.RemovePeer:
+		return s.FromStore
+	}
+	return 0
 }
 
 // GetOperatorStatus gets the operator and its status with the specify id.
@@ -773,11 +1104,47 @@ func (oc *OperatorController) updateCounts(operators map[uint64]*operator.Operat
 	for k := range oc.counts {
 		delete(oc.counts, k)
 	}
+	for k := range oc.storeCounts {
+		delete(oc.storeCounts, k)
+	}
+	for k := range oc.storeSendSnaps {
+		delete(oc.storeSendSnaps, k)
+	}
+	for k := range oc.storeRecvSnaps {
+		delete(oc.storeRecvSnaps, k)
+	}
+	oc.snapshotBytes = 0
 	for _, op := range operators {
 		oc.counts[op.SchedulerKind()]++
+		for _, storeID := range targetStores(op) {
+			oc.storeCounts[storeID]++
+		}
+		if oc.cluster == nil {
+			continue
+		}
+		region := oc.cluster.GetRegion(op.RegionID())
+		if region == nil {
+			continue
+		}
+		sendStore, recvStores := snapshotStores(op, region)
+		for _, storeID := range recvStores {
+			oc.storeRecvSnaps[storeID]++
+		}
+		if len(recvStores) > 0 {
+			oc.storeSendSnaps[sendStore]++
+		}
+		oc.snapshotBytes += snapshotBytes(op, region)
 	}
 }
 
+// SnapshotBytesInFlight returns the estimated size of the snapshots sent by
+// the running operators.
+func (oc *OperatorController) SnapshotBytesInFlight() uint64 {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.snapshotBytes
+}
+
 // OperatorCount gets the count of operators filtered by kind.
 // kind only has one OpKind.
 func (oc *OperatorController) OperatorCount(kind operator.OpKind) uint64 {
@@ -786,6 +1153,14 @@ func (oc *OperatorController) OperatorCount(kind operator.OpKind) uint64 {
 	return oc.counts[kind]
 }
 
+// StoreOperatorCount gets the count of operators moving peers or leaders into
+// the store.
+func (oc *OperatorController) StoreOperatorCount(storeID uint64) uint64 {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.storeCounts[storeID]
+}
+
 // GetOpInfluence gets OpInfluence.
 func (oc *OperatorController) GetOpInfluence(cluster Cluster) operator.OpInfluence {
 	influence := operator.OpInfluence{
@@ -845,7 +1220,11 @@ func NewTotalOpInfluence(operators []*operator.Operator, cluster Cluster) operat
 func (oc *OperatorController) SetOperator(op *operator.Operator) {
 	oc.Lock()
 	defer oc.Unlock()
+	if old, ok := oc.operators[op.RegionID()]; ok {
+		oc.dag.Remove(old)
+	}
 	oc.operators[op.RegionID()] = op
+	oc.dag.Add(op)
 	oc.updateCounts(oc.operators)
 }
 
@@ -23,10 +23,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -133,6 +137,129 @@ func (t *testOperatorControllerSuite) TestOperatorStatus(c *C) {
 	c.Assert(oc.GetOperatorStatus(2).Status, Equals, pdpb.OperatorStatus_SUCCESS)
 }
 
+func (t *testOperatorControllerSuite) TestOperatorResultStats(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(t.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 3)
+	tc.AddLeaderStore(2, 0)
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderRegion(i, 1, 2)
+	}
+	steps := []operator.OpStep{
+		operator.RemovePeer{FromStore: 2},
+		operator.AddPeer{ToStore: 2, PeerID: 4},
+	}
+	counterValue := func(counter *prometheus.CounterVec, typ, result string) float64 {
+		return testutil.ToFloat64(counter.WithLabelValues(typ, result))
+	}
+	successBefore := counterValue(operatorResultCounter, "test", OperatorResultSuccess)
+	stepSuccessBefore := counterValue(operatorStepResultCounter, "RemovePeer", OperatorResultSuccess)
+	stepTimeoutBefore := counterValue(operatorStepResultCounter, "RemovePeer", OperatorResultTimeout)
+
+	ops := make([]*operator.Operator, 0, 3)
+	for i := uint64(1); i <= 3; i++ {
+		op := operator.NewTestOperator(i, &metapb.RegionEpoch{}, operator.OpRegion, steps...)
+		c.Assert(op.Start(), IsTrue)
+		oc.SetOperator(op)
+		ops = append(ops, op)
+	}
+	// The operator of region 1 times out at the first step.
+	operator.SetOperatorStatusReachTime(ops[0], operator.STARTED, time.Now().Add(-10*time.Minute))
+	oc.Dispatch(tc.GetRegion(1), "test")
+	c.Assert(ops[0].Status(), Equals, operator.TIMEOUT)
+	// The operator of region 2 succeeds.
+	ApplyOperator(tc, ops[1])
+	oc.Dispatch(tc.GetRegion(2), "test")
+	c.Assert(ops[1].Status(), Equals, operator.SUCCESS)
+	// The operator of region 3 is canceled at the second step.
+	region3 := ApplyOperatorStep(tc.GetRegion(3), ops[2])
+	tc.PutRegion(region3)
+	oc.Dispatch(region3, "test")
+	c.Assert(oc.RemoveOperator(ops[2]), IsTrue)
+	c.Assert(ops[2].Status(), Equals, operator.CANCELED)
+
+	stats := oc.GetOperatorResultStats()
+	c.Assert(stats.Operators, DeepEquals, map[string]map[string]uint64{
+		"test": {OperatorResultSuccess: 1, OperatorResultTimeout: 1, OperatorResultCancelled: 1},
+	})
+	c.Assert(stats.Steps, DeepEquals, map[string]map[string]uint64{
+		"RemovePeer": {OperatorResultSuccess: 2, OperatorResultTimeout: 1},
+		"AddPeer":    {OperatorResultSuccess: 1, OperatorResultCancelled: 1},
+	})
+	c.Assert(counterValue(operatorResultCounter, "test", OperatorResultSuccess)-successBefore, Equals, 1.0)
+	c.Assert(counterValue(operatorStepResultCounter, "RemovePeer", OperatorResultSuccess)-stepSuccessBefore, Equals, 2.0)
+	c.Assert(counterValue(operatorStepResultCounter, "RemovePeer", OperatorResultTimeout)-stepTimeoutBefore, Equals, 1.0)
+
+	// The stats are copied.
+	stats.Operators["test"][OperatorResultSuccess] = 100
+	c.Assert(oc.GetOperatorResultStats().Operators["test"][OperatorResultSuccess], Equals, uint64(1))
+}
+
+func (t *testOperatorControllerSuite) TestCrossDCTraffic(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(t.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLabelsStore(1, 2, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 2, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(3, 0, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(4, 0, map[string]string{"zone": "z1"})
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+	crossDCBefore := testutil.ToFloat64(operatorCrossDCBytesCounter.WithLabelValues("z1->z2"))
+	sameDCBefore := testutil.ToFloat64(operatorSameDCBytesCounter)
+
+	// Region 1 moves a peer from z1 to z2, and region 2 moves a peer within z1.
+	for regionID, targetStore := range map[uint64]uint64{1: 3, 2: 4} {
+		op := operator.NewTestOperator(regionID, &metapb.RegionEpoch{}, operator.OpRegion,
+			operator.AddPeer{ToStore: targetStore, PeerID: 10 + regionID},
+			operator.RemovePeer{FromStore: 2},
+		)
+		op.ApproximateSize = 10
+		c.Assert(op.Start(), IsTrue)
+		oc.SetOperator(op)
+		ApplyOperator(tc, op)
+		oc.Dispatch(tc.GetRegion(regionID), "test")
+		c.Assert(op.Status(), Equals, operator.SUCCESS)
+	}
+	bytes := float64(10 * units.MiB)
+	c.Assert(testutil.ToFloat64(operatorCrossDCBytesCounter.WithLabelValues("z1->z2"))-crossDCBefore, Equals, bytes)
+	c.Assert(testutil.ToFloat64(operatorSameDCBytesCounter)-sameDCBefore, Equals, bytes)
+
+	traffic := oc.GetCrossDCTraffic()
+	c.Assert(traffic.History, HasLen, 1)
+	c.Assert(traffic.History[0].CrossDCBytes, Equals, uint64(10*units.MiB))
+	c.Assert(traffic.History[0].SameDCBytes, Equals, uint64(10*units.MiB))
+	c.Assert(traffic.CrossDCBytesRate, Equals, bytes/crossDCTrafficRateWindow.Seconds())
+	c.Assert(traffic.SameDCBytesRate, Equals, bytes/crossDCTrafficRateWindow.Seconds())
+}
+
+func (t *testOperatorControllerSuite) TestCrossDCTrafficRecorder(c *C) {
+	r := &crossDCTrafficRecorder{}
+	start := time.Unix(3600, 0)
+	r.add(start, true, 100)
+	r.add(start.Add(30*time.Second), false, 50)
+	r.add(start.Add(50*time.Minute), true, 300)
+
+	traffic := r.get(start.Add(50 * time.Minute))
+	c.Assert(traffic.History, DeepEquals, []CrossDCTrafficSample{
+		{Timestamp: 3600, CrossDCBytes: 100, SameDCBytes: 50},
+		{Timestamp: 3600 + 50*60, CrossDCBytes: 300},
+	})
+	// Only the samples in the last 5 minutes are counted in the rate.
+	c.Assert(traffic.CrossDCBytesRate, Equals, 1.0)
+	c.Assert(traffic.SameDCBytesRate, Equals, 0.0)
+
+	// The samples older than an hour are expired.
+	traffic = r.get(start.Add(70 * time.Minute))
+	c.Assert(traffic.History, HasLen, 1)
+	c.Assert(traffic.CrossDCBytesRate, Equals, 0.0)
+	r.add(start.Add(70*time.Minute), false, 10)
+	c.Assert(r.samples, HasLen, 2)
+}
+
 func (t *testOperatorControllerSuite) TestFastFailOperator(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(t.ctx, opt)
@@ -589,6 +716,293 @@ func (t *testOperatorControllerSuite) TestDispatchUnfinishedStep(c *C) {
 	}
 }
 
+func (t *testOperatorControllerSuite) TestRollbackOperator(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderStore(4, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	// Make sure the operator is not limited by the store limit.
+	for storeID := uint64(1); storeID <= 4; storeID++ {
+		tc.SetStoreLimit(storeID, storelimit.AddPeer, 6000)
+		tc.SetStoreLimit(storeID, storelimit.RemovePeer, 6000)
+	}
+	origin := tc.GetRegion(1)
+
+	steps := []operator.OpStep{
+		operator.AddLearner{ToStore: 3, PeerID: 3},
+		operator.AddLearner{ToStore: 4, PeerID: 4},
+		operator.RemovePeer{FromStore: 2, PeerID: 2},
+	}
+	op := operator.NewTestOperator(1, origin.GetRegionEpoch(), operator.OpRegion, steps...)
+	c.Assert(oc.AddOperator(op), IsTrue)
+
+	// The first step finishes.
+	region := ApplyOperatorStep(origin, op)
+	tc.PutRegion(region)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(op.Status(), Equals, operator.STARTED)
+	c.Assert(op.Step(1).IsFinish(region), IsFalse)
+
+	// The second step times out, the learner added by the first step is removed.
+	operator.SetOperatorStepFinishTime(op, 0, time.Now().Add(-10*time.Minute))
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(op.Status(), Equals, operator.TIMEOUT)
+	rollback := oc.GetOperator(1)
+	c.Assert(rollback, NotNil)
+	c.Assert(rollback.Desc(), Equals, "rollback-test")
+	c.Assert(rollback.Len(), Equals, 1)
+	c.Assert(rollback.Step(0), DeepEquals, operator.RemovePeer{FromStore: 3, PeerID: 3})
+
+	ApplyOperator(tc, rollback)
+	c.Assert(tc.GetRegion(1).GetPeers(), DeepEquals, origin.GetPeers())
+	c.Assert(tc.GetRegion(1).GetLeader(), DeepEquals, origin.GetLeader())
+	c.Assert(oc.RemoveOperator(rollback), IsTrue)
+
+	// The operator is only canceled if the step becomes stale, e.g. the target
+	// store is down.
+	origin = tc.GetRegion(1)
+	op = operator.NewTestOperator(1, origin.GetRegionEpoch(), operator.OpRegion, steps...)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	region = ApplyOperatorStep(origin, op)
+	tc.PutRegion(region)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	tc.SetStoreDown(4)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(op.Status(), Equals, operator.CANCELED)
+	c.Assert(oc.GetOperator(1), IsNil)
+}
+
+func (t *testOperatorControllerSuite) TestStoreBusy(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 10)
+	tc.AddLeaderStore(2, 0)
+	// Make sure the operators are not limited by the store limit.
+	tc.SetStoreLimit(2, storelimit.AddPeer, 6000)
+	c.Assert(tc.GetMaxOperatorsPerStore(), Equals, uint64(4))
+	addLearner := func(regionID uint64, kind operator.OpKind) *operator.Operator {
+		return operator.NewTestOperator(regionID, tc.GetRegion(regionID).GetRegionEpoch(), kind,
+			operator.AddLearner{ToStore: 2, PeerID: regionID + 100})
+	}
+
+	// Many schedulers try to add peers on store 2 concurrently.
+	ops := make([]*operator.Operator, 10)
+	for i := range ops {
+		regionID := uint64(i + 1)
+		tc.AddLeaderRegion(regionID, 1)
+		ops[i] = addLearner(regionID, operator.OpRegion)
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		added   []*operator.Operator
+		refused []uint64
+	)
+	for _, op := range ops {
+		wg.Add(1)
+		go func(op *operator.Operator) {
+			defer wg.Done()
+			ok := oc.AddOperator(op)
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				added = append(added, op)
+			} else {
+				refused = append(refused, op.RegionID())
+			}
+		}(op)
+	}
+	wg.Wait()
+	c.Assert(added, HasLen, 4)
+	c.Assert(refused, HasLen, 6)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(4))
+	c.Assert(oc.StoreOperatorCount(1), Equals, uint64(0))
+
+	// The fifth operator is refused because the store is busy.
+	op := addLearner(refused[0], operator.OpRegion)
+	c.Assert(errs.ErrStoreBusy.Equal(oc.checkStoreBusy(op)), IsTrue)
+	c.Assert(oc.AddOperator(op), IsFalse)
+	// The operators created by the administrator are not limited.
+	admin := addLearner(refused[0], operator.OpRegion|operator.OpAdmin)
+	c.Assert(oc.AddOperator(admin), IsTrue)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(5))
+	// The operators repairing the replicas are not limited either.
+	replica := addLearner(refused[1], operator.OpRegion|operator.OpReplica)
+	c.Assert(oc.checkStoreBusy(replica), IsNil)
+	c.Assert(oc.AddOperator(replica), IsTrue)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(6))
+
+	// Once the operators finish, the store can accept more operators.
+	c.Assert(oc.RemoveOperator(replica), IsTrue)
+	c.Assert(oc.RemoveOperator(admin), IsTrue)
+	c.Assert(oc.RemoveOperator(added[0]), IsTrue)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(3))
+	c.Assert(oc.AddOperator(addLearner(refused[0], operator.OpRegion)), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(refused[1], operator.OpRegion)), IsFalse)
+
+	// No limit if the option is 0.
+	tc.SetMaxOperatorsPerStore(0)
+	for _, regionID := range refused[1:] {
+		c.Assert(oc.AddOperator(addLearner(regionID, operator.OpRegion)), IsTrue)
+	}
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(9))
+}
+
+func (t *testOperatorControllerSuite) TestStoreBusyFinishedSteps(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	for id := uint64(1); id <= 3; id++ {
+		tc.AddLeaderStore(id, 0)
+		tc.SetStoreLimit(id, storelimit.AddPeer, 6000)
+	}
+	tc.AddLeaderRegion(1, 1)
+	op := operator.NewTestOperator(1, tc.GetRegion(1).GetRegionEpoch(), operator.OpRegion,
+		operator.AddLearner{ToStore: 2, PeerID: 102},
+		operator.AddLearner{ToStore: 3, PeerID: 103})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(1))
+	c.Assert(oc.StoreOperatorCount(3), Equals, uint64(1))
+
+	// The store of the finished step is not counted any more.
+	region := ApplyOperatorStep(tc.GetRegion(1), op)
+	tc.PutRegion(region)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(op.CurrentStepIndex(), Equals, 1)
+	c.Assert(oc.StoreOperatorCount(2), Equals, uint64(0))
+	c.Assert(oc.StoreOperatorCount(3), Equals, uint64(1))
+}
+
+func (t *testOperatorControllerSuite) TestStoreBandwidth(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.SetMaxOperatorsPerStore(0)
+	for id := uint64(1); id <= 3; id++ {
+		tc.AddLeaderStore(id, 0)
+		// Make sure the operators are not limited by the store limit.
+		tc.SetStoreLimit(id, storelimit.AddPeer, 6000)
+	}
+	for regionID := uint64(1); regionID <= 6; regionID++ {
+		tc.PutRegion(tc.AddLeaderRegion(regionID, 1).Clone(core.SetApproximateSize(50)))
+	}
+	c.Assert(tc.GetAverageRegionSize(), Equals, int64(50))
+	addLearner := func(regionID, storeID uint64) *operator.Operator {
+		return operator.NewTestOperator(regionID, tc.GetRegion(regionID).GetRegionEpoch(), operator.OpRegion,
+			operator.AddLearner{ToStore: storeID, PeerID: regionID + 100})
+	}
+
+	// Store 2 receives at most 2 snapshots of 50MB with 100MB/s.
+	tc.SetStoreBandwidth(2, 0, 100)
+	op1, op2 := addLearner(1, 2), addLearner(2, 2)
+	c.Assert(oc.AddOperator(op1), IsTrue)
+	c.Assert(oc.AddOperator(op2), IsTrue)
+	op := addLearner(3, 2)
+	c.Assert(errs.ErrStoreSnapshotBusy.Equal(oc.checkStoreBandwidth(op, tc.GetRegion(3))), IsTrue)
+	c.Assert(oc.AddOperator(op), IsFalse)
+	// The other stores are not limited.
+	c.Assert(oc.AddOperator(addLearner(3, 3)), IsTrue)
+
+	// Store 1 sends at most 2 snapshots of 50MB with 120MB/s, which is the
+	// leader of all the regions.
+	tc.SetStoreBandwidth(1, 120, 0)
+	c.Assert(oc.AddOperator(addLearner(4, 3)), IsFalse)
+
+	// Once the operators finish, the stores can transfer more snapshots.
+	c.Assert(oc.RemoveOperator(op1), IsTrue)
+	c.Assert(oc.RemoveOperator(op2), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(4, 2)), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(5, 3)), IsFalse)
+
+	// No limit if the bandwidth is 0.
+	tc.SetStoreBandwidth(1, 0, 0)
+	tc.SetStoreBandwidth(2, 0, 0)
+	c.Assert(oc.AddOperator(addLearner(5, 2)), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(6, 2)), IsTrue)
+}
+
+func (t *testOperatorControllerSuite) TestStoreBandwidthFinishedSteps(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.SetMaxOperatorsPerStore(0)
+	for id := uint64(1); id <= 3; id++ {
+		tc.AddLeaderStore(id, 0)
+		tc.SetStoreLimit(id, storelimit.AddPeer, 6000)
+	}
+	tc.PutRegion(tc.AddLeaderRegion(1, 1).Clone(core.SetApproximateSize(50)))
+	op := operator.NewTestOperator(1, tc.GetRegion(1).GetRegionEpoch(), operator.OpRegion,
+		operator.AddLearner{ToStore: 2, PeerID: 102},
+		operator.AddLearner{ToStore: 3, PeerID: 103})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.storeRecvSnaps[2], Equals, uint64(1))
+	c.Assert(oc.storeRecvSnaps[3], Equals, uint64(1))
+	c.Assert(oc.storeSendSnaps[1], Equals, uint64(1))
+
+	// The snapshot of the finished step is not counted any more.
+	region := ApplyOperatorStep(tc.GetRegion(1), op)
+	tc.PutRegion(region)
+	oc.Dispatch(region, DispatchFromHeartBeat)
+	c.Assert(op.CurrentStepIndex(), Equals, 1)
+	c.Assert(oc.storeRecvSnaps[2], Equals, uint64(0))
+	c.Assert(oc.storeRecvSnaps[3], Equals, uint64(1))
+	c.Assert(oc.storeSendSnaps[1], Equals, uint64(1))
+}
+
+func (t *testOperatorControllerSuite) TestSnapshotBytesInFlight(c *C) {
+	tc := mockcluster.NewCluster(t.ctx, config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.SetMaxOperatorsPerStore(0)
+	for id := uint64(1); id <= 4; id++ {
+		tc.AddLeaderStore(id, 0)
+		tc.SetStoreLimit(id, storelimit.AddPeer, 6000)
+	}
+	for regionID := uint64(1); regionID <= 6; regionID++ {
+		tc.PutRegion(tc.AddLeaderRegion(regionID, 1).Clone(core.SetApproximateSize(50)))
+	}
+	addLearner := func(regionID uint64, storeIDs ...uint64) *operator.Operator {
+		steps := make([]operator.OpStep, 0, len(storeIDs))
+		for _, storeID := range storeIDs {
+			steps = append(steps, operator.AddLearner{ToStore: storeID, PeerID: regionID*10 + storeID})
+		}
+		return operator.NewTestOperator(regionID, tc.GetRegion(regionID).GetRegionEpoch(), operator.OpRegion, steps...)
+	}
+
+	// No limit by default.
+	for regionID := uint64(1); regionID <= 3; regionID++ {
+		c.Assert(oc.AddOperator(addLearner(regionID, 2)), IsTrue)
+	}
+	c.Assert(oc.SnapshotBytesInFlight(), Equals, uint64(150*units.MiB))
+
+	// At most 200MB of the snapshots are in flight.
+	tc.SetMaxSnapshotBytesInFlight(200 * units.MiB)
+	c.Assert(oc.AddOperator(addLearner(4, 3)), IsTrue)
+	op := addLearner(5, 3)
+	c.Assert(errs.ErrSnapshotBytesExceeded.Equal(oc.checkSnapshotBytes(op, tc.GetRegion(5))), IsTrue)
+	c.Assert(oc.AddOperator(op), IsFalse)
+	c.Assert(oc.SnapshotBytesInFlight(), Equals, uint64(200*units.MiB))
+
+	// Once the operators finish, more snapshots can be sent.
+	for regionID := uint64(1); regionID <= 4; regionID++ {
+		c.Assert(oc.RemoveOperator(oc.GetOperator(regionID)), IsTrue)
+	}
+	c.Assert(oc.SnapshotBytesInFlight(), Equals, uint64(0))
+	c.Assert(oc.AddOperator(addLearner(5, 3, 4)), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(6, 2, 3, 4)), IsFalse)
+	c.Assert(oc.SnapshotBytesInFlight(), Equals, uint64(100*units.MiB))
+
+	// An operator is allowed if there is no snapshot in flight, even if it
+	// exceeds the limit.
+	c.Assert(oc.RemoveOperator(oc.GetOperator(5)), IsTrue)
+	c.Assert(oc.AddOperator(addLearner(6, 2, 3, 4)), IsTrue)
+}
+
 func newRegionInfo(id uint64, startKey, endKey string, size, keys int64, leader []uint64, peers ...[]uint64) *core.RegionInfo {
 	prs := make([]*metapb.Peer, 0, len(peers))
 	for _, peer := range peers {
@@ -622,6 +1036,8 @@ func (t *testOperatorControllerSuite) TestAddWaitingOperator(c *C) {
 	cluster.AddLabelsStore(1, 1, map[string]string{"host": "host1"})
 	cluster.AddLabelsStore(2, 1, map[string]string{"host": "host2"})
 	cluster.AddLabelsStore(3, 1, map[string]string{"host": "host3"})
+	// All the operators add peers on store 2, which is not limited here.
+	cluster.SetMaxOperatorsPerStore(0)
 	addPeerOp := func(i uint64) *operator.Operator {
 		start := fmt.Sprintf("%da", i)
 		end := fmt.Sprintf("%db", i)
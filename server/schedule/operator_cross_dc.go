@@ -0,0 +1,135 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+const (
+	// crossDCTrafficResolution is the time span of a traffic sample.
+	crossDCTrafficResolution = time.Minute
+	// crossDCTrafficHistory is how long the traffic samples are kept.
+	crossDCTrafficHistory = time.Hour
+	// crossDCTrafficRateWindow is the time span to calculate the current rate.
+	crossDCTrafficRateWindow = 5 * time.Minute
+)
+
+// CrossDCTrafficSample is the bytes moved by the operators finished in a minute.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type CrossDCTrafficSample struct {
+	// Timestamp is the unix timestamp in seconds at the start of the minute.
+	Timestamp    int64  `json:"timestamp"`
+	CrossDCBytes uint64 `json:"cross_dc_bytes"`
+	SameDCBytes  uint64 `json:"same_dc_bytes"`
+}
+
+// CrossDCTraffic is the data moved across and within the zones by the finished
+// operators.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type CrossDCTraffic struct {
+	// CrossDCBytesRate and SameDCBytesRate are the bytes per second moved in
+	// the last 5 minutes.
+	CrossDCBytesRate float64 `json:"cross_dc_bytes_rate"`
+	SameDCBytesRate  float64 `json:"same_dc_bytes_rate"`
+	// History is the bytes moved in each minute of the last hour from the
+	// oldest to the newest, the minutes without any move are omitted.
+	History []CrossDCTrafficSample `json:"history"`
+}
+
+// crossDCTrafficRecorder records the bytes moved by the finished operators.
+type crossDCTrafficRecorder struct {
+	mu      sync.RWMutex
+	samples []CrossDCTrafficSample
+}
+
+// record records the regions moved by the operator finished at now. A peer
+// moved between the stores with different zone labels is counted as cross
+// DC, and the moves between the stores without zone labels are ignored.
+func (r *crossDCTrafficRecorder) record(cluster Cluster, op *operator.Operator, now time.Time) {
+	bytes := uint64(op.ApproximateSize) * units.MiB
+	for _, history := range op.History() {
+		if history.Kind != core.RegionKind {
+			continue
+		}
+		sourceZone := getStoreZone(cluster, history.From)
+		targetZone := getStoreZone(cluster, history.To)
+		if sourceZone == "" || targetZone == "" {
+			continue
+		}
+		crossDC := sourceZone != targetZone
+		if crossDC {
+			operatorCrossDCBytesCounter.WithLabelValues(sourceZone + "->" + targetZone).Add(float64(bytes))
+		} else {
+			operatorSameDCBytesCounter.Add(float64(bytes))
+		}
+		r.add(now, crossDC, bytes)
+	}
+}
+
+func getStoreZone(cluster Cluster, storeID uint64) string {
+	if store := cluster.GetStore(storeID); store != nil {
+		return store.GetLabelValue(config.ZoneLabel)
+	}
+	return ""
+}
+
+func (r *crossDCTrafficRecorder) add(now time.Time, crossDC bool, bytes uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	timestamp := now.Truncate(crossDCTrafficResolution).Unix()
+	if len(r.samples) == 0 || r.samples[len(r.samples)-1].Timestamp != timestamp {
+		r.samples = append(r.samples, CrossDCTrafficSample{Timestamp: timestamp})
+	}
+	sample := &r.samples[len(r.samples)-1]
+	if crossDC {
+		sample.CrossDCBytes += bytes
+	} else {
+		sample.SameDCBytes += bytes
+	}
+	expired := now.Add(-crossDCTrafficHistory).Unix()
+	for len(r.samples) > 0 && r.samples[0].Timestamp < expired {
+		r.samples = r.samples[1:]
+	}
+}
+
+// get returns the traffic at now.
+func (r *crossDCTrafficRecorder) get(now time.Time) *CrossDCTraffic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	traffic := &CrossDCTraffic{History: []CrossDCTrafficSample{}}
+	expired := now.Add(-crossDCTrafficHistory).Unix()
+	rateStart := now.Add(-crossDCTrafficRateWindow).Unix()
+	var crossDCBytes, sameDCBytes uint64
+	for _, sample := range r.samples {
+		if sample.Timestamp < expired {
+			continue
+		}
+		traffic.History = append(traffic.History, sample)
+		if sample.Timestamp >= rateStart {
+			crossDCBytes += sample.CrossDCBytes
+			sameDCBytes += sample.SameDCBytes
+		}
+	}
+	traffic.CrossDCBytesRate = float64(crossDCBytes) / crossDCTrafficRateWindow.Seconds()
+	traffic.SameDCBytesRate = float64(sameDCBytes) / crossDCTrafficRateWindow.Seconds()
+	return traffic
+}
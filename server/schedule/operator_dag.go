@@ -0,0 +1,274 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// ConflictKind is the kind of the conflict between two operators.
+type ConflictKind int
+
+const (
+	// ConflictRegion means the operators involve the same region.
+	ConflictRegion ConflictKind = iota
+	// ConflictStore means the operators move the leaders or the peers of the
+	// same store in opposite directions.
+	ConflictStore
+)
+
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictRegion:
+		return "region"
+	case ConflictStore:
+		return "store"
+	}
+	return "unknown"
+}
+
+// ConflictReason describes why an operator conflicts with a pending operator.
+type ConflictReason struct {
+	Kind ConflictKind
+	// RegionID is set if the kind is ConflictRegion.
+	RegionID uint64
+	// StoreID is set if the kind is ConflictStore.
+	StoreID uint64
+	// Pending is the pending operator which the operator conflicts with.
+	Pending *operator.Operator
+}
+
+func (r ConflictReason) String() string {
+	switch r.Kind {
+	case ConflictRegion:
+		return fmt.Sprintf("region %d is involved in operator %s", r.RegionID, r.Pending)
+	case ConflictStore:
+		return fmt.Sprintf("store %d is moved in the opposite direction by operator %s", r.StoreID, r.Pending)
+	}
+	return fmt.Sprintf("conflict with operator %s", r.Pending)
+}
+
+// flowDirection is the direction in which an operator moves the leaders or the
+// peers of a store.
+type flowDirection int
+
+const (
+	leaderIn flowDirection = iota
+	leaderOut
+	peerIn
+	peerOut
+)
+
+func (d flowDirection) opposite() flowDirection {
+	switch d {
+	case leaderIn:
+		return leaderOut
+	case leaderOut:
+		return leaderIn
+	case peerIn:
+		return peerOut
+	default:
+		return peerIn
+	}
+}
+
+type storeFlow struct {
+	storeID   uint64
+	direction flowDirection
+}
+
+// opInvolvement records the regions and the store flows involved in an operator.
+type opInvolvement struct {
+	regions []uint64
+	flows   []storeFlow
+}
+
+func newOpInvolvement(op *operator.Operator) *opInvolvement {
+	inv := &opInvolvement{regions: []uint64{op.RegionID()}}
+	for i := 0; i < op.Len(); i++ {
+		switch step := op.Step(i).(type) {
+		case operator.TransferLeader:
+			inv.flows = append(inv.flows, storeFlow{step.FromStore, leaderOut})
+			if len(step.ToStores) == 0 {
+				inv.flows = append(inv.flows, storeFlow{step.ToStore, leaderIn})
+			}
+			for _, storeID := range step.ToStores {
+				inv.flows = append(inv.flows, storeFlow{storeID, leaderIn})
+			}
+		case operator.AddPeer:
+			inv.flows = append(inv.flows, storeFlow{step.ToStore, peerIn})
+		case operator.AddLearner:
+			inv.flows = append(inv.flows, storeFlow{step.ToStore, peerIn})
+		case operator.RemovePeer:
+			inv.flows = append(inv.flows, storeFlow{step.FromStore, peerOut})
+		case operator.MergeRegion:
+			for _, region := range []uint64{step.FromRegion.GetId(), step.ToRegion.GetId()} {
+				if region != op.RegionID() {
+					inv.regions = append(inv.regions, region)
+				}
+			}
+		}
+	}
+	return inv
+}
+
+// OperatorDAG tracks the regions and the stores involved in the pending
+// operators, which is used to refuse the operators conflicting with the pending
+// ones. An operator conflicts with a pending operator if
+//   - they involve the same region, unless the operator has a higher priority and
+//     will replace the pending one. The merge operators involve both regions.
+//   - they are generated by different schedulers and move the leaders or the
+//     peers of the same store in opposite directions, e.g. one scheduler moves
+//     leaders into a store while another one moves leaders out of it. The
+//     operators which repair replicas, merge or split regions, or are created by
+//     the administrator are not restricted by the stores. The operator controller
+//     only refuses these operators if enable-store-flow-conflict is true.
+type OperatorDAG struct {
+	mu        sync.RWMutex
+	operators map[*operator.Operator]*opInvolvement
+	regions   map[uint64]opSet
+	stores    map[storeFlow]opSet
+}
+
+type opSet map[*operator.Operator]struct{}
+
+// NewOperatorDAG creates an empty OperatorDAG.
+func NewOperatorDAG() *OperatorDAG {
+	return &OperatorDAG{
+		operators: make(map[*operator.Operator]*opInvolvement),
+		regions:   make(map[uint64]opSet),
+		stores:    make(map[storeFlow]opSet),
+	}
+}
+
+// Add adds a pending operator.
+func (dag *OperatorDAG) Add(op *operator.Operator) {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+	if _, ok := dag.operators[op]; ok {
+		return
+	}
+	inv := newOpInvolvement(op)
+	dag.operators[op] = inv
+	for _, regionID := range inv.regions {
+		if dag.regions[regionID] == nil {
+			dag.regions[regionID] = make(opSet)
+		}
+		dag.regions[regionID][op] = struct{}{}
+	}
+	for _, flow := range inv.flows {
+		if dag.stores[flow] == nil {
+			dag.stores[flow] = make(opSet)
+		}
+		dag.stores[flow][op] = struct{}{}
+	}
+}
+
+// Remove removes a pending operator.
+func (dag *OperatorDAG) Remove(op *operator.Operator) {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+	dag.removeLocked(op)
+}
+
+func (dag *OperatorDAG) removeLocked(op *operator.Operator) {
+	inv, ok := dag.operators[op]
+	if !ok {
+		return
+	}
+	delete(dag.operators, op)
+	for _, regionID := range inv.regions {
+		if delete(dag.regions[regionID], op); len(dag.regions[regionID]) == 0 {
+			delete(dag.regions, regionID)
+		}
+	}
+	for _, flow := range inv.flows {
+		if delete(dag.stores[flow], op); len(dag.stores[flow]) == 0 {
+			delete(dag.stores, flow)
+		}
+	}
+}
+
+// GC removes the operators which have finished, expired or been canceled.
+func (dag *OperatorDAG) GC() {
+	dag.mu.Lock()
+	defer dag.mu.Unlock()
+	for op := range dag.operators {
+		if op.IsEnd() {
+			dag.removeLocked(op)
+		}
+	}
+}
+
+// Len returns the number of the pending operators.
+func (dag *OperatorDAG) Len() int {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+	return len(dag.operators)
+}
+
+// Conflicts returns the reasons why the operator conflicts with the pending
+// operators. It returns nil if there is no conflict.
+func (dag *OperatorDAG) Conflicts(op *operator.Operator) []ConflictReason {
+	dag.mu.RLock()
+	defer dag.mu.RUnlock()
+	var reasons []ConflictReason
+	inv := newOpInvolvement(op)
+	for _, regionID := range inv.regions {
+		for pending := range dag.regions[regionID] {
+			if pending == op || pending.IsEnd() {
+				continue
+			}
+			// The operator with a higher priority replaces the old one, the
+			// merge operators always replace the ones of both regions.
+			if isHigherPriorityOperator(op, pending) {
+				continue
+			}
+			reasons = append(reasons, ConflictReason{Kind: ConflictRegion, RegionID: regionID, Pending: pending})
+		}
+	}
+	if !isStoreRestricted(op) {
+		return reasons
+	}
+	for _, flow := range inv.flows {
+		for pending := range dag.stores[storeFlow{flow.storeID, flow.direction.opposite()}] {
+			if pending == op || pending.IsEnd() || pending.Desc() == op.Desc() ||
+				!isStoreRestricted(pending) || isHigherPriorityOperator(op, pending) {
+				continue
+			}
+			reasons = append(reasons, ConflictReason{Kind: ConflictStore, StoreID: flow.storeID, Pending: pending})
+		}
+	}
+	return reasons
+}
+
+// regionConflicts returns the reasons of the ConflictRegion kind.
+func regionConflicts(reasons []ConflictReason) []ConflictReason {
+	res := reasons[:0]
+	for _, reason := range reasons {
+		if reason.Kind == ConflictRegion {
+			res = append(res, reason)
+		}
+	}
+	return res
+}
+
+// isStoreRestricted checks if the operator is restricted by the store flows.
+func isStoreRestricted(op *operator.Operator) bool {
+	return op.Kind()&(operator.OpAdmin|operator.OpReplica|operator.OpMerge|operator.OpSplit) == 0
+}
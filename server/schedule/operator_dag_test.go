@@ -0,0 +1,157 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/hbstream"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testOperatorDAGSuite{})
+
+type testOperatorDAGSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *testOperatorDAGSuite) SetUpSuite(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *testOperatorDAGSuite) TearDownSuite(c *C) {
+	s.cancel()
+}
+
+func newTransferLeaderTestOperator(desc string, regionID, from, to uint64) *operator.Operator {
+	return operator.NewOperator(desc, desc, regionID, &metapb.RegionEpoch{}, operator.OpLeader, 0,
+		operator.TransferLeader{FromStore: from, ToStore: to})
+}
+
+func (s *testOperatorDAGSuite) TestRegionConflict(c *C) {
+	dag := NewOperatorDAG()
+	op1 := newTransferLeaderTestOperator("balance-leader", 1, 1, 2)
+	dag.Add(op1)
+	c.Assert(dag.Len(), Equals, 1)
+
+	op2 := operator.NewTestOperator(1, &metapb.RegionEpoch{}, operator.OpRegion, operator.RemovePeer{FromStore: 3})
+	reasons := dag.Conflicts(op2)
+	c.Assert(reasons, HasLen, 1)
+	c.Assert(reasons[0].Kind, Equals, ConflictRegion)
+	c.Assert(reasons[0].RegionID, Equals, uint64(1))
+	c.Assert(reasons[0].Pending, Equals, op1)
+	// The operator with a higher priority replaces the pending one.
+	op2.SetPriorityLevel(core.HighPriority)
+	c.Assert(dag.Conflicts(op2), HasLen, 0)
+
+	// The merge operator involves both regions.
+	merge := operator.NewTestOperator(2, &metapb.RegionEpoch{}, operator.OpMerge, operator.MergeRegion{
+		FromRegion: &metapb.Region{Id: 2},
+		ToRegion:   &metapb.Region{Id: 3},
+	})
+	dag.Add(merge)
+	reasons = dag.Conflicts(operator.NewTestOperator(3, &metapb.RegionEpoch{}, operator.OpRegion, operator.RemovePeer{FromStore: 3}))
+	c.Assert(reasons, HasLen, 1)
+	c.Assert(reasons[0].RegionID, Equals, uint64(3))
+	c.Assert(reasons[0].Pending, Equals, merge)
+
+	dag.Remove(merge)
+	dag.Remove(op1)
+	c.Assert(dag.Len(), Equals, 0)
+	c.Assert(dag.regions, HasLen, 0)
+	c.Assert(dag.stores, HasLen, 0)
+}
+
+func (s *testOperatorDAGSuite) TestStoreConflict(c *C) {
+	dag := NewOperatorDAG()
+	// balance-leader moves leaders into store 2, while evict-leader moves
+	// leaders out of it.
+	op1 := newTransferLeaderTestOperator("balance-leader", 1, 1, 2)
+	dag.Add(op1)
+	op2 := newTransferLeaderTestOperator("evict-leader", 2, 2, 3)
+	reasons := dag.Conflicts(op2)
+	c.Assert(reasons, HasLen, 1)
+	c.Assert(reasons[0].Kind, Equals, ConflictStore)
+	c.Assert(reasons[0].StoreID, Equals, uint64(2))
+	c.Assert(reasons[0].Pending, Equals, op1)
+	c.Assert(reasons[0].String(), Matches, "store 2 .*")
+
+	// The operators of the same scheduler, moving in the same direction or
+	// created by the administrator do not conflict.
+	c.Assert(dag.Conflicts(newTransferLeaderTestOperator("balance-leader", 2, 2, 3)), HasLen, 0)
+	c.Assert(dag.Conflicts(newTransferLeaderTestOperator("evict-leader", 2, 3, 2)), HasLen, 0)
+	admin := operator.NewOperator("admin-transfer-leader", "admin", 2, &metapb.RegionEpoch{}, operator.OpLeader|operator.OpAdmin, 0,
+		operator.TransferLeader{FromStore: 2, ToStore: 3})
+	c.Assert(dag.Conflicts(admin), HasLen, 0)
+
+	// The peers are moved in opposite directions.
+	op3 := operator.NewOperator("balance-region", "balance-region", 3, &metapb.RegionEpoch{}, operator.OpRegion, 0,
+		operator.AddLearner{ToStore: 4, PeerID: 10}, operator.PromoteLearner{ToStore: 4, PeerID: 10}, operator.RemovePeer{FromStore: 1})
+	dag.Add(op3)
+	reasons = dag.Conflicts(operator.NewOperator("shuffle-region", "shuffle-region", 4, &metapb.RegionEpoch{}, operator.OpRegion, 0,
+		operator.AddLearner{ToStore: 1, PeerID: 11}, operator.PromoteLearner{ToStore: 1, PeerID: 11}, operator.RemovePeer{FromStore: 4}))
+	c.Assert(reasons, HasLen, 2)
+
+	// The ended operators are garbage-collected.
+	c.Assert(op1.Cancel(), IsTrue)
+	c.Assert(dag.Conflicts(op2), HasLen, 0)
+	c.Assert(op3.Start(), IsTrue)
+	dag.GC()
+	c.Assert(dag.Len(), Equals, 1)
+}
+
+func (s *testOperatorDAGSuite) TestOperatorController(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(s.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(s.ctx, tc, stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 1)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 2, 1, 3)
+	newOp := func(desc string, regionID, from, to uint64) *operator.Operator {
+		region := tc.GetRegion(regionID)
+		return operator.NewOperator(desc, desc, regionID, region.GetRegionEpoch(), operator.OpLeader, 0,
+			operator.TransferLeader{FromStore: from, ToStore: to})
+	}
+
+	// Two intentionally conflicting operators.
+	op1 := newOp("balance-leader", 1, 1, 2)
+	c.Assert(oc.AddOperator(op1), IsTrue)
+	op2 := newOp("evict-leader", 2, 2, 3)
+	c.Assert(oc.GetOperatorDAG().Conflicts(op2), HasLen, 1)
+	// The store flow conflict is disabled by default.
+	c.Assert(tc.IsStoreFlowConflictEnabled(), IsFalse)
+	c.Assert(oc.AddOperator(op2), IsTrue)
+	c.Assert(oc.RemoveOperator(op2), IsTrue)
+	tc.SetEnableStoreFlowConflict(true)
+	op2 = newOp("evict-leader", 2, 2, 3)
+	c.Assert(oc.AddOperator(op2), IsFalse)
+	c.Assert(oc.GetOperator(2), IsNil)
+
+	// The operator is removed from the graph after it ends.
+	c.Assert(oc.RemoveOperator(op1), IsTrue)
+	c.Assert(oc.GetOperatorDAG().Len(), Equals, 0)
+	op2 = newOp("evict-leader", 2, 2, 3)
+	c.Assert(oc.AddOperator(op2), IsTrue)
+	c.Assert(oc.GetOperatorDAG().Len(), Equals, 1)
+}
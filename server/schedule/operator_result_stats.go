@@ -0,0 +1,123 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// The results of the finished operators and steps.
+const (
+	OperatorResultSuccess   = "success"
+	OperatorResultCancelled = "cancelled"
+	OperatorResultTimeout   = "timeout"
+	OperatorResultError     = "error"
+)
+
+// operatorResult maps the end status of an operator to its result.
+func operatorResult(st operator.OpStatus) string {
+	switch st {
+	case operator.SUCCESS:
+		return OperatorResultSuccess
+	case operator.CANCELED, operator.REPLACED:
+		return OperatorResultCancelled
+	case operator.TIMEOUT, operator.EXPIRED:
+		return OperatorResultTimeout
+	default:
+		return OperatorResultError
+	}
+}
+
+// OperatorResultStats is the count of the finished operators and steps by
+// type and result.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type OperatorResultStats struct {
+	// Operators is keyed by the operator description, then the result.
+	Operators map[string]map[string]uint64 `json:"operators"`
+	// Steps is keyed by the step type, then the result. The steps after the
+	// one where an operator ends are not counted since they never start.
+	Steps map[string]map[string]uint64 `json:"steps"`
+}
+
+func newOperatorResultStats() *OperatorResultStats {
+	return &OperatorResultStats{
+		Operators: make(map[string]map[string]uint64),
+		Steps:     make(map[string]map[string]uint64),
+	}
+}
+
+func incResultCount(counts map[string]map[string]uint64, typ, result string) {
+	if counts[typ] == nil {
+		counts[typ] = make(map[string]uint64)
+	}
+	counts[typ][result]++
+}
+
+// operatorResultRecorder records the results of the finished operators.
+type operatorResultRecorder struct {
+	mu    sync.RWMutex
+	stats *OperatorResultStats
+}
+
+func newOperatorResultRecorder() *operatorResultRecorder {
+	return &operatorResultRecorder{stats: newOperatorResultStats()}
+}
+
+// record records the operator which ended with the status st. The finished
+// steps succeeded, and the step being executed shares the result of the
+// operator.
+func (r *operatorResultRecorder) record(op *operator.Operator, st operator.OpStatus) {
+	result := operatorResult(st)
+	operatorResultCounter.WithLabelValues(op.Desc(), result).Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	incResultCount(r.stats.Operators, op.Desc(), result)
+	current := op.CurrentStepIndex()
+	for i := 0; i < op.Len() && i <= current; i++ {
+		stepResult := OperatorResultSuccess
+		if i == current {
+			stepResult = result
+		}
+		stepType := reflect.TypeOf(op.Step(i)).Name()
+		operatorStepResultCounter.WithLabelValues(stepType, stepResult).Inc()
+		incResultCount(r.stats.Steps, stepType, stepResult)
+	}
+}
+
+// get returns a copy of the stats.
+func (r *operatorResultRecorder) get() *OperatorResultStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := newOperatorResultStats()
+	for typ, results := range r.stats.Operators {
+		stats.Operators[typ] = copyResultCounts(results)
+	}
+	for typ, results := range r.stats.Steps {
+		stats.Steps[typ] = copyResultCounts(results)
+	}
+	return stats
+}
+
+func copyResultCounts(results map[string]uint64) map[string]uint64 {
+	res := make(map[string]uint64, len(results))
+	for result, count := range results {
+		res[result] = count
+	}
+	return res
+}
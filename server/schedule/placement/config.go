@@ -79,6 +79,19 @@ func (c *ruleConfig) getGroup(id string) *RuleGroup {
 	return &RuleGroup{ID: id}
 }
 
+// clone returns a copy of the configurations. The rules are cloned so that
+// the copy can be adjusted without touching the original ones.
+func (c *ruleConfig) clone() *ruleConfig {
+	clone := newRuleConfig()
+	for key, r := range c.rules {
+		clone.rules[key] = r.Clone()
+	}
+	for id, g := range c.groups {
+		clone.groups[id] = g
+	}
+	return clone
+}
+
 func (c *ruleConfig) beginPatch() *ruleConfigPatch {
 	return &ruleConfigPatch{
 		c:   c,
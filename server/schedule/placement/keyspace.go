@@ -0,0 +1,73 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"bytes"
+
+	"github.com/tikv/pd/pkg/codec"
+)
+
+const (
+	// MaxKeyspaceID is the max ID of a keyspace, the ID takes 3 bytes in the keys.
+	MaxKeyspaceID = 1<<24 - 1
+	// keyspaceTxnPrefix is the prefix of the transactional keys of a keyspace.
+	keyspaceTxnPrefix = 'x'
+)
+
+func keyspacePrefix(id uint32) []byte {
+	return []byte{keyspaceTxnPrefix, byte(id >> 16), byte(id >> 8), byte(id)}
+}
+
+// KeyspaceKeyRange returns the encoded key range of the transactional data of
+// the keyspace, which is the key range of the keyspace rules by default.
+func KeyspaceKeyRange(id uint32) (startKey, endKey []byte) {
+	end := []byte{keyspaceTxnPrefix + 1}
+	if id < MaxKeyspaceID {
+		end = keyspacePrefix(id + 1)
+	}
+	return codec.EncodeBytes(keyspacePrefix(id)), codec.EncodeBytes(end)
+}
+
+// KeyspaceOfRange returns the keyspace whose transactional key range contains
+// the encoded key range.
+func KeyspaceOfRange(startKey, endKey []byte) (uint32, bool) {
+	_, key, err := codec.DecodeBytes(startKey)
+	if err != nil || len(key) < len(keyspacePrefix(0)) || key[0] != keyspaceTxnPrefix {
+		return 0, false
+	}
+	id := uint32(key[1])<<16 | uint32(key[2])<<8 | uint32(key[3])
+	_, end := KeyspaceKeyRange(id)
+	if len(endKey) == 0 || bytes.Compare(endKey, end) > 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// filterKeyspaceRules makes the keyspace rules take precedence over the
+// cluster-wide rules, the cluster-wide rules are not applied to a range if
+// there are keyspace rules for it.
+func filterKeyspaceRules(rules []*Rule) []*Rule {
+	var keyspaceRules []*Rule
+	for _, rule := range rules {
+		if rule.KeyspaceID > 0 {
+			keyspaceRules = append(keyspaceRules, rule)
+		}
+	}
+	if len(keyspaceRules) == 0 {
+		return rules
+	}
+	return keyspaceRules
+}
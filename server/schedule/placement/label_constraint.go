@@ -66,6 +66,24 @@ func (c *LabelConstraint) MatchStore(store *core.StoreInfo) bool {
 	return false
 }
 
+// MatchLabels checks if the labels, which may have multiple values for a key,
+// match the constraint. `in` and `exists` match if any value matches, while
+// `notIn` and `notExists` match if none of the values matches.
+func (c *LabelConstraint) MatchLabels(labels map[string][]string) bool {
+	values := labels[c.Key]
+	switch c.Op {
+	case In:
+		return slice.AnyOf(values, func(i int) bool { return slice.Contains(c.Values, values[i]) })
+	case NotIn:
+		return slice.NoneOf(values, func(i int) bool { return slice.Contains(c.Values, values[i]) })
+	case Exists:
+		return len(values) > 0
+	case NotExists:
+		return len(values) == 0
+	}
+	return false
+}
+
 // For backward compatibility. Need to remove later.
 var legacyExclusiveLabels = []string{core.EngineKey, "exclusive"}
 
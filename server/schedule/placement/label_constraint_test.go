@@ -65,6 +65,31 @@ func (s *testLabelConstraintsSuite) TestLabelConstraint(c *C) {
 	}
 }
 
+func (s *testLabelConstraintsSuite) TestMatchLabels(c *C) {
+	labels := map[string][]string{
+		"zone": {"zone1", "zone2"},
+		"disk": {"ssd"},
+	}
+	cases := []struct {
+		constraint LabelConstraint
+		match      bool
+	}{
+		{LabelConstraint{Key: "zone", Op: "in", Values: []string{"zone2", "zone3"}}, true},
+		{LabelConstraint{Key: "zone", Op: "in", Values: []string{"zone3"}}, false},
+		{LabelConstraint{Key: "rack", Op: "in", Values: []string{"rack1"}}, false},
+		{LabelConstraint{Key: "zone", Op: "notIn", Values: []string{"zone1"}}, false},
+		{LabelConstraint{Key: "zone", Op: "notIn", Values: []string{"zone3"}}, true},
+		{LabelConstraint{Key: "rack", Op: "notIn", Values: []string{"rack1"}}, true},
+		{LabelConstraint{Key: "disk", Op: "exists"}, true},
+		{LabelConstraint{Key: "rack", Op: "exists"}, false},
+		{LabelConstraint{Key: "disk", Op: "notExists"}, false},
+		{LabelConstraint{Key: "rack", Op: "notExists"}, true},
+	}
+	for _, t := range cases {
+		c.Assert(t.constraint.MatchLabels(labels), Equals, t.match)
+	}
+}
+
 func (s *testLabelConstraintsSuite) TestLabelConstraints(c *C) {
 	stores := []map[string]string{
 		{},                                       // 1
@@ -18,7 +18,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"sort"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 )
 
@@ -62,14 +64,28 @@ type Rule struct {
 	StartKeyHex      string            `json:"start_key"`                   // hex format start key, for marshal/unmarshal
 	EndKey           []byte            `json:"-"`                           // range end key
 	EndKeyHex        string            `json:"end_key"`                     // hex format end key, for marshal/unmarshal
+	KeyspaceID       uint32            `json:"keyspace_id,omitempty"`       // the keyspace whose data the rule places, 0 means the rule is cluster-wide
 	Role             PeerRoleType      `json:"role"`                        // expected role of the peers
 	Count            int               `json:"count"`                       // expected count of the peers
 	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"` // used to select stores to place peers
+	StoreLabelMatch  []LabelConstraint `json:"store_label_match,omitempty"` // used to match regions by the union of the labels of their stores
 	LocationLabels   []string          `json:"location_labels,omitempty"`   // used to make peers isolated physically
 	IsolationLevel   string            `json:"isolation_level,omitempty"`   // used to isolate replicas explicitly and forcibly
+	Supersedes       []string          `json:"supersedes,omitempty"`        // IDs of the rules in the same group which are skipped when the rule matches
+	ActiveFrom       string            `json:"active_from,omitempty"`       // the time of day in "HH:MM[:SS]" the rule becomes active every day
+	ActiveUntil      string            `json:"active_until,omitempty"`      // the time of day in "HH:MM[:SS]" the rule becomes inactive every day
+	ActiveTimezone   string            `json:"active_timezone,omitempty"`   // the IANA time zone of ActiveFrom and ActiveUntil, UTC if it is empty
 	Version          uint64            `json:"version,omitempty"`           // only set at runtime, add 1 each time rules updated, begin from 0.
 	CreateTimestamp  uint64            `json:"create_timestamp,omitempty"`  // only set at runtime, recorded rule create timestamp
 	group            *RuleGroup        // only set at runtime, no need to {,un}marshal or persist.
+	activePeriod     *activePeriod     // only set at runtime, parsed by adjustRule.
+}
+
+// activePeriod is the parsed daily time window of a rule.
+type activePeriod struct {
+	// from and until are the seconds since midnight.
+	from, until int
+	loc         *time.Location
 }
 
 func (r *Rule) String() string {
@@ -83,9 +99,64 @@ func (r *Rule) Clone() *Rule {
 	json.Unmarshal([]byte(r.String()), &clone)
 	clone.StartKey = append(r.StartKey[:0:0], r.StartKey...)
 	clone.EndKey = append(r.EndKey[:0:0], r.EndKey...)
+	clone.activePeriod = r.activePeriod
 	return &clone
 }
 
+func secondsOfDay(t time.Time) int {
+	hour, min, sec := t.Clock()
+	return hour*3600 + min*60 + sec
+}
+
+// parseTimeOfDay parses the time of day in "HH:MM[:SS]" to the seconds since
+// midnight.
+func parseTimeOfDay(s string) (int, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return secondsOfDay(t), nil
+		}
+	}
+	return 0, errors.Errorf("invalid time of day %s", s)
+}
+
+// parseActivePeriod parses the active period of the rule. It returns nil if
+// the rule has no active period.
+func (r *Rule) parseActivePeriod() (*activePeriod, error) {
+	if r.ActiveFrom == "" && r.ActiveUntil == "" {
+		return nil, nil
+	}
+	from, err := parseTimeOfDay(r.ActiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseTimeOfDay(r.ActiveUntil)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(r.ActiveTimezone)
+	if err != nil {
+		return nil, err
+	}
+	return &activePeriod{from: from, until: until, loc: loc}, nil
+}
+
+// IsActive checks if the rule is active at the time. ActiveFrom and
+// ActiveUntil are interpreted in ActiveTimezone. If ActiveUntil is before
+// ActiveFrom, the active period crosses midnight. The rule is always active if
+// they are not set or the same. The period is parsed by adjustRule, the rule
+// is always active before that.
+func (r *Rule) IsActive(t time.Time) bool {
+	p := r.activePeriod
+	if p == nil || p.from == p.until {
+		return true
+	}
+	now := secondsOfDay(t.In(p.loc))
+	if p.from < p.until {
+		return now >= p.from && now < p.until
+	}
+	return now >= p.from || now < p.until
+}
+
 // Key returns (groupID, ID) as the global unique key of a rule.
 func (r *Rule) Key() [2]string {
 	return [2]string{r.GroupID, r.ID}
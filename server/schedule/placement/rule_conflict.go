@@ -0,0 +1,263 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RuleConflict is a set of rules which can not be satisfied simultaneously.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RuleConflict struct {
+	// Rules are the `group/id` of the conflicting rules.
+	Rules  []string `json:"rules"`
+	Reason string   `json:"reason"`
+}
+
+// DetectConflicts checks if the rules, after they are inserted or updated,
+// conflict with each other or with the current rules. The rules of the same
+// role in the same group which are applied to the same key range are regarded
+// as placing the same peers, so their label constraints must be matched by a
+// store together. A rule whose own label constraints contradict is a conflict
+// too.
+// Only the conflicts involving the given rules are returned.
+func (m *RuleManager) DetectConflicts(rules []*Rule) []RuleConflict {
+	m.RLock()
+	all := make(map[[2]string]*Rule, len(m.ruleConfig.rules)+len(rules))
+	for key, r := range m.ruleConfig.rules {
+		all[key] = r
+	}
+	m.RUnlock()
+	updated := make(map[[2]string]struct{}, len(rules))
+	for _, r := range rules {
+		all[r.Key()] = r
+		updated[r.Key()] = struct{}{}
+	}
+
+	groups := make(map[string][]*conflictRule)
+	for key, r := range all {
+		cr, ok := newConflictRule(r)
+		if !ok {
+			// The invalid keys are reported by the rule validation.
+			continue
+		}
+		_, cr.updated = updated[key]
+		groups[r.GroupID] = append(groups[r.GroupID], cr)
+	}
+	groupIDs := make([]string, 0, len(groups))
+	for id := range groups {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Strings(groupIDs)
+
+	d := &conflictDetector{reported: make(map[string]struct{})}
+	for _, id := range groupIDs {
+		d.detectGroup(groups[id])
+	}
+	return d.conflicts
+}
+
+// conflictRule is a rule with its decoded key range.
+type conflictRule struct {
+	*Rule
+	startKey, endKey []byte
+	updated          bool
+}
+
+func newConflictRule(r *Rule) (*conflictRule, bool) {
+	startKey, err := hex.DecodeString(r.StartKeyHex)
+	if err != nil {
+		return nil, false
+	}
+	endKey, err := hex.DecodeString(r.EndKeyHex)
+	if err != nil {
+		return nil, false
+	}
+	return &conflictRule{Rule: r, startKey: startKey, endKey: endKey}, true
+}
+
+func (r *conflictRule) contains(key []byte) bool {
+	return bytes.Compare(r.startKey, key) <= 0 && (len(r.endKey) == 0 || bytes.Compare(key, r.endKey) < 0)
+}
+
+type conflictDetector struct {
+	conflicts []RuleConflict
+	reported  map[string]struct{}
+}
+
+// detectGroup checks the rules of a group. Since the key range shared by some
+// rules always begins at the start key of one of them, the rules applied to
+// the start key of every rule are checked.
+func (d *conflictDetector) detectGroup(rules []*conflictRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Index != rules[j].Index {
+			return rules[i].Index < rules[j].Index
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	for _, point := range rules {
+		var applied []*conflictRule
+		for _, r := range rules {
+			if !r.contains(point.startKey) {
+				continue
+			}
+			if r.Override {
+				// The rule disables all the rules before it in the group.
+				applied = applied[:0]
+			}
+			applied = append(applied, r)
+		}
+		applied = filterKeyspaceConflictRules(applied)
+		byRole := make(map[PeerRoleType][]*conflictRule)
+		var roles []PeerRoleType
+		for _, r := range applied {
+			if _, ok := byRole[r.Role]; !ok {
+				roles = append(roles, r.Role)
+			}
+			byRole[r.Role] = append(byRole[r.Role], r)
+		}
+		for _, role := range roles {
+			d.detectRules(byRole[role])
+		}
+	}
+}
+
+// filterKeyspaceConflictRules drops the cluster-wide rules if there are
+// keyspace rules, which are applied instead of them.
+func filterKeyspaceConflictRules(rules []*conflictRule) []*conflictRule {
+	var keyspaceRules []*conflictRule
+	for _, r := range rules {
+		if r.KeyspaceID > 0 {
+			keyspaceRules = append(keyspaceRules, r)
+		}
+	}
+	if len(keyspaceRules) == 0 {
+		return rules
+	}
+	return keyspaceRules
+}
+
+// detectRules checks the rules placing the same peers. The rules conflicting
+// by themselves and the conflicting pairs are reported first, the rules are
+// checked altogether only if there is neither of them, e.g. `in [a, b]`,
+// `in [b, c]` and `in [c, a]` do not conflict in pairs but do altogether.
+func (d *conflictDetector) detectRules(rules []*conflictRule) {
+	found := false
+	for i := range rules {
+		found = d.detect(rules[i:i+1]) || found
+	}
+	if found {
+		return
+	}
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			found = d.detect([]*conflictRule{rules[i], rules[j]}) || found
+		}
+	}
+	if !found && len(rules) > 2 {
+		d.detect(rules)
+	}
+}
+
+// detect reports the rules if their label constraints conflict, it returns
+// true if they conflict.
+func (d *conflictDetector) detect(rules []*conflictRule) bool {
+	var constraints []LabelConstraint
+	updated := false
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		constraints = append(constraints, r.LabelConstraints...)
+		updated = updated || r.updated
+		names = append(names, r.GroupID+"/"+r.ID)
+	}
+	reason := conflictReason(constraints)
+	if len(reason) == 0 {
+		return false
+	}
+	if !updated {
+		return true
+	}
+	sort.Strings(names)
+	key := strings.Join(names, ",")
+	if _, ok := d.reported[key]; !ok {
+		d.reported[key] = struct{}{}
+		d.conflicts = append(d.conflicts, RuleConflict{Rules: names, Reason: reason})
+	}
+	return true
+}
+
+// conflictReason explains why no store can match all the label constraints,
+// it returns an empty string if the constraints can be matched.
+func conflictReason(constraints []LabelConstraint) string {
+	byKey := make(map[string][]LabelConstraint)
+	var keys []string
+	for _, c := range constraints {
+		if _, ok := byKey[c.Key]; !ok {
+			keys = append(keys, c.Key)
+		}
+		byKey[c.Key] = append(byKey[c.Key], c)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var (
+			values            map[string]struct{} // nil means any value
+			exists, notExists bool
+			notIn             = make(map[string]struct{})
+			descs             []string
+		)
+		for _, c := range byKey[key] {
+			if len(c.Values) > 0 {
+				descs = append(descs, fmt.Sprintf("%s %v", c.Op, c.Values))
+			} else {
+				descs = append(descs, string(c.Op))
+			}
+			switch c.Op {
+			case In:
+				exists = true
+				allowed := make(map[string]struct{})
+				for _, v := range c.Values {
+					if _, ok := values[v]; values == nil || ok {
+						allowed[v] = struct{}{}
+					}
+				}
+				values = allowed
+			case NotIn:
+				for _, v := range c.Values {
+					notIn[v] = struct{}{}
+				}
+			case Exists:
+				exists = true
+			case NotExists:
+				notExists = true
+			}
+		}
+		if exists && notExists {
+			return fmt.Sprintf("label %s is required to both exist and not exist by %s", key, strings.Join(descs, ", "))
+		}
+		if values != nil {
+			for v := range notIn {
+				delete(values, v)
+			}
+			if len(values) == 0 {
+				return fmt.Sprintf("no value of label %s matches all of %s", key, strings.Join(descs, ", "))
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/storage"
+)
+
+var _ = Suite(&testRuleConflictSuite{})
+
+type testRuleConflictSuite struct {
+	manager *RuleManager
+}
+
+func (s *testRuleConflictSuite) SetUpTest(c *C) {
+	s.manager = NewRuleManager(storage.NewStorageWithMemoryBackend(), nil, nil)
+	c.Assert(s.manager.Initialize(3, []string{"zone", "rack", "host"}), IsNil)
+}
+
+func newConflictTestRule(id string, start, end string, constraints ...LabelConstraint) *Rule {
+	return &Rule{GroupID: "g", ID: id, StartKeyHex: start, EndKeyHex: end, Role: Follower, Count: 1, LabelConstraints: constraints}
+}
+
+func (s *testRuleConflictSuite) conflictRules(rules ...*Rule) [][]string {
+	var res [][]string
+	for _, conflict := range s.manager.DetectConflicts(rules) {
+		res = append(res, conflict.Rules)
+	}
+	return res
+}
+
+func (s *testRuleConflictSuite) TestDirectConflict(c *C) {
+	rackA := LabelConstraint{Key: "rack", Op: In, Values: []string{"A"}}
+	rackB := LabelConstraint{Key: "rack", Op: In, Values: []string{"B"}}
+	conflicts := s.manager.DetectConflicts([]*Rule{newConflictTestRule("1", "", "", rackA), newConflictTestRule("2", "", "", rackB)})
+	c.Assert(conflicts, HasLen, 1)
+	c.Assert(conflicts[0].Rules, DeepEquals, []string{"g/1", "g/2"})
+	c.Assert(conflicts[0].Reason, Equals, "no value of label rack matches all of in [A], in [B]")
+
+	// in vs notIn, exists vs notExists
+	c.Assert(s.conflictRules(
+		newConflictTestRule("1", "", "", rackA),
+		newConflictTestRule("2", "", "", LabelConstraint{Key: "rack", Op: NotIn, Values: []string{"A"}}),
+	), DeepEquals, [][]string{{"g/1", "g/2"}})
+	c.Assert(s.conflictRules(
+		newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: Exists}),
+		newConflictTestRule("2", "", "", LabelConstraint{Key: "rack", Op: NotExists}),
+	), DeepEquals, [][]string{{"g/1", "g/2"}})
+	// A rule contradicting itself.
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "", rackA, rackB)), DeepEquals, [][]string{{"g/1"}})
+
+	// The satisfiable constraints.
+	c.Assert(s.conflictRules(
+		newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"A", "B"}}),
+		newConflictTestRule("2", "", "", LabelConstraint{Key: "rack", Op: NotIn, Values: []string{"A"}}),
+		newConflictTestRule("3", "", "", LabelConstraint{Key: "zone", Op: NotExists}),
+	), HasLen, 0)
+	// The different roles, groups and key ranges.
+	learner := newConflictTestRule("2", "", "", rackB)
+	learner.Role = Learner
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "", rackA), learner), HasLen, 0)
+	other := newConflictTestRule("2", "", "", rackB)
+	other.GroupID = "other"
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "", rackA), other), HasLen, 0)
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "10", rackA), newConflictTestRule("2", "10", "", rackB)), HasLen, 0)
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "11", rackA), newConflictTestRule("2", "10", "", rackB)), HasLen, 1)
+	// The rule overriding the other.
+	override := newConflictTestRule("2", "", "", rackB)
+	override.Override = true
+	c.Assert(s.conflictRules(newConflictTestRule("1", "", "", rackA), override), HasLen, 0)
+}
+
+func (s *testRuleConflictSuite) TestCircularConflict(c *C) {
+	// Every two of the rules can be satisfied, but the three can not.
+	rules := []*Rule{
+		newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"A", "B"}}),
+		newConflictTestRule("2", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"B", "C"}}),
+		newConflictTestRule("3", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"C", "A"}}),
+	}
+	c.Assert(s.conflictRules(rules[:2]...), HasLen, 0)
+	c.Assert(s.conflictRules(rules...), DeepEquals, [][]string{{"g/1", "g/2", "g/3"}})
+	// The three rules only meet in [20, 30).
+	rules[0].StartKeyHex, rules[0].EndKeyHex = "10", "30"
+	rules[1].StartKeyHex, rules[1].EndKeyHex = "20", "40"
+	rules[2].StartKeyHex, rules[2].EndKeyHex = "", "30"
+	c.Assert(s.conflictRules(rules...), DeepEquals, [][]string{{"g/1", "g/2", "g/3"}})
+	rules[2].EndKeyHex = "20"
+	c.Assert(s.conflictRules(rules...), HasLen, 0)
+}
+
+func (s *testRuleConflictSuite) TestMultiRuleConflict(c *C) {
+	// The rule conflicts with two rules for different labels.
+	c.Assert(s.conflictRules(
+		newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"A"}},
+			LabelConstraint{Key: "zone", Op: NotExists}),
+		newConflictTestRule("2", "", "", LabelConstraint{Key: "rack", Op: NotIn, Values: []string{"A"}}),
+		newConflictTestRule("3", "", "", LabelConstraint{Key: "zone", Op: In, Values: []string{"z1"}}),
+	), DeepEquals, [][]string{{"g/1", "g/2"}, {"g/1", "g/3"}})
+
+	// The rule conflicts with the current rules.
+	c.Assert(s.manager.SetRule(newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"A"}})), IsNil)
+	c.Assert(s.manager.SetRule(newConflictTestRule("2", "", "", LabelConstraint{Key: "host", Op: In, Values: []string{"h1"}})), IsNil)
+	c.Assert(s.conflictRules(
+		newConflictTestRule("3", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"B"}},
+			LabelConstraint{Key: "host", Op: NotIn, Values: []string{"h1"}}),
+	), DeepEquals, [][]string{{"g/1", "g/3"}, {"g/2", "g/3"}})
+	// The rule updated resolves the conflict.
+	c.Assert(s.conflictRules(
+		newConflictTestRule("3", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"B"}}),
+		newConflictTestRule("1", "", "", LabelConstraint{Key: "rack", Op: In, Values: []string{"A", "B"}}),
+	), HasLen, 0)
+}
@@ -65,6 +65,9 @@ type ruleContainer interface {
 // buildRuleList builds the applied ruleList for the give rules
 // rules indicates the map (rule's GroupID, ID) => rule
 func buildRuleList(rules ruleContainer) (ruleList, error) {
+	if err := checkSupersedesCycle(rules); err != nil {
+		return ruleList{}, err
+	}
 	builder := rangelist.NewBuilder()
 	builder.SetCompareFunc(func(a, b interface{}) int {
 		return compareRule(a.(*Rule), b.(*Rule))
@@ -99,7 +102,7 @@ func buildRuleList(rules ruleContainer) (ruleList, error) {
 			rules[i] = data[i].(*Rule)
 		}
 
-		applyRules := prepareRulesForApply(rules)
+		applyRules := filterKeyspaceRules(prepareRulesForApply(rules))
 		if err := checkApplyRules(applyRules); err != nil {
 			return ruleList{}, errs.ErrBuildRuleList.FastGenByArgs(fmt.Sprintf("%s for range {%s, %s}",
 				err,
@@ -23,11 +23,13 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/storage/endpoint"
@@ -48,6 +50,8 @@ type RuleManager struct {
 	storeSetInformer core.StoreSetInformer
 	cache            *RegionRuleFitCacheManager
 	opt              *config.PersistOptions
+	// now is used to check if the rules are active, it is replaced in tests.
+	now func() time.Time
 }
 
 // NewRuleManager creates a RuleManager instance.
@@ -58,6 +62,7 @@ func NewRuleManager(storage endpoint.RuleStorage, storeSetInformer core.StoreSet
 		opt:              opt,
 		ruleConfig:       newRuleConfig(),
 		cache:            NewRegionRuleFitCacheManager(),
+		now:              time.Now,
 	}
 }
 
@@ -167,6 +172,19 @@ func (m *RuleManager) adjustRule(r *Rule, groupID string) (err error) {
 	if len(r.EndKey) > 0 && bytes.Compare(r.EndKey, r.StartKey) <= 0 {
 		return errs.ErrRuleContent.FastGenByArgs("endKey should be greater than startKey")
 	}
+	if r.KeyspaceID > 0 {
+		if r.KeyspaceID > MaxKeyspaceID {
+			return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid keyspace ID %d", r.KeyspaceID))
+		}
+		// The rule places the whole keyspace if its key range is not specified.
+		start, end := KeyspaceKeyRange(r.KeyspaceID)
+		if len(r.StartKey) == 0 && len(r.EndKey) == 0 {
+			r.StartKey, r.EndKey = start, end
+			r.StartKeyHex, r.EndKeyHex = hex.EncodeToString(start), hex.EncodeToString(end)
+		} else if bytes.Compare(r.StartKey, start) < 0 || len(r.EndKey) == 0 || bytes.Compare(r.EndKey, end) > 0 {
+			return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("key range should be in keyspace %d", r.KeyspaceID))
+		}
+	}
 
 	if m.keyType == core.Table.String() || m.keyType == core.Txn.String() {
 		if len(r.StartKey) > 0 {
@@ -203,9 +221,20 @@ func (m *RuleManager) adjustRule(r *Rule, groupID string) (err error) {
 	if r.Role == Leader && r.Count > 1 {
 		return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("define multiple leaders by count %d", r.Count))
 	}
-	for _, c := range r.LabelConstraints {
-		if !validateOp(c.Op) {
-			return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid op %s", c.Op))
+	if (r.ActiveFrom == "") != (r.ActiveUntil == "") {
+		return errs.ErrRuleContent.FastGenByArgs("active_from and active_until should be set together")
+	}
+	if r.ActiveFrom == "" && r.ActiveTimezone != "" {
+		return errs.ErrRuleContent.FastGenByArgs("active_timezone is set without active_from and active_until")
+	}
+	if r.activePeriod, err = r.parseActivePeriod(); err != nil {
+		return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid active period, %v", err))
+	}
+	for _, constraints := range [][]LabelConstraint{r.LabelConstraints, r.StoreLabelMatch} {
+		for _, c := range constraints {
+			if !validateOp(c.Op) {
+				return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid op %s", c.Op))
+			}
 		}
 	}
 
@@ -245,6 +274,37 @@ func (m *RuleManager) SetRule(rule *Rule) error {
 	return nil
 }
 
+// SimulateRule returns a RuleManager which has the rule inserted or updated
+// on the current rules, the current rules are neither changed nor persisted.
+// The returned RuleManager is only used to fit the regions, it should not be
+// modified.
+func (m *RuleManager) SimulateRule(rule *Rule) (*RuleManager, error) {
+	if err := m.adjustRule(rule, ""); err != nil {
+		return nil, err
+	}
+	m.RLock()
+	sim := &RuleManager{
+		initialized:      true,
+		ruleConfig:       m.ruleConfig.clone(),
+		keyType:          m.keyType,
+		storeSetInformer: m.storeSetInformer,
+		cache:            NewRegionRuleFitCacheManager(),
+		opt:              m.opt,
+		now:              m.now,
+	}
+	m.RUnlock()
+	p := sim.beginPatch()
+	p.setRule(rule)
+	p.adjust()
+	ruleList, err := buildRuleList(p)
+	if err != nil {
+		return nil, err
+	}
+	p.commit()
+	sim.ruleList = ruleList
+	return sim, nil
+}
+
 // DeleteRule removes a Rule.
 func (m *RuleManager) DeleteRule(group, id string) error {
 	m.Lock()
@@ -291,6 +351,37 @@ func (m *RuleManager) GetRulesByGroup(group string) []*Rule {
 	return rules
 }
 
+// GetRulesByKeyspace returns sorted rules of a keyspace.
+func (m *RuleManager) GetRulesByKeyspace(keyspaceID uint32) []*Rule {
+	m.RLock()
+	defer m.RUnlock()
+	var rules []*Rule
+	for _, r := range m.ruleConfig.rules {
+		if r.KeyspaceID == keyspaceID {
+			rules = append(rules, r.Clone())
+		}
+	}
+	sortRules(rules)
+	return rules
+}
+
+// DeleteKeyspaceRules removes all the rules of a keyspace.
+func (m *RuleManager) DeleteKeyspaceRules(keyspaceID uint32) error {
+	m.Lock()
+	defer m.Unlock()
+	p := m.beginPatch()
+	for k, r := range m.ruleConfig.rules {
+		if r.KeyspaceID == keyspaceID {
+			p.deleteRule(k[0], k[1])
+		}
+	}
+	if err := m.tryCommitPatch(p); err != nil {
+		return err
+	}
+	log.Info("keyspace placement rules are removed", zap.Uint32("keyspace-id", keyspaceID))
+	return nil
+}
+
 // GetRulesByKey returns sorted rules that affects a key.
 func (m *RuleManager) GetRulesByKey(key []byte) []*Rule {
 	m.RLock()
@@ -304,16 +395,17 @@ func (m *RuleManager) GetRulesByKey(key []byte) []*Rule {
 }
 
 // GetRulesForApplyRegion returns the rules list that should be applied to a region.
+// The rules not active now are excluded.
 func (m *RuleManager) GetRulesForApplyRegion(region *core.RegionInfo) []*Rule {
 	m.RLock()
 	defer m.RUnlock()
-	return m.ruleList.getRulesForApplyRegion(region.GetStartKey(), region.GetEndKey())
+	return filterActiveRules(m.ruleList.getRulesForApplyRegion(region.GetStartKey(), region.GetEndKey()), m.now())
 }
 
 // FitRegion fits a region to the rules it matches.
 func (m *RuleManager) FitRegion(storeSet StoreSet, region *core.RegionInfo) *RegionFit {
 	regionStores := getStoresByRegion(storeSet, region)
-	rules := m.GetRulesForApplyRegion(region)
+	rules := filterSupersededRules(filterRulesByStoreLabels(m.GetRulesForApplyRegion(region), regionStores))
 	if m.opt.IsPlacementRulesCacheEnabled() {
 		if ok, fit := m.cache.CheckAndGetCache(region, rules, regionStores); fit != nil && ok {
 			return fit
@@ -702,6 +794,42 @@ func (m *RuleManager) SetKeyType(h string) *RuleManager {
 	return m
 }
 
+// filterRulesByStoreLabels filters out the rules whose store label match is
+// not satisfied by the union of the labels of the region stores.
+func filterRulesByStoreLabels(rules []*Rule, regionStores []*core.StoreInfo) []*Rule {
+	var labels map[string][]string
+	filtered := rules[:0:0]
+	for _, rule := range rules {
+		if len(rule.StoreLabelMatch) > 0 {
+			if labels == nil {
+				labels = core.UnionStoreLabels(regionStores)
+			}
+			if slice.AllOf(rule.StoreLabelMatch, func(i int) bool { return rule.StoreLabelMatch[i].MatchLabels(labels) }) {
+				filtered = append(filtered, rule)
+			}
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	return filtered
+}
+
+// filterActiveRules excludes the rules not active at the time. The rules are
+// kept as they are if the active ones can not make up a raft group, which is
+// safer than removing all the voters at night.
+func filterActiveRules(rules []*Rule, t time.Time) []*Rule {
+	active := rules[:0:0]
+	for _, rule := range rules {
+		if rule.IsActive(t) {
+			active = append(active, rule)
+		}
+	}
+	if len(active) == len(rules) || checkApplyRules(active) != nil {
+		return rules
+	}
+	return active
+}
+
 func getStoresByRegion(storeSet StoreSet, region *core.RegionInfo) []*core.StoreInfo {
 	r := make([]*core.StoreInfo, 0, len(region.GetPeers()))
 	for _, peer := range region.GetPeers() {
@@ -16,10 +16,13 @@ package placement
 
 import (
 	"encoding/hex"
+	"fmt"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/storage"
 	"github.com/tikv/pd/server/storage/endpoint"
@@ -394,6 +397,91 @@ func (s *testManagerSuite) TestCheckApplyRules(c *C) {
 	c.Assert(err, ErrorMatches, "needs at least one leader or voter")
 }
 
+func (s *testManagerSuite) TestStoreLabelMatch(c *C) {
+	manager := NewRuleManager(s.store, nil, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone"}), IsNil)
+	c.Assert(manager.SetRule(&Rule{GroupID: "pd", ID: "prod", Role: Learner, Count: 1,
+		StoreLabelMatch: []LabelConstraint{{Key: "env", Op: In, Values: []string{"prod"}}}}), IsNil)
+	c.Assert(manager.SetRule(&Rule{GroupID: "pd", ID: "invalid", Role: Learner, Count: 1,
+		StoreLabelMatch: []LabelConstraint{{Key: "env", Op: "invalid"}}}), NotNil)
+
+	stores := core.NewStoresInfo()
+	stores.SetStore(core.NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1", "env": "prod"}))
+	stores.SetStore(core.NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z2", "env": "test"}))
+	stores.SetStore(core.NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z3"}))
+	newRegion := func(storeIDs ...uint64) *core.RegionInfo {
+		meta := &metapb.Region{Id: 1}
+		for _, id := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id, StoreId: id})
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	ruleIDs := func(fit *RegionFit) []string {
+		var ids []string
+		for _, rf := range fit.RuleFits {
+			ids = append(ids, rf.Rule.ID)
+		}
+		return ids
+	}
+
+	// The rule applies to the regions which have replicas on the prod stores.
+	c.Assert(ruleIDs(manager.FitRegion(stores, newRegion(1, 2, 3))), DeepEquals, []string{"default", "prod"})
+	c.Assert(ruleIDs(manager.FitRegion(stores, newRegion(2, 3))), DeepEquals, []string{"default"})
+}
+
+func (s *testManagerSuite) TestActiveRules(c *C) {
+	const timezone = "Asia/Shanghai"
+	loc, err := time.LoadLocation(timezone)
+	c.Assert(err, IsNil)
+	c.Assert(s.manager.SetRule(&Rule{GroupID: "pd", ID: "dr", Role: Follower, Count: 3,
+		ActiveFrom: "09:00", ActiveUntil: "18:00", ActiveTimezone: timezone}), IsNil)
+	c.Assert(s.manager.SetRule(&Rule{GroupID: "pd", ID: "night", Role: Follower, Count: 1,
+		ActiveFrom: "18:00:00", ActiveUntil: "09:00:00", ActiveTimezone: timezone}), IsNil)
+	for _, rule := range []*Rule{
+		{ActiveFrom: "18:00"},
+		{ActiveTimezone: timezone},
+		{ActiveFrom: "18:00", ActiveUntil: "25:00"},
+		{ActiveFrom: "6pm", ActiveUntil: "09:00"},
+		{ActiveFrom: "18:00", ActiveUntil: "09:00", ActiveTimezone: "Mars/Olympus"},
+	} {
+		rule.GroupID, rule.ID, rule.Role, rule.Count = "pd", "invalid", Follower, 1
+		c.Assert(s.manager.SetRule(rule), NotNil)
+	}
+	// The rules survive the persistence.
+	rule := s.manager.GetRule("pd", "dr")
+	c.Assert(rule.ActiveFrom, Equals, "09:00")
+	c.Assert(rule.ActiveUntil, Equals, "18:00")
+	c.Assert(rule.ActiveTimezone, Equals, timezone)
+	// The rules without the active period do not have the fields.
+	c.Assert(s.manager.GetRule("pd", "default").String(), Not(Matches), ".*active_.*")
+
+	region := core.NewRegionInfo(&metapb.Region{Id: 1}, nil)
+	ruleIDs := func(now time.Time) []string {
+		s.manager.now = func() time.Time { return now }
+		var ids []string
+		for _, rule := range s.manager.GetRulesForApplyRegion(region) {
+			ids = append(ids, rule.ID)
+		}
+		return ids
+	}
+	// 9 AM and 11 PM in the time zone of the rules.
+	c.Assert(ruleIDs(time.Date(2022, 6, 1, 9, 0, 0, 0, loc)), DeepEquals, []string{"default", "dr"})
+	c.Assert(ruleIDs(time.Date(2022, 6, 1, 23, 0, 0, 0, loc)), DeepEquals, []string{"default", "night"})
+	// The time zone of the time does not matter.
+	c.Assert(ruleIDs(time.Date(2022, 6, 1, 1, 0, 0, 0, time.UTC)), DeepEquals, []string{"default", "dr"})
+
+	// The rules are kept as they are if the active ones can not make up a raft group.
+	c.Assert(s.manager.SetRule(&Rule{GroupID: "pd", ID: "default", Role: Voter, Count: 3,
+		ActiveFrom: "09:00", ActiveUntil: "18:00", ActiveTimezone: timezone}), IsNil)
+	c.Assert(ruleIDs(time.Date(2022, 6, 1, 23, 0, 0, 0, loc)), DeepEquals, []string{"default", "dr", "night"})
+
+	// The active period is parsed again when the rules are loaded.
+	manager := NewRuleManager(s.store, nil, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone"}), IsNil)
+	c.Assert(manager.GetRule("pd", "dr").IsActive(time.Date(2022, 6, 1, 9, 0, 0, 0, loc)), IsTrue)
+	c.Assert(manager.GetRule("pd", "dr").IsActive(time.Date(2022, 6, 1, 23, 0, 0, 0, loc)), IsFalse)
+}
+
 func (s *testManagerSuite) dhex(hk string) []byte {
 	k, err := hex.DecodeString(hk)
 	if err != nil {
@@ -401,3 +489,105 @@ func (s *testManagerSuite) dhex(hk string) []byte {
 	}
 	return k
 }
+
+func (s *testManagerSuite) TestKeyspaceRules(c *C) {
+	manager := NewRuleManager(s.store, nil, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone"}), IsNil)
+	stores := core.NewStoresInfo()
+	for id := uint64(1); id <= 6; id++ {
+		zone := "eu-west"
+		if id > 3 {
+			zone = "us-east"
+		}
+		stores.SetStore(core.NewStoreInfoWithLabel(id, 0, map[string]string{"zone": zone}))
+	}
+	keyspaceRule := func(keyspaceID uint32, zone string) *Rule {
+		return &Rule{GroupID: "tenant", ID: fmt.Sprint(keyspaceID), KeyspaceID: keyspaceID, Role: Voter, Count: 3,
+			LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{zone}}}}
+	}
+	c.Assert(manager.SetRules([]*Rule{keyspaceRule(1, "eu-west"), keyspaceRule(2, "us-east")}), IsNil)
+	// The rule places the whole keyspace by default.
+	start, end := KeyspaceKeyRange(1)
+	rule := manager.GetRule("tenant", "1")
+	c.Assert(rule.StartKey, DeepEquals, start)
+	c.Assert(rule.EndKey, DeepEquals, end)
+	c.Assert(manager.GetRulesByKeyspace(2), HasLen, 1)
+
+	newRegion := func(keyspaceID uint32, storeIDs ...uint64) *core.RegionInfo {
+		prefix := keyspacePrefix(keyspaceID)
+		meta := &metapb.Region{
+			Id:       1,
+			StartKey: codec.EncodeBytes(append(prefix, 'a')),
+			EndKey:   codec.EncodeBytes(append(prefix, 'b')),
+		}
+		for _, id := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id, StoreId: id})
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	// The keyspace rules take precedence over the default rule.
+	rules := manager.GetRulesForApplyRegion(newRegion(1, 1, 2, 3))
+	c.Assert(rules, HasLen, 1)
+	c.Assert(rules[0].ID, Equals, "1")
+	// The regions of the keyspaces are placed on the different stores.
+	c.Assert(manager.FitRegion(stores, newRegion(1, 1, 2, 3)).IsSatisfied(), IsTrue)
+	c.Assert(manager.FitRegion(stores, newRegion(1, 4, 5, 6)).IsSatisfied(), IsFalse)
+	c.Assert(manager.FitRegion(stores, newRegion(2, 4, 5, 6)).IsSatisfied(), IsTrue)
+	c.Assert(manager.FitRegion(stores, newRegion(2, 1, 2, 3)).IsSatisfied(), IsFalse)
+	// The other keyspaces follow the default rule.
+	c.Assert(manager.GetRulesForApplyRegion(newRegion(3, 1, 5, 6))[0].ID, Equals, "default")
+
+	// The key range of the rule must be in the keyspace.
+	rule = keyspaceRule(1, "eu-west")
+	rule.StartKeyHex, rule.EndKeyHex = hex.EncodeToString(start), ""
+	c.Assert(manager.SetRule(rule), ErrorMatches, ".*key range should be in keyspace 1.*")
+	c.Assert(manager.SetRule(keyspaceRule(MaxKeyspaceID+1, "eu-west")), ErrorMatches, ".*invalid keyspace ID.*")
+
+	c.Assert(manager.DeleteKeyspaceRules(1), IsNil)
+	c.Assert(manager.GetRulesByKeyspace(1), HasLen, 0)
+	c.Assert(manager.GetRulesForApplyRegion(newRegion(1, 1, 2, 3))[0].ID, Equals, "default")
+}
+
+func (s *testManagerSuite) TestSupersedes(c *C) {
+	manager := NewRuleManager(s.store, nil, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone"}), IsNil)
+	stores := core.NewStoresInfo()
+	stores.SetStore(core.NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1", "env": "prod"}))
+	stores.SetStore(core.NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z2"}))
+	stores.SetStore(core.NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z3"}))
+	newRegion := func(storeIDs ...uint64) *core.RegionInfo {
+		meta := &metapb.Region{Id: 1}
+		for _, id := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id, StoreId: id})
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	ruleIDs := func(fit *RegionFit) []string {
+		var ids []string
+		for _, rf := range fit.RuleFits {
+			ids = append(ids, rf.Rule.ID)
+		}
+		return ids
+	}
+
+	c.Assert(manager.SetRules([]*Rule{
+		{GroupID: "pd", ID: "prod", Role: Learner, Count: 1, Supersedes: []string{"learner1", "learner2"},
+			StoreLabelMatch: []LabelConstraint{{Key: "env", Op: In, Values: []string{"prod"}}}},
+		{GroupID: "pd", ID: "learner1", Role: Learner, Count: 1, Supersedes: []string{"learner3"}},
+		{GroupID: "pd", ID: "learner2", Role: Learner, Count: 1},
+		{GroupID: "pd", ID: "learner3", Role: Learner, Count: 1},
+	}), IsNil)
+	// The matching rule suppresses the rules it supersedes, and the rule
+	// superseded by a skipped rule is applied.
+	c.Assert(ruleIDs(manager.FitRegion(stores, newRegion(1, 2, 3))), DeepEquals, []string{"default", "learner3", "prod"})
+	// The rule does not match the region.
+	c.Assert(ruleIDs(manager.FitRegion(stores, newRegion(2, 3))), DeepEquals, []string{"default", "learner1", "learner2"})
+
+	// The supersedes graph can not have cycles.
+	err := manager.SetRule(&Rule{GroupID: "pd", ID: "learner3", Role: Learner, Count: 1, Supersedes: []string{"prod"}})
+	c.Assert(err, ErrorMatches, ".*supersedes cycle pd/learner1 -> pd/learner3 -> pd/prod -> pd/learner1.*")
+	err = manager.SetRule(&Rule{GroupID: "pd", ID: "learner2", Role: Learner, Count: 1, Supersedes: []string{"learner2"}})
+	c.Assert(err, ErrorMatches, ".*supersedes cycle pd/learner2 -> pd/learner2.*")
+	// The rules in the other groups are not superseded.
+	c.Assert(manager.SetRule(&Rule{GroupID: "other", ID: "learner3", Role: Learner, Count: 1, Supersedes: []string{"prod"}}), IsNil)
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// checkSupersedesCycle checks that no rule supersedes itself, directly or
+// through the rules it supersedes.
+func checkSupersedesCycle(rules ruleContainer) error {
+	edges := make(map[[2]string][][2]string)
+	rules.iterateRules(func(r *Rule) {
+		for _, id := range r.Supersedes {
+			edges[r.Key()] = append(edges[r.Key()], [2]string{r.GroupID, id})
+		}
+	})
+	keys := make([][2]string, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[[2]string]int)
+	var path []string
+	var visit func(key [2]string) error
+	visit = func(key [2]string) error {
+		switch state[key] {
+		case visiting:
+			return errs.ErrBuildRuleList.FastGenByArgs(fmt.Sprintf("supersedes cycle %s -> %s/%s", strings.Join(path, " -> "), key[0], key[1]))
+		case visited:
+			return nil
+		}
+		state[key] = visiting
+		path = append(path, key[0]+"/"+key[1])
+		for _, next := range edges[key] {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[key] = visited
+		return nil
+	}
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterSupersededRules skips the rules superseded by the other matching
+// rules. A rule superseded by a skipped rule is still applied. The rules are
+// kept if the left ones violate the raft constraints.
+func filterSupersededRules(rules []*Rule) []*Rule {
+	supersededBy := make(map[[2]string][]*Rule)
+	for _, r := range rules {
+		for _, id := range r.Supersedes {
+			key := [2]string{r.GroupID, id}
+			supersededBy[key] = append(supersededBy[key], r)
+		}
+	}
+	if len(supersededBy) == 0 {
+		return rules
+	}
+	skipped := make(map[[2]string]bool)
+	var isSkipped func(r *Rule) bool
+	isSkipped = func(r *Rule) bool {
+		if s, ok := skipped[r.Key()]; ok {
+			return s
+		}
+		// The supersedes graph is checked to be acyclic when the rules are set.
+		skipped[r.Key()] = false
+		for _, s := range supersededBy[r.Key()] {
+			if !isSkipped(s) {
+				skipped[r.Key()] = true
+				break
+			}
+		}
+		return skipped[r.Key()]
+	}
+	res := rules[:0:0]
+	for _, r := range rules {
+		if !isSkipped(r) {
+			res = append(res, r)
+		}
+	}
+	if checkApplyRules(res) != nil {
+		return rules
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// RuleTemplate is a set of rules for a common cluster topology. The label
+// values of the rules can be parameters in the form of `{name}`, which are
+// filled when the template is instantiated.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RuleTemplate struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Parameters  []string `json:"parameters"`
+	Rules       []*Rule  `json:"rules"`
+}
+
+func templateParam(name string) string {
+	return "{" + name + "}"
+}
+
+// RuleTemplates returns the built-in rule templates by their names.
+func RuleTemplates() map[string]*RuleTemplate {
+	templates := []*RuleTemplate{
+		{
+			Name:        "single-dc",
+			Description: "3 voters in a DC, isolated by zone, rack and host.",
+			Parameters:  []string{"dc"},
+			Rules: []*Rule{{
+				GroupID:          "pd",
+				ID:               "default",
+				Role:             Voter,
+				Count:            3,
+				LabelConstraints: []LabelConstraint{{Key: "dc", Op: In, Values: []string{templateParam("dc")}}},
+				LocationLabels:   []string{"zone", "rack", "host"},
+			}},
+		},
+		{
+			Name: "two-dc-with-witness",
+			Description: "5 voters: the leader in the primary DC, 3 voters isolated by DC in the primary and the secondary DC, " +
+				"and a follower in the witness DC to keep the majority when a DC is down.",
+			Parameters: []string{"primary-dc", "secondary-dc", "witness-dc"},
+			Rules: []*Rule{
+				{
+					GroupID:          "pd",
+					ID:               "default",
+					Role:             Voter,
+					Count:            3,
+					LabelConstraints: []LabelConstraint{{Key: "dc", Op: In, Values: []string{templateParam("primary-dc"), templateParam("secondary-dc")}}},
+					LocationLabels:   []string{"dc", "host"},
+				},
+				{
+					GroupID:          "pd",
+					ID:               "primary",
+					Role:             Leader,
+					Count:            1,
+					LabelConstraints: []LabelConstraint{{Key: "dc", Op: In, Values: []string{templateParam("primary-dc")}}},
+					LocationLabels:   []string{"host"},
+				},
+				{
+					GroupID:          "pd",
+					ID:               "witness",
+					Role:             Follower,
+					Count:            1,
+					LabelConstraints: []LabelConstraint{{Key: "dc", Op: In, Values: []string{templateParam("witness-dc")}}},
+					LocationLabels:   []string{"host"},
+				},
+			},
+		},
+		newMultiAZTemplate("three-az", 3),
+		newMultiAZTemplate("five-az", 5),
+	}
+	res := make(map[string]*RuleTemplate, len(templates))
+	for _, t := range templates {
+		res[t.Name] = t
+	}
+	return res
+}
+
+// newMultiAZTemplate creates a template placing a voter in each of the AZs.
+func newMultiAZTemplate(name string, count int) *RuleTemplate {
+	t := &RuleTemplate{
+		Name:        name,
+		Description: fmt.Sprintf("%d voters, one in each AZ.", count),
+	}
+	values := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		t.Parameters = append(t.Parameters, fmt.Sprintf("az%d", i))
+		values = append(values, templateParam(t.Parameters[i-1]))
+	}
+	t.Rules = []*Rule{{
+		GroupID:          "pd",
+		ID:               "default",
+		Role:             Voter,
+		Count:            count,
+		LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: values}},
+		LocationLabels:   []string{"zone", "host"},
+		IsolationLevel:   "zone",
+	}}
+	return t
+}
+
+// Instantiate creates the rules of the template with the parameters filled
+// by the values. All the parameters are required.
+func (t *RuleTemplate) Instantiate(values map[string]string) ([]*Rule, error) {
+	params := make(map[string]string, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if len(values[p]) == 0 {
+			return nil, errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("missing the value of the template parameter %s", p))
+		}
+		params[templateParam(p)] = values[p]
+	}
+	for name := range values {
+		if _, ok := params[templateParam(name)]; !ok {
+			return nil, errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("unknown template parameter %s", name))
+		}
+	}
+	rules := make([]*Rule, 0, len(t.Rules))
+	for _, r := range t.Rules {
+		rule := r.Clone()
+		for i := range rule.LabelConstraints {
+			for j, v := range rule.LabelConstraints[i].Values {
+				if strings.HasPrefix(v, "{") {
+					rule.LabelConstraints[i].Values[j] = params[v]
+				}
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/storage"
+)
+
+var _ = Suite(&testRuleTemplateSuite{})
+
+type testRuleTemplateSuite struct{}
+
+func (s *testRuleTemplateSuite) TestInstantiate(c *C) {
+	template := RuleTemplates()["three-az"]
+	c.Assert(template.Parameters, DeepEquals, []string{"az1", "az2", "az3"})
+	rules, err := template.Instantiate(map[string]string{"az1": "a", "az2": "b", "az3": "c"})
+	c.Assert(err, IsNil)
+	c.Assert(rules, HasLen, 1)
+	c.Assert(rules[0].LabelConstraints[0].Values, DeepEquals, []string{"a", "b", "c"})
+	// The template itself is not changed.
+	c.Assert(template.Rules[0].LabelConstraints[0].Values, DeepEquals, []string{"{az1}", "{az2}", "{az3}"})
+
+	_, err = template.Instantiate(map[string]string{"az1": "a", "az2": "b"})
+	c.Assert(err, ErrorMatches, ".*missing the value of the template parameter az3.*")
+	_, err = template.Instantiate(map[string]string{"az1": "a", "az2": "b", "az3": "c", "az4": "d"})
+	c.Assert(err, ErrorMatches, ".*unknown template parameter az4.*")
+}
+
+func (s *testRuleTemplateSuite) TestTemplates(c *C) {
+	type store struct {
+		id     uint64
+		labels map[string]string
+	}
+	testCases := []struct {
+		template string
+		values   map[string]string
+		stores   []store
+	}{
+		{
+			template: "single-dc",
+			values:   map[string]string{"dc": "dc1"},
+			stores: []store{
+				{1, map[string]string{"dc": "dc1", "zone": "z1", "rack": "r1", "host": "h1"}},
+				{2, map[string]string{"dc": "dc1", "zone": "z2", "rack": "r2", "host": "h2"}},
+				{3, map[string]string{"dc": "dc1", "zone": "z3", "rack": "r3", "host": "h3"}},
+			},
+		},
+		{
+			template: "two-dc-with-witness",
+			values:   map[string]string{"primary-dc": "dc1", "secondary-dc": "dc2", "witness-dc": "dc3"},
+			stores: []store{
+				{1, map[string]string{"dc": "dc1", "host": "h1"}},
+				{2, map[string]string{"dc": "dc1", "host": "h2"}},
+				{3, map[string]string{"dc": "dc2", "host": "h3"}},
+				{4, map[string]string{"dc": "dc2", "host": "h4"}},
+				{5, map[string]string{"dc": "dc3", "host": "h5"}},
+			},
+		},
+		{
+			template: "three-az",
+			values:   map[string]string{"az1": "a", "az2": "b", "az3": "c"},
+			stores: []store{
+				{1, map[string]string{"zone": "a", "host": "h1"}},
+				{2, map[string]string{"zone": "b", "host": "h2"}},
+				{3, map[string]string{"zone": "c", "host": "h3"}},
+			},
+		},
+		{
+			template: "five-az",
+			values:   map[string]string{"az1": "a", "az2": "b", "az3": "c", "az4": "d", "az5": "e"},
+			stores: []store{
+				{1, map[string]string{"zone": "a", "host": "h1"}},
+				{2, map[string]string{"zone": "b", "host": "h2"}},
+				{3, map[string]string{"zone": "c", "host": "h3"}},
+				{4, map[string]string{"zone": "d", "host": "h4"}},
+				{5, map[string]string{"zone": "e", "host": "h5"}},
+			},
+		},
+	}
+	c.Assert(RuleTemplates(), HasLen, len(testCases))
+	for _, tc := range testCases {
+		comment := Commentf("template %s", tc.template)
+		cluster := core.NewBasicCluster()
+		region := &metapb.Region{Id: 1}
+		for _, s := range tc.stores {
+			cluster.PutStore(core.NewStoreInfoWithLabel(s.id, 0, s.labels))
+			region.Peers = append(region.Peers, &metapb.Peer{Id: s.id, StoreId: s.id})
+		}
+		manager := NewRuleManager(storage.NewStorageWithMemoryBackend(), cluster, config.NewTestOptions())
+		c.Assert(manager.Initialize(3, []string{"zone", "rack", "host"}), IsNil, comment)
+
+		template, ok := RuleTemplates()[tc.template]
+		c.Assert(ok, IsTrue, comment)
+		rules, err := template.Instantiate(tc.values)
+		c.Assert(err, IsNil, comment)
+		c.Assert(manager.DetectConflicts(rules), HasLen, 0, comment)
+		c.Assert(manager.SetRules(rules), IsNil, comment)
+		// A region with a replica on every store satisfies the rules.
+		fit := manager.FitRegion(cluster, core.NewRegionInfo(region, region.Peers[0]))
+		c.Assert(fit.IsSatisfied(), IsTrue, comment)
+	}
+
+	// The rules of a template can not match the stores of another topology.
+	cluster := core.NewBasicCluster()
+	cluster.PutStore(core.NewStoreInfoWithLabel(1, 0, map[string]string{"dc": "dc1", "host": "h1"}))
+	manager := NewRuleManager(storage.NewStorageWithMemoryBackend(), cluster, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone", "rack", "host"}), IsNil)
+	rules, err := RuleTemplates()["three-az"].Instantiate(map[string]string{"az1": "a", "az2": "b", "az3": "c"})
+	c.Assert(err, IsNil)
+	c.Assert(manager.SetRules(rules), NotNil)
+}
@@ -0,0 +1,208 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/slice"
+	"github.com/tikv/pd/server/core"
+)
+
+// RuleTraceStatus tells whether a rule is applied to the region.
+type RuleTraceStatus string
+
+const (
+	// RuleTraceApplied means the rule is applied to the region.
+	RuleTraceApplied RuleTraceStatus = "applied"
+	// RuleTraceOverridden means the rule is overridden by the other rules.
+	RuleTraceOverridden RuleTraceStatus = "overridden"
+	// RuleTraceInactive means the rule is out of its daily time window.
+	RuleTraceInactive RuleTraceStatus = "inactive"
+	// RuleTraceStoreLabelMismatch means the labels of the region stores do
+	// not match the store label match of the rule.
+	RuleTraceStoreLabelMismatch RuleTraceStatus = "store-label-mismatch"
+	// RuleTraceSuperseded means the rule is superseded by another matching rule.
+	RuleTraceSuperseded RuleTraceStatus = "superseded"
+	// RuleTraceCrossRange means the region is not split by the key ranges of
+	// the rules yet, so no rule is applied.
+	RuleTraceCrossRange RuleTraceStatus = "cross-range"
+)
+
+// RuleTrace explains how the placement rules are evaluated for a region.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RuleTrace struct {
+	RegionID uint64 `json:"region_id"`
+	// Rules are the rules of the key range of the region in priority order.
+	Rules []*RuleTraceStep `json:"rules"`
+	// OrphanPeers are the peers not needed by any applied rule.
+	OrphanPeers []*RuleTracePeer `json:"orphan_peers,omitempty"`
+	Satisfied   bool             `json:"satisfied"`
+}
+
+// RuleTraceStep is the evaluation of a rule.
+type RuleTraceStep struct {
+	Rule   string          `json:"rule"`
+	Role   PeerRoleType    `json:"role"`
+	Count  int             `json:"count"`
+	Status RuleTraceStatus `json:"status"`
+	// CandidateStores are the stores matching the label constraints of the rule.
+	CandidateStores []uint64 `json:"candidate_stores,omitempty"`
+	// EliminatedStores are the stores not matching the label constraints.
+	EliminatedStores []*RuleTraceElimination `json:"eliminated_stores,omitempty"`
+	// SelectedPeers are the peers of the region fitted to the rule.
+	SelectedPeers  []*RuleTracePeer `json:"selected_peers,omitempty"`
+	IsolationScore float64          `json:"isolation_score,omitempty"`
+	Satisfied      bool             `json:"satisfied"`
+}
+
+// RuleTraceElimination is a store eliminated by a label constraint.
+type RuleTraceElimination struct {
+	StoreID    uint64 `json:"store_id"`
+	Constraint string `json:"constraint"`
+}
+
+// RuleTracePeer is a peer of the region with the reason why it is selected by
+// a rule or is an orphan.
+type RuleTracePeer struct {
+	PeerID  uint64 `json:"peer_id"`
+	StoreID uint64 `json:"store_id"`
+	Reason  string `json:"reason"`
+}
+
+// TraceRuleEvaluation re-evaluates the rules of the region and explains the
+// result. It returns nil if the region does not exist.
+func (m *RuleManager) TraceRuleEvaluation(regionID uint64) *RuleTrace {
+	regions, ok := m.storeSetInformer.(interface {
+		GetRegion(regionID uint64) *core.RegionInfo
+	})
+	if !ok {
+		return nil
+	}
+	region := regions.GetRegion(regionID)
+	if region == nil {
+		return nil
+	}
+	m.RLock()
+	rules := m.ruleList.getRulesByKey(region.GetStartKey())
+	prepared := m.ruleList.getRulesForApplyRegion(region.GetStartKey(), region.GetEndKey())
+	m.RUnlock()
+	active := filterActiveRules(prepared, m.now())
+	labelMatched := filterRulesByStoreLabels(active, getStoresByRegion(m.storeSetInformer, region))
+	fit := m.FitRegion(m.storeSetInformer, region)
+	ruleFits := make(map[[2]string]*RuleFit, len(fit.RuleFits))
+	for _, rf := range fit.RuleFits {
+		ruleFits[rf.Rule.Key()] = rf
+	}
+
+	stores := m.storeSetInformer.GetStores()
+	sort.Slice(stores, func(i, j int) bool { return stores[i].GetID() < stores[j].GetID() })
+	trace := &RuleTrace{RegionID: regionID, Satisfied: fit.IsSatisfied()}
+	for _, rule := range rules {
+		step := &RuleTraceStep{
+			Rule:  rule.GroupID + "/" + rule.ID,
+			Role:  rule.Role,
+			Count: rule.Count,
+		}
+		trace.Rules = append(trace.Rules, step)
+		rf, applied := ruleFits[rule.Key()]
+		switch {
+		case len(prepared) == 0:
+			step.Status = RuleTraceCrossRange
+		case !containsRule(prepared, rule):
+			step.Status = RuleTraceOverridden
+		case !containsRule(active, rule):
+			step.Status = RuleTraceInactive
+		case !containsRule(labelMatched, rule):
+			step.Status = RuleTraceStoreLabelMismatch
+		case !applied:
+			step.Status = RuleTraceSuperseded
+		default:
+			step.Status = RuleTraceApplied
+		}
+		if !applied {
+			continue
+		}
+		for _, store := range stores {
+			if store.IsRemoved() {
+				continue
+			}
+			if constraint := eliminatingConstraint(store, rule.LabelConstraints); len(constraint) > 0 {
+				step.EliminatedStores = append(step.EliminatedStores, &RuleTraceElimination{StoreID: store.GetID(), Constraint: constraint})
+			} else {
+				step.CandidateStores = append(step.CandidateStores, store.GetID())
+			}
+		}
+		for _, peer := range rf.Peers {
+			reason := fmt.Sprintf("matches the label constraints as a %s", peerRoleName(region, peer))
+			if slice.AnyOf(rf.PeersWithDifferentRole, func(i int) bool { return rf.PeersWithDifferentRole[i].GetId() == peer.GetId() }) {
+				reason = fmt.Sprintf("matches the label constraints but is a %s instead of a %s", peerRoleName(region, peer), rule.Role)
+			}
+			step.SelectedPeers = append(step.SelectedPeers, &RuleTracePeer{PeerID: peer.GetId(), StoreID: peer.GetStoreId(), Reason: reason})
+		}
+		step.IsolationScore = rf.IsolationScore
+		step.Satisfied = rf.IsSatisfied()
+	}
+	for _, peer := range fit.OrphanPeers {
+		trace.OrphanPeers = append(trace.OrphanPeers, &RuleTracePeer{
+			PeerID:  peer.GetId(),
+			StoreID: peer.GetStoreId(),
+			Reason:  "no applied rule needs the peer",
+		})
+	}
+	return trace
+}
+
+func containsRule(rules []*Rule, rule *Rule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// eliminatingConstraint returns the first label constraint the store does not
+// match, it returns an empty string if the store matches all of them.
+func eliminatingConstraint(store *core.StoreInfo, constraints []LabelConstraint) string {
+	for _, l := range store.GetLabels() {
+		if isExclusiveLabel(l.GetKey()) &&
+			slice.NoneOf(constraints, func(i int) bool { return constraints[i].Key == l.GetKey() }) {
+			return fmt.Sprintf("exclusive label %s=%s", l.GetKey(), l.GetValue())
+		}
+	}
+	for i := range constraints {
+		if !constraints[i].MatchStore(store) {
+			if len(constraints[i].Values) == 0 {
+				return fmt.Sprintf("%s %s", constraints[i].Key, constraints[i].Op)
+			}
+			return fmt.Sprintf("%s %s %v", constraints[i].Key, constraints[i].Op, constraints[i].Values)
+		}
+	}
+	return ""
+}
+
+func peerRoleName(region *core.RegionInfo, peer *metapb.Peer) PeerRoleType {
+	switch {
+	case peer.GetId() == region.GetLeader().GetId():
+		return Leader
+	case peer.GetRole() == metapb.PeerRole_Learner:
+		return Learner
+	default:
+		return Follower
+	}
+}
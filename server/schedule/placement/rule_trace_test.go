@@ -0,0 +1,92 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/storage"
+)
+
+var _ = Suite(&testRuleTraceSuite{})
+
+type testRuleTraceSuite struct{}
+
+func (s *testRuleTraceSuite) TestTraceRuleEvaluation(c *C) {
+	cluster := core.NewBasicCluster()
+	cluster.PutStore(core.NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1"}))
+	cluster.PutStore(core.NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z2"}))
+	cluster.PutStore(core.NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z3"}))
+	cluster.PutStore(core.NewStoreInfoWithLabel(4, 0, map[string]string{"zone": "z1", "engine": "tiflash"}))
+	manager := NewRuleManager(storage.NewStorageWithMemoryBackend(), cluster, config.NewTestOptions())
+	c.Assert(manager.Initialize(3, []string{"zone"}), IsNil)
+	c.Assert(manager.SetRules([]*Rule{
+		{GroupID: "pd", ID: "default", Role: Voter, Count: 2,
+			LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{"z1", "z2"}}}},
+		{GroupID: "g", ID: "old", Role: Learner, Count: 1},
+		{GroupID: "g", ID: "learner", Index: 1, Override: true, Role: Learner, Count: 1,
+			LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{"z3"}}}},
+	}), IsNil)
+
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: []*metapb.Peer{
+		{Id: 11, StoreId: 1},
+		{Id: 12, StoreId: 2},
+		{Id: 13, StoreId: 3, Role: metapb.PeerRole_Learner},
+		{Id: 14, StoreId: 4},
+	}}, &metapb.Peer{Id: 11, StoreId: 1})
+	cluster.PutRegion(region)
+	c.Assert(manager.TraceRuleEvaluation(2), IsNil)
+	trace := manager.TraceRuleEvaluation(1)
+	c.Assert(trace, NotNil)
+	c.Assert(trace.Satisfied, IsFalse)
+	c.Assert(trace.Rules, HasLen, 3)
+
+	old := trace.Rules[0]
+	c.Assert(old.Rule, Equals, "g/old")
+	c.Assert(old.Status, Equals, RuleTraceOverridden)
+	c.Assert(old.CandidateStores, HasLen, 0)
+
+	learner := trace.Rules[1]
+	c.Assert(learner.Rule, Equals, "g/learner")
+	c.Assert(learner.Status, Equals, RuleTraceApplied)
+	c.Assert(learner.CandidateStores, DeepEquals, []uint64{3})
+	c.Assert(learner.EliminatedStores, DeepEquals, []*RuleTraceElimination{
+		{StoreID: 1, Constraint: "zone in [z3]"},
+		{StoreID: 2, Constraint: "zone in [z3]"},
+		{StoreID: 4, Constraint: "exclusive label engine=tiflash"},
+	})
+	c.Assert(learner.SelectedPeers, DeepEquals, []*RuleTracePeer{
+		{PeerID: 13, StoreID: 3, Reason: "matches the label constraints as a learner"},
+	})
+	c.Assert(learner.Satisfied, IsTrue)
+
+	def := trace.Rules[2]
+	c.Assert(def.Rule, Equals, "pd/default")
+	c.Assert(def.Status, Equals, RuleTraceApplied)
+	c.Assert(def.CandidateStores, DeepEquals, []uint64{1, 2})
+	c.Assert(def.EliminatedStores, HasLen, 2)
+	c.Assert(def.SelectedPeers, DeepEquals, []*RuleTracePeer{
+		{PeerID: 11, StoreID: 1, Reason: "matches the label constraints as a leader"},
+		{PeerID: 12, StoreID: 2, Reason: "matches the label constraints as a follower"},
+	})
+	c.Assert(def.Satisfied, IsTrue)
+
+	// The peer on the TiFlash store is not needed by any rule.
+	c.Assert(trace.OrphanPeers, DeepEquals, []*RuleTracePeer{
+		{PeerID: 14, StoreID: 4, Reason: "no applied rule needs the peer"},
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"encoding/binary"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+// throttleRegionField is the field number of the ThrottleRegion hint in the
+// RegionHeartbeatResponse. kvproto has no such field yet, so the hint is kept
+// in the unrecognized fields of the response, which are marshaled with it.
+const throttleRegionField = 100
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ThrottleRegion is the hint asking TiKV to throttle the flow of a region. It
+// is encoded as the message
+//
+//	message ThrottleRegion {
+//	    uint64 read_bytes_per_second = 1;
+//	    uint64 write_bytes_per_second = 2;
+//	}
+//
+// 0 means no limit.
+type ThrottleRegion struct {
+	ReadBytesPerSecond  uint64
+	WriteBytesPerSecond uint64
+}
+
+// SetThrottleRegion sets the ThrottleRegion hint of the response.
+func SetThrottleRegion(resp *pdpb.RegionHeartbeatResponse, hint *ThrottleRegion) {
+	var msg []byte
+	if hint.ReadBytesPerSecond > 0 {
+		msg = appendVarint(msg, 1<<3|wireVarint)
+		msg = appendVarint(msg, hint.ReadBytesPerSecond)
+	}
+	if hint.WriteBytesPerSecond > 0 {
+		msg = appendVarint(msg, 2<<3|wireVarint)
+		msg = appendVarint(msg, hint.WriteBytesPerSecond)
+	}
+	var field []byte
+	field = appendVarint(field, throttleRegionField<<3|wireBytes)
+	field = appendVarint(field, uint64(len(msg)))
+	field = append(field, msg...)
+	resp.XXX_unrecognized = append(resp.XXX_unrecognized, field...)
+}
+
+// GetThrottleRegion returns the ThrottleRegion hint of the response, nil if
+// there is none.
+func GetThrottleRegion(resp *pdpb.RegionHeartbeatResponse) *ThrottleRegion {
+	var hint *ThrottleRegion
+	walkFields(resp.XXX_unrecognized, func(field, wire uint64, v uint64, b []byte) {
+		if field != throttleRegionField || wire != wireBytes {
+			return
+		}
+		hint = &ThrottleRegion{}
+		walkFields(b, func(field, wire uint64, v uint64, _ []byte) {
+			switch {
+			case field == 1 && wire == wireVarint:
+				hint.ReadBytesPerSecond = v
+			case field == 2 && wire == wireVarint:
+				hint.WriteBytesPerSecond = v
+			}
+		})
+	})
+	return hint
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// walkFields calls f with every field of the encoded message, and stops at the
+// first malformed field.
+func walkFields(b []byte, f func(field, wire uint64, v uint64, b []byte)) {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return
+		}
+		b = b[n:]
+		field, wire := key>>3, key&7
+		switch wire {
+		case wireVarint:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return
+			}
+			b = b[n:]
+			f(field, wire, v, nil)
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return
+			}
+			f(field, wire, 0, b[n:n+int(l)])
+			b = b[n+int(l):]
+		case wireFixed64:
+			if len(b) < 8 {
+				return
+			}
+			f(field, wire, binary.LittleEndian.Uint64(b), nil)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return
+			}
+			f(field, wire, uint64(binary.LittleEndian.Uint32(b)), nil)
+			b = b[4:]
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/eraftpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+var _ = Suite(&testHintSuite{})
+
+type testHintSuite struct{}
+
+func (s *testHintSuite) TestThrottleRegion(c *C) {
+	resp := &pdpb.RegionHeartbeatResponse{
+		RegionId: 1,
+		ChangePeer: &pdpb.ChangePeer{
+			Peer:       &metapb.Peer{Id: 2, StoreId: 2},
+			ChangeType: eraftpb.ConfChangeType_AddNode,
+		},
+	}
+	c.Assert(GetThrottleRegion(resp), IsNil)
+	hint := &ThrottleRegion{WriteBytesPerSecond: 1 << 20}
+	SetThrottleRegion(resp, hint)
+	c.Assert(GetThrottleRegion(resp), DeepEquals, hint)
+
+	// The hint is kept through the wire.
+	data, err := resp.Marshal()
+	c.Assert(err, IsNil)
+	var decoded pdpb.RegionHeartbeatResponse
+	c.Assert(decoded.Unmarshal(data), IsNil)
+	c.Assert(decoded.GetRegionId(), Equals, uint64(1))
+	c.Assert(decoded.GetChangePeer().GetPeer().GetStoreId(), Equals, uint64(2))
+	c.Assert(GetThrottleRegion(&decoded), DeepEquals, hint)
+
+	// Malformed fields are ignored.
+	c.Assert(GetThrottleRegion(&pdpb.RegionHeartbeatResponse{XXX_unrecognized: []byte{0xa2, 0x06, 0x10}}), IsNil)
+}
@@ -0,0 +1,288 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/rangelist"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// RegionQuota limits the read and write flow of every region in a key range.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionQuota struct {
+	ID          string `json:"id"`
+	StartKey    []byte `json:"-"`         // range start key
+	StartKeyHex string `json:"start_key"` // hex format start key, for marshal/unmarshal
+	EndKey      []byte `json:"-"`         // range end key
+	EndKeyHex   string `json:"end_key"`   // hex format end key, for marshal/unmarshal
+	// ReadBytesPerSecond and WriteBytesPerSecond are the max flow of a region, 0 means no limit.
+	ReadBytesPerSecond  uint64 `json:"read_bytes_per_second"`
+	WriteBytesPerSecond uint64 `json:"write_bytes_per_second"`
+}
+
+func (q *RegionQuota) checkAndAdjust() error {
+	if q.ID == "" {
+		return errs.ErrRegionQuotaContent.FastGenByArgs("empty quota id")
+	}
+	if q.ReadBytesPerSecond == 0 && q.WriteBytesPerSecond == 0 {
+		return errs.ErrRegionQuotaContent.FastGenByArgs("no read or write quota")
+	}
+	var err error
+	q.StartKey, err = hex.DecodeString(q.StartKeyHex)
+	if err != nil {
+		return errs.ErrHexDecodingString.FastGenByArgs(q.StartKeyHex)
+	}
+	q.EndKey, err = hex.DecodeString(q.EndKeyHex)
+	if err != nil {
+		return errs.ErrHexDecodingString.FastGenByArgs(q.EndKeyHex)
+	}
+	if len(q.EndKey) > 0 && bytes.Compare(q.EndKey, q.StartKey) <= 0 {
+		return errs.ErrRegionQuotaContent.FastGenByArgs("endKey should be greater than startKey")
+	}
+	return nil
+}
+
+// Violation is a region whose flow exceeded its quota in the last heartbeat.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type Violation struct {
+	RegionID            uint64 `json:"region_id"`
+	QuotaID             string `json:"quota_id"`
+	ReadBytesPerSecond  uint64 `json:"read_bytes_per_second"`
+	WriteBytesPerSecond uint64 `json:"write_bytes_per_second"`
+
+	readLimit, writeLimit uint64
+}
+
+// Hint returns the hint asking TiKV to throttle the region to the quota it
+// exceeds.
+func (v *Violation) Hint() *ThrottleRegion {
+	return &ThrottleRegion{
+		ReadBytesPerSecond:  v.readLimit,
+		WriteBytesPerSecond: v.writeLimit,
+	}
+}
+
+// Manager keeps the region quotas and the regions exceeding them.
+type Manager struct {
+	storage endpoint.RegionQuotaStorage
+	sync.RWMutex
+	quotas     map[string]*RegionQuota
+	rangeList  rangelist.List // sorted RegionQuotas
+	violations map[uint64]*Violation
+}
+
+// NewManager creates a Manager and loads the quotas from the storage.
+func NewManager(storage endpoint.RegionQuotaStorage) (*Manager, error) {
+	m := &Manager{
+		storage:    storage,
+		quotas:     make(map[string]*RegionQuota),
+		violations: make(map[uint64]*Violation),
+	}
+	var toDelete []string
+	err := storage.LoadRegionQuotas(func(k, v string) {
+		var q RegionQuota
+		if err := json.Unmarshal([]byte(v), &q); err != nil {
+			log.Error("failed to unmarshal region quota", zap.String("quota-key", k), zap.String("quota-value", v), errs.ZapError(errs.ErrJSONUnmarshal, err))
+			toDelete = append(toDelete, k)
+			return
+		}
+		if err := q.checkAndAdjust(); err != nil {
+			log.Error("failed to adjust region quota", zap.String("quota-key", k), zap.String("quota-value", v), zap.Error(err))
+			toDelete = append(toDelete, k)
+			return
+		}
+		m.quotas[q.ID] = &q
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range toDelete {
+		if err := storage.DeleteRegionQuota(d); err != nil {
+			return nil, err
+		}
+	}
+	m.buildRangeList()
+	return m, nil
+}
+
+func (m *Manager) buildRangeList() {
+	builder := rangelist.NewBuilder()
+	for _, q := range m.quotas {
+		builder.AddItem(q.StartKey, q.EndKey, q)
+	}
+	m.rangeList = builder.Build()
+}
+
+// GetQuotas returns all the quotas in the order of the ID.
+func (m *Manager) GetQuotas() []*RegionQuota {
+	m.RLock()
+	defer m.RUnlock()
+	quotas := make([]*RegionQuota, 0, len(m.quotas))
+	for _, q := range m.quotas {
+		quotas = append(quotas, q)
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].ID < quotas[j].ID })
+	return quotas
+}
+
+// GetQuota returns the quota with the ID, nil if it does not exist.
+func (m *Manager) GetQuota(id string) *RegionQuota {
+	m.RLock()
+	defer m.RUnlock()
+	return m.quotas[id]
+}
+
+// SetQuota inserts or updates a quota.
+func (m *Manager) SetQuota(q *RegionQuota) error {
+	if err := q.checkAndAdjust(); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	if err := m.storage.SaveRegionQuota(q.ID, q); err != nil {
+		return err
+	}
+	m.quotas[q.ID] = q
+	m.buildRangeList()
+	// The regions are checked against the new quota by their next heartbeats.
+	m.clearViolationsLocked(q.ID)
+	return nil
+}
+
+// DeleteQuota removes a quota.
+func (m *Manager) DeleteQuota(id string) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.quotas[id]; !ok {
+		return errs.ErrRegionQuotaNotFound.FastGenByArgs(id)
+	}
+	if err := m.storage.DeleteRegionQuota(id); err != nil {
+		return err
+	}
+	delete(m.quotas, id)
+	m.buildRangeList()
+	m.clearViolationsLocked(id)
+	return nil
+}
+
+func (m *Manager) clearViolationsLocked(id string) {
+	for regionID, v := range m.violations {
+		if v.QuotaID == id {
+			delete(m.violations, regionID)
+		}
+	}
+}
+
+// Observe checks the flow of the region in its heartbeat against the quotas of
+// its key range, and returns the violation if it exceeds any of them.
+func (m *Manager) Observe(region *core.RegionInfo) *Violation {
+	if m == nil {
+		return nil
+	}
+	interval := region.GetInterval().GetEndTimestamp() - region.GetInterval().GetStartTimestamp()
+	if interval == 0 {
+		return nil
+	}
+	readRate, writeRate := region.GetBytesRead()/interval, region.GetBytesWritten()/interval
+
+	m.Lock()
+	defer m.Unlock()
+	var violation *Violation
+	for _, q := range m.getOverlappedQuotasLocked(region.GetStartKey(), region.GetEndKey()) {
+		if (q.ReadBytesPerSecond > 0 && readRate > q.ReadBytesPerSecond) ||
+			(q.WriteBytesPerSecond > 0 && writeRate > q.WriteBytesPerSecond) {
+			violation = &Violation{
+				RegionID:            region.GetID(),
+				QuotaID:             q.ID,
+				ReadBytesPerSecond:  readRate,
+				WriteBytesPerSecond: writeRate,
+				readLimit:           q.ReadBytesPerSecond,
+				writeLimit:          q.WriteBytesPerSecond,
+			}
+			break
+		}
+	}
+	if violation == nil {
+		delete(m.violations, region.GetID())
+		return nil
+	}
+	if _, ok := m.violations[region.GetID()]; !ok {
+		log.Warn("region exceeds its quota",
+			zap.Uint64("region-id", region.GetID()),
+			zap.String("quota-id", violation.QuotaID),
+			zap.Uint64("read-bytes-per-second", readRate),
+			zap.Uint64("write-bytes-per-second", writeRate))
+	}
+	m.violations[region.GetID()] = violation
+	return violation
+}
+
+// getOverlappedQuotasLocked returns the quotas overlapping with the key range
+// in the order of the ID.
+func (m *Manager) getOverlappedQuotasLocked(start, end []byte) []*RegionQuota {
+	i, _ := m.rangeList.GetDataByKey(start)
+	if i == -1 {
+		i = 0
+	}
+	overlapped := make(map[string]*RegionQuota)
+	for ; i < m.rangeList.Len(); i++ {
+		key, data := m.rangeList.Get(i)
+		if len(end) > 0 && bytes.Compare(key, end) >= 0 {
+			break
+		}
+		for _, d := range data {
+			q := d.(*RegionQuota)
+			overlapped[q.ID] = q
+		}
+	}
+	quotas := make([]*RegionQuota, 0, len(overlapped))
+	for _, q := range overlapped {
+		quotas = append(quotas, q)
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].ID < quotas[j].ID })
+	return quotas
+}
+
+// Remove removes the violation of the region.
+func (m *Manager) Remove(regionID uint64) {
+	if m == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	delete(m.violations, regionID)
+}
+
+// GetViolations returns the regions exceeding their quotas in the order of the region ID.
+func (m *Manager) GetViolations() []*Violation {
+	m.RLock()
+	defer m.RUnlock()
+	violations := make([]*Violation, 0, len(m.violations))
+	for _, v := range m.violations {
+		violations = append(violations, v)
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].RegionID < violations[j].RegionID })
+	return violations
+}
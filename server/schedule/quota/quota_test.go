@@ -0,0 +1,142 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/storage"
+	"github.com/tikv/pd/server/storage/endpoint"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testQuotaSuite{})
+
+type testQuotaSuite struct {
+	store   endpoint.RegionQuotaStorage
+	manager *Manager
+}
+
+func (s *testQuotaSuite) SetUpTest(c *C) {
+	s.store = storage.NewStorageWithMemoryBackend()
+	var err error
+	s.manager, err = NewManager(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testQuotaSuite) TestAdjustQuota(c *C) {
+	q := RegionQuota{ID: "q1", StartKeyHex: "12ab", EndKeyHex: "34cd", WriteBytesPerSecond: 1024}
+	c.Assert(q.checkAndAdjust(), IsNil)
+	c.Assert(q.StartKey, BytesEquals, []byte{0x12, 0xab})
+	c.Assert(q.EndKey, BytesEquals, []byte{0x34, 0xcd})
+
+	badQuotas := []RegionQuota{
+		// no id
+		{StartKeyHex: "12ab", EndKeyHex: "34cd", WriteBytesPerSecond: 1024},
+		// no quota
+		{ID: "q1", StartKeyHex: "12ab", EndKeyHex: "34cd"},
+		// end key is not greater than start key
+		{ID: "q1", StartKeyHex: "34cd", EndKeyHex: "12ab", WriteBytesPerSecond: 1024},
+	}
+	for _, q := range badQuotas {
+		c.Assert(errs.ErrRegionQuotaContent.Equal(q.checkAndAdjust()), IsTrue)
+	}
+	q = RegionQuota{ID: "q1", StartKeyHex: "xx", WriteBytesPerSecond: 1024}
+	c.Assert(errs.ErrHexDecodingString.Equal(q.checkAndAdjust()), IsTrue)
+}
+
+func (s *testQuotaSuite) TestSetDeleteQuota(c *C) {
+	quotas := []*RegionQuota{
+		{ID: "q2", StartKeyHex: "34cd", EndKeyHex: "56ef", ReadBytesPerSecond: 2048},
+		{ID: "q1", StartKeyHex: "12ab", EndKeyHex: "34cd", WriteBytesPerSecond: 1024},
+	}
+	for _, q := range quotas {
+		c.Assert(s.manager.SetQuota(q), IsNil)
+	}
+	c.Assert(s.manager.GetQuotas(), DeepEquals, []*RegionQuota{quotas[1], quotas[0]})
+	c.Assert(s.manager.GetQuota("q2"), DeepEquals, quotas[0])
+
+	// The quotas are loaded from the storage.
+	manager, err := NewManager(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(manager.GetQuotas(), DeepEquals, s.manager.GetQuotas())
+
+	c.Assert(s.manager.DeleteQuota("q2"), IsNil)
+	c.Assert(s.manager.GetQuota("q2"), IsNil)
+	c.Assert(errs.ErrRegionQuotaNotFound.Equal(s.manager.DeleteQuota("q2")), IsTrue)
+	manager, err = NewManager(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(manager.GetQuotas(), DeepEquals, []*RegionQuota{quotas[1]})
+}
+
+func (s *testQuotaSuite) TestLoadBadQuota(c *C) {
+	c.Assert(s.store.SaveRegionQuota("bad", json.RawMessage(`{"id":"bad"}`)), IsNil)
+	c.Assert(s.store.SaveRegionQuota("q1", &RegionQuota{ID: "q1", StartKeyHex: "12ab", WriteBytesPerSecond: 1024}), IsNil)
+	manager, err := NewManager(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(manager.GetQuotas(), HasLen, 1)
+	c.Assert(manager.GetQuota("q1"), NotNil)
+}
+
+func (s *testQuotaSuite) TestObserve(c *C) {
+	c.Assert(s.manager.SetQuota(&RegionQuota{ID: "q1", StartKeyHex: "10", EndKeyHex: "20", WriteBytesPerSecond: 1024}), IsNil)
+	c.Assert(s.manager.SetQuota(&RegionQuota{ID: "q2", StartKeyHex: "20", EndKeyHex: "30", ReadBytesPerSecond: 1024}), IsNil)
+
+	// 2 KiB/s of write in the range of q1.
+	region := newTestRegion(1, []byte{0x11}, []byte{0x12}, core.SetWrittenBytes(20*1024), core.SetReportInterval(10))
+	v := s.manager.Observe(region)
+	c.Assert(v.RegionID, Equals, uint64(1))
+	c.Assert(v.QuotaID, Equals, "q1")
+	c.Assert(v.WriteBytesPerSecond, Equals, uint64(2048))
+	c.Assert(v.Hint(), DeepEquals, &ThrottleRegion{WriteBytesPerSecond: 1024})
+	// Only the read flow is limited in the range of q2.
+	c.Assert(s.manager.Observe(newTestRegion(2, []byte{0x21}, []byte{0x22}, core.SetWrittenBytes(20*1024), core.SetReportInterval(10))), IsNil)
+	// Out of all the ranges.
+	c.Assert(s.manager.Observe(newTestRegion(3, []byte{0x31}, []byte{0x32}, core.SetWrittenBytes(20*1024), core.SetReportInterval(10))), IsNil)
+	// A region across the ranges is checked against both quotas.
+	v4 := s.manager.Observe(newTestRegion(4, []byte{0x19}, []byte{0x21}, core.SetReadBytes(20*1024), core.SetReportInterval(10)))
+	c.Assert(v4.QuotaID, Equals, "q2")
+	c.Assert(v4.ReadBytesPerSecond, Equals, uint64(2048))
+	c.Assert(v4.Hint(), DeepEquals, &ThrottleRegion{ReadBytesPerSecond: 1024})
+	c.Assert(s.manager.GetViolations(), DeepEquals, []*Violation{v, v4})
+
+	// The region is back under its quota.
+	c.Assert(s.manager.Observe(newTestRegion(1, []byte{0x11}, []byte{0x12}, core.SetWrittenBytes(1024), core.SetReportInterval(10))), IsNil)
+	c.Assert(s.manager.GetViolations(), HasLen, 1)
+	// A heartbeat without the interval does not change the violations.
+	c.Assert(s.manager.Observe(newTestRegion(4, []byte{0x19}, []byte{0x21})), IsNil)
+	c.Assert(s.manager.GetViolations(), HasLen, 1)
+	// The violations are cleared with the quota.
+	c.Assert(s.manager.DeleteQuota("q2"), IsNil)
+	c.Assert(s.manager.GetViolations(), HasLen, 0)
+
+	s.manager.Observe(region)
+	c.Assert(s.manager.GetViolations(), HasLen, 1)
+	s.manager.Remove(1)
+	c.Assert(s.manager.GetViolations(), HasLen, 0)
+}
+
+func newTestRegion(id uint64, start, end []byte, opts ...core.RegionCreateOption) *core.RegionInfo {
+	meta := &metapb.Region{Id: id, StartKey: start, EndKey: end, Peers: []*metapb.Peer{{Id: id, StoreId: 1}}}
+	return core.NewRegionInfo(meta, meta.Peers[0], opts...)
+}
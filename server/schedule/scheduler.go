@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -142,3 +143,52 @@ func FindSchedulerTypeByName(name string) string {
 	}
 	return typ
 }
+
+// SchedulerPlugin is an interface to provide external scheduling policies
+// which can not be expressed by the built-in schedulers.
+//
+// The lifecycle of a plugin is:
+//  1. The plugin is registered by RegisterPlugin, usually in the init() func
+//     of its package, which must be linked into the PD binary.
+//  2. Once the coordinator starts to run schedulers on the PD leader, a
+//     scheduler is created for every registered plugin and added after all
+//     built-in schedulers. Its name is the name of the plugin.
+//  3. Schedule is called periodically like other schedulers, but only when
+//     the operator limits of the cluster are not exceeded, and the operators
+//     beyond the limits are dropped.
+//  4. The plugin scheduler can be paused, resumed and removed like other
+//     schedulers. It is created again when the coordinator restarts, e.g.
+//     after a leader change.
+type SchedulerPlugin interface {
+	// Name returns the unique name of the plugin.
+	Name() string
+	// Schedule generates the operators according to the policy.
+	Schedule(cluster Cluster) []*operator.Operator
+	// Config returns the config of the plugin, which is shown as JSON by the API.
+	Config() interface{}
+}
+
+var (
+	schedulerPluginsMu sync.RWMutex
+	schedulerPlugins   []SchedulerPlugin
+)
+
+// RegisterPlugin registers a scheduler plugin. It should be called in init()
+// func of a package.
+func RegisterPlugin(p SchedulerPlugin) {
+	schedulerPluginsMu.Lock()
+	defer schedulerPluginsMu.Unlock()
+	for _, registered := range schedulerPlugins {
+		if registered.Name() == p.Name() {
+			log.Fatal("duplicated scheduler plugin", zap.String("name", p.Name()), errs.ZapError(errs.ErrSchedulerDuplicated))
+		}
+	}
+	schedulerPlugins = append(schedulerPlugins, p)
+}
+
+// GetPlugins returns all the registered scheduler plugins in the order they are registered.
+func GetPlugins() []SchedulerPlugin {
+	schedulerPluginsMu.RLock()
+	defer schedulerPluginsMu.RUnlock()
+	return append([]SchedulerPlugin(nil), schedulerPlugins...)
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+const timeWindowClockLayout = "15:04:05"
+
+// TimeWindow is a daily time window, only the clocks of Start and End are
+// used, which are interpreted in Location. If End is before Start, the window
+// crosses midnight. The zero TimeWindow, or a window whose Start and End are
+// the same, covers the whole day.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+	// Location is the time zone of the window, nil means the local time zone.
+	Location *time.Location
+}
+
+func secondsOfDay(t time.Time) int {
+	hour, min, sec := t.Clock()
+	return hour*3600 + min*60 + sec
+}
+
+// IsZero returns true if the time window does not restrict anything.
+func (tw TimeWindow) IsZero() bool {
+	return secondsOfDay(tw.Start) == secondsOfDay(tw.End)
+}
+
+// Contains checks if the time is inside the time window.
+func (tw TimeWindow) Contains(t time.Time) bool {
+	if tw.IsZero() {
+		return true
+	}
+	loc := tw.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now, start, end := secondsOfDay(t.In(loc)), secondsOfDay(tw.Start), secondsOfDay(tw.End)
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+type timeWindowJSON struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Location string `json:"location"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (tw TimeWindow) MarshalJSON() ([]byte, error) {
+	loc := tw.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return json.Marshal(&timeWindowJSON{
+		Start:    tw.Start.Format(timeWindowClockLayout),
+		End:      tw.End.Format(timeWindowClockLayout),
+		Location: loc.String(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (tw *TimeWindow) UnmarshalJSON(data []byte) error {
+	var v timeWindowJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errs.ErrJSONUnmarshal.Wrap(err).FastGenWithCause()
+	}
+	loc := time.Local
+	if len(v.Location) > 0 {
+		var err error
+		if loc, err = time.LoadLocation(v.Location); err != nil {
+			return errs.ErrSchedulerConfig.FastGenByArgs("time window location " + v.Location)
+		}
+	}
+	start, err := time.ParseInLocation(timeWindowClockLayout, v.Start, loc)
+	if err != nil {
+		return errs.ErrSchedulerConfig.FastGenByArgs("time window start " + v.Start)
+	}
+	end, err := time.ParseInLocation(timeWindowClockLayout, v.End, loc)
+	if err != nil {
+		return errs.ErrSchedulerConfig.FastGenByArgs("time window end " + v.End)
+	}
+	*tw = TimeWindow{Start: start, End: end, Location: loc}
+	return nil
+}
+
+// SchedulerOption is used to decorate a scheduler.
+type SchedulerOption func(Scheduler) Scheduler
+
+// WithTimeWindow restricts the scheduler to generate operators only inside the
+// time window. If the scheduler has been restricted by a time window already,
+// the time window is replaced.
+func WithTimeWindow(tw TimeWindow) SchedulerOption {
+	return func(s Scheduler) Scheduler {
+		ts, ok := s.(*timeWindowScheduler)
+		if !ok {
+			ts = &timeWindowScheduler{Scheduler: s, now: time.Now}
+		}
+		ts.window.Store(tw)
+		return ts
+	}
+}
+
+// GetTimeWindow returns the time window of the scheduler. The zero TimeWindow
+// is returned if the scheduler is not restricted.
+func GetTimeWindow(s Scheduler) TimeWindow {
+	if ts, ok := s.(*timeWindowScheduler); ok {
+		return ts.window.Load().(TimeWindow)
+	}
+	return TimeWindow{}
+}
+
+type timeWindowScheduler struct {
+	Scheduler
+	window atomic.Value
+	now    func() time.Time
+}
+
+func (s *timeWindowScheduler) Schedule(cluster Cluster) []*operator.Operator {
+	if !s.window.Load().(TimeWindow).Contains(s.now()) {
+		return nil
+	}
+	return s.Scheduler.Schedule(cluster)
+}
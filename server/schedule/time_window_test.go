@@ -0,0 +1,107 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testTimeWindowSuite{})
+
+type testTimeWindowSuite struct{}
+
+type mockTimeWindowScheduler struct {
+	Scheduler
+}
+
+func (s *mockTimeWindowScheduler) Schedule(cluster Cluster) []*operator.Operator {
+	return []*operator.Operator{operator.NewTestOperator(1, &metapb.RegionEpoch{}, operator.OpLeader)}
+}
+
+func newTestTimeWindow(loc *time.Location, startHour, endHour int) TimeWindow {
+	return TimeWindow{
+		Start:    time.Date(0, 1, 1, startHour, 0, 0, 0, loc),
+		End:      time.Date(0, 1, 1, endHour, 0, 0, 0, loc),
+		Location: loc,
+	}
+}
+
+func (s *testTimeWindowSuite) TestContains(c *C) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	at := func(hour, min int) time.Time {
+		return time.Date(2021, 6, 1, hour, min, 0, 0, loc)
+	}
+
+	c.Assert(TimeWindow{}.IsZero(), IsTrue)
+	c.Assert(TimeWindow{}.Contains(at(2, 0)), IsTrue)
+
+	tw := newTestTimeWindow(loc, 12, 16)
+	c.Assert(tw.IsZero(), IsFalse)
+	c.Assert(tw.Contains(at(2, 0)), IsFalse)
+	c.Assert(tw.Contains(at(11, 59)), IsFalse)
+	c.Assert(tw.Contains(at(12, 0)), IsTrue)
+	c.Assert(tw.Contains(at(15, 59)), IsTrue)
+	c.Assert(tw.Contains(at(16, 0)), IsFalse)
+	// The time is converted to the location of the window.
+	c.Assert(tw.Contains(time.Date(2021, 6, 1, 5, 0, 0, 0, time.UTC)), IsTrue)
+
+	// The window crosses midnight.
+	tw = newTestTimeWindow(loc, 22, 4)
+	c.Assert(tw.Contains(at(23, 0)), IsTrue)
+	c.Assert(tw.Contains(at(2, 0)), IsTrue)
+	c.Assert(tw.Contains(at(12, 0)), IsFalse)
+}
+
+func (s *testTimeWindowSuite) TestJSON(c *C) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	tw := newTestTimeWindow(time.UTC, 12, 16)
+	data, err := json.Marshal(tw)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"start":"12:00:00","end":"16:00:00","location":"UTC"}`)
+
+	var tw2 TimeWindow
+	c.Assert(json.Unmarshal(data, &tw2), IsNil)
+	c.Assert(tw2.Location, Equals, time.UTC)
+	c.Assert(tw2.Contains(time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)), IsFalse)
+	c.Assert(tw2.Contains(time.Date(2021, 6, 1, 21, 0, 0, 0, loc)), IsTrue)
+
+	c.Assert(json.Unmarshal([]byte(`{"start":"12:00","end":"16:00:00"}`), &tw2), NotNil)
+	c.Assert(json.Unmarshal([]byte(`{"start":"12:00:00","end":"16:00:00","location":"Nowhere/Nowhere"}`), &tw2), NotNil)
+}
+
+func (s *testTimeWindowSuite) TestWithTimeWindow(c *C) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2021, 6, 1, 2, 0, 0, 0, loc)
+	sche := WithTimeWindow(newTestTimeWindow(loc, 12, 16))(&mockTimeWindowScheduler{})
+	sche.(*timeWindowScheduler).now = func() time.Time { return now }
+
+	// No operator is emitted at 2 AM when the window is noon to 4 PM.
+	c.Assert(sche.Schedule(nil), HasLen, 0)
+	now = time.Date(2021, 6, 1, 13, 0, 0, 0, loc)
+	c.Assert(sche.Schedule(nil), HasLen, 1)
+
+	// Applying the option again replaces the window instead of wrapping twice.
+	c.Assert(WithTimeWindow(newTestTimeWindow(loc, 22, 4))(sche), Equals, sche)
+	c.Assert(GetTimeWindow(sche), DeepEquals, newTestTimeWindow(loc, 22, 4))
+	c.Assert(sche.Schedule(nil), HasLen, 0)
+	WithTimeWindow(TimeWindow{})(sche)
+	c.Assert(sche.Schedule(nil), HasLen, 1)
+	c.Assert(GetTimeWindow(&mockTimeWindowScheduler{}).IsZero(), IsTrue)
+}
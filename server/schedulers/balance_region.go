@@ -23,9 +23,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/keyspace"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/filter"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage/endpoint"
 	"go.uber.org/zap"
 )
@@ -97,6 +99,7 @@ func newBalanceRegionScheduler(opController *schedule.OperatorController, conf *
 	scheduler.filters = []filter.Filter{
 		&filter.StoreStateFilter{ActionScope: scheduler.GetName(), MoveRegion: true},
 		filter.NewSpecialUseFilter(scheduler.GetName()),
+		filter.NewWarmUpFilter(scheduler.GetName()),
 	}
 	return scheduler
 }
@@ -155,6 +158,11 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.
 			stores[j].RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), jOp)
 	})
 
+	var ioRates map[uint64]float64
+	if opts.GetRegionIOImbalanceThreshold() > 0 {
+		ioRates = getStoresIORates(cluster)
+	}
+
 	var allowBalanceEmptyRegion func(*core.RegionInfo) bool
 
 	switch cluster.(type) {
@@ -203,7 +211,7 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.
 				continue
 			}
 
-			if op := s.transferPeer(plan); op != nil {
+			if op := s.transferPeer(plan, ioRates); op != nil {
 				s.retryQuota.ResetLimit(plan.source)
 				op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
 				return []*operator.Operator{op}
@@ -216,7 +224,9 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*operator.
 }
 
 // transferPeer selects the best store to create a new peer to replace the old peer.
-func (s *balanceRegionScheduler) transferPeer(plan *balancePlan) *operator.Operator {
+// ioRates are the disk IO rates of the stores, which are nil if the IO
+// imbalance is not considered.
+func (s *balanceRegionScheduler) transferPeer(plan *balancePlan, ioRates map[uint64]float64) *operator.Operator {
 	filters := []filter.Filter{
 		filter.NewExcludedFilter(s.GetName(), nil, plan.region.GetStoreIds()),
 		filter.NewPlacementSafeguard(s.GetName(), plan.GetOpts(), plan.GetBasicCluster(), plan.GetRuleManager(), plan.region, plan.source),
@@ -224,6 +234,9 @@ func (s *balanceRegionScheduler) transferPeer(plan *balancePlan) *operator.Opera
 		filter.NewSpecialUseFilter(s.GetName()),
 		&filter.StoreStateFilter{ActionScope: s.GetName(), MoveRegion: true},
 	}
+	if constraints := keyspace.GetRegionAffinityConstraints(plan.Cluster, plan.region); len(constraints) > 0 {
+		filters = append(filters, filter.NewLabelConstaintFilter(s.GetName(), constraints))
+	}
 
 	candidates := filter.NewCandidates(plan.GetStores()).
 		FilterTarget(plan.GetOpts(), filters...).
@@ -235,9 +248,13 @@ func (s *balanceRegionScheduler) transferPeer(plan *balancePlan) *operator.Opera
 		targetID := plan.target.GetID()
 		log.Debug("", zap.Uint64("region-id", regionID), zap.Uint64("source-store", sourceID), zap.Uint64("target-store", targetID))
 
+		ioImbalanced := false
 		if !plan.shouldBalance(s.GetName()) {
-			schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
-			continue
+			if ioImbalanced = shouldBalanceIO(plan, ioRates); !ioImbalanced {
+				schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
+				continue
+			}
+			schedulerCounter.WithLabelValues(s.GetName(), "io-imbalance").Inc()
 		}
 
 		oldPeer := plan.region.GetStorePeer(sourceID)
@@ -256,6 +273,10 @@ func (s *balanceRegionScheduler) transferPeer(plan *balancePlan) *operator.Opera
 		)
 		op.AdditionalInfos["sourceScore"] = strconv.FormatFloat(plan.sourceScore, 'f', 2, 64)
 		op.AdditionalInfos["targetScore"] = strconv.FormatFloat(plan.targetScore, 'f', 2, 64)
+		if ioImbalanced {
+			op.AdditionalInfos["sourceIORate"] = strconv.FormatFloat(ioRates[sourceID], 'f', 2, 64)
+			op.AdditionalInfos["targetIORate"] = strconv.FormatFloat(ioRates[targetID], 'f', 2, 64)
+		}
 		return op
 	}
 
@@ -263,6 +284,28 @@ func (s *balanceRegionScheduler) transferPeer(plan *balancePlan) *operator.Opera
 	return nil
 }
 
+// getStoresIORates returns the sum of the disk read and write rates of the stores.
+func getStoresIORates(cluster schedule.Cluster) map[uint64]float64 {
+	storesLoads := cluster.GetStoresLoads()
+	ioRates := make(map[uint64]float64, len(storesLoads))
+	for storeID, loads := range storesLoads {
+		ioRates[storeID] = loads[statistics.StoreDiskReadRate] + loads[statistics.StoreDiskWriteRate]
+	}
+	return ioRates
+}
+
+// shouldBalanceIO checks if the region should be moved since the disk IO rate
+// of the source store exceeds (1 + region-io-imbalance-threshold) times that
+// of the target store. It is only checked when the region scores are close,
+// the region score filter already keeps the target score below the source.
+func shouldBalanceIO(plan *balancePlan, ioRates map[uint64]float64) bool {
+	if ioRates == nil {
+		return false
+	}
+	sourceIO, targetIO := ioRates[plan.SourceStoreID()], ioRates[plan.TargetStoreID()]
+	return sourceIO > targetIO*(1+plan.GetOpts().GetRegionIOImbalanceThreshold())
+}
+
 // isEmptyRegionAllowBalance checks if a region is an empty region and can be balanced.
 func isEmptyRegionAllowBalance(cluster schedule.Cluster, region *core.RegionInfo) bool {
 	return region.GetApproximateSize() > core.EmptyRegionApproximateSize || cluster.GetRegionCount() < balanceEmptyRegionThreshold
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -710,6 +711,45 @@ func (s *testBalanceRegionSchedulerSuite) TestBalance(c *C) {
 	c.Assert(len(sb.Schedule(tc)), Greater, 0)
 }
 
+func (s *testBalanceRegionSchedulerSuite) TestIOImbalance(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	sb, err := schedule.CreateScheduler(BalanceRegionType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	c.Assert(err, IsNil)
+
+	opt.SetMaxReplicas(1)
+	tc.AddRegionStore(1, 11)
+	tc.AddRegionStore(2, 10)
+	tc.AddLeaderRegion(1, 1)
+	tc.AddLeaderRegion(2, 2)
+	// The disk IO rate of store 1 is 4 times that of store 2.
+	tc.UpdateStorageIORates(1, 2000, 2000)
+	tc.UpdateStorageIORates(2, 500, 500)
+
+	// The region scores are close enough.
+	c.Assert(sb.Schedule(tc), HasLen, 0)
+
+	tc.SetRegionIOImbalanceThreshold(1)
+	ops := sb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferPeerWithLeaderTransfer(c, ops[0], operator.OpKind(0), 1, 2)
+	c.Assert(ops[0].AdditionalInfos["sourceIORate"], Equals, "4000.00")
+	c.Assert(ops[0].AdditionalInfos["targetIORate"], Equals, "1000.00")
+
+	// The IO imbalance does not exceed the threshold.
+	tc.SetRegionIOImbalanceThreshold(3)
+	c.Assert(sb.Schedule(tc), HasLen, 0)
+
+	// The region is not moved to the store with a higher region score.
+	tc.SetRegionIOImbalanceThreshold(1)
+	tc.UpdateRegionCount(2, 12)
+	c.Assert(sb.Schedule(tc), HasLen, 0)
+}
+
 func (s *testBalanceRegionSchedulerSuite) TestReplicas3(c *C) {
 	opt := config.NewTestOptions()
 	// TODO: enable placementrules
@@ -1352,6 +1392,88 @@ func (s *testScatterRangeSuite) TestBalanceWhenRegionNotHeartbeat(c *C) {
 	scheduleAndApplyOperator(tc, hb, 100)
 }
 
+func (s *testScatterRangeSuite) TestMultipleRanges(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetTolerantSizeRatio(10000)
+	// Add stores 1,2,3,4,5.
+	for i := uint64(1); i <= 5; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	// The regions of two tables are all on stores 1,2,3 and led by store 1.
+	var id uint64
+	key := func(i int) []byte {
+		return []byte(fmt.Sprintf("t%d_%02d", i/30+1, i%30))
+	}
+	for i := 0; i < 60; i++ {
+		meta := &metapb.Region{
+			Id: id + 4,
+			Peers: []*metapb.Peer{
+				{Id: id + 1, StoreId: 1},
+				{Id: id + 2, StoreId: 2},
+				{Id: id + 3, StoreId: 3},
+			},
+			StartKey: key(i),
+			EndKey:   key(i + 1),
+		}
+		id += 4
+		tc.Regions.SetRegion(core.NewRegionInfo(meta, meta.Peers[0], core.SetApproximateKeys(1), core.SetApproximateSize(1)))
+	}
+	for i := 0; i < 200; i++ {
+		_, err := tc.AllocPeer(1)
+		c.Assert(err, IsNil)
+	}
+	for i := 1; i <= 5; i++ {
+		tc.UpdateStoreStatus(uint64(i))
+	}
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	hb, err := schedule.CreateScheduler(ScatterRangeType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(ScatterRangeType, []string{"t1_00", "t2_00", "t"}))
+	c.Assert(err, IsNil)
+	conf := hb.(*scatterRangeScheduler).config
+	c.Assert(conf.AddRange([]byte("t1_10"), []byte("t1_20")), NotNil)
+	c.Assert(conf.AddRange([]byte("t2_00"), []byte("t1_00")), NotNil)
+	c.Assert(conf.AddRange([]byte("t2_00"), []byte("t3_00")), IsNil)
+	c.Assert(conf.GetRanges(), HasLen, 2)
+	// The first range can not be updated to overlap with the other ranges.
+	c.Assert(conf.BuildWithArgs([]string{"t", "t1_00", "t2_10"}), NotNil)
+	c.Assert(conf.GetStartKey(), DeepEquals, []byte("t1_00"))
+	c.Assert(conf.GetEndKey(), DeepEquals, []byte("t2_00"))
+	c.Assert(conf.GetRanges()[0].EndKey, DeepEquals, []byte("t2_00"))
+
+	scheduleAndApplyOperator(tc, hb, 100)
+	for _, r := range conf.GetRanges() {
+		leaderCounts := make(map[uint64]int)
+		regionCounts := make(map[uint64]int)
+		for _, region := range tc.ScanRegions(r.StartKey, r.EndKey, -1) {
+			leaderCounts[region.GetLeader().GetStoreId()]++
+			for _, peer := range region.GetPeers() {
+				regionCounts[peer.GetStoreId()]++
+			}
+		}
+		// Each range is scattered across all the stores.
+		for i := uint64(1); i <= 5; i++ {
+			c.Check(leaderCounts[i], Greater, 2)
+			c.Check(leaderCounts[i], LessEqual, 8)
+			c.Check(regionCounts[i], Greater, 14)
+			c.Check(regionCounts[i], LessEqual, 22)
+		}
+	}
+	for _, p := range hb.(*scatterRangeScheduler).getProgress() {
+		c.Assert(p.LeaderOperators, Greater, uint64(0))
+		c.Assert(p.RegionOperators, Greater, uint64(0))
+		c.Assert(p.Balanced, IsTrue)
+	}
+
+	c.Assert(conf.RemoveRange([]byte("t3_00")), NotNil)
+	c.Assert(conf.RemoveRange([]byte("t1_00")), IsNil)
+	c.Assert(conf.RemoveRange([]byte("t2_00")), NotNil)
+	c.Assert(conf.GetStartKey(), DeepEquals, []byte("t2_00"))
+	c.Assert(conf.GetEndKey(), DeepEquals, []byte("t3_00"))
+}
+
 // scheduleAndApplyOperator will try to schedule for `count` times and apply the operator if the operator is created.
 func scheduleAndApplyOperator(tc *mockcluster.Cluster, hb schedule.Scheduler, count int) {
 	limit := 0
@@ -1367,3 +1489,404 @@ func scheduleAndApplyOperator(tc *mockcluster.Cluster, hb schedule.Scheduler, co
 		schedule.ApplyOperator(tc, ops[0])
 	}
 }
+
+var _ = Suite(&testLabelBalanceLeaderSchedulerSuite{})
+
+type testLabelBalanceLeaderSchedulerSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	tc     *mockcluster.Cluster
+	oc     *schedule.OperatorController
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) SetUpTest(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.tc = mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	s.oc = schedule.NewOperatorController(s.ctx, s.tc, nil)
+	// Stores:  1    2    3    4    5    6
+	// Zone:    z1   z1   z2   z2   z3   z3
+	for i := uint64(1); i <= 6; i++ {
+		s.tc.AddLabelsStore(i, 0, map[string]string{"zone": fmt.Sprintf("z%d", (i+1)/2)})
+	}
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TearDownTest(c *C) {
+	s.cancel()
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) createScheduler(c *C, args ...string) schedule.Scheduler {
+	lb, err := schedule.CreateScheduler(LabelBalanceLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(LabelBalanceLeaderType, args))
+	c.Assert(err, IsNil)
+	return lb
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) zoneLeaderCount(zone string) int {
+	count := 0
+	for _, store := range s.tc.GetStores() {
+		if store.GetLabelValue("zone") == zone {
+			count += s.tc.Regions.GetStoreLeaderCount(store.GetID())
+		}
+	}
+	return count
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestConfig(c *C) {
+	_, err := schedule.CreateScheduler(LabelBalanceLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(LabelBalanceLeaderType, nil))
+	c.Assert(err, NotNil)
+	_, err = schedule.CreateScheduler(LabelBalanceLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(LabelBalanceLeaderType, []string{"zone", "abc"}))
+	c.Assert(err, NotNil)
+	_, err = schedule.CreateScheduler(LabelBalanceLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(LabelBalanceLeaderType, []string{"zone", "-1"}))
+	c.Assert(err, NotNil)
+
+	lb := s.createScheduler(c, "zone")
+	c.Assert(lb.GetName(), Equals, LabelBalanceLeaderName)
+	conf := lb.(*labelBalanceLeaderScheduler).conf
+	c.Assert(conf.BalanceLabelKey, Equals, "zone")
+	c.Assert(conf.Tolerance, Equals, DefaultLabelBalanceTolerance)
+	lb = s.createScheduler(c, "zone", "0.2")
+	c.Assert(lb.(*labelBalanceLeaderScheduler).conf.Tolerance, Equals, 0.2)
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestBalance(c *C) {
+	lb := s.createScheduler(c, "zone")
+	// Zone:       z1        z2        z3
+	// Leaders:    12(6+6)   1(1+0)    5(3+2)
+	// Region1:    L(1)      F(3)      F(5)
+	s.tc.UpdateLeaderCount(1, 6)
+	s.tc.UpdateLeaderCount(2, 6)
+	s.tc.UpdateLeaderCount(3, 1)
+	s.tc.UpdateLeaderCount(5, 3)
+	s.tc.UpdateLeaderCount(6, 2)
+	s.tc.AddLeaderRegion(1, 1, 3, 5)
+	ops := lb.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 1, 3)
+
+	// The source store and the target store are chosen by the leader count
+	// inside the zones.
+	// Zone:       z1        z2        z3
+	// Leaders:    6(0+6)    1(1+0)    5(3+2)
+	// Region2:    L(2)      F(3),F(4) F(5)
+	s.tc.UpdateLeaderCount(1, 0)
+	s.tc.AddLeaderRegion(2, 2, 3, 4, 5)
+	ops = lb.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 2, 4)
+
+	// The operator influence is taken into account, (6-1)-(1+1) > 1.
+	s.oc.SetOperator(ops[0])
+	c.Assert(lb.Schedule(s.tc), NotNil)
+
+	// Zone:       z1        z2        z3
+	// Leaders:    6(0+6)    5(0+5)    5(3+2)
+	s.tc.UpdateLeaderCount(4, 5)
+	s.tc.UpdateLeaderCount(3, 0)
+	s.oc.RemoveOperator(ops[0])
+	c.Assert(lb.Schedule(s.tc), HasLen, 0)
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestTolerance(c *C) {
+	// Zone:       z1   z2   z3
+	// Leaders:    12   10   8
+	s.tc.UpdateLeaderCount(1, 12)
+	s.tc.UpdateLeaderCount(3, 10)
+	s.tc.UpdateLeaderCount(5, 8)
+	s.tc.AddLeaderRegion(1, 1, 3, 5)
+	c.Assert(s.createScheduler(c, "zone").Schedule(s.tc), HasLen, 1)
+	// 12-8 <= 0.4*10
+	c.Assert(s.createScheduler(c, "zone", "0.4").Schedule(s.tc), HasLen, 0)
+	c.Assert(s.createScheduler(c, "zone", "0.3").Schedule(s.tc), HasLen, 1)
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestIgnoreStores(c *C) {
+	lb := s.createScheduler(c, "rack")
+	s.tc.UpdateLeaderCount(1, 10)
+	s.tc.AddLeaderRegion(1, 1, 3, 5)
+	// No store has the label.
+	c.Assert(lb.Schedule(s.tc), HasLen, 0)
+
+	// Only one label value is valid.
+	s.tc.AddLabelsStore(7, 0, map[string]string{"rack": "r1"})
+	s.tc.AddLabelsStore(8, 0, map[string]string{"rack": "r2"})
+	s.tc.SetStoreDown(8)
+	s.tc.UpdateLeaderCount(7, 10)
+	s.tc.AddLeaderRegion(2, 7, 8)
+	c.Assert(lb.Schedule(s.tc), HasLen, 0)
+
+	// The leader can not be transferred to a store without the label.
+	s.tc.SetStoreUp(8)
+	s.tc.AddLeaderRegion(2, 7, 1)
+	c.Assert(lb.Schedule(s.tc), HasLen, 0)
+	s.tc.AddLeaderRegion(2, 7, 8)
+	ops := lb.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 7, 8)
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestOperatorLimit(c *C) {
+	lb := s.createScheduler(c, "zone")
+	c.Assert(lb.IsScheduleAllowed(s.tc), IsTrue)
+	s.tc.SetLeaderScheduleLimit(0)
+	c.Assert(lb.IsScheduleAllowed(s.tc), IsFalse)
+}
+
+func (s *testLabelBalanceLeaderSchedulerSuite) TestThreeZones(c *C) {
+	// Every region has one peer in each zone, and all the leaders are in z1.
+	for i := uint64(1); i <= 60; i++ {
+		s.tc.AddLeaderRegion(i, 1+i%2, 3+i%2, 5+i%2)
+	}
+	for i := uint64(1); i <= 6; i++ {
+		s.tc.UpdateStoreStatus(i)
+	}
+	c.Assert(s.zoneLeaderCount("z1"), Equals, 60)
+
+	lb := s.createScheduler(c, "zone")
+	scheduleAndApplyOperator(s.tc, lb, 100)
+	for _, zone := range []string{"z1", "z2", "z3"} {
+		c.Assert(s.zoneLeaderCount(zone), Equals, 20)
+	}
+}
+
+var _ = Suite(&testTieredBalanceSchedulerSuite{})
+
+type testTieredBalanceSchedulerSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *testTieredBalanceSchedulerSuite) SetUpSuite(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *testTieredBalanceSchedulerSuite) TearDownSuite(c *C) {
+	s.cancel()
+}
+
+func (s *testTieredBalanceSchedulerSuite) TestConfig(c *C) {
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+	sche, err := schedule.CreateScheduler(TieredBalanceType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(TieredBalanceType, []string{}))
+	c.Assert(err, IsNil)
+	conf := sche.(*tieredBalanceScheduler).conf
+	c.Assert(conf.LeaderTolerance, Equals, DefaultTieredLeaderTolerance)
+	c.Assert(conf.PeerTolerance, Equals, DefaultTieredPeerTolerance)
+	sche, err = schedule.CreateScheduler(TieredBalanceType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(TieredBalanceType, []string{"0.2", "0.3"}))
+	c.Assert(err, IsNil)
+	conf = sche.(*tieredBalanceScheduler).conf
+	c.Assert(conf.LeaderTolerance, Equals, 0.2)
+	c.Assert(conf.PeerTolerance, Equals, 0.3)
+
+	for _, args := range [][]string{{"abc"}, {"-1"}, {"0.1", "-1"}, {"0.1", "0.1", "0.1"}} {
+		_, err = schedule.CreateScheduler(TieredBalanceType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(TieredBalanceType, args))
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testTieredBalanceSchedulerSuite) TestLeaderBalanceFirst(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetTolerantSizeRatio(1)
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	// Simulate a newly bootstrapped cluster: store 1 has a peer of every
+	// region and leads all of them.
+	var id uint64
+	for i := 0; i < 40; i++ {
+		meta := &metapb.Region{
+			Id: id + 4,
+			Peers: []*metapb.Peer{
+				{Id: id + 1, StoreId: 1},
+				{Id: id + 2, StoreId: uint64(2 + i%3)},
+				{Id: id + 3, StoreId: uint64(2 + (i+1)%3)},
+			},
+			StartKey: []byte(fmt.Sprintf("t_%02d", i)),
+			EndKey:   []byte(fmt.Sprintf("t_%02d", i+1)),
+		}
+		if i == 39 {
+			meta.EndKey = []byte("")
+		}
+		id += 4
+		tc.Regions.SetRegion(core.NewRegionInfo(meta, meta.Peers[0], core.SetApproximateKeys(1), core.SetApproximateSize(1)))
+	}
+	for i := 0; i < 200; i++ {
+		_, err := tc.AllocPeer(1)
+		c.Assert(err, IsNil)
+	}
+	for i := uint64(1); i <= 4; i++ {
+		tc.UpdateStoreStatus(i)
+	}
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+	// The leader tolerance should not be tighter than what the balance leader
+	// scheduler can reach with the tolerant size ratio.
+	hb, err := schedule.CreateScheduler(TieredBalanceType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(TieredBalanceType, []string{"0.2", "0.1"}))
+	c.Assert(err, IsNil)
+	sche := hb.(*tieredBalanceScheduler)
+	leaderBalanced := func() bool { return sche.isLeaderBalanced(tc, oc.GetOpInfluence(tc)) }
+	peerBalanced := func() bool { return sche.isPeerBalanced(tc, oc.GetOpInfluence(tc)) }
+	c.Assert(leaderBalanced(), IsFalse)
+	c.Assert(peerBalanced(), IsFalse)
+
+	leaderBalancedStep, peerBalancedStep := -1, -1
+	for step := 0; step < 200 && (leaderBalancedStep < 0 || peerBalancedStep < 0); step++ {
+		ops := hb.Schedule(tc)
+		c.Assert(ops, Not(HasLen), 0)
+		for _, op := range ops {
+			// No peer is moved before the leaders are balanced.
+			if leaderBalancedStep < 0 {
+				c.Assert(op.Kind()&operator.OpLeader, Not(Equals), operator.OpKind(0))
+				c.Assert(op.Kind()&operator.OpRegion, Equals, operator.OpKind(0))
+			}
+			schedule.ApplyOperator(tc, op)
+		}
+		if leaderBalancedStep < 0 && leaderBalanced() {
+			leaderBalancedStep = step
+		}
+		if peerBalancedStep < 0 && peerBalanced() {
+			peerBalancedStep = step
+		}
+	}
+	c.Assert(leaderBalancedStep, Not(Equals), -1)
+	c.Assert(peerBalancedStep, Greater, leaderBalancedStep)
+	c.Assert(leaderBalanced(), IsTrue)
+	c.Assert(hb.Schedule(tc), HasLen, 0)
+}
+
+func (s *testBalanceRegionSchedulerSuite) TestStoreWarmUp(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(s.ctx, opt)
+	// TODO: enable placementrules
+	tc.SetPlacementRuleEnabled(false)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	sb, err := schedule.CreateScheduler(BalanceRegionType, oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	opt.SetMaxReplicas(1)
+
+	for id := uint64(1); id <= 4; id++ {
+		tc.AddRegionStore(id, 0)
+	}
+	for id := uint64(1); id <= 90; id++ {
+		tc.AddLeaderRegion(id, (id-1)%3+1)
+	}
+	for id := uint64(1); id <= 4; id++ {
+		tc.UpdateStoreStatus(id)
+	}
+
+	// Store 4 joins newly, the regions are moved into it gradually while
+	// it warms up.
+	duration := 30 * time.Minute
+	start := time.Now()
+	lastCount := 0
+	var counts []int
+	for elapsed := time.Duration(0); elapsed <= duration; elapsed += 5 * time.Minute {
+		tc.SetStoreWarmUp(4, start.Add(-elapsed), duration)
+		for i := 0; i < 100; i++ {
+			ops := sb.Schedule(tc)
+			if len(ops) == 0 {
+				break
+			}
+			// The warming-up store is not the source.
+			c.Assert(tc.GetRegion(ops[0].RegionID()).GetStorePeer(4), IsNil)
+			schedule.ApplyOperator(tc, ops[0])
+		}
+		count := tc.GetStoreRegionCount(4)
+		c.Assert(count, GreaterEqual, lastCount)
+		lastCount = count
+		counts = append(counts, count)
+	}
+	c.Assert(counts[0], Equals, 0)
+	c.Assert(counts[len(counts)/2], Less, counts[len(counts)-1])
+}
+
+var _ = Suite(&testZoneAwareLeaderSchedulerSuite{})
+
+type testZoneAwareLeaderSchedulerSuite struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	tc     *mockcluster.Cluster
+	oc     *schedule.OperatorController
+}
+
+func (s *testZoneAwareLeaderSchedulerSuite) SetUpTest(c *C) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.tc = mockcluster.NewCluster(s.ctx, config.NewTestOptions())
+	s.oc = schedule.NewOperatorController(s.ctx, s.tc, nil)
+	// Stores:  1    2    3    4    5    6
+	// Zone:    z1   z1   z2   z2   z3   z3
+	for i := uint64(1); i <= 6; i++ {
+		s.tc.AddLabelsStore(i, 0, map[string]string{"zone": fmt.Sprintf("z%d", (i+1)/2)})
+	}
+}
+
+func (s *testZoneAwareLeaderSchedulerSuite) TearDownTest(c *C) {
+	s.cancel()
+}
+
+func (s *testZoneAwareLeaderSchedulerSuite) TestConfig(c *C) {
+	_, err := schedule.CreateScheduler(ZoneAwareLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(ZoneAwareLeaderType, []string{"zone", "host"}))
+	c.Assert(err, NotNil)
+	zl, err := schedule.CreateScheduler(ZoneAwareLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(ZoneAwareLeaderType, nil))
+	c.Assert(err, IsNil)
+	c.Assert(zl.GetName(), Equals, ZoneAwareLeaderName)
+	c.Assert(zl.(*zoneAwareLeaderScheduler).conf.ZoneLabelKey, Equals, DefaultZoneLabelKey)
+	zl, err = schedule.CreateScheduler(ZoneAwareLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(ZoneAwareLeaderType, []string{"dc"}))
+	c.Assert(err, IsNil)
+	c.Assert(zl.(*zoneAwareLeaderScheduler).conf.ZoneLabelKey, Equals, "dc")
+}
+
+func (s *testZoneAwareLeaderSchedulerSuite) TestSchedule(c *C) {
+	zl, err := schedule.CreateScheduler(ZoneAwareLeaderType, s.oc, storage.NewStorageWithMemoryBackend(), schedule.ConfigSliceDecoder(ZoneAwareLeaderType, nil))
+	c.Assert(err, IsNil)
+	// Region:     1         2
+	// Range:      [a, b)    [b, c)
+	// Peers:      L(1) F(3) F(5)  L(1) F(4) F(6)
+	s.tc.AddLeaderRegionWithRange(1, "a", "b", 1, 3, 5)
+	s.tc.AddLeaderRegionWithRange(2, "b", "c", 1, 4, 6)
+	s.tc.UpdateLeaderCount(3, 10)
+
+	// disabled by default
+	c.Assert(zl.IsScheduleAllowed(s.tc), IsFalse)
+	s.tc.GetOpts().GetScheduleConfig().EnableZoneAwareLeader = true
+	c.Assert(zl.IsScheduleAllowed(s.tc), IsTrue)
+
+	// no hint
+	c.Assert(zl.Schedule(s.tc), HasLen, 0)
+
+	// The leader moves to the zone with the most requests.
+	zl.(*zoneAwareLeaderScheduler).setHints([]*zoneTrafficHint{
+		{StartKey: "a", EndKey: "b", ZoneRequests: map[string]uint64{"z1": 10, "z2": 100, "z3": 50}},
+	})
+	ops := zl.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 1, 3)
+
+	// The hints with more requests are scheduled first, and the follower
+	// with the least leaders in the zone is chosen.
+	zl.(*zoneAwareLeaderScheduler).setHints([]*zoneTrafficHint{
+		{StartKey: "a", EndKey: "b", ZoneRequests: map[string]uint64{"z3": 10}},
+		{StartKey: "a", EndKey: "", ZoneRequests: map[string]uint64{"z2": 100}},
+	})
+	ops = zl.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 1, 3)
+	schedule.ApplyOperator(s.tc, ops[0])
+	ops = zl.Schedule(s.tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpLeader, 1, 4)
+	schedule.ApplyOperator(s.tc, ops[0])
+
+	// The leaders are all in the hot zone.
+	zl.(*zoneAwareLeaderScheduler).setHints([]*zoneTrafficHint{
+		{StartKey: "a", EndKey: "c", ZoneRequests: map[string]uint64{"z1": 10, "z2": 100}},
+	})
+	c.Assert(zl.Schedule(s.tc), HasLen, 0)
+
+	// No follower in the hot zone.
+	zl.(*zoneAwareLeaderScheduler).setHints([]*zoneTrafficHint{
+		{StartKey: "a", EndKey: "c", ZoneRequests: map[string]uint64{"z4": 100}},
+	})
+	c.Assert(zl.Schedule(s.tc), HasLen, 0)
+}
@@ -21,17 +21,21 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/keyspace"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/filter"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage/endpoint"
 	"go.uber.org/zap"
@@ -72,6 +76,10 @@ const (
 
 	minHotScheduleInterval = time.Second
 	maxHotScheduleInterval = 20 * time.Second
+
+	// maxScatterHistory is the max number of regions whose last schedule time
+	// is kept for the scatter cool-off.
+	maxScatterHistory = 10000
 )
 
 var (
@@ -92,6 +100,9 @@ type hotScheduler struct {
 	// this records regionID which have pending Operator by operation type. During filterHotPeers, the hot peers won't
 	// be selected if its owner region is tracked in this attribute.
 	regionPendings map[uint64]*pendingInfluence
+	// lastScatters stores regionID -> the time the region is last scheduled.
+	// The region won't be selected again during the scatter cool-off.
+	lastScatters cache.Cache
 
 	// store information, including pending Influence by resource type
 	// Every time `Schedule()` will recalculate it.
@@ -112,6 +123,7 @@ func newHotScheduler(opController *schedule.OperatorController, conf *hotRegionS
 		types:          []statistics.RWType{statistics.Write, statistics.Read},
 		r:              rand.New(rand.NewSource(time.Now().UnixNano())),
 		regionPendings: make(map[uint64]*pendingInfluence),
+		lastScatters:   cache.NewCache(maxScatterHistory, cache.LRUCache),
 		conf:           conf,
 	}
 	for ty := resourceType(0); ty < resourceTypeLen; ty++ {
@@ -253,11 +265,26 @@ func (h *hotScheduler) tryAddPendingInfluence(op *operator.Operator, srcStore, d
 
 	influence := newPendingInfluence(op, srcStore, dstStore, infl, maxZombieDur)
 	h.regionPendings[regionID] = influence
+	h.lastScatters.Put(regionID, time.Now())
 
 	schedulerStatus.WithLabelValues(h.GetName(), "pending_op_infos").Inc()
 	return true
 }
 
+// isInScatterCoolOff checks whether the region is scheduled by the hot
+// scheduler within the scatter cool-off duration.
+func (h *hotScheduler) isInScatterCoolOff(regionID uint64) bool {
+	coolOff := h.conf.GetScatterCoolOffDuration()
+	if coolOff <= 0 {
+		return false
+	}
+	v, ok := h.lastScatters.Peek(regionID)
+	if !ok {
+		return false
+	}
+	return time.Since(v.(time.Time)) < coolOff
+}
+
 func (h *hotScheduler) balanceHotReadRegions(cluster schedule.Cluster) []*operator.Operator {
 	leaderSolver := newBalanceSolver(h, cluster, statistics.Read, transferLeader)
 	leaderOps := leaderSolver.solve()
@@ -344,6 +371,10 @@ type balanceSolver struct {
 	// they may be byte(0), key(1), query(2), and always less than dimLen
 	firstPriority  int
 	secondPriority int
+
+	// pinnedRegions are the regions whose leaders are too hot for reads to
+	// be moved.
+	pinnedRegions map[uint64]struct{}
 }
 
 type solution struct {
@@ -361,6 +392,9 @@ type solution struct {
 func (bs *balanceSolver) init() {
 	// Init store load detail according to the type.
 	bs.stLoadDetail = bs.sche.stLoadInfos[toResourceType(bs.rwTy, bs.opTy)]
+	if bs.GetOpts().IsPinReadHotRegionsEnabled() {
+		bs.pinnedRegions = pinnedReadHotRegions(bs.RegionReadStats(), bs.GetOpts().GetReadHotPinThreshold())
+	}
 
 	bs.maxSrc = &statistics.StoreLoad{Loads: make([]float64, statistics.DimLen)}
 	bs.minDst = &statistics.StoreLoad{
@@ -547,11 +581,19 @@ func (bs *balanceSolver) filterHotPeers() []*statistics.HotPeerStat {
 	// filter pending region
 	appendItem := func(items []*statistics.HotPeerStat, item *statistics.HotPeerStat) []*statistics.HotPeerStat {
 		minHotDegree := bs.GetOpts().GetHotRegionCacheHitsThreshold()
-		if _, ok := bs.sche.regionPendings[item.ID()]; !ok && !item.IsNeedCoolDownTransferLeader(minHotDegree) {
-			// no in pending operator and no need cool down after transfer leader
-			items = append(items, item)
+		if _, ok := bs.sche.regionPendings[item.ID()]; ok || item.IsNeedCoolDownTransferLeader(minHotDegree) {
+			// in pending operator or need cool down after transfer leader
+			return items
 		}
-		return items
+		if bs.isLeaderPinned(item) {
+			schedulerCounter.WithLabelValues(bs.sche.GetName(), "read-hot-pinned").Inc()
+			return items
+		}
+		if bs.sche.isInScatterCoolOff(item.ID()) {
+			schedulerCounter.WithLabelValues(bs.sche.GetName(), "scatter-cool-off").Inc()
+			return items
+		}
+		return append(items, item)
 	}
 	if len(ret) <= maxPeerNum {
 		nret := make([]*statistics.HotPeerStat, 0, len(ret))
@@ -569,6 +611,27 @@ func (bs *balanceSolver) filterHotPeers() []*statistics.HotPeerStat {
 	return ret
 }
 
+// isLeaderPinned checks whether scheduling the peer moves the pinned leader.
+func (bs *balanceSolver) isLeaderPinned(peer *statistics.HotPeerStat) bool {
+	if _, ok := bs.pinnedRegions[peer.ID()]; !ok {
+		return false
+	}
+	return bs.opTy == transferLeader || peer.IsLeader()
+}
+
+// pinnedReadHotRegions returns the regions whose leader read QPS exceeds the threshold.
+func pinnedReadHotRegions(readStats map[uint64][]*statistics.HotPeerStat, threshold float64) map[uint64]struct{} {
+	pinned := make(map[uint64]struct{})
+	for _, peers := range readStats {
+		for _, peer := range peers {
+			if peer.IsLeader() && peer.GetLoad(statistics.RegionReadQuery) > threshold {
+				pinned[peer.ID()] = struct{}{}
+			}
+		}
+	}
+	return pinned
+}
+
 func (bs *balanceSolver) sortHotPeers(ret []*statistics.HotPeerStat, maxPeerNum int) map[*statistics.HotPeerStat]struct{} {
 	firstSort := make([]*statistics.HotPeerStat, len(ret))
 	copy(firstSort, ret)
@@ -676,6 +739,9 @@ func (bs *balanceSolver) filterDstStores() map[uint64]*statistics.StoreLoadDetai
 			filter.NewSpecialUseFilter(bs.sche.GetName(), filter.SpecialUseHotRegion),
 			filter.NewPlacementSafeguard(bs.sche.GetName(), bs.GetOpts(), bs.GetBasicCluster(), bs.GetRuleManager(), bs.cur.region, srcStore),
 		}
+		if constraints := keyspace.GetRegionAffinityConstraints(bs.Cluster, bs.cur.region); len(constraints) > 0 {
+			filters = append(filters, filter.NewLabelConstaintFilter(bs.sche.GetName(), constraints))
+		}
 
 		for _, detail := range bs.stLoadDetail {
 			candidates = append(candidates, detail)
@@ -699,7 +765,72 @@ func (bs *balanceSolver) filterDstStores() map[uint64]*statistics.StoreLoadDetai
 	default:
 		return nil
 	}
-	return bs.pickDstStores(filters, candidates)
+	ret := bs.pickDstStores(filters, candidates)
+	if bs.opTy == movePeer {
+		ret = bs.preferRuleSatisfiedStores(ret)
+	}
+	return ret
+}
+
+// preferRuleSatisfiedStores returns the stores which make the region satisfy
+// its placement rules after moving the peer to them, including the isolation
+// levels of the rules. The placement safeguard only ensures the placement does
+// not become worse, so a region violating the isolation level may be kept
+// violating it. All the stores are returned if none of them satisfies the rules.
+func (bs *balanceSolver) preferRuleSatisfiedStores(stores map[uint64]*statistics.StoreLoadDetail) map[uint64]*statistics.StoreLoadDetail {
+	if !bs.GetOpts().IsPlacementRulesEnabled() || len(stores) == 0 {
+		return stores
+	}
+	srcStoreID := bs.cur.srcStore.GetID()
+	satisfied := make(map[uint64]*statistics.StoreLoadDetail, len(stores))
+	for id, detail := range stores {
+		region := bs.cur.region.Clone(core.WithReplacePeerStore(srcStoreID, id))
+		fit := bs.GetRuleManager().FitRegion(bs.GetBasicCluster(), region)
+		if fit.IsSatisfied() && bs.isIsolationSatisfied(fit) {
+			satisfied[id] = detail
+		}
+	}
+	if len(satisfied) == 0 {
+		return stores
+	}
+	if len(satisfied) < len(stores) {
+		schedulerCounter.WithLabelValues(bs.sche.GetName(), "rule-unsatisfied-dst").Add(float64(len(stores) - len(satisfied)))
+	}
+	return satisfied
+}
+
+// isIsolationSatisfied checks whether the peers of each rule are placed in
+// different locations at the isolation level of the rule.
+func (bs *balanceSolver) isIsolationSatisfied(fit *placement.RegionFit) bool {
+	for _, rf := range fit.RuleFits {
+		level := -1
+		for i, label := range rf.Rule.LocationLabels {
+			if label == rf.Rule.IsolationLevel {
+				level = i
+				break
+			}
+		}
+		if rf.Rule.IsolationLevel == "" || level < 0 {
+			continue
+		}
+		locations := make(map[string]struct{}, len(rf.Peers))
+		for _, peer := range rf.Peers {
+			store := bs.GetStore(peer.GetStoreId())
+			if store == nil {
+				return false
+			}
+			values := make([]string, 0, level+1)
+			for _, label := range rf.Rule.LocationLabels[:level+1] {
+				values = append(values, store.GetLabelValue(label))
+			}
+			location := strings.Join(values, "/")
+			if _, ok := locations[location]; ok {
+				return false
+			}
+			locations[location] = struct{}{}
+		}
+	}
+	return true
 }
 
 func (bs *balanceSolver) pickDstStores(filters []filter.Filter, candidates []*statistics.StoreLoadDetail) map[uint64]*statistics.StoreLoadDetail {
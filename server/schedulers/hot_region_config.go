@@ -25,6 +25,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/slice"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/storage/endpoint"
@@ -103,6 +104,7 @@ func (conf *hotRegionSchedulerConfig) getValidConf() *hotRegionSchedulerConfig {
 		WritePeerPriorities:    adjustConfig(conf.lastQuerySupported, conf.WritePeerPriorities, getWritePeerPriorities),
 		StrictPickingStore:     conf.StrictPickingStore,
 		EnableForTiFlash:       conf.EnableForTiFlash,
+		ScatterCoolOffDuration: conf.ScatterCoolOffDuration,
 	}
 }
 
@@ -138,6 +140,10 @@ type hotRegionSchedulerConfig struct {
 	EnableForTiFlash bool `json:"enable-for-tiflash,string"`
 	// forbid read or write scheduler, only for test
 	ForbidRWType string `json:"forbid-rw-type,omitempty"`
+	// ScatterCoolOffDuration is the period after a region is scheduled by the
+	// hot scheduler during which it will not be scheduled again, to avoid
+	// moving a hot region back and forth between stores. 0 disables it.
+	ScatterCoolOffDuration typeutil.Duration `json:"scatter-cool-off-duration"`
 }
 
 func (conf *hotRegionSchedulerConfig) EncodeConfig() ([]byte, error) {
@@ -272,6 +278,18 @@ func (conf *hotRegionSchedulerConfig) GetWritePeerPriorities() []string {
 	return conf.WritePeerPriorities
 }
 
+func (conf *hotRegionSchedulerConfig) GetScatterCoolOffDuration() time.Duration {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.ScatterCoolOffDuration.Duration
+}
+
+func (conf *hotRegionSchedulerConfig) SetScatterCoolOffDuration(d time.Duration) {
+	conf.Lock()
+	defer conf.Unlock()
+	conf.ScatterCoolOffDuration = typeutil.NewDuration(d)
+}
+
 func (conf *hotRegionSchedulerConfig) IsStrictPickingStoreEnabled() bool {
 	conf.RLock()
 	defer conf.RUnlock()
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule"
@@ -338,6 +339,70 @@ func (s *testHotWriteRegionSchedulerSuite) checkByteRateOnly(c *C, tc *mockclust
 	clearPendingInfluence(hb.(*hotScheduler))
 }
 
+func (s *testHotWriteRegionSchedulerSuite) TestIsolationLevel(c *C) {
+	originValue := schedulePeerPr
+	defer func() {
+		schedulePeerPr = originValue
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statistics.Denoising = false
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(ctx, opt)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetHotRegionCacheHitsThreshold(0)
+	hb, err := schedule.CreateScheduler(statistics.Write.String(), schedule.NewOperatorController(ctx, nil, nil), storage.NewStorageWithMemoryBackend(), nil)
+	c.Assert(err, IsNil)
+	hb.(*hotScheduler).conf.WritePeerPriorities = []string{BytePriority, KeyPriority}
+	schedulePeerPr = 1.0
+
+	tc.AddLabelsStore(1, 3, map[string]string{"zone": "z1", "host": "h1"})
+	tc.AddLabelsStore(2, 3, map[string]string{"zone": "z1", "host": "h2"})
+	tc.AddLabelsStore(3, 3, map[string]string{"zone": "z2", "host": "h3"})
+	tc.AddLabelsStore(4, 0, map[string]string{"zone": "z2", "host": "h4"})
+	tc.AddLabelsStore(5, 0, map[string]string{"zone": "z3", "host": "h5"})
+	tc.UpdateStorageWrittenBytes(1, 8*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(2, 5*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(3, 5*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(4, 1*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(5, 3*MB*statistics.StoreHeartBeatReportInterval)
+
+	// The regions have two peers in z1, which violates the isolation level.
+	addRegionInfo(tc, statistics.Write, []testRegionInfo{
+		{1, []uint64{3, 1, 2}, 512 * KB, 0, 0},
+		{2, []uint64{3, 1, 2}, 512 * KB, 0, 0},
+		{3, []uint64{3, 1, 2}, 512 * KB, 0, 0},
+	})
+	setRule := func(isolationLevel string) {
+		c.Assert(tc.RuleManager.SetRule(&placement.Rule{
+			GroupID:        "pd",
+			ID:             "default",
+			Role:           placement.Voter,
+			Count:          3,
+			LocationLabels: []string{"zone", "host"},
+			IsolationLevel: isolationLevel,
+		}), IsNil)
+	}
+	check := func(targetID uint64) {
+		for i := 0; i < 20; i++ {
+			clearPendingInfluence(hb.(*hotScheduler))
+			ops := hb.Schedule(tc)
+			c.Assert(ops, HasLen, 1)
+			testutil.CheckTransferPeer(c, ops[0], operator.OpHotRegion, 1, targetID)
+		}
+	}
+
+	// The least loaded store is picked without the isolation level.
+	setRule("")
+	check(4)
+	// The peer is moved to z3 to satisfy the isolation level.
+	setRule("zone")
+	check(5)
+	// The least loaded store is picked if no store satisfies the isolation level.
+	tc.SetStoreDown(5)
+	check(4)
+}
+
 func (s *testHotWriteRegionSchedulerSuite) TestByteRateOnlyWithTiFlash(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -581,6 +646,108 @@ func (s *testHotWriteRegionSchedulerSuite) TestWithQuery(c *C) {
 	}
 }
 
+func (s *testHotWriteRegionSchedulerSuite) TestExpiredHotPeers(c *C) {
+	originValue := schedulePeerPr
+	defer func() {
+		schedulePeerPr = originValue
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statistics.Denoising = false
+	opt := config.NewTestOptions()
+	cfg := opt.GetScheduleConfig().Clone()
+	cfg.HotCacheEntryTTL = typeutil.NewDuration(10 * time.Minute)
+	opt.SetScheduleConfig(cfg)
+	hb, err := schedule.CreateScheduler(statistics.Write.String(), schedule.NewOperatorController(ctx, nil, nil), storage.NewStorageWithMemoryBackend(), nil)
+	c.Assert(err, IsNil)
+	hb.(*hotScheduler).conf.SetSrcToleranceRatio(1)
+	hb.(*hotScheduler).conf.SetDstToleranceRatio(1)
+	hb.(*hotScheduler).conf.WriteLeaderPriorities = []string{QueryPriority, BytePriority}
+
+	tc := mockcluster.NewCluster(ctx, opt)
+	tc.SetHotRegionCacheHitsThreshold(0)
+	tc.AddRegionStore(1, 20)
+	tc.AddRegionStore(2, 20)
+	tc.AddRegionStore(3, 20)
+
+	tc.UpdateStorageWriteQuery(1, 11000*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWriteQuery(2, 10000*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWriteQuery(3, 9000*statistics.StoreHeartBeatReportInterval)
+
+	addRegionInfo(tc, statistics.Write, []testRegionInfo{
+		{1, []uint64{1, 2, 3}, 500, 0, 500},
+		{2, []uint64{1, 2, 3}, 500, 0, 500},
+		{3, []uint64{2, 1, 3}, 500, 0, 500},
+	})
+	schedulePeerPr = 0.0
+	ops := hb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpHotRegion, 1, 3)
+
+	// The hot peers not updated for an hour are removed from the cache after
+	// their loads decay, and are not scheduled any more.
+	for _, peers := range tc.RegionWriteStats() {
+		for _, peer := range peers {
+			peer.LastUpdateTime = time.Now().Add(-time.Hour)
+		}
+	}
+	clearPendingInfluence(hb.(*hotScheduler))
+	c.Assert(hb.Schedule(tc), HasLen, 0)
+	for _, peers := range tc.RegionWriteStats() {
+		c.Assert(peers, HasLen, 0)
+	}
+}
+
+func (s *testHotWriteRegionSchedulerSuite) TestScatterCoolOff(c *C) {
+	originValue := schedulePeerPr
+	defer func() {
+		schedulePeerPr = originValue
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statistics.Denoising = false
+	opt := config.NewTestOptions()
+	hb, err := schedule.CreateScheduler(statistics.Write.String(), schedule.NewOperatorController(ctx, nil, nil), storage.NewStorageWithMemoryBackend(), nil)
+	c.Assert(err, IsNil)
+	hb.(*hotScheduler).conf.SetSrcToleranceRatio(1)
+	hb.(*hotScheduler).conf.SetDstToleranceRatio(1)
+	hb.(*hotScheduler).conf.WriteLeaderPriorities = []string{QueryPriority, BytePriority}
+
+	tc := mockcluster.NewCluster(ctx, opt)
+	tc.SetHotRegionCacheHitsThreshold(0)
+	tc.AddRegionStore(1, 20)
+	tc.AddRegionStore(2, 20)
+	tc.AddRegionStore(3, 20)
+
+	tc.UpdateStorageWriteQuery(1, 11000*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWriteQuery(2, 10000*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWriteQuery(3, 9000*statistics.StoreHeartBeatReportInterval)
+
+	addRegionInfo(tc, statistics.Write, []testRegionInfo{
+		{1, []uint64{1, 2, 3}, 500, 0, 500},
+	})
+	schedulePeerPr = 0.0
+
+	// The region can be scheduled again without the cool-off.
+	for i := 0; i < 2; i++ {
+		clearPendingInfluence(hb.(*hotScheduler))
+		ops := hb.Schedule(tc)
+		c.Assert(ops, HasLen, 1)
+		testutil.CheckTransferLeader(c, ops[0], operator.OpHotRegion, 1, 3)
+	}
+
+	// The region is skipped within the cool-off.
+	hb.(*hotScheduler).conf.SetScatterCoolOffDuration(time.Minute)
+	clearPendingInfluence(hb.(*hotScheduler))
+	c.Assert(hb.Schedule(tc), HasLen, 0)
+
+	// The region can be scheduled after the cool-off.
+	hb.(*hotScheduler).lastScatters.Put(1, time.Now().Add(-2*time.Minute))
+	ops := hb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpHotRegion, 1, 3)
+}
+
 func (s *testHotWriteRegionSchedulerSuite) TestWithKeyRate(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1076,6 +1243,53 @@ func (s *testHotReadRegionSchedulerSuite) TestWithQuery(c *C) {
 	}
 }
 
+func (s *testHotReadRegionSchedulerSuite) TestPinReadHotRegions(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statistics.Denoising = false
+	opt := config.NewTestOptions()
+	hb, err := schedule.CreateScheduler(statistics.Read.String(), schedule.NewOperatorController(ctx, nil, nil), storage.NewStorageWithMemoryBackend(), nil)
+	c.Assert(err, IsNil)
+	hb.(*hotScheduler).conf.SetSrcToleranceRatio(1)
+	hb.(*hotScheduler).conf.SetDstToleranceRatio(1)
+
+	tc := mockcluster.NewCluster(ctx, opt)
+	tc.SetHotRegionCacheHitsThreshold(0)
+	tc.AddRegionStore(1, 20)
+	tc.AddRegionStore(2, 20)
+	tc.AddRegionStore(3, 20)
+
+	tc.UpdateStorageReadQuery(1, 10500*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageReadQuery(2, 10000*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageReadQuery(3, 9000*statistics.StoreHeartBeatReportInterval)
+
+	addRegionInfo(tc, statistics.Read, []testRegionInfo{
+		{1, []uint64{1, 2, 3}, 0, 0, 500},
+		{2, []uint64{2, 1, 3}, 0, 0, 500},
+	})
+
+	// The leader is not hot enough to be pinned.
+	tc.SetPinReadHotRegions(true, 1000)
+	clearPendingInfluence(hb.(*hotScheduler))
+	ops := hb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpHotRegion, 1, 3)
+
+	// The leader is pinned.
+	tc.SetPinReadHotRegions(true, 400)
+	for i := 0; i < 20; i++ {
+		clearPendingInfluence(hb.(*hotScheduler))
+		c.Assert(hb.Schedule(tc), HasLen, 0)
+	}
+
+	// The leader is not pinned if disabled.
+	tc.SetPinReadHotRegions(false, 400)
+	clearPendingInfluence(hb.(*hotScheduler))
+	ops = hb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	testutil.CheckTransferLeader(c, ops[0], operator.OpHotRegion, 1, 3)
+}
+
 func (s *testHotReadRegionSchedulerSuite) TestWithKeyRate(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,268 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+const (
+	// LabelBalanceLeaderName is label balance leader scheduler name.
+	LabelBalanceLeaderName = "label-balance-leader-scheduler"
+	// LabelBalanceLeaderType is label balance leader scheduler type.
+	LabelBalanceLeaderType = "label-balance-leader"
+	// DefaultLabelBalanceTolerance is the default tolerance of the leader
+	// count difference between label values, relative to the average.
+	DefaultLabelBalanceTolerance = 0.05
+)
+
+func init() {
+	schedule.RegisterSliceDecoderBuilder(LabelBalanceLeaderType, func(args []string) schedule.ConfigDecoder {
+		return func(v interface{}) error {
+			conf, ok := v.(*labelBalanceLeaderSchedulerConfig)
+			if !ok {
+				return errs.ErrScheduleConfigNotExist.FastGenByArgs()
+			}
+			if len(args) < 1 || len(args[0]) == 0 {
+				return errs.ErrSchedulerConfig.FastGenByArgs("balance label key")
+			}
+			conf.BalanceLabelKey = args[0]
+			conf.Tolerance = DefaultLabelBalanceTolerance
+			if len(args) > 1 {
+				tolerance, err := strconv.ParseFloat(args[1], 64)
+				if err != nil {
+					return errs.ErrStrconvParseFloat.Wrap(err).FastGenWithCause()
+				}
+				if tolerance < 0 {
+					return errs.ErrSchedulerConfig.FastGenByArgs("tolerance")
+				}
+				conf.Tolerance = tolerance
+			}
+			conf.Ranges = []core.KeyRange{core.NewKeyRange("", "")}
+			conf.Name = LabelBalanceLeaderName
+			return nil
+		}
+	})
+
+	schedule.RegisterScheduler(LabelBalanceLeaderType, func(opController *schedule.OperatorController, storage endpoint.ConfigStorage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
+		conf := &labelBalanceLeaderSchedulerConfig{Tolerance: DefaultLabelBalanceTolerance}
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		if len(conf.BalanceLabelKey) == 0 {
+			return nil, errs.ErrSchedulerConfig.FastGenByArgs("balance label key")
+		}
+		return newLabelBalanceLeaderScheduler(opController, conf), nil
+	})
+}
+
+type labelBalanceLeaderSchedulerConfig struct {
+	Name   string          `json:"name"`
+	Ranges []core.KeyRange `json:"ranges"`
+	// BalanceLabelKey is the key of the store label whose values the leaders
+	// are balanced across, e.g. `zone`.
+	BalanceLabelKey string `json:"balance-label-key"`
+	// Tolerance is the tolerable leader count difference between the most
+	// and the least loaded label values, relative to the average.
+	Tolerance float64 `json:"tolerance"`
+}
+
+type labelBalanceLeaderScheduler struct {
+	*BaseScheduler
+	conf    *labelBalanceLeaderSchedulerConfig
+	filters []filter.Filter
+}
+
+// labelLeaders records the stores and their leader count of a label value.
+type labelLeaders struct {
+	value       string
+	stores      []*core.StoreInfo
+	leaderCount int64
+}
+
+// newLabelBalanceLeaderScheduler creates a scheduler that tends to keep leaders
+// balanced across the values of a store label, so that the leaders will not
+// concentrate on a single zone or rack.
+func newLabelBalanceLeaderScheduler(opController *schedule.OperatorController, conf *labelBalanceLeaderSchedulerConfig) schedule.Scheduler {
+	s := &labelBalanceLeaderScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+	}
+	s.filters = []filter.Filter{
+		&filter.StoreStateFilter{ActionScope: s.GetName(), TransferLeader: true},
+		filter.NewSpecialUseFilter(s.GetName()),
+	}
+	return s
+}
+
+func (s *labelBalanceLeaderScheduler) GetName() string {
+	return s.conf.Name
+}
+
+func (s *labelBalanceLeaderScheduler) GetType() string {
+	return LabelBalanceLeaderType
+}
+
+func (s *labelBalanceLeaderScheduler) EncodeConfig() ([]byte, error) {
+	return schedule.EncodeConfig(s.conf)
+}
+
+func (s *labelBalanceLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	return allowed
+}
+
+func (s *labelBalanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	opInfluence := s.OpController.GetOpInfluence(cluster)
+	leaderCount := func(store *core.StoreInfo) int64 {
+		return int64(store.GetLeaderCount()) + opInfluence.GetStoreInfluence(store.GetID()).LeaderCount
+	}
+
+	groups := s.groupStores(cluster, leaderCount)
+	if len(groups) < 2 {
+		schedulerCounter.WithLabelValues(s.GetName(), "no-label").Inc()
+		return nil
+	}
+	source, target := groups[0], groups[len(groups)-1]
+	if !s.shouldBalance(groups) {
+		schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
+		return nil
+	}
+	log.Debug("label balance leader scheduler selects label values",
+		zap.String("scheduler", s.GetName()),
+		zap.String("source", source.value), zap.Int64("source-leaders", source.leaderCount),
+		zap.String("target", target.value), zap.Int64("target-leaders", target.leaderCount))
+
+	targetStores := make(map[uint64]struct{}, len(target.stores))
+	for _, store := range target.stores {
+		targetStores[store.GetID()] = struct{}{}
+	}
+	// Prefer to move leaders out of the store with the most leaders.
+	sort.Slice(source.stores, func(i, j int) bool {
+		return leaderCount(source.stores[i]) > leaderCount(source.stores[j])
+	})
+	for _, store := range source.stores {
+		for i := 0; i < balanceLeaderRetryLimit; i++ {
+			schedulerCounter.WithLabelValues(s.GetName(), "total").Inc()
+			region := cluster.RandLeaderRegion(store.GetID(), s.conf.Ranges, schedule.IsRegionHealthy)
+			if region == nil {
+				schedulerCounter.WithLabelValues(s.GetName(), "no-leader-region").Inc()
+				break
+			}
+			if cluster.IsRegionHot(region) {
+				schedulerCounter.WithLabelValues(s.GetName(), "region-hot").Inc()
+				continue
+			}
+			if op := s.transferLeader(cluster, region, store, targetStores, leaderCount); op != nil {
+				return []*operator.Operator{op}
+			}
+		}
+	}
+	schedulerCounter.WithLabelValues(s.GetName(), "no-region").Inc()
+	return nil
+}
+
+// groupStores groups the stores which can transfer leaders by the value of the
+// balance label, and sorts the groups by their leader count in descending order.
+// The stores without the balance label are ignored.
+func (s *labelBalanceLeaderScheduler) groupStores(cluster schedule.Cluster, leaderCount func(*core.StoreInfo) int64) []*labelLeaders {
+	groups := make(map[string]*labelLeaders)
+	for _, store := range filter.SelectSourceStores(cluster.GetStores(), s.filters, cluster.GetOpts()) {
+		value := store.GetLabelValue(s.conf.BalanceLabelKey)
+		if len(value) == 0 {
+			continue
+		}
+		group, ok := groups[value]
+		if !ok {
+			group = &labelLeaders{value: value}
+			groups[value] = group
+		}
+		group.stores = append(group.stores, store)
+		group.leaderCount += leaderCount(store)
+	}
+	result := make([]*labelLeaders, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].leaderCount == result[j].leaderCount {
+			return result[i].value < result[j].value
+		}
+		return result[i].leaderCount > result[j].leaderCount
+	})
+	return result
+}
+
+// shouldBalance returns true if the leader count difference between the most
+// and the least loaded label values exceeds the tolerance. Since transferring
+// one leader narrows the difference by two, a difference of one is always
+// tolerated.
+func (s *labelBalanceLeaderScheduler) shouldBalance(groups []*labelLeaders) bool {
+	var total int64
+	for _, group := range groups {
+		total += group.leaderCount
+	}
+	avg := float64(total) / float64(len(groups))
+	tolerance := math.Max(1, s.conf.Tolerance*avg)
+	return float64(groups[0].leaderCount-groups[len(groups)-1].leaderCount) > tolerance
+}
+
+// transferLeader creates an operator which transfers the leader of the region
+// to the follower with the least leaders among the target stores.
+func (s *labelBalanceLeaderScheduler) transferLeader(cluster schedule.Cluster, region *core.RegionInfo, source *core.StoreInfo,
+	targetStores map[uint64]struct{}, leaderCount func(*core.StoreInfo) int64) *operator.Operator {
+	var candidates []*core.StoreInfo
+	for _, store := range cluster.GetFollowerStores(region) {
+		if _, ok := targetStores[store.GetID()]; ok {
+			candidates = append(candidates, store)
+		}
+	}
+	finalFilters := s.filters
+	opts := cluster.GetOpts()
+	if leaderFilter := filter.NewPlacementLeaderSafeguard(s.GetName(), opts, cluster.GetBasicCluster(), cluster.GetRuleManager(), region, source); leaderFilter != nil {
+		finalFilters = append(s.filters, leaderFilter)
+	}
+	candidates = filter.SelectTargetStores(candidates, finalFilters, opts)
+	if len(candidates) == 0 {
+		log.Debug("region has no target store", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
+		schedulerCounter.WithLabelValues(s.GetName(), "no-target-store").Inc()
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return leaderCount(candidates[i]) < leaderCount(candidates[j])
+	})
+	op, err := operator.CreateTransferLeaderOperator(LabelBalanceLeaderType, cluster, region, source.GetID(), candidates[0].GetID(), []uint64{}, operator.OpLeader)
+	if err != nil {
+		log.Debug("fail to create label balance leader operator", errs.ZapError(err))
+		return nil
+	}
+	op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
+	return op
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"net/http"
+
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/unrolled/render"
+)
+
+// PluginType is the type of the schedulers created from scheduler plugins.
+const PluginType = "plugin"
+
+type pluginScheduler struct {
+	*BaseScheduler
+	plugin schedule.SchedulerPlugin
+	rd     *render.Render
+}
+
+// NewPluginScheduler creates a scheduler which runs the scheduler plugin and
+// makes it obey the operator limits of the cluster.
+func NewPluginScheduler(opController *schedule.OperatorController, plugin schedule.SchedulerPlugin) schedule.Scheduler {
+	return &pluginScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		plugin:        plugin,
+		rd:            render.New(render.Options{IndentJSON: true}),
+	}
+}
+
+func (s *pluginScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.rd.JSON(w, http.StatusOK, s.plugin.Config())
+}
+
+func (s *pluginScheduler) GetName() string {
+	return s.plugin.Name()
+}
+
+func (s *pluginScheduler) GetType() string {
+	return PluginType
+}
+
+func (s *pluginScheduler) EncodeConfig() ([]byte, error) {
+	return schedule.EncodeConfig(s.plugin.Config())
+}
+
+func (s *pluginScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	leaderAllowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
+	regionAllowed := s.OpController.OperatorCount(operator.OpRegion) < cluster.GetOpts().GetRegionScheduleLimit()
+	if !leaderAllowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	if !regionAllowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpRegion.String()).Inc()
+	}
+	return leaderAllowed || regionAllowed
+}
+
+func (s *pluginScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	ops := s.plugin.Schedule(cluster)
+	// The plugin is not aware of the operator limits, so drop the operators
+	// beyond the limits.
+	leaderQuota := int64(cluster.GetOpts().GetLeaderScheduleLimit()) - int64(s.OpController.OperatorCount(operator.OpLeader))
+	regionQuota := int64(cluster.GetOpts().GetRegionScheduleLimit()) - int64(s.OpController.OperatorCount(operator.OpRegion))
+	result := make([]*operator.Operator, 0, len(ops))
+	for _, op := range ops {
+		if op == nil {
+			continue
+		}
+		switch {
+		case op.Kind()&operator.OpRegion != 0:
+			if regionQuota <= 0 {
+				operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpRegion.String()).Inc()
+				continue
+			}
+			regionQuota--
+		case op.Kind()&operator.OpLeader != 0:
+			if leaderQuota <= 0 {
+				operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+				continue
+			}
+			leaderQuota--
+		}
+		op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
+		result = append(result, op)
+	}
+	return result
+}
@@ -15,6 +15,7 @@
 package schedulers
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"sync"
@@ -58,6 +59,9 @@ func init() {
 		if err := decoder(conf); err != nil {
 			return nil, err
 		}
+		if err := conf.adjust(); err != nil {
+			return nil, err
+		}
 		rangeName := conf.RangeName
 		if len(rangeName) == 0 {
 			return nil, errs.ErrSchedulerConfig.FastGenByArgs("range name")
@@ -77,8 +81,30 @@ type scatterRangeSchedulerConfig struct {
 	mu        sync.RWMutex
 	storage   endpoint.ConfigStorage
 	RangeName string `json:"range-name"`
-	StartKey  string `json:"start-key"`
-	EndKey    string `json:"end-key"`
+	// StartKey and EndKey are the first range, they are kept for compatibility.
+	StartKey string `json:"start-key"`
+	EndKey   string `json:"end-key"`
+	// Ranges are all the key ranges to scatter, which are scheduled in
+	// round-robin order.
+	Ranges []core.KeyRange `json:"ranges"`
+}
+
+// adjust makes the config which is created by args or persisted by the old
+// version contain the first range. The first range must not overlap with the
+// other ranges, like the ones added by AddRange.
+func (conf *scatterRangeSchedulerConfig) adjust() error {
+	first := core.NewKeyRange(conf.StartKey, conf.EndKey)
+	if len(conf.Ranges) == 0 {
+		conf.Ranges = []core.KeyRange{first}
+		return nil
+	}
+	for _, r := range conf.Ranges[1:] {
+		if isRangeOverlapped(r.StartKey, r.EndKey, first.StartKey, first.EndKey) {
+			return errs.ErrSchedulerConfig.FastGenByArgs("overlapped range")
+		}
+	}
+	conf.Ranges[0] = first
+	return nil
 }
 
 func (conf *scatterRangeSchedulerConfig) BuildWithArgs(args []string) error {
@@ -88,19 +114,100 @@ func (conf *scatterRangeSchedulerConfig) BuildWithArgs(args []string) error {
 	conf.mu.Lock()
 	defer conf.mu.Unlock()
 
+	oldName, oldStartKey, oldEndKey := conf.RangeName, conf.StartKey, conf.EndKey
 	conf.RangeName = args[0]
 	conf.StartKey = args[1]
 	conf.EndKey = args[2]
+	if err := conf.adjust(); err != nil {
+		conf.RangeName, conf.StartKey, conf.EndKey = oldName, oldStartKey, oldEndKey
+		return err
+	}
+	return nil
+}
+
+// AddRange adds a key range to scatter, the range must not overlap with the
+// existing ranges.
+func (conf *scatterRangeSchedulerConfig) AddRange(startKey, endKey []byte) error {
+	if len(endKey) > 0 && bytes.Compare(startKey, endKey) >= 0 {
+		return errs.ErrSchedulerConfig.FastGenByArgs("range")
+	}
+	conf.mu.Lock()
+	for _, r := range conf.Ranges {
+		if isRangeOverlapped(r.StartKey, r.EndKey, startKey, endKey) {
+			conf.mu.Unlock()
+			return errs.ErrSchedulerConfig.FastGenByArgs("overlapped range")
+		}
+	}
+	conf.Ranges = append(conf.Ranges, core.KeyRange{StartKey: startKey, EndKey: endKey})
+	conf.mu.Unlock()
+
+	if err := conf.Persist(); err != nil {
+		conf.mu.Lock()
+		conf.Ranges = conf.Ranges[:len(conf.Ranges)-1]
+		conf.mu.Unlock()
+		return err
+	}
 	return nil
 }
 
+// RemoveRange removes the key range which starts with the start key. The last
+// range cannot be removed, please delete the scheduler instead.
+func (conf *scatterRangeSchedulerConfig) RemoveRange(startKey []byte) error {
+	conf.mu.Lock()
+	old := conf.Ranges
+	idx := -1
+	for i, r := range old {
+		if bytes.Equal(r.StartKey, startKey) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		conf.mu.Unlock()
+		return errs.ErrSchedulerConfig.FastGenByArgs("range not found")
+	}
+	if len(old) == 1 {
+		conf.mu.Unlock()
+		return errs.ErrSchedulerConfig.FastGenByArgs("cannot remove the last range")
+	}
+	ranges := make([]core.KeyRange, 0, len(old)-1)
+	ranges = append(ranges, old[:idx]...)
+	ranges = append(ranges, old[idx+1:]...)
+	conf.setRangesLocked(ranges)
+	conf.mu.Unlock()
+
+	if err := conf.Persist(); err != nil {
+		conf.mu.Lock()
+		conf.setRangesLocked(old)
+		conf.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (conf *scatterRangeSchedulerConfig) setRangesLocked(ranges []core.KeyRange) {
+	conf.Ranges = ranges
+	conf.StartKey = string(ranges[0].StartKey)
+	conf.EndKey = string(ranges[0].EndKey)
+}
+
+// isRangeOverlapped checks if [start1, end1) and [start2, end2) overlap, an
+// empty end key means the end of all keys.
+func isRangeOverlapped(start1, end1, start2, end2 []byte) bool {
+	return (len(end2) == 0 || bytes.Compare(start1, end2) < 0) &&
+		(len(end1) == 0 || bytes.Compare(start2, end1) < 0)
+}
+
 func (conf *scatterRangeSchedulerConfig) Clone() *scatterRangeSchedulerConfig {
 	conf.mu.RLock()
 	defer conf.mu.RUnlock()
+	ranges := make([]core.KeyRange, len(conf.Ranges))
+	copy(ranges, conf.Ranges)
 	return &scatterRangeSchedulerConfig{
 		StartKey:  conf.StartKey,
 		EndKey:    conf.EndKey,
 		RangeName: conf.RangeName,
+		Ranges:    ranges,
 	}
 }
 
@@ -133,46 +240,120 @@ func (conf *scatterRangeSchedulerConfig) GetEndKey() []byte {
 	return []byte(conf.EndKey)
 }
 
+func (conf *scatterRangeSchedulerConfig) GetRanges() []core.KeyRange {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	ranges := make([]core.KeyRange, len(conf.Ranges))
+	copy(ranges, conf.Ranges)
+	return ranges
+}
+
 func (conf *scatterRangeSchedulerConfig) getSchedulerName() string {
 	conf.mu.RLock()
 	defer conf.mu.RUnlock()
 	return fmt.Sprintf("scatter-range-%s", conf.RangeName)
 }
 
-type scatterRangeScheduler struct {
-	*BaseScheduler
-	name          string
-	config        *scatterRangeSchedulerConfig
+// scatterRangeProgress records the scatter progress of a key range.
+type scatterRangeProgress struct {
+	StartKey []byte `json:"start-key"`
+	EndKey   []byte `json:"end-key"`
+	// LeaderOperators and RegionOperators are the number of the operators
+	// created for the range.
+	LeaderOperators uint64 `json:"leader-operators"`
+	RegionOperators uint64 `json:"region-operators"`
+	// Balanced is true if no operator was created for the range last time.
+	Balanced bool `json:"balanced"`
+}
+
+// scatterRangeState is the scheduling state of a key range. Each range has its
+// own balance schedulers, so that the ranges are scattered independently.
+type scatterRangeState struct {
+	progress      scatterRangeProgress
 	balanceLeader schedule.Scheduler
 	balanceRegion schedule.Scheduler
-	handler       http.Handler
 }
 
-// newScatterRangeScheduler creates a scheduler that balances the distribution of leaders and regions that in the specified key range.
+type scatterRangeScheduler struct {
+	*BaseScheduler
+	name    string
+	config  *scatterRangeSchedulerConfig
+	handler http.Handler
+
+	mu struct {
+		sync.RWMutex
+		// states are keyed by the start key of the range.
+		states map[string]*scatterRangeState
+		// next is the index of the range to schedule next time.
+		next int
+	}
+}
+
+// newScatterRangeScheduler creates a scheduler that balances the distribution of leaders and regions that in the specified key ranges.
 func newScatterRangeScheduler(opController *schedule.OperatorController, config *scatterRangeSchedulerConfig) schedule.Scheduler {
 	base := NewBaseScheduler(opController)
 
 	name := config.getSchedulerName()
-	handler := newScatterRangeHandler(config)
 	scheduler := &scatterRangeScheduler{
 		BaseScheduler: base,
 		config:        config,
-		handler:       handler,
 		name:          name,
+	}
+	scheduler.mu.states = make(map[string]*scatterRangeState)
+	scheduler.handler = newScatterRangeHandler(config, scheduler.getProgress)
+	return scheduler
+}
+
+func (l *scatterRangeScheduler) newRangeState(r core.KeyRange) *scatterRangeState {
+	return &scatterRangeState{
+		progress: scatterRangeProgress{StartKey: r.StartKey, EndKey: r.EndKey},
 		balanceLeader: newBalanceLeaderScheduler(
-			opController,
+			l.OpController,
 			&balanceLeaderSchedulerConfig{Ranges: []core.KeyRange{core.NewKeyRange("", "")}},
 			WithBalanceLeaderName("scatter-range-leader"),
 			WithBalanceLeaderCounter(scatterRangeLeaderCounter),
 		),
 		balanceRegion: newBalanceRegionScheduler(
-			opController,
+			l.OpController,
 			&balanceRegionSchedulerConfig{Ranges: []core.KeyRange{core.NewKeyRange("", "")}},
 			WithBalanceRegionName("scatter-range-region"),
 			WithBalanceRegionCounter(scatterRangeRegionCounter),
 		),
 	}
-	return scheduler
+}
+
+// getRangeStates returns the states of the ranges in order, the states of the
+// removed ranges are dropped.
+func (l *scatterRangeScheduler) getRangeStates(ranges []core.KeyRange) []*scatterRangeState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	states := make(map[string]*scatterRangeState, len(ranges))
+	result := make([]*scatterRangeState, 0, len(ranges))
+	for _, r := range ranges {
+		state, ok := l.mu.states[string(r.StartKey)]
+		if !ok || !bytes.Equal(state.progress.EndKey, r.EndKey) {
+			state = l.newRangeState(r)
+		}
+		states[string(r.StartKey)] = state
+		result = append(result, state)
+	}
+	l.mu.states = states
+	return result
+}
+
+func (l *scatterRangeScheduler) getProgress() []scatterRangeProgress {
+	ranges := l.config.GetRanges()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	result := make([]scatterRangeProgress, 0, len(ranges))
+	for _, r := range ranges {
+		if state, ok := l.mu.states[string(r.StartKey)]; ok && bytes.Equal(state.progress.EndKey, r.EndKey) {
+			result = append(result, state.progress)
+			continue
+		}
+		result = append(result, scatterRangeProgress{StartKey: r.StartKey, EndKey: r.EndKey})
+	}
+	return result
 }
 
 func (l *scatterRangeScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -215,41 +396,71 @@ func (l *scatterRangeScheduler) allowBalanceRegion(cluster schedule.Cluster) boo
 
 func (l *scatterRangeScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
 	schedulerCounter.WithLabelValues(l.GetName(), "schedule").Inc()
+	states := l.getRangeStates(l.config.GetRanges())
+	l.mu.RLock()
+	next := l.mu.next
+	l.mu.RUnlock()
+	// Schedule the ranges in round-robin order, so that a range with a lot of
+	// regions will not starve the others.
+	for i := range states {
+		idx := (next + i) % len(states)
+		if ops := l.scheduleRange(cluster, states[idx]); len(ops) > 0 {
+			l.mu.Lock()
+			l.mu.next = idx + 1
+			l.mu.Unlock()
+			return ops
+		}
+	}
+	return nil
+}
+
+func (l *scatterRangeScheduler) scheduleRange(cluster schedule.Cluster, state *scatterRangeState) []*operator.Operator {
 	// isolate a new cluster according to the key range
-	c := schedule.GenRangeCluster(cluster, l.config.GetStartKey(), l.config.GetEndKey())
+	c := schedule.GenRangeCluster(cluster, state.progress.StartKey, state.progress.EndKey)
 	c.SetTolerantSizeRatio(2)
+	rangeName := l.config.GetRangeName()
 	if l.allowBalanceLeader(cluster) {
-		ops := l.balanceLeader.Schedule(c)
+		ops := state.balanceLeader.Schedule(c)
 		if len(ops) > 0 {
-			ops[0].SetDesc(fmt.Sprintf("scatter-range-leader-%s", l.config.RangeName))
+			ops[0].SetDesc(fmt.Sprintf("scatter-range-leader-%s", rangeName))
 			ops[0].AttachKind(operator.OpRange)
 			ops[0].Counters = append(ops[0].Counters,
 				schedulerCounter.WithLabelValues(l.GetName(), "new-operator"),
 				schedulerCounter.WithLabelValues(l.GetName(), "new-leader-operator"))
+			l.updateProgress(state, func(p *scatterRangeProgress) { p.LeaderOperators++ })
 			return ops
 		}
 		schedulerCounter.WithLabelValues(l.GetName(), "no-need-balance-leader").Inc()
 	}
 	if l.allowBalanceRegion(cluster) {
-		ops := l.balanceRegion.Schedule(c)
+		ops := state.balanceRegion.Schedule(c)
 		if len(ops) > 0 {
-			ops[0].SetDesc(fmt.Sprintf("scatter-range-region-%s", l.config.RangeName))
+			ops[0].SetDesc(fmt.Sprintf("scatter-range-region-%s", rangeName))
 			ops[0].AttachKind(operator.OpRange)
 			ops[0].Counters = append(ops[0].Counters,
 				schedulerCounter.WithLabelValues(l.GetName(), "new-operator"),
 				schedulerCounter.WithLabelValues(l.GetName(), "new-region-operator"),
 			)
+			l.updateProgress(state, func(p *scatterRangeProgress) { p.RegionOperators++ })
 			return ops
 		}
 		schedulerCounter.WithLabelValues(l.GetName(), "no-need-balance-region").Inc()
 	}
-
+	l.updateProgress(state, func(p *scatterRangeProgress) { p.Balanced = true })
 	return nil
 }
 
+func (l *scatterRangeScheduler) updateProgress(state *scatterRangeState, f func(*scatterRangeProgress)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	state.progress.Balanced = false
+	f(&state.progress)
+}
+
 type scatterRangeHandler struct {
-	rd     *render.Render
-	config *scatterRangeSchedulerConfig
+	rd       *render.Render
+	config   *scatterRangeSchedulerConfig
+	progress func() []scatterRangeProgress
 }
 
 func (handler *scatterRangeHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
@@ -295,13 +506,56 @@ func (handler *scatterRangeHandler) ListConfig(w http.ResponseWriter, r *http.Re
 	handler.rd.JSON(w, http.StatusOK, conf)
 }
 
-func newScatterRangeHandler(config *scatterRangeSchedulerConfig) http.Handler {
+func (handler *scatterRangeHandler) AddRange(w http.ResponseWriter, r *http.Request) {
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	startKey, ok := input["start-key"].(string)
+	if !ok {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("start-key").Error())
+		return
+	}
+	endKey, ok := input["end-key"].(string)
+	if !ok {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("end-key").Error())
+		return
+	}
+	if err := handler.config.AddRange([]byte(startKey), []byte(endKey)); err != nil {
+		handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (handler *scatterRangeHandler) RemoveRange(w http.ResponseWriter, r *http.Request) {
+	startKeys, ok := r.URL.Query()["start-key"]
+	if !ok || len(startKeys) != 1 {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("start-key").Error())
+		return
+	}
+	if err := handler.config.RemoveRange([]byte(startKeys[0])); err != nil {
+		handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (handler *scatterRangeHandler) ListProgress(w http.ResponseWriter, r *http.Request) {
+	handler.rd.JSON(w, http.StatusOK, handler.progress())
+}
+
+func newScatterRangeHandler(config *scatterRangeSchedulerConfig, progress func() []scatterRangeProgress) http.Handler {
 	h := &scatterRangeHandler{
-		config: config,
-		rd:     render.New(render.Options{IndentJSON: true}),
+		config:   config,
+		progress: progress,
+		rd:       render.New(render.Options{IndentJSON: true}),
 	}
 	router := mux.NewRouter()
 	router.HandleFunc("/config", h.UpdateConfig).Methods("POST")
 	router.HandleFunc("/list", h.ListConfig).Methods("GET")
+	router.HandleFunc("/ranges", h.AddRange).Methods("POST")
+	router.HandleFunc("/ranges", h.RemoveRange).Methods("DELETE")
+	router.HandleFunc("/progress", h.ListProgress).Methods("GET")
 	return router
 }
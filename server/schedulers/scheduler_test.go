@@ -655,3 +655,66 @@ func (s *testEvictSlowStoreSuite) TestEvictSlowStore(c *C) {
 	op = bs.Schedule(tc)
 	testutil.CheckTransferLeader(c, op[0], operator.OpLeader, 2, 1)
 }
+
+type mockSchedulerPlugin struct {
+	ops []*operator.Operator
+}
+
+func (p *mockSchedulerPlugin) Name() string {
+	return "mock-plugin"
+}
+
+func (p *mockSchedulerPlugin) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	return p.ops
+}
+
+func (p *mockSchedulerPlugin) Config() interface{} {
+	return map[string]int{"ops": len(p.ops)}
+}
+
+var _ = Suite(&testPluginSchedulerSuite{})
+
+type testPluginSchedulerSuite struct{}
+
+func (s *testPluginSchedulerSuite) TestOperatorLimit(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(ctx, opt)
+	tc.SetLeaderScheduleLimit(2)
+	tc.SetRegionScheduleLimit(1)
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderStore(i, 0)
+	}
+	plugin := &mockSchedulerPlugin{}
+	for i := uint64(1); i <= 3; i++ {
+		region := tc.AddLeaderRegion(i, 1, 2, 3)
+		op, err := operator.CreateTransferLeaderOperator("mock", tc, region, 1, 2, []uint64{}, operator.OpLeader)
+		c.Assert(err, IsNil)
+		plugin.ops = append(plugin.ops, op)
+		op, err = operator.CreateMovePeerOperator("mock", tc, region, operator.OpRegion, 3, &metapb.Peer{StoreId: 4})
+		c.Assert(err, IsNil)
+		plugin.ops = append(plugin.ops, op)
+	}
+
+	oc := schedule.NewOperatorController(ctx, tc, nil)
+	ps := NewPluginScheduler(oc, plugin)
+	c.Assert(ps.GetName(), Equals, "mock-plugin")
+	c.Assert(ps.GetType(), Equals, PluginType)
+	data, err := ps.EncodeConfig()
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"ops":6}`)
+
+	// The operators beyond the limits are dropped.
+	c.Assert(ps.IsScheduleAllowed(tc), IsTrue)
+	ops := ps.Schedule(tc)
+	c.Assert(ops, HasLen, 3)
+	c.Assert(ops[0].Kind()&operator.OpLeader, Not(Equals), operator.OpKind(0))
+	c.Assert(ops[1].Kind()&operator.OpRegion, Not(Equals), operator.OpKind(0))
+	c.Assert(ops[2].Kind()&operator.OpLeader, Not(Equals), operator.OpKind(0))
+
+	tc.SetLeaderScheduleLimit(0)
+	tc.SetRegionScheduleLimit(0)
+	c.Assert(ps.IsScheduleAllowed(tc), IsFalse)
+	c.Assert(ps.Schedule(tc), HasLen, 0)
+}
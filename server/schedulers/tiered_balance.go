@@ -0,0 +1,236 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/storage/endpoint"
+)
+
+const (
+	// TieredBalanceName is tiered balance scheduler name.
+	TieredBalanceName = "tiered-balance-scheduler"
+	// TieredBalanceType is tiered balance scheduler type.
+	TieredBalanceType = "tiered-balance"
+	// DefaultTieredLeaderTolerance is the default tolerance of the leader count
+	// difference between stores, relative to the average.
+	DefaultTieredLeaderTolerance = 0.1
+	// DefaultTieredPeerTolerance is the default tolerance of the peer count
+	// difference between stores, relative to the average.
+	DefaultTieredPeerTolerance = 0.1
+)
+
+func init() {
+	// args: [leader-tolerance, peer-tolerance], both are optional.
+	schedule.RegisterSliceDecoderBuilder(TieredBalanceType, func(args []string) schedule.ConfigDecoder {
+		return func(v interface{}) error {
+			conf, ok := v.(*tieredBalanceSchedulerConfig)
+			if !ok {
+				return errs.ErrScheduleConfigNotExist.FastGenByArgs()
+			}
+			if len(args) > 2 {
+				return errs.ErrSchedulerConfig.FastGenByArgs("tolerances")
+			}
+			conf.LeaderTolerance = DefaultTieredLeaderTolerance
+			conf.PeerTolerance = DefaultTieredPeerTolerance
+			var err error
+			if len(args) > 0 {
+				if conf.LeaderTolerance, err = parseTolerance(args[0]); err != nil {
+					return err
+				}
+			}
+			if len(args) > 1 {
+				if conf.PeerTolerance, err = parseTolerance(args[1]); err != nil {
+					return err
+				}
+			}
+			conf.Ranges = []core.KeyRange{core.NewKeyRange("", "")}
+			conf.Name = TieredBalanceName
+			return nil
+		}
+	})
+
+	schedule.RegisterScheduler(TieredBalanceType, func(opController *schedule.OperatorController, storage endpoint.ConfigStorage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
+		conf := &tieredBalanceSchedulerConfig{
+			LeaderTolerance: DefaultTieredLeaderTolerance,
+			PeerTolerance:   DefaultTieredPeerTolerance,
+		}
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		return newTieredBalanceScheduler(opController, conf), nil
+	})
+}
+
+func parseTolerance(arg string) (float64, error) {
+	tolerance, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, errs.ErrStrconvParseFloat.Wrap(err).FastGenWithCause()
+	}
+	if tolerance < 0 {
+		return 0, errs.ErrSchedulerConfig.FastGenByArgs("tolerance")
+	}
+	return tolerance, nil
+}
+
+type tieredBalanceSchedulerConfig struct {
+	Name   string          `json:"name"`
+	Ranges []core.KeyRange `json:"ranges"`
+	// LeaderTolerance is the tolerable leader count difference between the
+	// most and the least loaded stores, relative to the average. Peers are
+	// not moved until the leaders are balanced within the tolerance.
+	LeaderTolerance float64 `json:"leader-tolerance"`
+	// PeerTolerance is the tolerable peer count difference between the most
+	// and the least loaded stores, relative to the average.
+	PeerTolerance float64 `json:"peer-tolerance"`
+}
+
+type tieredBalanceScheduler struct {
+	*BaseScheduler
+	conf          *tieredBalanceSchedulerConfig
+	leaderFilters []filter.Filter
+	peerFilters   []filter.Filter
+	balanceLeader schedule.Scheduler
+	balanceRegion schedule.Scheduler
+}
+
+// newTieredBalanceScheduler creates a scheduler that balances the leaders
+// first, and balances the peers only after the leaders are balanced, since
+// the leader imbalance hurts the latency much more than the peer imbalance.
+func newTieredBalanceScheduler(opController *schedule.OperatorController, conf *tieredBalanceSchedulerConfig) schedule.Scheduler {
+	s := &tieredBalanceScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+		balanceLeader: newBalanceLeaderScheduler(
+			opController,
+			&balanceLeaderSchedulerConfig{Ranges: conf.Ranges, Batch: BalanceLeaderBatchSize},
+			WithBalanceLeaderName("tiered-balance-leader"),
+		),
+		balanceRegion: newBalanceRegionScheduler(
+			opController,
+			&balanceRegionSchedulerConfig{Ranges: conf.Ranges},
+			WithBalanceRegionName("tiered-balance-region"),
+		),
+	}
+	s.leaderFilters = []filter.Filter{
+		&filter.StoreStateFilter{ActionScope: s.GetName(), TransferLeader: true},
+		filter.NewSpecialUseFilter(s.GetName()),
+	}
+	s.peerFilters = []filter.Filter{
+		&filter.StoreStateFilter{ActionScope: s.GetName(), MoveRegion: true},
+		filter.NewSpecialUseFilter(s.GetName()),
+	}
+	return s
+}
+
+func (s *tieredBalanceScheduler) GetName() string {
+	return s.conf.Name
+}
+
+func (s *tieredBalanceScheduler) GetType() string {
+	return TieredBalanceType
+}
+
+func (s *tieredBalanceScheduler) EncodeConfig() ([]byte, error) {
+	return schedule.EncodeConfig(s.conf)
+}
+
+func (s *tieredBalanceScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	return s.balanceLeader.IsScheduleAllowed(cluster) || s.balanceRegion.IsScheduleAllowed(cluster)
+}
+
+func (s *tieredBalanceScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	opInfluence := s.OpController.GetOpInfluence(cluster)
+	if !s.isLeaderBalanced(cluster, opInfluence) {
+		if !s.balanceLeader.IsScheduleAllowed(cluster) {
+			return nil
+		}
+		if ops := s.balanceLeader.Schedule(cluster); len(ops) > 0 {
+			s.attachCounters(ops, "new-leader-operator")
+			return ops
+		}
+		// The leaders cannot be balanced any further until some peers are
+		// moved, e.g. a new store without any peer joins the cluster.
+		schedulerCounter.WithLabelValues(s.GetName(), "leader-blocked").Inc()
+	}
+	if s.isPeerBalanced(cluster, opInfluence) {
+		schedulerCounter.WithLabelValues(s.GetName(), "balanced").Inc()
+		return nil
+	}
+	if !s.balanceRegion.IsScheduleAllowed(cluster) {
+		return nil
+	}
+	ops := s.balanceRegion.Schedule(cluster)
+	s.attachCounters(ops, "new-peer-operator")
+	return ops
+}
+
+func (s *tieredBalanceScheduler) attachCounters(ops []*operator.Operator, event string) {
+	for _, op := range ops {
+		op.Counters = append(op.Counters,
+			schedulerCounter.WithLabelValues(s.GetName(), "new-operator"),
+			schedulerCounter.WithLabelValues(s.GetName(), event))
+	}
+}
+
+// isLeaderBalanced returns true if the leader count difference between the
+// stores is within the leader tolerance.
+func (s *tieredBalanceScheduler) isLeaderBalanced(cluster schedule.Cluster, opInfluence operator.OpInfluence) bool {
+	stores := filter.SelectSourceStores(cluster.GetStores(), s.leaderFilters, cluster.GetOpts())
+	return isCountBalanced(stores, s.conf.LeaderTolerance, func(store *core.StoreInfo) int64 {
+		return int64(store.GetLeaderCount()) + opInfluence.GetStoreInfluence(store.GetID()).LeaderCount
+	})
+}
+
+// isPeerBalanced returns true if the peer count difference between the stores
+// is within the peer tolerance.
+func (s *tieredBalanceScheduler) isPeerBalanced(cluster schedule.Cluster, opInfluence operator.OpInfluence) bool {
+	stores := filter.SelectSourceStores(cluster.GetStores(), s.peerFilters, cluster.GetOpts())
+	return isCountBalanced(stores, s.conf.PeerTolerance, func(store *core.StoreInfo) int64 {
+		return int64(store.GetRegionCount()) + opInfluence.GetStoreInfluence(store.GetID()).RegionCount
+	})
+}
+
+// isCountBalanced checks if the difference between the max and the min count
+// of the stores is within the tolerance relative to the average. Since moving
+// one unit narrows the difference by two, a difference of one is always
+// tolerated.
+func isCountBalanced(stores []*core.StoreInfo, tolerance float64, count func(*core.StoreInfo) int64) bool {
+	if len(stores) < 2 {
+		return true
+	}
+	var total int64
+	maxCount, minCount := int64(math.MinInt64), int64(math.MaxInt64)
+	for _, store := range stores {
+		c := count(store)
+		total += c
+		if c > maxCount {
+			maxCount = c
+		}
+		if c < minCount {
+			minCount = c
+		}
+	}
+	avg := float64(total) / float64(len(stores))
+	return float64(maxCount-minCount) <= math.Max(1, tolerance*avg)
+}
@@ -0,0 +1,281 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/storage/endpoint"
+	"github.com/unrolled/render"
+	"go.uber.org/zap"
+)
+
+const (
+	// ZoneAwareLeaderName is zone aware leader scheduler name.
+	ZoneAwareLeaderName = "zone-aware-leader-scheduler"
+	// ZoneAwareLeaderType is zone aware leader scheduler type.
+	ZoneAwareLeaderType = "zone-aware-leader"
+	// DefaultZoneLabelKey is the default key of the store label telling the zone.
+	DefaultZoneLabelKey = "zone"
+	// zoneAwareLeaderScanLimit is the max number of regions scanned in the
+	// key range of a hint in one schedule.
+	zoneAwareLeaderScanLimit = 128
+)
+
+func init() {
+	// args: [zone-label-key], it is optional.
+	schedule.RegisterSliceDecoderBuilder(ZoneAwareLeaderType, func(args []string) schedule.ConfigDecoder {
+		return func(v interface{}) error {
+			conf, ok := v.(*zoneAwareLeaderSchedulerConfig)
+			if !ok {
+				return errs.ErrScheduleConfigNotExist.FastGenByArgs()
+			}
+			if len(args) > 1 {
+				return errs.ErrSchedulerConfig.FastGenByArgs("zone label key")
+			}
+			conf.ZoneLabelKey = DefaultZoneLabelKey
+			if len(args) > 0 && len(args[0]) > 0 {
+				conf.ZoneLabelKey = args[0]
+			}
+			conf.Name = ZoneAwareLeaderName
+			return nil
+		}
+	})
+
+	schedule.RegisterScheduler(ZoneAwareLeaderType, func(opController *schedule.OperatorController, storage endpoint.ConfigStorage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
+		conf := &zoneAwareLeaderSchedulerConfig{ZoneLabelKey: DefaultZoneLabelKey}
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		return newZoneAwareLeaderScheduler(opController, conf), nil
+	})
+}
+
+type zoneAwareLeaderSchedulerConfig struct {
+	Name string `json:"name"`
+	// ZoneLabelKey is the key of the store label telling the zone of a store.
+	ZoneLabelKey string `json:"zone-label-key"`
+}
+
+// zoneTrafficHint is the request count of every zone to a key range, reported
+// by the SQL layer from its hot cache.
+type zoneTrafficHint struct {
+	StartKey     string            `json:"start-key"`
+	EndKey       string            `json:"end-key"`
+	ZoneRequests map[string]uint64 `json:"zone-requests"`
+}
+
+// hotZone returns the zone sending the most requests to the key range. The
+// zone with the smaller name wins a tie to keep the choice stable.
+func (h *zoneTrafficHint) hotZone() (zone string, requests uint64) {
+	for z, r := range h.ZoneRequests {
+		if r > requests || (r == requests && r > 0 && z < zone) {
+			zone, requests = z, r
+		}
+	}
+	return
+}
+
+func (h *zoneTrafficHint) totalRequests() uint64 {
+	var total uint64
+	for _, r := range h.ZoneRequests {
+		total += r
+	}
+	return total
+}
+
+type zoneAwareLeaderScheduler struct {
+	*BaseScheduler
+	conf    *zoneAwareLeaderSchedulerConfig
+	filters []filter.Filter
+	handler http.Handler
+
+	mu    sync.RWMutex
+	hints []*zoneTrafficHint
+}
+
+// newZoneAwareLeaderScheduler creates a scheduler that moves the leader of
+// the regions to the zone sending the most requests to them, so that the
+// reads are not served across the zones. It works only if the
+// enable-zone-aware-leader option is on.
+func newZoneAwareLeaderScheduler(opController *schedule.OperatorController, conf *zoneAwareLeaderSchedulerConfig) schedule.Scheduler {
+	s := &zoneAwareLeaderScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+	}
+	s.filters = []filter.Filter{
+		&filter.StoreStateFilter{ActionScope: s.GetName(), TransferLeader: true},
+		filter.NewSpecialUseFilter(s.GetName()),
+	}
+	s.handler = newZoneAwareLeaderHandler(s)
+	return s
+}
+
+func (s *zoneAwareLeaderScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *zoneAwareLeaderScheduler) GetName() string {
+	return s.conf.Name
+}
+
+func (s *zoneAwareLeaderScheduler) GetType() string {
+	return ZoneAwareLeaderType
+}
+
+func (s *zoneAwareLeaderScheduler) EncodeConfig() ([]byte, error) {
+	return schedule.EncodeConfig(s.conf)
+}
+
+func (s *zoneAwareLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool {
+	if !cluster.GetOpts().IsZoneAwareLeaderEnabled() {
+		return false
+	}
+	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	return allowed
+}
+
+// setHints replaces the traffic hints, the hints with more requests are
+// scheduled first.
+func (s *zoneAwareLeaderScheduler) setHints(hints []*zoneTrafficHint) {
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].totalRequests() > hints[j].totalRequests()
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hints = hints
+}
+
+func (s *zoneAwareLeaderScheduler) getHints() []*zoneTrafficHint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hints
+}
+
+func (s *zoneAwareLeaderScheduler) Schedule(cluster schedule.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	hints := s.getHints()
+	if len(hints) == 0 {
+		schedulerCounter.WithLabelValues(s.GetName(), "no-hint").Inc()
+		return nil
+	}
+	for _, hint := range hints {
+		zone, requests := hint.hotZone()
+		if requests == 0 {
+			continue
+		}
+		for _, region := range cluster.ScanRegions([]byte(hint.StartKey), []byte(hint.EndKey), zoneAwareLeaderScanLimit) {
+			if !schedule.IsRegionHealthy(region) {
+				continue
+			}
+			leader := cluster.GetStore(region.GetLeader().GetStoreId())
+			if leader == nil || leader.GetLabelValue(s.conf.ZoneLabelKey) == zone {
+				continue
+			}
+			if op := s.transferLeader(cluster, region, leader, zone); op != nil {
+				return []*operator.Operator{op}
+			}
+		}
+	}
+	schedulerCounter.WithLabelValues(s.GetName(), "no-region").Inc()
+	return nil
+}
+
+// transferLeader creates an operator which transfers the leader of the region
+// to the follower with the least leaders in the zone.
+func (s *zoneAwareLeaderScheduler) transferLeader(cluster schedule.Cluster, region *core.RegionInfo, source *core.StoreInfo, zone string) *operator.Operator {
+	var candidates []*core.StoreInfo
+	for _, store := range cluster.GetFollowerStores(region) {
+		if store.GetLabelValue(s.conf.ZoneLabelKey) == zone {
+			candidates = append(candidates, store)
+		}
+	}
+	finalFilters := s.filters
+	opts := cluster.GetOpts()
+	if leaderFilter := filter.NewPlacementLeaderSafeguard(s.GetName(), opts, cluster.GetBasicCluster(), cluster.GetRuleManager(), region, source); leaderFilter != nil {
+		finalFilters = append(s.filters, leaderFilter)
+	}
+	candidates = filter.SelectTargetStores(candidates, finalFilters, opts)
+	if len(candidates) == 0 {
+		log.Debug("region has no target store in the zone", zap.String("scheduler", s.GetName()),
+			zap.Uint64("region-id", region.GetID()), zap.String("zone", zone))
+		schedulerCounter.WithLabelValues(s.GetName(), "no-target-store").Inc()
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].GetLeaderCount() < candidates[j].GetLeaderCount()
+	})
+	op, err := operator.CreateTransferLeaderOperator(ZoneAwareLeaderType, cluster, region, source.GetID(), candidates[0].GetID(), []uint64{}, operator.OpLeader)
+	if err != nil {
+		log.Debug("fail to create zone aware leader operator", errs.ZapError(err))
+		return nil
+	}
+	op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
+	return op
+}
+
+type zoneAwareLeaderHandler struct {
+	rd        *render.Render
+	scheduler *zoneAwareLeaderScheduler
+}
+
+// UpdateHints replaces the traffic hints by the hints in the body.
+func (handler *zoneAwareLeaderHandler) UpdateHints(w http.ResponseWriter, r *http.Request) {
+	var hints []*zoneTrafficHint
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &hints); err != nil {
+		return
+	}
+	for _, hint := range hints {
+		if len(hint.EndKey) > 0 && hint.StartKey >= hint.EndKey {
+			handler.rd.JSON(w, http.StatusBadRequest, "start key should be less than end key")
+			return
+		}
+	}
+	handler.scheduler.setHints(hints)
+	handler.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (handler *zoneAwareLeaderHandler) ListHints(w http.ResponseWriter, r *http.Request) {
+	handler.rd.JSON(w, http.StatusOK, handler.scheduler.getHints())
+}
+
+func (handler *zoneAwareLeaderHandler) ListConfig(w http.ResponseWriter, r *http.Request) {
+	handler.rd.JSON(w, http.StatusOK, handler.scheduler.conf)
+}
+
+func newZoneAwareLeaderHandler(scheduler *zoneAwareLeaderScheduler) http.Handler {
+	h := &zoneAwareLeaderHandler{
+		scheduler: scheduler,
+		rd:        render.New(render.Options{IndentJSON: true}),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/hints", h.UpdateHints).Methods("POST")
+	router.HandleFunc("/hints", h.ListHints).Methods("GET")
+	router.HandleFunc("/list", h.ListConfig).Methods("GET")
+	return router
+}
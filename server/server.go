@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
@@ -44,14 +45,19 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/etcdutil"
 	"github.com/tikv/pd/pkg/grpcutil"
+	"github.com/tikv/pd/pkg/ipfilter"
+	"github.com/tikv/pd/pkg/jwtauth"
 	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/pkg/systimemon"
+	"github.com/tikv/pd/pkg/tlsutil"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/encryptionkm"
+	"github.com/tikv/pd/server/events"
 	"github.com/tikv/pd/server/id"
+	"github.com/tikv/pd/server/keyspace"
 	"github.com/tikv/pd/server/member"
 	syncer "github.com/tikv/pd/server/region_syncer"
 	"github.com/tikv/pd/server/schedule"
@@ -78,6 +84,9 @@ const (
 	pdRootPath      = "/pd"
 	pdAPIPrefix     = "/pd/"
 	pdClusterIDPath = "/pd/cluster_id"
+	// auditLogHeadFile is the file in the data dir to persist the hash of the
+	// last audit entry.
+	auditLogHeadFile = "audit-log-head"
 )
 
 var (
@@ -104,6 +113,8 @@ type Server struct {
 	etcdCfg            *embed.Config
 	persistOptions     *config.PersistOptions
 	handler            *Handler
+	// tsoClientLimiter limits the TSO requests of every client, nil means no limit.
+	tsoClientLimiter *tsoClientLimiter
 
 	ctx              context.Context
 	serverLoopCtx    context.Context
@@ -128,6 +139,8 @@ type Server struct {
 	encryptionKeyManager *encryptionkm.KeyManager
 	// for storage operation.
 	storage storage.Storage
+	// for keyspace metadata.
+	keyspaceManager *keyspace.Manager
 	// for basicCluster operation.
 	basicCluster *core.BasicCluster
 	// for tso.
@@ -161,6 +174,21 @@ type Server struct {
 	serviceAuditBackendLabels map[string]*audit.BackendLabels
 
 	auditBackends []audit.Backend
+	// auditLogger records the mutating HTTP API calls, it is nil if the audit
+	// log is disabled.
+	auditLogger *audit.Logger
+	// ipFilter decides which networks are allowed to access the HTTP and gRPC
+	// APIs, it is nil if all networks are allowed.
+	ipFilter *ipfilter.Filter
+	// grpcWebServer serves the gRPC-Web requests, it is nil if gRPC-Web is
+	// disabled.
+	grpcWebServer *grpcWebServer
+	// certReloader reloads the certificate used by PD to connect to the other
+	// components once it is rotated, it is nil if TLS is disabled.
+	certReloader *tlsutil.CertReloader
+	// jwtSigner signs the tokens of the HTTP API users, its key is rotated
+	// once this PD becomes the leader. It is nil if JWT auth is disabled.
+	jwtSigner *jwtauth.Signer
 }
 
 // HandlerBuilder builds a server HTTP handler.
@@ -248,6 +276,9 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 		storeConfigManager: config.NewStoreConfigManager(&cfg.Security),
 	}
 	s.handler = newHandler(s)
+	if cfg.TSOClientRateLimit > 0 {
+		s.tsoClientLimiter = newTSOClientLimiter(ctx, cfg.TSOClientRateLimit, cfg.TSOClientBurst)
+	}
 
 	// create audit backend
 	s.auditBackends = []audit.Backend{
@@ -257,6 +288,21 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 	s.serviceAuditBackendLabels = make(map[string]*audit.BackendLabels)
 	s.serviceLabels = make(map[string][]apiutil.AccessPath)
 	s.apiServiceLabelMap = make(map[apiutil.AccessPath]string)
+	auditLogger, err := newAuditLogger(cfg.Security.AuditLog, cfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	s.auditLogger = auditLogger
+	s.ipFilter, err = ipfilter.New(cfg.Security.APIAllowCIDRs, cfg.Security.APIDenyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Security.EnableJWTAuth {
+		s.jwtSigner, err = jwtauth.NewSigner()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Adjust etcd config.
 	etcdCfg, err := s.cfg.GenEmbedEtcdConfig()
@@ -268,11 +314,22 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 		if err != nil {
 			return nil, err
 		}
+		for prefix, handler := range userHandlers {
+			userHandlers[prefix] = s.ipFilter.Handler(handler)
+		}
 		etcdCfg.UserHandlers = userHandlers
 	}
 	etcdCfg.ServiceRegister = func(gs *grpc.Server) {
-		pdpb.RegisterPDServer(gs, &GrpcServer{Server: s})
+		registerPDServer(gs, &GrpcServer{Server: s})
 		diagnosticspb.RegisterDiagnosticsServer(gs, s)
+		if cfg.Security.EnableGRPCReflection {
+			// Prevent the RPCs from being discovered by the unauthenticated clients.
+			if len(cfg.Security.CertPath) == 0 {
+				log.Warn("gRPC reflection is not enabled since TLS is not enabled")
+			} else {
+				registerGRPCReflection(gs)
+			}
+		}
 	}
 	s.etcdCfg = etcdCfg
 	if EnableZap {
@@ -288,6 +345,36 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 	return s, nil
 }
 
+func newAuditLogger(cfg config.AuditLogConfig, dataDir string) (*audit.Logger, error) {
+	var (
+		w   audit.Writer
+		key []byte
+		err error
+	)
+	if cfg.HMACKeyPath != "" {
+		if key, err = ioutil.ReadFile(cfg.HMACKeyPath); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	switch cfg.Writer {
+	case "file":
+		w, err = audit.NewFileWriter(cfg.Filename)
+	case "syslog":
+		w, err = audit.NewSyslogWriter("pd-audit")
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger, err := audit.NewLogger(w, key, filepath.Join(dataDir, auditLogHeadFile))
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+	return logger, nil
+}
+
 func (s *Server) startEtcd(ctx context.Context) error {
 	newCtx, cancel := context.WithTimeout(ctx, EtcdStartTimeout)
 	defer cancel()
@@ -306,6 +393,15 @@ func (s *Server) startEtcd(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if tlsConfig != nil && len(s.cfg.Security.CertPath) != 0 {
+		// The listeners owned by the embedded etcd read the certificate files
+		// on every handshake, only the connections made by PD need the reloader.
+		s.certReloader, err = tlsutil.NewCertReloader(s.cfg.Security.CertPath, s.cfg.Security.KeyPath)
+		if err != nil {
+			return err
+		}
+		s.certReloader.Apply(tlsConfig)
+	}
 
 	if err = etcdutil.CheckClusterID(etcd.Server.Cluster().ID(), urlMap, tlsConfig); err != nil {
 		return err
@@ -406,6 +502,7 @@ func (s *Server) startServer(ctx context.Context) error {
 	}
 	defaultStorage := storage.NewStorageWithEtcdBackend(s.client, s.rootPath)
 	s.storage = storage.NewCoreStorage(defaultStorage, regionStorage)
+	s.keyspaceManager = keyspace.NewManager(s.storage)
 	s.basicCluster = core.NewBasicCluster()
 	s.cluster = cluster.NewRaftCluster(ctx, s.clusterID, syncer.NewRegionSyncer(s), s.client, s.httpClient, s.storeConfigManager)
 	s.hbStreams = hbstream.NewHeartbeatStreams(ctx, s.clusterID, s.cluster)
@@ -456,6 +553,7 @@ func (s *Server) Close() {
 	log.Info("closing server")
 
 	s.stopServerLoop()
+	s.closeGRPCWebServer()
 
 	if s.client != nil {
 		if err := s.client.Close(); err != nil {
@@ -487,6 +585,12 @@ func (s *Server) Close() {
 		cb()
 	}
 
+	if s.auditLogger != nil {
+		if err := s.auditLogger.Close(); err != nil {
+			log.Error("close audit log meet error", errs.ZapError(err))
+		}
+	}
+
 	log.Info("close server")
 }
 
@@ -507,6 +611,11 @@ func (s *Server) Run() error {
 	if err := s.startServer(s.ctx); err != nil {
 		return err
 	}
+	if s.cfg.EnableGRPCWeb {
+		if err := s.startGRPCWebServer(); err != nil {
+			return err
+		}
+	}
 
 	s.startServerLoop(s.ctx)
 
@@ -535,12 +644,13 @@ func (s *Server) LoopContext() context.Context {
 
 func (s *Server) startServerLoop(ctx context.Context) {
 	s.serverLoopCtx, s.serverLoopCancel = context.WithCancel(ctx)
-	s.serverLoopWg.Add(5)
+	s.serverLoopWg.Add(6)
 	go s.leaderLoop()
 	go s.etcdLeaderLoop()
 	go s.serverMetricsLoop()
 	go s.tsoAllocatorLoop()
 	go s.encryptionKeyManagerLoop()
+	go s.certReloaderLoop()
 }
 
 func (s *Server) stopServerLoop() {
@@ -587,6 +697,23 @@ func (s *Server) encryptionKeyManagerLoop() {
 	log.Info("server is closed, exist encryption key manager loop")
 }
 
+// certReloaderLoop is used to reload the certificate once it is rotated.
+func (s *Server) certReloaderLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	if s.certReloader == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.serverLoopCtx)
+	defer cancel()
+	if err := s.certReloader.Run(ctx); err != nil {
+		log.Error("failed to watch the certificate", errs.ZapError(err))
+		return
+	}
+	log.Info("server is closed, exit cert reloader loop")
+}
+
 func (s *Server) collectEtcdStateMetrics() {
 	etcdStateGauge.WithLabelValues("term").Set(float64(s.member.Etcd().Server.Term()))
 	etcdStateGauge.WithLabelValues("appliedIndex").Set(float64(s.member.Etcd().Server.AppliedIndex()))
@@ -747,6 +874,11 @@ func (s *Server) GetStorage() storage.Storage {
 	return s.storage
 }
 
+// GetKeyspaceManager returns the keyspace manager.
+func (s *Server) GetKeyspaceManager() *keyspace.Manager {
+	return s.keyspaceManager
+}
+
 // GetHistoryHotRegionStorage returns the backend storage of historyHotRegion.
 func (s *Server) GetHistoryHotRegionStorage() *storage.HotRegionStorage {
 	return s.hotRegionStorage
@@ -768,6 +900,29 @@ func (s *Server) IsAuditMiddlewareEnabled() bool {
 	return s.cfg.EnableAuditMiddleware
 }
 
+// GetAuditLogger returns the logger of the mutating HTTP API calls, it is nil
+// if the audit log is disabled.
+func (s *Server) GetAuditLogger() *audit.Logger {
+	return s.auditLogger
+}
+
+// GetAPITokens returns the tokens and their roles of the HTTP API clients, it
+// is empty if the HTTP API is not protected.
+func (s *Server) GetAPITokens() map[string]string {
+	return s.cfg.Security.APITokens
+}
+
+// GetJWTSigner returns the signer of the tokens of the HTTP API users, it is
+// nil if JWT auth is disabled.
+func (s *Server) GetJWTSigner() *jwtauth.Signer {
+	return s.jwtSigner
+}
+
+// GetJWTExpiry returns how long the tokens of the HTTP API users are valid.
+func (s *Server) GetJWTExpiry() time.Duration {
+	return s.cfg.Security.JWTExpiry.Duration
+}
+
 // GetBasicCluster returns the basic cluster of server.
 func (s *Server) GetBasicCluster() *core.BasicCluster {
 	return s.basicCluster
@@ -875,6 +1030,7 @@ func (s *Server) SetScheduleConfig(cfg config.ScheduleConfig) error {
 		return err
 	}
 	log.Info("schedule config is updated", zap.Reflect("new", cfg), zap.Reflect("old", old))
+	s.publishConfigChange("schedule")
 	return nil
 }
 
@@ -961,6 +1117,7 @@ func (s *Server) SetReplicationConfig(cfg config.ReplicationConfig) error {
 		return err
 	}
 	log.Info("replication config is updated", zap.Reflect("new", cfg), zap.Reflect("old", old))
+	s.publishConfigChange("replication")
 	return nil
 }
 
@@ -997,6 +1154,7 @@ func (s *Server) SetPDServerConfig(cfg config.PDServerConfig) error {
 		return err
 	}
 	log.Info("PD server config is updated", zap.Reflect("new", cfg), zap.Reflect("old", old))
+	s.publishConfigChange("pd-server")
 	return nil
 }
 
@@ -1013,6 +1171,7 @@ func (s *Server) SetLabelPropertyConfig(cfg config.LabelPropertyConfig) error {
 		return err
 	}
 	log.Info("label property config is updated", zap.Reflect("new", cfg), zap.Reflect("old", old))
+	s.publishConfigChange("label-property")
 	return nil
 }
 
@@ -1032,6 +1191,7 @@ func (s *Server) SetLabelProperty(typ, labelKey, labelValue string) error {
 	}
 
 	log.Info("label property config is updated", zap.Reflect("config", s.persistOptions.GetLabelPropertyConfig()))
+	s.publishConfigChange("label-property")
 	return nil
 }
 
@@ -1051,6 +1211,7 @@ func (s *Server) DeleteLabelProperty(typ, labelKey, labelValue string) error {
 	}
 
 	log.Info("label property config is deleted", zap.Reflect("config", s.persistOptions.GetLabelPropertyConfig()))
+	s.publishConfigChange("label-property")
 	return nil
 }
 
@@ -1218,6 +1379,9 @@ func (s *Server) SetReplicationModeConfig(cfg config.ReplicationModeConfig) erro
 	if config.NormalizeReplicationMode(cfg.ReplicationMode) == "" {
 		return errors.Errorf("invalid replication mode: %v", cfg.ReplicationMode)
 	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
 
 	old := s.persistOptions.GetReplicationModeConfig()
 	s.persistOptions.SetReplicationModeConfig(&cfg)
@@ -1246,13 +1410,28 @@ func (s *Server) SetReplicationModeConfig(cfg config.ReplicationModeConfig) erro
 			if revertErr != nil {
 				log.Error("failed to revert replication mode persistent config", errs.ZapError(revertErr))
 			}
+			return err
 		}
-		return err
 	}
 
+	s.publishConfigChange("replication-mode")
 	return nil
 }
 
+// GetEventHub returns the hub of the cluster events, it returns nil if the
+// server has not started.
+func (s *Server) GetEventHub() *events.Hub {
+	if s.cluster == nil {
+		return nil
+	}
+	return s.cluster.GetEventHub()
+}
+
+// publishConfigChange publishes the change event of the config section.
+func (s *Server) publishConfigChange(section string) {
+	s.GetEventHub().Publish(events.NewClusterEvent(events.ConfigChange, section))
+}
+
 func (s *Server) leaderLoop() {
 	defer logutil.LogPanic()
 	defer s.serverLoopWg.Done()
@@ -1296,6 +1475,16 @@ func (s *Server) leaderLoop() {
 			time.Sleep(200 * time.Millisecond)
 			continue
 		}
+		// Campaigning with the drifted clock only makes the leader step down again.
+		if allocator, err := s.tsoAllocatorManager.GetAllocator(tso.GlobalDCLocation); err == nil {
+			if err := allocator.CheckClockDrift(); err != nil {
+				log.Warn("skip campaigning of pd leader and check later",
+					zap.String("server-name", s.Name()),
+					errs.ZapError(err))
+				time.Sleep(200 * time.Millisecond)
+				continue
+			}
+		}
 		s.campaignLeader()
 	}
 }
@@ -1366,6 +1555,14 @@ func (s *Server) campaignLeader() {
 		log.Error("failed to sync id from etcd", errs.ZapError(err))
 		return
 	}
+	// Rotate the key to reject the tokens issued before, in case this PD was
+	// the leader once.
+	if s.jwtSigner != nil {
+		if err := s.jwtSigner.Rotate(); err != nil {
+			log.Error("failed to rotate the jwt signing key", errs.ZapError(err))
+			return
+		}
+	}
 	// EnableLeader to accept the remaining service, such as GetStore, GetRegion.
 	s.member.EnableLeader()
 	// Check the cluster dc-location after the PD leader is elected.
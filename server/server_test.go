@@ -15,21 +15,42 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/assertutil"
 	"github.com/tikv/pd/pkg/etcdutil"
+	"github.com/tikv/pd/pkg/grpcutil"
+	"github.com/tikv/pd/pkg/tempurl"
 	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
 	"go.etcd.io/etcd/embed"
 	"go.etcd.io/etcd/pkg/types"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
 )
 
 func TestServer(t *testing.T) {
@@ -196,6 +217,223 @@ func (s *testServerSuite) TestCheckClusterID(c *C) {
 	testutil.CleanServer(cfgA.DataDir)
 }
 
+func (s *testServerSuite) TestGRPCReflection(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	certDir := "../tests/client/cert"
+	cfg := NewTestSingleConfig(checkerWithNilAssert(c))
+	cfg.Security.TLSConfig = grpcutil.TLSConfig{
+		CAPath:   certDir + "/ca.pem",
+		CertPath: certDir + "/pd-server.pem",
+		KeyPath:  certDir + "/pd-server-key.pem",
+	}
+	cfg.Security.EnableGRPCReflection = true
+	for _, u := range []*string{&cfg.ClientUrls, &cfg.AdvertiseClientUrls, &cfg.PeerUrls, &cfg.AdvertisePeerUrls, &cfg.InitialCluster} {
+		*u = strings.ReplaceAll(*u, "http", "https")
+	}
+	svrs, cleanup := newTestServersWithCfgs(ctx, c, []*config.Config{cfg})
+	defer cleanup()
+
+	tlsCfg, err := grpcutil.TLSConfig{
+		CAPath:   certDir + "/ca.pem",
+		CertPath: certDir + "/client.pem",
+		KeyPath:  certDir + "/client-key.pem",
+	}.ToTLSConfig()
+	c.Assert(err, IsNil)
+	conn, err := grpcutil.GetClientConn(ctx, svrs[0].GetAddr(), tlsCfg)
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	c.Assert(err, IsNil)
+
+	// List the services.
+	err = stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}})
+	c.Assert(err, IsNil)
+	resp, err := stream.Recv()
+	c.Assert(err, IsNil)
+	services := make(map[string]struct{})
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services[service.GetName()] = struct{}{}
+	}
+	for _, name := range []string{"pdpb.PD", "diagnosticspb.Diagnostics", "grpc.reflection.v1alpha.ServerReflection"} {
+		_, ok := services[name]
+		c.Assert(ok, IsTrue, Commentf("service %s not found", name))
+	}
+
+	// List the methods of PD.
+	err = stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "pdpb.PD"}})
+	c.Assert(err, IsNil)
+	resp, err = stream.Recv()
+	c.Assert(err, IsNil)
+	methods := make(map[string]struct{})
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptor.FileDescriptorProto{}
+		c.Assert(proto.Unmarshal(b, fd), IsNil)
+		for _, service := range fd.GetService() {
+			if fd.GetPackage()+"."+service.GetName() != "pdpb.PD" {
+				continue
+			}
+			for _, method := range service.GetMethod() {
+				methods[method.GetName()] = struct{}{}
+			}
+		}
+	}
+	for _, name := range []string{"GetMembers", "Tso", "StoreHeartbeat", "RegionHeartbeat", "GetRegion"} {
+		_, ok := methods[name]
+		c.Assert(ok, IsTrue, Commentf("method %s not found", name))
+	}
+}
+
+func (s *testServerSuite) TestGRPCReflectionWithoutTLS(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := NewTestSingleConfig(checkerWithNilAssert(c))
+	cfg.Security.EnableGRPCReflection = true
+	svrs, cleanup := newTestServersWithCfgs(ctx, c, []*config.Config{cfg})
+	defer cleanup()
+
+	conn, err := grpcutil.GetClientConn(ctx, svrs[0].GetAddr(), nil)
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	c.Assert(err, IsNil)
+	err = stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}})
+	c.Assert(err, IsNil)
+	_, err = stream.Recv()
+	c.Assert(status.Code(err), Equals, codes.Unimplemented)
+}
+
+func (s *testServerSuite) TestGRPCWeb(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := NewTestSingleConfig(checkerWithNilAssert(c))
+	cfg.EnableGRPCWeb = true
+	cfg.GRPCWebAddr = strings.TrimPrefix(tempurl.Alloc(), "http://")
+	cfg.GRPCWebAllowedOrigins = []string{"https://dashboard.example.com"}
+	svrs, cleanup := newTestServersWithCfgs(ctx, c, []*config.Config{cfg})
+	defer cleanup()
+
+	// A gRPC-Web message is prefixed by a flag byte and a 4 bytes length.
+	req := &pdpb.GetMembersRequest{Header: &pdpb.RequestHeader{ClusterId: svrs[0].ClusterID()}}
+	data, err := req.Marshal()
+	c.Assert(err, IsNil)
+	body := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(data)))
+	body = append(body, data...)
+	httpReq, err := http.NewRequest(http.MethodPost, "http://"+cfg.GRPCWebAddr+"/pdpb.PD/GetMembers", bytes.NewReader(body))
+	c.Assert(err, IsNil)
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("X-Grpc-Web", "1")
+	resp, err := http.DefaultClient.Do(httpReq)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "application/grpc-web+proto")
+	respBody, err := io.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+
+	// The response message is followed by the trailers with the flag 0x80.
+	c.Assert(len(respBody) > 5, IsTrue)
+	c.Assert(respBody[0], Equals, byte(0))
+	n := binary.BigEndian.Uint32(respBody[1:5])
+	members := &pdpb.GetMembersResponse{}
+	c.Assert(members.Unmarshal(respBody[5:5+n]), IsNil)
+	c.Assert(members.GetHeader().GetClusterId(), Equals, svrs[0].ClusterID())
+	c.Assert(members.GetMembers(), HasLen, 1)
+	c.Assert(members.GetLeader().GetName(), Equals, cfg.Name)
+	trailers := respBody[5+n:]
+	c.Assert(trailers[0], Equals, byte(0x80))
+	c.Assert(strings.ToLower(string(trailers[5:])), Matches, "(?s).*grpc-status: ?0.*")
+
+	// Only the allowed origins pass the CORS preflight.
+	preflight := func(origin string) string {
+		req, err := http.NewRequest(http.MethodOptions, "http://"+cfg.GRPCWebAddr+"/pdpb.PD/GetMembers", nil)
+		c.Assert(err, IsNil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, IsNil)
+		resp.Body.Close()
+		return resp.Header.Get("Access-Control-Allow-Origin")
+	}
+	c.Assert(preflight("https://dashboard.example.com"), Equals, "https://dashboard.example.com")
+	c.Assert(preflight("https://evil.example.com"), Equals, "")
+}
+
+func (s *testServerSuite) TestRPCDeadline(c *C) {
+	cfg := config.NewConfig()
+	cfg.RPCDeadlines = map[string]typeutil.Duration{"GetRegion": typeutil.NewDuration(time.Second)}
+	grpcServer := &GrpcServer{Server: &Server{cfg: cfg}}
+
+	// The server max is used if the client provides no deadline.
+	ctx, cancel := grpcServer.withDeadline(context.Background(), "GetRegion")
+	deadline, ok := ctx.Deadline()
+	c.Assert(ok, IsTrue)
+	c.Assert(time.Until(deadline), LessEqual, time.Second)
+	cancel()
+
+	// The longer client deadline is truncated.
+	clientCtx, clientCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer clientCancel()
+	ctx, cancel = grpcServer.withDeadline(clientCtx, "GetRegion")
+	deadline, ok = ctx.Deadline()
+	c.Assert(ok, IsTrue)
+	c.Assert(time.Until(deadline), LessEqual, time.Second)
+	cancel()
+
+	// The shorter client deadline is kept.
+	clientCtx, clientCancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer clientCancel()
+	ctx, cancel = grpcServer.withDeadline(clientCtx, "GetRegion")
+	c.Assert(ctx, Equals, clientCtx)
+	cancel()
+
+	// The methods without the max deadline are not limited.
+	ctx, cancel = grpcServer.withDeadline(context.Background(), "GetStore")
+	_, ok = ctx.Deadline()
+	c.Assert(ok, IsFalse)
+	cancel()
+}
+
+func (s *testServerSuite) TestClientIdentity(c *C) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	c.Assert(getClientIdentity(ctx), Equals, "10.0.0.1")
+	// The identity in the metadata is not trusted.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("pd-client-identity", "tidb-0"))
+	c.Assert(getClientIdentity(ctx), Equals, "10.0.0.1")
+	// The CN of the verified certificate is used.
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "tidb"}}
+	info := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: info})
+	c.Assert(getClientIdentity(ctx), Equals, "tidb")
+}
+
+func (s *testServerSuite) TestChainUnaryInterceptors(c *C) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/pdpb.PD/GetRegion"}
+	resp, err := chainUnaryInterceptors(interceptor("first"), interceptor("second"))(context.Background(), 1, info, handler)
+	c.Assert(err, IsNil)
+	c.Assert(resp, Equals, 1)
+	c.Assert(calls, DeepEquals, []string{"first", "second", "handler"})
+
+	calls = nil
+	_, err = chainUnaryInterceptors()(context.Background(), 1, info, handler)
+	c.Assert(err, IsNil)
+	c.Assert(calls, DeepEquals, []string{"handler"})
+}
+
 var _ = Suite(&testServerHandlerSuite{})
 
 type testServerHandlerSuite struct{}
@@ -239,6 +477,65 @@ func (s *testServerHandlerSuite) TestRegisterServerHandler(c *C) {
 	c.Assert(bodyString, Equals, "Hello World\n")
 }
 
+func (s *testServerHandlerSuite) TestIPFilter(c *C) {
+	mokHandler := func(ctx context.Context, s *Server) (http.Handler, ServiceGroup, error) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/pd/apis/mok/v1/hello", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "Hello World")
+		})
+		return mux, ServiceGroup{Name: "mok", Version: "v1"}, nil
+	}
+	cfg := NewTestSingleConfig(checkerWithNilAssert(c))
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg.Security.APIAllowCIDRs = []string{"127.0.0.0/8"}
+	cfg.Security.APIDenyCIDRs = []string{"127.0.0.1"}
+	_, err := CreateServer(ctx, cfg, mokHandler)
+	c.Assert(err, NotNil)
+	// The loopback client is denied.
+	cfg.Security.APIDenyCIDRs = []string{"127.0.0.1/32"}
+	svr, err := CreateServer(ctx, cfg, mokHandler)
+	c.Assert(err, IsNil)
+	defer func() {
+		cancel()
+		svr.Close()
+		testutil.CleanServer(svr.cfg.DataDir)
+	}()
+	err = svr.Run()
+	c.Assert(err, IsNil)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/pd/apis/mok/v1/hello", svr.GetAddr()), nil)
+	c.Assert(err, IsNil)
+	// The forwarding headers are ignored.
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusForbidden)
+
+	conn, err := grpcutil.GetClientConn(ctx, svr.GetAddr(), nil)
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	// Every RPC of the PD service rejects the client. The empty message is
+	// decoded as the empty request of any method.
+	gs := grpc.NewServer()
+	pdpb.RegisterPDServer(gs, struct{ pdpb.PDServer }{})
+	for _, m := range gs.GetServiceInfo()[pdServiceName].Methods {
+		method := "/" + pdServiceName + "/" + m.Name
+		if !m.IsClientStream && !m.IsServerStream {
+			err = conn.Invoke(ctx, method, &pdpb.GetMembersRequest{}, &pdpb.GetMembersResponse{})
+			c.Assert(status.Code(err), Equals, codes.PermissionDenied, Commentf("method: %s", m.Name))
+			continue
+		}
+		desc := &grpc.StreamDesc{StreamName: m.Name, ClientStreams: m.IsClientStream, ServerStreams: m.IsServerStream}
+		stream, err := conn.NewStream(ctx, desc, method)
+		c.Assert(err, IsNil)
+		c.Assert(stream.SendMsg(&pdpb.GetMembersRequest{}), IsNil)
+		c.Assert(stream.CloseSend(), IsNil)
+		err = stream.RecvMsg(&pdpb.GetMembersResponse{})
+		c.Assert(status.Code(err), Equals, codes.PermissionDenied, Commentf("method: %s", m.Name))
+	}
+}
+
 func (s *testServerHandlerSuite) TestSourceIpForHeaderForwarded(c *C) {
 	mokHandler := func(ctx context.Context, s *Server) (http.Handler, ServiceGroup, error) {
 		mux := http.NewServeMux()
@@ -16,6 +16,7 @@ package statistics
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/tikv/pd/pkg/movingaverage"
@@ -33,6 +34,10 @@ type HotCache struct {
 	ctx        context.Context
 	writeCache *hotPeerCache
 	readCache  *hotPeerCache
+
+	mu      sync.RWMutex
+	expiry  hotCacheExpiry
+	windows hotCacheWindows
 }
 
 // NewHotCache creates a new hot spot cache.
@@ -67,9 +72,43 @@ func (w *HotCache) CheckReadAsync(task flowItemTask) bool {
 	}
 }
 
+// SetExpiry sets the expiry policy of the hot peers. The loads of a hot peer
+// not updated for ttl decay exponentially by decayRate per second, and the
+// peer is removed from the cache once it's no longer hot. 0 ttl means the
+// loads never decay.
+func (w *HotCache) SetExpiry(ttl time.Duration, decayRate float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expiry = hotCacheExpiry{ttl: ttl, decayRate: decayRate}
+}
+
+func (w *HotCache) getExpiry() hotCacheExpiry {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.expiry
+}
+
+// SetWindows sets the short, medium and long windows to calculate the heat
+// scores of the hot peers, and the weights of them. It only takes effect on
+// the peers becoming hot later.
+func (w *HotCache) SetWindows(short, medium, long time.Duration, weights [WindowLen]float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.windows = hotCacheWindows{
+		durations: [WindowLen]time.Duration{short, medium, long},
+		weights:   weights,
+	}
+}
+
+func (w *HotCache) getWindows() hotCacheWindows {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.windows
+}
+
 // RegionStats returns hot items according to kind
 func (w *HotCache) RegionStats(kind RWType, minHotDegree int) map[uint64][]*HotPeerStat {
-	task := newCollectRegionStatsTask(minHotDegree)
+	task := newCollectRegionStatsTask(minHotDegree, w.getExpiry())
 	var succ bool
 	switch kind {
 	case Write:
@@ -132,6 +171,7 @@ func (w *HotCache) updateItems(queue <-chan flowItemTask, runTask func(task flow
 func (w *HotCache) runReadTask(task flowItemTask) {
 	if task != nil {
 		// TODO: do we need a run-task timeout to protect the queue won't be stuck by a task?
+		w.readCache.windows = w.getWindows()
 		task.runTask(w.readCache)
 		hotCacheFlowQueueStatusGauge.WithLabelValues(Read.String()).Set(float64(len(w.readCache.taskQueue)))
 	}
@@ -140,6 +180,7 @@ func (w *HotCache) runReadTask(task flowItemTask) {
 func (w *HotCache) runWriteTask(task flowItemTask) {
 	if task != nil {
 		// TODO: do we need a run-task timeout to protect the queue won't be stuck by a task?
+		w.writeCache.windows = w.getWindows()
 		task.runTask(w.writeCache)
 		hotCacheFlowQueueStatusGauge.WithLabelValues(Write.String()).Set(float64(len(w.writeCache.taskQueue)))
 	}
@@ -159,12 +200,14 @@ func (w *HotCache) Update(item *HotPeerStat) {
 // CheckWritePeerSync checks the write status, returns update items.
 // This is used for mockcluster, for test purpose.
 func (w *HotCache) CheckWritePeerSync(peer *core.PeerInfo, region *core.RegionInfo) *HotPeerStat {
+	w.writeCache.windows = w.getWindows()
 	return w.writeCache.checkPeerFlow(peer, region)
 }
 
 // CheckReadPeerSync checks the read status, returns update items.
 // This is used for mockcluster, for test purpose.
 func (w *HotCache) CheckReadPeerSync(peer *core.PeerInfo, region *core.RegionInfo) *HotPeerStat {
+	w.readCache.windows = w.getWindows()
 	return w.readCache.checkPeerFlow(peer, region)
 }
 
@@ -111,12 +111,14 @@ func (t *collectUnReportedPeerTask) runTask(cache *hotPeerCache) {
 
 type collectRegionStatsTask struct {
 	minDegree int
+	expiry    hotCacheExpiry
 	ret       chan map[uint64][]*HotPeerStat
 }
 
-func newCollectRegionStatsTask(minDegree int) *collectRegionStatsTask {
+func newCollectRegionStatsTask(minDegree int, expiry hotCacheExpiry) *collectRegionStatsTask {
 	return &collectRegionStatsTask{
 		minDegree: minDegree,
+		expiry:    expiry,
 		ret:       make(chan map[uint64][]*HotPeerStat, 1),
 	}
 }
@@ -126,7 +128,7 @@ func (t *collectRegionStatsTask) taskType() flowItemTaskKind {
 }
 
 func (t *collectRegionStatsTask) runTask(cache *hotPeerCache) {
-	t.ret <- cache.RegionStats(t.minDegree)
+	t.ret <- cache.RegionStats(t.minDegree, t.expiry)
 }
 
 // TODO: do we need a wait-return timeout?
@@ -32,23 +32,55 @@ const (
 	DimLen
 )
 
+// The multi-scale windows of the heat scores.
+const (
+	ShortWindow int = iota
+	MediumWindow
+	LongWindow
+	WindowLen
+)
+
 type dimStat struct {
 	typ         RegionStatKind
 	rolling     *movingaverage.TimeMedian  // it's used to statistic hot degree and average speed.
 	lastAverage *movingaverage.AvgOverTime // it's used to obtain the average speed in last second as instantaneous speed.
+	// windows are the average speeds in the multi-scale windows, it's nil if
+	// the windows are not configured.
+	windows []*movingaverage.AvgOverTime
 }
 
-func newDimStat(typ RegionStatKind, reportInterval time.Duration) *dimStat {
-	return &dimStat{
+func newDimStat(typ RegionStatKind, reportInterval time.Duration, windows hotCacheWindows) *dimStat {
+	d := &dimStat{
 		typ:         typ,
 		rolling:     movingaverage.NewTimeMedian(DefaultAotSize, rollingWindowsSize, reportInterval),
 		lastAverage: movingaverage.NewAvgOverTime(reportInterval),
 	}
+	if windows.enabled() {
+		d.windows = make([]*movingaverage.AvgOverTime, WindowLen)
+		for i := range d.windows {
+			d.windows[i] = movingaverage.NewAvgOverTime(windows.durations[i])
+		}
+	}
+	return d
 }
 
 func (d *dimStat) Add(delta float64, interval time.Duration) {
 	d.lastAverage.Add(delta, interval)
 	d.rolling.Add(delta, interval)
+	for _, w := range d.windows {
+		w.Add(delta, interval)
+	}
+}
+
+func (d *dimStat) getWindowLoads() []float64 {
+	if d.windows == nil {
+		return nil
+	}
+	loads := make([]float64, WindowLen)
+	for i, w := range d.windows {
+		loads[i] = w.GetPartial()
+	}
+	return loads
 }
 
 func (d *dimStat) isLastAverageHot(threshold float64) bool {
@@ -72,11 +104,18 @@ func (d *dimStat) Get() float64 {
 }
 
 func (d *dimStat) Clone() *dimStat {
-	return &dimStat{
+	ret := &dimStat{
 		typ:         d.typ,
 		rolling:     d.rolling.Clone(),
 		lastAverage: d.lastAverage.Clone(),
 	}
+	if d.windows != nil {
+		ret.windows = make([]*movingaverage.AvgOverTime, len(d.windows))
+		for i, w := range d.windows {
+			ret.windows[i] = w.Clone()
+		}
+	}
+	return ret
 }
 
 // HotPeerStat records each hot peer's statistics
@@ -102,6 +141,7 @@ type HotPeerStat struct {
 	isLeader               bool
 	interval               uint64
 	thresholds             []float64
+	windowWeights          [WindowLen]float64
 	peers                  []*metapb.Peer
 	lastTransferLeaderTime time.Time
 	// If the peer didn't been send by store heartbeat when it is already stored as hot peer stat,
@@ -177,6 +217,37 @@ func (stat *HotPeerStat) GetLoads() []float64 {
 	return loads
 }
 
+// GetWindowLoads returns the average loads in the short, medium and long
+// windows. It returns nil if the windows are not configured.
+func (stat *HotPeerStat) GetWindowLoads(k RegionStatKind) []float64 {
+	for i, kind := range stat.Kind.RegionStats() {
+		if kind == k && i < len(stat.rollingLoads) {
+			return stat.rollingLoads[i].getWindowLoads()
+		}
+	}
+	return nil
+}
+
+// GetHeatScore returns the weighted average of the loads in the multi-scale
+// windows, which is high for a sustained hotspot but low for a short burst
+// if the long window is weighted. It returns the load if the windows are not
+// configured.
+func (stat *HotPeerStat) GetHeatScore(k RegionStatKind) float64 {
+	loads := stat.GetWindowLoads(k)
+	if loads == nil {
+		return stat.GetLoad(k)
+	}
+	var score, weightSum float64
+	for i, load := range loads {
+		score += load * stat.windowWeights[i]
+		weightSum += stat.windowWeights[i]
+	}
+	if weightSum == 0 {
+		return stat.GetLoad(k)
+	}
+	return math.Round(score / weightSum)
+}
+
 // GetThresholds returns thresholds.
 // Only for test purpose.
 func (stat *HotPeerStat) GetThresholds() []float64 {
@@ -200,6 +271,18 @@ func (stat *HotPeerStat) isHot() bool {
 	})
 }
 
+// isLoadHot checks if any of the loads reaches its threshold.
+func (stat *HotPeerStat) isLoadHot() bool {
+	regionStats := stat.Kind.RegionStats()
+	return slice.AnyOf(regionStats, func(i int) bool {
+		threshold := minHotThresholds[regionStats[i]]
+		if i < len(stat.thresholds) {
+			threshold = stat.thresholds[i]
+		}
+		return stat.GetLoad(regionStats[i]) >= threshold
+	})
+}
+
 func (stat *HotPeerStat) clearLastAverage() {
 	for _, l := range stat.rollingLoads {
 		l.clearLastAverage()
@@ -60,6 +60,7 @@ type hotPeerCache struct {
 	topNTTL            time.Duration
 	reportIntervalSecs int
 	taskQueue          chan flowItemTask
+	windows            hotCacheWindows
 }
 
 // NewHotPeerCache creates a hotPeerCache
@@ -80,15 +81,67 @@ func NewHotPeerCache(kind RWType) *hotPeerCache {
 	return c
 }
 
+// hotCacheWindows is the multi-scale windows to calculate the heat scores of
+// the hot peers.
+type hotCacheWindows struct {
+	durations [WindowLen]time.Duration
+	weights   [WindowLen]float64
+}
+
+func (w hotCacheWindows) enabled() bool {
+	for _, d := range w.durations {
+		if d <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hotCacheExpiry is the expiry policy of the hot peers.
+type hotCacheExpiry struct {
+	ttl       time.Duration
+	decayRate float64
+}
+
+// decay returns a copy of the hot peer with the decayed loads, or nil if the
+// peer is updated within the ttl.
+func (e hotCacheExpiry) decay(stat *HotPeerStat, now time.Time) *HotPeerStat {
+	if e.ttl <= 0 {
+		return nil
+	}
+	expired := now.Sub(stat.LastUpdateTime) - e.ttl
+	if expired <= 0 {
+		return nil
+	}
+	ratio := math.Exp(-e.decayRate * expired.Seconds())
+	ret := stat.Clone()
+	for i := range ret.Loads {
+		ret.Loads[i] *= ratio
+	}
+	return ret
+}
+
 // TODO: rename RegionStats as PeerStats
-// RegionStats returns hot items
-func (f *hotPeerCache) RegionStats(minHotDegree int) map[uint64][]*HotPeerStat {
+// RegionStats returns hot items, the loads of the expired items are decayed,
+// and the items no longer hot after decaying are removed.
+func (f *hotPeerCache) RegionStats(minHotDegree int, expiry hotCacheExpiry) map[uint64][]*HotPeerStat {
 	res := make(map[uint64][]*HotPeerStat)
+	now := time.Now()
 	for storeID, peers := range f.peersOfStore {
 		values := peers.GetAll()
 		stat := make([]*HotPeerStat, 0, len(values))
 		for _, v := range values {
-			if peer := v.(*HotPeerStat); peer.HotDegree >= minHotDegree && !peer.inCold {
+			peer := v.(*HotPeerStat)
+			if decayed := expiry.decay(peer, now); decayed != nil {
+				if !decayed.isLoadHot() {
+					f.removeItem(peer)
+					peer.Log("hot peer expired", log.Debug)
+					incMetrics("expire_item", peer.StoreID, peer.Kind)
+					continue
+				}
+				peer = decayed
+			}
+			if peer.HotDegree >= minHotDegree && !peer.inCold {
 				stat = append(stat, peer)
 			}
 		}
@@ -378,6 +431,7 @@ func (f *hotPeerCache) isRegionHotWithPeer(region *core.RegionInfo, peer *metapb
 
 func (f *hotPeerCache) updateHotPeerStat(region *core.RegionInfo, newItem, oldItem *HotPeerStat, deltaLoads []float64, interval time.Duration) *HotPeerStat {
 	regionStats := f.kind.RegionStats()
+	newItem.windowWeights = f.windows.weights
 	if oldItem == nil {
 		return f.updateNewHotPeerStat(regionStats, newItem, deltaLoads, interval)
 	}
@@ -453,7 +507,7 @@ func (f *hotPeerCache) updateNewHotPeerStat(regionStats []RegionStatKind, newIte
 	newItem.actionType = Add
 	newItem.rollingLoads = make([]*dimStat, len(regionStats))
 	for i, k := range regionStats {
-		ds := newDimStat(k, time.Duration(newItem.hotStatReportInterval())*time.Second)
+		ds := newDimStat(k, time.Duration(newItem.hotStatReportInterval())*time.Second, f.windows)
 		ds.Add(deltaLoads[k], interval)
 		if ds.isFull() {
 			ds.clearLastAverage()
@@ -15,6 +15,7 @@
 package statistics
 
 import (
+	"math"
 	"math/rand"
 	"sort"
 	"testing"
@@ -36,7 +37,7 @@ func (t *testHotPeerCache) TestStoreTimeUnsync(c *C) {
 		region := buildRegion(Write, 3, interval)
 		checkAndUpdate(c, cache, region, 3)
 		{
-			stats := cache.RegionStats(0)
+			stats := cache.RegionStats(0, hotCacheExpiry{})
 			c.Assert(stats, HasLen, 3)
 			for _, s := range stats {
 				c.Assert(s, HasLen, 1)
@@ -581,6 +582,91 @@ func (t *testHotPeerCache) TestCacheInherit(c *C) {
 	}
 }
 
+func (t *testHotPeerCache) TestExpiry(c *C) {
+	cache := NewHotPeerCache(Write)
+	region := buildRegion(Write, 3, 60)
+	checkAndUpdate(c, cache, region, 3)
+	expiry := hotCacheExpiry{ttl: time.Minute, decayRate: 0.01}
+	storeID := region.GetLeader().GetStoreId()
+	item := cache.getOldHotPeerStat(region.GetID(), storeID)
+	load := item.GetLoad(RegionWriteBytes)
+	getStat := func() *HotPeerStat {
+		stats := cache.RegionStats(0, expiry)[storeID]
+		if len(stats) == 0 {
+			return nil
+		}
+		c.Assert(stats, HasLen, 1)
+		return stats[0]
+	}
+
+	// The loads don't decay within the ttl.
+	c.Assert(getStat(), Equals, item)
+
+	// The loads decay after the ttl.
+	item.LastUpdateTime = time.Now().Add(-time.Minute - 100*time.Second)
+	stat := getStat()
+	c.Assert(stat, NotNil)
+	c.Assert(stat.GetLoad(RegionWriteBytes), Less, load/2)
+	c.Assert(stat.GetLoad(RegionWriteBytes), Greater, load/3)
+	c.Assert(item.GetLoad(RegionWriteBytes), Equals, load)
+	c.Assert(cache.RegionStats(0, hotCacheExpiry{})[storeID][0], Equals, item)
+
+	// The item is removed once it's no longer hot.
+	item.LastUpdateTime = time.Now().Add(-time.Hour)
+	c.Assert(getStat(), IsNil)
+	c.Assert(cache.getOldHotPeerStat(region.GetID(), storeID), IsNil)
+	for _, peer := range region.GetPeers() {
+		if peer.GetStoreId() != storeID {
+			c.Assert(cache.getOldHotPeerStat(region.GetID(), peer.GetStoreId()), NotNil)
+		}
+	}
+}
+
+func (t *testHotPeerCache) TestHeatScore(c *C) {
+	cache := NewHotPeerCache(Read)
+	cache.windows = hotCacheWindows{
+		durations: [WindowLen]time.Duration{time.Minute, 5 * time.Minute, time.Hour},
+		weights:   [WindowLen]float64{0.2, 0.3, 0.5},
+	}
+	update := func(item *HotPeerStat, load float64) *HotPeerStat {
+		newItem := &HotPeerStat{actionType: Update, thresholds: []float64{0.0, 0.0, 0.0}, Kind: Read}
+		return cache.updateHotPeerStat(nil, newItem, item, []float64{load * 10, load * 10, load * 10}, 10*time.Second)
+	}
+
+	// A burst lasting for a minute.
+	var item *HotPeerStat
+	for i := 0; i < 6; i++ {
+		item = update(item, 1000)
+	}
+	loads := item.GetWindowLoads(RegionReadBytes)
+	c.Assert(loads, HasLen, WindowLen)
+	c.Assert(loads[ShortWindow], Equals, 1000.0)
+	c.Assert(loads[MediumWindow], Equals, 200.0)
+	c.Assert(math.Round(loads[LongWindow]), Equals, 17.0)
+	burstScore := item.GetHeatScore(RegionReadBytes)
+	c.Assert(burstScore, Equals, math.Round(1000*0.2+200*0.3+1000.0/60*0.5))
+
+	// A sustained hotspot with the same load has a higher score.
+	for i := 0; i < 360; i++ {
+		item = update(item, 1000)
+	}
+	loads = item.GetWindowLoads(RegionReadBytes)
+	for _, load := range loads {
+		c.Assert(math.Round(load), Equals, 1000.0)
+	}
+	c.Assert(item.GetHeatScore(RegionReadBytes), Equals, 1000.0)
+	c.Assert(item.GetHeatScore(RegionReadBytes), Greater, 3*burstScore)
+
+	// The windows are kept when inherited.
+	c.Assert(item.rollingLoads[0].Clone().getWindowLoads(), DeepEquals, item.rollingLoads[0].getWindowLoads())
+
+	// The load is used without the windows.
+	cache.windows = hotCacheWindows{}
+	item = update(nil, 1000)
+	c.Assert(item.GetWindowLoads(RegionReadBytes), IsNil)
+	c.Assert(item.GetHeatScore(RegionReadBytes), Equals, item.GetLoad(RegionReadBytes))
+}
+
 func BenchmarkCheckRegionFlow(b *testing.B) {
 	cache := NewHotPeerCache(Read)
 	region := buildRegion(Read, 3, 10)
@@ -178,6 +178,22 @@ var (
 			Name:      "hot_peers_summary",
 			Help:      "Hot peers summary for each store",
 		}, []string{"type", "store"})
+
+	storeReadAmplificationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "store",
+			Name:      "read_amplification",
+			Help:      "Ratio of the bytes read from the disk to the bytes read by the clients of the store.",
+		}, []string{"store"})
+
+	storeWriteAmplificationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "store",
+			Name:      "write_amplification",
+			Help:      "Ratio of the bytes written to the disk to the bytes written by the clients of the store.",
+		}, []string{"store"})
 )
 
 var (
@@ -205,4 +221,6 @@ func init() {
 	prometheus.MustRegister(regionAbnormalPeerDuration)
 	prometheus.MustRegister(hotCacheFlowQueueStatusGauge)
 	prometheus.MustRegister(hotPeerSummary)
+	prometheus.MustRegister(storeReadAmplificationGauge)
+	prometheus.MustRegister(storeWriteAmplificationGauge)
 }
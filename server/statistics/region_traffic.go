@@ -0,0 +1,160 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tikv/pd/server/core"
+)
+
+// TrafficSample is the traffic of a region reported by a heartbeat.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type TrafficSample struct {
+	// Timestamp is the unix timestamp in seconds at the end of the report interval.
+	Timestamp  int64  `json:"timestamp"`
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+	ReadKeys   uint64 `json:"read_keys"`
+	WriteKeys  uint64 `json:"write_keys"`
+}
+
+// trafficRing keeps the most recent samples of a region. It grows on demand
+// until the capacity, so the regions with few heartbeats take less memory.
+type trafficRing struct {
+	samples []TrafficSample
+	// next is the position to overwrite once the ring is full.
+	next int
+}
+
+func (r *trafficRing) add(sample TrafficSample, capacity int) {
+	if len(r.samples) < capacity {
+		if len(r.samples) == cap(r.samples) {
+			newCap := 2*cap(r.samples) + 1
+			if newCap > capacity {
+				newCap = capacity
+			}
+			samples := make([]TrafficSample, len(r.samples), newCap)
+			copy(samples, r.samples)
+			r.samples = samples
+		}
+		r.samples = append(r.samples, sample)
+		return
+	}
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % capacity
+}
+
+// since returns the samples not before the given timestamp from the oldest to the newest.
+func (r *trafficRing) since(timestamp int64) []TrafficSample {
+	res := make([]TrafficSample, 0, len(r.samples))
+	for i := range r.samples {
+		sample := r.samples[(r.next+i)%len(r.samples)]
+		if sample.Timestamp >= timestamp {
+			res = append(res, sample)
+		}
+	}
+	return res
+}
+
+// RegionTraffic records the traffic of the regions from the heartbeats, at
+// most capacity samples are kept for each region.
+type RegionTraffic struct {
+	sync.RWMutex
+	capacity int
+	regions  map[uint64]*trafficRing
+}
+
+// NewRegionTraffic creates a RegionTraffic which keeps capacity samples for
+// each region, nothing is recorded if the capacity is not positive.
+func NewRegionTraffic(capacity int) *RegionTraffic {
+	return &RegionTraffic{
+		capacity: capacity,
+		regions:  make(map[uint64]*trafficRing),
+	}
+}
+
+// Observe records the traffic reported by the region heartbeat.
+func (t *RegionTraffic) Observe(region *core.RegionInfo) {
+	if t == nil || t.capacity <= 0 {
+		return
+	}
+	timestamp := int64(region.GetInterval().GetEndTimestamp())
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	sample := TrafficSample{
+		Timestamp:  timestamp,
+		ReadBytes:  region.GetBytesRead(),
+		WriteBytes: region.GetBytesWritten(),
+		ReadKeys:   region.GetKeysRead(),
+		WriteKeys:  region.GetKeysWritten(),
+	}
+	t.Lock()
+	defer t.Unlock()
+	ring, ok := t.regions[region.GetID()]
+	if !ok {
+		ring = &trafficRing{}
+		t.regions[region.GetID()] = ring
+	}
+	ring.add(sample, t.capacity)
+}
+
+// Remove removes the samples of the region.
+func (t *RegionTraffic) Remove(regionID uint64) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	delete(t.regions, regionID)
+}
+
+// GetSamples returns the samples of the region not before the given time,
+// from the oldest to the newest.
+func (t *RegionTraffic) GetSamples(regionID uint64, since time.Time) []TrafficSample {
+	if t == nil {
+		return nil
+	}
+	t.RLock()
+	defer t.RUnlock()
+	if ring, ok := t.regions[regionID]; ok {
+		return ring.since(since.Unix())
+	}
+	return nil
+}
+
+// AggregateTraffic sums the samples into the buckets of the resolution, the
+// timestamp of a bucket is its start. The empty buckets are omitted.
+func AggregateTraffic(samples []TrafficSample, resolution time.Duration) []TrafficSample {
+	step := int64(resolution / time.Second)
+	if step <= 1 {
+		return samples
+	}
+	var res []TrafficSample
+	for _, sample := range samples {
+		start := sample.Timestamp - sample.Timestamp%step
+		if len(res) == 0 || res[len(res)-1].Timestamp != start {
+			res = append(res, TrafficSample{Timestamp: start})
+		}
+		bucket := &res[len(res)-1]
+		bucket.ReadBytes += sample.ReadBytes
+		bucket.WriteBytes += sample.WriteBytes
+		bucket.ReadKeys += sample.ReadKeys
+		bucket.WriteKeys += sample.WriteKeys
+	}
+	return res
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/core"
+)
+
+var _ = Suite(&testRegionTrafficSuite{})
+
+type testRegionTrafficSuite struct{}
+
+func newTrafficRegion(id uint64, timestamp int64, bytes uint64) *core.RegionInfo {
+	meta := &metapb.Region{Id: id, Peers: []*metapb.Peer{{Id: id, StoreId: 1}}}
+	return core.NewRegionInfo(meta, meta.Peers[0],
+		core.SetReportInterval(uint64(timestamp)),
+		core.SetReadBytes(bytes),
+		core.SetWrittenBytes(2*bytes),
+		core.SetReadKeys(bytes/10),
+		core.SetWrittenKeys(bytes/5),
+	)
+}
+
+func (t *testRegionTrafficSuite) TestRingBuffer(c *C) {
+	traffic := NewRegionTraffic(3)
+	now := time.Now().Unix()
+	for i := int64(1); i <= 5; i++ {
+		traffic.Observe(newTrafficRegion(1, now-100+i*10, uint64(i)*100))
+		c.Assert(cap(traffic.regions[1].samples), LessEqual, 3)
+	}
+	samples := traffic.GetSamples(1, time.Unix(0, 0))
+	c.Assert(samples, HasLen, 3)
+	for i, sample := range samples {
+		c.Assert(sample.Timestamp, Equals, now-100+int64(i+3)*10)
+		c.Assert(sample.ReadBytes, Equals, uint64(i+3)*100)
+		c.Assert(sample.WriteBytes, Equals, uint64(i+3)*200)
+		c.Assert(sample.ReadKeys, Equals, uint64(i+3)*10)
+		c.Assert(sample.WriteKeys, Equals, uint64(i+3)*20)
+	}
+	samples = traffic.GetSamples(1, time.Unix(now-55, 0))
+	c.Assert(samples, HasLen, 1)
+	c.Assert(samples[0].ReadBytes, Equals, uint64(500))
+	c.Assert(traffic.GetSamples(2, time.Unix(0, 0)), HasLen, 0)
+
+	traffic.Remove(1)
+	c.Assert(traffic.GetSamples(1, time.Unix(0, 0)), HasLen, 0)
+
+	// Nothing is recorded if it is disabled.
+	traffic = NewRegionTraffic(0)
+	traffic.Observe(newTrafficRegion(1, now, 100))
+	c.Assert(traffic.regions, HasLen, 0)
+}
+
+func (t *testRegionTrafficSuite) TestAggregate(c *C) {
+	samples := []TrafficSample{
+		{Timestamp: 100, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+		{Timestamp: 110, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+		{Timestamp: 130, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+		{Timestamp: 190, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+	}
+	c.Assert(AggregateTraffic(samples, 0), DeepEquals, samples)
+	c.Assert(AggregateTraffic(samples, 30*time.Second), DeepEquals, []TrafficSample{
+		{Timestamp: 90, ReadBytes: 2, WriteBytes: 4, ReadKeys: 6, WriteKeys: 8},
+		{Timestamp: 120, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+		{Timestamp: 180, ReadBytes: 1, WriteBytes: 2, ReadKeys: 3, WriteKeys: 4},
+	})
+}
+
+// BenchmarkRegionTrafficMemory reports the memory taken by 1M regions with
+// different numbers of heartbeats. A sample takes 40 bytes, so the full ring
+// buffers of 360 samples take about 14KB for each region.
+func BenchmarkRegionTrafficMemory(b *testing.B) {
+	const regionCount = 1000000
+	regions := make([]*core.RegionInfo, regionCount)
+	now := time.Now().Unix()
+	for i := range regions {
+		regions[i] = newTrafficRegion(uint64(i), now, 100)
+	}
+	for _, heartbeats := range []int{1, 10, 60} {
+		b.Run(fmt.Sprintf("heartbeats-%d", heartbeats), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				traffic := NewRegionTraffic(360)
+				for j := 0; j < heartbeats; j++ {
+					for _, region := range regions {
+						traffic.Observe(region)
+					}
+				}
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/regionCount, "bytes/region")
+				runtime.KeepAlive(traffic)
+			}
+		})
+	}
+}
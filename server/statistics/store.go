@@ -114,6 +114,32 @@ func (s *StoresStats) GetStoresLoads() map[uint64][]float64 {
 	return res
 }
 
+// StoreIO is the rolling average of the IO rates of a store in bytes per second.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreIO struct {
+	StoreID uint64 `json:"store_id"`
+	// DiskReadRate and DiskWriteRate are the IO rates of the disk.
+	DiskReadRate  float64 `json:"disk_read_rate"`
+	DiskWriteRate float64 `json:"disk_write_rate"`
+	// ReadBytesRate and WriteBytesRate are the rates of the bytes read and
+	// written by the clients.
+	ReadBytesRate  float64 `json:"read_bytes_rate"`
+	WriteBytesRate float64 `json:"write_bytes_rate"`
+}
+
+// GetStoreIO returns the IO rates of the store, which are 0 if the store has
+// not reported its statistics yet.
+func (s *StoresStats) GetStoreIO(storeID uint64) *StoreIO {
+	io := &StoreIO{StoreID: storeID}
+	if stats := s.GetRollingStoreStats(storeID); stats != nil {
+		io.DiskReadRate = stats.GetLoad(StoreDiskReadRate)
+		io.DiskWriteRate = stats.GetLoad(StoreDiskWriteRate)
+		io.ReadBytesRate = stats.GetLoad(StoreReadBytes)
+		io.WriteBytesRate = stats.GetLoad(StoreWriteBytes)
+	}
+	return io
+}
+
 // FilterUnhealthyStore filter unhealthy store
 func (s *StoresStats) FilterUnhealthyStore(cluster core.StoreSetInformer) {
 	s.Lock()
@@ -0,0 +1,149 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+// AmplificationSamples is the number of the amplification samples kept for
+// each store.
+const AmplificationSamples = 60
+
+// AmplificationSample is the amplification of a store reported by a heartbeat.
+// The read (write) amplification is the ratio of the bytes read from (written
+// to) the disk to the bytes read (written) by the clients, 0 means there is no
+// client traffic in the interval.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type AmplificationSample struct {
+	// Timestamp is the unix timestamp in seconds at the end of the report interval.
+	Timestamp          int64   `json:"timestamp"`
+	ReadAmplification  float64 `json:"read_amplification"`
+	WriteAmplification float64 `json:"write_amplification"`
+}
+
+// StoreAmplificationInfo is the current and the recent amplification of a store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreAmplificationInfo struct {
+	StoreID uint64               `json:"store_id"`
+	Current *AmplificationSample `json:"current,omitempty"`
+	// History is from the oldest to the newest, including the current one.
+	History []AmplificationSample `json:"history"`
+}
+
+// amplificationRing keeps the most recent AmplificationSamples samples.
+type amplificationRing struct {
+	samples [AmplificationSamples]AmplificationSample
+	count   int
+	// next is the position to write the next sample.
+	next int
+}
+
+func (r *amplificationRing) add(sample AmplificationSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % AmplificationSamples
+	if r.count < AmplificationSamples {
+		r.count++
+	}
+}
+
+// history returns the samples from the oldest to the newest.
+func (r *amplificationRing) history() []AmplificationSample {
+	res := make([]AmplificationSample, 0, r.count)
+	start := (r.next - r.count + AmplificationSamples) % AmplificationSamples
+	for i := 0; i < r.count; i++ {
+		res = append(res, r.samples[(start+i)%AmplificationSamples])
+	}
+	return res
+}
+
+// StoreAmplification records the read and write amplification of the stores
+// from the store heartbeats.
+type StoreAmplification struct {
+	sync.RWMutex
+	stores map[uint64]*amplificationRing
+}
+
+// NewStoreAmplification creates a StoreAmplification.
+func NewStoreAmplification() *StoreAmplification {
+	return &StoreAmplification{stores: make(map[uint64]*amplificationRing)}
+}
+
+// Observe records the amplification reported by the store heartbeat. The disk
+// bytes are estimated by the IO rates of the threads over the report interval.
+func (a *StoreAmplification) Observe(stats *pdpb.StoreStats) {
+	interval := stats.GetInterval()
+	seconds := interval.GetEndTimestamp() - interval.GetStartTimestamp()
+	if seconds == 0 {
+		return
+	}
+	sample := AmplificationSample{
+		Timestamp:          int64(interval.GetEndTimestamp()),
+		ReadAmplification:  amplification(stats.GetReadIoRates(), seconds, stats.GetBytesRead()),
+		WriteAmplification: amplification(stats.GetWriteIoRates(), seconds, stats.GetBytesWritten()),
+	}
+	storeID := stats.GetStoreId()
+	storeLabel := strconv.FormatUint(storeID, 10)
+	storeReadAmplificationGauge.WithLabelValues(storeLabel).Set(sample.ReadAmplification)
+	storeWriteAmplificationGauge.WithLabelValues(storeLabel).Set(sample.WriteAmplification)
+
+	a.Lock()
+	defer a.Unlock()
+	ring, ok := a.stores[storeID]
+	if !ok {
+		ring = &amplificationRing{}
+		a.stores[storeID] = ring
+	}
+	ring.add(sample)
+}
+
+func amplification(diskRates []*pdpb.RecordPair, seconds uint64, clientBytes uint64) float64 {
+	if clientBytes == 0 {
+		return 0
+	}
+	var rate uint64
+	for _, pair := range diskRates {
+		rate += pair.GetValue()
+	}
+	return float64(rate*seconds) / float64(clientBytes)
+}
+
+// Remove removes the samples and the metrics of the store.
+func (a *StoreAmplification) Remove(storeID uint64) {
+	storeLabel := strconv.FormatUint(storeID, 10)
+	storeReadAmplificationGauge.DeleteLabelValues(storeLabel)
+	storeWriteAmplificationGauge.DeleteLabelValues(storeLabel)
+	a.Lock()
+	defer a.Unlock()
+	delete(a.stores, storeID)
+}
+
+// GetAmplification returns the amplification of the store.
+func (a *StoreAmplification) GetAmplification(storeID uint64) *StoreAmplificationInfo {
+	info := &StoreAmplificationInfo{StoreID: storeID, History: []AmplificationSample{}}
+	a.RLock()
+	defer a.RUnlock()
+	if ring, ok := a.stores[storeID]; ok {
+		info.History = ring.history()
+		if len(info.History) > 0 {
+			current := info.History[len(info.History)-1]
+			info.Current = &current
+		}
+	}
+	return info
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Suite(&testStoreAmplificationSuite{})
+
+type testStoreAmplificationSuite struct{}
+
+func newAmplificationStats(storeID uint64, end uint64, diskRead, bytesRead, diskWrite, bytesWritten uint64) *pdpb.StoreStats {
+	return &pdpb.StoreStats{
+		StoreId:      storeID,
+		Interval:     &pdpb.TimeInterval{StartTimestamp: end - 10, EndTimestamp: end},
+		ReadIoRates:  []*pdpb.RecordPair{{Key: "a", Value: diskRead / 10 / 2}, {Key: "b", Value: diskRead / 10 / 2}},
+		BytesRead:    bytesRead,
+		WriteIoRates: []*pdpb.RecordPair{{Key: "a", Value: diskWrite / 10}},
+		BytesWritten: bytesWritten,
+	}
+}
+
+func (s *testStoreAmplificationSuite) TestObserve(c *C) {
+	a := NewStoreAmplification()
+	a.Observe(newAmplificationStats(1, 100, 3000, 1000, 5000, 500))
+	info := a.GetAmplification(1)
+	c.Assert(info.Current, NotNil)
+	c.Assert(*info.Current, Equals, AmplificationSample{Timestamp: 100, ReadAmplification: 3, WriteAmplification: 10})
+	c.Assert(info.History, HasLen, 1)
+	c.Assert(testutil.ToFloat64(storeReadAmplificationGauge.WithLabelValues("1")), Equals, 3.0)
+	c.Assert(testutil.ToFloat64(storeWriteAmplificationGauge.WithLabelValues("1")), Equals, 10.0)
+
+	// No client traffic.
+	a.Observe(newAmplificationStats(1, 110, 3000, 0, 0, 0))
+	c.Assert(*a.GetAmplification(1).Current, Equals, AmplificationSample{Timestamp: 110})
+	c.Assert(testutil.ToFloat64(storeReadAmplificationGauge.WithLabelValues("1")), Equals, 0.0)
+	// The heartbeat without the interval is ignored.
+	a.Observe(&pdpb.StoreStats{StoreId: 1, BytesRead: 100})
+	c.Assert(a.GetAmplification(1).History, HasLen, 2)
+
+	info = a.GetAmplification(2)
+	c.Assert(info.Current, IsNil)
+	c.Assert(info.History, HasLen, 0)
+
+	a.Remove(1)
+	c.Assert(a.GetAmplification(1).History, HasLen, 0)
+}
+
+func (s *testStoreAmplificationSuite) TestHistory(c *C) {
+	a := NewStoreAmplification()
+	for i := uint64(1); i <= AmplificationSamples+10; i++ {
+		a.Observe(newAmplificationStats(1, i*10, i*1000, 1000, 0, 0))
+	}
+	info := a.GetAmplification(1)
+	c.Assert(info.History, HasLen, AmplificationSamples)
+	for i, sample := range info.History {
+		c.Assert(sample.Timestamp, Equals, int64(i+11)*10)
+		c.Assert(sample.ReadAmplification, Equals, float64(i+11))
+	}
+	c.Assert(*info.Current, Equals, info.History[AmplificationSamples-1])
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+)
+
+const (
+	// maxHealthScore is the score of a critically ill store.
+	maxHealthScore = 100
+	// degradedHealthScore and criticalHealthScore are the lower bounds of
+	// the overall score of the degraded and critical stores.
+	degradedHealthScore = 30
+	criticalHealthScore = 70
+
+	// StoreHealthy, StoreDegraded and StoreCritical are the health status of a store.
+	StoreHealthy  = "healthy"
+	StoreDegraded = "degraded"
+	StoreCritical = "critical"
+)
+
+// StoreHealthScore is the health score of a store aggregated from its
+// components, 0 means healthy and 100 means critically ill.
+type StoreHealthScore struct {
+	StoreID      uint64             `json:"store_id"`
+	OverallScore float64            `json:"overall_score"`
+	Components   map[string]float64 `json:"components"`
+	Status       string             `json:"status"`
+}
+
+// HealthScorer scores a health signal of a store.
+type HealthScorer interface {
+	// Name returns the component name of the score.
+	Name() string
+	// Score returns the score of the store between 0 (healthy) and 100
+	// (critically ill), stores are all the stores of the cluster.
+	Score(store *core.StoreInfo, stores []*core.StoreInfo) float64
+}
+
+// NewHealthScorers creates the default scorers of the store health.
+func NewHealthScorers(opt *config.PersistOptions) []HealthScorer {
+	return []HealthScorer{
+		&diskScorer{},
+		&cpuScorer{},
+		&networkScorer{opt: opt},
+		&latencyScorer{opt: opt},
+		&leaderCountScorer{},
+	}
+}
+
+// CalcStoreHealthScore scores the store by the scorers, the overall score is
+// the worst score of the components.
+func CalcStoreHealthScore(store *core.StoreInfo, stores []*core.StoreInfo, scorers []HealthScorer) *StoreHealthScore {
+	health := &StoreHealthScore{
+		StoreID:    store.GetID(),
+		Components: make(map[string]float64, len(scorers)),
+	}
+	for _, scorer := range scorers {
+		score := clampHealthScore(scorer.Score(store, stores))
+		health.Components[scorer.Name()] = score
+		health.OverallScore = math.Max(health.OverallScore, score)
+	}
+	switch {
+	case health.OverallScore >= criticalHealthScore:
+		health.Status = StoreCritical
+	case health.OverallScore >= degradedHealthScore:
+		health.Status = StoreDegraded
+	default:
+		health.Status = StoreHealthy
+	}
+	return health
+}
+
+func clampHealthScore(score float64) float64 {
+	if math.IsNaN(score) || score < 0 {
+		return 0
+	}
+	return math.Min(score, maxHealthScore)
+}
+
+// diskScorer scores the used ratio of the disk.
+type diskScorer struct{}
+
+func (s *diskScorer) Name() string {
+	return "disk"
+}
+
+func (s *diskScorer) Score(store *core.StoreInfo, _ []*core.StoreInfo) float64 {
+	if store.GetCapacity() == 0 {
+		return 0
+	}
+	return (1 - store.AvailableRatio()) * maxHealthScore
+}
+
+// cpuScorer scores the usage of the busiest thread of the store.
+type cpuScorer struct{}
+
+func (s *cpuScorer) Name() string {
+	return "cpu"
+}
+
+func (s *cpuScorer) Score(store *core.StoreInfo, _ []*core.StoreInfo) float64 {
+	var usage uint64
+	for _, pair := range store.GetStoreStats().GetCpuUsages() {
+		if pair.GetValue() > usage {
+			usage = pair.GetValue()
+		}
+	}
+	return float64(usage)
+}
+
+// networkScorer scores the delay of the heartbeats of the store, the store
+// reaching max-store-down-time is critically ill.
+type networkScorer struct {
+	opt *config.PersistOptions
+}
+
+func (s *networkScorer) Name() string {
+	return "network"
+}
+
+func (s *networkScorer) Score(store *core.StoreInfo, _ []*core.StoreInfo) float64 {
+	maxDownTime := s.opt.GetMaxStoreDownTime()
+	if maxDownTime <= 0 {
+		return 0
+	}
+	return float64(store.DownTime()) / float64(maxDownTime) * maxHealthScore
+}
+
+// latencyScorer scores the slow score and the p99 latency of the store, the
+// store whose latency reaches the thresholds is degraded.
+type latencyScorer struct {
+	opt *config.PersistOptions
+}
+
+func (s *latencyScorer) Name() string {
+	return "latency"
+}
+
+func (s *latencyScorer) Score(store *core.StoreInfo, _ []*core.StoreInfo) float64 {
+	// The slow score starts from 1.
+	score := float64(store.GetSlowScore()) - 1
+	if threshold := s.opt.GetSlowStoreReadLatencyThresholdUs(); threshold > 0 {
+		score = math.Max(score, float64(store.GetP99ReadLatencyUs())/float64(threshold)*maxHealthScore/2)
+	}
+	if threshold := s.opt.GetSlowStoreWriteLatencyThresholdUs(); threshold > 0 {
+		score = math.Max(score, float64(store.GetP99WriteLatencyUs())/float64(threshold)*maxHealthScore/2)
+	}
+	return score
+}
+
+// leaderCountScorer scores how much the leader count of the store exceeds
+// the average of the stores not removed, the store having twice the average is
+// critically ill.
+type leaderCountScorer struct{}
+
+func (s *leaderCountScorer) Name() string {
+	return "leader_count"
+}
+
+func (s *leaderCountScorer) Score(store *core.StoreInfo, stores []*core.StoreInfo) float64 {
+	var total, count int
+	for _, other := range stores {
+		if !other.IsRemoved() {
+			total += other.GetLeaderCount()
+			count++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	avg := float64(total) / float64(count)
+	return (float64(store.GetLeaderCount()) - avg) / avg * maxHealthScore
+}
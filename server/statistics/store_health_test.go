@@ -0,0 +1,112 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+)
+
+var _ = Suite(&testStoreHealthSuite{})
+
+type testStoreHealthSuite struct{}
+
+func (t *testStoreHealthSuite) newStore(id uint64, stats *pdpb.StoreStats, opts ...core.StoreCreateOption) *core.StoreInfo {
+	stats.StoreId = id
+	opts = append([]core.StoreCreateOption{core.SetLastHeartbeatTS(time.Now()), core.SetStoreStats(stats)}, opts...)
+	return core.NewStoreInfo(&metapb.Store{Id: id, Address: "mock://tikv"}, opts...)
+}
+
+func (t *testStoreHealthSuite) TestStoreHealthScore(c *C) {
+	opt := config.NewTestOptions()
+	opt.GetScheduleConfig().SlowStoreReadLatencyThresholdUs = 1000
+	opt.GetScheduleConfig().SlowStoreWriteLatencyThresholdUs = 1000
+	scorers := NewHealthScorers(opt)
+	healthy := &pdpb.StoreStats{Capacity: 100, Available: 100, SlowScore: 1}
+
+	checkComponent := func(store *core.StoreInfo, stores []*core.StoreInfo, component string, score float64, status string) {
+		health := CalcStoreHealthScore(store, stores, scorers)
+		c.Assert(health.StoreID, Equals, store.GetID())
+		c.Assert(health.Components, HasLen, len(scorers))
+		for name, s := range health.Components {
+			if name == component {
+				c.Assert(s, Equals, score)
+			} else {
+				c.Assert(s < degradedHealthScore, IsTrue, Commentf("component %s, score %f", name, s))
+			}
+		}
+		// The heartbeat delay contributes a tiny network score.
+		c.Assert(math.Abs(health.OverallScore-score) < 1e-3, IsTrue)
+		c.Assert(health.Status, Equals, status)
+	}
+
+	// disk
+	store := t.newStore(1, &pdpb.StoreStats{Capacity: 100, Available: 10, SlowScore: 1})
+	checkComponent(store, []*core.StoreInfo{store}, "disk", 90, StoreCritical)
+
+	// cpu
+	store = t.newStore(1, &pdpb.StoreStats{Capacity: 100, Available: 90, SlowScore: 1,
+		CpuUsages: []*pdpb.RecordPair{{Key: "raftstore", Value: 50}, {Key: "apply", Value: 20}}})
+	checkComponent(store, []*core.StoreInfo{store}, "cpu", 50, StoreDegraded)
+
+	// network
+	store = t.newStore(1, healthy, core.SetLastHeartbeatTS(time.Now().Add(-opt.GetMaxStoreDownTime())))
+	checkComponent(store, []*core.StoreInfo{store}, "network", 100, StoreCritical)
+
+	// latency
+	store = t.newStore(1, &pdpb.StoreStats{Capacity: 100, Available: 90, SlowScore: 41})
+	checkComponent(store, []*core.StoreInfo{store}, "latency", 40, StoreDegraded)
+	store = t.newStore(1, &pdpb.StoreStats{Capacity: 100, Available: 90, SlowScore: 1,
+		OpLatencies: []*pdpb.RecordPair{{Key: core.P99WriteLatencyKey, Value: 1500}}})
+	checkComponent(store, []*core.StoreInfo{store}, "latency", 75, StoreCritical)
+
+	// leader_count
+	stores := []*core.StoreInfo{
+		t.newStore(1, healthy, core.SetLeaderCount(200)),
+		t.newStore(2, healthy, core.SetLeaderCount(50)),
+		t.newStore(3, healthy, core.SetLeaderCount(50)),
+		t.newStore(4, healthy, core.SetLeaderCount(1000), core.TombstoneStore()),
+	}
+	checkComponent(stores[0], stores, "leader_count", 100, StoreCritical)
+	checkComponent(stores[1], stores, "leader_count", 0, StoreHealthy)
+}
+
+type constScorer struct {
+	score float64
+}
+
+func (s *constScorer) Name() string {
+	return "const"
+}
+
+func (s *constScorer) Score(*core.StoreInfo, []*core.StoreInfo) float64 {
+	return s.score
+}
+
+func (t *testStoreHealthSuite) TestCustomHealthScorer(c *C) {
+	store := t.newStore(1, &pdpb.StoreStats{})
+	health := CalcStoreHealthScore(store, nil, []HealthScorer{&constScorer{score: 200}})
+	c.Assert(health.Components["const"], Equals, float64(100))
+	c.Assert(health.Status, Equals, StoreCritical)
+	health = CalcStoreHealthScore(store, nil, []HealthScorer{&constScorer{score: -1}})
+	c.Assert(health.OverallScore, Equals, float64(0))
+	c.Assert(health.Status, Equals, StoreHealthy)
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// APICredential is the credential of a HTTP API user, the plain password is
+// never stored.
+type APICredential struct {
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	PasswordHash string `json:"password-hash"`
+}
+
+// APICredentialStorage defines the storage operations on the credentials of
+// the HTTP API users.
+type APICredentialStorage interface {
+	LoadAPICredential(username string) (*APICredential, error)
+	SaveAPICredential(credential *APICredential) error
+	RemoveAPICredential(username string) error
+}
+
+var _ APICredentialStorage = (*StorageEndpoint)(nil)
+
+// LoadAPICredential loads the credential of the user, it returns nil if the
+// user does not exist.
+func (se *StorageEndpoint) LoadAPICredential(username string) (*APICredential, error) {
+	v, err := se.Load(apiCredentialKeyPath(username))
+	if err != nil || v == "" {
+		return nil, err
+	}
+	credential := &APICredential{}
+	if err := json.Unmarshal([]byte(v), credential); err != nil {
+		return nil, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return credential, nil
+}
+
+// SaveAPICredential stores the credential of the user.
+func (se *StorageEndpoint) SaveAPICredential(credential *APICredential) error {
+	value, err := json.Marshal(credential)
+	if err != nil {
+		return errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return se.Save(apiCredentialKeyPath(credential.Username), string(value))
+}
+
+// RemoveAPICredential removes the credential of the user.
+func (se *StorageEndpoint) RemoveAPICredential(username string) error {
+	return se.Remove(apiCredentialKeyPath(username))
+}
@@ -29,6 +29,9 @@ type ConfigStorage interface {
 	LoadAllScheduleConfig() ([]string, []string, error)
 	SaveScheduleConfig(scheduleName string, data []byte) error
 	RemoveScheduleConfig(scheduleName string) error
+	LoadScheduleTimeWindow(scheduleName string) (string, error)
+	SaveScheduleTimeWindow(scheduleName string, data []byte) error
+	RemoveScheduleTimeWindow(scheduleName string) error
 }
 
 var _ ConfigStorage = (*StorageEndpoint)(nil)
@@ -74,3 +77,18 @@ func (se *StorageEndpoint) SaveScheduleConfig(scheduleName string, data []byte)
 func (se *StorageEndpoint) RemoveScheduleConfig(scheduleName string) error {
 	return se.Remove(scheduleConfigPath(scheduleName))
 }
+
+// LoadScheduleTimeWindow loads the time window of scheduler.
+func (se *StorageEndpoint) LoadScheduleTimeWindow(scheduleName string) (string, error) {
+	return se.Load(scheduleTimeWindowKeyPath(scheduleName))
+}
+
+// SaveScheduleTimeWindow saves the time window of scheduler.
+func (se *StorageEndpoint) SaveScheduleTimeWindow(scheduleName string, data []byte) error {
+	return se.Save(scheduleTimeWindowKeyPath(scheduleName), string(data))
+}
+
+// RemoveScheduleTimeWindow removes the time window of scheduler.
+func (se *StorageEndpoint) RemoveScheduleTimeWindow(scheduleName string) error {
+	return se.Remove(scheduleTimeWindowKeyPath(scheduleName))
+}
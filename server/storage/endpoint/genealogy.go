@@ -0,0 +1,41 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import "fmt"
+
+// GenealogyStorage defines the storage operations on the region genealogy.
+type GenealogyStorage interface {
+	LoadGenealogy(f func(k, v string)) error
+	SaveGenealogy(seq uint64, entry interface{}) error
+	DeleteGenealogy(seq uint64) error
+}
+
+var _ GenealogyStorage = (*StorageEndpoint)(nil)
+
+// LoadGenealogy loads all the region genealogy entries in the order of the sequence.
+func (se *StorageEndpoint) LoadGenealogy(f func(k, v string)) error {
+	return se.loadRangeByPrefix(regionGenealogyPath+"/", f)
+}
+
+// SaveGenealogy saves a region genealogy entry.
+func (se *StorageEndpoint) SaveGenealogy(seq uint64, entry interface{}) error {
+	return se.saveJSON(regionGenealogyPath, fmt.Sprintf("%020d", seq), entry)
+}
+
+// DeleteGenealogy removes a region genealogy entry.
+func (se *StorageEndpoint) DeleteGenealogy(seq uint64) error {
+	return se.Remove(regionGenealogyKeyPath(seq))
+}
@@ -27,10 +27,16 @@ const (
 	rulesPath                  = "rules"
 	ruleGroupPath              = "rule_group"
 	regionLabelPath            = "region_label"
+	regionGenealogyPath        = "region_genealogy"
 	replicationPath            = "replication_mode"
 	customScheduleConfigPath   = "scheduler_config"
+	scheduleTimeWindowPath     = "scheduler_time_window"
 	gcWorkerServiceSafePointID = "gc_worker"
 	minResolvedTS              = "min_resolved_ts"
+	apiCredentialPath          = "api_credential"
+	regionQuotaPath            = "region_quota"
+	keyspacePath               = "keyspaces"
+	keyspaceAffinityPath       = "keyspace_affinity"
 )
 
 // AppendToRootPath appends the given key to the rootPath.
@@ -52,6 +58,10 @@ func scheduleConfigPath(scheduleName string) string {
 	return path.Join(customScheduleConfigPath, scheduleName)
 }
 
+func scheduleTimeWindowKeyPath(scheduleName string) string {
+	return path.Join(scheduleTimeWindowPath, scheduleName)
+}
+
 // StorePath returns the store meta info key path with the given store ID.
 func StorePath(storeID uint64) string {
 	return path.Join(clusterPath, "s", fmt.Sprintf("%020d", storeID))
@@ -65,6 +75,10 @@ func storeRegionWeightPath(storeID uint64) string {
 	return path.Join(schedulePath, "store_weight", fmt.Sprintf("%020d", storeID), "region")
 }
 
+func storeDrainPath(storeID uint64) string {
+	return path.Join(schedulePath, "store_drain", fmt.Sprintf("%020d", storeID))
+}
+
 // RegionPath returns the region meta info key path with the given region ID.
 func RegionPath(regionID uint64) string {
 	return path.Join(clusterPath, "r", fmt.Sprintf("%020d", regionID))
@@ -82,6 +96,10 @@ func regionLabelKeyPath(ruleKey string) string {
 	return path.Join(regionLabelPath, ruleKey)
 }
 
+func regionGenealogyKeyPath(seq uint64) string {
+	return path.Join(regionGenealogyPath, fmt.Sprintf("%020d", seq))
+}
+
 func replicationModePath(mode string) string {
 	return path.Join(replicationPath, mode)
 }
@@ -103,3 +121,23 @@ func gcSafePointServicePath(serviceID string) string {
 func MinResolvedTSPath() string {
 	return path.Join(clusterPath, minResolvedTS)
 }
+
+func apiCredentialKeyPath(username string) string {
+	return path.Join(apiCredentialPath, username)
+}
+
+func regionQuotaKeyPath(id string) string {
+	return path.Join(regionQuotaPath, id)
+}
+
+func keyspaceMetaPath(id uint32) string {
+	return path.Join(keyspacePath, fmt.Sprintf("%08d", id))
+}
+
+func keyspaceAffinityKeyPath(id uint32) string {
+	return path.Join(keyspaceAffinityPath, fmt.Sprintf("%08d", id))
+}
+
+func keyspaceGCSafePointPath(id uint32) string {
+	return path.Join(gcPath, "keyspace_safe_point", fmt.Sprintf("%08d", id))
+}
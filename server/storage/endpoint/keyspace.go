@@ -0,0 +1,139 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/storage/kv"
+)
+
+// KeyspaceMeta is the metadata of a keyspace.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type KeyspaceMeta struct {
+	ID        uint32            `json:"id"`
+	Name      string            `json:"name"`
+	State     string            `json:"state"`
+	Config    map[string]string `json:"config"`
+	CreatedAt int64             `json:"created_at"`
+	// StartKey and EndKey are the hex-encoded key range routed to the keyspace.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// TTLSeconds is the time to live of the data in the keyspace, the data is
+	// kept forever if it is 0.
+	TTLSeconds uint64 `json:"ttl_seconds,omitempty"`
+}
+
+// KeyspaceAffinity restricts the stores serving the regions of a keyspace to
+// the ones with the required labels.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type KeyspaceAffinity struct {
+	KeyspaceID          uint32            `json:"keyspace_id"`
+	RequiredStoreLabels map[string]string `json:"required_store_labels"`
+}
+
+// KeyspaceStorage defines the storage operations on the keyspace metadata.
+type KeyspaceStorage interface {
+	LoadKeyspace(id uint32) (*KeyspaceMeta, error)
+	LoadKeyspaces(f func(k, v string)) error
+	SaveKeyspace(meta *KeyspaceMeta) error
+	RunInTxn(f func(txn kv.Txn) error) error
+	LoadKeyspaceAffinities(f func(k, v string)) error
+	SaveKeyspaceAffinity(affinity *KeyspaceAffinity) error
+	RemoveKeyspaceAffinity(id uint32) error
+	LoadKeyspaceGCSafePoint(id uint32) (uint64, error)
+	SaveKeyspaceGCSafePoint(id uint32, safePoint uint64) error
+}
+
+var _ KeyspaceStorage = (*StorageEndpoint)(nil)
+
+// LoadKeyspace loads the metadata of the keyspace, it returns nil if the
+// keyspace does not exist.
+func (se *StorageEndpoint) LoadKeyspace(id uint32) (*KeyspaceMeta, error) {
+	return LoadKeyspaceInTxn(se, id)
+}
+
+// LoadKeyspaceInTxn loads the metadata of the keyspace in the transaction, it
+// returns nil if the keyspace does not exist.
+func LoadKeyspaceInTxn(txn kv.Txn, id uint32) (*KeyspaceMeta, error) {
+	v, err := txn.Load(keyspaceMetaPath(id))
+	if err != nil || v == "" {
+		return nil, err
+	}
+	meta := &KeyspaceMeta{}
+	if err := json.Unmarshal([]byte(v), meta); err != nil {
+		return nil, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return meta, nil
+}
+
+// LoadKeyspaces loads the metadata of all the keyspaces in the order of the ID.
+func (se *StorageEndpoint) LoadKeyspaces(f func(k, v string)) error {
+	return se.loadRangeByPrefix(keyspacePath+"/", f)
+}
+
+// SaveKeyspace stores the metadata of the keyspace.
+func (se *StorageEndpoint) SaveKeyspace(meta *KeyspaceMeta) error {
+	return SaveKeyspaceInTxn(se, meta)
+}
+
+// SaveKeyspaceInTxn stores the metadata of the keyspace in the transaction.
+func SaveKeyspaceInTxn(txn kv.Txn, meta *KeyspaceMeta) error {
+	value, err := json.Marshal(meta)
+	if err != nil {
+		return errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return txn.Save(keyspaceMetaPath(meta.ID), string(value))
+}
+
+// LoadKeyspaceAffinities loads the affinities of all the keyspaces.
+func (se *StorageEndpoint) LoadKeyspaceAffinities(f func(k, v string)) error {
+	return se.loadRangeByPrefix(keyspaceAffinityPath+"/", f)
+}
+
+// SaveKeyspaceAffinity stores the affinity of the keyspace.
+func (se *StorageEndpoint) SaveKeyspaceAffinity(affinity *KeyspaceAffinity) error {
+	value, err := json.Marshal(affinity)
+	if err != nil {
+		return errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return se.Save(keyspaceAffinityKeyPath(affinity.KeyspaceID), string(value))
+}
+
+// RemoveKeyspaceAffinity removes the affinity of the keyspace.
+func (se *StorageEndpoint) RemoveKeyspaceAffinity(id uint32) error {
+	return se.Remove(keyspaceAffinityKeyPath(id))
+}
+
+// LoadKeyspaceGCSafePoint loads the GC safe point of the keyspace, it is 0 if
+// the keyspace has no safe point.
+func (se *StorageEndpoint) LoadKeyspaceGCSafePoint(id uint32) (uint64, error) {
+	value, err := se.Load(keyspaceGCSafePointPath(id))
+	if err != nil || value == "" {
+		return 0, err
+	}
+	safePoint, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, errs.ErrStrconvParseUint.Wrap(err).GenWithStackByArgs()
+	}
+	return safePoint, nil
+}
+
+// SaveKeyspaceGCSafePoint stores the GC safe point of the keyspace.
+func (se *StorageEndpoint) SaveKeyspaceGCSafePoint(id uint32, safePoint uint64) error {
+	return se.Save(keyspaceGCSafePointPath(id), strconv.FormatUint(safePoint, 16))
+}
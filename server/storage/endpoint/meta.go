@@ -35,6 +35,8 @@ type MetaStorage interface {
 	LoadStore(storeID uint64, store *metapb.Store) (bool, error)
 	SaveStore(store *metapb.Store) error
 	SaveStoreWeight(storeID uint64, leader, region float64) error
+	SaveStoreDrainStartTime(storeID uint64, startTime time.Time) error
+	DeleteStoreDrainStartTime(storeID uint64) error
 	LoadStores(f func(store *core.StoreInfo)) error
 	DeleteStore(store *metapb.Store) error
 	RegionStorage
@@ -92,6 +94,16 @@ func (se *StorageEndpoint) SaveStoreWeight(storeID uint64, leader, region float6
 	return se.Save(storeRegionWeightPath(storeID), regionValue)
 }
 
+// SaveStoreDrainStartTime saves the time when a store starts draining to storage.
+func (se *StorageEndpoint) SaveStoreDrainStartTime(storeID uint64, startTime time.Time) error {
+	return se.Save(storeDrainPath(storeID), strconv.FormatInt(startTime.Unix(), 10))
+}
+
+// DeleteStoreDrainStartTime deletes the draining state of a store from storage.
+func (se *StorageEndpoint) DeleteStoreDrainStartTime(storeID uint64) error {
+	return se.Remove(storeDrainPath(storeID))
+}
+
 // LoadStores loads all stores from storage to StoresInfo.
 func (se *StorageEndpoint) LoadStores(f func(store *core.StoreInfo)) error {
 	nextID := uint64(0)
@@ -115,7 +127,12 @@ func (se *StorageEndpoint) LoadStores(f func(store *core.StoreInfo)) error {
 			if err != nil {
 				return err
 			}
-			newStoreInfo := core.NewStoreInfo(store, core.SetLeaderWeight(leaderWeight), core.SetRegionWeight(regionWeight))
+			drainStartTime, err := se.loadStoreDrainStartTime(store.GetId())
+			if err != nil {
+				return err
+			}
+			newStoreInfo := core.NewStoreInfo(store, core.SetLeaderWeight(leaderWeight), core.SetRegionWeight(regionWeight),
+				core.SetDrainStartTime(drainStartTime))
 
 			nextID = store.GetId() + 1
 			f(newStoreInfo)
@@ -141,6 +158,18 @@ func (se *StorageEndpoint) loadFloatWithDefaultValue(path string, def float64) (
 	return val, nil
 }
 
+func (se *StorageEndpoint) loadStoreDrainStartTime(storeID uint64) (time.Time, error) {
+	res, err := se.Load(storeDrainPath(storeID))
+	if err != nil || res == "" {
+		return time.Time{}, err
+	}
+	val, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return time.Time{}, errs.ErrStrconvParseInt.Wrap(err).GenWithStackByArgs()
+	}
+	return time.Unix(val, 0), nil
+}
+
 // DeleteStore deletes one store from storage.
 func (se *StorageEndpoint) DeleteStore(store *metapb.Store) error {
 	return se.Remove(StorePath(store.GetId()))
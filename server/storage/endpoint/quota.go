@@ -0,0 +1,39 @@
+// Copyright 2022 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+// RegionQuotaStorage defines the storage operations on the region quotas.
+type RegionQuotaStorage interface {
+	LoadRegionQuotas(f func(k, v string)) error
+	SaveRegionQuota(id string, quota interface{}) error
+	DeleteRegionQuota(id string) error
+}
+
+var _ RegionQuotaStorage = (*StorageEndpoint)(nil)
+
+// LoadRegionQuotas loads all the region quotas from storage.
+func (se *StorageEndpoint) LoadRegionQuotas(f func(k, v string)) error {
+	return se.loadRangeByPrefix(regionQuotaPath+"/", f)
+}
+
+// SaveRegionQuota saves a region quota to storage.
+func (se *StorageEndpoint) SaveRegionQuota(id string, quota interface{}) error {
+	return se.saveJSON(regionQuotaPath, id, quota)
+}
+
+// DeleteRegionQuota removes a region quota from storage.
+func (se *StorageEndpoint) DeleteRegionQuota(id string) error {
+	return se.Remove(regionQuotaKeyPath(id))
+}
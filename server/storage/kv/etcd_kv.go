@@ -26,6 +26,7 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/etcdutil"
 	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/clientv3/concurrency"
 	"go.uber.org/zap"
 )
 
@@ -119,6 +120,36 @@ func (kv *etcdKVBase) Remove(key string) error {
 	return nil
 }
 
+// RunInTxn runs f in an etcd software transactional memory, the keys loaded
+// in f are checked to be unchanged when the writes are committed.
+func (kv *etcdKVBase) RunInTxn(f func(txn Txn) error) error {
+	ctx, cancel := context.WithTimeout(kv.client.Ctx(), requestTimeout)
+	defer cancel()
+	_, err := concurrency.NewSTM(kv.client, func(stm concurrency.STM) error {
+		return f(&etcdTxn{stm: stm, rootPath: kv.rootPath})
+	}, concurrency.WithAbortContext(ctx))
+	return err
+}
+
+type etcdTxn struct {
+	stm      concurrency.STM
+	rootPath string
+}
+
+func (t *etcdTxn) Load(key string) (string, error) {
+	return t.stm.Get(path.Join(t.rootPath, key)), nil
+}
+
+func (t *etcdTxn) Save(key, value string) error {
+	t.stm.Put(path.Join(t.rootPath, key), value)
+	return nil
+}
+
+func (t *etcdTxn) Remove(key string) error {
+	t.stm.Del(path.Join(t.rootPath, key))
+	return nil
+}
+
 // SlowLogTxn wraps etcd transaction and log slow one.
 type SlowLogTxn struct {
 	clientv3.Txn
@@ -20,4 +20,14 @@ type Base interface {
 	LoadRange(key, endKey string, limit int) (keys []string, values []string, err error)
 	Save(key, value string) error
 	Remove(key string) error
+	// RunInTxn runs f in a transaction, the writes in f are applied
+	// atomically if f returns nil, and discarded otherwise.
+	RunInTxn(f func(txn Txn) error) error
+}
+
+// Txn is the key-value operations in a transaction.
+type Txn interface {
+	Load(key string) (string, error)
+	Save(key, value string) error
+	Remove(key string) error
 }
@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/tempurl"
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/embed"
@@ -54,6 +55,7 @@ func (s *testKVSuite) TestEtcd(c *C) {
 	kv := NewEtcdKVBase(client, rootPath)
 	s.testReadWrite(c, kv)
 	s.testRange(c, kv)
+	s.testTxn(c, kv)
 }
 
 func (s *testKVSuite) TestLevelDB(c *C) {
@@ -65,12 +67,14 @@ func (s *testKVSuite) TestLevelDB(c *C) {
 
 	s.testReadWrite(c, kv)
 	s.testRange(c, kv)
+	s.testTxn(c, kv)
 }
 
 func (s *testKVSuite) TestMemKV(c *C) {
 	kv := NewMemoryKV()
 	s.testReadWrite(c, kv)
 	s.testRange(c, kv)
+	s.testTxn(c, kv)
 }
 
 func (s *testKVSuite) testReadWrite(c *C, kv Base) {
@@ -126,6 +130,37 @@ func (s *testKVSuite) testRange(c *C, kv Base) {
 	}
 }
 
+func (s *testKVSuite) testTxn(c *C, kv Base) {
+	c.Assert(kv.Save("txn-a", "1"), IsNil)
+	c.Assert(kv.Save("txn-b", "2"), IsNil)
+
+	// The writes are discarded if the transaction fails.
+	err := kv.RunInTxn(func(txn Txn) error {
+		c.Assert(txn.Save("txn-a", "3"), IsNil)
+		return errors.New("abort")
+	})
+	c.Assert(err, NotNil)
+	v, err := kv.Load("txn-a")
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, "1")
+
+	err = kv.RunInTxn(func(txn Txn) error {
+		v, err := txn.Load("txn-a")
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, "1")
+		c.Assert(txn.Save("txn-a", "3"), IsNil)
+		c.Assert(txn.Remove("txn-b"), IsNil)
+		c.Assert(txn.Save("txn-c", "4"), IsNil)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	for key, expect := range map[string]string{"txn-a": "3", "txn-b": "", "txn-c": "4"} {
+		v, err := kv.Load(key)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, expect)
+	}
+}
+
 func newTestSingleConfig() *embed.Config {
 	cfg := embed.NewConfig()
 	cfg.Name = "test_etcd"
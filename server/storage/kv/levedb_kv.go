@@ -77,6 +77,27 @@ func (kv *LevelDBKV) Remove(key string) error {
 	return errors.WithStack(kv.Delete([]byte(key), nil))
 }
 
+// RunInTxn runs f and writes its changes in a batch. The reads in f are not
+// isolated from the concurrent writes.
+func (kv *LevelDBKV) RunInTxn(f func(txn Txn) error) error {
+	txn := &bufferedTxn{load: kv.Load}
+	if err := f(txn); err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	for key, value := range txn.writes {
+		if value == nil {
+			batch.Delete([]byte(key))
+		} else {
+			batch.Put([]byte(key), []byte(*value))
+		}
+	}
+	if err := kv.Write(batch, nil); err != nil {
+		return errs.ErrLevelDBWrite.Wrap(err).GenWithStackByCause()
+	}
+	return nil
+}
+
 // SaveRegions stores some regions.
 func (kv *LevelDBKV) SaveRegions(regions map[string]*metapb.Region) error {
 	batch := new(leveldb.Batch)
@@ -88,3 +88,61 @@ func (kv *memoryKV) Remove(key string) error {
 	kv.tree.Delete(memoryKVItem{key, ""})
 	return nil
 }
+
+// RunInTxn runs f with the kv locked, the writes are buffered until f returns.
+func (kv *memoryKV) RunInTxn(f func(txn Txn) error) error {
+	kv.Lock()
+	defer kv.Unlock()
+	txn := &bufferedTxn{load: func(key string) (string, error) {
+		item := kv.tree.Get(memoryKVItem{key, ""})
+		if item == nil {
+			return "", nil
+		}
+		return item.(memoryKVItem).value, nil
+	}}
+	if err := f(txn); err != nil {
+		return err
+	}
+	for key, value := range txn.writes {
+		if value == nil {
+			kv.tree.Delete(memoryKVItem{key, ""})
+		} else {
+			kv.tree.ReplaceOrInsert(memoryKVItem{key, *value})
+		}
+	}
+	return nil
+}
+
+// bufferedTxn buffers the writes of a transaction, a nil value means the key
+// is removed.
+type bufferedTxn struct {
+	load   func(key string) (string, error)
+	writes map[string]*string
+}
+
+func (t *bufferedTxn) Load(key string) (string, error) {
+	if value, ok := t.writes[key]; ok {
+		if value == nil {
+			return "", nil
+		}
+		return *value, nil
+	}
+	return t.load(key)
+}
+
+func (t *bufferedTxn) Save(key, value string) error {
+	t.put(key, &value)
+	return nil
+}
+
+func (t *bufferedTxn) Remove(key string) error {
+	t.put(key, nil)
+	return nil
+}
+
+func (t *bufferedTxn) put(key string, value *string) {
+	if t.writes == nil {
+		t.writes = make(map[string]*string)
+	}
+	t.writes[key] = value
+}
@@ -38,6 +38,10 @@ type Storage interface {
 	endpoint.ReplicationStatusStorage
 	endpoint.GCSafePointStorage
 	endpoint.MinResolvedTSStorage
+	endpoint.APICredentialStorage
+	endpoint.RegionQuotaStorage
+	endpoint.GenealogyStorage
+	endpoint.KeyspaceStorage
 }
 
 // NewStorageWithMemoryBackend creates a new storage with memory backend.
@@ -120,6 +120,11 @@ type AllocatorManager struct {
 	updatePhysicalInterval time.Duration
 	maxResetTSGap          func() time.Duration
 	securityConfig         *grpcutil.TLSConfig
+	// clockDriftWarningThreshold and clockDriftFatalThreshold are the thresholds
+	// of the drift between the system time and the physical part of TSO.
+	clockDriftWarningThreshold time.Duration
+	clockDriftFatalThreshold   time.Duration
+	hlcMode                    bool
 	// for gRPC use
 	localAllocatorConn struct {
 		sync.RWMutex
@@ -142,6 +147,10 @@ func NewAllocatorManager(
 		updatePhysicalInterval: cfg.TSOUpdatePhysicalInterval.Duration,
 		maxResetTSGap:          maxResetTSGap,
 		securityConfig:         &cfg.Security.TLSConfig,
+
+		clockDriftWarningThreshold: cfg.TSOClockDriftWarningThreshold.Duration,
+		clockDriftFatalThreshold:   cfg.TSOClockDriftFatalThreshold.Duration,
+		hlcMode:                    cfg.TSOHLCMode,
 	}
 	allocatorManager.mu.allocatorGroups = make(map[string]*allocatorGroup)
 	allocatorManager.mu.clusterDCLocations = make(map[string]*DCLocationInfo)
@@ -413,6 +422,15 @@ func (am *AllocatorManager) allocatorLeaderLoop(ctx context.Context, allocator *
 			continue
 		}
 
+		// Campaigning with the drifted clock only makes the allocator step down again.
+		if err := allocator.CheckClockDrift(); err != nil {
+			log.Warn("skip campaigning of the local tso allocator leader and check later",
+				zap.String("dc-location", allocator.GetDCLocation()),
+				errs.ZapError(err))
+			time.Sleep(200 * time.Millisecond)
+			continue
+		}
+
 		am.campaignAllocatorLeader(ctx, allocator, dcLocationInfo, isNextLeader)
 	}
 }
@@ -933,6 +951,22 @@ func (am *AllocatorManager) HandleTSORequest(dcLocation string, count uint32) (p
 	return allocatorGroup.allocator.GenerateTSO(count)
 }
 
+// BatchAllocTimestamps allocates count Global TSOs at once by advancing the logical
+// part atomically, and returns the base (the smallest) timestamp of the batch.
+// The allocated timestamps are [base, base+count) in the logical part if there
+// is no suffix, otherwise the step of the logical part is 1<<suffixBits.
+func (am *AllocatorManager) BatchAllocTimestamps(ctx context.Context, count uint32) (physical, logical int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, errs.ErrGenerateTimestamp.FastGenByArgs(err.Error())
+	}
+	ts, err := am.HandleTSORequest(GlobalDCLocation, count)
+	if err != nil {
+		return 0, 0, err
+	}
+	// The logical part of the response is the largest one of the batch.
+	return ts.GetPhysical(), ts.GetLogical() - int64(count-1)<<ts.GetSuffixBits(), nil
+}
+
 // ResetAllocatorGroup will reset the allocator's leadership and TSO initialized in memory.
 // It usually should be called before re-triggering an Allocator leader campaign.
 func (am *AllocatorManager) ResetAllocatorGroup(dcLocation string) {
@@ -51,6 +51,10 @@ type Allocator interface {
 	GenerateTSO(count uint32) (pdpb.Timestamp, error)
 	// Reset is used to reset the TSO allocator.
 	Reset()
+	// CheckClockDrift returns an error if the system time hasn't caught up with the TSO
+	// since the allocator stepped down due to the clock drift. The allocator should not
+	// campaign until then, otherwise it will step down again.
+	CheckClockDrift() error
 }
 
 // GlobalTSOAllocator is the global single point TSO allocator.
@@ -83,6 +87,10 @@ func NewGlobalTSOAllocator(
 			maxResetTSGap:          am.maxResetTSGap,
 			dcLocation:             GlobalDCLocation,
 			tsoMux:                 &tsoObject{},
+
+			clockDriftWarningThreshold: am.clockDriftWarningThreshold,
+			clockDriftFatalThreshold:   am.clockDriftFatalThreshold,
+			hlcMode:                    am.hlcMode,
 		},
 	}
 	return gta
@@ -137,6 +145,12 @@ func (gta *GlobalTSOAllocator) UpdateTSO() error {
 	return gta.timestampOracle.UpdateTimestamp(gta.leadership)
 }
 
+// CheckClockDrift returns an error if the system time hasn't caught up with the TSO
+// since the allocator stepped down due to the clock drift.
+func (gta *GlobalTSOAllocator) CheckClockDrift() error {
+	return gta.timestampOracle.checkClockDriftRecovered()
+}
+
 // SetTSO sets the physical part with given TSO.
 func (gta *GlobalTSOAllocator) SetTSO(tso uint64) error {
 	return gta.timestampOracle.resetUserTimestamp(gta.leadership, tso, false)
@@ -258,7 +272,7 @@ func (gta *GlobalTSOAllocator) precheckLogical(maxTSO *pdpb.Timestamp, suffixBit
 		return false
 	}
 	// Check if the logical part will reach the overflow condition after being differenitated.
-	if differentiatedLogical := gta.timestampOracle.differentiateLogical(maxTSO.Logical, suffixBits); differentiatedLogical >= maxLogical {
+	if differentiatedLogical := gta.timestampOracle.differentiateLogical(maxTSO.Logical, suffixBits); differentiatedLogical >= gta.timestampOracle.getMaxLogical() {
 		log.Error("estimated logical part outside of max logical interval, please check ntp time",
 			zap.Reflect("max-tso", maxTSO), errs.ZapError(errs.ErrLogicOverflow))
 		tsoCounter.WithLabelValues("precheck_logical_overflow", gta.timestampOracle.dcLocation).Inc()
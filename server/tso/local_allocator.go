@@ -64,6 +64,10 @@ func NewLocalTSOAllocator(
 			maxResetTSGap:          am.maxResetTSGap,
 			dcLocation:             dcLocation,
 			tsoMux:                 &tsoObject{},
+
+			clockDriftWarningThreshold: am.clockDriftWarningThreshold,
+			clockDriftFatalThreshold:   am.clockDriftFatalThreshold,
+			hlcMode:                    am.hlcMode,
 		},
 		rootPath: leadership.GetLeaderKey(),
 	}
@@ -92,6 +96,12 @@ func (lta *LocalTSOAllocator) UpdateTSO() error {
 	return lta.timestampOracle.UpdateTimestamp(lta.leadership)
 }
 
+// CheckClockDrift returns an error if the system time hasn't caught up with the TSO
+// since the allocator stepped down due to the clock drift.
+func (lta *LocalTSOAllocator) CheckClockDrift() error {
+	return lta.timestampOracle.checkClockDriftRecovered()
+}
+
 // SetTSO sets the physical part with given TSO.
 func (lta *LocalTSOAllocator) SetTSO(tso uint64) error {
 	return lta.timestampOracle.resetUserTimestamp(lta.leadership, tso, false)
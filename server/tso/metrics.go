@@ -53,6 +53,14 @@ var (
 			Name:      "role",
 			Help:      "Indicate the PD server role info, whether it's a TSO allocator.",
 		}, []string{dcLabel})
+
+	tsoClockDrift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "tso",
+			Name:      "clock_drift_ms",
+			Help:      "The drift of the system time behind the physical part of TSO in milliseconds.",
+		}, []string{dcLabel})
 )
 
 func init() {
@@ -60,4 +68,5 @@ func init() {
 	prometheus.MustRegister(tsoGauge)
 	prometheus.MustRegister(tsoGap)
 	prometheus.MustRegister(tsoAllocatorRole)
+	prometheus.MustRegister(tsoClockDrift)
 }
@@ -42,6 +42,8 @@ const (
 	// When a TSO's logical time reaches this limit,
 	// the physical time will be forced to increase.
 	maxLogical = int64(1 << 18)
+	// hlcMaxLogical is the max upper limit for logical time in HLC mode.
+	hlcMaxLogical = int64(1 << 16)
 	// MaxSuffixBits indicates the max number of suffix bits.
 	MaxSuffixBits = 4
 )
@@ -68,6 +70,14 @@ type timestampOracle struct {
 	lastSavedTime atomic.Value // stored as time.Time
 	suffix        int
 	dcLocation    string
+	// the thresholds of the drift between the system time and the physical part of TSO
+	clockDriftWarningThreshold time.Duration
+	clockDriftFatalThreshold   time.Duration
+	// driftedPhysical is the physical part of TSO when the fatal clock drift is detected,
+	// the allocator refuses to campaign until the system time catches up with it.
+	driftedPhysical atomic.Value // stored as time.Time
+	// hlcMode indicates whether the TSO works as a hybrid logical clock.
+	hlcMode bool
 }
 
 func (t *timestampOracle) setTSOPhysical(next time.Time) {
@@ -101,6 +111,9 @@ func (t *timestampOracle) generateTSO(count int64, suffixBits int) (physical int
 	if t.tsoMux.physical == typeutil.ZeroTime {
 		return 0, 0, typeutil.ZeroTime
 	}
+	if t.hlcMode {
+		t.advancePhysicalLocked(time.Now())
+	}
 	physical = t.tsoMux.physical.UnixNano() / int64(time.Millisecond)
 	t.tsoMux.logical += count
 	logical = t.tsoMux.logical
@@ -113,6 +126,84 @@ func (t *timestampOracle) generateTSO(count int64, suffixBits int) (physical int
 	return physical, logical, lastUpdateTime
 }
 
+// advancePhysicalLocked advances the physical part to the given system time in HLC mode.
+// It's safe without saving to etcd as long as the time is still in the saved time window.
+func (t *timestampOracle) advancePhysicalLocked(now time.Time) {
+	if typeutil.SubTSOPhysicalByWallClock(now, t.tsoMux.physical) <= 0 {
+		return
+	}
+	lastSavedTime, ok := t.lastSavedTime.Load().(time.Time)
+	if !ok || typeutil.SubRealTimeByWallClock(lastSavedTime, now) <= UpdateTimestampGuard {
+		return
+	}
+	t.tsoMux.physical = now
+	t.tsoMux.logical = 0
+	t.setTSOUpdateTimeLocked(now)
+}
+
+// getMaxLogical returns the max upper limit for logical time.
+func (t *timestampOracle) getMaxLogical() int64 {
+	if t.hlcMode {
+		return hlcMaxLogical
+	}
+	return maxLogical
+}
+
+// checkClockDrift checks how far the system time falls behind the given physical time of TSO.
+// It returns an error if the drift exceeds the fatal threshold.
+func (t *timestampOracle) checkClockDrift(physical time.Time) error {
+	if physical == typeutil.ZeroTime {
+		return nil
+	}
+	now := t.now()
+	drift := typeutil.SubRealTimeByWallClock(physical, now)
+	if drift < 0 {
+		drift = 0
+	}
+	tsoClockDrift.WithLabelValues(t.dcLocation).Set(float64(drift.Milliseconds()))
+	if t.clockDriftFatalThreshold > 0 && drift > t.clockDriftFatalThreshold {
+		log.Error("system time falls behind the tso too much, the tso allocator will step down",
+			zap.Duration("clock-drift", drift), zap.Time("physical", physical), zap.Time("now", now),
+			zap.Duration("fatal-threshold", t.clockDriftFatalThreshold), zap.String("dc-location", t.dcLocation))
+		tsoCounter.WithLabelValues("clock_drift_fatal", t.dcLocation).Inc()
+		t.driftedPhysical.Store(physical)
+		return errs.ErrClockDrift.FastGenByArgs(drift, t.clockDriftFatalThreshold)
+	}
+	if t.clockDriftWarningThreshold > 0 && drift > t.clockDriftWarningThreshold {
+		log.Warn("system time falls behind the tso, please check ntp time",
+			zap.Duration("clock-drift", drift), zap.Time("physical", physical), zap.Time("now", now),
+			zap.Duration("warning-threshold", t.clockDriftWarningThreshold), zap.String("dc-location", t.dcLocation))
+		tsoCounter.WithLabelValues("clock_drift_warning", t.dcLocation).Inc()
+	}
+	return nil
+}
+
+// checkClockDriftRecovered returns an error if the system time hasn't caught up with
+// the physical part of TSO since the fatal clock drift is detected.
+func (t *timestampOracle) checkClockDriftRecovered() error {
+	physical, ok := t.driftedPhysical.Load().(time.Time)
+	if !ok || physical == typeutil.ZeroTime {
+		return nil
+	}
+	if drift := typeutil.SubRealTimeByWallClock(physical, t.now()); drift > 0 {
+		return errs.ErrClockDrift.FastGenByArgs(drift, t.clockDriftFatalThreshold)
+	}
+	log.Info("system time has caught up with the tso", zap.Time("physical", physical), zap.String("dc-location", t.dcLocation))
+	t.driftedPhysical.Store(typeutil.ZeroTime)
+	return nil
+}
+
+// now returns the system time.
+func (t *timestampOracle) now() time.Time {
+	now := time.Now()
+	failpoint.Inject("clockBackwardJump", func(val failpoint.Value) {
+		if ms, ok := val.(int); ok {
+			now = now.Add(-time.Duration(ms) * time.Millisecond)
+		}
+	})
+	return now
+}
+
 // Because the Local TSO in each Local TSO Allocator is independent, so they are possible
 // to be the same at sometimes, to avoid this case, we need to use the logical part of the
 // Local TSO to do some differentiating work.
@@ -295,6 +386,10 @@ func (t *timestampOracle) resetUserTimestamp(leadership *election.Leadership, ts
 // 3. The physical time is always less than the saved timestamp.
 func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error {
 	prevPhysical, prevLogical := t.getTSO()
+	// Return the error to make the allocator step down if the clock drift is too large.
+	if err := t.checkClockDrift(prevPhysical); err != nil {
+		return err
+	}
 	tsoGauge.WithLabelValues("tso", t.dcLocation).Set(float64(prevPhysical.UnixNano() / int64(time.Millisecond)))
 	tsoGap.WithLabelValues(t.dcLocation).Set(float64(time.Since(prevPhysical).Milliseconds()))
 
@@ -322,7 +417,7 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 	// If the system time is greater, it will be synchronized with the system time.
 	if jetLag > UpdateTimestampGuard {
 		next = now
-	} else if prevLogical > maxLogical/2 {
+	} else if prevLogical > t.getMaxLogical()/2 {
 		// The reason choosing maxLogical/2 here is that it's big enough for common cases.
 		// Because there is enough timestamp can be allocated before next update.
 		log.Warn("the logical time may be not enough", zap.Int64("prev-logical", prevLogical))
@@ -372,11 +467,16 @@ func (t *timestampOracle) getTS(leadership *election.Leadership, count uint32, s
 		if resp.GetPhysical() == 0 {
 			return pdpb.Timestamp{}, errs.ErrGenerateTimestamp.FastGenByArgs("timestamp in memory has been reset")
 		}
-		if resp.GetLogical() >= maxLogical {
+		if resp.GetLogical() >= t.getMaxLogical() {
+			tsoCounter.WithLabelValues("logical_overflow", t.dcLocation).Inc()
+			// The physical part will be advanced on the next allocation in HLC mode.
+			if t.hlcMode {
+				time.Sleep(UpdateTimestampGuard)
+				continue
+			}
 			log.Error("logical part outside of max logical interval, please check ntp time",
 				zap.Reflect("response", resp),
 				zap.Int("retry-count", i), errs.ZapError(errs.ErrLogicOverflow))
-			tsoCounter.WithLabelValues("logical_overflow", t.dcLocation).Inc()
 			time.Sleep(t.updatePhysicalInterval)
 			continue
 		}
@@ -300,6 +300,7 @@ func (s *schedulerTestSuite) TestScheduler(c *C) {
 		"write-peer-priorities":      []interface{}{"byte", "key"},
 		"strict-picking-store":       "true",
 		"enable-for-tiflash":         "true",
+		"scatter-cool-off-duration":  "0s",
 	}
 	var conf map[string]interface{}
 	mustExec([]string{"-u", pdAddr, "scheduler", "config", "balance-hot-region-scheduler", "list"}, &conf)
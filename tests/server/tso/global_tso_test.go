@@ -19,18 +19,29 @@ package tso_test
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sort"
 	"strings"
 	"sync"
+	"testing"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/grpcutil"
 	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/pkg/tsoutil"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/tso"
 	"github.com/tikv/pd/tests"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // There are three kinds of ways to generate a TSO:
@@ -114,6 +125,269 @@ func (s *testNormalGlobalTSOSuite) TestZeroTSOCount(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testNormalGlobalTSOSuite) TestBatchAllocTimestamps(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	am := cluster.GetServer(cluster.GetLeader()).GetTSOAllocatorManager()
+	_, _, err = am.BatchAllocTimestamps(s.ctx, 0)
+	c.Assert(err, NotNil)
+
+	lastPhysical, lastLogical, err := am.BatchAllocTimestamps(s.ctx, 1)
+	c.Assert(err, IsNil)
+	for _, count := range []uint32{1, 10, 1000} {
+		physical, logical, err := am.BatchAllocTimestamps(s.ctx, count)
+		c.Assert(err, IsNil)
+		c.Assert(tsoutil.ComposeTS(physical, logical), Greater, tsoutil.ComposeTS(lastPhysical, lastLogical))
+		// The next allocation should be larger than the whole batch.
+		lastPhysical, lastLogical, err = am.BatchAllocTimestamps(s.ctx, 1)
+		c.Assert(err, IsNil)
+		c.Assert(tsoutil.ComposeTS(lastPhysical, lastLogical), Greater, tsoutil.ComposeTS(physical, logical+int64(count)-1))
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	_, _, err = am.BatchAllocTimestamps(ctx, 1)
+	c.Assert(err, NotNil)
+
+	// The Tso stream allocates in batch and responds the largest timestamp of the batch.
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	grpcPDClient := testutil.MustNewGrpcClient(c, leaderServer.GetAddr())
+	ctx, cancel = context.WithCancel(s.ctx)
+	defer cancel()
+	tsoClient, err := grpcPDClient.Tso(ctx)
+	c.Assert(err, IsNil)
+	defer tsoClient.CloseSend()
+	last := tsoutil.ComposeTS(lastPhysical, lastLogical)
+	for _, count := range []uint32{1, 10, 1000} {
+		err = tsoClient.Send(&pdpb.TsoRequest{
+			Header:     testutil.NewRequestHeader(leaderServer.GetClusterID()),
+			Count:      count,
+			DcLocation: tso.GlobalDCLocation,
+		})
+		c.Assert(err, IsNil)
+		resp, err := tsoClient.Recv()
+		c.Assert(err, IsNil)
+		c.Assert(resp.GetCount(), Equals, count)
+		ts := tsoutil.GenerateTS(resp.GetTimestamp())
+		c.Assert(ts-uint64(count)+1, Greater, last)
+		last = ts
+	}
+	physical, logical, err := am.BatchAllocTimestamps(s.ctx, 1)
+	c.Assert(err, IsNil)
+	c.Assert(tsoutil.ComposeTS(physical, logical), Greater, last)
+}
+
+func (s *testNormalGlobalTSOSuite) TestClockDrift(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config, serverName string) {
+		conf.TSOClockDriftFatalThreshold = typeutil.NewDuration(time.Second)
+		conf.TSOSaveInterval = typeutil.NewDuration(500 * time.Millisecond)
+	})
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	_, _, err = leaderServer.GetTSOAllocatorManager().BatchAllocTimestamps(s.ctx, 1)
+	c.Assert(err, IsNil)
+
+	// The drift exceeds the warning threshold only.
+	c.Assert(failpoint.Enable("github.com/tikv/pd/server/tso/clockBackwardJump", `return(600)`), IsNil)
+	_, _, err = leaderServer.GetTSOAllocatorManager().BatchAllocTimestamps(s.ctx, 1)
+	c.Assert(err, IsNil)
+
+	// The system time jumps backward and the drift exceeds the fatal threshold,
+	// the leader steps down and doesn't campaign again.
+	c.Assert(failpoint.Enable("github.com/tikv/pd/server/tso/clockBackwardJump", `return(2000)`), IsNil)
+	testutil.WaitUntil(c, func() bool {
+		return cluster.GetLeader() == ""
+	})
+	allocator, err := leaderServer.GetTSOAllocatorManager().GetAllocator(tso.GlobalDCLocation)
+	c.Assert(err, IsNil)
+	c.Assert(errs.ErrClockDrift.Equal(allocator.CheckClockDrift()), IsTrue)
+	time.Sleep(time.Second)
+	c.Assert(cluster.GetLeader(), Equals, "")
+
+	// The system time is recovered.
+	c.Assert(failpoint.Disable("github.com/tikv/pd/server/tso/clockBackwardJump"), IsNil)
+	testutil.WaitUntil(c, func() bool {
+		leader := cluster.GetServer(cluster.WaitLeader())
+		if leader == nil {
+			return false
+		}
+		_, _, err = leader.GetTSOAllocatorManager().BatchAllocTimestamps(s.ctx, 1)
+		return err == nil
+	})
+}
+
+func (s *testNormalGlobalTSOSuite) TestClientRateLimit(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config, serverName string) {
+		conf.TSOClientRateLimit = 20
+		conf.TSOClientBurst = 20
+	})
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	req := &pdpb.TsoRequest{
+		Header:     testutil.NewRequestHeader(leaderServer.GetClusterID()),
+		Count:      1,
+		DcLocation: tso.GlobalDCLocation,
+	}
+	// requestTSO sends n TSO requests from the given loopback IP and returns the number of succeeded ones.
+	requestTSO := func(ip string, n int, interval time.Duration) (int, error) {
+		dialer := &net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(ip)}}
+		conn, err := grpc.Dial(strings.TrimPrefix(leaderServer.GetAddr(), "http://"), grpc.WithInsecure(),
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "tcp", addr)
+			}))
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithCancel(s.ctx)
+		defer cancel()
+		tsoClient, err := pdpb.NewPDClient(conn).Tso(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer tsoClient.CloseSend()
+		for i := 0; i < n; i++ {
+			if err := tsoClient.Send(req); err != nil {
+				return i, err
+			}
+			if _, err := tsoClient.Recv(); err != nil {
+				return i, err
+			}
+			time.Sleep(interval)
+		}
+		return n, nil
+	}
+
+	var (
+		wg        sync.WaitGroup
+		greedyErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// The greedy client is limited.
+		for i := 0; i < 10; i++ {
+			_, greedyErr = requestTSO("127.0.0.2", 1000, 0)
+		}
+	}()
+	// The well-behaved client is not affected by the greedy one.
+	succeeded, err := requestTSO("127.0.0.3", 20, 50*time.Millisecond)
+	c.Assert(err, IsNil)
+	c.Assert(succeeded, Equals, 20)
+	wg.Wait()
+	c.Assert(greedyErr, NotNil)
+	c.Assert(status.Code(greedyErr), Equals, codes.ResourceExhausted)
+}
+
+func (s *testNormalGlobalTSOSuite) TestHLCMode(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config, serverName string) {
+		conf.TSOHLCMode = true
+		// make sure the physical part is advanced by the allocation rather than the update loop.
+		conf.TSOUpdatePhysicalInterval = typeutil.NewDuration(time.Second)
+	})
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	am := cluster.GetServer(cluster.GetLeader()).GetTSOAllocatorManager()
+	var last uint64
+	for i := 0; i < 100; i++ {
+		before := time.Now()
+		physical, logical, err := am.BatchAllocTimestamps(s.ctx, 1000)
+		c.Assert(err, IsNil)
+		c.Assert(logical+1000, LessEqual, int64(1<<16))
+		// The physical part follows the system time.
+		c.Assert(physical, GreaterEqual, before.UnixNano()/int64(time.Millisecond)-1)
+		ts := tsoutil.ComposeTS(physical, logical)
+		c.Assert(ts, Greater, last)
+		last = tsoutil.ComposeTS(physical, logical+999)
+	}
+}
+
+func (s *testNormalGlobalTSOSuite) TestHLCEncoding(c *C) {
+	for _, compatible := range []bool{false, true} {
+		cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config, serverName string) {
+			conf.TSOHLCMode = true
+			conf.TSOHLCCompatible = compatible
+		})
+		c.Assert(err, IsNil)
+
+		err = cluster.RunInitialServers()
+		c.Assert(err, IsNil)
+		cluster.WaitLeader()
+
+		leaderServer := cluster.GetServer(cluster.GetLeader())
+		grpcPDClient := testutil.MustNewGrpcClient(c, leaderServer.GetAddr())
+		req := &pdpb.TsoRequest{
+			Header:     testutil.NewRequestHeader(leaderServer.GetClusterID()),
+			Count:      10,
+			DcLocation: tso.GlobalDCLocation,
+		}
+		ctx, cancel := context.WithCancel(s.ctx)
+		tsoClient, err := grpcPDClient.Tso(ctx)
+		c.Assert(err, IsNil)
+		var last uint64
+		for i := 0; i < 10; i++ {
+			before := time.Now()
+			c.Assert(tsoClient.Send(req), IsNil)
+			resp, err := tsoClient.Recv()
+			c.Assert(err, IsNil)
+			ts := tsoutil.GenerateTS(resp.GetTimestamp())
+			c.Assert(ts, Greater, last)
+			last = ts
+			// The clients compose the ts in the standard layout and get the physical time by the layout of the ts.
+			parse := tsoutil.ParseHLC
+			if compatible {
+				parse = tsoutil.ParseTS
+			}
+			physical, logical := parse(ts)
+			c.Assert(logical, Less, uint64(1<<16))
+			c.Assert(physical.UnixNano()/int64(time.Millisecond), GreaterEqual, before.UnixNano()/int64(time.Millisecond)-1)
+			c.Assert(physical.Before(time.Now().Add(time.Second)), IsTrue)
+		}
+
+		// The reset ts is in the same layout.
+		physical, logical := tsoutil.ParseTS(last)
+		if !compatible {
+			physical, logical = tsoutil.ParseHLC(last)
+		}
+		next := physical.Add(time.Hour).UnixNano() / int64(time.Millisecond)
+		resetTS := tsoutil.ComposeTS(next, int64(logical))
+		if !compatible {
+			resetTS = tsoutil.ComposeHLC(next, int64(logical))
+		}
+		c.Assert(leaderServer.GetServer().GetHandler().ResetTS(resetTS), IsNil)
+		c.Assert(tsoClient.Send(req), IsNil)
+		resp, err := tsoClient.Recv()
+		c.Assert(err, IsNil)
+		c.Assert(tsoutil.GenerateTS(resp.GetTimestamp()), Greater, resetTS)
+
+		tsoClient.CloseSend()
+		cancel()
+		cluster.Destroy()
+	}
+}
+
 func (s *testNormalGlobalTSOSuite) TestRequestFollower(c *C) {
 	cluster, err := tests.NewTestCluster(s.ctx, 2)
 	c.Assert(err, IsNil)
@@ -205,3 +479,97 @@ func (s *testNormalGlobalTSOSuite) TestDelaySyncTimestamp(c *C) {
 	c.Assert(checkAndReturnTimestampResponse(c, req, resp), NotNil)
 	failpoint.Disable("github.com/tikv/pd/server/tso/delaySyncTimestamp")
 }
+
+func BenchmarkBatchAllocTimestamps(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestCluster(ctx, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer cluster.Destroy()
+	if err := cluster.RunInitialServers(); err != nil {
+		b.Fatal(err)
+	}
+	cluster.WaitLeader()
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	conn, err := grpc.Dial(strings.TrimPrefix(leaderServer.GetAddr(), "http://"), grpc.WithInsecure())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	tsoClient, err := pdpb.NewPDClient(conn).Tso(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tsoClient.CloseSend()
+	header := testutil.NewRequestHeader(leaderServer.GetClusterID())
+
+	// Every request allocates count timestamps, the reported timestamps/s
+	// shows whether the single and the batched allocation keep up with 10k,
+	// 100k and 1M requests/s.
+	for _, count := range []uint32{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("count-%d", count), func(b *testing.B) {
+			req := &pdpb.TsoRequest{
+				Header:     header,
+				Count:      count,
+				DcLocation: tso.GlobalDCLocation,
+			}
+			start := time.Now()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := tsoClient.Send(req); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := tsoClient.Recv(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(b.N)*float64(count)/time.Since(start).Seconds(), "timestamps/s")
+		})
+	}
+}
+
+func BenchmarkTSOLatency(b *testing.B) {
+	for _, hlcMode := range []bool{false, true} {
+		name := "standard"
+		if hlcMode {
+			name = "hlc"
+		}
+		b.Run(name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cluster, err := tests.NewTestCluster(ctx, 1, func(conf *config.Config, serverName string) {
+				conf.TSOHLCMode = hlcMode
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer cluster.Destroy()
+			if err := cluster.RunInitialServers(); err != nil {
+				b.Fatal(err)
+			}
+			cluster.WaitLeader()
+			am := cluster.GetServer(cluster.GetLeader()).GetTSOAllocatorManager()
+
+			latencies := make([]time.Duration, 0, b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				start := time.Now()
+				if _, _, err := am.BatchAllocTimestamps(ctx, 1); err != nil {
+					b.Fatal(err)
+				}
+				latencies = append(latencies, time.Since(start))
+			}
+			b.StopTimer()
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			for _, p := range []struct {
+				name       string
+				percentile float64
+			}{{"p50-ns", 0.5}, {"p99-ns", 0.99}, {"p999-ns", 0.999}} {
+				b.ReportMetric(float64(latencies[int(float64(len(latencies)-1)*p.percentile)]), p.name)
+			}
+		})
+	}
+}